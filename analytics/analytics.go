@@ -0,0 +1,289 @@
+package analytics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/storage"
+)
+
+// Store provides the outreach history a report is computed from
+type Store interface {
+	GetOutreachTotals(since time.Time) (*storage.OutreachTotals, error)
+	GetConnectionTemplateStats(since time.Time) ([]*storage.TemplateStats, error)
+	GetMessageTemplateStats(since time.Time) ([]*storage.TemplateStats, error)
+	GetAcceptanceDelays(since time.Time) ([]time.Duration, error)
+	GetDailyActivity(since time.Time) ([]*storage.DailyActivity, error)
+}
+
+// Bucket counts the acceptances that took up to Max
+type Bucket struct {
+	Label string
+	Max   time.Duration // 0 for the open-ended last bucket
+	Count int
+}
+
+// Report summarizes outreach performance over a period
+type Report struct {
+	Since  time.Time
+	Until  time.Time
+	Totals *storage.OutreachTotals
+
+	// Best first: connection templates by acceptance rate, message templates by reply rate
+	ConnectionTemplates []*storage.TemplateStats
+	MessageTemplates    []*storage.TemplateStats
+
+	TimeToAccept       []*Bucket
+	MedianTimeToAccept time.Duration
+	Accepted           int // Requests with a known acceptance time
+
+	Daily []*storage.DailyActivity // One entry per day, including days without activity
+
+	Invites *ratelimit.WeeklyPlan // The weekly invitation budget from now on; nil when there is no weekly limit
+}
+
+// Build computes the report for the days up to and including now
+func Build(store Store, days int, now time.Time) (*Report, error) {
+	if days < 1 {
+		return nil, fmt.Errorf("the period must be at least one day")
+	}
+	// Days are counted in UTC, like the dates stored in the database
+	utc := now.UTC()
+	today := time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, time.UTC)
+	report := &Report{
+		Since: today.AddDate(0, 0, -(days - 1)),
+		Until: now,
+	}
+
+	var err error
+	if report.Totals, err = store.GetOutreachTotals(report.Since); err != nil {
+		return nil, err
+	}
+
+	if report.ConnectionTemplates, err = store.GetConnectionTemplateStats(report.Since); err != nil {
+		return nil, err
+	}
+	sortByRate(report.ConnectionTemplates, (*storage.TemplateStats).AcceptanceRate)
+
+	if report.MessageTemplates, err = store.GetMessageTemplateStats(report.Since); err != nil {
+		return nil, err
+	}
+	sortByRate(report.MessageTemplates, (*storage.TemplateStats).ReplyRate)
+
+	delays, err := store.GetAcceptanceDelays(report.Since)
+	if err != nil {
+		return nil, err
+	}
+	report.TimeToAccept, report.MedianTimeToAccept = distribution(delays)
+	report.Accepted = len(delays)
+
+	activity, err := store.GetDailyActivity(report.Since)
+	if err != nil {
+		return nil, err
+	}
+	report.Daily = fillDays(activity, report.Since, days)
+
+	return report, nil
+}
+
+// AcceptanceRate returns the share of requests in the period that were accepted, as a percentage
+func (r *Report) AcceptanceRate() float64 {
+	return percentage(r.Totals.ConnectionsAccepted, r.Totals.ConnectionsSent)
+}
+
+// ReplyRate returns the share of messaged profiles that replied, as a percentage
+func (r *Report) ReplyRate() float64 {
+	return percentage(r.Totals.ProfilesReplied, r.Totals.ProfilesMessaged)
+}
+
+// UnclassifiedReplies returns the messaged profiles that replied without a
+// reply being labelled
+func (r *Report) UnclassifiedReplies() int {
+	return r.Totals.ProfilesReplied - r.Totals.RepliedInterested - r.Totals.RepliedNotInterested - r.Totals.RepliedLater
+}
+
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
+
+// sortByRate orders templates by rate, breaking ties by volume
+func sortByRate(stats []*storage.TemplateStats, rate func(*storage.TemplateStats) float64) {
+	sort.SliceStable(stats, func(i, j int) bool {
+		if ri, rj := rate(stats[i]), rate(stats[j]); ri != rj {
+			return ri > rj
+		}
+		return stats[i].Sent > stats[j].Sent
+	})
+}
+
+// distribution buckets acceptance delays and returns their median
+func distribution(delays []time.Duration) ([]*Bucket, time.Duration) {
+	day := 24 * time.Hour
+	buckets := []*Bucket{
+		{Label: "< 1 day", Max: day},
+		{Label: "1-3 days", Max: 3 * day},
+		{Label: "3-7 days", Max: 7 * day},
+		{Label: "1-2 weeks", Max: 14 * day},
+		{Label: "2-4 weeks", Max: 28 * day},
+		{Label: "> 4 weeks"},
+	}
+
+	for _, delay := range delays {
+		for _, bucket := range buckets {
+			if bucket.Max == 0 || delay < bucket.Max {
+				bucket.Count++
+				break
+			}
+		}
+	}
+
+	if len(delays) == 0 {
+		return buckets, 0
+	}
+	sorted := append([]time.Duration(nil), delays...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return buckets, (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return buckets, sorted[middle]
+}
+
+// fillDays returns one entry per day of the period, in order
+func fillDays(activity []*storage.DailyActivity, since time.Time, days int) []*storage.DailyActivity {
+	byDate := make(map[string]*storage.DailyActivity, len(activity))
+	for _, day := range activity {
+		byDate[day.Date] = day
+	}
+
+	filled := make([]*storage.DailyActivity, 0, days)
+	for i := 0; i < days; i++ {
+		date := since.AddDate(0, 0, i).Format("2006-01-02")
+		day, ok := byDate[date]
+		if !ok {
+			day = &storage.DailyActivity{Date: date}
+		}
+		filled = append(filled, day)
+	}
+	return filled
+}
+
+// TemplateName labels templates in reports
+func TemplateName(stats *storage.TemplateStats) string {
+	if stats.Template == "" {
+		return "(custom)"
+	}
+	return stats.Template
+}
+
+// FormatDuration prints a delay in days or hours
+func FormatDuration(d time.Duration) string {
+	if d >= 24*time.Hour {
+		return fmt.Sprintf("%.1f days", d.Hours()/24)
+	}
+	return fmt.Sprintf("%.1f hours", d.Hours())
+}
+
+const barWidth = 40
+
+// WriteText renders the report with ASCII charts
+func (r *Report) WriteText(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Outreach Analytics (%s to %s)\n", r.Since.Format("2006-01-02"), r.Until.Format("2006-01-02"))
+	fmt.Fprintf(&b, "==========================================\n\n")
+
+	fmt.Fprintf(&b, "Connection requests: %d sent, %d accepted (%.1f%%)\n",
+		r.Totals.ConnectionsSent, r.Totals.ConnectionsAccepted, r.AcceptanceRate())
+	fmt.Fprintf(&b, "Messages: %d profiles messaged, %d replied (%.1f%%)\n",
+		r.Totals.ProfilesMessaged, r.Totals.ProfilesReplied, r.ReplyRate())
+	fmt.Fprintf(&b, "Replies: %d interested, %d not interested, %d later, %d unclassified\n\n",
+		r.Totals.RepliedInterested, r.Totals.RepliedNotInterested, r.Totals.RepliedLater, r.UnclassifiedReplies())
+
+	if r.Invites != nil {
+		fmt.Fprintf(&b, "Weekly Invitations: %d/%d sent in the last 7 days, %d remaining\n",
+			r.Invites.Sent, r.Invites.Limit, r.Invites.Remaining)
+		for _, day := range r.Invites.Days {
+			fmt.Fprintf(&b, "  %s %-*s %d\n", day.Date.Format("Mon 01-02"), barWidth, bar(day.Target, r.Invites.Limit, '#'), day.Target)
+		}
+		fmt.Fprintf(&b, "\n")
+	}
+
+	fmt.Fprintf(&b, "Time to Accept:\n")
+	if r.Accepted == 0 {
+		fmt.Fprintf(&b, "  No accepted requests\n")
+	} else {
+		max := r.MaxBucket()
+		for _, bucket := range r.TimeToAccept {
+			fmt.Fprintf(&b, "  %-10s %-*s %d\n", bucket.Label, barWidth, bar(bucket.Count, max, '#'), bucket.Count)
+		}
+		fmt.Fprintf(&b, "  Median: %s\n", FormatDuration(r.MedianTimeToAccept))
+	}
+	fmt.Fprintf(&b, "\n")
+
+	fmt.Fprintf(&b, "Connection Templates (by acceptance rate):\n")
+	if len(r.ConnectionTemplates) == 0 {
+		fmt.Fprintf(&b, "  No requests\n")
+	}
+	for _, stats := range r.ConnectionTemplates {
+		fmt.Fprintf(&b, "  %-28s %4d sent, %4d accepted (%5.1f%%), %4d replied\n",
+			TemplateName(stats), stats.Sent, stats.Accepted, stats.AcceptanceRate(), stats.Replied)
+	}
+	fmt.Fprintf(&b, "\n")
+
+	fmt.Fprintf(&b, "Message Templates (by reply rate):\n")
+	if len(r.MessageTemplates) == 0 {
+		fmt.Fprintf(&b, "  No messages\n")
+	}
+	for _, stats := range r.MessageTemplates {
+		fmt.Fprintf(&b, "  %-28s %4d sent, %4d replied (%5.1f%%)\n",
+			TemplateName(stats), stats.Sent, stats.Replied, stats.ReplyRate())
+	}
+	fmt.Fprintf(&b, "\n")
+
+	r.writeTrend(&b, "Daily Connections (# accepted, - sent)",
+		func(day *storage.DailyActivity) (int, int) { return day.ConnectionsSent, day.ConnectionsAccepted })
+	r.writeTrend(&b, "Daily Messages (# replies, - sent)",
+		func(day *storage.DailyActivity) (int, int) { return day.MessagesSent, day.RepliesReceived })
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeTrend draws one bar per day, with the second series drawn over the first
+func (r *Report) writeTrend(b *strings.Builder, title string, series func(*storage.DailyActivity) (int, int)) {
+	fmt.Fprintf(b, "%s:\n", title)
+
+	max := r.maxDaily(series)
+	for _, day := range r.Daily {
+		total, part := series(day)
+		line := []rune(bar(total, max, '-'))
+		partBar := []rune(bar(part, max, '#'))
+		if len(partBar) > len(line) {
+			line = partBar
+		} else {
+			copy(line, partBar)
+		}
+		fmt.Fprintf(b, "  %s %-*s %d/%d\n", day.Date[5:], barWidth, string(line), part, total)
+	}
+	fmt.Fprintf(b, "\n")
+}
+
+// bar returns a bar of value scaled so that max fills the chart width
+func bar(value, max int, char rune) string {
+	if value <= 0 || max <= 0 {
+		return ""
+	}
+	length := value * barWidth / max
+	if length == 0 {
+		length = 1
+	}
+	return strings.Repeat(string(char), length)
+}
@@ -0,0 +1,155 @@
+package analytics
+
+import (
+	"html/template"
+	"io"
+
+	"linkedin-automation/storage"
+)
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"name":     TemplateName,
+	"duration": FormatDuration,
+	"width": func(value, max int) float64 {
+		if max == 0 {
+			return 0
+		}
+		return float64(value) * 100 / float64(max)
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Outreach Analytics</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1d2226; }
+h1 { font-size: 1.5em; }
+h2 { font-size: 1.1em; margin-top: 2em; }
+table { border-collapse: collapse; }
+th, td { padding: 4px 12px 4px 0; text-align: left; }
+td.num { text-align: right; }
+.summary { display: flex; gap: 2em; }
+.summary div { background: #f3f2ef; padding: 1em 1.5em; border-radius: 6px; }
+.summary strong { display: block; font-size: 1.6em; }
+.chart td.bar { width: 400px; }
+.track { position: relative; height: 14px; }
+.track span { position: absolute; left: 0; top: 0; height: 14px; border-radius: 2px; }
+.total { background: #b3d4f5; }
+.part { background: #0a66c2; }
+</style>
+</head>
+<body>
+<h1>Outreach Analytics</h1>
+<p>{{.Since.Format "2006-01-02"}} to {{.Until.Format "2006-01-02"}}</p>
+
+<div class="summary">
+<div><strong>{{printf "%.1f" .AcceptanceRate}}%</strong>acceptance rate ({{.Totals.ConnectionsAccepted}} of {{.Totals.ConnectionsSent}} requests)</div>
+<div><strong>{{printf "%.1f" .ReplyRate}}%</strong>reply rate ({{.Totals.ProfilesReplied}} of {{.Totals.ProfilesMessaged}} profiles messaged)</div>
+<div><strong>{{if .Accepted}}{{duration .MedianTimeToAccept}}{{else}}-{{end}}</strong>median time to accept</div>
+</div>
+
+<h2>Replies</h2>
+<table>
+<tr><th>Interested</th><th>Not interested</th><th>Later</th><th>Unclassified</th></tr>
+<tr><td class="num">{{.Totals.RepliedInterested}}</td><td class="num">{{.Totals.RepliedNotInterested}}</td><td class="num">{{.Totals.RepliedLater}}</td><td class="num">{{.UnclassifiedReplies}}</td></tr>
+</table>
+
+{{- with .Invites}}
+<h2>Weekly Invitations</h2>
+<p>{{.Sent}} of {{.Limit}} sent in the last 7 days, {{.Remaining}} remaining</p>
+<table class="chart">
+{{- $limit := .Limit}}
+{{- range .Days}}
+<tr><td>{{.Date.Format "Mon 01-02"}}</td><td class="bar"><div class="track"><span class="part" style="width: {{width .Target $limit}}%"></span></div></td><td class="num">{{.Target}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+<h2>Time to Accept</h2>
+<table class="chart">
+{{- $max := .MaxBucket}}
+{{- range .TimeToAccept}}
+<tr><td>{{.Label}}</td><td class="bar"><div class="track"><span class="part" style="width: {{width .Count $max}}%"></span></div></td><td class="num">{{.Count}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Connection Templates</h2>
+<table>
+<tr><th>Template</th><th>Sent</th><th>Accepted</th><th>Acceptance</th><th>Replied</th></tr>
+{{- range .ConnectionTemplates}}
+<tr><td>{{name .}}</td><td class="num">{{.Sent}}</td><td class="num">{{.Accepted}}</td><td class="num">{{printf "%.1f" .AcceptanceRate}}%</td><td class="num">{{.Replied}}</td></tr>
+{{- else}}
+<tr><td colspan="5">No requests</td></tr>
+{{- end}}
+</table>
+
+<h2>Message Templates</h2>
+<table>
+<tr><th>Template</th><th>Sent</th><th>Replied</th><th>Reply rate</th></tr>
+{{- range .MessageTemplates}}
+<tr><td>{{name .}}</td><td class="num">{{.Sent}}</td><td class="num">{{.Replied}}</td><td class="num">{{printf "%.1f" .ReplyRate}}%</td></tr>
+{{- else}}
+<tr><td colspan="4">No messages</td></tr>
+{{- end}}
+</table>
+
+<h2>Daily Connections</h2>
+<p>Light: sent, dark: accepted</p>
+<table class="chart">
+{{- $max := .MaxConnections}}
+{{- range .Daily}}
+<tr><td>{{.Date}}</td><td class="bar"><div class="track"><span class="total" style="width: {{width .ConnectionsSent $max}}%"></span><span class="part" style="width: {{width .ConnectionsAccepted $max}}%"></span></div></td><td class="num">{{.ConnectionsAccepted}}/{{.ConnectionsSent}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Daily Messages</h2>
+<p>Light: sent, dark: replies received</p>
+<table class="chart">
+{{- $max := .MaxMessages}}
+{{- range .Daily}}
+<tr><td>{{.Date}}</td><td class="bar"><div class="track"><span class="total" style="width: {{width .MessagesSent $max}}%"></span><span class="part" style="width: {{width .RepliesReceived $max}}%"></span></div></td><td class="num">{{.RepliesReceived}}/{{.MessagesSent}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// WriteHTML renders the report as a standalone HTML page
+func (r *Report) WriteHTML(w io.Writer) error {
+	return htmlReport.Execute(w, r)
+}
+
+// MaxBucket returns the largest time-to-accept bucket, for scaling its chart
+func (r *Report) MaxBucket() int {
+	max := 0
+	for _, bucket := range r.TimeToAccept {
+		if bucket.Count > max {
+			max = bucket.Count
+		}
+	}
+	return max
+}
+
+// MaxConnections returns the busiest day of connection activity, for scaling its chart
+func (r *Report) MaxConnections() int {
+	return r.maxDaily(func(day *storage.DailyActivity) (int, int) { return day.ConnectionsSent, day.ConnectionsAccepted })
+}
+
+// MaxMessages returns the busiest day of message activity, for scaling its chart
+func (r *Report) MaxMessages() int {
+	return r.maxDaily(func(day *storage.DailyActivity) (int, int) { return day.MessagesSent, day.RepliesReceived })
+}
+
+func (r *Report) maxDaily(series func(*storage.DailyActivity) (int, int)) int {
+	max := 0
+	for _, day := range r.Daily {
+		total, part := series(day)
+		if total > max {
+			max = total
+		}
+		if part > max {
+			max = part
+		}
+	}
+	return max
+}
@@ -0,0 +1,69 @@
+package analytics
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/storage"
+)
+
+type jsonReport struct {
+	Since                     time.Time                `json:"since"`
+	Until                     time.Time                `json:"until"`
+	Totals                    *storage.OutreachTotals  `json:"totals"`
+	AcceptanceRate            float64                  `json:"acceptance_rate"`
+	ReplyRate                 float64                  `json:"reply_rate"`
+	ConnectionTemplates       []*storage.TemplateStats `json:"connection_templates"`
+	MessageTemplates          []*storage.TemplateStats `json:"message_templates"`
+	TimeToAccept              []jsonBucket             `json:"time_to_accept"`
+	MedianTimeToAcceptSeconds float64                  `json:"median_time_to_accept_seconds"`
+	Accepted                  int                      `json:"accepted"`
+	Daily                     []*storage.DailyActivity `json:"daily"`
+	WeeklyInvites             *ratelimit.WeeklyPlan    `json:"weekly_invites,omitempty"`
+}
+
+type jsonBucket struct {
+	Label      string  `json:"label"`
+	MaxSeconds float64 `json:"max_seconds,omitempty"` // Omitted for the open-ended last bucket
+	Count      int     `json:"count"`
+}
+
+// WriteJSON writes the report as a JSON document, with durations in seconds
+func (r *Report) WriteJSON(w io.Writer) error {
+	out := jsonReport{
+		Since:                     r.Since,
+		Until:                     r.Until,
+		Totals:                    r.Totals,
+		AcceptanceRate:            r.AcceptanceRate(),
+		ReplyRate:                 r.ReplyRate(),
+		ConnectionTemplates:       nonNil(r.ConnectionTemplates),
+		MessageTemplates:          nonNil(r.MessageTemplates),
+		TimeToAccept:              []jsonBucket{},
+		MedianTimeToAcceptSeconds: r.MedianTimeToAccept.Seconds(),
+		Accepted:                  r.Accepted,
+		Daily:                     r.Daily,
+		WeeklyInvites:             r.Invites,
+	}
+	for _, bucket := range r.TimeToAccept {
+		out.TimeToAccept = append(out.TimeToAccept, jsonBucket{
+			Label:      bucket.Label,
+			MaxSeconds: bucket.Max.Seconds(),
+			Count:      bucket.Count,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(out)
+}
+
+// nonNil keeps empty lists as [] rather than null
+func nonNil(stats []*storage.TemplateStats) []*storage.TemplateStats {
+	if stats == nil {
+		return []*storage.TemplateStats{}
+	}
+	return stats
+}
@@ -0,0 +1,352 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/logger"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/storage"
+)
+
+// keyBatchGap ends a keystroke batch when typing pauses for longer
+const keyBatchGap = 3 * time.Second
+
+// Sink stores audit entries
+type Sink interface {
+	SaveAuditEntry(entry *storage.AuditEntry) error
+}
+
+// Recorder logs every navigation, click and keystroke batch a browser
+// performs. It sits between rod and the browser's DevTools connection, so
+// actions are recorded whichever package performs them. Typed text is never
+// recorded, only how many keys were pressed.
+type Recorder struct {
+	sink     Sink
+	logger   *logrus.Logger
+	mu       sync.Mutex
+	sessions map[string]*sessionState
+}
+
+// sessionState tracks one page (DevTools session)
+type sessionState struct {
+	pageURL    string
+	lastTarget string              // Element last clicked, which keystrokes usually go to
+	keys       *storage.AuditEntry // Keystroke batch being collected
+	keyCount   int
+	lastKeyAt  time.Time
+}
+
+// NewRecorder creates a recorder that saves entries to sink
+func NewRecorder(sink Sink, logger *logrus.Logger) *Recorder {
+	return &Recorder{
+		sink:     sink,
+		logger:   logger,
+		sessions: make(map[string]*sessionState),
+	}
+}
+
+// Wrap returns a client that records the actions sent through client
+func (r *Recorder) Wrap(client rod.CDPClient) rod.CDPClient {
+	return &recordingClient{client: client, recorder: r}
+}
+
+// Flush records keystroke batches still being collected
+func (r *Recorder) Flush() {
+	r.mu.Lock()
+	var pending []*storage.AuditEntry
+	for _, state := range r.sessions {
+		if entry := state.takeKeys(); entry != nil {
+			pending = append(pending, entry)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, entry := range pending {
+		r.save(entry)
+	}
+}
+
+// recordingClient forwards DevTools calls and events, recording browser actions
+type recordingClient struct {
+	client   rod.CDPClient
+	recorder *Recorder
+	once     sync.Once
+	events   chan *cdp.Event
+}
+
+// Event forwards the browser's events, following top-level navigations
+func (c *recordingClient) Event() <-chan *cdp.Event {
+	c.once.Do(func() {
+		c.events = make(chan *cdp.Event)
+		go func() {
+			defer close(c.events)
+			for event := range c.client.Event() {
+				if event.Method == "Page.frameNavigated" {
+					c.recorder.frameNavigated(event)
+				}
+				c.events <- event
+			}
+		}()
+	})
+	return c.events
+}
+
+// Call forwards a DevTools call, recording it if it is a browser action
+func (c *recordingClient) Call(ctx context.Context, sessionID, method string, params interface{}) ([]byte, error) {
+	call := func() ([]byte, error) {
+		return c.client.Call(ctx, sessionID, method, params)
+	}
+
+	switch method {
+	case "Page.navigate":
+		var navigate proto.PageNavigate
+		if decode(params, &navigate) {
+			return c.recorder.navigate(sessionID, navigate.URL, call)
+		}
+	case "Input.dispatchMouseEvent":
+		var mouse proto.InputDispatchMouseEvent
+		if decode(params, &mouse) && mouse.Type == proto.InputDispatchMouseEventTypeMousePressed {
+			target := describeNodeAt(ctx, c.client, sessionID, mouse.X, mouse.Y)
+			detail := fmt.Sprintf("%s button at (%.0f, %.0f)", mouse.Button, mouse.X, mouse.Y)
+			return c.recorder.click(sessionID, target, detail, call)
+		}
+	case "Input.dispatchKeyEvent":
+		var key proto.InputDispatchKeyEvent
+		if decode(params, &key) && (key.Type == proto.InputDispatchKeyEventTypeKeyDown || key.Type == proto.InputDispatchKeyEventTypeRawKeyDown) {
+			return c.recorder.keys(sessionID, 1, call)
+		}
+	case "Input.insertText":
+		var insert proto.InputInsertText
+		if decode(params, &insert) {
+			return c.recorder.keys(sessionID, len([]rune(insert.Text)), call)
+		}
+	}
+
+	return call()
+}
+
+func (r *Recorder) navigate(sessionID, url string, call func() ([]byte, error)) ([]byte, error) {
+	r.flushKeys(sessionID)
+
+	entry := r.newEntry(sessionID, storage.AuditNavigate, url)
+	if profile := profileOf(url); profile != "" {
+		entry.ProfileURL = profile
+	}
+
+	res, err := call()
+	if err == nil {
+		var result proto.PageNavigateResult
+		if json.Unmarshal(res, &result) == nil && result.ErrorText != "" {
+			entry.Outcome, entry.Error = storage.AuditError, result.ErrorText
+		}
+	}
+	r.finish(entry, err)
+
+	r.mu.Lock()
+	r.session(sessionID).pageURL = url
+	r.mu.Unlock()
+	return res, err
+}
+
+func (r *Recorder) click(sessionID, target, detail string, call func() ([]byte, error)) ([]byte, error) {
+	r.flushKeys(sessionID)
+
+	entry := r.newEntry(sessionID, storage.AuditClick, target)
+	entry.Detail = detail
+
+	res, err := call()
+	r.finish(entry, err)
+
+	r.mu.Lock()
+	r.session(sessionID).lastTarget = target
+	r.mu.Unlock()
+	return res, err
+}
+
+// keys adds keystrokes to the session's current batch, which is recorded as
+// one entry once another action happens or typing pauses
+func (r *Recorder) keys(sessionID string, count int, call func() ([]byte, error)) ([]byte, error) {
+	started := time.Now()
+	res, err := call()
+
+	r.mu.Lock()
+	state := r.session(sessionID)
+	var done *storage.AuditEntry
+	if state.keys != nil && started.Sub(state.lastKeyAt) > keyBatchGap {
+		done = state.takeKeys()
+	}
+	if state.keys == nil {
+		state.keys = &storage.AuditEntry{
+			OccurredAt: started,
+			Action:     storage.AuditType,
+			Target:     state.lastTarget,
+			PageURL:    state.pageURL,
+			ProfileURL: profileOf(state.pageURL),
+			Outcome:    storage.AuditOK,
+			RunID:      logger.RunID(),
+		}
+	}
+	state.keyCount += count
+	state.lastKeyAt = time.Now()
+	state.keys.Duration = state.lastKeyAt.Sub(state.keys.OccurredAt)
+	if err != nil {
+		state.keys.Outcome, state.keys.Error = storage.AuditError, err.Error()
+	}
+	r.mu.Unlock()
+
+	if done != nil {
+		r.save(done)
+	}
+	return res, err
+}
+
+// frameNavigated follows the page URL when the top frame navigates, including
+// through links and redirects
+func (r *Recorder) frameNavigated(event *cdp.Event) {
+	var navigated proto.PageFrameNavigated
+	if err := json.Unmarshal(event.Params, &navigated); err != nil || navigated.Frame == nil || navigated.Frame.ParentID != "" {
+		return
+	}
+
+	r.mu.Lock()
+	r.session(event.SessionID).pageURL = navigated.Frame.URL
+	r.mu.Unlock()
+}
+
+func (r *Recorder) flushKeys(sessionID string) {
+	r.mu.Lock()
+	entry := r.session(sessionID).takeKeys()
+	r.mu.Unlock()
+
+	if entry != nil {
+		r.save(entry)
+	}
+}
+
+// newEntry starts an entry for an action on the session's current page
+func (r *Recorder) newEntry(sessionID, action, target string) *storage.AuditEntry {
+	r.mu.Lock()
+	pageURL := r.session(sessionID).pageURL
+	r.mu.Unlock()
+
+	return &storage.AuditEntry{
+		OccurredAt: time.Now(),
+		Action:     action,
+		Target:     target,
+		PageURL:    pageURL,
+		ProfileURL: profileOf(pageURL),
+		Outcome:    storage.AuditOK,
+		RunID:      logger.RunID(),
+	}
+}
+
+func (r *Recorder) finish(entry *storage.AuditEntry, err error) {
+	entry.Duration = time.Since(entry.OccurredAt)
+	if err != nil {
+		entry.Outcome, entry.Error = storage.AuditError, err.Error()
+	}
+	r.save(entry)
+}
+
+func (r *Recorder) save(entry *storage.AuditEntry) {
+	if err := r.sink.SaveAuditEntry(entry); err != nil {
+		r.logger.WithError(err).WithField("action", entry.Action).Warn("Failed to record audit entry")
+	}
+}
+
+// session returns the state of a session; the caller must hold r.mu
+func (r *Recorder) session(sessionID string) *sessionState {
+	state, ok := r.sessions[sessionID]
+	if !ok {
+		state = &sessionState{}
+		r.sessions[sessionID] = state
+	}
+	return state
+}
+
+// takeKeys ends the keystroke batch being collected and returns it, if any
+func (s *sessionState) takeKeys() *storage.AuditEntry {
+	entry := s.keys
+	if entry != nil {
+		entry.Detail = fmt.Sprintf("%d keys", s.keyCount)
+	}
+	s.keys, s.keyCount = nil, 0
+	return entry
+}
+
+// describeNodeAt names the element at a point in CSS selector form, e.g.
+// button#send.artdeco-button[aria-label="Send now"]
+func describeNodeAt(ctx context.Context, client rod.CDPClient, sessionID string, x, y float64) string {
+	res, err := client.Call(ctx, sessionID, "DOM.getNodeForLocation", proto.DOMGetNodeForLocation{X: int(x), Y: int(y)})
+	if err != nil {
+		return ""
+	}
+	var location proto.DOMGetNodeForLocationResult
+	if err := json.Unmarshal(res, &location); err != nil {
+		return ""
+	}
+
+	res, err = client.Call(ctx, sessionID, "DOM.describeNode", proto.DOMDescribeNode{BackendNodeID: location.BackendNodeID})
+	if err != nil {
+		return ""
+	}
+	var described proto.DOMDescribeNodeResult
+	if err := json.Unmarshal(res, &described); err != nil || described.Node == nil {
+		return ""
+	}
+	return selectorFor(described.Node)
+}
+
+// selectorFor builds a selector from a node's tag, ID, first classes and label
+func selectorFor(node *proto.DOMNode) string {
+	attributes := make(map[string]string)
+	for i := 0; i+1 < len(node.Attributes); i += 2 {
+		attributes[node.Attributes[i]] = node.Attributes[i+1]
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.ToLower(node.NodeName))
+	if id := attributes["id"]; id != "" {
+		b.WriteString("#" + id)
+	}
+	classes := strings.Fields(attributes["class"])
+	if len(classes) > 2 {
+		classes = classes[:2]
+	}
+	for _, class := range classes {
+		b.WriteString("." + class)
+	}
+	for _, name := range []string{"aria-label", "name", "type"} {
+		if value := attributes[name]; value != "" {
+			fmt.Fprintf(&b, "[%s=%q]", name, value)
+			break
+		}
+	}
+	return b.String()
+}
+
+func profileOf(pageURL string) string {
+	if profileurl.Slug(pageURL) == "" {
+		return ""
+	}
+	return profileurl.Canonicalize(pageURL)
+}
+
+// decode copies DevTools call parameters into a typed struct
+func decode(params interface{}, v interface{}) bool {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
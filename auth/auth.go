@@ -4,16 +4,21 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
 	"github.com/go-rod/rod/lib/launcher"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/errs"
+	"linkedin-automation/pause"
+	"linkedin-automation/selectors"
 )
 
 // AuthManager handles LinkedIn authentication
@@ -24,7 +29,16 @@ type AuthManager struct {
 	email     string
 	password  string
 	sessionPath string
+	proxy     string
+	executablePath string
+	remoteURL string
+	container bool
+	separate  bool
+	captchaSolver CaptchaSolver
+	codeSource VerificationCodeSource
+	clientWrappers []ClientWrapper
 	rng       *rand.Rand
+	launcher  *launcher.Launcher // Set when the browser process was started for this session
 }
 
 // LoginResult represents the result of a login attempt
@@ -47,28 +61,68 @@ func NewAuthManager(email, password, sessionPath string, logger *logrus.Logger)
 	}
 }
 
-// isChromeRunning checks if any Chrome process is running
-func isChromeRunning() bool {
-	cmd := exec.Command("tasklist", "/FI", "IMAGENAME eq chrome.exe", "/FO", "CSV")
-	output, err := cmd.Output()
+// SetProxy routes browser traffic through a proxy server, e.g.
+// http://host:port or socks5://host:port. Chrome does not accept credentials
+// in the proxy URL, so authenticated proxies must whitelist the IP.
+func (a *AuthManager) SetProxy(proxy string) {
+	a.proxy = proxy
+}
+
+// SetContainerMode launches the browser with flags that work inside a
+// container: always headless and without Chrome's sandbox, which needs
+// privileges containers do not grant
+func (a *AuthManager) SetContainerMode(container bool) {
+	a.container = container
+}
+
+// SetSeparateInstance always launches a browser of its own, with its own
+// profile and a free debugging port, so several accounts can run side by side
+func (a *AuthManager) SetSeparateInstance() {
+	a.separate = true
+}
+
+// SetExecutablePath launches the browser at path instead of looking for an
+// installed Chrome
+func (a *AuthManager) SetExecutablePath(path string) {
+	a.executablePath = path
+}
+
+// ClientWrapper wraps the browser's DevTools connection, e.g. to record the
+// actions sent through it
+type ClientWrapper interface {
+	Wrap(client rod.CDPClient) rod.CDPClient
+}
+
+// AddClientWrapper routes every DevTools call through wrapper once the
+// browser is connected. The wrapper added last sees calls first.
+func (a *AuthManager) AddClientWrapper(wrapper ClientWrapper) {
+	a.clientWrappers = append(a.clientWrappers, wrapper)
+}
+
+// connect attaches to the browser at controlURL, through the client wrappers if any are set.
+// header, if set, is sent with the WebSocket handshake.
+func (a *AuthManager) connect(controlURL string, header http.Header) error {
+	if len(a.clientWrappers) == 0 && header == nil {
+		a.browser = rod.New().ControlURL(controlURL)
+		return a.browser.Connect()
+	}
+
+	cdpClient, err := cdp.StartWithURL(context.Background(), controlURL, header)
 	if err != nil {
-		return false
+		return err
 	}
-	
-	// Check if chrome.exe is found in the output
-	return strings.Contains(string(output), "chrome.exe")
+	var client rod.CDPClient = cdpClient
+	for _, wrapper := range a.clientWrappers {
+		client = wrapper.Wrap(client)
+	}
+	a.browser = rod.New().Client(client)
+	return a.browser.Connect()
 }
 
-// getChromePath returns the path to Chrome executable
-func getChromePath() string {
-	// Common Chrome installation paths on Windows
-	paths := []string{
-		filepath.Join(os.Getenv("LOCALAPPDATA"), "Google\\Chrome\\Application\\chrome.exe"),
-		filepath.Join(os.Getenv("PROGRAMFILES"), "Google\\Chrome\\Application\\chrome.exe"),
-		filepath.Join(os.Getenv("PROGRAMFILES(X86)"), "Google\\Chrome\\Application\\chrome.exe"),
-	}
-	
-	for _, path := range paths {
+// FindChrome returns the path of an installed Chrome or Chromium executable,
+// or an empty string when none is found
+func FindChrome() string {
+	for _, path := range chromeCandidates() {
 		if _, err := os.Stat(path); err == nil {
 			return path
 		}
@@ -80,11 +134,22 @@ func getChromePath() string {
 func (a *AuthManager) InitializeBrowser(headless bool, userAgent string) error {
 	a.logger.Info("Initializing browser")
 
+	if a.remoteURL != "" {
+		return a.connectRemote()
+	}
+	if a.container {
+		headless = true
+	}
+
 	// Check if Chrome is running and use Chrome instead of Chromium
 	chromeRunning := isChromeRunning()
-	chromePath := getChromePath()
+	chromePath := FindChrome()
 	
-	if chromeRunning && chromePath != "" {
+	if a.executablePath != "" {
+		// A configured executable is always used
+		chromeRunning, chromePath = true, a.executablePath
+		a.logger.WithField("path", chromePath).Info("Using configured browser executable")
+	} else if chromeRunning && chromePath != "" {
 		a.logger.Info("Chrome process detected, using Chrome instead of Chromium")
 	} else if chromeRunning {
 		a.logger.Warn("Chrome process detected but Chrome executable not found, proceeding with default browser")
@@ -93,7 +158,7 @@ func (a *AuthManager) InitializeBrowser(headless bool, userAgent string) error {
 	}
 
 	// Try to connect to existing browser first
-	if !headless {
+	if !headless && !a.separate {
 		// Try to connect to existing Chrome instance with user's profile
 		l := launcher.New()
 		
@@ -104,13 +169,16 @@ func (a *AuthManager) InitializeBrowser(headless bool, userAgent string) error {
 		
 		l = l.Leakless(false).
 			Headless(false).
-			Set("user-data-dir", os.Getenv("LOCALAPPDATA")+"\\Google\\Chrome\\User Data").
+			Set("user-data-dir", chromeUserDataDir()).
 			Set("profile-directory", "Default").
 			Set("no-first-run", "true").
 			Set("no-default-browser-check", "true").
 			Set("disable-features", "VizDisplayCompositor").
 			Set("disable-web-security", "false").
 			Set("remote-debugging-port", "9222")
+		if a.proxy != "" {
+			l = l.Proxy(a.proxy)
+		}
 		
 		// Try to launch with remote debugging
 		url, err := l.Launch()
@@ -121,19 +189,21 @@ func (a *AuthManager) InitializeBrowser(headless bool, userAgent string) error {
 			l = launcher.New().
 				Leakless(false).
 				Headless(false).
-				Set("user-data-dir", os.Getenv("LOCALAPPDATA")+"\\Microsoft\\Edge\\User Data").
+				Set("user-data-dir", edgeUserDataDir()).
 				Set("profile-directory", "Default").
 				Set("no-first-run", "true").
 				Set("no-default-browser-check", "true").
 				Set("remote-debugging-port", "9223")
+			if a.proxy != "" {
+				l = l.Proxy(a.proxy)
+			}
 			
 			url, err = l.Launch()
 			if err != nil {
 				a.logger.Warn("Failed to connect to existing browsers, launching new one")
 			} else {
 				// Connect to Edge
-				a.browser = rod.New().ControlURL(url)
-				if err := a.browser.Connect(); err != nil {
+				if err := a.connect(url, nil); err != nil {
 					a.logger.Warn("Failed to connect to Edge, launching new one")
 				} else {
 					a.logger.Info("Connected to existing Edge successfully")
@@ -142,8 +212,7 @@ func (a *AuthManager) InitializeBrowser(headless bool, userAgent string) error {
 			}
 		} else {
 			// Connect to Chrome
-			a.browser = rod.New().ControlURL(url)
-			if err := a.browser.Connect(); err != nil {
+			if err := a.connect(url, nil); err != nil {
 				a.logger.Warn("Failed to connect to Chrome, launching new one")
 			} else {
 				a.logger.Info("Connected to existing Chrome successfully")
@@ -186,6 +255,21 @@ func (a *AuthManager) InitializeBrowser(headless bool, userAgent string) error {
 		Set("disable-dev-shm-usage", "true").
 		Set("disable-gpu", "true").
 		Set("remote-debugging-port", "9222")
+	if a.separate {
+		l = l.Set("remote-debugging-port", "0") // Let the browser pick a free port
+	}
+
+	if a.proxy != "" {
+		l = l.Proxy(a.proxy)
+		a.logger.WithField("proxy", a.proxy).Info("Using proxy")
+	}
+
+	if a.container {
+		// Root in a container cannot use the sandbox; /dev/shm is often only 64MB,
+		// which disable-dev-shm-usage above already works around
+		l = l.NoSandbox(true).Set("disable-setuid-sandbox")
+		a.logger.Info("Launching browser in container mode")
+	}
 
 	// Add user data directory for session persistence
 	// Use unique directory to avoid conflicts with existing Chrome processes
@@ -201,10 +285,10 @@ func (a *AuthManager) InitializeBrowser(headless bool, userAgent string) error {
 	if err != nil {
 		return fmt.Errorf("failed to launch browser: %w", err)
 	}
+	a.launcher = l
 
 	// Connect to browser
-	a.browser = rod.New().ControlURL(url)
-	if err := a.browser.Connect(); err != nil {
+	if err := a.connect(url, nil); err != nil {
 		return fmt.Errorf("failed to connect to browser: %w", err)
 	}
 
@@ -215,6 +299,7 @@ func (a *AuthManager) InitializeBrowser(headless bool, userAgent string) error {
 // Login performs LinkedIn login and returns authentication result
 func (a *AuthManager) Login(ctx context.Context) (*LoginResult, error) {
 	a.logger.Info("Starting LinkedIn login process")
+	startedAt := time.Now()
 
 	// Create context with extended timeout for checkpoint verification
 	ctx, cancel := context.WithTimeout(ctx, 300*time.Second) // 5 minutes for manual verification
@@ -228,8 +313,15 @@ func (a *AuthManager) Login(ctx context.Context) (*LoginResult, error) {
 		return nil, fmt.Errorf("failed to create page: %w", err)
 	}
 	defer page.Close()
+	// Bound to ctx so a cancelled or timed-out login stops waiting on the page
+	page = page.Context(ctx)
 	a.page = page
 
+	// Cookies of an earlier or imported session skip the login form
+	if err := a.loadSession(); err != nil && !os.IsNotExist(err) {
+		a.logger.WithError(err).Warn("Failed to restore saved session")
+	}
+
 	// Navigate to LinkedIn login page
 	a.logger.Info("Navigating to LinkedIn login page")
 	
@@ -257,7 +349,7 @@ func (a *AuthManager) Login(ctx context.Context) (*LoginResult, error) {
 			if err != nil {
 				a.logger.Warn("Page.WaitLoad() failed, trying alternative approach")
 				// Fallback: wait for a specific element instead
-				_, err := page.Element("input[name='session_key']")
+				_, err := page.Element(selectors.CSS(selectors.LoginEmail))
 				if err != nil {
 					return nil, fmt.Errorf("failed to find login form: %w", err)
 				}
@@ -265,7 +357,7 @@ func (a *AuthManager) Login(ctx context.Context) (*LoginResult, error) {
 		case <-time.After(20 * time.Second):
 			a.logger.Warn("Page load timeout, trying to proceed anyway")
 			// Fallback: try to proceed if we can find the login form
-			_, err := page.Element("input[name='session_key']")
+			_, err := page.Element(selectors.CSS(selectors.LoginEmail))
 			if err != nil {
 				return nil, fmt.Errorf("page load timeout and login form not found")
 			}
@@ -287,12 +379,21 @@ func (a *AuthManager) Login(ctx context.Context) (*LoginResult, error) {
 		// If we're already on a LinkedIn page (not login), we're logged in
 		if !strings.Contains(urlInfo.URL, "linkedin.com/login") {
 			a.logger.Info("Already logged in - detected by URL")
+			// LinkedIn refreshes the cookies of a live session
+			if err := a.saveSession(); err != nil {
+				a.logger.WithError(err).Warn("Failed to save session")
+			}
 			result.Success = true
 			result.SessionID = a.getSessionID()
 			return result, nil
 		}
 	}
 	
+	if a.password == "" {
+		result.ErrorMessage = "no valid saved session and no password configured; import a session cookie with 'session import --cookie'"
+		return result, nil
+	}
+
 	a.logger.Info("Not logged in, proceeding with credential filling...")
 
 	// Fill in credentials
@@ -331,7 +432,9 @@ func (a *AuthManager) Login(ctx context.Context) (*LoginResult, error) {
 
 	// Check if login succeeded by checking URL
 	a.logger.Info("Checking login status...")
-	time.Sleep(2 * time.Second) // Wait for page to settle
+	if err := pause.Sleep(ctx, 2*time.Second); err != nil { // Wait for page to settle
+		return nil, err
+	}
 	
 	urlInfo, err = a.page.Info()
 	if err == nil && urlInfo.URL != "" {
@@ -342,19 +445,32 @@ func (a *AuthManager) Login(ctx context.Context) (*LoginResult, error) {
 			// Check if we're on a checkpoint/challenge page
 			if strings.Contains(urlInfo.URL, "checkpoint") || 
 			   strings.Contains(urlInfo.URL, "challenge") {
-				a.logger.Warn("LinkedIn checkpoint/challenge detected - waiting for manual verification")
-				a.logger.Info("Please complete any verification in the browser window")
-				
-				// Wait for manual verification with extended timeout
-				select {
-				case <-ctx.Done():
-					return nil, fmt.Errorf("timeout during checkpoint verification")
-				case <-time.After(5 * time.Minute):
-					a.logger.Info("Checkpoint timeout, proceeding anyway...")
+				verified := false
+				if a.codeSource != nil {
+					if err := a.enterVerificationCode(ctx, startedAt); err != nil {
+						a.logger.WithError(err).Warn("Automatic checkpoint verification failed")
+					} else {
+						verified = true
+					}
+				}
+
+				if !verified {
+					a.logger.Warn("LinkedIn checkpoint/challenge detected - waiting for manual verification")
+					a.logger.Info("Please complete any verification in the browser window")
+
+					// Wait for manual verification with extended timeout
+					select {
+					case <-ctx.Done():
+						return nil, fmt.Errorf("timeout during checkpoint verification")
+					case <-time.After(5 * time.Minute):
+						a.logger.Info("Checkpoint timeout, proceeding anyway...")
+					}
 				}
 				
 				// Check again after waiting
-				time.Sleep(2 * time.Second)
+				if err := pause.Sleep(ctx, 2*time.Second); err != nil {
+					return nil, err
+				}
 				urlInfo, err = a.page.Info()
 				if err == nil && urlInfo.URL != "" {
 					// If we're still on checkpoint, fail
@@ -399,6 +515,12 @@ func (a *AuthManager) Login(ctx context.Context) (*LoginResult, error) {
 			return result, nil
 		}
 
+		if a.requiresCaptcha() && a.captchaSolver != nil {
+			if err := a.solveCaptcha(ctx); err != nil {
+				a.logger.WithError(err).Warn("Automatic CAPTCHA solving failed")
+			}
+		}
+
 		if a.requiresCaptcha() {
 			a.logger.Warn("Login requires CAPTCHA - attempting manual handling")
 			if err := a.handleCaptchaManually(ctx); err != nil {
@@ -409,7 +531,9 @@ func (a *AuthManager) Login(ctx context.Context) (*LoginResult, error) {
 			a.logger.Info("CAPTCHA handled successfully, proceeding...")
 			
 			// Check again after CAPTCHA
-			time.Sleep(2 * time.Second)
+			if err := pause.Sleep(ctx, 2*time.Second); err != nil {
+				return nil, err
+			}
 			urlInfo, err = a.page.Info()
 			if err == nil && urlInfo.URL != "" && !strings.Contains(urlInfo.URL, "linkedin.com/login") {
 				a.logger.Info("Login successful after CAPTCHA")
@@ -468,6 +592,7 @@ func (a *AuthManager) VerifySession(ctx context.Context) (bool, error) {
 		return false, fmt.Errorf("failed to create page: %w", err)
 	}
 	defer page.Close()
+	page = page.Context(ctx)
 
 	// Navigate to LinkedIn homepage
 	if err := page.Navigate("https://www.linkedin.com"); err != nil {
@@ -506,7 +631,7 @@ func (a *AuthManager) GetAuthenticatedPage(ctx context.Context) (*rod.Page, erro
 		// Try to login again
 		result, err := a.Login(ctx)
 		if err != nil || !result.Success {
-			return nil, fmt.Errorf("authentication failed: %v", err)
+			return nil, fmt.Errorf("%w: %v", errs.ErrLoginFailed, err)
 		}
 
 		// Create new page after login
@@ -519,12 +644,31 @@ func (a *AuthManager) GetAuthenticatedPage(ctx context.Context) (*rod.Page, erro
 	return page, nil
 }
 
+// NewPage opens a blank page without checking the login state
+func (a *AuthManager) NewPage() (*rod.Page, error) {
+	if a.browser == nil {
+		return nil, fmt.Errorf("browser not initialized")
+	}
+
+	page, err := a.browser.Page(proto.TargetCreateTarget{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create page: %w", err)
+	}
+	return page, nil
+}
+
 // Close closes the browser
 func (a *AuthManager) Close() error {
-	if a.browser != nil {
-		return a.browser.Close()
+	var err error
+	// A remote browser is shared and outlives the run; only its pages are closed
+	if a.browser != nil && a.remoteURL == "" {
+		err = a.browser.Close()
 	}
-	return nil
+	// Leakless is disabled, so make sure a browser we started does not outlive us
+	if a.launcher != nil {
+		a.launcher.Kill()
+	}
+	return err
 }
 
 // Helper function to wait for element with timeout
@@ -592,12 +736,18 @@ func (a *AuthManager) inputText(selector, text string, timeout time.Duration) er
 			}
 			
 			// Add typing delay
-			time.Sleep(typingDelay)
+			if err := pause.Page(a.page, typingDelay); err != nil {
+				done <- err
+				return
+			}
 			
 			// Occasional longer pause (thinking)
 			if i > 0 && i%5 == 0 && a.rng.Float64() < 0.3 {
 				pauseTime := time.Duration(200 + a.rng.Intn(300)) * time.Millisecond
-				time.Sleep(pauseTime)
+				if err := pause.Page(a.page, pauseTime); err != nil {
+					done <- err
+					return
+				}
 			}
 		}
 		
@@ -650,64 +800,80 @@ func (a *AuthManager) fillCredentials() error {
 	a.logger.Info("Filling login credentials")
 	
 	// Wait for email field with timeout
-	if err := a.waitForElement("input[name='session_key']", 10*time.Second); err != nil {
+	if err := a.waitForElement(selectors.CSS(selectors.LoginEmail), 10*time.Second); err != nil {
 		return fmt.Errorf("email field not found: %w", err)
 	}
 
 	// Human-like pause before starting to fill
-	time.Sleep(time.Duration(1000 + a.rng.Intn(2000)) * time.Millisecond)
+	if err := pause.Page(a.page, time.Duration(1000 + a.rng.Intn(2000)) * time.Millisecond); err != nil {
+		return err
+	}
 
 	// Clear and fill email
-	if err := a.clickElement("input[name='session_key']", 5*time.Second); err != nil {
+	if err := a.clickElement(selectors.CSS(selectors.LoginEmail), 5*time.Second); err != nil {
 		return fmt.Errorf("failed to click email field: %w", err)
 	}
 
 	// Pause after clicking field
-	time.Sleep(time.Duration(200 + a.rng.Intn(300)) * time.Millisecond)
+	if err := pause.Page(a.page, time.Duration(200 + a.rng.Intn(300)) * time.Millisecond); err != nil {
+		return err
+	}
 
-	if err := a.inputText("input[name='session_key']", "", 5*time.Second); err != nil {
+	if err := a.inputText(selectors.CSS(selectors.LoginEmail), "", 5*time.Second); err != nil {
 		return fmt.Errorf("failed to clear email field: %w", err)
 	}
 
 	// Brief pause before typing email
-	time.Sleep(time.Duration(100 + a.rng.Intn(200)) * time.Millisecond)
+	if err := pause.Page(a.page, time.Duration(100 + a.rng.Intn(200)) * time.Millisecond); err != nil {
+		return err
+	}
 
-	if err := a.inputText("input[name='session_key']", a.email, 15*time.Second); err != nil {
+	if err := a.inputText(selectors.CSS(selectors.LoginEmail), a.email, 15*time.Second); err != nil {
 		return fmt.Errorf("failed to input email: %w", err)
 	}
 
 	// Human-like pause between email and password
-	time.Sleep(time.Duration(1000 + a.rng.Intn(1500)) * time.Millisecond)
+	if err := pause.Page(a.page, time.Duration(1000 + a.rng.Intn(1500)) * time.Millisecond); err != nil {
+		return err
+	}
 
 	// Wait for password field with timeout
-	if err := a.waitForElement("input[name='session_password']", 10*time.Second); err != nil {
+	if err := a.waitForElement(selectors.CSS(selectors.LoginPassword), 10*time.Second); err != nil {
 		return fmt.Errorf("password field not found: %w", err)
 	}
 
 	// Pause before clicking password field
-	time.Sleep(time.Duration(200 + a.rng.Intn(300)) * time.Millisecond)
+	if err := pause.Page(a.page, time.Duration(200 + a.rng.Intn(300)) * time.Millisecond); err != nil {
+		return err
+	}
 
 	// Clear and fill password
-	if err := a.clickElement("input[name='session_password']", 5*time.Second); err != nil {
+	if err := a.clickElement(selectors.CSS(selectors.LoginPassword), 5*time.Second); err != nil {
 		return fmt.Errorf("failed to click password field: %w", err)
 	}
 
 	// Pause after clicking field
-	time.Sleep(time.Duration(200 + a.rng.Intn(300)) * time.Millisecond)
+	if err := pause.Page(a.page, time.Duration(200 + a.rng.Intn(300)) * time.Millisecond); err != nil {
+		return err
+	}
 
-	if err := a.inputText("input[name='session_password']", "", 5*time.Second); err != nil {
+	if err := a.inputText(selectors.CSS(selectors.LoginPassword), "", 5*time.Second); err != nil {
 		return fmt.Errorf("failed to clear password field: %w", err)
 	}
 
 	// Brief pause before typing password
-	time.Sleep(time.Duration(100 + a.rng.Intn(200)) * time.Millisecond)
+	if err := pause.Page(a.page, time.Duration(100 + a.rng.Intn(200)) * time.Millisecond); err != nil {
+		return err
+	}
 
-	if err := a.inputText("input[name='session_password']", a.password, 15*time.Second); err != nil {
+	if err := a.inputText(selectors.CSS(selectors.LoginPassword), a.password, 15*time.Second); err != nil {
 		return fmt.Errorf("failed to input password: %w", err)
 	}
 
 	// Final pause before submitting
-	time.Sleep(time.Duration(1500 + a.rng.Intn(2000)) * time.Millisecond)
+	if err := pause.Page(a.page, time.Duration(1500 + a.rng.Intn(2000)) * time.Millisecond); err != nil {
+		return err
+	}
 
 	a.logger.Info("Credentials filled successfully")
 	return nil
@@ -717,14 +883,16 @@ func (a *AuthManager) submitLogin() error {
 	a.logger.Info("Submitting login form")
 	
 	// Find and click login button with timeout
-	if err := a.waitForElement("button[type='submit']", 10*time.Second); err != nil {
+	if err := a.waitForElement(selectors.CSS(selectors.LoginSubmit), 10*time.Second); err != nil {
 		return fmt.Errorf("login button not found: %w", err)
 	}
 
 	// Human-like hesitation before clicking submit
-	time.Sleep(time.Duration(1000 + a.rng.Intn(2000)) * time.Millisecond)
+	if err := pause.Page(a.page, time.Duration(1000 + a.rng.Intn(2000)) * time.Millisecond); err != nil {
+		return err
+	}
 
-	if err := a.clickElement("button[type='submit']", 5*time.Second); err != nil {
+	if err := a.clickElement(selectors.CSS(selectors.LoginSubmit), 5*time.Second); err != nil {
 		return fmt.Errorf("failed to click login button: %w", err)
 	}
 
@@ -741,7 +909,7 @@ func (a *AuthManager) requires2FA() bool {
 	}()
 	
 	// Check for 2FA input field - most reliable indicator
-	_, err := a.page.Element("input[name='pin']")
+	_, err := a.page.Element(selectors.CSS(selectors.LoginPin))
 	if err == nil {
 		return true
 	}
@@ -777,6 +945,7 @@ func (a *AuthManager) handleCaptchaManually(ctx context.Context) error {
 		return fmt.Errorf("failed to create page: %w", err)
 	}
 	defer page.Close()
+	page = page.Context(ctx)
 	a.page = page
 	
 	// Navigate to login again
@@ -785,7 +954,9 @@ func (a *AuthManager) handleCaptchaManually(ctx context.Context) error {
 	}
 	
 	// Wait for page load
-	time.Sleep(3 * time.Second)
+	if err := pause.Sleep(ctx, 3*time.Second); err != nil {
+		return err
+	}
 	
 	// Fill credentials again
 	if err := a.fillCredentials(); err != nil {
@@ -810,7 +981,9 @@ func (a *AuthManager) handleCaptchaManually(ctx context.Context) error {
 	}
 	
 	// Check if login succeeded after CAPTCHA
-	time.Sleep(2 * time.Second)
+	if err := pause.Sleep(ctx, 2*time.Second); err != nil {
+		return err
+	}
 	if a.isLoggedIn() {
 		a.logger.Info("CAPTCHA solved successfully - logged in!")
 		return nil
@@ -841,14 +1014,7 @@ func (a *AuthManager) requiresCaptcha() bool {
 
 func (a *AuthManager) getLoginError() string {
 	// Check for error messages
-	errorElements := []string{
-		".alert-error",
-		".login__form-error",
-		".form-error",
-		"[data-test-id='error']",
-	}
-
-	for _, selector := range errorElements {
+	for _, selector := range selectors.Get(selectors.LoginError) {
 		errorElement, err := a.page.Element(selector)
 		if err == nil && errorElement != nil {
 			errorText, err := errorElement.Text()
@@ -879,43 +1045,23 @@ func (a *AuthManager) getSessionID() string {
 	return ""
 }
 
-func (a *AuthManager) saveSession() error {
-	if err := os.MkdirAll(a.sessionPath, 0755); err != nil {
-		return fmt.Errorf("failed to create session directory: %w", err)
-	}
-
-	// Get current cookies
-	currentCookies, err := a.page.Cookies([]string{"https://www.linkedin.com"})
+// SessionCookies returns the li_at and JSESSIONID cookies of the logged-in
+// session, which authenticate requests to LinkedIn's internal API
+func (a *AuthManager) SessionCookies() (liAt, jsessionID string) {
+	cookies, err := a.page.Cookies([]string{"https://www.linkedin.com"})
 	if err != nil {
-		return fmt.Errorf("failed to get cookies: %w", err)
-	}
-
-	// Save session info
-	sessionFile := filepath.Join(a.sessionPath, "session.json")
-	_ = map[string]interface{}{
-		"cookies":    currentCookies,
-		"created_at": time.Now(),
-		"user_agent": a.page.MustEval("navigator.userAgent").String(),
+		a.logger.WithError(err).Warn("Failed to get cookies")
+		return "", ""
 	}
 
-	// In a real implementation, you would save this to a file
-	// For now, we'll just log the session info
-	a.logger.WithFields(logrus.Fields{
-		"cookies_count": len(currentCookies),
-		"session_file":  sessionFile,
-	}).Info("Session saved")
-
-	return nil
-}
-
-func (a *AuthManager) loadSession() error {
-	sessionFile := filepath.Join(a.sessionPath, "session.json")
-	if _, err := os.Stat(sessionFile); os.IsNotExist(err) {
-		return fmt.Errorf("no session file found")
+	for _, cookie := range cookies {
+		switch cookie.Name {
+		case "li_at":
+			liAt = cookie.Value
+		case "JSESSIONID":
+			jsessionID = cookie.Value
+		}
 	}
 
-	// In a real implementation, you would load and restore cookies
-	// For now, we'll just log that we found a session file
-	a.logger.WithField("session_file", sessionFile).Info("Session file found")
-	return nil
+	return liAt, jsessionID
 }
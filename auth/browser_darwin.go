@@ -0,0 +1,37 @@
+//go:build darwin
+
+package auth
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// isChromeRunning checks if any Chrome process is running
+func isChromeRunning() bool {
+	// pgrep exits non-zero when nothing matches
+	return exec.Command("pgrep", "-x", "Google Chrome").Run() == nil
+}
+
+// chromeCandidates returns the common Chrome installation paths on macOS
+func chromeCandidates() []string {
+	const bundle = "Google Chrome.app/Contents/MacOS/Google Chrome"
+	home, _ := os.UserHomeDir()
+	return []string{
+		filepath.Join("/Applications", bundle),
+		filepath.Join(home, "Applications", bundle),
+	}
+}
+
+// chromeUserDataDir returns the user's own Chrome profile directory
+func chromeUserDataDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, "Library", "Application Support", "Google", "Chrome")
+}
+
+// edgeUserDataDir returns the user's own Edge profile directory
+func edgeUserDataDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, "Library", "Application Support", "Microsoft Edge")
+}
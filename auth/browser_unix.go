@@ -0,0 +1,61 @@
+//go:build !windows && !darwin
+
+package auth
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// chromeBinaries are the executable names Chrome and Chromium are packaged under
+var chromeBinaries = []string{"google-chrome", "google-chrome-stable", "chromium", "chromium-browser"}
+
+// isChromeRunning checks if any Chrome or Chromium process is running
+func isChromeRunning() bool {
+	for _, name := range chromeBinaries {
+		// pgrep exits non-zero when nothing matches
+		if exec.Command("pgrep", "-x", name).Run() == nil {
+			return true
+		}
+	}
+	// The Chrome process is named "chrome" regardless of its launcher script
+	return exec.Command("pgrep", "-x", "chrome").Run() == nil
+}
+
+// chromeCandidates returns the Chrome and Chromium executables found on PATH
+// and in the usual install locations
+func chromeCandidates() []string {
+	var paths []string
+	for _, name := range chromeBinaries {
+		if path, err := exec.LookPath(name); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	return append(paths,
+		"/opt/google/chrome/chrome",
+		"/usr/bin/google-chrome",
+		"/usr/bin/chromium",
+		"/usr/bin/chromium-browser",
+		"/snap/bin/chromium",
+	)
+}
+
+// chromeUserDataDir returns the user's own Chrome profile directory
+func chromeUserDataDir() string {
+	return filepath.Join(configHome(), "google-chrome")
+}
+
+// edgeUserDataDir returns the user's own Edge profile directory
+func edgeUserDataDir() string {
+	return filepath.Join(configHome(), "microsoft-edge")
+}
+
+// configHome returns $XDG_CONFIG_HOME, or ~/.config when it is unset
+func configHome() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config")
+}
@@ -0,0 +1,41 @@
+//go:build windows
+
+package auth
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// isChromeRunning checks if any Chrome process is running
+func isChromeRunning() bool {
+	cmd := exec.Command("tasklist", "/FI", "IMAGENAME eq chrome.exe", "/FO", "CSV")
+	output, err := cmd.Output()
+	if err != nil {
+		return false
+	}
+
+	// Check if chrome.exe is found in the output
+	return strings.Contains(string(output), "chrome.exe")
+}
+
+// chromeCandidates returns the common Chrome installation paths on Windows
+func chromeCandidates() []string {
+	return []string{
+		filepath.Join(os.Getenv("LOCALAPPDATA"), "Google\\Chrome\\Application\\chrome.exe"),
+		filepath.Join(os.Getenv("PROGRAMFILES"), "Google\\Chrome\\Application\\chrome.exe"),
+		filepath.Join(os.Getenv("PROGRAMFILES(X86)"), "Google\\Chrome\\Application\\chrome.exe"),
+	}
+}
+
+// chromeUserDataDir returns the user's own Chrome profile directory
+func chromeUserDataDir() string {
+	return filepath.Join(os.Getenv("LOCALAPPDATA"), "Google\\Chrome\\User Data")
+}
+
+// edgeUserDataDir returns the user's own Edge profile directory
+func edgeUserDataDir() string {
+	return filepath.Join(os.Getenv("LOCALAPPDATA"), "Microsoft\\Edge\\User Data")
+}
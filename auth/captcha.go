@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/captcha"
+	"linkedin-automation/selectors"
+)
+
+// CaptchaSolver solves login challenges through an external service
+type CaptchaSolver interface {
+	Name() string
+	SolveImage(ctx context.Context, image []byte) (string, error)
+	SolveFunCaptcha(ctx context.Context, challenge captcha.FunCaptcha) (string, error)
+}
+
+// SetCaptchaSolver lets Login solve CAPTCHA challenges automatically; the
+// manual non-headless fallback is used if the solver fails
+func (a *AuthManager) SetCaptchaSolver(solver CaptchaSolver) {
+	a.captchaSolver = solver
+}
+
+// solveCaptcha solves the challenge on the current page with the configured
+// solver and waits for LinkedIn to accept it
+func (a *AuthManager) solveCaptcha(ctx context.Context) error {
+	log := a.logger.WithField("provider", a.captchaSolver.Name())
+
+	if image, _ := selectors.Find(a.page, selectors.CaptchaImage); image != nil {
+		log.Info("Solving image CAPTCHA")
+		if err := a.solveImageCaptcha(ctx, image); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Solving FunCaptcha challenge")
+		if err := a.solveFunCaptcha(ctx); err != nil {
+			return err
+		}
+	}
+
+	// LinkedIn verifies the answer and redirects off the challenge page
+	for i := 0; i < 15; i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+		if !a.requiresCaptcha() {
+			log.Info("CAPTCHA solved")
+			return nil
+		}
+	}
+	return fmt.Errorf("challenge still shown after submitting the %s solution", a.captchaSolver.Name())
+}
+
+func (a *AuthManager) solveImageCaptcha(ctx context.Context, image *rod.Element) error {
+	data, err := image.Resource()
+	if err != nil {
+		return fmt.Errorf("failed to read CAPTCHA image: %w", err)
+	}
+
+	text, err := a.captchaSolver.SolveImage(ctx, data)
+	if err != nil {
+		return fmt.Errorf("failed to solve image CAPTCHA: %w", err)
+	}
+
+	input, _ := selectors.Find(a.page, selectors.CaptchaImageInput)
+	if input == nil {
+		return fmt.Errorf("CAPTCHA answer field not found")
+	}
+	if err := input.Input(text); err != nil {
+		return fmt.Errorf("failed to enter CAPTCHA answer: %w", err)
+	}
+
+	submit, _ := selectors.Find(a.page, selectors.CaptchaImageSubmit)
+	if submit == nil {
+		return fmt.Errorf("CAPTCHA submit button not found")
+	}
+	return submit.Click("left", 1)
+}
+
+// solveFunCaptcha obtains an Arkose Labs token and injects it into the
+// challenge form, which LinkedIn normally fills once the puzzle is completed
+func (a *AuthManager) solveFunCaptcha(ctx context.Context) error {
+	info, err := a.page.Info()
+	if err != nil {
+		return err
+	}
+
+	token, err := a.captchaSolver.SolveFunCaptcha(ctx, captcha.FunCaptcha{
+		PublicKey: a.funCaptchaPublicKey(),
+		PageURL:   info.URL,
+		Subdomain: captcha.LinkedInFunCaptchaSubdomain,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to solve FunCaptcha: %w", err)
+	}
+
+	// The form lives on the page itself on current layouts, inside the
+	// challenge frame on older ones
+	pages := []*rod.Page{a.page}
+	if frame, _ := selectors.Find(a.page, selectors.CaptchaFrame); frame != nil {
+		if framePage, err := frame.Frame(); err == nil {
+			pages = append(pages, framePage)
+		}
+	}
+
+	for _, page := range pages {
+		input, _ := selectors.Find(page, selectors.CaptchaTokenInput)
+		if input == nil {
+			continue
+		}
+		if _, err := input.Eval(`function (token) { this.value = token; }`, token); err != nil {
+			return fmt.Errorf("failed to inject CAPTCHA token: %w", err)
+		}
+
+		form, _ := selectors.Find(page, selectors.CaptchaForm)
+		if form == nil {
+			return fmt.Errorf("CAPTCHA form not found")
+		}
+		if _, err := form.Eval(`function () { this.submit(); }`); err != nil {
+			return fmt.Errorf("failed to submit CAPTCHA form: %w", err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("CAPTCHA token field not found")
+}
+
+// funCaptchaPublicKey reads the challenge's public key from the pk parameter
+// of its frame, falling back to LinkedIn's known key
+func (a *AuthManager) funCaptchaPublicKey() string {
+	for _, selector := range selectors.Get(selectors.CaptchaFrame) {
+		frames, err := a.page.Elements(selector)
+		if err != nil {
+			continue
+		}
+		for _, frame := range frames {
+			src, err := frame.Attribute("src")
+			if err != nil || src == nil {
+				continue
+			}
+			if parsed, err := url.Parse(*src); err == nil {
+				if key := parsed.Query().Get("pk"); key != "" {
+					return key
+				}
+			}
+		}
+	}
+	return captcha.LinkedInFunCaptchaKey
+}
@@ -0,0 +1,185 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/errs"
+	"linkedin-automation/pause"
+	"linkedin-automation/ratelimit"
+)
+
+// MonitorConfig controls how a running session is watched and restored
+type MonitorConfig struct {
+	Interval   time.Duration // Between background session checks; 0 only watches the pages
+	Attempts   int           // Logins to try before giving up; 0 fails at once
+	RetryDelay time.Duration // Pause between login attempts
+}
+
+// loggedOutPaths are where LinkedIn sends a browser whose session ended
+var loggedOutPaths = []string{"linkedin.com/login", "linkedin.com/authwall", "linkedin.com/uas/login"}
+
+// Monitor keeps a browser session logged in while a batch or the queue runs.
+// It wraps the session's rate limiter, which every action asks first: when a
+// background check or one of the watched pages shows that LinkedIn logged the
+// account out, the next action waits while the account logs in again, and
+// only fails with errs.ErrSessionExpired once every attempt did.
+type Monitor struct {
+	limiter ratelimit.Limiter
+	auth    *AuthManager
+	config  MonitorConfig
+	logger  *logrus.Logger
+	expired atomic.Bool
+	login   sync.Mutex // Held by background checks and logins, which use the browser's cookies
+	mu      sync.Mutex
+	pages   []*rod.Page
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewMonitor watches the session of a, gating limiter, and starts the
+// background checks. Stop them with Stop.
+func NewMonitor(a *AuthManager, limiter ratelimit.Limiter, config MonitorConfig, logger *logrus.Logger) *Monitor {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Monitor{
+		limiter: limiter,
+		auth:    a,
+		config:  config,
+		logger:  logger,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go m.run(ctx)
+	return m
+}
+
+// Watch adds a page the session works in; landing on the login page shows
+// the session ended
+func (m *Monitor) Watch(page *rod.Page) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pages = append(m.pages, page)
+}
+
+// Unwrap returns the limiter the monitor gates
+func (m *Monitor) Unwrap() ratelimit.Limiter {
+	return m.limiter
+}
+
+// Stop ends the background checks
+func (m *Monitor) Stop() {
+	m.cancel()
+	<-m.done
+}
+
+// WaitForPermission logs in again first if the session was found to have
+// ended, then defers to the wrapped limiter
+func (m *Monitor) WaitForPermission(ctx context.Context, action ratelimit.ActionType) error {
+	if url := m.loggedOutPage(); url != "" && !m.expired.Swap(true) {
+		m.logger.WithField("url", url).Warn("Page was sent to the login page, LinkedIn session ended")
+	}
+	if m.expired.Load() {
+		if err := m.reauthenticate(ctx); err != nil {
+			return err
+		}
+	}
+	return m.limiter.WaitForPermission(ctx, action)
+}
+
+// run checks the session every interval until Stop is called
+func (m *Monitor) run(ctx context.Context) {
+	defer close(m.done)
+	if m.config.Interval <= 0 {
+		<-ctx.Done()
+		return
+	}
+
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if m.expired.Load() {
+			continue
+		}
+
+		m.login.Lock()
+		loggedIn, err := m.auth.VerifySession(ctx)
+		m.login.Unlock()
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			m.logger.WithError(err).Warn("Failed to check LinkedIn session")
+		case !loggedIn:
+			m.logger.Warn("Session check found the account logged out")
+			m.expired.Store(true)
+		default:
+			m.logger.Debug("LinkedIn session still valid")
+		}
+	}
+}
+
+// loggedOutPage returns the URL of a watched page LinkedIn sent to log in, or ""
+func (m *Monitor) loggedOutPage() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, page := range m.pages {
+		info, err := page.Info()
+		if err != nil {
+			continue // Closed tabs stay in the list
+		}
+		for _, path := range loggedOutPaths {
+			if strings.Contains(info.URL, path) {
+				return info.URL
+			}
+		}
+	}
+	return ""
+}
+
+// reauthenticate pauses work until the account is logged in again. Each
+// attempt restores the saved session first, so a fresh cookie imported with
+// 'session import' meanwhile is picked up, then falls back to the password.
+func (m *Monitor) reauthenticate(ctx context.Context) error {
+	m.login.Lock()
+	defer m.login.Unlock()
+	if !m.expired.Load() {
+		return nil // Another tab logged in while this one waited
+	}
+
+	for attempt := 1; attempt <= m.config.Attempts; attempt++ {
+		m.logger.WithField("attempt", attempt).Warn("Pausing work to log in to LinkedIn again")
+		result, err := m.auth.Login(ctx)
+		if err == nil && result.Success {
+			m.expired.Store(false)
+			m.logger.Info("Logged in again, resuming work")
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err == nil {
+			err = fmt.Errorf("%s", result.ErrorMessage)
+		}
+		m.logger.WithError(err).WithField("attempt", attempt).Warn("Failed to log in again")
+
+		if attempt < m.config.Attempts {
+			ratelimit.NotifyWait(ctx, "new login", m.config.RetryDelay)
+			if err := pause.Sleep(ctx, m.config.RetryDelay); err != nil {
+				return err
+			}
+		}
+	}
+	return fmt.Errorf("%w: could not log in again", errs.ErrSessionExpired)
+}
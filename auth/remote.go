@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-rod/rod/lib/launcher"
+)
+
+// SetRemoteURL attaches to an already-running browser at the given DevTools
+// endpoint instead of launching one. A ws:// or wss:// URL, such as a
+// browserless endpoint with a ?token= query, is dialled as is; an http(s)://
+// URL, host:port or bare port is resolved through /json/version. Credentials
+// in the URL (user:password@host) are sent as basic authentication.
+func (a *AuthManager) SetRemoteURL(remoteURL string) {
+	a.remoteURL = remoteURL
+}
+
+// connectRemote attaches to the browser at the configured remote URL
+func (a *AuthManager) connectRemote() error {
+	controlURL, header, err := resolveRemoteURL(a.remoteURL)
+	if err != nil {
+		return fmt.Errorf("invalid remote debugging URL: %w", err)
+	}
+	if a.proxy != "" {
+		a.logger.Warn("Ignoring browser proxy, the remote browser's own network settings apply")
+	}
+	if a.executablePath != "" {
+		a.logger.Warn("Ignoring browser executable path, attaching to a remote browser")
+	}
+
+	if err := a.connect(controlURL, header); err != nil {
+		return fmt.Errorf("failed to connect to remote browser: %w", err)
+	}
+
+	a.logger.WithField("host", hostOf(controlURL)).Info("Connected to remote browser")
+	return nil
+}
+
+// resolveRemoteURL returns the WebSocket URL to dial and the headers that
+// authenticate the handshake
+func resolveRemoteURL(remoteURL string) (string, http.Header, error) {
+	remoteURL = strings.TrimSpace(remoteURL)
+
+	var header http.Header
+	if strings.Contains(remoteURL, "://") {
+		parsed, err := url.Parse(remoteURL)
+		if err != nil {
+			return "", nil, err
+		}
+		if parsed.User != nil {
+			// The WebSocket dialler does not send URL credentials itself
+			password, _ := parsed.User.Password()
+			credentials := base64.StdEncoding.EncodeToString([]byte(parsed.User.Username() + ":" + password))
+			header = http.Header{"Authorization": {"Basic " + credentials}}
+			parsed.User = nil
+			remoteURL = parsed.String()
+		}
+		if parsed.Scheme == "ws" || parsed.Scheme == "wss" {
+			return remoteURL, header, nil
+		}
+	}
+
+	// An HTTP endpoint advertises the browser's WebSocket URL
+	controlURL, err := launcher.ResolveURL(remoteURL)
+	if err != nil {
+		return "", nil, err
+	}
+	if controlURL == "" {
+		return "", nil, fmt.Errorf("%s did not report a WebSocket debugger URL", remoteURL)
+	}
+	return controlURL, header, nil
+}
+
+// hostOf returns the host of rawURL for logging, leaving out paths and tokens
+func hostOf(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Host
+}
@@ -0,0 +1,222 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/errs"
+)
+
+// sessionFile is the file in the session directory holding the cookies of the
+// last logged-in session
+const sessionFile = "session.json"
+
+const (
+	cookieDomain = ".linkedin.com"
+	feedURL      = "https://www.linkedin.com/feed/"
+)
+
+// SavedSession is a logged-in LinkedIn session as kept in the session
+// directory and exchanged by 'session export' and 'session import'
+type SavedSession struct {
+	Cookies   []*proto.NetworkCookie `json:"cookies"`
+	CreatedAt time.Time              `json:"created_at"`
+	UserAgent string                 `json:"user_agent,omitempty"`
+}
+
+// Cookie returns the value of the named cookie, or "" when it is not set
+func (s *SavedSession) Cookie(name string) string {
+	for _, cookie := range s.Cookies {
+		if cookie.Name == name {
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+// CookieSession builds a session from an li_at cookie copied out of a
+// browser, and its JSESSIONID if known; LinkedIn issues a new JSESSIONID to
+// a session without one. The value may be pasted as li_at=... or quoted.
+func CookieSession(liAt, jsessionID string) (*SavedSession, error) {
+	liAt = cookieValue("li_at", liAt)
+	if err := checkCookie("li_at", liAt); err != nil {
+		return nil, err
+	}
+	session := &SavedSession{
+		Cookies:   []*proto.NetworkCookie{linkedInCookie("li_at", liAt)},
+		CreatedAt: time.Now(),
+	}
+
+	if jsessionID != "" {
+		// The CSRF token LinkedIn reads from JSESSIONID keeps its quotes
+		jsessionID = `"` + cookieValue("JSESSIONID", jsessionID) + `"`
+		if err := checkCookie("JSESSIONID", strings.Trim(jsessionID, `"`)); err != nil {
+			return nil, err
+		}
+		cookie := linkedInCookie("JSESSIONID", jsessionID)
+		cookie.HTTPOnly = false
+		session.Cookies = append(session.Cookies, cookie)
+	}
+	return session, nil
+}
+
+// cookieValue strips the name= prefix and quotes a copied cookie value may carry
+func cookieValue(name, value string) string {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(value, name+"=")
+	return strings.Trim(value, `"`)
+}
+
+// checkCookie rejects values that cannot be a cookie, such as an empty one or
+// several cookies pasted together
+func checkCookie(name, value string) error {
+	if value == "" {
+		return fmt.Errorf("%s cookie is empty", name)
+	}
+	if strings.ContainsAny(value, " \t\r\n;,\"") {
+		return fmt.Errorf("%s cookie must be a single cookie value, without spaces, ';' or ','", name)
+	}
+	return nil
+}
+
+func linkedInCookie(name, value string) *proto.NetworkCookie {
+	return &proto.NetworkCookie{
+		Name:     name,
+		Value:    value,
+		Domain:   cookieDomain,
+		Path:     "/",
+		Expires:  proto.TimeSinceEpoch(time.Now().AddDate(1, 0, 0).Unix()),
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: proto.NetworkCookieSameSiteNone,
+	}
+}
+
+// ReadSession reads the session saved in sessionPath; the error matches
+// os.ErrNotExist when there is none
+func ReadSession(sessionPath string) (*SavedSession, error) {
+	data, err := os.ReadFile(filepath.Join(sessionPath, sessionFile))
+	if err != nil {
+		return nil, err
+	}
+	return ParseSession(data)
+}
+
+// ParseSession decodes a session written by 'session export'
+func ParseSession(data []byte) (*SavedSession, error) {
+	var session SavedSession
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse session: %w", err)
+	}
+	if session.Cookie("li_at") == "" {
+		return nil, fmt.Errorf("session has no li_at cookie")
+	}
+	return &session, nil
+}
+
+// WriteSession saves session in sessionPath, readable by the owner only since
+// its cookies log in as the account
+func WriteSession(sessionPath string, session *SavedSession) error {
+	if err := os.MkdirAll(sessionPath, 0755); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(sessionPath, sessionFile), append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	return nil
+}
+
+// ImportSession sets the cookies of session in the browser and opens the feed
+// to check that LinkedIn accepts them, then saves the session as LinkedIn
+// left it, with any cookies it added, for later runs to log in with
+func (a *AuthManager) ImportSession(ctx context.Context, session *SavedSession) error {
+	if a.browser == nil {
+		return fmt.Errorf("browser not initialized")
+	}
+	if err := a.browser.SetCookies(cookieParams(session.Cookies)); err != nil {
+		return fmt.Errorf("failed to set cookies: %w", err)
+	}
+
+	page, err := a.browser.Page(proto.TargetCreateTarget{})
+	if err != nil {
+		return fmt.Errorf("failed to create page: %w", err)
+	}
+	defer page.Close()
+	page = page.Context(ctx)
+	a.page = page
+
+	if err := page.Navigate(feedURL); err != nil {
+		return fmt.Errorf("failed to navigate to feed: %w", err)
+	}
+	if err := page.WaitLoad(); err != nil {
+		return fmt.Errorf("failed to wait for page load: %w", err)
+	}
+	if !a.checkPageLoginStatus(page) {
+		return fmt.Errorf("%w: linkedin did not accept the cookie", errs.ErrSessionExpired)
+	}
+	return a.saveSession()
+}
+
+// saveSession writes the cookies of the logged-in page to the session file
+func (a *AuthManager) saveSession() error {
+	cookies, err := a.page.Cookies([]string{"https://www.linkedin.com"})
+	if err != nil {
+		return fmt.Errorf("failed to get cookies: %w", err)
+	}
+
+	session := &SavedSession{Cookies: cookies, CreatedAt: time.Now()}
+	if userAgent, err := a.page.Eval("() => navigator.userAgent"); err == nil {
+		session.UserAgent = userAgent.Value.String()
+	}
+	if err := WriteSession(a.sessionPath, session); err != nil {
+		return err
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"cookies_count": len(cookies),
+		"session_file":  filepath.Join(a.sessionPath, sessionFile),
+	}).Info("Session saved")
+	return nil
+}
+
+// loadSession sets the cookies of the saved session in the browser, so the
+// login page forwards to the feed instead of asking for the password
+func (a *AuthManager) loadSession() error {
+	session, err := ReadSession(a.sessionPath)
+	if err != nil {
+		return err
+	}
+	if err := a.browser.SetCookies(cookieParams(session.Cookies)); err != nil {
+		return fmt.Errorf("failed to restore cookies: %w", err)
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"cookies_count": len(session.Cookies),
+		"saved_at":      session.CreatedAt,
+	}).Info("Session restored")
+	return nil
+}
+
+// cookieParams converts saved cookies for setting them in the browser. Those
+// without an expiry were only valid until the browser closed and stay so.
+func cookieParams(cookies []*proto.NetworkCookie) []*proto.NetworkCookieParam {
+	params := proto.CookiesToParams(cookies)
+	for i, cookie := range cookies {
+		if cookie.Session || cookie.Expires <= 0 {
+			params[i].Expires = 0
+		}
+	}
+	return params
+}
@@ -0,0 +1,251 @@
+// Package backup takes scheduled backups of the database, keeps a checksum
+// next to each one so it can be verified before a restore, and removes the
+// oldest backups beyond the retention limit.
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/storage"
+)
+
+// filePrefix starts the name of every backup file, followed by its timestamp
+const filePrefix = "linkedin-"
+
+// checksumExtension is appended to a backup's name for its SHA-256 file
+const checksumExtension = ".sha256"
+
+// Database is the database being backed up
+type Database interface {
+	Backend() string
+	Backup(path string) error
+}
+
+// Options controls where backups are kept and how often they are taken
+type Options struct {
+	Dir      string
+	Keep     int           // Number of backups to keep; 0 keeps all
+	Interval time.Duration // Minimum time between scheduled backups
+}
+
+// Backup describes a backup file
+type Backup struct {
+	Path      string
+	CreatedAt time.Time
+	Size      int64
+}
+
+// Manager takes and manages backups of a database
+type Manager struct {
+	db     Database
+	opts   Options
+	logger *logrus.Logger
+}
+
+// NewManager creates a manager for the backups of db
+func NewManager(db Database, opts Options, logger *logrus.Logger) *Manager {
+	return &Manager{
+		db:     db,
+		opts:   opts,
+		logger: logger,
+	}
+}
+
+// Create backs up the database now, verifies the copy and records its checksum
+func (m *Manager) Create() (*Backup, error) {
+	if err := os.MkdirAll(m.opts.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	now := time.Now()
+	name := filePrefix + now.Format("20060102-150405") + storage.BackupExtension(m.db.Backend())
+	path := filepath.Join(m.opts.Dir, name)
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("backup %s already exists", path)
+	}
+
+	// Back up under a temporary name so an interrupted backup is never listed
+	tmp := path + ".tmp"
+	os.Remove(tmp)
+	if err := m.db.Backup(tmp); err != nil {
+		return nil, err
+	}
+	if err := storage.VerifyBackup(m.db.Backend(), tmp); err != nil {
+		os.Remove(tmp)
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("failed to save backup: %w", err)
+	}
+
+	sum, err := checksum(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path+checksumExtension, []byte(sum+"  "+name+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("failed to save backup checksum: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	m.logger.WithField("file", path).Info("Database backed up")
+	return &Backup{Path: path, CreatedAt: now, Size: info.Size()}, nil
+}
+
+// List returns the backups in the backup directory, newest first
+func (m *Manager) List() ([]*Backup, error) {
+	entries, err := os.ReadDir(m.opts.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	var backups []*Backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, filePrefix) {
+			continue
+		}
+		ext := filepath.Ext(name)
+		if ext != ".db" && ext != ".sql" {
+			continue
+		}
+		createdAt, err := time.ParseInLocation("20060102-150405", strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), ext), time.Local)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, &Backup{Path: filepath.Join(m.opts.Dir, name), CreatedAt: createdAt, Size: info.Size()})
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+	return backups, nil
+}
+
+// Latest returns the newest backup, or nil if there is none
+func (m *Manager) Latest() (*Backup, error) {
+	backups, err := m.List()
+	if err != nil || len(backups) == 0 {
+		return nil, err
+	}
+	return backups[0], nil
+}
+
+// Prune removes the oldest backups beyond the number to keep
+func (m *Manager) Prune() (int, error) {
+	if m.opts.Keep <= 0 {
+		return 0, nil
+	}
+
+	backups, err := m.List()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, b := range backups[min(m.opts.Keep, len(backups)):] {
+		if err := os.Remove(b.Path); err != nil {
+			return removed, fmt.Errorf("failed to remove old backup: %w", err)
+		}
+		os.Remove(b.Path + checksumExtension)
+		removed++
+		m.logger.WithField("file", b.Path).Debug("Old backup removed")
+	}
+	return removed, nil
+}
+
+// Verify checks a backup against its recorded checksum and that it is a
+// complete backup of the database's backend
+func (m *Manager) Verify(path string) error {
+	recorded, err := os.ReadFile(path + checksumExtension)
+	if err != nil {
+		return fmt.Errorf("failed to read backup checksum: %w", err)
+	}
+	fields := strings.Fields(string(recorded))
+	if len(fields) == 0 {
+		return fmt.Errorf("backup checksum file %s is empty", path+checksumExtension)
+	}
+
+	sum, err := checksum(path)
+	if err != nil {
+		return err
+	}
+	if sum != fields[0] {
+		return fmt.Errorf("backup %s does not match its checksum; the file has changed since it was taken", path)
+	}
+
+	return storage.VerifyBackup(m.db.Backend(), path)
+}
+
+// RunIfDue takes a backup and prunes old ones when the latest backup is
+// older than the interval
+func (m *Manager) RunIfDue() error {
+	latest, err := m.Latest()
+	if err != nil {
+		return err
+	}
+	if latest != nil && time.Since(latest.CreatedAt) < m.opts.Interval {
+		return nil
+	}
+
+	if _, err := m.Create(); err != nil {
+		return err
+	}
+	_, err = m.Prune()
+	return err
+}
+
+// Schedule takes backups every interval until ctx is done
+func (m *Manager) Schedule(ctx context.Context) {
+	if m.opts.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.RunIfDue(); err != nil {
+				m.logger.WithError(err).Warn("Scheduled database backup failed")
+			}
+		}
+	}
+}
+
+// checksum returns the hex SHA-256 of a file
+func checksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to checksum backup: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
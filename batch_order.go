@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/spf13/cobra"
+)
+
+// addBatchOrderFlags adds the flags that keep a batch from working through
+// its list top to bottom
+func addBatchOrderFlags(cmd *cobra.Command, noun string) {
+	cmd.Flags().Bool("shuffle", false, fmt.Sprintf("Work through the %s in random order", noun))
+	cmd.Flags().Int("skip-percent", 0, fmt.Sprintf("Randomly leave out this percentage of the %s, for a later run with --resume", noun))
+}
+
+// applyBatchOrder shuffles profiles and randomly leaves some out as --shuffle
+// and --skip-percent ask, returning the rest and how many were left out. It
+// runs after the batch ID is derived, since that depends on the order of the
+// list, so --resume later finds the same batch.
+func applyBatchOrder(cmd *cobra.Command, profiles []string) ([]string, int, error) {
+	shuffle, _ := cmd.Flags().GetBool("shuffle")
+	skipPercent, _ := cmd.Flags().GetInt("skip-percent")
+	if skipPercent < 0 || skipPercent >= 100 {
+		return nil, 0, fmt.Errorf("--skip-percent must be between 0 and 99")
+	}
+	if !shuffle && skipPercent == 0 {
+		return profiles, 0, nil
+	}
+
+	order := rand.Perm(len(profiles))
+	skip := len(profiles) * skipPercent / 100
+	skipped := make(map[int]bool, skip)
+	for _, i := range order[:skip] {
+		skipped[i] = true
+	}
+
+	kept := make([]string, 0, len(profiles)-skip)
+	if shuffle {
+		// The rest of the permutation is already in random order
+		for _, i := range order[skip:] {
+			kept = append(kept, profiles[i])
+		}
+		return kept, skip, nil
+	}
+	for i, profileURL := range profiles {
+		if !skipped[i] {
+			kept = append(kept, profileURL)
+		}
+	}
+	return kept, skip, nil
+}
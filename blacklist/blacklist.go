@@ -0,0 +1,203 @@
+package blacklist
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/personalize"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/storage"
+)
+
+// Entry kinds
+const (
+	KindProfile = "profile" // A member profile URL
+	KindDomain  = "domain"  // A company domain, matched against the member's current company
+	KindName    = "name"    // A name pattern, where * matches any run of characters and ? any one
+)
+
+// Kinds lists the supported entry kinds
+var Kinds = []string{KindProfile, KindDomain, KindName}
+
+// Checker decides whether a profile is on the do-not-contact list
+type Checker struct {
+	store    Store
+	profiles ProfileSource
+	logger   *logrus.Logger
+}
+
+// Store provides the blacklist entries
+type Store interface {
+	ListBlacklist() ([]*storage.BlacklistEntry, error)
+}
+
+// ProfileSource resolves the name and company of a profile, scraping the page
+// if it is showing the profile
+type ProfileSource interface {
+	ProfileData(ctx context.Context, page *rod.Page, profileURL string) *personalize.ProfileData
+}
+
+// legalSuffixes are dropped from company names before comparing them with domains
+var legalSuffixes = map[string]bool{
+	"inc": true, "llc": true, "ltd": true, "limited": true, "corp": true, "corporation": true,
+	"co": true, "company": true, "plc": true, "gmbh": true, "ag": true, "sa": true, "bv": true,
+}
+
+// NewChecker creates a checker for the entries in store. profiles may be nil,
+// in which case only profile URL entries are enforced.
+func NewChecker(store Store, profiles ProfileSource, logger *logrus.Logger) *Checker {
+	return &Checker{
+		store:    store,
+		profiles: profiles,
+		logger:   logger,
+	}
+}
+
+// Check returns the entry that forbids contacting profileURL, or nil if none does.
+// Entries are read on every call so changes apply to batches already running.
+func (c *Checker) Check(ctx context.Context, page *rod.Page, profileURL string) (*storage.BlacklistEntry, error) {
+	entries, err := c.store.ListBlacklist()
+	if err != nil {
+		return nil, err
+	}
+
+	canonical := profileurl.Canonicalize(profileURL)
+	needsProfile := false
+	for _, entry := range entries {
+		if entry.Kind == KindProfile && entry.Value == canonical {
+			return entry, nil
+		}
+		if entry.Kind != KindProfile {
+			needsProfile = true
+		}
+	}
+	if !needsProfile || c.profiles == nil {
+		return nil, nil
+	}
+
+	data := c.profiles.ProfileData(ctx, page, profileURL)
+	name := strings.ToLower(strings.Join(strings.Fields(data.Name), " "))
+	company := companyKey(data.Company)
+	for _, entry := range entries {
+		switch entry.Kind {
+		case KindDomain:
+			if company != "" && company == domainKey(entry.Value) {
+				return entry, nil
+			}
+		case KindName:
+			if matched, _ := path.Match(entry.Value, name); name != "" && matched {
+				return entry, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// Blocked returns why profileURL must not be contacted, or an empty string if it may be
+func (c *Checker) Blocked(ctx context.Context, page *rod.Page, profileURL string) (string, error) {
+	entry, err := c.Check(ctx, page, profileURL)
+	if err != nil || entry == nil {
+		return "", err
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"profile_url": profileURL,
+		"kind":        entry.Kind,
+		"value":       entry.Value,
+	}).Info("Profile is on the blacklist")
+
+	reason := fmt.Sprintf("%s %s", entry.Kind, entry.Value)
+	if entry.Reason != "" {
+		reason += " (" + entry.Reason + ")"
+	}
+	return reason, nil
+}
+
+// Normalize validates a value of the given kind and returns the form it is stored in
+func Normalize(kind, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("empty %s", kind)
+	}
+
+	switch kind {
+	case KindProfile:
+		if profileurl.Slug(value) == "" {
+			return "", fmt.Errorf("not a profile URL: %s", value)
+		}
+		return profileurl.Canonicalize(value), nil
+	case KindDomain:
+		domain := normalizeDomain(value)
+		if !strings.Contains(domain, ".") || strings.ContainsAny(domain, " /") {
+			return "", fmt.Errorf("not a domain: %s", value)
+		}
+		return domain, nil
+	case KindName:
+		pattern := strings.ToLower(strings.Join(strings.Fields(value), " "))
+		if _, err := path.Match(pattern, ""); err != nil {
+			return "", fmt.Errorf("invalid name pattern %q: %w", value, err)
+		}
+		return pattern, nil
+	}
+	return "", fmt.Errorf("unknown blacklist kind %q (want %s)", kind, strings.Join(Kinds, ", "))
+}
+
+// DetectKind guesses the kind of a bare value: profile URLs, then domains, then names
+func DetectKind(value string) string {
+	value = strings.TrimSpace(value)
+	if profileurl.Slug(value) != "" {
+		return KindProfile
+	}
+	if strings.Contains(value, ".") && !strings.ContainsAny(value, " \t*?") {
+		return KindDomain
+	}
+	return KindName
+}
+
+// normalizeDomain reduces a URL, email address or domain to a lower-cased host
+// without a leading www.
+func normalizeDomain(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if at := strings.LastIndex(value, "@"); at >= 0 {
+		value = value[at+1:]
+	}
+	if strings.Contains(value, "://") {
+		if parsed, err := url.Parse(value); err == nil {
+			value = parsed.Hostname()
+		}
+	}
+	value = strings.SplitN(value, "/", 2)[0]
+	return strings.TrimPrefix(value, "www.")
+}
+
+// domainKey is the comparable form of a domain's company name: acme-labs.co.uk gives acmelabs
+func domainKey(domain string) string {
+	return alphanumeric(strings.SplitN(domain, ".", 2)[0])
+}
+
+// companyKey is the comparable form of a company name: "Acme Labs, Inc." gives acmelabs
+func companyKey(company string) string {
+	words := strings.FieldsFunc(strings.ToLower(company), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for len(words) > 1 && legalSuffixes[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, "")
+}
+
+func alphanumeric(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
@@ -0,0 +1,65 @@
+package blacklist
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/queue"
+	"linkedin-automation/storage"
+)
+
+// OptOutRecorder handles prospects who reply asking not to be contacted again:
+// it blacklists their profile, cancels their queued connection requests and
+// messages, and marks them as opted out
+type OptOutRecorder struct {
+	store  OptOutStore
+	logger *logrus.Logger
+}
+
+// OptOutStore persists opt-outs and the blacklist entries and tasks they affect
+type OptOutStore interface {
+	queue.CancelStore
+	AddBlacklistEntry(entry *storage.BlacklistEntry) (bool, error)
+	RecordOptOut(profileURL, threadID, phrase, content string) error
+}
+
+// NewOptOutRecorder creates a new opt-out recorder
+func NewOptOutRecorder(store OptOutStore, logger *logrus.Logger) *OptOutRecorder {
+	return &OptOutRecorder{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// OptOut acts on a received message that contains the opt-out phrase
+func (r *OptOutRecorder) OptOut(received *storage.ReceivedMessage, phrase string) error {
+	profileURL, err := Normalize(KindProfile, received.SenderURL)
+	if err != nil {
+		return err
+	}
+
+	if err := r.store.RecordOptOut(profileURL, received.ThreadID, phrase, received.Content); err != nil {
+		return err
+	}
+
+	if _, err := r.store.AddBlacklistEntry(&storage.BlacklistEntry{
+		Kind:   KindProfile,
+		Value:  profileURL,
+		Reason: fmt.Sprintf("opted out: %q", phrase),
+	}); err != nil {
+		return err
+	}
+
+	cancelled, err := queue.CancelForProfile(r.store, profileURL)
+	if err != nil {
+		return fmt.Errorf("failed to cancel queued tasks: %w", err)
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"profile_url":     profileURL,
+		"phrase":          phrase,
+		"tasks_cancelled": cancelled,
+	}).Info("Prospect opted out")
+	return nil
+}
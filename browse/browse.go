@@ -0,0 +1,315 @@
+// Package browse scrolls the feed like a member passively reading it, with
+// reading pauses, occasional reactions and hovers over authors' profiles, to
+// produce organic activity between outreach batches.
+package browse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/pause"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/selectors"
+	"linkedin-automation/storage"
+)
+
+const feedURL = "https://www.linkedin.com/feed/"
+
+// FeedBrowser passively browses the feed
+type FeedBrowser struct {
+	page        *rod.Page
+	logger      *logrus.Logger
+	stealth     StealthManager
+	rateLimiter RateLimiter
+	store       Store
+	dryRun      bool
+	capturer    Capturer
+	rng         *rand.Rand
+}
+
+// StealthManager interface for stealth operations
+type StealthManager interface {
+	HumanLikeMouseMove(page *rod.Page, fromX, fromY, toX, toY float64) error
+	RandomDelay() time.Duration
+	HumanLikeScroll(page *rod.Page, scrollAmount int) error
+	AddIdleMovement(page *rod.Page) error
+}
+
+// RateLimiter gates actions against configured quotas
+type RateLimiter interface {
+	WaitForPermission(ctx context.Context, action ratelimit.ActionType) error
+}
+
+// Store records reactions so they count as engagements
+type Store interface {
+	SaveEngagement(engagement *storage.Engagement) error
+}
+
+// Capturer saves the page for debugging when an action fails
+type Capturer interface {
+	Failure(page *rod.Page, action string, err error)
+}
+
+// Options controls how the feed is browsed
+type Options struct {
+	Duration         time.Duration
+	ReactProbability float64 // Chance of liking a post after reading it; 0 never reacts
+	HoverProbability float64 // Chance of hovering over a post author's profile after reading
+}
+
+// Stats summarizes a browsing session
+type Stats struct {
+	Duration      time.Duration
+	Scrolls       int
+	PostsRead     int
+	Reactions     int
+	ProfileHovers int
+}
+
+// NewFeedBrowser creates a new feed browser
+func NewFeedBrowser(page *rod.Page, logger *logrus.Logger, stealth StealthManager) *FeedBrowser {
+	return &FeedBrowser{
+		page:    page,
+		logger:  logger,
+		stealth: stealth,
+		rng:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// SetRateLimiter makes reactions count against the daily like quota
+func (b *FeedBrowser) SetRateLimiter(limiter RateLimiter) {
+	b.rateLimiter = limiter
+}
+
+// SetStore records reactions in the engagements table
+func (b *FeedBrowser) SetStore(store Store) {
+	b.store = store
+}
+
+// SetDryRun makes reactions find their buttons without clicking them
+func (b *FeedBrowser) SetDryRun(dryRun bool) {
+	b.dryRun = dryRun
+}
+
+// SetCapturer saves the page whenever opening the feed fails
+func (b *FeedBrowser) SetCapturer(capturer Capturer) {
+	b.capturer = capturer
+}
+
+// Browse scrolls and reads the feed until opts.Duration has passed or ctx is cancelled
+func (b *FeedBrowser) Browse(ctx context.Context, opts Options) (stats *Stats, err error) {
+	b.logger.WithField("duration", opts.Duration).Info("Browsing feed")
+	start := time.Now()
+	stats = &Stats{}
+	defer b.withContext(ctx)()
+	defer func() {
+		stats.Duration = time.Since(start).Round(time.Second)
+		if err != nil && b.capturer != nil {
+			b.capturer.Failure(b.page, "browse feed", err)
+		}
+	}()
+
+	if err := b.page.Navigate(feedURL); err != nil {
+		return stats, fmt.Errorf("failed to open feed: %w", err)
+	}
+	if err := b.page.WaitLoad(); err != nil {
+		return stats, fmt.Errorf("failed to wait for feed: %w", err)
+	}
+	if info, err := b.page.Info(); err == nil && (strings.Contains(info.URL, "/login") || strings.Contains(info.URL, "/authwall")) {
+		return stats, fmt.Errorf("redirected to login page - authentication required")
+	}
+
+	read := make(map[string]bool)
+	react := opts.ReactProbability > 0
+	deadline := start.Add(opts.Duration)
+	for time.Now().Before(deadline) {
+		if err := ctx.Err(); err != nil {
+			return stats, nil
+		}
+
+		post, urn := b.nextUnreadPost(read)
+		if post == nil {
+			if err := b.stealth.HumanLikeScroll(b.page, 400+b.rng.Intn(700)); err != nil {
+				b.logger.WithError(err).Debug("Failed to scroll feed")
+			}
+			stats.Scrolls++
+			pause.Sleep(ctx, b.stealth.RandomDelay())
+			continue
+		}
+
+		read[urn] = true
+		b.readPost(ctx, post)
+		stats.PostsRead++
+
+		if react && b.rng.Float64() < opts.ReactProbability {
+			reacted, err := b.react(ctx, post, urn)
+			switch {
+			case errors.Is(err, ratelimit.ErrLimitReached):
+				b.logger.WithError(err).Info("Like limit reached, browsing without reactions")
+				react = false
+			case err != nil:
+				b.logger.WithError(err).Debug("Failed to react to post")
+			case reacted:
+				stats.Reactions++
+			}
+		}
+
+		if b.rng.Float64() < opts.HoverProbability && b.hoverAuthor(ctx, post) {
+			stats.ProfileHovers++
+		}
+
+		if b.rng.Float64() < 0.2 {
+			if err := b.stealth.AddIdleMovement(b.page); err != nil {
+				b.logger.WithError(err).Debug("Failed to add idle movement")
+			}
+		}
+	}
+
+	b.logger.WithFields(logrus.Fields{
+		"posts_read": stats.PostsRead,
+		"reactions":  stats.Reactions,
+		"hovers":     stats.ProfileHovers,
+	}).Info("Feed browsing completed")
+	return stats, nil
+}
+
+// nextUnreadPost returns the first visible post not read yet and its URN, or nil
+func (b *FeedBrowser) nextUnreadPost(read map[string]bool) (*rod.Element, string) {
+	for _, post := range selectors.FindAll(b.page, selectors.Post) {
+		urn, err := post.Attribute("data-urn")
+		if err != nil || urn == nil || read[*urn] {
+			continue
+		}
+		if visible, err := post.Visible(); err != nil || !visible {
+			continue
+		}
+		return post, *urn
+	}
+	return nil, ""
+}
+
+// readPost brings a post into view and rests on it for a time that grows with its length
+func (b *FeedBrowser) readPost(ctx context.Context, post *rod.Element) {
+	if err := post.ScrollIntoView(); err != nil {
+		b.logger.WithError(err).Debug("Failed to scroll post into view")
+	}
+	if err := post.Hover(); err != nil {
+		b.logger.WithError(err).Debug("Failed to hover post")
+	}
+
+	// About 20 characters a second, with a short glance for posts without text
+	chars := len([]rune(selectors.TextIn(post, selectors.PostText)))
+	reading := time.Second + time.Duration(chars)*50*time.Millisecond
+	if reading > 20*time.Second {
+		reading = 20 * time.Second
+	}
+	pause.Sleep(ctx, time.Duration(float64(reading)*(0.6+0.8*b.rng.Float64())))
+}
+
+// react likes a post that is not liked yet, reporting whether it did
+func (b *FeedBrowser) react(ctx context.Context, post *rod.Element, urn string) (bool, error) {
+	button := selectors.FindIn(post, selectors.PostLikeButton)
+	if button == nil {
+		return false, fmt.Errorf("like button not found")
+	}
+	if pressed, err := button.Attribute("aria-pressed"); err == nil && pressed != nil && *pressed == "true" {
+		return false, nil
+	}
+
+	if b.rateLimiter != nil {
+		if err := b.rateLimiter.WaitForPermission(ctx, ratelimit.ActionLike); err != nil {
+			return false, err
+		}
+	}
+
+	if !b.dryRun {
+		if err := b.click(button); err != nil {
+			return false, err
+		}
+	}
+
+	if b.store != nil {
+		if err := b.store.SaveEngagement(&storage.Engagement{
+			TargetURL: b.authorURL(post),
+			PostURN:   urn,
+			Action:    string(ratelimit.ActionLike),
+			Status:    storage.EngagementDone,
+			DryRun:    b.dryRun,
+		}); err != nil {
+			b.logger.WithError(err).Warn("Failed to record reaction")
+		}
+	}
+
+	b.logger.WithField("post", urn).Info("Reacted to post while browsing")
+	pause.Sleep(ctx, b.stealth.RandomDelay())
+	return true, nil
+}
+
+// hoverAuthor rests the mouse on a post author's name until the profile card shows
+func (b *FeedBrowser) hoverAuthor(ctx context.Context, post *rod.Element) bool {
+	link := selectors.FindIn(post, selectors.PostAuthorLink)
+	if link == nil {
+		return false
+	}
+	if err := link.Hover(); err != nil {
+		b.logger.WithError(err).Debug("Failed to hover post author")
+		return false
+	}
+	pause.Sleep(ctx, 2*time.Second+time.Duration(b.rng.Int63n(int64(3*time.Second))))
+	return true
+}
+
+func (b *FeedBrowser) authorURL(post *rod.Element) string {
+	link := selectors.FindIn(post, selectors.PostAuthorLink)
+	if link == nil {
+		return ""
+	}
+	href, err := link.Attribute("href")
+	if err != nil || href == nil {
+		return ""
+	}
+	if profileurl.Slug(*href) != "" {
+		return profileurl.Canonicalize(*href)
+	}
+	return strings.SplitN(*href, "?", 2)[0]
+}
+
+func (b *FeedBrowser) click(button *rod.Element) error {
+	shape, err := button.Shape()
+	if err != nil {
+		return fmt.Errorf("failed to get button position: %w", err)
+	}
+	box := shape.Box()
+
+	viewport, err := b.page.Eval("({width: window.innerWidth, height: window.innerHeight})")
+	if err != nil {
+		return fmt.Errorf("failed to get viewport: %w", err)
+	}
+	fromX := viewport.Value.Get("width").Num() / 2
+	fromY := viewport.Value.Get("height").Num() / 2
+
+	if err := b.stealth.HumanLikeMouseMove(b.page, fromX, fromY, box.X+box.Width/2, box.Y+box.Height/2); err != nil {
+		b.logger.WithError(err).Warn("Failed to perform human-like mouse movement")
+	}
+
+	if err := button.Click("left", 1); err != nil {
+		return fmt.Errorf("failed to click button: %w", err)
+	}
+	return nil
+}
+
+// withContext binds the page to ctx until the returned function is called,
+// so navigation, element lookups and pauses stop once ctx is cancelled
+func (b *FeedBrowser) withContext(ctx context.Context) func() {
+	page := b.page
+	b.page = page.Context(ctx)
+	return func() { b.page = page }
+}
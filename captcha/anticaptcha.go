@@ -0,0 +1,107 @@
+package captcha
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+const antiCaptchaURL = "https://api.anti-captcha.com"
+
+// antiCaptcha solves challenges through the Anti-Captcha createTask API
+type antiCaptcha struct {
+	apiKey  string
+	baseURL string
+	timeout time.Duration
+}
+
+type antiCaptchaError struct {
+	ErrorID          int    `json:"errorId"`
+	ErrorCode        string `json:"errorCode"`
+	ErrorDescription string `json:"errorDescription"`
+}
+
+func (e antiCaptchaError) err() error {
+	if e.ErrorID == 0 {
+		return nil
+	}
+	return fmt.Errorf("anti-captcha error %s: %s", e.ErrorCode, e.ErrorDescription)
+}
+
+func newAntiCaptcha(apiKey string, timeout time.Duration) *antiCaptcha {
+	return &antiCaptcha{
+		apiKey:  apiKey,
+		baseURL: antiCaptchaURL,
+		timeout: timeout,
+	}
+}
+
+// Name returns the provider name
+func (a *antiCaptcha) Name() string {
+	return ProviderAntiCaptcha
+}
+
+// SolveImage submits an ImageToTextTask and waits for its text
+func (a *antiCaptcha) SolveImage(ctx context.Context, image []byte) (string, error) {
+	return a.solve(ctx, map[string]interface{}{
+		"type": "ImageToTextTask",
+		"body": base64.StdEncoding.EncodeToString(image),
+	})
+}
+
+// SolveFunCaptcha submits a proxyless FunCaptcha task and waits for its token
+func (a *antiCaptcha) SolveFunCaptcha(ctx context.Context, challenge FunCaptcha) (string, error) {
+	task := map[string]interface{}{
+		"type":             "FunCaptchaTaskProxyless",
+		"websiteURL":       challenge.PageURL,
+		"websitePublicKey": challenge.PublicKey,
+	}
+	if challenge.Subdomain != "" {
+		task["funcaptchaApiJSSubdomain"] = challenge.Subdomain
+	}
+	return a.solve(ctx, task)
+}
+
+func (a *antiCaptcha) solve(ctx context.Context, task map[string]interface{}) (string, error) {
+	var created struct {
+		antiCaptchaError
+		TaskID int64 `json:"taskId"`
+	}
+	if err := postJSON(ctx, a.baseURL+"/createTask", map[string]interface{}{
+		"clientKey": a.apiKey,
+		"task":      task,
+	}, &created); err != nil {
+		return "", fmt.Errorf("anti-captcha submit failed: %w", err)
+	}
+	if err := created.err(); err != nil {
+		return "", err
+	}
+
+	return poll(ctx, a.timeout, func() (string, bool, error) {
+		var result struct {
+			antiCaptchaError
+			Status   string `json:"status"`
+			Solution struct {
+				Text  string `json:"text"`
+				Token string `json:"token"`
+			} `json:"solution"`
+		}
+		if err := postJSON(ctx, a.baseURL+"/getTaskResult", map[string]interface{}{
+			"clientKey": a.apiKey,
+			"taskId":    created.TaskID,
+		}, &result); err != nil {
+			return "", false, fmt.Errorf("anti-captcha result failed: %w", err)
+		}
+		if err := result.err(); err != nil {
+			return "", false, err
+		}
+		if result.Status != "ready" {
+			return "", false, nil
+		}
+		if result.Solution.Token != "" {
+			return result.Solution.Token, true, nil
+		}
+		return result.Solution.Text, true, nil
+	})
+}
@@ -0,0 +1,132 @@
+// Package captcha solves challenges through paid solver services so logins
+// that hit a CAPTCHA can continue unattended.
+package captcha
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Supported providers
+const (
+	Provider2Captcha    = "2captcha"
+	ProviderAntiCaptcha = "anticaptcha"
+)
+
+// LinkedInFunCaptchaKey is the Arkose Labs public key of LinkedIn's login challenge
+const LinkedInFunCaptchaKey = "3117BF26-4762-4F5A-8ED9-A85E69209A46"
+
+// LinkedInFunCaptchaSubdomain serves LinkedIn's Arkose Labs challenge
+const LinkedInFunCaptchaSubdomain = "https://client-api.arkoselabs.com"
+
+// ErrTimeout means the service did not return a solution in time
+var ErrTimeout = errors.New("captcha was not solved in time")
+
+// FunCaptcha describes an Arkose Labs challenge
+type FunCaptcha struct {
+	PublicKey string
+	PageURL   string
+	Subdomain string // API subdomain the widget is loaded from; optional
+}
+
+// Solver solves CAPTCHA challenges
+type Solver interface {
+	// Name returns the provider name
+	Name() string
+	// SolveImage returns the text shown in a CAPTCHA image
+	SolveImage(ctx context.Context, image []byte) (string, error)
+	// SolveFunCaptcha returns a token that completes a FunCaptcha challenge
+	SolveFunCaptcha(ctx context.Context, challenge FunCaptcha) (string, error)
+}
+
+// NewSolver creates a solver for a provider
+func NewSolver(provider, apiKey string, timeout time.Duration) (Solver, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("captcha api key is required")
+	}
+	if timeout <= 0 {
+		timeout = 3 * time.Minute
+	}
+
+	switch provider {
+	case Provider2Captcha:
+		return newTwoCaptcha(apiKey, timeout), nil
+	case ProviderAntiCaptcha:
+		return newAntiCaptcha(apiKey, timeout), nil
+	default:
+		return nil, fmt.Errorf("unsupported captcha provider %q (use %s or %s)", provider, Provider2Captcha, ProviderAntiCaptcha)
+	}
+}
+
+// pollInterval is how often a pending solution is checked; solver services
+// ask clients not to poll more than every few seconds
+const pollInterval = 5 * time.Second
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// poll calls check until it reports a solution, an error or the timeout passes
+func poll(ctx context.Context, timeout time.Duration, check func() (string, bool, error)) (string, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(pollInterval):
+		}
+
+		solution, ready, err := check()
+		if err != nil {
+			return "", err
+		}
+		if ready {
+			return solution, nil
+		}
+		if time.Now().After(deadline) {
+			return "", ErrTimeout
+		}
+	}
+}
+
+// postJSON sends body as JSON and decodes the response into out
+func postJSON(ctx context.Context, url string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return do(req, out)
+}
+
+// do sends req and decodes the JSON response into out
+func do(req *http.Request, out interface{}) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
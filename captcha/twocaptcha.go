@@ -0,0 +1,103 @@
+package captcha
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const twoCaptchaURL = "https://2captcha.com"
+
+// twoCaptcha solves challenges through the 2Captcha in.php/res.php API
+type twoCaptcha struct {
+	apiKey  string
+	baseURL string
+	timeout time.Duration
+}
+
+// twoCaptchaResponse is returned by both endpoints; request holds the task ID,
+// the solution or an error code depending on status
+type twoCaptchaResponse struct {
+	Status  int    `json:"status"`
+	Request string `json:"request"`
+}
+
+func newTwoCaptcha(apiKey string, timeout time.Duration) *twoCaptcha {
+	return &twoCaptcha{
+		apiKey:  apiKey,
+		baseURL: twoCaptchaURL,
+		timeout: timeout,
+	}
+}
+
+// Name returns the provider name
+func (t *twoCaptcha) Name() string {
+	return Provider2Captcha
+}
+
+// SolveImage submits the image as base64 and waits for its text
+func (t *twoCaptcha) SolveImage(ctx context.Context, image []byte) (string, error) {
+	return t.solve(ctx, url.Values{
+		"method": {"base64"},
+		"body":   {base64.StdEncoding.EncodeToString(image)},
+	})
+}
+
+// SolveFunCaptcha submits an Arkose Labs challenge and waits for its token
+func (t *twoCaptcha) SolveFunCaptcha(ctx context.Context, challenge FunCaptcha) (string, error) {
+	params := url.Values{
+		"method":    {"funcaptcha"},
+		"publickey": {challenge.PublicKey},
+		"pageurl":   {challenge.PageURL},
+	}
+	if challenge.Subdomain != "" {
+		params.Set("surl", challenge.Subdomain)
+	}
+	return t.solve(ctx, params)
+}
+
+func (t *twoCaptcha) solve(ctx context.Context, params url.Values) (string, error) {
+	params.Set("key", t.apiKey)
+	params.Set("json", "1")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+"/in.php", strings.NewReader(params.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var submitted twoCaptchaResponse
+	if err := do(req, &submitted); err != nil {
+		return "", fmt.Errorf("2captcha submit failed: %w", err)
+	}
+	if submitted.Status != 1 {
+		return "", fmt.Errorf("2captcha rejected the task: %s", submitted.Request)
+	}
+
+	resultURL := fmt.Sprintf("%s/res.php?key=%s&action=get&json=1&id=%s",
+		t.baseURL, url.QueryEscape(t.apiKey), url.QueryEscape(submitted.Request))
+
+	return poll(ctx, t.timeout, func() (string, bool, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, resultURL, nil)
+		if err != nil {
+			return "", false, err
+		}
+
+		var result twoCaptchaResponse
+		if err := do(req, &result); err != nil {
+			return "", false, fmt.Errorf("2captcha result failed: %w", err)
+		}
+		switch {
+		case result.Status == 1:
+			return result.Request, true, nil
+		case result.Request == "CAPCHA_NOT_READY":
+			return "", false, nil
+		default:
+			return "", false, fmt.Errorf("2captcha could not solve the task: %s", result.Request)
+		}
+	})
+}
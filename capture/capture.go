@@ -0,0 +1,257 @@
+// Package capture saves screenshots and HTML snapshots of the browser page to
+// a debug directory, when an action fails and optionally at every step, so
+// broken selectors and flows can be inspected afterwards.
+package capture
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/errs"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/storage"
+)
+
+// captureTimeout bounds how long a capture may hold up the run
+const captureTimeout = 15 * time.Second
+
+// Sink stores the audit entries that reference failure captures
+type Sink interface {
+	SaveAuditEntry(entry *storage.AuditEntry) error
+}
+
+// Capture names the files saved for one capture
+type Capture struct {
+	Screenshot string
+	HTML       string
+}
+
+// Capturer saves page captures to a directory. A nil Capturer captures nothing.
+type Capturer struct {
+	dir     string
+	onError bool
+	all     bool
+	sink    Sink
+	logger  *logrus.Logger
+	mu      sync.Mutex
+	seq     int
+}
+
+// NewCapturer creates a capturer writing to dir. With onError, pages are
+// captured when an action fails; with all, before every click and after
+// every navigation as well.
+func NewCapturer(dir string, onError, all bool, logger *logrus.Logger) *Capturer {
+	return &Capturer{
+		dir:     dir,
+		onError: onError,
+		all:     all,
+		logger:  logger,
+	}
+}
+
+// SetAuditSink records failure captures in the audit log
+func (c *Capturer) SetAuditSink(sink Sink) {
+	c.sink = sink
+}
+
+// Failure captures page after action failed with err and logs where the files
+// were saved. Limits, cancellations and review aborts are not captured since
+// the page has nothing to show about them.
+func (c *Capturer) Failure(page *rod.Page, action string, err error) {
+	if c == nil || !c.onError || page == nil || err == nil || !worthCapturing(err) {
+		return
+	}
+
+	page = page.Timeout(captureTimeout)
+	defer page.CancelTimeout()
+
+	pageURL := ""
+	if info, infoErr := page.Info(); infoErr == nil {
+		pageURL = info.URL
+	}
+
+	png, shotErr := page.Screenshot(true, &proto.PageCaptureScreenshot{Format: proto.PageCaptureScreenshotFormatPng})
+	html, htmlErr := page.HTML()
+	if shotErr != nil && htmlErr != nil {
+		c.logger.WithError(shotErr).WithField("action", action).Warn("Failed to capture page after failure")
+		return
+	}
+
+	saved, saveErr := c.save(action+"-failed", png, html)
+	if saveErr != nil {
+		c.logger.WithError(saveErr).WithField("action", action).Warn("Failed to save page capture")
+		return
+	}
+
+	c.logger.WithError(err).WithFields(logrus.Fields{
+		"action":     action,
+		"page_url":   pageURL,
+		"screenshot": saved.Screenshot,
+		"html":       saved.HTML,
+	}).Warn("Captured page after failure")
+
+	if c.sink != nil {
+		entry := &storage.AuditEntry{
+			OccurredAt: time.Now(),
+			Action:     storage.AuditFailure,
+			Target:     action,
+			PageURL:    pageURL,
+			Detail:     saved.Screenshot,
+			Outcome:    storage.AuditError,
+			Error:      err.Error(),
+		}
+		if entry.Detail == "" {
+			entry.Detail = saved.HTML
+		}
+		if profileurl.Slug(pageURL) != "" {
+			entry.ProfileURL = profileurl.Canonicalize(pageURL)
+		}
+		if err := c.sink.SaveAuditEntry(entry); err != nil {
+			c.logger.WithError(err).Warn("Failed to record page capture in audit log")
+		}
+	}
+}
+
+// Wrap returns a client that captures the page before every click and after
+// every navigation when step capture is on, and client itself otherwise
+func (c *Capturer) Wrap(client rod.CDPClient) rod.CDPClient {
+	if c == nil || !c.all {
+		return client
+	}
+	return &stepClient{client: client, capturer: c}
+}
+
+// save writes a capture's files under a name that sorts by time
+func (c *Capturer) save(label string, png []byte, html string) (*Capture, error) {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create capture directory: %w", err)
+	}
+
+	c.mu.Lock()
+	c.seq++
+	base := filepath.Join(c.dir, fmt.Sprintf("%s-%04d-%s", time.Now().Format("20060102-150405"), c.seq, fileLabel(label)))
+	c.mu.Unlock()
+
+	saved := &Capture{}
+	if len(png) > 0 {
+		saved.Screenshot = base + ".png"
+		if err := os.WriteFile(saved.Screenshot, png, 0600); err != nil {
+			return nil, fmt.Errorf("failed to save screenshot: %w", err)
+		}
+	}
+	if html != "" {
+		saved.HTML = base + ".html"
+		if err := os.WriteFile(saved.HTML, []byte(html), 0600); err != nil {
+			return nil, fmt.Errorf("failed to save HTML snapshot: %w", err)
+		}
+	}
+	return saved, nil
+}
+
+// stepClient forwards DevTools calls, capturing the page around browser actions
+type stepClient struct {
+	client   rod.CDPClient
+	capturer *Capturer
+}
+
+func (s *stepClient) Event() <-chan *cdp.Event {
+	return s.client.Event()
+}
+
+func (s *stepClient) Call(ctx context.Context, sessionID, method string, params interface{}) ([]byte, error) {
+	switch method {
+	case "Page.navigate":
+		res, err := s.client.Call(ctx, sessionID, method, params)
+		if err == nil {
+			s.step(ctx, sessionID, "navigate")
+		}
+		return res, err
+	case "Input.dispatchMouseEvent":
+		var mouse proto.InputDispatchMouseEvent
+		if decode(params, &mouse) && mouse.Type == proto.InputDispatchMouseEventTypeMousePressed {
+			s.step(ctx, sessionID, "click")
+		}
+	}
+	return s.client.Call(ctx, sessionID, method, params)
+}
+
+// step captures the page of a session through the raw DevTools connection
+func (s *stepClient) step(ctx context.Context, sessionID, label string) {
+	ctx, cancel := context.WithTimeout(ctx, captureTimeout)
+	defer cancel()
+
+	var png []byte
+	if res, err := s.client.Call(ctx, sessionID, "Page.captureScreenshot", proto.PageCaptureScreenshot{Format: proto.PageCaptureScreenshotFormatPng}); err == nil {
+		var shot proto.PageCaptureScreenshotResult
+		if json.Unmarshal(res, &shot) == nil {
+			png = shot.Data
+		}
+	}
+
+	var html string
+	if res, err := s.client.Call(ctx, sessionID, "Runtime.evaluate", proto.RuntimeEvaluate{
+		Expression:    "document.documentElement.outerHTML",
+		ReturnByValue: true,
+	}); err == nil {
+		var evaluated proto.RuntimeEvaluateResult
+		if json.Unmarshal(res, &evaluated) == nil && evaluated.Result != nil {
+			html = evaluated.Result.Value.Str()
+		}
+	}
+
+	if len(png) == 0 && html == "" {
+		return
+	}
+	saved, err := s.capturer.save(label, png, html)
+	if err != nil {
+		s.capturer.logger.WithError(err).Warn("Failed to save step capture")
+		return
+	}
+	s.capturer.logger.WithFields(logrus.Fields{"step": label, "screenshot": saved.Screenshot}).Debug("Captured step")
+}
+
+// decode copies DevTools call parameters into a typed struct
+func decode(params interface{}, v interface{}) bool {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// worthCapturing reports whether the page may show why err happened
+func worthCapturing(err error) bool {
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	case errors.Is(err, ratelimit.ErrLimitReached), errors.Is(err, errs.ErrAborted):
+		return false
+	}
+	return true
+}
+
+// fileLabel turns an action name into a file name part, e.g. "send message" into "send-message"
+func fileLabel(label string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, label)
+}
@@ -0,0 +1,131 @@
+// Package client lets other Go programs run the automation without executing
+// the CLI. A Client wires the browser session, stealth, quotas and storage the
+// same way the commands do, and runs searches, connection requests and
+// messages end to end:
+//
+//	c, err := client.Open(cfg, client.Options{Headless: true, Logger: log})
+//	if err != nil {
+//		return err
+//	}
+//	defer c.Close()
+//
+//	found, err := c.Search(ctx, search.SearchQuery{Keywords: "golang", MaxResults: 20}, client.SearchOptions{ExcludeContacted: true})
+//	if err != nil {
+//		return err
+//	}
+//	batch, err := c.Connect(ctx, found.Profiles, client.ConnectOptions{Template: "professional"})
+package client
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/config"
+	"linkedin-automation/logger"
+	"linkedin-automation/scoring"
+	"linkedin-automation/storage"
+)
+
+// Options control how a client runs
+type Options struct {
+	Headless   bool           // Run the browser without a window
+	DryRun     bool           // Go through actions without sending, liking or commenting anything
+	CaptureAll bool           // Save a screenshot and HTML snapshot of every navigation and click
+	Command    string         // Path of the command running, e.g. "connect to-profiles", whose navigation mode applies
+	Navigation string         // Navigation mode overriding the configured one, if set
+	Logger     *logrus.Logger // Defaults to the logger package's global logger
+}
+
+// Client runs automation for the account in its configuration
+type Client struct {
+	cfg    *config.Config
+	db     *storage.Database
+	opts   Options
+	ownsDB bool // The database was opened by Open and is closed by Close
+}
+
+// New creates a client storing its history in db, which stays open after
+// the client is done with it
+func New(cfg *config.Config, db *storage.Database, opts Options) *Client {
+	return &Client{cfg: cfg, db: db, opts: opts}
+}
+
+// Open creates a client with a connection to the configured database, which
+// Close closes
+func Open(cfg *config.Config, opts Options) (*Client, error) {
+	log := opts.Logger
+	if log == nil {
+		log = logger.GetLogger()
+	}
+	db, err := ConnectStorage(cfg, log)
+	if err != nil {
+		return nil, err
+	}
+	c := New(cfg, db, opts)
+	c.ownsDB = true
+	return c, nil
+}
+
+// Close closes the database if the client opened it
+func (c *Client) Close() error {
+	if !c.ownsDB {
+		return nil
+	}
+	return c.db.Close()
+}
+
+// Config returns the client's configuration
+func (c *Client) Config() *config.Config {
+	return c.cfg
+}
+
+// DB returns the database the client stores its history in
+func (c *Client) DB() *storage.Database {
+	return c.db
+}
+
+func (c *Client) logger() *logrus.Logger {
+	if c.opts.Logger != nil {
+		return c.opts.Logger
+	}
+	return logger.GetLogger()
+}
+
+// ConnectStorage connects to the configured database: the SQLite file at
+// storage.path, or the shared Postgres or MySQL database at storage.dsn.
+// Profiles saved through it are scored by the configured scoring rules.
+func ConnectStorage(cfg *config.Config, log *logrus.Logger) (*storage.Database, error) {
+	model, err := scoring.New(cfg.Scoring.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load scoring rules: %w", err)
+	}
+
+	var db *storage.Database
+	if cfg.Storage.Type == "" || cfg.Storage.Type == storage.BackendSQLite {
+		db, err = storage.NewDatabase(cfg.Storage.Path, log)
+	} else {
+		db, err = storage.Open(cfg.Storage.Type, cfg.Storage.DSN, log)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !model.Empty() {
+		db.SetScorer(model.Score)
+	}
+	db.SetRunID(logger.RunID())
+	return db, nil
+}
+
+// DeriveBatchID builds a stable batch identifier from the action and its targets,
+// so re-running the same list with resume set picks up where it left off
+func DeriveBatchID(action string, items []string) string {
+	hash := sha1.New()
+	hash.Write([]byte(action))
+	for _, item := range items {
+		hash.Write([]byte("\n" + item))
+	}
+	return action + "-" + hex.EncodeToString(hash.Sum(nil))[:12]
+}
@@ -0,0 +1,189 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"linkedin-automation/browse"
+	"linkedin-automation/connect"
+	"linkedin-automation/personalize"
+	"linkedin-automation/storage"
+	"linkedin-automation/templates"
+)
+
+// ConnectOptions control a batch of connection requests
+type ConnectOptions struct {
+	Message  string            // Note sent with each request; when empty, Template is loaded
+	Template string            // Connection template sent when Message is empty, and recorded with the requests
+	Variants []connect.Variant // A/B variants assigned at random instead of a single note
+	NoNote   bool              // Send the requests without a note, ignoring Message, Template and Variants
+	Campaign string            // Groups the requests for analytics; defaults to the batch ID when using variants
+	BatchID  string            // Defaults to one derived from the profiles, so a resumed batch picks up where it left off
+	Resume   bool              // Skip profiles already completed under the same batch ID
+	Tabs     int               // Browser tabs working the batch at once; 0 means 1
+	Reviewer connect.Reviewer  // Approves each note before it is sent; may be nil
+	Progress connect.Progress  // Told about each profile as the batch works through it; may be nil
+
+	Variables map[string]map[string]string // Template variables per profile URL, filled in over the profile's own
+
+	BrowseEvery int            // Browse the feed after about every this many requests; 0 never does
+	Browse      browse.Options // How each of those browses goes
+}
+
+// Connect sends connection requests to profiles in a browser session of
+// its own and records the requests that went out, even when the batch ends
+// in an error
+func (c *Client) Connect(ctx context.Context, profiles []string, opts ConnectOptions) (*connect.BatchResult, error) {
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("no profiles provided")
+	}
+
+	content, templateName := opts.Message, opts.Template
+	if opts.NoNote {
+		content, templateName, opts.Variants = "", "", nil
+	} else if content == "" && len(opts.Variants) == 0 {
+		t, err := templates.NewManager(c.db, c.logger()).Get(templates.KindConnection, opts.Template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load template: %w", err)
+		}
+		content = t.Content
+	}
+	if err := personalize.Validate(content); err != nil {
+		return nil, err
+	}
+	notes := []string{content}
+	for _, variant := range opts.Variants {
+		notes = append(notes, variant.Content)
+	}
+	if err := c.CheckNotes(notes...); err != nil {
+		return nil, err
+	}
+
+	batchID := opts.BatchID
+	if batchID == "" {
+		batchID = DeriveBatchID("connect", profiles)
+	}
+	campaign := opts.Campaign
+	if campaign == "" && len(opts.Variants) > 0 {
+		campaign = batchID
+	}
+
+	browser, err := c.OpenSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer browser.Close()
+
+	browser.WarmUp(ctx)
+
+	sessions, err := browser.OpenTabs(ctx, opts.Tabs)
+	if err != nil {
+		return nil, err
+	}
+	defer CloseTabs(sessions[1:])
+
+	managers := make([]*connect.ConnectManager, len(sessions))
+	for i, tab := range sessions {
+		managers[i] = c.ConnectManager(tab)
+		if len(opts.Variables) > 0 {
+			managers[i].SetPersonalizer(c.personalizerWith(tab, opts.Variables))
+		}
+		if opts.Reviewer != nil {
+			managers[i].SetReviewer(opts.Reviewer)
+		}
+		if opts.BrowseEvery > 0 {
+			feedBrowser := c.FeedBrowser(tab)
+			managers[i].SetInterleave(opts.BrowseEvery, func(ctx context.Context) error {
+				_, err := feedBrowser.Browse(ctx, opts.Browse)
+				return err
+			})
+		}
+	}
+
+	batch, err := connect.ParallelBatchSendConnectionRequests(ctx, managers, profiles, content, connect.BatchOptions{
+		BatchID:  batchID,
+		Resume:   opts.Resume,
+		Variants: opts.Variants,
+		Progress: opts.Progress,
+	})
+	// Store whatever was sent, even when the batch ended in an error
+	if batch != nil {
+		if err := RecordConnections(c.db, campaign, templateName, batch.Results); err != nil {
+			c.logger().WithError(err).Warn("Failed to store connection requests")
+		}
+	}
+	if err != nil {
+		return batch, fmt.Errorf("batch connection failed: %w", err)
+	}
+	return batch, nil
+}
+
+// CheckNotes returns an error if a connection note is too long for the
+// account before its variables are filled. Notes only personalization takes
+// past the limit are shortened as they are sent.
+func (c *Client) CheckNotes(notes ...string) error {
+	limit := templates.NoteLimit(c.cfg.LinkedIn.Premium)
+	for _, note := range notes {
+		if err := templates.CheckNote(note, limit); err != nil {
+			if !c.cfg.LinkedIn.Premium && templates.StaticLength(note) <= templates.ConnectionNoteLimit {
+				return fmt.Errorf("%w; set linkedin.premium if the account has Premium", err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Variants resolves connection templates into A/B variants named after them
+func (c *Client) Variants(names []string) ([]connect.Variant, error) {
+	if len(names) < 2 {
+		return nil, fmt.Errorf("variants need at least two templates")
+	}
+
+	manager := templates.NewManager(c.db, c.logger())
+	variants := make([]connect.Variant, 0, len(names))
+	for _, name := range names {
+		t, err := manager.Get(templates.KindConnection, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load variant %q: %w", name, err)
+		}
+		if err := personalize.Validate(t.Content); err != nil {
+			return nil, fmt.Errorf("invalid variant %q: %w", name, err)
+		}
+		variants = append(variants, connect.Variant{Name: name, Content: t.Content})
+	}
+	return variants, nil
+}
+
+// RecordConnections stores the requests that were actually sent, and the
+// profiles followed or messaged instead, so later runs can recognise the
+// profiles as already contacted
+func RecordConnections(db *storage.Database, campaign, template string, results []*connect.ConnectionResult) error {
+	for _, result := range results {
+		if result.Skipped || !(result.RequestSent || result.Followed || result.Messaged || result.DryRun) {
+			continue
+		}
+		status := "pending"
+		switch result.Action {
+		case connect.ActionFollow:
+			status = "followed"
+		case connect.ActionMessage:
+			status = "messaged"
+		}
+		if err := db.SaveConnectionRequest(&storage.ConnectionRequest{
+			ProfileURL: result.ProfileURL,
+			Message:    result.Message,
+			Status:     status,
+			SentAt:     time.Now(),
+			Campaign:   campaign,
+			Variant:    result.Variant,
+			Template:   template,
+			DryRun:     result.DryRun,
+			Action:     result.Action,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
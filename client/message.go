@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"linkedin-automation/message"
+	"linkedin-automation/personalize"
+	"linkedin-automation/storage"
+	"linkedin-automation/templates"
+)
+
+// MessageOptions control a batch of messages
+type MessageOptions struct {
+	Message  string           // Content sent to each recipient; when empty, Template is loaded
+	Template string           // Message template sent when Message is empty, and recorded with the messages
+	BatchID  string           // Defaults to one derived from the recipients, so a resumed batch picks up where it left off
+	Resume   bool             // Skip recipients already completed under the same batch ID
+	Tabs     int              // Browser tabs working the batch at once; 0 means 1
+	Reviewer message.Reviewer // Approves each message before it is sent; may be nil
+	Progress message.Progress // Told about each recipient as the batch works through it; may be nil
+
+	Variables map[string]map[string]string // Template variables per recipient URL, filled in over the recipient's own
+}
+
+// Message sends a message to each recipient in a browser session of its own
+// and records the messages that went out, even when the batch ends in an error
+func (c *Client) Message(ctx context.Context, recipients []string, opts MessageOptions) (*message.BatchResult, error) {
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("no recipients provided")
+	}
+
+	content, templateName := opts.Message, opts.Template
+	if content == "" {
+		t, err := templates.NewManager(c.db, c.logger()).Get(templates.KindMessage, opts.Template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load template: %w", err)
+		}
+		content = t.Content
+	}
+	if err := personalize.Validate(content); err != nil {
+		return nil, err
+	}
+
+	batchID := opts.BatchID
+	if batchID == "" {
+		batchID = DeriveBatchID("message", recipients)
+	}
+
+	browser, err := c.OpenSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer browser.Close()
+
+	browser.WarmUp(ctx)
+
+	sessions, err := browser.OpenTabs(ctx, opts.Tabs)
+	if err != nil {
+		return nil, err
+	}
+	defer CloseTabs(sessions[1:])
+
+	managers := make([]*message.MessageManager, len(sessions))
+	for i, tab := range sessions {
+		managers[i] = c.MessageManager(tab)
+		if len(opts.Variables) > 0 {
+			managers[i].SetPersonalizer(c.personalizerWith(tab, opts.Variables))
+		}
+		if opts.Reviewer != nil {
+			managers[i].SetReviewer(opts.Reviewer)
+		}
+	}
+
+	batch, err := message.ParallelBatchSendMessages(ctx, managers, recipients, content, message.BatchOptions{
+		BatchID:  batchID,
+		Resume:   opts.Resume,
+		Progress: opts.Progress,
+	})
+	// Store whatever was sent, even when the batch ended in an error
+	if batch != nil {
+		if err := RecordMessages(c.db, "direct", templateName, batch.Results); err != nil {
+			c.logger().WithError(err).Warn("Failed to store messages")
+		}
+	}
+	if err != nil {
+		return batch, fmt.Errorf("batch messaging failed: %w", err)
+	}
+	return batch, nil
+}
+
+// RecordMessages stores the messages that were sent successfully under messageType
+func RecordMessages(db *storage.Database, messageType, template string, results []*message.MessageResult) error {
+	for _, result := range results {
+		if result.Skipped || !result.Success {
+			continue
+		}
+		if err := db.SaveMessage(&storage.Message{
+			RecipientURL: result.RecipientURL,
+			Content:      result.Content,
+			Type:         messageType,
+			Status:       "sent",
+			SentAt:       result.SentAt,
+			Template:     template,
+			DryRun:       result.DryRun,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"linkedin-automation/search"
+	"linkedin-automation/storage"
+)
+
+// SearchOptions control how search results are recorded
+type SearchOptions struct {
+	ExcludeContacted bool // Drop profiles already sent a request or message instead of flagging them
+	NoCache          bool // Search LinkedIn even if the same search was stored within storage.search_cache_ttl
+}
+
+// SearchResult is a finished search with its profiles stored
+type SearchResult struct {
+	*search.SearchSession
+	Contacted int        // Profiles already contacted, flagged or dropped as the options say
+	CachedAt  *time.Time // When LinkedIn was searched, for results taken from the search cache
+}
+
+// Search runs a people search and stores the profiles it found, so later
+// connection requests and messages can personalize from them. The search
+// runs over the API when credentials are configured, and in the browser
+// otherwise or if the API fails. The same search within
+// storage.search_cache_ttl returns the stored results instead, unless
+// opts.NoCache is set.
+func (c *Client) Search(ctx context.Context, query search.SearchQuery, opts SearchOptions) (*SearchResult, error) {
+	ttl := c.cfg.Storage.SearchCacheTTL
+	key := searchCacheKey(c.cfg.Account, query)
+
+	var session *search.SearchSession
+	var cachedAt *time.Time
+	if ttl > 0 && !opts.NoCache {
+		session, cachedAt = c.cachedSearch(key, ttl)
+	}
+	if session == nil {
+		var err error
+		if session, err = c.peopleSearch(ctx, query); err != nil {
+			return nil, err
+		}
+		if ttl > 0 {
+			c.cacheSearch(key, session, ttl)
+		}
+	}
+
+	contacted, err := c.RecordSearch(session, opts.ExcludeContacted)
+	if err != nil {
+		return nil, err
+	}
+	return &SearchResult{SearchSession: session, Contacted: contacted, CachedAt: cachedAt}, nil
+}
+
+// searchCacheKey identifies a search of account in the search cache
+func searchCacheKey(account string, query search.SearchQuery) string {
+	encoded, _ := json.Marshal(query)
+	sum := sha256.Sum256(append([]byte(account+"\n"), encoded...))
+	return hex.EncodeToString(sum[:])
+}
+
+// cachedSearch returns the search stored under key within ttl and when it
+// ran, or nil if there is none
+func (c *Client) cachedSearch(key string, ttl time.Duration) (*search.SearchSession, *time.Time) {
+	cached, err := c.db.GetCachedSearch(key, time.Now().Add(-ttl))
+	if err != nil {
+		c.logger().WithError(err).Warn("Failed to read search cache")
+		return nil, nil
+	}
+	if cached == nil {
+		return nil, nil
+	}
+
+	var session search.SearchSession
+	if err := json.Unmarshal([]byte(cached.Session), &session); err != nil {
+		c.logger().WithError(err).Warn("Failed to decode cached search, searching again")
+		return nil, nil
+	}
+	c.logger().WithField("searched_at", cached.CreatedAt).Info("Using cached search results")
+	return &session, &cached.CreatedAt
+}
+
+// cacheSearch stores session under key for identical searches within ttl.
+// A search cut short by the rate limit is not stored, so the same search
+// later gets the full results.
+func (c *Client) cacheSearch(key string, session *search.SearchSession, ttl time.Duration) {
+	if session.StoppedAtLimit {
+		return
+	}
+	encoded, err := json.Marshal(session)
+	if err != nil {
+		c.logger().WithError(err).Warn("Failed to encode search for the cache")
+		return
+	}
+	now := time.Now()
+	if err := c.db.SaveCachedSearch(&storage.CachedSearch{
+		Key:       key,
+		Session:   string(encoded),
+		CreatedAt: now,
+	}, now.Add(-ttl)); err != nil {
+		c.logger().WithError(err).Warn("Failed to cache search results")
+	}
+}
+
+func (c *Client) peopleSearch(ctx context.Context, query search.SearchQuery) (*search.SearchSession, error) {
+	// With API credentials configured the search can run without a browser
+	var session *search.SearchSession
+	var err error
+	triedAPI := c.APIClient(nil) != nil && !query.NeedsBrowser()
+	if triedAPI {
+		session, err = c.SearchManager(nil).SearchUsers(ctx, query)
+		if err != nil {
+			c.logger().WithError(err).Warn("API search failed, falling back to browser")
+			session = nil
+		}
+	}
+
+	if session == nil {
+		browser, err := c.OpenSession(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer browser.Close()
+
+		searchManager := c.SearchManager(browser)
+		if triedAPI {
+			// The configured credentials just failed; don't try them again
+			searchManager.SetAPIClient(nil)
+		}
+
+		session, err = searchManager.SearchUsers(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+	}
+
+	return session, nil
+}
+
+// RecordSearch flags (or drops) profiles we've already reached out to and
+// stores the rest, returning how many were already contacted
+func (c *Client) RecordSearch(session *search.SearchSession, excludeContacted bool) (int, error) {
+	contacted, err := c.db.GetContactedProfiles()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load contacted profiles: %w", err)
+	}
+	contactedCount := session.MarkContacted(contacted, excludeContacted)
+
+	if err := saveSearchProfiles(c.db, session); err != nil {
+		c.logger().WithError(err).Warn("Failed to store search results")
+	}
+
+	return contactedCount, nil
+}
+
+func saveSearchProfiles(db *storage.Database, session *search.SearchSession) error {
+	for _, result := range session.Results {
+		if result.ProfileURL == "" {
+			continue
+		}
+		profile := &storage.Profile{
+			URL:         result.ProfileURL,
+			Name:        result.Name,
+			Title:       result.Title,
+			Headline:    result.Title,
+			Company:     result.Company,
+			Location:    result.Location,
+			SearchQuery: result.SearchQuery,
+			Source:      result.Source,
+
+			Degree:            result.Degree,
+			MutualConnections: result.MutualConnections,
+			OpenToWork:        result.OpenToWork,
+			Hiring:            result.Hiring,
+			Premium:           result.Premium,
+		}
+		if err := db.SaveProfile(profile); err != nil {
+			return err
+		}
+		if err := db.SaveProfileInsights(profile); err != nil {
+			return err
+		}
+	}
+
+	query, err := json.Marshal(session.Query)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search query: %w", err)
+	}
+
+	if err := db.SaveSearchSession(&storage.SearchSession{
+		Query:        string(query),
+		ResultsCount: len(session.Results),
+		CreatedAt:    session.SearchTime,
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
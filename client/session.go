@@ -0,0 +1,846 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/audit"
+	"linkedin-automation/auth"
+	"linkedin-automation/blacklist"
+	"linkedin-automation/browse"
+	"linkedin-automation/captcha"
+	"linkedin-automation/capture"
+	"linkedin-automation/config"
+	"linkedin-automation/connect"
+	"linkedin-automation/endorse"
+	"linkedin-automation/engage"
+	"linkedin-automation/errs"
+	"linkedin-automation/geo"
+	"linkedin-automation/imap"
+	"linkedin-automation/invitations"
+	"linkedin-automation/message"
+	"linkedin-automation/navigate"
+	"linkedin-automation/nurture"
+	"linkedin-automation/opener"
+	"linkedin-automation/pause"
+	"linkedin-automation/personalize"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/recording"
+	"linkedin-automation/replies"
+	"linkedin-automation/resolve"
+	"linkedin-automation/restriction"
+	"linkedin-automation/scrape"
+	"linkedin-automation/search"
+	"linkedin-automation/selectors"
+	"linkedin-automation/sequence"
+	"linkedin-automation/stealth"
+	"linkedin-automation/storage"
+	"linkedin-automation/templates"
+	"linkedin-automation/uitext"
+	"linkedin-automation/visit"
+	"linkedin-automation/voyager"
+	"linkedin-automation/warmup"
+)
+
+// Session is an authenticated, stealth-patched page shared by the managers
+// working in it
+type Session struct {
+	client     *Client
+	auth       *auth.AuthManager
+	page       *rod.Page
+	stealth    *stealth.StealthManager
+	limiter    ratelimit.Limiter   // Shared by every manager so session caps cover all actions
+	audit      *audit.Recorder     // Nil when the audit log is disabled
+	capture    *capture.Capturer   // Nil when page captures are disabled
+	screencast *recording.Recorder // Nil unless recording is enabled
+	navigator  *navigate.Navigator // Nil when profiles are opened by URL
+	monitor    *auth.Monitor       // Set with the limiter; nil for extra tabs
+	guard      *restriction.Guard  // Set with the limiter; nil for extra tabs
+}
+
+// OpenSession launches the browser, logs in and applies stealth to the
+// page. Browser actions are recorded in the audit log when it is enabled, and
+// pages are captured for debugging as configured.
+func (c *Client) OpenSession(ctx context.Context) (*Session, error) {
+	if err := c.LoadSelectors(); err != nil {
+		return nil, err
+	}
+	pause.SetWaits(c.cfg.PageWaits())
+	if err := uitext.Configure(c.cfg.LinkedIn.UILanguage, c.cfg.LinkedIn.UIText); err != nil {
+		return nil, err
+	}
+	if c.db != nil {
+		// A restricted account is left alone until its cool-down has passed
+		if err := restriction.CheckCooldown(c.db, c.cfg.Account, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	authManager := c.AuthManager()
+	sink := c.auditSink()
+	var recorder *audit.Recorder
+	if sink != nil {
+		recorder = audit.NewRecorder(sink, c.logger())
+		authManager.AddClientWrapper(recorder)
+	}
+	capturer := c.capturer(sink)
+	if capturer != nil {
+		authManager.AddClientWrapper(capturer)
+	}
+
+	if err := authManager.InitializeBrowser(c.opts.Headless, c.cfg.Browser.UserAgent); err != nil {
+		return nil, fmt.Errorf("failed to initialize browser: %w", err)
+	}
+
+	loginResult, err := authManager.Login(ctx)
+	if err != nil {
+		authManager.Close()
+		return nil, fmt.Errorf("%w: %w", errs.ErrLoginFailed, err)
+	}
+	if !loginResult.Success {
+		authManager.Close()
+		return nil, fmt.Errorf("%w: %s", errs.ErrLoginFailed, loginResult.ErrorMessage)
+	}
+
+	page, err := authManager.GetAuthenticatedPage(ctx)
+	if err != nil {
+		authManager.Close()
+		return nil, fmt.Errorf("failed to get authenticated page: %w", err)
+	}
+
+	stealthManager := c.StealthManager(ctx)
+	if err := stealthManager.ApplyStealth(page); err != nil {
+		c.logger().WithError(err).Warn("Failed to apply some stealth features")
+	}
+
+	return &Session{
+		client:     c,
+		auth:       authManager,
+		page:       page,
+		stealth:    stealthManager,
+		audit:      recorder,
+		capture:    capturer,
+		screencast: c.startRecording(page),
+		navigator:  c.navigator(stealthManager),
+	}, nil
+}
+
+// NavigationMode returns how the running command opens profiles
+func (c *Client) NavigationMode() string {
+	if c.opts.Navigation != "" {
+		return c.opts.Navigation
+	}
+	return c.cfg.Stealth.Navigation.ModeFor(c.opts.Command)
+}
+
+// navigator returns a navigator clicking through to profiles, or nil when
+// the command opens them by URL
+func (c *Client) navigator(stealthManager *stealth.StealthManager) *navigate.Navigator {
+	if c.NavigationMode() != navigate.ModeClick {
+		return nil
+	}
+	var profiles navigate.Profiles
+	if c.db != nil {
+		profiles = c.db
+	}
+	return navigate.NewNavigator(stealthManager, profiles, c.logger())
+}
+
+// startRecording starts the screencast of page when recording is enabled.
+// A recording that cannot start does not stop the run.
+func (c *Client) startRecording(page *rod.Page) *recording.Recorder {
+	if !c.cfg.Recording.Enabled {
+		return nil
+	}
+	recorder, err := recording.Start(page, recording.Options{
+		Dir:           c.cfg.Recording.Dir,
+		Format:        c.cfg.Recording.Format,
+		Quality:       c.cfg.Recording.Quality,
+		MaxWidth:      c.cfg.Recording.MaxWidth,
+		MaxHeight:     c.cfg.Recording.MaxHeight,
+		EveryNthFrame: c.cfg.Recording.EveryNthFrame,
+	}, c.logger())
+	if err != nil {
+		c.logger().WithError(err).Warn("Session will not be recorded")
+		return nil
+	}
+	return recorder
+}
+
+// auditSink returns the configured audit file or the database, or nil when
+// the audit log is disabled
+func (c *Client) auditSink() audit.Sink {
+	if !c.cfg.Audit.Enabled {
+		return nil
+	}
+	if c.cfg.Audit.File != "" {
+		return audit.NewFileLog(c.cfg.Audit.File)
+	}
+	return c.db
+}
+
+// capturer returns the capturer for failed actions and, with CaptureAll,
+// every step, or nil when neither is enabled
+func (c *Client) capturer(sink audit.Sink) *capture.Capturer {
+	all := c.opts.CaptureAll || c.cfg.Capture.All
+	if !c.cfg.Capture.OnError && !all {
+		return nil
+	}
+	capturer := capture.NewCapturer(c.cfg.Capture.Dir, c.cfg.Capture.OnError, all, c.logger())
+	if sink != nil {
+		capturer.SetAuditSink(sink)
+	}
+	return capturer
+}
+
+// AuthManager creates an auth manager for the configured account and proxy
+func (c *Client) AuthManager() *auth.AuthManager {
+	cfg := c.cfg
+	authManager := auth.NewAuthManager(cfg.LinkedIn.Email, cfg.LinkedIn.Password, cfg.Browser.SessionDir, c.logger())
+	authManager.SetProxy(cfg.Browser.Proxy)
+	authManager.SetExecutablePath(cfg.Browser.ExecutablePath)
+	authManager.SetRemoteURL(cfg.Browser.RemoteDebuggingURL)
+	authManager.SetContainerMode(cfg.Browser.Container)
+	if cfg.Account != "" {
+		// Accounts of a campaign run side by side, each in a browser of its own
+		authManager.SetSeparateInstance()
+	}
+
+	if cfg.Captcha.Provider != "" {
+		solver, err := captcha.NewSolver(cfg.Captcha.Provider, cfg.Captcha.APIKey, cfg.Captcha.Timeout)
+		if err != nil {
+			c.logger().WithError(err).Warn("CAPTCHA solver disabled")
+		} else {
+			authManager.SetCaptchaSolver(solver)
+		}
+	}
+
+	if cfg.IMAP.Host != "" {
+		authManager.SetVerificationCodeSource(imap.NewCodeReader(imap.Config{
+			Host:     cfg.IMAP.Host,
+			Port:     cfg.IMAP.Port,
+			Username: cfg.IMAP.Username,
+			Password: cfg.IMAP.Password,
+			Mailbox:  cfg.IMAP.Mailbox,
+			Timeout:  cfg.IMAP.Timeout,
+		}, c.logger()))
+	}
+	return authManager
+}
+
+// StealthManager creates a stealth manager presenting the account's stored
+// fingerprint and the configured region's timezone and language
+func (c *Client) StealthManager(ctx context.Context) *stealth.StealthManager {
+	stealthConfig := convertConfigToStealth(c.cfg.Stealth)
+	stealthConfig.Locale = c.resolveLocale(ctx)
+
+	stealthManager := stealth.NewStealthManager(stealthConfig, c.logger())
+	if c.cfg.Stealth.Fingerprint.File != "" {
+		fingerprint, err := stealth.LoadFingerprint(c.cfg.Stealth.Fingerprint.File, c.cfg.LinkedIn.Email)
+		if err != nil {
+			c.logger().WithError(err).Warn("Failed to load fingerprint, using a random one")
+		} else {
+			stealthManager.SetFingerprint(fingerprint)
+		}
+	}
+	return stealthManager
+}
+
+// resolveLocale derives the browser's timezone and language from the configured
+// region, letting explicit timezone and locale settings take precedence
+func (c *Client) resolveLocale(ctx context.Context) stealth.LocaleConfig {
+	var locale stealth.LocaleConfig
+	region := c.cfg.Stealth.Locale.Region
+
+	if strings.EqualFold(region, "auto") {
+		detected, err := stealth.DetectLocale(ctx, c.cfg.Browser.Proxy)
+		if err != nil {
+			c.logger().WithError(err).Warn("Failed to detect region, keeping browser defaults")
+		} else {
+			locale = detected
+		}
+	} else if region != "" {
+		if regional, ok := stealth.RegionLocale(region); ok {
+			locale = regional
+		} else {
+			c.logger().WithField("region", region).Warn("Unknown region, set stealth.locale.timezone and locale instead")
+		}
+	}
+
+	if c.cfg.Stealth.Locale.Timezone != "" {
+		locale.Timezone = c.cfg.Stealth.Locale.Timezone
+	}
+	if c.cfg.Stealth.Locale.Locale != "" {
+		locale.Locale = c.cfg.Stealth.Locale.Locale
+	}
+
+	if locale.Timezone != "" || locale.Locale != "" {
+		c.logger().WithFields(logrus.Fields{
+			"timezone": locale.Timezone,
+			"locale":   locale.Locale,
+		}).Info("Emulating region")
+	}
+	return locale
+}
+
+// LoadSelectors applies selector overrides from the configured file, if present
+func (c *Client) LoadSelectors() error {
+	if c.cfg.Browser.SelectorsFile == "" {
+		return nil
+	}
+
+	count, err := selectors.LoadFile(c.cfg.Browser.SelectorsFile)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		c.logger().WithField("file", c.cfg.Browser.SelectorsFile).WithField("overrides", count).Info("Loaded selector overrides")
+	}
+	return nil
+}
+
+// Page returns the session's page
+func (s *Session) Page() *rod.Page {
+	return s.page
+}
+
+// Stealth returns the stealth manager of the session's page
+func (s *Session) Stealth() *stealth.StealthManager {
+	return s.stealth
+}
+
+// WarmUp browses the feed before a batch, if the warm-up roll says so, and
+// stops early once ctx is cancelled
+func (s *Session) WarmUp(ctx context.Context) {
+	if s.client.opts.DryRun || !s.stealth.ShouldWarmUp() {
+		return
+	}
+	if err := s.stealth.WarmUpSession(s.page.Context(ctx)); err != nil && ctx.Err() == nil {
+		s.client.logger().WithError(err).Warn("Session warm-up failed")
+	}
+}
+
+// RateLimiter returns the quota limiter shared by the managers working in
+// the session
+func (s *Session) RateLimiter() ratelimit.Limiter {
+	return s.client.rateLimiter(s)
+}
+
+// rateLimiter returns the quota limiter for managers working in session.
+// A nil session, as used by API-only searches, gets plain quotas without session caps.
+// Dry runs check the quotas without waiting or using them up.
+// A configuration for one account of a campaign counts only that account's actions.
+// While the account warms up, its quotas are lowered.
+func (c *Client) rateLimiter(session *Session) ratelimit.Limiter {
+	var events ratelimit.EventStore = c.db
+	if c.cfg.Account != "" {
+		// Each account of a campaign has quotas of its own
+		events = ratelimit.ScopedStore(c.db, "account:"+c.cfg.Account)
+	}
+
+	if session == nil {
+		return c.warmedUp(ratelimit.NewPersistentRateLimiter(c.cfg.RateLimiterConfig(), events, c.logger()))
+	}
+	if session.limiter == nil {
+		var limiter ratelimit.Limiter
+		if c.opts.DryRun {
+			limiter = c.warmedUp(ratelimit.NewDryRunRateLimiter(c.cfg.RateLimiterConfig(), events, c.logger()))
+		} else {
+			quotas := c.warmedUp(ratelimit.NewPersistentRateLimiter(c.cfg.RateLimiterConfig(), events, c.logger()))
+			limiter = ratelimit.NewSessionLimiter(quotas, c.cfg.SessionLimiterConfig(), c.logger())
+		}
+		// Every action asks the limiter first, so it is where a lost login or a
+		// restricted account is noticed
+		session.monitor = auth.NewMonitor(session.auth, limiter, c.monitorConfig(), c.logger())
+		session.monitor.Watch(session.page)
+		session.guard = c.restrictionGuard(session.monitor)
+		session.guard.Watch(session.page)
+		session.limiter = session.guard
+	}
+	return session.limiter
+}
+
+// warmedUp lowers the quotas of limiter while the account warms up, if it does
+func (c *Client) warmedUp(limiter *ratelimit.RateLimiter) ratelimit.Limiter {
+	if !c.cfg.Limits.Warmup.Enabled || c.db == nil {
+		return limiter
+	}
+	return warmup.NewLimiter(limiter, c.cfg.RateLimiterConfig(), c.db, c.cfg.Account, c.cfg.WarmupConfig(), c.logger())
+}
+
+// restrictionGuard stops the account's session once LinkedIn restricts it,
+// recording the restriction for the cool-down and posting it to the webhook
+func (c *Client) restrictionGuard(limiter ratelimit.Limiter) *restriction.Guard {
+	guard := restriction.NewGuard(limiter, c.db, c.cfg.Account, c.cfg.Restrictions.Cooldown, c.logger())
+	if c.cfg.Restrictions.WebhookURL != "" {
+		guard.SetNotifier(restriction.NewWebhook(c.cfg.Restrictions.WebhookURL))
+	}
+	return guard
+}
+
+// monitorConfig converts the session check settings
+func (c *Client) monitorConfig() auth.MonitorConfig {
+	check := c.cfg.Browser.SessionCheck
+	return auth.MonitorConfig{
+		Interval:   check.Interval,
+		Attempts:   check.Attempts,
+		RetryDelay: check.RetryDelay,
+	}
+}
+
+// OpenTabs returns n sessions working tabs of the same browser, the first
+// being s itself. Extra tabs share the login, the rate limiter, the audit
+// log and captures, but get their own stealth manager, whose randomness is
+// not safe for concurrent use. The rate limiter paces the actions of each tab
+// separately when they run in a context from ratelimit.WithTab, and counts
+// them all against the same quotas. Close the extra tabs with CloseTabs.
+func (s *Session) OpenTabs(ctx context.Context, n int) ([]*Session, error) {
+	s.RateLimiter() // Created before sharing so every tab draws on the same quotas
+
+	tabs := []*Session{s}
+	for len(tabs) < n {
+		page, err := s.auth.NewPage()
+		if err != nil {
+			CloseTabs(tabs[1:])
+			return nil, fmt.Errorf("failed to open tab: %w", err)
+		}
+		if s.monitor != nil {
+			s.monitor.Watch(page)
+			s.guard.Watch(page)
+		}
+
+		stealthManager := s.client.StealthManager(ctx)
+		if err := stealthManager.ApplyStealth(page); err != nil {
+			s.client.logger().WithError(err).Warn("Failed to apply some stealth features")
+		}
+
+		tabs = append(tabs, &Session{
+			client:    s.client,
+			auth:      s.auth,
+			page:      page,
+			stealth:   stealthManager,
+			limiter:   s.limiter,
+			audit:     s.audit,
+			capture:   s.capture,
+			navigator: s.client.navigator(stealthManager),
+		})
+	}
+	return tabs, nil
+}
+
+// CloseTabs closes the pages of tabs opened by OpenTabs, leaving the browser running
+func CloseTabs(tabs []*Session) {
+	for _, tab := range tabs {
+		tab.page.Close()
+	}
+}
+
+// Close closes the page and the browser
+func (s *Session) Close() {
+	if s.screencast != nil {
+		if _, err := s.screencast.Stop(); err != nil {
+			s.client.logger().WithError(err).Warn("Failed to save session recording")
+		}
+	}
+	if s.monitor != nil {
+		s.monitor.Stop()
+		// A batch stopped by a restriction page leaves it open; record it
+		s.guard.Check(context.Background())
+	}
+	s.page.Close()
+	s.auth.Close()
+	if s.audit != nil {
+		s.audit.Flush() // Keystrokes typed just before closing
+	}
+}
+
+// APIClient returns a Voyager client when API mode is enabled, authenticated
+// with the configured cookies or, failing that, those of the browser session.
+// It returns nil when API mode is off or no credentials are available.
+func (c *Client) APIClient(session *Session) *voyager.Client {
+	if !c.cfg.API.Enabled {
+		return nil
+	}
+
+	credentials := c.apiCredentials(session)
+	if !credentials.Valid() {
+		c.logger().Debug("API mode enabled but no session cookies available")
+		return nil
+	}
+
+	client := voyager.NewClient(credentials, c.cfg.Browser.UserAgent, c.logger())
+	if c.cfg.API.SearchQueryID != "" {
+		client.SetSearchQueryID(c.cfg.API.SearchQueryID)
+	}
+	return client
+}
+
+// apiCredentials returns the configured session cookies or, failing that,
+// those of the browser session
+func (c *Client) apiCredentials(session *Session) voyager.Credentials {
+	credentials := voyager.Credentials{LiAt: c.cfg.API.LiAt, JSessionID: c.cfg.API.JSessionID}
+	if !credentials.Valid() && session != nil {
+		credentials.LiAt, credentials.JSessionID = session.auth.SessionCookies()
+	}
+	return credentials
+}
+
+// CompanyResolver returns a resolver for company names, caching results in
+// the database, or nil when no session cookies are available for the typeahead
+func (c *Client) CompanyResolver(session *Session) *resolve.Resolver {
+	credentials := c.apiCredentials(session)
+	if !credentials.Valid() {
+		return nil
+	}
+	client := voyager.NewClient(credentials, c.cfg.Browser.UserAgent, c.logger())
+	return resolve.NewResolver(client, c.db, c.logger())
+}
+
+// SearchManager creates a search manager; with a nil session it can only
+// search through the API
+func (c *Client) SearchManager(session *Session) *search.SearchManager {
+	var page *rod.Page
+	if session != nil {
+		page = session.page
+	}
+
+	searchManager := search.NewSearchManager(page, c.logger())
+	searchManager.SetRateLimiter(c.rateLimiter(session))
+	if session != nil {
+		searchManager.SetCapturer(session.capture)
+		searchManager.SetClicker(session.stealth)
+	}
+	if client := c.APIClient(session); client != nil {
+		searchManager.SetAPIClient(client)
+	}
+	if c.cfg.API.ResolveCompanies {
+		if resolver := c.CompanyResolver(session); resolver != nil {
+			searchManager.SetCompanyResolver(resolver)
+		}
+	}
+	return searchManager
+}
+
+func (c *Client) Personalizer(session *Session) *personalize.Personalizer {
+	personalizer := personalize.NewPersonalizer(c.db, c.logger())
+	if client := c.APIClient(session); client != nil {
+		personalizer.SetProfileFetcher(client)
+	}
+	if writer := c.OpenerWriter(); writer != nil {
+		personalizer.SetOpener(writer)
+	}
+	return personalizer
+}
+
+// personalizerWith is Personalizer, also filling the variables given per
+// profile URL
+func (c *Client) personalizerWith(session *Session, variables map[string]map[string]string) *personalize.Personalizer {
+	personalizer := c.Personalizer(session)
+	for profileURL, profileVariables := range variables {
+		personalizer.SetVariables(profileURL, profileVariables)
+	}
+	return personalizer
+}
+
+// OpenerWriter creates the writer of {{ai_opener}} lines for the configured
+// language model, or returns nil if none is configured
+func (c *Client) OpenerWriter() *opener.Writer {
+	generator := c.aiGenerator()
+	if generator == nil {
+		return nil
+	}
+	ai := c.cfg.AI
+	return opener.NewWriter(generator, c.db, opener.Options{
+		Instructions:     ai.Instructions,
+		MaxTokens:        ai.MaxTokens,
+		DailyTokenBudget: ai.DailyTokenBudget,
+	}, c.logger())
+}
+
+// aiGenerator returns the configured language model, or nil if none is
+func (c *Client) aiGenerator() opener.Generator {
+	ai := c.cfg.AI
+	switch ai.Provider {
+	case "openai":
+		return opener.NewOpenAI(ai.APIKey, ai.Model, ai.BaseURL)
+	case "anthropic":
+		return opener.NewAnthropic(ai.APIKey, ai.Model, ai.BaseURL)
+	case "local":
+		return opener.NewLocal(ai.APIKey, ai.Model, ai.BaseURL)
+	}
+	return nil
+}
+
+// ReplyClassifier creates the classifier labelling new replies, or returns
+// nil if classification is disabled
+func (c *Client) ReplyClassifier() *replies.Classifier {
+	settings := c.cfg.Inbox.Classify
+	if !settings.Enabled {
+		return nil
+	}
+
+	classifier := replies.NewClassifier(c.db, replies.Rules{
+		Interested:    settings.Interested,
+		NotInterested: settings.NotInterested,
+		Later:         settings.Later,
+	}, settings.RevisitAfter, c.logger())
+	if settings.UseAI {
+		if generator := c.aiGenerator(); generator != nil {
+			classifier.SetGenerator(generator)
+		}
+	}
+	return classifier
+}
+
+// ProspectHours creates the timing of actions to each prospect's working
+// hours, or returns nil if stealth.schedule.prospect_timezone is off
+func (c *Client) ProspectHours() *geo.ProspectHours {
+	schedule := c.cfg.Stealth.Schedule
+	if !schedule.ProspectTimezone {
+		return nil
+	}
+
+	fallback, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		fallback = time.UTC
+	}
+	return geo.NewProspectHours(c.db, geo.Hours{Start: schedule.StartHour, End: schedule.EndHour}, fallback)
+}
+
+// OperatorHours creates the timing of every action to start_hour-end_hour on
+// weekdays in stealth.schedule.timezone, or returns nil if
+// stealth.schedule.business_hours_only is off
+func (c *Client) OperatorHours() *geo.OperatorHours {
+	schedule := c.cfg.Stealth.Schedule
+	if !schedule.BusinessHoursOnly {
+		return nil
+	}
+
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return geo.NewOperatorHours(geo.Hours{Start: schedule.StartHour, End: schedule.EndHour}, loc)
+}
+
+// Blacklist creates the do-not-contact check, resolving names and companies
+// the same way templates are personalized
+func (c *Client) Blacklist(session *Session) *blacklist.Checker {
+	return blacklist.NewChecker(c.db, c.Personalizer(session), c.logger())
+}
+
+// SequenceEngine creates the engine that queues the steps of drip sequences
+func (c *Client) SequenceEngine(session *Session, sequences map[string]*sequence.Sequence) *sequence.Engine {
+	engine := sequence.NewEngine(c.db, templates.NewManager(c.db, c.logger()), sequences, c.logger())
+	engine.SetBlacklist(c.Blacklist(session))
+	engine.SetSync(c.cfg.Sequences.SyncInterval, c.cfg.Sequences.InboxLimit)
+	return engine
+}
+
+func (c *Client) ScrapeManager(session *Session) *scrape.ScrapeManager {
+	scrapeManager := scrape.NewScrapeManager(session.page, c.logger(), session.stealth)
+	scrapeManager.SetRateLimiter(c.rateLimiter(session))
+	scrapeManager.SetCapturer(session.capture)
+	return scrapeManager
+}
+
+func (c *Client) ConnectManager(session *Session) *connect.ConnectManager {
+	connectManager := connect.NewConnectManager(session.page, c.logger(), session.stealth)
+	connectManager.SetBatchStore(c.db)
+	connectManager.SetRateLimiter(c.rateLimiter(session))
+	connectManager.SetPersonalizer(c.Personalizer(session))
+	connectManager.SetRetryPolicy(c.cfg.RetryPolicy())
+	connectManager.SetLimitStore(c.limitStore())
+	connectManager.SetDryRun(c.opts.DryRun)
+	connectManager.SetNoteLimit(templates.NoteLimit(c.cfg.LinkedIn.Premium))
+	connectManager.SetBlacklist(c.Blacklist(session))
+	connectManager.SetCapturer(session.capture)
+	if session.navigator != nil {
+		connectManager.SetNavigator(session.navigator)
+	}
+	connectManager.SetFallbacks(c.cfg.Connect.Fallbacks, openProfileMessenger{c.MessageManager(session)}, c.cfg.Connect.OpenProfileSubject)
+	return connectManager
+}
+
+// openProfileMessenger sends the open profile messages connection requests fall back to
+type openProfileMessenger struct {
+	manager *message.MessageManager
+}
+
+func (m openProfileMessenger) SendOpenProfileMessage(ctx context.Context, profileURL, subject, body string) error {
+	_, err := m.manager.SendOpenProfileMessage(ctx, profileURL, subject, body)
+	return err
+}
+
+// limitStore returns where LinkedIn's blocks on the account's actions are kept
+func (c *Client) limitStore() connect.LimitStore {
+	if c.cfg.Account == "" {
+		return c.db
+	}
+	return accountLimitStore{db: c.db, scope: "account:" + c.cfg.Account + ":"}
+}
+
+// accountLimitStore keeps the blocks on one account of a campaign apart from the others'
+type accountLimitStore struct {
+	db    *storage.Database
+	scope string
+}
+
+func (s accountLimitStore) GetActionBlock(action string) (time.Time, error) {
+	return s.db.GetActionBlock(s.scope + action)
+}
+
+func (s accountLimitStore) SetActionBlock(action string, until time.Time, reason string) error {
+	return s.db.SetActionBlock(s.scope+action, until, reason)
+}
+
+func (c *Client) InvitationManager(session *Session) *invitations.Manager {
+	invitationManager := invitations.NewManager(session.page, c.logger(), session.stealth)
+	invitationManager.SetStore(c.db)
+	invitationManager.SetDryRun(c.opts.DryRun)
+	invitationManager.SetCapturer(session.capture)
+	return invitationManager
+}
+
+func (c *Client) MessageManager(session *Session) *message.MessageManager {
+	messageManager := message.NewMessageManager(session.page, c.logger(), session.stealth)
+	messageManager.SetBatchStore(c.db)
+	messageManager.SetRateLimiter(c.rateLimiter(session))
+	messageManager.SetPersonalizer(c.Personalizer(session))
+	messageManager.SetInboxStore(c.db)
+	messageManager.SetRetryPolicy(c.cfg.RetryPolicy())
+	messageManager.SetCreditStore(c.db)
+	messageManager.SetDryRun(c.opts.DryRun)
+	messageManager.SetBlacklist(c.Blacklist(session))
+	messageManager.SetOptOut(c.cfg.Inbox.OptOutPhrases, blacklist.NewOptOutRecorder(c.db, c.logger()))
+	if classifier := c.ReplyClassifier(); classifier != nil {
+		messageManager.SetReplyClassifier(classifier)
+	}
+	messageManager.SetCapturer(session.capture)
+	if session.navigator != nil {
+		messageManager.SetNavigator(session.navigator)
+	}
+	return messageManager
+}
+
+func (c *Client) NurtureManager(session *Session) *nurture.Manager {
+	nurtureManager := nurture.NewManager(session.page, c.logger(), session.stealth)
+	nurtureManager.SetCapturer(session.capture)
+	return nurtureManager
+}
+
+func (c *Client) VisitManager(session *Session) *visit.VisitManager {
+	visitManager := visit.NewVisitManager(session.page, c.logger(), session.stealth)
+	visitManager.SetBatchStore(c.db)
+	visitManager.SetRateLimiter(c.rateLimiter(session))
+	visitManager.SetCapturer(session.capture)
+	if session.navigator != nil {
+		visitManager.SetNavigator(session.navigator)
+	}
+	return visitManager
+}
+
+func (c *Client) FeedBrowser(session *Session) *browse.FeedBrowser {
+	feedBrowser := browse.NewFeedBrowser(session.page, c.logger(), session.stealth)
+	feedBrowser.SetStore(c.db)
+	feedBrowser.SetRateLimiter(c.rateLimiter(session))
+	feedBrowser.SetDryRun(c.opts.DryRun)
+	feedBrowser.SetCapturer(session.capture)
+	return feedBrowser
+}
+
+func (c *Client) EndorseManager(session *Session) *endorse.EndorseManager {
+	endorseManager := endorse.NewEndorseManager(session.page, c.logger(), session.stealth)
+	endorseManager.SetStore(c.db)
+	endorseManager.SetRateLimiter(c.rateLimiter(session))
+	endorseManager.SetDryRun(c.opts.DryRun)
+	endorseManager.SetCapturer(session.capture)
+	return endorseManager
+}
+
+func (c *Client) EngageManager(session *Session) *engage.EngageManager {
+	engageManager := engage.NewEngageManager(session.page, c.logger(), session.stealth)
+	engageManager.SetStore(c.db)
+	engageManager.SetRateLimiter(c.rateLimiter(session))
+	engageManager.SetDryRun(c.opts.DryRun)
+	engageManager.SetCapturer(session.capture)
+	return engageManager
+}
+
+func convertConfigToStealth(cfg config.StealthConfig) stealth.StealthConfig {
+	return stealth.StealthConfig{
+		Enabled: cfg.Enabled,
+		MouseMovement: stealth.MouseMovementConfig{
+			BezierCurves:     cfg.MouseMovement.BezierCurves,
+			VariableSpeed:    cfg.MouseMovement.VariableSpeed,
+			Overshoot:        cfg.MouseMovement.Overshoot,
+			MicroCorrections: cfg.MouseMovement.MicroCorrections,
+			MinSpeed:         cfg.MouseMovement.MinSpeed,
+			MaxSpeed:         cfg.MouseMovement.MaxSpeed,
+			IdleMovements:    cfg.MouseMovement.IdleMovements,
+			IdleProbability:  cfg.MouseMovement.IdleProbability,
+		},
+		Timing: stealth.TimingConfig{
+			MinDelay:    cfg.Timing.MinDelay,
+			MaxDelay:    cfg.Timing.MaxDelay,
+			ThinkTime:   cfg.Timing.ThinkTime,
+			ScrollDelay: cfg.Timing.ScrollDelay,
+			ClickDelay:  cfg.Timing.ClickDelay,
+			TypeDelay:   cfg.Timing.TypeDelay,
+		},
+		Typing: stealth.TypingConfig{
+			VariableSpeed:   cfg.Typing.VariableSpeed,
+			TypoRate:        cfg.Typing.TypoRate,
+			CorrectionDelay: cfg.Typing.CorrectionDelay,
+			MinSpeed:        cfg.Typing.MinSpeed,
+			MaxSpeed:        cfg.Typing.MaxSpeed,
+			MaxComposeTime:  cfg.Typing.MaxComposeTime,
+			ReviewPause:     cfg.Typing.ReviewPause,
+			ReadDelay:       cfg.Typing.ReadDelay,
+		},
+		Scrolling: stealth.ScrollingConfig{
+			VariableSpeed: cfg.Scrolling.VariableSpeed,
+			Acceleration:  cfg.Scrolling.Acceleration,
+			Deceleration:  cfg.Scrolling.Deceleration,
+			ScrollBack:    cfg.Scrolling.ScrollBack,
+			MinSpeed:      cfg.Scrolling.MinSpeed,
+			MaxSpeed:      cfg.Scrolling.MaxSpeed,
+		},
+		Schedule: stealth.ScheduleConfig{
+			BusinessHoursOnly: cfg.Schedule.BusinessHoursOnly,
+			StartHour:         cfg.Schedule.StartHour,
+			EndHour:           cfg.Schedule.EndHour,
+			BreakDuration:     cfg.Schedule.BreakDuration,
+			BreakFrequency:    cfg.Schedule.BreakFrequency,
+			Timezone:          cfg.Schedule.Timezone,
+		},
+		Fingerprint: stealth.FingerprintConfig{
+			RandomUserAgent:   cfg.Fingerprint.RandomUserAgent,
+			RandomViewport:    cfg.Fingerprint.RandomViewport,
+			MinViewportWidth:  cfg.Fingerprint.MinViewportWidth,
+			MaxViewportWidth:  cfg.Fingerprint.MaxViewportWidth,
+			MinViewportHeight: cfg.Fingerprint.MinViewportHeight,
+			MaxViewportHeight: cfg.Fingerprint.MaxViewportHeight,
+			UserAgents:        cfg.Fingerprint.UserAgents,
+			SpoofCanvas:       cfg.Fingerprint.SpoofCanvas,
+			SpoofWebGL:        cfg.Fingerprint.SpoofWebGL,
+			SpoofAudio:        cfg.Fingerprint.SpoofAudio,
+		},
+		WarmUp: stealth.WarmUpConfig{
+			Probability:             cfg.WarmUp.Probability,
+			MinDuration:             cfg.WarmUp.MinDuration,
+			MaxDuration:             cfg.WarmUp.MaxDuration,
+			NotificationProbability: cfg.WarmUp.NotificationProbability,
+		},
+		Reading: stealth.ReadingConfig{
+			WordsPerMinute: cfg.Reading.WordsPerMinute,
+			MinDwell:       cfg.Reading.MinDwell,
+			MaxDwell:       cfg.Reading.MaxDwell,
+		},
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/analytics"
+	"linkedin-automation/storage"
+)
+
+// jobFields are the profile fields whose change means a new job
+var jobFields = []string{"title", "company"}
+
+func createAnalyticsCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "analytics",
+		Short: "Report acceptance and reply rates, template performance and daily trends",
+		Long: `Compute acceptance rate, reply rate, time-to-accept distribution, the
+best-performing connection and message templates, and daily activity charts
+from the stored history. Run 'connect sync-accepted' and 'message sync-inbox'
+first so acceptances and replies are up to date.`,
+		RunE: runAnalytics,
+	}
+
+	cmd.Flags().Int("days", 30, "Number of days to report on, ending today")
+	cmd.Flags().String("html", "", "Also write the report as an HTML page to this file")
+
+	cmd.AddCommand(createAnalyticsJobChangesCmd())
+
+	return cmd
+}
+
+func createAnalyticsJobChangesCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "job-changes",
+		Short: "List prospects who moved to a new title or company",
+		Long: `List the new titles and companies that 'visit profiles' noticed when it
+scraped a stored profile again. By default only past prospects, profiles
+already sent a connection request or message, are listed: a job change is a
+good reason to get back in touch.`,
+		RunE: runAnalyticsJobChanges,
+	}
+
+	cmd.Flags().Duration("since", 30*24*time.Hour, "How far back to list changes")
+	cmd.Flags().Bool("all", false, "Include profiles never contacted")
+	cmd.Flags().String("profile", "", "Show every title, company and location recorded for this profile instead")
+
+	return cmd
+}
+
+func runAnalytics(cmd *cobra.Command, args []string) error {
+	days, _ := cmd.Flags().GetInt("days")
+	htmlPath, _ := cmd.Flags().GetString("html")
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	report, err := analytics.Build(db, days, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to build analytics: %w", err)
+	}
+	invites, err := inviteBudget(cfg, db, time.Now())
+	if err != nil {
+		return err
+	}
+	if invites != nil {
+		report.Invites = invites.WeeklyPlan
+	}
+
+	if jsonOutput {
+		if err := report.WriteJSON(os.Stdout); err != nil {
+			return fmt.Errorf("failed to write JSON report: %w", err)
+		}
+	} else if err := report.WriteText(os.Stdout); err != nil {
+		return err
+	}
+
+	if htmlPath != "" {
+		f, err := os.Create(htmlPath)
+		if err != nil {
+			return fmt.Errorf("failed to create HTML report: %w", err)
+		}
+		defer f.Close()
+
+		if err := report.WriteHTML(f); err != nil {
+			return fmt.Errorf("failed to write HTML report: %w", err)
+		}
+		if !jsonOutput {
+			fmt.Printf("HTML report written to %s\n", htmlPath)
+		}
+	}
+
+	return nil
+}
+
+// jobChange is a past prospect's new title or company, as listed by
+// 'analytics job-changes'
+type jobChange struct {
+	ProfileURL string                   `json:"profile_url"`
+	Name       string                   `json:"name,omitempty"`
+	Contacted  bool                     `json:"contacted"`
+	ChangedAt  time.Time                `json:"changed_at"`
+	Changes    []*storage.ProfileChange `json:"changes"`
+}
+
+func runAnalyticsJobChanges(cmd *cobra.Command, args []string) error {
+	since, _ := cmd.Flags().GetDuration("since")
+	all, _ := cmd.Flags().GetBool("all")
+	profileURL, _ := cmd.Flags().GetString("profile")
+
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if profileURL != "" {
+		return printProfileHistory(db, profileURL)
+	}
+
+	changes, err := db.ListProfileChanges(time.Now().Add(-since), jobFields)
+	if err != nil {
+		return err
+	}
+	contacted, err := db.GetContactedProfiles()
+	if err != nil {
+		return fmt.Errorf("failed to load contacted profiles: %w", err)
+	}
+
+	// One entry per profile, ordered by its latest change
+	var moved []*jobChange
+	byProfile := make(map[string]*jobChange)
+	for _, change := range changes {
+		if !all && !contacted[change.ProfileURL] {
+			continue
+		}
+		entry := byProfile[change.ProfileURL]
+		if entry == nil {
+			entry = &jobChange{
+				ProfileURL: change.ProfileURL,
+				Name:       change.Name,
+				Contacted:  contacted[change.ProfileURL],
+				ChangedAt:  change.ChangedAt,
+			}
+			byProfile[change.ProfileURL] = entry
+			moved = append(moved, entry)
+		}
+		entry.Changes = append(entry.Changes, change)
+	}
+
+	if jsonOutput {
+		return printJSON(moved)
+	}
+
+	fmt.Printf("Job Changes\n")
+	fmt.Printf("===========\n\n")
+	if len(moved) == 0 {
+		fmt.Printf("None noticed; 'visit profiles' records changes when it scrapes a stored profile again\n")
+		return nil
+	}
+	for _, entry := range moved {
+		name := entry.Name
+		if name == "" {
+			name = entry.ProfileURL
+		}
+		fmt.Printf("%s  %s", entry.ChangedAt.Local().Format("2006-01-02"), name)
+		if !entry.Contacted {
+			fmt.Printf("  (not contacted)")
+		}
+		fmt.Printf("\n    %s\n", entry.ProfileURL)
+		for _, change := range entry.Changes {
+			fmt.Printf("    %s: %s -> %s\n", change.Field, change.OldValue, change.NewValue)
+		}
+	}
+	fmt.Printf("\nTotal: %d\n", len(moved))
+
+	return nil
+}
+
+// printProfileHistory prints the values recorded for a profile's title,
+// company and location over its scrapes
+func printProfileHistory(db *storage.Database, profileURL string) error {
+	history, err := db.GetProfileHistory(profileURL)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		return printJSON(history)
+	}
+
+	if len(history) == 0 {
+		fmt.Printf("No history recorded for %s\n", profileURL)
+		return nil
+	}
+	for _, change := range history {
+		fmt.Printf("%s  %-8s ", change.ChangedAt.Local().Format("2006-01-02"), change.Field)
+		if change.OldValue == "" {
+			fmt.Printf("%s\n", change.NewValue)
+		} else {
+			fmt.Printf("%s -> %s\n", change.OldValue, change.NewValue)
+		}
+	}
+
+	return nil
+}
+
+// hasJobChange reports whether changes include a new title or company
+func hasJobChange(changes []*storage.ProfileChange) bool {
+	for _, change := range changes {
+		for _, field := range jobFields {
+			if change.Field == field {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/audit"
+	"linkedin-automation/storage"
+)
+
+func createAuditCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the log of browser actions",
+		Long: `Inspect the audit log of every navigation, click and keystroke batch the
+browser performed, with the page it happened on and whether it succeeded.
+Typed text is never logged, only the number of keys.`,
+	}
+
+	cmd.AddCommand(createAuditShowCmd())
+
+	return cmd
+}
+
+func createAuditShowCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "show",
+		Short: "Show recorded browser actions",
+		RunE:  runAuditShow,
+	}
+
+	cmd.Flags().String("since", "", "Only show actions from this date on (YYYY-MM-DD or RFC 3339)")
+	cmd.Flags().String("until", "", "Only show actions before this date (YYYY-MM-DD is inclusive, or RFC 3339)")
+	cmd.Flags().String("profile", "", "Only show actions on this profile")
+	cmd.Flags().String("action", "", "Only show this action (navigate, click, type)")
+	cmd.Flags().String("run", "", "Only show actions of this run, by the run_id its log entries carry")
+	cmd.Flags().Int("limit", 100, "Show at most this many of the most recent actions (0 for all)")
+
+	return cmd
+}
+
+// auditLog reads audit entries
+type auditLog interface {
+	ListAuditEntries(filter storage.AuditFilter) ([]*storage.AuditEntry, error)
+}
+
+func runAuditShow(cmd *cobra.Command, args []string) error {
+	since, _ := cmd.Flags().GetString("since")
+	until, _ := cmd.Flags().GetString("until")
+	profile, _ := cmd.Flags().GetString("profile")
+	action, _ := cmd.Flags().GetString("action")
+	runID, _ := cmd.Flags().GetString("run")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	filter := storage.AuditFilter{ProfileURL: profile, Action: action, RunID: runID, Limit: limit}
+	var err error
+	if filter.Since, err = parseExportDate(since, false); err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	if filter.Until, err = parseExportDate(until, true); err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var log auditLog = db
+	if cfg.Audit.File != "" {
+		log = audit.NewFileLog(cfg.Audit.File)
+	}
+
+	entries, err := log.ListAuditEntries(filter)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Audit Log\n")
+	fmt.Printf("=========\n\n")
+	if len(entries) == 0 {
+		fmt.Printf("No actions recorded\n")
+		return nil
+	}
+	for _, entry := range entries {
+		target := entry.Target
+		if entry.Detail != "" {
+			target = fmt.Sprintf("%s (%s)", target, entry.Detail)
+		}
+		fmt.Printf("%s  %-8s %-5s %6s  %s\n", entry.OccurredAt.Local().Format("2006-01-02 15:04:05"), entry.Action,
+			entry.Outcome, entry.Duration.Round(time.Millisecond), target)
+		if entry.PageURL != "" && entry.Action != storage.AuditNavigate {
+			fmt.Printf("    on %s\n", entry.PageURL)
+		}
+		if entry.Error != "" {
+			fmt.Printf("    error: %s\n", entry.Error)
+		}
+	}
+	fmt.Printf("\nTotal: %d\n", len(entries))
+
+	return nil
+}
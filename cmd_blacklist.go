@@ -0,0 +1,297 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/blacklist"
+	"linkedin-automation/storage"
+)
+
+func createBlacklistCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "blacklist",
+		Short: "Manage the do-not-contact list",
+		Long: `Manage the profiles, company domains and name patterns that connection
+requests and messages are never sent to, such as customers, competitors or
+people who opted out.`,
+	}
+
+	cmd.AddCommand(createBlacklistListCmd())
+	cmd.AddCommand(createBlacklistAddCmd())
+	cmd.AddCommand(createBlacklistRemoveCmd())
+	cmd.AddCommand(createBlacklistImportCmd())
+
+	return cmd
+}
+
+func createBlacklistListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List blacklist entries",
+		RunE:  runBlacklistList,
+	}
+}
+
+func createBlacklistAddCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "add",
+		Short: "Add profiles, domains or name patterns to the blacklist",
+		RunE:  runBlacklistAdd,
+	}
+
+	addBlacklistEntryFlags(cmd)
+	cmd.Flags().String("reason", "", "Why these entries must not be contacted")
+
+	return cmd
+}
+
+func createBlacklistRemoveCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "remove",
+		Short: "Remove profiles, domains or name patterns from the blacklist",
+		RunE:  runBlacklistRemove,
+	}
+
+	addBlacklistEntryFlags(cmd)
+
+	return cmd
+}
+
+func createBlacklistImportCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "import",
+		Short: "Import blacklist entries from a file",
+		Long: `Import blacklist entries from a CSV or plain text file. Each line holds a
+value and an optional reason ("acme.com,customer"), or a kind, a value and an
+optional reason ("name,john doe*,opted out"). Without a kind, profile URLs,
+domains and names are told apart automatically. Lines starting with # are ignored.`,
+		RunE: runBlacklistImport,
+	}
+
+	cmd.Flags().String("file", "", "File to import")
+	cmd.Flags().String("kind", "", "Treat every value as this kind (profile, domain or name)")
+	cmd.Flags().String("reason", "", "Reason for entries that do not give one")
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func addBlacklistEntryFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("profile", nil, "Profile URL (repeatable)")
+	cmd.Flags().StringArray("domain", nil, "Company domain, e.g. acme.com (repeatable)")
+	cmd.Flags().StringArray("name", nil, "Name or pattern, e.g. \"john doe\" or \"* smith\" (repeatable)")
+}
+
+// blacklistEntriesFromFlags collects and normalizes the entries given on the command line
+func blacklistEntriesFromFlags(cmd *cobra.Command) ([]*storage.BlacklistEntry, error) {
+	var entries []*storage.BlacklistEntry
+	for _, kind := range blacklist.Kinds {
+		values, _ := cmd.Flags().GetStringArray(kind)
+		for _, value := range values {
+			normalized, err := blacklist.Normalize(kind, value)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, &storage.BlacklistEntry{Kind: kind, Value: normalized})
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("give at least one --profile, --domain or --name")
+	}
+	return entries, nil
+}
+
+func runBlacklistList(cmd *cobra.Command, args []string) error {
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	entries, err := db.ListBlacklist()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Blacklist\n")
+	fmt.Printf("=========\n\n")
+	if len(entries) == 0 {
+		fmt.Printf("No entries\n")
+		return nil
+	}
+	for _, entry := range entries {
+		fmt.Printf("%-8s %s", entry.Kind, entry.Value)
+		if entry.Reason != "" {
+			fmt.Printf(" (%s)", entry.Reason)
+		}
+		fmt.Printf("\n")
+	}
+	fmt.Printf("\nTotal: %d\n", len(entries))
+
+	return nil
+}
+
+func runBlacklistAdd(cmd *cobra.Command, args []string) error {
+	reason, _ := cmd.Flags().GetString("reason")
+
+	entries, err := blacklistEntriesFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	for _, entry := range entries {
+		entry.Reason = reason
+		added, err := db.AddBlacklistEntry(entry)
+		if err != nil {
+			return err
+		}
+		if added {
+			fmt.Printf("Added %s %s\n", entry.Kind, entry.Value)
+		} else {
+			fmt.Printf("Updated %s %s\n", entry.Kind, entry.Value)
+		}
+	}
+
+	return nil
+}
+
+func runBlacklistRemove(cmd *cobra.Command, args []string) error {
+	entries, err := blacklistEntriesFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	for _, entry := range entries {
+		removed, err := db.RemoveBlacklistEntry(entry.Kind, entry.Value)
+		if err != nil {
+			return err
+		}
+		if removed {
+			fmt.Printf("Removed %s %s\n", entry.Kind, entry.Value)
+		} else {
+			fmt.Printf("Not on the blacklist: %s %s\n", entry.Kind, entry.Value)
+		}
+	}
+
+	return nil
+}
+
+func runBlacklistImport(cmd *cobra.Command, args []string) error {
+	file, _ := cmd.Flags().GetString("file")
+	kind, _ := cmd.Flags().GetString("kind")
+	reason, _ := cmd.Flags().GetString("reason")
+
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	if kind != "" && !isBlacklistKind(kind) {
+		return fmt.Errorf("unknown blacklist kind %q (want %s)", kind, strings.Join(blacklist.Kinds, ", "))
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer f.Close()
+
+	entries, err := parseBlacklistFile(f, kind, reason)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	added := 0
+	for _, entry := range entries {
+		isNew, err := db.AddBlacklistEntry(entry)
+		if err != nil {
+			return err
+		}
+		if isNew {
+			added++
+		}
+	}
+
+	fmt.Printf("Imported %d entries (%d new, %d already listed)\n", len(entries), added, len(entries)-added)
+	return nil
+}
+
+// parseBlacklistFile reads entries as described in the import command's help
+func parseBlacklistFile(r io.Reader, kind, reason string) ([]*storage.BlacklistEntry, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var entries []*storage.BlacklistEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		// Skip a header row such as "kind,value,reason"
+		if header := strings.ToLower(strings.TrimSpace(record[0])); len(entries) == 0 && (header == "kind" || header == "value") {
+			continue
+		}
+
+		entryKind := kind
+		if entryKind == "" {
+			if isBlacklistKind(record[0]) && len(record) > 1 {
+				entryKind, record = strings.ToLower(strings.TrimSpace(record[0])), record[1:]
+			} else {
+				entryKind = blacklist.DetectKind(record[0])
+			}
+		}
+
+		value, err := blacklist.Normalize(entryKind, record[0])
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		entry := &storage.BlacklistEntry{Kind: entryKind, Value: value, Reason: reason}
+		if len(record) > 1 && strings.TrimSpace(record[1]) != "" {
+			entry.Reason = strings.TrimSpace(record[1])
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func isBlacklistKind(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, kind := range blacklist.Kinds {
+		if value == kind {
+			return true
+		}
+	}
+	return false
+}
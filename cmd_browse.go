@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/browse"
+)
+
+// Browsing behaviour shared by 'browse feed' and the interleaved browsing of 'queue run'
+const (
+	defaultReactProbability = 0.05
+	defaultHoverProbability = 0.2
+)
+
+func createBrowseCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "browse",
+		Short: "Browse LinkedIn passively",
+		Long:  `Generate organic-looking activity between outreach batches.`,
+	}
+
+	cmd.AddCommand(createBrowseFeedCmd())
+	return cmd
+}
+
+func createBrowseFeedCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "feed",
+		Short: "Scroll and read the feed for a while",
+		Long: `Scroll the feed with reading pauses that follow each post's length, now and
+then like a post or hover over its author's profile, and stop after --duration.
+
+Reactions count against limits.daily_likes and are stored in the engagements
+table; once the limit is reached, browsing continues without them.`,
+		RunE: runBrowseFeed,
+	}
+
+	cmd.Flags().Duration("duration", 10*time.Minute, "How long to browse")
+	cmd.Flags().Float64("react-probability", defaultReactProbability, "Chance of liking each post read (0 disables reactions)")
+	cmd.Flags().Float64("hover-probability", defaultHoverProbability, "Chance of hovering over the author of each post read")
+
+	return cmd
+}
+
+func runBrowseFeed(cmd *cobra.Command, args []string) error {
+	duration, _ := cmd.Flags().GetDuration("duration")
+	react, _ := cmd.Flags().GetFloat64("react-probability")
+	hover, _ := cmd.Flags().GetFloat64("hover-probability")
+
+	if duration <= 0 {
+		return fmt.Errorf("--duration must be positive")
+	}
+	if react < 0 || react > 1 || hover < 0 || hover > 1 {
+		return fmt.Errorf("probabilities must be between 0 and 1")
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := cmd.Context()
+
+	browser, err := openBrowserSession(ctx, cfg, db)
+	if err != nil {
+		return err
+	}
+	defer browser.Close()
+
+	stats, err := newFeedBrowser(cfg, browser, db).Browse(ctx, browse.Options{
+		Duration:         duration,
+		ReactProbability: react,
+		HoverProbability: hover,
+	})
+	if err != nil {
+		return fmt.Errorf("feed browsing failed: %w", err)
+	}
+
+	fmt.Printf("Feed browsing completed!\n")
+	fmt.Printf("Duration: %s\n", stats.Duration)
+	fmt.Printf("Posts read: %d\n", stats.PostsRead)
+	fmt.Printf("Reactions: %d\n", stats.Reactions)
+	if dryRun {
+		fmt.Printf("  (dry run: no post was liked)\n")
+	}
+	fmt.Printf("Profile hovers: %d\n", stats.ProfileHovers)
+	return nil
+}
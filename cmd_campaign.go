@@ -0,0 +1,432 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/client"
+	"linkedin-automation/config"
+	"linkedin-automation/connect"
+	"linkedin-automation/logger"
+	"linkedin-automation/message"
+	"linkedin-automation/personalize"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/storage"
+	"linkedin-automation/templates"
+)
+
+func createCampaignCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "campaign",
+		Short: "Spread outreach over several accounts",
+		Long: `Split one prospect list between the accounts configured under accounts, each
+logging in with its own browser, proxy and limits. Every prospect is assigned
+to one account and stays with it, so no two accounts contact the same person.`,
+	}
+
+	cmd.AddCommand(createCampaignRunCmd())
+	cmd.AddCommand(createCampaignAssignmentsCmd())
+
+	return cmd
+}
+
+func createCampaignRunCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "run",
+		Short: "Work a prospect list with several accounts at once",
+		Long: `Assign each prospect to one of --accounts and run a batch per account, all at
+the same time. Prospects assigned in an earlier run stay with their account;
+those owned by an account not in --accounts are left out. New prospects go to
+the account with the fewest.
+
+Each account's batch is tracked as campaign-<campaign>-<account>, so --resume
+skips what each account already processed.`,
+		RunE: runCampaign,
+	}
+
+	cmd.Flags().String("accounts", "", "Comma-separated names of configured accounts")
+	cmd.Flags().String("profiles", "", "Comma-separated list of profile URLs")
+	cmd.Flags().String("action", "connect", "What to do with each prospect: connect or message")
+	cmd.Flags().String("message", "", "Connection note or message content")
+	cmd.Flags().String("template", "", "Template for the note or message (defaults to professional or follow_up_professional)")
+	cmd.Flags().String("campaign", "", "Campaign name (derived from the profile list if empty)")
+	cmd.Flags().Bool("resume", false, "Skip prospects each account already processed in a previous run of the campaign")
+	cmd.Flags().Bool("exclude-contacted", false, "Skip profiles already sent a connection request or message")
+	addTagFilterFlag(cmd, "profiles")
+
+	return cmd
+}
+
+func createCampaignAssignmentsCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "assignments",
+		Short: "Show which account owns each prospect",
+		RunE:  runCampaignAssignments,
+	}
+
+	cmd.Flags().String("campaign", "", "Only show prospects assigned by this campaign")
+	cmd.Flags().Bool("counts", false, "Only show the number of prospects per account")
+
+	return cmd
+}
+
+// campaignAccount is one account's share of a campaign run
+type campaignAccount struct {
+	name     string
+	cfg      *config.Config
+	profiles []string
+	deferred int // Prospects left out because they are outside their working hours
+	session  *browserSession
+	connect  *connect.BatchResult
+	message  *message.BatchResult
+	err      error
+}
+
+func runCampaign(cmd *cobra.Command, args []string) error {
+	accountNames, _ := cmd.Flags().GetString("accounts")
+	profiles, _ := cmd.Flags().GetString("profiles")
+	action, _ := cmd.Flags().GetString("action")
+	content, _ := cmd.Flags().GetString("message")
+	template, _ := cmd.Flags().GetString("template")
+	campaign, _ := cmd.Flags().GetString("campaign")
+	resume, _ := cmd.Flags().GetBool("resume")
+	excludeContacted, _ := cmd.Flags().GetBool("exclude-contacted")
+	tags, _ := cmd.Flags().GetStringArray("tag")
+
+	if action != "connect" && action != "message" {
+		return fmt.Errorf("--action must be connect or message")
+	}
+	names := parseCommaSeparated(accountNames)
+	if len(names) == 0 {
+		return fmt.Errorf("--accounts is required")
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Resolve every account up front so a misspelled name fails before any browser starts
+	accounts := make([]*campaignAccount, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			return fmt.Errorf("account %q is listed twice", name)
+		}
+		seen[name] = true
+		accountCfg, err := cfg.ForAccount(name)
+		if err != nil {
+			return err
+		}
+		accounts = append(accounts, &campaignAccount{name: name, cfg: accountCfg})
+	}
+
+	profileList, err := applyTagFilter(db, profileurl.Dedupe(parseCommaSeparated(profiles)), tags)
+	if err != nil {
+		return err
+	}
+	if len(profileList) == 0 {
+		if len(tags) > 0 {
+			return fmt.Errorf("no profiles with tags %s", strings.Join(tags, ", "))
+		}
+		return fmt.Errorf("no profiles provided")
+	}
+
+	excludedCount := 0
+	if excludeContacted {
+		contacted, err := db.GetContactedProfiles()
+		if err != nil {
+			return fmt.Errorf("failed to load contacted profiles: %w", err)
+		}
+		remaining := make([]string, 0, len(profileList))
+		for _, profileURL := range profileList {
+			if contacted[profileURL] {
+				excludedCount++
+				continue
+			}
+			remaining = append(remaining, profileURL)
+		}
+		profileList = remaining
+		if len(profileList) == 0 {
+			fmt.Printf("All %d profiles have already been contacted\n", excludedCount)
+			return nil
+		}
+	}
+
+	kind, defaultTemplate := templates.KindConnection, "professional"
+	if action == "message" {
+		kind, defaultTemplate = templates.KindMessage, "follow_up_professional"
+	}
+	templateName := ""
+	if content == "" {
+		if template == "" {
+			template = defaultTemplate
+		}
+		t, err := templates.NewManager(db, logger.GetLogger()).Get(kind, template)
+		if err != nil {
+			return fmt.Errorf("failed to load template: %w", err)
+		}
+		content, templateName = t.Content, template
+	}
+	if err := personalize.Validate(content); err != nil {
+		return err
+	}
+
+	if campaign == "" {
+		campaign = client.DeriveBatchID("campaign", profileList)
+	}
+
+	assigned, ownedElsewhere, err := assignProspects(db, campaign, profileList, names)
+	if err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		account.profiles, account.deferred, err = applyProspectHours(account.cfg, db, assigned[account.name])
+		if err != nil {
+			return err
+		}
+	}
+
+	// Log in one account at a time; each gets a browser of its own
+	ctx := cmd.Context()
+	for _, account := range accounts {
+		if len(account.profiles) == 0 || ctx.Err() != nil {
+			continue
+		}
+		session, err := openBrowserSession(ctx, account.cfg, db)
+		if err != nil {
+			logger.GetLogger().WithError(err).WithField("account", account.name).Warn("Account could not log in; its prospects stay assigned to it")
+			account.err = err
+			continue
+		}
+		defer session.Close()
+		account.session = session
+	}
+
+	var wg sync.WaitGroup
+	for _, account := range accounts {
+		if account.session == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(account *campaignAccount) {
+			defer wg.Done()
+
+			account.session.WarmUp(ctx)
+			batchID := "campaign-" + campaign + "-" + account.name
+			ctx, tracker := startProgress(ctx, account.cfg, batchID, action, len(account.profiles))
+			defer tracker.Finish()
+
+			if action == "connect" {
+				account.connect, account.err = newConnectManager(account.cfg, account.session, db).BatchSendConnectionRequests(ctx, account.profiles, content, connect.BatchOptions{
+					BatchID:  batchID,
+					Resume:   resume,
+					Progress: tracker,
+				})
+				return
+			}
+			account.message, account.err = newMessageManager(account.cfg, account.session, db).BatchSendMessages(ctx, account.profiles, content, message.BatchOptions{
+				BatchID:  batchID,
+				Resume:   resume,
+				Progress: tracker,
+			})
+		}(account)
+	}
+	wg.Wait()
+
+	// Store whatever was sent, even by accounts whose batch ended in an error
+	for _, account := range accounts {
+		var err error
+		if account.connect != nil {
+			err = client.RecordConnections(db, campaign, templateName, account.connect.Results)
+		}
+		if account.message != nil {
+			err = client.RecordMessages(db, "direct", templateName, account.message.Results)
+		}
+		if err != nil {
+			logger.GetLogger().WithError(err).WithField("account", account.name).Warn("Failed to store results")
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("Dry run: nothing was sent and no prospect was assigned\n\n")
+	}
+
+	fmt.Printf("Campaign %s\n", campaign)
+	fmt.Printf("Total profiles: %d\n", len(profileList))
+	if ownedElsewhere > 0 {
+		fmt.Printf("Skipped (owned by another account): %d\n", ownedElsewhere)
+	}
+	if excludeContacted {
+		fmt.Printf("Excluded (already contacted): %d\n", excludedCount)
+	}
+	fmt.Printf("\n")
+
+	interrupted := false
+	totalFailed, totalAttempted := 0, 0
+	var totalStopErr error
+	var accountErr error
+	for _, account := range accounts {
+		sent, skipped, failed, attempted := 0, 0, 0, 0
+		var stopErr error
+		if account.connect != nil {
+			attempted = len(account.connect.Results)
+			for _, result := range account.connect.Results {
+				switch {
+				case result.Skipped || result.EmailRequired || result.Blacklisted || result.Rejected:
+					skipped++
+				case result.Success:
+					sent++
+				default:
+					failed++
+				}
+			}
+			if account.connect.StoppedAtLimit {
+				stopErr = account.connect.StopErr
+			}
+			interrupted = interrupted || account.connect.Interrupted
+		}
+		if account.message != nil {
+			attempted = len(account.message.Results)
+			for _, result := range account.message.Results {
+				switch {
+				case result.Skipped:
+					skipped++
+				case result.Success:
+					sent++
+				default:
+					failed++
+				}
+			}
+			if account.message.StoppedAtLimit {
+				stopErr = account.message.StopErr
+			}
+			interrupted = interrupted || account.message.Interrupted
+		}
+
+		fmt.Printf("  %-16s assigned %d, sent %d, skipped %d, failed %d\n", account.name, len(account.profiles)+account.deferred, sent, skipped, failed)
+		if account.deferred > 0 {
+			fmt.Printf("  %-16s deferred (outside working hours): %d (re-run later with --resume)\n", "", account.deferred)
+		}
+		if stopErr != nil {
+			fmt.Printf("  %-16s stopped at limit: %v (%d not attempted)\n", "", stopErr, len(account.profiles)-attempted)
+		}
+		if account.err != nil {
+			fmt.Printf("  %-16s error: %v\n", "", account.err)
+			if accountErr == nil {
+				accountErr = fmt.Errorf("account %s: %w", account.name, account.err)
+			}
+		}
+		totalFailed += failed
+		totalAttempted += attempted
+		if totalStopErr == nil {
+			totalStopErr = stopErr
+		}
+	}
+
+	if interrupted {
+		fmt.Printf("\nInterrupted; resume with: %s <same arguments> --campaign %s --resume\n", cmd.CommandPath(), campaign)
+	}
+
+	// An account that failed outright decides the exit code, e.g. when its login expired
+	if accountErr != nil {
+		return &exitError{code: exitCode(accountErr), err: accountErr, reported: true}
+	}
+	return batchExit("profiles", totalFailed, totalAttempted, totalStopErr)
+}
+
+// assignProspects splits profiles between accounts. A prospect assigned in an
+// earlier run stays with its account; one owned by an account outside the list
+// is left out, so it is never contacted from a second account. New prospects go
+// to the account with the fewest and are stored unless this is a dry run.
+func assignProspects(db *storage.Database, campaign string, profiles, accounts []string) (map[string][]string, int, error) {
+	assigned := make(map[string][]string, len(accounts))
+	inList := make(map[string]bool, len(accounts))
+	for _, account := range accounts {
+		inList[account] = true
+	}
+
+	var unassigned []string
+	ownedElsewhere := 0
+	for _, profileURL := range profiles {
+		owner, err := db.GetAssignment(profileURL)
+		if err != nil {
+			return nil, 0, err
+		}
+		switch {
+		case owner == nil:
+			unassigned = append(unassigned, profileURL)
+		case inList[owner.Account]:
+			assigned[owner.Account] = append(assigned[owner.Account], profileURL)
+		default:
+			ownedElsewhere++
+		}
+	}
+
+	for _, profileURL := range unassigned {
+		account := accounts[0]
+		for _, candidate := range accounts[1:] {
+			if len(assigned[candidate]) < len(assigned[account]) {
+				account = candidate
+			}
+		}
+		if !dryRun {
+			if _, err := db.SaveAssignment(&storage.Assignment{ProfileURL: profileURL, Account: account, Campaign: campaign}); err != nil {
+				return nil, 0, err
+			}
+		}
+		assigned[account] = append(assigned[account], profileURL)
+	}
+
+	return assigned, ownedElsewhere, nil
+}
+
+func runCampaignAssignments(cmd *cobra.Command, args []string) error {
+	campaign, _ := cmd.Flags().GetString("campaign")
+	counts, _ := cmd.Flags().GetBool("counts")
+
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	assignments, err := db.ListAssignments(campaign)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Assignments\n")
+	fmt.Printf("===========\n\n")
+	if len(assignments) == 0 {
+		fmt.Printf("No assignments\n")
+		return nil
+	}
+
+	if counts {
+		perAccount := make(map[string]int)
+		var order []string
+		for _, assignment := range assignments {
+			if perAccount[assignment.Account] == 0 {
+				order = append(order, assignment.Account)
+			}
+			perAccount[assignment.Account]++
+		}
+		for _, account := range order {
+			fmt.Printf("%-20s %d profiles\n", account, perAccount[account])
+		}
+	} else {
+		fmt.Printf("%-20s %-20s %-20s %s\n", "ACCOUNT", "CAMPAIGN", "ASSIGNED", "PROFILE")
+		for _, assignment := range assignments {
+			fmt.Printf("%-20s %-20s %-20s %s\n", assignment.Account, assignment.Campaign,
+				assignment.AssignedAt.Local().Format("2006-01-02 15:04"), assignment.ProfileURL)
+		}
+	}
+
+	fmt.Printf("\nTotal: %d\n", len(assignments))
+	return nil
+}
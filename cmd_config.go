@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"linkedin-automation/config"
+)
+
+func createConfigCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the configuration",
+	}
+
+	cmd.AddCommand(createConfigCheckCmd())
+
+	return cmd
+}
+
+func createConfigCheckCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "check",
+		Short: "Validate the configuration and print the effective settings",
+		Long: `Read the configuration file and LINKEDIN_* environment variables the same way
+every other command does, then report every problem at once: values of the
+wrong type, settings out of range such as a start hour after the end hour or a
+minimum delay above the maximum, and missing credentials. Keys that no setting
+reads, usually typos, are reported as warnings.
+
+The effective configuration after defaults and environment variables are
+applied is printed with passwords, keys, tokens and cookies masked. The command
+fails when there are errors, and with --strict when there are warnings too.`,
+		RunE: runConfigCheck,
+	}
+
+	cmd.Flags().Bool("strict", false, "Fail on warnings such as unknown keys")
+	cmd.Flags().Bool("quiet", false, "Only print problems, not the effective configuration")
+
+	return cmd
+}
+
+func runConfigCheck(cmd *cobra.Command, args []string) error {
+	strict, _ := cmd.Flags().GetBool("strict")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+
+	result := config.Check(configFile)
+
+	if _, err := os.Stat(configFile); err != nil && config.EnvOnly() {
+		fmt.Printf("Config: no file at %s, using defaults and LINKEDIN_* environment variables\n", configFile)
+	} else {
+		fmt.Printf("Config: %s\n", configFile)
+	}
+
+	if len(result.Credentials) > 0 {
+		fmt.Printf("\nCredentials:\n")
+		for _, credential := range result.Credentials {
+			fmt.Printf("  %-34s %s\n", credential.Key, credential.Source)
+		}
+	}
+
+	if len(result.Warnings) > 0 {
+		fmt.Printf("\nWarnings:\n")
+		for _, warning := range result.Warnings {
+			fmt.Printf("  - %s\n", warning)
+		}
+	}
+	if len(result.Errors) > 0 {
+		fmt.Printf("\nErrors:\n")
+		for _, problem := range result.Errors {
+			fmt.Printf("  - %s\n", problem)
+		}
+	}
+
+	if result.Config != nil && !quiet {
+		data, err := yaml.Marshal(result.Config.Masked())
+		if err != nil {
+			return fmt.Errorf("failed to encode config: %w", err)
+		}
+		fmt.Printf("\nEffective configuration:\n%s", data)
+	}
+
+	switch {
+	case len(result.Errors) > 0:
+		return &exitError{code: exitConfig, err: fmt.Errorf("config check found %d error(s)", len(result.Errors))}
+	case strict && len(result.Warnings) > 0:
+		return &exitError{code: exitConfig, err: fmt.Errorf("config check found %d warning(s)", len(result.Warnings))}
+	}
+	fmt.Printf("\nConfiguration is valid\n")
+	return nil
+}
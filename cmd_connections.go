@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/export"
+	"linkedin-automation/storage"
+)
+
+func createConnectionsCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "connections",
+		Short: "Work with the account's 1st-degree connections",
+	}
+
+	cmd.AddCommand(createConnectionsExportCmd())
+	return cmd
+}
+
+func createConnectionsExportCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "export",
+		Short: "Export the full connection list to storage and CSV",
+		Long: `Scroll through the account's connection list and store each connection's
+name, headline, profile URL and connection date in the network_connections
+table, then write every stored connection to a CSV file.
+
+Dates LinkedIn shows as "Connected 2 weeks ago" are approximate; the first date
+recorded for a connection is kept on later exports.`,
+		RunE: runConnectionsExport,
+	}
+
+	cmd.Flags().StringP("output", "o", "", "CSV file (defaults to connections-<date>.csv)")
+	cmd.Flags().Int("max-results", 0, "Stop after this many connections (0 for all)")
+	cmd.Flags().Bool("stored", false, "Write the stored connections without opening LinkedIn")
+
+	return cmd
+}
+
+func runConnectionsExport(cmd *cobra.Command, args []string) error {
+	output, _ := cmd.Flags().GetString("output")
+	maxResults, _ := cmd.Flags().GetInt("max-results")
+	stored, _ := cmd.Flags().GetBool("stored")
+
+	if maxResults < 0 {
+		return fmt.Errorf("--max-results must not be negative")
+	}
+	if output == "" {
+		output = fmt.Sprintf("connections-%s.csv", time.Now().Format("2006-01-02"))
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if !stored {
+		ctx := cmd.Context()
+
+		browser, err := openBrowserSession(ctx, cfg, db)
+		if err != nil {
+			return err
+		}
+		defer browser.Close()
+
+		connections, exportErr := newConnectManager(cfg, browser, db).ExportConnections(ctx, maxResults)
+		records := make([]*storage.NetworkConnection, 0, len(connections))
+		for _, c := range connections {
+			records = append(records, &storage.NetworkConnection{
+				ProfileURL:  c.ProfileURL,
+				Name:        c.Name,
+				Headline:    c.Headline,
+				ConnectedAt: c.ConnectedAt,
+			})
+		}
+		added, err := db.SaveNetworkConnections(records)
+		if err != nil {
+			return err
+		}
+		if exportErr != nil {
+			return fmt.Errorf("connection export failed after %d connections: %w", len(connections), exportErr)
+		}
+		fmt.Printf("Read %d connections (%d new)\n", len(connections), added)
+	}
+
+	connections, err := db.ListNetworkConnections()
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(output); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer file.Close()
+
+	if err := export.WriteCSV(file, export.NetworkTable(connections)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported %d connections to %s\n", len(connections), output)
+	return nil
+}
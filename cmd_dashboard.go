@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/config"
+	"linkedin-automation/dashboard"
+	"linkedin-automation/logger"
+	"linkedin-automation/progress"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/storage"
+)
+
+// dashboardResults is the number of recent batch results the dashboard reads
+const dashboardResults = 20
+
+func createDashboardCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "dashboard",
+		Short: "Follow a running daemon in a terminal UI",
+		Long: `Show limit gauges, queue depth, running batches, recent results and alerts
+on one screen, refreshed every --interval. Run it in a second terminal next to
+'queue run --follow' or any long batch; it only reads the database and never
+opens a browser.
+
+Pass --log-file with the file the daemon's output is written to, e.g.
+'queue run --follow > daemon.log 2>&1', to follow the log as well. Checkpoints,
+CAPTCHAs, expired sessions and errors in the log are raised as alerts.`,
+		RunE: runDashboard,
+	}
+
+	cmd.Flags().Duration("interval", 2*time.Second, "How often the dashboard refreshes")
+	cmd.Flags().String("log-file", "", "Log file of the daemon to follow")
+
+	return cmd
+}
+
+func runDashboard(cmd *cobra.Command, args []string) error {
+	interval, _ := cmd.Flags().GetDuration("interval")
+	logFile, _ := cmd.Flags().GetString("log-file")
+
+	if interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := setupLogger(cfg.Logging); err != nil {
+		return fmt.Errorf("failed to setup logger: %w", err)
+	}
+	// Log output would draw over the screen
+	logger.GetLogger().SetOutput(io.Discard)
+
+	db, err := openStorage(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer db.Close()
+
+	return dashboard.Run(cmd.Context(), &dashboardSource{cfg: cfg, db: db}, dashboard.Options{
+		Interval: interval,
+		LogFile:  logFile,
+	})
+}
+
+// dashboardSource reads the dashboard's figures from the database and the
+// progress files of running batches
+type dashboardSource struct {
+	cfg *config.Config
+	db  *storage.Database
+}
+
+func (s *dashboardSource) Snapshot() (*dashboard.Snapshot, error) {
+	now := time.Now()
+	snapshot := &dashboard.Snapshot{}
+
+	quotas, _, err := currentQuotas(s.cfg, s.db, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, limit := range actionLimits(quotas) {
+		daily, err := s.db.CountRateLimitEvents(string(limit.action), now.Add(-24*time.Hour))
+		if err != nil {
+			return nil, err
+		}
+		hourly, err := s.db.CountRateLimitEvents(string(limit.action), now.Add(-time.Hour))
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Gauges = append(snapshot.Gauges, dashboard.Gauge{
+			Name:        string(limit.action),
+			Daily:       daily,
+			DailyLimit:  limit.daily,
+			Hourly:      hourly,
+			HourlyLimit: limit.hourly,
+		})
+	}
+
+	taskCounts, err := s.db.CountTasksByStatus()
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range []string{storage.TaskPending, storage.TaskRunning, storage.TaskDone, storage.TaskFailed, storage.TaskCancelled} {
+		snapshot.Queue = append(snapshot.Queue, dashboard.Count{Name: status, Count: taskCounts[status]})
+	}
+
+	if snapshot.Running, err = progress.List(s.cfg.Storage.ProgressDir); err != nil {
+		return nil, err
+	}
+
+	items, err := s.db.GetRecentBatchItems(dashboardResults)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		snapshot.Results = append(snapshot.Results, dashboard.Result{
+			At:         item.ProcessedAt,
+			Action:     item.Action,
+			ProfileURL: item.ItemURL,
+			Status:     item.Status,
+			Detail:     item.ErrorMessage,
+		})
+	}
+
+	inviteBlock, err := s.db.GetActionBlock(string(ratelimit.ActionConnect))
+	if err != nil {
+		return nil, err
+	}
+	if now.Before(inviteBlock) {
+		snapshot.Alerts = append(snapshot.Alerts, dashboard.Alert{
+			At:      now,
+			Message: "LinkedIn weekly invitation limit: connection requests blocked until " + inviteBlock.Local().Format("2006-01-02 15:04"),
+		})
+	}
+	restriction, err := s.db.GetActiveRestriction("", now)
+	if err != nil {
+		return nil, err
+	}
+	if restriction != nil {
+		snapshot.Alerts = append(snapshot.Alerts, dashboard.Alert{
+			At:      restriction.DetectedAt,
+			Message: "LinkedIn restricted the account (" + restriction.Reason + "); automation paused until " + restriction.CooldownUntil.Local().Format("2006-01-02 15:04"),
+		})
+	}
+
+	return snapshot, nil
+}
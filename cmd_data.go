@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/profileurl"
+)
+
+func createDataCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "data",
+		Short: "Delete personal data on request or once it is old",
+		Long: `Delete what is stored about a person, such as when they ask to be
+forgotten, or about everyone inactive for a while. Opt-outs and blacklist
+entries are kept so nobody deleted is contacted again. Backups made before
+the deletion still hold the data until they are rotated out.`,
+	}
+
+	cmd.AddCommand(createDataForgetCmd())
+	cmd.AddCommand(createDataPurgeCmd())
+	cmd.AddCommand(createDataRetentionCmd())
+
+	return cmd
+}
+
+func createDataForgetCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "forget",
+		Short: "Delete a person's profile, requests, messages and audit entries",
+		Long: `Delete everything stored about a person: their profile and its history,
+connection requests, messages both ways, visits, engagements, tags, queued
+tasks and audit entries. With --dry-run the rows that would be deleted are
+listed instead.`,
+		RunE: runDataForget,
+	}
+
+	cmd.Flags().String("profile", "", "Profile URL of the person to forget")
+	cmd.MarkFlagRequired("profile")
+
+	return cmd
+}
+
+func createDataPurgeCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "purge",
+		Short: "Forget everyone with no activity for a while",
+		Long: `Forget every person whose profile was not updated, contacted, heard from
+or visited within --older-than. With --dry-run they are listed instead.`,
+		RunE: runDataPurge,
+	}
+
+	cmd.Flags().Duration("older-than", 0, "Forget people inactive for this long (e.g. 8760h)")
+	cmd.MarkFlagRequired("older-than")
+
+	return cmd
+}
+
+func createDataRetentionCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "retention",
+		Short: "Apply the retention policy now",
+		Long: `Clear the text of messages older than retention.messages and anonymize
+everyone inactive for retention.profiles, keeping their profile URL so they are
+never contacted twice, then report what was cleared. 'queue run --follow' does
+the same every retention.interval. With --dry-run nothing changes and the report
+lists what would.`,
+		RunE: runDataRetention,
+	}
+
+	return cmd
+}
+
+// forgetOutput is what 'data forget' and 'data purge' delete, or would
+type forgetOutput struct {
+	DryRun   bool                        `json:"dry_run"`
+	Profiles map[string]map[string]int64 `json:"profiles"` // Rows per table, by profile URL
+}
+
+func runDataForget(cmd *cobra.Command, args []string) error {
+	profileURL, _ := cmd.Flags().GetString("profile")
+	profileURL = profileurl.Canonicalize(profileURL)
+
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	deleted, err := db.ForgetProfile(profileURL, dryRun)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		return printJSON(forgetOutput{DryRun: dryRun, Profiles: map[string]map[string]int64{profileURL: deleted}})
+	}
+
+	if len(deleted) == 0 {
+		fmt.Printf("Nothing stored about %s\n", profileURL)
+		return nil
+	}
+	if dryRun {
+		fmt.Printf("Dry run: would delete for %s\n", profileURL)
+	} else {
+		fmt.Printf("Deleted for %s\n", profileURL)
+	}
+	printDeletedRows(deleted)
+	return nil
+}
+
+func runDataPurge(cmd *cobra.Command, args []string) error {
+	olderThan, _ := cmd.Flags().GetDuration("older-than")
+	if olderThan <= 0 {
+		return fmt.Errorf("--older-than must be positive")
+	}
+
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	cutoff := time.Now().Add(-olderThan)
+	inactive, err := db.InactiveProfiles(cutoff)
+	if err != nil {
+		return err
+	}
+
+	out := forgetOutput{DryRun: dryRun, Profiles: make(map[string]map[string]int64, len(inactive))}
+	total := make(map[string]int64)
+	for _, profileURL := range inactive {
+		deleted, err := db.ForgetProfile(profileURL, dryRun)
+		if err != nil {
+			return err
+		}
+		out.Profiles[profileURL] = deleted
+		for table, count := range deleted {
+			total[table] += count
+		}
+	}
+	if jsonOutput {
+		return printJSON(out)
+	}
+
+	if len(inactive) == 0 {
+		fmt.Printf("Nobody inactive since %s\n", cutoff.Local().Format("2006-01-02"))
+		return nil
+	}
+	if dryRun {
+		fmt.Printf("Dry run: would forget %d people inactive since %s\n", len(inactive), cutoff.Local().Format("2006-01-02"))
+		for _, profileURL := range inactive {
+			fmt.Printf("  %s\n", profileURL)
+		}
+	} else {
+		fmt.Printf("Forgot %d people inactive since %s\n", len(inactive), cutoff.Local().Format("2006-01-02"))
+	}
+	printDeletedRows(total)
+	return nil
+}
+
+func runDataRetention(cmd *cobra.Command, args []string) error {
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	manager := newRetentionManager(cfg, db)
+	if !manager.Enabled() {
+		return fmt.Errorf("no retention period is set; set retention.messages or retention.profiles")
+	}
+	report, err := manager.Run(time.Now(), dryRun)
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		return printJSON(report)
+	}
+
+	if report.Empty() {
+		fmt.Printf("Nothing older than the retention policy allows\n")
+		return nil
+	}
+	verb := "Cleared"
+	if dryRun {
+		verb = "Dry run: would clear"
+	}
+	if len(report.Messages) > 0 {
+		fmt.Printf("%s message text older than %s\n", verb, report.StartedAt.Add(-cfg.Retention.Messages).Local().Format("2006-01-02"))
+		printDeletedRows(report.Messages)
+	}
+	if len(report.Profiles) > 0 {
+		verb = "Anonymized"
+		if dryRun {
+			verb = "Dry run: would anonymize"
+		}
+		fmt.Printf("%s %d people inactive since %s\n", verb, len(report.Profiles), report.StartedAt.Add(-cfg.Retention.Profiles).Local().Format("2006-01-02"))
+		printDeletedRows(report.Anonymized)
+	}
+	return nil
+}
+
+// printDeletedRows prints the rows deleted or cleared per table
+func printDeletedRows(deleted map[string]int64) {
+	tables := make([]string, 0, len(deleted))
+	for table := range deleted {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		fmt.Printf("  %-22s %d\n", table, deleted[table])
+	}
+}
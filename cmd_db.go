@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/backup"
+	"linkedin-automation/config"
+	"linkedin-automation/storage"
+)
+
+func createDBCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "db",
+		Short: "Back up and restore the database",
+		Long: `Back up the database to storage.backup_dir, verify backups and restore one.
+SQLite databases are copied; Postgres and MySQL are dumped with pg_dump or
+mysqldump, which must be installed. With storage.backup enabled, a backup is
+also taken whenever a command starts and the last one is older than
+storage.backup_interval.`,
+	}
+
+	cmd.AddCommand(createDBBackupCmd())
+	cmd.AddCommand(createDBListCmd())
+	cmd.AddCommand(createDBVerifyCmd())
+	cmd.AddCommand(createDBRestoreCmd())
+
+	return cmd
+}
+
+func createDBBackupCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "backup",
+		Short: "Back up the database now",
+		RunE:  runDBBackup,
+	}
+}
+
+func createDBListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List backups, newest first",
+		RunE:  runDBList,
+	}
+}
+
+func createDBVerifyCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "verify",
+		Short: "Check a backup against its checksum and for completeness",
+		RunE:  runDBVerify,
+	}
+
+	cmd.Flags().String("file", "", "Backup to verify (default: the latest)")
+
+	return cmd
+}
+
+func createDBRestoreCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "restore",
+		Short: "Replace the database with a backup",
+		Long: `Verify a backup and replace the database with it. The current database is
+backed up first, so a restore can be undone by restoring that backup. Stop any
+running 'queue run' before restoring.`,
+		RunE: runDBRestore,
+	}
+
+	cmd.Flags().String("file", "", "Backup to restore (default: the latest)")
+
+	return cmd
+}
+
+// openBackups connects to the database without taking a scheduled backup
+func openBackups() (*config.Config, *storage.Database, *backup.Manager, error) {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := setupLogger(cfg.Logging); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to setup logger: %w", err)
+	}
+
+	db, err := connectStorage(cfg)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+
+	return cfg, db, newBackupManager(cfg, db), nil
+}
+
+// backupFile returns the --file flag, or the latest backup when it is not set
+func backupFile(cmd *cobra.Command, backups *backup.Manager) (string, error) {
+	file, _ := cmd.Flags().GetString("file")
+	if file != "" {
+		return file, nil
+	}
+
+	latest, err := backups.Latest()
+	if err != nil {
+		return "", err
+	}
+	if latest == nil {
+		return "", fmt.Errorf("no backups found; give a backup with --file")
+	}
+	return latest.Path, nil
+}
+
+func runDBBackup(cmd *cobra.Command, args []string) error {
+	_, db, backups, err := openBackups()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	created, err := backups.Create()
+	if err != nil {
+		return err
+	}
+	removed, err := backups.Prune()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Backed up the database to %s (%d bytes)\n", created.Path, created.Size)
+	if removed > 0 {
+		fmt.Printf("Removed %d old backups\n", removed)
+	}
+
+	return nil
+}
+
+func runDBList(cmd *cobra.Command, args []string) error {
+	_, db, backups, err := openBackups()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	list, err := backups.List()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Backups\n")
+	fmt.Printf("=======\n\n")
+	if len(list) == 0 {
+		fmt.Printf("No backups\n")
+		return nil
+	}
+	for _, b := range list {
+		fmt.Printf("%s  %10d  %s\n", b.CreatedAt.Format("2006-01-02 15:04:05"), b.Size, b.Path)
+	}
+	fmt.Printf("\nTotal: %d\n", len(list))
+
+	return nil
+}
+
+func runDBVerify(cmd *cobra.Command, args []string) error {
+	_, db, backups, err := openBackups()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	file, err := backupFile(cmd, backups)
+	if err != nil {
+		return err
+	}
+	if err := backups.Verify(file); err != nil {
+		return err
+	}
+
+	fmt.Printf("Backup %s is intact\n", file)
+	return nil
+}
+
+func runDBRestore(cmd *cobra.Command, args []string) error {
+	cfg, db, backups, err := openBackups()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	file, err := backupFile(cmd, backups)
+	if err != nil {
+		return err
+	}
+	if err := backups.Verify(file); err != nil {
+		return fmt.Errorf("not restoring %s: %w", file, err)
+	}
+
+	current, err := backups.Create()
+	if err != nil {
+		return fmt.Errorf("failed to back up the current database before restoring: %w", err)
+	}
+
+	// SQLite's file is replaced, so the connection must be closed first
+	db.Close()
+	if err := storage.Restore(db.Backend(), storageSource(cfg), file); err != nil {
+		return err
+	}
+
+	fmt.Printf("Restored the database from %s\n", file)
+	fmt.Printf("The previous database was backed up to %s\n", current.Path)
+	return nil
+}
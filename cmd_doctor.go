@@ -0,0 +1,236 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/auth"
+	"linkedin-automation/config"
+)
+
+// minShmSize is the /dev/shm size below which Chrome would run out of shared
+// memory on larger pages
+const minShmSize = 512 << 20
+
+// doctorCheck is the outcome of one environment check
+type doctorCheck struct {
+	name   string
+	status string // "ok", "WARN" or "FAIL"
+	detail string
+}
+
+func createDoctorCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "doctor",
+		Short: "Check that the environment can run the browser",
+		Long: `Check the configuration, browser, writable paths and container settings
+without contacting LinkedIn. Each check reports ok, WARN for something that
+works but may cause trouble, or FAIL for something that stops commands from
+running; the command fails when any check does.
+
+Use --launch to also start the browser with the configured settings.`,
+		RunE: runDoctor,
+	}
+
+	cmd.Flags().Bool("launch", false, "Start and close the browser as a final check")
+
+	return cmd
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	launch, _ := cmd.Flags().GetBool("launch")
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		printDoctorCheck(doctorCheck{"config", "FAIL", err.Error()})
+		return fmt.Errorf("doctor found 1 problem")
+	}
+
+	if err := setupLogger(cfg.Logging); err != nil {
+		return fmt.Errorf("failed to setup logger: %w", err)
+	}
+
+	checks := []doctorCheck{doctorConfigCheck()}
+	checks = append(checks, doctorContainerChecks(cfg)...)
+	checks = append(checks, doctorBrowserCheck(cfg))
+	checks = append(checks, doctorPathChecks(cfg)...)
+	checks = append(checks, doctorStorageCheck(cfg))
+	if launch {
+		checks = append(checks, doctorLaunchCheck(cfg))
+	}
+
+	failed := 0
+	for _, check := range checks {
+		printDoctorCheck(check)
+		if check.status == "FAIL" {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("doctor found %d problem(s)", failed)
+	}
+	fmt.Printf("\nEnvironment looks good\n")
+	return nil
+}
+
+func printDoctorCheck(check doctorCheck) {
+	fmt.Printf("  %-6s %-16s %s\n", check.status, check.name, check.detail)
+}
+
+func doctorConfigCheck() doctorCheck {
+	if _, err := os.Stat(configFile); err != nil && config.EnvOnly() {
+		return doctorCheck{"config", "ok", "no config file, using defaults and LINKEDIN_* environment variables"}
+	}
+	return doctorCheck{"config", "ok", configFile}
+}
+
+func doctorContainerChecks(cfg *config.Config) []doctorCheck {
+	var checks []doctorCheck
+
+	inside := inContainer()
+	switch {
+	case inside && !cfg.Browser.Container && cfg.Browser.RemoteDebuggingURL == "":
+		checks = append(checks, doctorCheck{"container", "WARN", "running in a container; set browser.container (LINKEDIN_BROWSER_CONTAINER=true) to launch without the sandbox"})
+	case inside:
+		checks = append(checks, doctorCheck{"container", "ok", "running in a container"})
+	case cfg.Browser.Container:
+		checks = append(checks, doctorCheck{"container", "ok", "container mode on outside a container"})
+	}
+
+	if os.Geteuid() == 0 && !cfg.Browser.Container && cfg.Browser.RemoteDebuggingURL == "" {
+		checks = append(checks, doctorCheck{"sandbox", "FAIL", "Chrome will not start as root with its sandbox; enable browser.container"})
+	}
+
+	// The launched browser is told to use /tmp instead, so a small /dev/shm only costs speed
+	if size, ok := shmSize(); ok && size < minShmSize {
+		checks = append(checks, doctorCheck{"shm", "ok", fmt.Sprintf("/dev/shm is %dMB; the browser uses /tmp instead (run with --shm-size=1g to avoid it)", size>>20)})
+	}
+
+	return checks
+}
+
+func doctorBrowserCheck(cfg *config.Config) doctorCheck {
+	if remote := cfg.Browser.RemoteDebuggingURL; remote != "" {
+		// Like the browser connection, accept host:port and a bare port
+		if _, err := strconv.Atoi(remote); err == nil {
+			remote = "127.0.0.1:" + remote
+		}
+		if !strings.Contains(remote, "://") {
+			remote = "http://" + remote
+		}
+		parsed, err := url.Parse(remote)
+		if err != nil || parsed.Host == "" {
+			return doctorCheck{"browser", "FAIL", "browser.remote_debugging_url is not a URL"}
+		}
+		host := parsed.Host
+		if parsed.Port() == "" {
+			port := "80"
+			if parsed.Scheme == "wss" || parsed.Scheme == "https" {
+				port = "443"
+			}
+			host = net.JoinHostPort(parsed.Hostname(), port)
+		}
+		conn, err := net.DialTimeout("tcp", host, 5*time.Second)
+		if err != nil {
+			return doctorCheck{"browser", "FAIL", fmt.Sprintf("remote browser unreachable: %v", err)}
+		}
+		conn.Close()
+		return doctorCheck{"browser", "ok", "remote browser reachable at " + parsed.Host}
+	}
+
+	if path := cfg.Browser.ExecutablePath; path != "" {
+		return doctorCheck{"browser", "ok", path}
+	}
+	if path := auth.FindChrome(); path != "" {
+		if cfg.Browser.Container {
+			return doctorCheck{"browser", "WARN", path + " found; set browser.executable_path so it is used instead of a downloaded Chromium"}
+		}
+		return doctorCheck{"browser", "ok", path}
+	}
+	return doctorCheck{"browser", "WARN", "no Chrome found; Chromium will be downloaded on first launch (needs network access and a writable home)"}
+}
+
+func doctorPathChecks(cfg *config.Config) []doctorCheck {
+	dirs := []struct{ name, dir string }{
+		{"session dir", cfg.Browser.SessionDir},
+		{"data dir", filepath.Dir(cfg.Storage.Path)},
+		{"progress dir", cfg.Storage.ProgressDir},
+	}
+	if cfg.Storage.Backup {
+		dirs = append(dirs, struct{ name, dir string }{"backup dir", cfg.Storage.BackupDir})
+	}
+
+	var checks []doctorCheck
+	for _, d := range dirs {
+		if d.dir == "" {
+			continue
+		}
+		if err := checkWritable(d.dir); err != nil {
+			checks = append(checks, doctorCheck{d.name, "FAIL", err.Error()})
+			continue
+		}
+		checks = append(checks, doctorCheck{d.name, "ok", d.dir})
+	}
+	return checks
+}
+
+func doctorStorageCheck(cfg *config.Config) doctorCheck {
+	db, err := openStorage(cfg)
+	if err != nil {
+		return doctorCheck{"database", "FAIL", err.Error()}
+	}
+	db.Close()
+
+	storageType := cfg.Storage.Type
+	if storageType == "" {
+		storageType = "sqlite"
+	}
+	return doctorCheck{"database", "ok", storageType}
+}
+
+func doctorLaunchCheck(cfg *config.Config) doctorCheck {
+	authManager := newAuthManager(cfg)
+	if err := authManager.InitializeBrowser(headless, cfg.Browser.UserAgent); err != nil {
+		return doctorCheck{"launch", "FAIL", err.Error()}
+	}
+	defer authManager.Close()
+
+	page, err := authManager.NewPage()
+	if err != nil {
+		return doctorCheck{"launch", "FAIL", err.Error()}
+	}
+	page.Close()
+	return doctorCheck{"launch", "ok", "browser started and opened a page"}
+}
+
+// checkWritable creates dir if needed and confirms files can be written in it
+func checkWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return fmt.Errorf("%s is not writable: %w", dir, err)
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+// inContainer reports whether the process runs in a Docker or Podman container
+func inContainer() bool {
+	for _, marker := range []string{"/.dockerenv", "/run/.containerenv"} {
+		if _, err := os.Stat(marker); err == nil {
+			return true
+		}
+	}
+	return os.Getenv("container") != ""
+}
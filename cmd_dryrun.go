@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"linkedin-automation/connect"
+	"linkedin-automation/engage"
+	"linkedin-automation/message"
+)
+
+// reportDryRunConnections lists the connection requests a dry run stopped short of sending
+func reportDryRunConnections(results []*connect.ConnectionResult) {
+	if !dryRun {
+		return
+	}
+
+	fmt.Printf("Dry run: nothing was sent\n")
+	for _, result := range results {
+		if !result.DryRun {
+			continue
+		}
+		if result.Message == "" {
+			fmt.Printf("Would send a connection request to %s without a note\n", result.ProfileURL)
+			continue
+		}
+		fmt.Printf("Would send a connection request to %s with the note:\n", result.ProfileURL)
+		printIndented(result.Message)
+	}
+	fmt.Printf("\n")
+}
+
+// reportDryRunMessages lists the messages a dry run stopped short of sending
+func reportDryRunMessages(results []*message.MessageResult) {
+	if !dryRun {
+		return
+	}
+
+	fmt.Printf("Dry run: nothing was sent\n")
+	for _, result := range results {
+		if !result.DryRun {
+			continue
+		}
+		fmt.Printf("Would message %s:\n", result.RecipientURL)
+		printIndented(result.Content)
+	}
+	fmt.Printf("\n")
+}
+
+// reportDryRunEngagements lists the likes and comments a dry run stopped short of making
+func reportDryRunEngagements(results []*engage.EngagementResult) {
+	if !dryRun {
+		return
+	}
+
+	fmt.Printf("Dry run: nothing was liked or commented on\n")
+	for _, result := range results {
+		if !result.DryRun {
+			continue
+		}
+		switch result.Action {
+		case engage.ActionLike:
+			fmt.Printf("Would like post %s of %s\n", result.PostURN, result.TargetURL)
+		case engage.ActionComment:
+			fmt.Printf("Would comment on post %s of %s:\n", result.PostURN, result.TargetURL)
+			printIndented(result.Content)
+		}
+	}
+	fmt.Printf("\n")
+}
+
+func printIndented(text string) {
+	fmt.Printf("  %s\n", strings.ReplaceAll(text, "\n", "\n  "))
+}
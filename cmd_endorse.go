@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/endorse"
+	"linkedin-automation/logger"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/storage"
+)
+
+func createEndorseCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "endorse",
+		Short: "Endorse skills of 1st-degree connections",
+		Long: `Open the skills page of each connection and endorse up to --max-skills of
+their skills. The skills under endorse.skills in the configuration are endorsed
+in order of preference; without them, the top listed skills are endorsed.
+
+Skills endorsed in an earlier run or already endorsed on LinkedIn are skipped.
+Endorsements count against limits.daily_endorsements and are stored in the
+endorsements table.`,
+		RunE: runEndorse,
+	}
+
+	cmd.Flags().String("profiles", "", "Comma-separated list of connection profile URLs")
+	cmd.Flags().String("skills", "", "Comma-separated skills to endorse (defaults to endorse.skills)")
+	cmd.Flags().Int("max-skills", 0, "Skills endorsed per connection (defaults to endorse.max_skills)")
+	addTagFilterFlag(cmd, "profiles")
+
+	return cmd
+}
+
+func runEndorse(cmd *cobra.Command, args []string) error {
+	profiles, _ := cmd.Flags().GetString("profiles")
+	skills, _ := cmd.Flags().GetString("skills")
+	maxSkills, _ := cmd.Flags().GetInt("max-skills")
+	tags, _ := cmd.Flags().GetStringArray("tag")
+
+	if maxSkills < 0 {
+		return fmt.Errorf("--max-skills must not be negative")
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	profileList, err := applyTagFilter(db, profileurl.Dedupe(parseCommaSeparated(profiles)), tags)
+	if err != nil {
+		return err
+	}
+	if len(profileList) == 0 {
+		if len(tags) > 0 {
+			return fmt.Errorf("no profiles with tags %s", strings.Join(tags, ", "))
+		}
+		return fmt.Errorf("no profiles provided")
+	}
+
+	opts := endorse.Options{
+		Skills:    cfg.Endorse.Skills,
+		MaxSkills: cfg.Endorse.MaxSkills,
+	}
+	if skills != "" {
+		opts.Skills = parseCommaSeparated(skills)
+	}
+	if maxSkills > 0 {
+		opts.MaxSkills = maxSkills
+	}
+
+	ctx := cmd.Context()
+
+	browser, err := openBrowserSession(ctx, cfg, db)
+	if err != nil {
+		return err
+	}
+	defer browser.Close()
+
+	batch, err := newEndorseManager(cfg, browser, db).Endorse(ctx, profileList, opts)
+	if batch != nil {
+		if err := recordEndorsementResults(db, batch.Results); err != nil {
+			logger.GetLogger().WithError(err).Warn("Failed to store endorsements")
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("endorsement failed: %w", err)
+	}
+
+	if dryRun {
+		fmt.Printf("Dry run: nothing was endorsed\n")
+		for _, result := range batch.Results {
+			if result.DryRun {
+				fmt.Printf("Would endorse %s on %s\n", result.Skill, result.ProfileURL)
+			}
+		}
+		fmt.Printf("\n")
+	}
+
+	endorsedCount := 0
+	skippedCount := 0
+	failedCount := 0
+	for _, result := range batch.Results {
+		switch {
+		case result.Skipped:
+			skippedCount++
+		case result.Success:
+			endorsedCount++
+		default:
+			failedCount++
+		}
+	}
+
+	fmt.Printf("Endorsements completed!\n")
+	fmt.Printf("Profiles: %d\n", len(profileList))
+	fmt.Printf("Endorsed: %d\n", endorsedCount)
+	fmt.Printf("Skipped (already endorsed): %d\n", skippedCount)
+	fmt.Printf("Failed: %d\n", failedCount)
+	if batch.StoppedAtLimit {
+		fmt.Printf("Stopped at limit: %s\n", batch.StopReason)
+	}
+
+	return batchExit("endorsements", failedCount, len(batch.Results), batch.StopErr)
+}
+
+// recordEndorsementResults stores attempted endorsements
+func recordEndorsementResults(db *storage.Database, results []*endorse.EndorsementResult) error {
+	for _, result := range results {
+		// Skipped skills were recorded by an earlier run or are already endorsed on LinkedIn;
+		// profile-level failures have no skill to record
+		if result.Skipped || result.Skill == "" {
+			continue
+		}
+
+		status := storage.EngagementDone
+		if !result.Success {
+			status = storage.EngagementFailed
+		}
+		if err := db.SaveEndorsement(&storage.Endorsement{
+			ProfileURL:   result.ProfileURL,
+			Skill:        result.Skill,
+			Status:       status,
+			ErrorMessage: result.ErrorMessage,
+			CreatedAt:    result.CreatedAt,
+			DryRun:       result.DryRun,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/engage"
+	"linkedin-automation/logger"
+	"linkedin-automation/personalize"
+	"linkedin-automation/storage"
+	"linkedin-automation/templates"
+)
+
+func createEngageCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "engage",
+		Short: "Engage with posts",
+		Long:  `Like and comment on recent posts to warm up prospects before sending connection requests.`,
+	}
+
+	cmd.AddCommand(createEngagePostsCmd())
+	return cmd
+}
+
+func createEngagePostsCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "posts",
+		Short: "Like and comment on recent posts of profiles or company pages",
+		Long: `Open the recent activity of each target and like and/or comment on its latest posts.
+
+Posts already liked or commented on in an earlier run are skipped. Likes and
+comments count against limits.daily_likes and limits.daily_comments.`,
+		RunE: runEngagePosts,
+	}
+
+	cmd.Flags().String("targets", "", "Comma-separated list of profile or company page URLs")
+	cmd.Flags().Int("posts", 3, "Number of recent posts per target")
+	cmd.Flags().Bool("like", true, "Like each post")
+	cmd.Flags().Bool("comment", false, "Comment on each post")
+	cmd.Flags().String("comment-template", "comment_insightful", "Comment template")
+	cmd.MarkFlagRequired("targets")
+
+	return cmd
+}
+
+func runEngagePosts(cmd *cobra.Command, args []string) error {
+	targets, _ := cmd.Flags().GetString("targets")
+	posts, _ := cmd.Flags().GetInt("posts")
+	like, _ := cmd.Flags().GetBool("like")
+	comment, _ := cmd.Flags().GetBool("comment")
+	commentTemplate, _ := cmd.Flags().GetString("comment-template")
+
+	if !like && !comment {
+		return fmt.Errorf("nothing to do: use --like and/or --comment")
+	}
+	if posts < 1 {
+		return fmt.Errorf("--posts must be at least 1")
+	}
+
+	targetList := parseCommaSeparated(targets)
+	if len(targetList) == 0 {
+		return fmt.Errorf("no targets provided")
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	opts := engage.Options{Posts: posts, Like: like}
+	if comment {
+		t, err := templates.NewManager(db, logger.GetLogger()).Get(templates.KindComment, commentTemplate)
+		if err != nil {
+			return fmt.Errorf("failed to load template: %w", err)
+		}
+		if err := personalize.Validate(t.Content); err != nil {
+			return err
+		}
+		opts.Comment = t.Content
+	}
+
+	ctx := cmd.Context()
+
+	browser, err := openBrowserSession(ctx, cfg, db)
+	if err != nil {
+		return err
+	}
+	defer browser.Close()
+
+	batch, err := newEngageManager(cfg, browser, db).Engage(ctx, targetList, opts)
+	if batch != nil {
+		if err := recordEngagementResults(db, batch.Results); err != nil {
+			logger.GetLogger().WithError(err).Warn("Failed to store engagements")
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("engagement failed: %w", err)
+	}
+
+	reportDryRunEngagements(batch.Results)
+
+	counts := make(map[string]int)
+	skippedCount := 0
+	failedCount := 0
+	for _, result := range batch.Results {
+		switch {
+		case result.Skipped:
+			skippedCount++
+		case result.Success:
+			counts[result.Action]++
+		default:
+			failedCount++
+		}
+	}
+
+	fmt.Printf("Post engagement completed!\n")
+	fmt.Printf("Targets: %d\n", len(targetList))
+	fmt.Printf("Liked: %d\n", counts[engage.ActionLike])
+	fmt.Printf("Commented: %d\n", counts[engage.ActionComment])
+	fmt.Printf("Skipped (already engaged): %d\n", skippedCount)
+	fmt.Printf("Failed: %d\n", failedCount)
+	if batch.StoppedAtLimit {
+		fmt.Printf("Stopped at limit: %s\n", batch.StopReason)
+	}
+
+	return batchExit("engagements", failedCount, len(batch.Results), batch.StopErr)
+}
+
+// recordEngagementResults stores attempted likes and comments
+func recordEngagementResults(db *storage.Database, results []*engage.EngagementResult) error {
+	for _, result := range results {
+		// Skipped posts were recorded by an earlier run or are already liked on LinkedIn;
+		// target-level failures have no post to record
+		if result.Skipped || result.PostURN == "" {
+			continue
+		}
+
+		status := storage.EngagementDone
+		if !result.Success {
+			status = storage.EngagementFailed
+		}
+		if err := db.SaveEngagement(&storage.Engagement{
+			TargetURL:    result.TargetURL,
+			PostURN:      result.PostURN,
+			Action:       result.Action,
+			Content:      result.Content,
+			Status:       status,
+			ErrorMessage: result.ErrorMessage,
+			CreatedAt:    result.CreatedAt,
+			DryRun:       result.DryRun,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
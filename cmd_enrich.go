@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/config"
+	"linkedin-automation/enrich"
+	"linkedin-automation/logger"
+	"linkedin-automation/profileurl"
+)
+
+func createEnrichCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "enrich",
+		Short: "Add details to stored profiles from external sources",
+	}
+
+	cmd.AddCommand(createEnrichEmailsCmd())
+	return cmd
+}
+
+func createEnrichEmailsCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "emails",
+		Short: "Look up work email addresses for scraped profiles",
+		Long: `Look up a work email address for each profile from its scraped name and
+company, through the email finders configured under enrich (Hunter.io,
+Apollo, Dropcontact). Providers are tried in order until one finds an address,
+which is stored with the profile and included in 'export --entity profiles'.
+
+Profiles that already have an address are skipped, as are profiles no
+provider found one for in an earlier run unless --recheck is given.`,
+		RunE: runEnrichEmails,
+	}
+
+	cmd.Flags().String("profiles", "", "Comma-separated list of profile URLs")
+	cmd.Flags().String("provider", "", "Only use this provider (hunter, apollo, dropcontact)")
+	cmd.Flags().Bool("recheck", false, "Look up profiles again that no address was found for before")
+	addTagFilterFlag(cmd, "profiles")
+
+	return cmd
+}
+
+func runEnrichEmails(cmd *cobra.Command, args []string) error {
+	profiles, _ := cmd.Flags().GetString("profiles")
+	only, _ := cmd.Flags().GetString("provider")
+	recheck, _ := cmd.Flags().GetBool("recheck")
+	tags, _ := cmd.Flags().GetStringArray("tag")
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	providers, err := newEmailFinders(cfg, only)
+	if err != nil {
+		return err
+	}
+
+	profileList, err := applyTagFilter(db, profileurl.Dedupe(parseCommaSeparated(profiles)), tags)
+	if err != nil {
+		return err
+	}
+	if len(profileList) == 0 {
+		if len(tags) > 0 {
+			return fmt.Errorf("no profiles with tags %s", strings.Join(tags, ", "))
+		}
+		return fmt.Errorf("no profiles provided")
+	}
+
+	enricher := enrich.NewEnricher(db, providers, logger.GetLogger())
+	result, err := enricher.Enrich(cmd.Context(), profileList, enrich.Options{
+		MinScore: cfg.Enrich.MinScore,
+		Recheck:  recheck,
+	})
+	if result != nil {
+		for _, lookup := range result.Lookups {
+			switch {
+			case lookup.Error != nil:
+				fmt.Printf("  %s failed: %v\n", lookup.ProfileURL, lookup.Error)
+			case lookup.Skipped == "" && lookup.Email != "":
+				fmt.Printf("  %s: %s (%s)\n", lookup.ProfileURL, lookup.Email, lookup.Provider)
+			}
+		}
+
+		fmt.Printf("Email enrichment completed!\n")
+		fmt.Printf("Found: %d\n", result.Found)
+		fmt.Printf("Not found: %d\n", result.NotFound)
+		fmt.Printf("Skipped: %d\n", result.Skipped)
+		fmt.Printf("Failed: %d\n", result.Failed)
+	}
+	if err != nil {
+		return fmt.Errorf("email enrichment failed: %w", err)
+	}
+	return nil
+}
+
+// newEmailFinders builds the configured email finders in order, optionally
+// limited to one
+func newEmailFinders(cfg *config.Config, only string) ([]enrich.Provider, error) {
+	names, finders := cfg.Enrich.EmailFinders()
+
+	var providers []enrich.Provider
+	for _, name := range names {
+		if only != "" && name != only {
+			continue
+		}
+		finder := finders[name]
+		switch name {
+		case "hunter":
+			providers = append(providers, enrich.NewHunter(finder.APIKey, finder.BaseURL))
+		case "apollo":
+			providers = append(providers, enrich.NewApollo(finder.APIKey, finder.BaseURL))
+		case "dropcontact":
+			providers = append(providers, enrich.NewDropcontact(finder.APIKey, finder.BaseURL))
+		}
+	}
+
+	if len(providers) == 0 {
+		if only != "" {
+			return nil, fmt.Errorf("provider %q is not configured under enrich", only)
+		}
+		return nil, fmt.Errorf("no email finders configured; set enrich.hunter, enrich.apollo or enrich.dropcontact api_key")
+	}
+	return providers, nil
+}
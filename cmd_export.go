@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/export"
+	"linkedin-automation/storage"
+)
+
+func createExportCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "export",
+		Short: "Export stored data",
+		Long: `Export profiles, connection requests and messages for import into a CRM.
+
+CSV writes one file per entity; with --entity all the entity name is appended
+to the output file name. JSON and XLSX write a single file with one section or
+sheet per entity.`,
+		RunE: runExport,
+	}
+
+	cmd.Flags().String("format", export.FormatCSV, "Output format (csv, json, xlsx)")
+	cmd.Flags().String("entity", "all", "Data to export (profiles, connections, messages, all)")
+	cmd.Flags().String("since", "", "Only export records from this date on (YYYY-MM-DD or RFC 3339)")
+	cmd.Flags().String("until", "", "Only export records before this date (YYYY-MM-DD is inclusive, or RFC 3339)")
+	cmd.Flags().StringP("output", "o", "", "Output file (defaults to export-<date>.<format>)")
+	cmd.Flags().StringArray("tag", nil, "Only export records of profiles with this tag (repeatable; every tag must match)")
+
+	return cmd
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	entity, _ := cmd.Flags().GetString("entity")
+	since, _ := cmd.Flags().GetString("since")
+	until, _ := cmd.Flags().GetString("until")
+	output, _ := cmd.Flags().GetString("output")
+	tags, _ := cmd.Flags().GetStringArray("tag")
+
+	format = strings.ToLower(format)
+	switch format {
+	case export.FormatCSV, export.FormatJSON, export.FormatXLSX:
+	default:
+		return fmt.Errorf("unsupported format %q (use csv, json or xlsx)", format)
+	}
+
+	var entities []string
+	switch entity {
+	case "all":
+		entities = []string{export.EntityProfiles, export.EntityConnections, export.EntityMessages}
+	case export.EntityProfiles, export.EntityConnections, export.EntityMessages:
+		entities = []string{entity}
+	default:
+		return fmt.Errorf("unsupported entity %q (use profiles, connections, messages or all)", entity)
+	}
+
+	var filter storage.ExportFilter
+	var err error
+	if filter.Since, err = parseExportDate(since, false); err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	if filter.Until, err = parseExportDate(until, true); err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
+
+	if output == "" {
+		output = fmt.Sprintf("export-%s.%s", time.Now().Format("2006-01-02"), format)
+	}
+
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if len(tags) > 0 {
+		tagged, err := db.GetTaggedProfiles(tags)
+		if err != nil {
+			return err
+		}
+		filter.Profiles = make(map[string]bool, len(tagged))
+		for _, profileURL := range tagged {
+			filter.Profiles[profileURL] = true
+		}
+	}
+
+	tables := make([]*export.Table, 0, len(entities))
+	for _, name := range entities {
+		table, err := loadExportTable(db, name, filter)
+		if err != nil {
+			return err
+		}
+		tables = append(tables, table)
+	}
+
+	switch format {
+	case export.FormatCSV:
+		for _, table := range tables {
+			path := output
+			if len(tables) > 1 {
+				ext := filepath.Ext(output)
+				path = strings.TrimSuffix(output, ext) + "-" + table.Name + ext
+			}
+			if err := writeExportFile(path, func(w io.Writer) error { return export.WriteCSV(w, table) }); err != nil {
+				return err
+			}
+			fmt.Printf("Exported %d %s to %s\n", len(table.Rows), table.Name, path)
+		}
+		return nil
+
+	case export.FormatJSON:
+		err = writeExportFile(output, func(w io.Writer) error { return export.WriteJSON(w, tables) })
+	case export.FormatXLSX:
+		err = writeExportFile(output, func(w io.Writer) error { return export.WriteXLSX(w, tables) })
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		fmt.Printf("Exported %d %s to %s\n", len(table.Rows), table.Name, output)
+	}
+	return nil
+}
+
+func loadExportTable(db *storage.Database, entity string, filter storage.ExportFilter) (*export.Table, error) {
+	switch entity {
+	case export.EntityProfiles:
+		profiles, err := db.ExportProfiles(filter)
+		if err != nil {
+			return nil, err
+		}
+		return export.ProfilesTable(profiles), nil
+	case export.EntityConnections:
+		requests, err := db.ExportConnectionRequests(filter)
+		if err != nil {
+			return nil, err
+		}
+		return export.ConnectionsTable(requests), nil
+	default:
+		messages, err := db.ExportMessages(filter)
+		if err != nil {
+			return nil, err
+		}
+		return export.MessagesTable(messages), nil
+	}
+}
+
+// parseExportDate accepts a date or an RFC 3339 timestamp. A bare date used as
+// an upper bound covers the whole day.
+func parseExportDate(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected YYYY-MM-DD or RFC 3339, got %q", value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+func writeExportFile(path string, write func(w io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
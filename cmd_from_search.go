@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// searchFile is a file of search results, as saved by 'search users
+// --output' or printed by 'search users --json'
+type searchFile struct {
+	Results []struct {
+		ProfileURL string `json:"ProfileURL"`
+		URL        string `json:"URL"`
+		Profile    string `json:"profile_url"`
+	} `json:"results"`
+	Profiles []string `json:"profiles"`
+}
+
+// addFromSearchFlags adds the flags taking the profiles of a command from a
+// search results file and capping their number
+func addFromSearchFlags(cmd *cobra.Command, noun string) {
+	cmd.Flags().String("from-search", "", fmt.Sprintf("Also take the %s from a file saved by 'search users --output' or printed by 'search users --json'", noun))
+	cmd.Flags().Int("limit", 0, fmt.Sprintf("Use at most this many %s, after the other filters (0 for all)", noun))
+}
+
+// profilesFromSearch returns the profile URLs of the file named by
+// --from-search in result order, or nil without the flag
+func profilesFromSearch(cmd *cobra.Command) ([]string, error) {
+	path, _ := cmd.Flags().GetString("from-search")
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read search results: %w", err)
+	}
+	var file searchFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse search results %s: %w", path, err)
+	}
+
+	var urls []string
+	for _, result := range file.Results {
+		switch {
+		case result.ProfileURL != "":
+			urls = append(urls, result.ProfileURL)
+		case result.Profile != "":
+			urls = append(urls, result.Profile)
+		case result.URL != "":
+			urls = append(urls, result.URL)
+		}
+	}
+	if len(urls) == 0 {
+		urls = file.Profiles
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no profiles in search results %s", path)
+	}
+	return urls, nil
+}
+
+// applyLimit keeps the first profiles up to --limit
+func applyLimit(cmd *cobra.Command, profiles []string) []string {
+	if limit, _ := cmd.Flags().GetInt("limit"); limit > 0 && len(profiles) > limit {
+		return profiles[:limit]
+	}
+	return profiles
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/errs"
+	"linkedin-automation/logger"
+	"linkedin-automation/pause"
+	"linkedin-automation/personalize"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/storage"
+)
+
+func createInMailCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "inmail",
+		Short: "Send InMails to people outside the network",
+		Long: `Send InMails with a Premium or Sales Navigator account. InMail reaches
+members who are not connections; each one uses a credit. The remaining credit
+balance is read from the compose form and shown by the status command.`,
+		RunE: runInMail,
+	}
+
+	cmd.Flags().String("recipients", "", "Comma-separated list of profile URLs")
+	cmd.Flags().String("subject", "", "InMail subject (template variables allowed)")
+	cmd.Flags().String("message", "", "InMail body (template variables allowed)")
+	cmd.MarkFlagRequired("recipients")
+	cmd.MarkFlagRequired("subject")
+	cmd.MarkFlagRequired("message")
+	addReviewFlags(cmd)
+
+	return cmd
+}
+
+func runInMail(cmd *cobra.Command, args []string) error {
+	recipients, _ := cmd.Flags().GetString("recipients")
+	subject, _ := cmd.Flags().GetString("subject")
+	body, _ := cmd.Flags().GetString("message")
+
+	for _, text := range []string{subject, body} {
+		if err := personalize.Validate(text); err != nil {
+			return err
+		}
+	}
+
+	reviewer, err := reviewerFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	recipientList := profileurl.Dedupe(parseCommaSeparated(recipients))
+	if len(recipientList) == 0 {
+		return fmt.Errorf("no recipients provided")
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	recordOpenerPreview, err := checkAIOpenerPreview(cfg, db, reviewer != nil, subject, body)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+
+	browser, err := openBrowserSession(ctx, cfg, db)
+	if err != nil {
+		return err
+	}
+	defer browser.Close()
+
+	messageManager := newMessageManager(cfg, browser, db)
+	if reviewer != nil {
+		messageManager.SetReviewer(reviewer)
+	}
+	limiter := browser.RateLimiter()
+
+	sent, failed, rejected, blacklisted := 0, 0, 0, 0
+	var stopReason string
+	for i, recipientURL := range recipientList {
+		if err := limiter.WaitForPermission(ctx, ratelimit.ActionMessage); err != nil {
+			if !errors.Is(err, ratelimit.ErrLimitReached) {
+				return err
+			}
+			stopReason = err.Error()
+			break
+		}
+
+		result, err := messageManager.SendInMail(ctx, recipientURL, subject, body)
+		if err != nil {
+			if errors.Is(err, ratelimit.ErrLimitReached) {
+				stopReason = err.Error()
+				break
+			}
+			if errors.Is(err, errs.ErrSessionExpired) {
+				return err
+			}
+			logger.GetLogger().WithError(err).WithField("recipient", recipientURL).Error("Failed to send InMail")
+			failed++
+			continue
+		}
+
+		if result.Blacklisted {
+			blacklisted++
+			continue
+		}
+		if result.Rejected {
+			rejected++
+			continue
+		}
+
+		sent++
+		if result.DryRun {
+			fmt.Printf("Would send InMail to %s with the subject %q:\n", result.RecipientURL, result.Subject)
+			printIndented(result.Content)
+		}
+		if err := db.SaveMessage(&storage.Message{
+			RecipientURL: result.RecipientURL,
+			Content:      result.Content,
+			Subject:      result.Subject,
+			Type:         "inmail",
+			Status:       "sent",
+			SentAt:       result.SentAt,
+			DryRun:       result.DryRun,
+		}); err != nil {
+			logger.GetLogger().WithError(err).Warn("Failed to store InMail")
+		}
+
+		if i < len(recipientList)-1 {
+			if err := pause.Sleep(ctx, browser.Stealth().RandomDelay()); err != nil {
+				return err
+			}
+		}
+	}
+
+	recordOpenerPreview()
+
+	if dryRun {
+		fmt.Printf("Dry run: nothing was sent\n")
+	}
+	fmt.Printf("InMail sending completed!\n")
+	fmt.Printf("Total recipients: %d\n", len(recipientList))
+	fmt.Printf("Sent: %d\n", sent)
+	if reviewer != nil {
+		fmt.Printf("Skipped (in review): %d\n", rejected)
+	}
+	fmt.Printf("Skipped (blacklisted): %d\n", blacklisted)
+	fmt.Printf("Failed: %d\n", failed)
+	if credits, checkedAt, err := db.GetInMailCredits(); err == nil && credits >= 0 {
+		fmt.Printf("InMail credits remaining: %d (as of %s)\n", credits, checkedAt.Local().Format("2006-01-02 15:04"))
+	}
+	if stopReason != "" {
+		fmt.Printf("Stopped at limit: %s\n", stopReason)
+		fmt.Printf("Not attempted: %d\n", len(recipientList)-sent-failed-rejected-blacklisted)
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/logger"
+	"linkedin-automation/personalize"
+	"linkedin-automation/profileurl"
+)
+
+// csvProfileColumns are the header names the profile URL column may have
+var csvProfileColumns = map[string]bool{"profile_url": true, "url": true, "profile": true, "linkedin_url": true, "linkedin": true}
+
+// csvVariablePattern is what a column name must look like to be a template variable
+var csvVariablePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// csvInput is a CSV file of profiles, one per row, whose other columns are
+// template variables of that profile
+type csvInput struct {
+	path      string
+	Profiles  []string                     // Profile URLs in row order
+	Columns   []string                     // Variable names, from the header
+	Variables map[string]map[string]string // Each profile's variables, by canonical profile URL
+}
+
+// addInputCSVFlag adds the flag taking profiles and their template variables
+// from a CSV file
+func addInputCSVFlag(cmd *cobra.Command, noun string) {
+	cmd.Flags().String("input-csv", "", fmt.Sprintf("Also take the %s from a CSV file with a profile_url column; its other columns become template variables of each row", noun))
+}
+
+// inputCSV reads the file named by --input-csv, or returns nil without the flag
+func inputCSV(cmd *cobra.Command) (*csvInput, error) {
+	path, _ := cmd.Flags().GetString("input-csv")
+	if path == "" {
+		return nil, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	input, err := parseInputCSV(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	input.path = path
+	return input, nil
+}
+
+// parseInputCSV reads a header row naming the columns, then a profile per row
+func parseInputCSV(r io.Reader) (*csvInput, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("the file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	input := &csvInput{Variables: make(map[string]map[string]string)}
+	profileColumn := -1
+	names := make([]string, len(header))
+	for i, column := range header {
+		name := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))), " ", "_")
+		if profileColumn < 0 && csvProfileColumns[name] {
+			profileColumn = i
+			continue
+		}
+		if !csvVariablePattern.MatchString(name) {
+			return nil, fmt.Errorf("column %q cannot be a template variable; use letters, digits and underscores", column)
+		}
+		names[i] = name
+		input.Columns = append(input.Columns, name)
+	}
+	if profileColumn < 0 {
+		return nil, fmt.Errorf("no profile_url column")
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		profileURL := strings.TrimSpace(record[profileColumn])
+		if profileURL == "" {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("line %d has no profile URL", line)
+		}
+		variables := make(map[string]string, len(input.Columns))
+		for i, value := range record {
+			if value = strings.TrimSpace(value); names[i] != "" && value != "" {
+				variables[names[i]] = value
+			}
+		}
+		key := profileurl.Canonicalize(profileURL)
+		if _, seen := input.Variables[key]; !seen {
+			input.Profiles = append(input.Profiles, profileURL)
+		}
+		input.Variables[key] = variables
+	}
+	if len(input.Profiles) == 0 {
+		return nil, fmt.Errorf("no profiles")
+	}
+	return input, nil
+}
+
+// Of returns the variables of a profile, or nil if the file has none for it
+func (in *csvInput) Of(profileURL string) map[string]string {
+	if in == nil {
+		return nil
+	}
+	return in.Variables[profileurl.Canonicalize(profileURL)]
+}
+
+// For returns the variables of the given profiles, for batch options
+func (in *csvInput) For(profiles []string) map[string]map[string]string {
+	if in == nil {
+		return nil
+	}
+	variables := make(map[string]map[string]string, len(profiles))
+	for _, profileURL := range profiles {
+		if v := in.Of(profileURL); v != nil {
+			variables[profileURL] = v
+		}
+	}
+	return variables
+}
+
+// Check returns an error if a template variable is neither filled from
+// profile data nor a column of the file, and warns about the profiles whose
+// row leaves one of its own variables empty
+func (in *csvInput) Check(profiles []string, variables []string) error {
+	if in == nil {
+		return nil
+	}
+
+	columns := make(map[string]bool, len(in.Columns))
+	for _, column := range in.Columns {
+		columns[column] = true
+	}
+	for _, name := range variables {
+		// Empty cells of these fall back to the profile's own data
+		if personalize.IsProfileVariable(name) {
+			continue
+		}
+		if !columns[name] {
+			return fmt.Errorf("template variable %q is neither a profile field nor a column of %s", name, in.path)
+		}
+
+		empty := 0
+		for _, profileURL := range profiles {
+			if v := in.Of(profileURL); v != nil && v[name] == "" {
+				empty++
+			}
+		}
+		if empty > 0 {
+			logger.GetLogger().WithFields(logrus.Fields{
+				"variable": name,
+				"rows":     empty,
+			}).Warn("Rows of the CSV file leave a template variable empty")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/invitations"
+	"linkedin-automation/profileurl"
+)
+
+func createInvitationsCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "invitations",
+		Short: "Review and answer incoming connection invitations",
+		Long: `List the connection invitations the account has received and accept or
+ignore them, one by one or with the rules under invitations.rules in the
+configuration. Rules are tried in order and the first match decides; an
+invitation no rule matches is left pending. Every answered invitation is
+recorded in the invitation_actions table.`,
+	}
+
+	cmd.AddCommand(createInvitationsListCmd())
+	cmd.AddCommand(createInvitationsProcessCmd())
+	cmd.AddCommand(createInvitationsRespondCmd(invitations.ActionAccept, "Accept the pending invitations from the given profiles"))
+	cmd.AddCommand(createInvitationsRespondCmd(invitations.ActionIgnore, "Ignore the pending invitations from the given profiles"))
+	cmd.AddCommand(createInvitationsHistoryCmd())
+
+	return cmd
+}
+
+func createInvitationsListCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "list",
+		Short: "List pending invitations and what the rules would do with each",
+		RunE:  runInvitationsList,
+	}
+
+	cmd.Flags().Int("max-results", 0, "Stop after this many invitations (0 for all)")
+
+	return cmd
+}
+
+func createInvitationsProcessCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "process",
+		Short: "Accept or ignore pending invitations using the configured rules",
+		RunE:  runInvitationsProcess,
+	}
+
+	cmd.Flags().Int("max-results", 0, "Stop after this many invitations (0 for all)")
+
+	return cmd
+}
+
+func createInvitationsRespondCmd(action, short string) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   action,
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInvitationsRespond(cmd, action)
+		},
+	}
+
+	cmd.Flags().String("profiles", "", "Comma-separated list of profile URLs")
+	cmd.MarkFlagRequired("profiles")
+
+	return cmd
+}
+
+func createInvitationsHistoryCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "history",
+		Short: "Show recently answered invitations",
+		RunE:  runInvitationsHistory,
+	}
+
+	cmd.Flags().Int("limit", 50, "Number of invitations to show")
+
+	return cmd
+}
+
+func runInvitationsList(cmd *cobra.Command, args []string) error {
+	maxResults, _ := cmd.Flags().GetInt("max-results")
+	if maxResults < 0 {
+		return fmt.Errorf("--max-results must not be negative")
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	rules, err := invitations.RulesFromConfig(cfg.Invitations.Rules)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+
+	browser, err := openBrowserSession(ctx, cfg, db)
+	if err != nil {
+		return err
+	}
+	defer browser.Close()
+
+	pending, err := newInvitationManager(cfg, browser, db).List(ctx, maxResults)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Pending Invitations\n")
+	fmt.Printf("===================\n")
+	fmt.Printf("Total: %d\n\n", len(pending))
+	for _, invitation := range pending {
+		printInvitation(invitation)
+		if rule := invitations.Match(rules, invitation); rule != nil {
+			fmt.Printf("  Rule: %s\n", rule)
+		} else {
+			fmt.Printf("  Rule: none (left pending)\n")
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func runInvitationsProcess(cmd *cobra.Command, args []string) error {
+	maxResults, _ := cmd.Flags().GetInt("max-results")
+	if maxResults < 0 {
+		return fmt.Errorf("--max-results must not be negative")
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	rules, err := invitations.RulesFromConfig(cfg.Invitations.Rules)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return fmt.Errorf("no invitation rules configured under invitations.rules")
+	}
+
+	ctx := cmd.Context()
+
+	browser, err := openBrowserSession(ctx, cfg, db)
+	if err != nil {
+		return err
+	}
+	defer browser.Close()
+
+	results, err := newInvitationManager(cfg, browser, db).Process(ctx, rules, maxResults)
+	if err != nil && len(results) == 0 {
+		return err
+	}
+
+	accepted, ignored, pending, failed := 0, 0, 0, 0
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			failed++
+			fmt.Printf("Failed to %s %s: %v\n", result.Action, result.Invitation.ProfileURL, result.Err)
+		case result.Action == invitations.ActionAccept:
+			accepted++
+		case result.Action == invitations.ActionIgnore:
+			ignored++
+		default:
+			pending++
+		}
+		if result.DryRun && result.Err == nil && result.Action != "" {
+			fmt.Printf("Would %s %s (%s)\n", result.Action, result.Invitation.ProfileURL, result.Rule)
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("Dry run: no invitation was answered\n")
+	}
+	fmt.Printf("Processed %d invitations: %d accepted, %d ignored, %d left pending, %d failed\n",
+		len(results), accepted, ignored, pending, failed)
+	return err
+}
+
+func runInvitationsRespond(cmd *cobra.Command, action string) error {
+	profiles, _ := cmd.Flags().GetString("profiles")
+
+	profileList := profileurl.Dedupe(parseCommaSeparated(profiles))
+	if len(profileList) == 0 {
+		return fmt.Errorf("no profiles provided")
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := cmd.Context()
+
+	browser, err := openBrowserSession(ctx, cfg, db)
+	if err != nil {
+		return err
+	}
+	defer browser.Close()
+
+	manager := newInvitationManager(cfg, browser, db)
+	pending, err := manager.List(ctx, 0)
+	if err != nil {
+		return err
+	}
+	byURL := make(map[string]*invitations.Invitation, len(pending))
+	for _, invitation := range pending {
+		byURL[invitation.ProfileURL] = invitation
+	}
+
+	answered := 0
+	for _, profileURL := range profileList {
+		invitation, ok := byURL[profileurl.Canonicalize(profileURL)]
+		if !ok {
+			fmt.Printf("No pending invitation from %s\n", profileURL)
+			continue
+		}
+		if err := manager.Respond(ctx, invitation, action, ""); err != nil {
+			fmt.Printf("Failed to %s %s: %v\n", action, profileURL, err)
+			continue
+		}
+		answered++
+	}
+
+	if dryRun {
+		fmt.Printf("Dry run: would %s %d of %d invitations\n", action, answered, len(profileList))
+		return nil
+	}
+	fmt.Printf("Answered %d of %d invitations with %s\n", answered, len(profileList), action)
+	return nil
+}
+
+func runInvitationsHistory(cmd *cobra.Command, args []string) error {
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	actions, err := db.ListInvitationActions(limit)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Answered Invitations\n")
+	fmt.Printf("====================\n")
+	fmt.Printf("Total: %d\n\n", len(actions))
+	for _, a := range actions {
+		line := fmt.Sprintf("%s  %-6s  %s", a.ActedAt.Local().Format("2006-01-02 15:04"), a.Action, a.ProfileURL)
+		if a.Name != "" {
+			line += " (" + a.Name + ")"
+		}
+		if a.Rule != "" {
+			line += "  [" + a.Rule + "]"
+		}
+		if a.DryRun {
+			line += "  (dry run)"
+		}
+		fmt.Println(line)
+	}
+
+	return nil
+}
+
+func printInvitation(invitation *invitations.Invitation) {
+	fmt.Printf("%s\n", invitation.Name)
+	if invitation.Headline != "" {
+		fmt.Printf("  Headline: %s\n", invitation.Headline)
+	}
+	fmt.Printf("  Profile: %s\n", invitation.ProfileURL)
+	fmt.Printf("  Mutual connections: %d\n", invitation.Mutual)
+	if !invitation.HasPhoto {
+		fmt.Printf("  No profile photo\n")
+	}
+	if invitation.Message != "" {
+		fmt.Printf("  Message: %s\n", invitation.Message)
+	}
+}
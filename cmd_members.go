@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/logger"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/search"
+)
+
+func createSearchGroupMembersCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "group-members",
+		Short: "List the members of a LinkedIn group",
+		Long: `Page through the member list of a group the account has joined.
+
+Members are stored as profiles with search query "group:<id>", so they can be
+used like search results. Each page of members counts against the search rate
+limit.`,
+		RunE: runSearchGroupMembers,
+	}
+
+	cmd.Flags().String("group", "", "Group URL (e.g. https://www.linkedin.com/groups/12345/)")
+	addMemberListingFlags(cmd, "members")
+	cmd.MarkFlagRequired("group")
+
+	return cmd
+}
+
+func createSearchEventAttendeesCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "event-attendees",
+		Short: "List the attendees of a LinkedIn event",
+		Long: `Page through the attendee list of an event the account attends.
+
+Attendees are stored as profiles with search query "event:<id>", so they can be
+used like search results. Each page of attendees counts against the search
+rate limit.`,
+		RunE: runSearchEventAttendees,
+	}
+
+	cmd.Flags().String("event", "", "Event URL (e.g. https://www.linkedin.com/events/7012345678901234567/)")
+	addMemberListingFlags(cmd, "attendees")
+	cmd.MarkFlagRequired("event")
+
+	return cmd
+}
+
+func createSearchSimilarCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "similar",
+		Short: "Find prospects similar to a profile",
+		Long: `Visit a seed profile and collect the profiles in its "People also viewed"
+and "People you may know" modules.
+
+Profiles are stored with search query "similar:<slug>" and, the first time
+they are found, with the module and seed as their source, e.g.
+"also-viewed:<seed URL>". The visit counts against the profile visit rate
+limit.`,
+		RunE: runSearchSimilar,
+	}
+
+	cmd.Flags().String("profile", "", "Seed profile URL")
+	cmd.Flags().String("sources", "", fmt.Sprintf("Modules to harvest, comma-separated (%s; default all)", strings.Join(search.SimilarSources, ",")))
+	addMemberListingFlags(cmd, "profiles")
+	cmd.MarkFlagRequired("profile")
+
+	return cmd
+}
+
+func addMemberListingFlags(cmd *cobra.Command, noun string) {
+	cmd.Flags().Int("max-results", 100, "Maximum number of "+noun)
+	cmd.Flags().String("output", "", "Output file path")
+	cmd.Flags().Bool("exclude-contacted", false, "Drop profiles already sent a connection request or message")
+}
+
+func runSearchGroupMembers(cmd *cobra.Command, args []string) error {
+	group, _ := cmd.Flags().GetString("group")
+	if search.GroupID(group) == "" {
+		return fmt.Errorf("--group must be a group URL")
+	}
+
+	return runMemberListing(cmd, "Group member extraction", "members",
+		func(ctx context.Context, manager *search.SearchManager, maxResults int) (*search.SearchSession, error) {
+			return manager.GetGroupMembers(ctx, group, maxResults)
+		})
+}
+
+func runSearchEventAttendees(cmd *cobra.Command, args []string) error {
+	event, _ := cmd.Flags().GetString("event")
+	if search.EventID(event) == "" {
+		return fmt.Errorf("--event must be an event URL")
+	}
+
+	return runMemberListing(cmd, "Event attendee extraction", "attendees",
+		func(ctx context.Context, manager *search.SearchManager, maxResults int) (*search.SearchSession, error) {
+			return manager.GetEventAttendees(ctx, event, maxResults)
+		})
+}
+
+func runSearchSimilar(cmd *cobra.Command, args []string) error {
+	profile, _ := cmd.Flags().GetString("profile")
+	sources, _ := cmd.Flags().GetString("sources")
+	if profileurl.Slug(profile) == "" {
+		return fmt.Errorf("--profile must be a profile URL")
+	}
+
+	return runMemberListing(cmd, "Similar profile extraction", "profiles",
+		func(ctx context.Context, manager *search.SearchManager, maxResults int) (*search.SearchSession, error) {
+			return manager.GetSimilarProfiles(ctx, profile, parseCommaSeparated(sources), maxResults)
+		})
+}
+
+// runMemberListing runs a group, event or recommendation listing and stores and reports its
+// results the same way as a people search
+func runMemberListing(cmd *cobra.Command, label, noun string,
+	list func(ctx context.Context, manager *search.SearchManager, maxResults int) (*search.SearchSession, error)) error {
+	maxResults, _ := cmd.Flags().GetInt("max-results")
+	output, _ := cmd.Flags().GetString("output")
+	excludeContacted, _ := cmd.Flags().GetBool("exclude-contacted")
+
+	if maxResults < 1 {
+		return fmt.Errorf("--max-results must be at least 1")
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := cmd.Context()
+
+	browser, err := openBrowserSession(ctx, cfg, db)
+	if err != nil {
+		return err
+	}
+	defer browser.Close()
+
+	session, err := list(ctx, newSearchManager(cfg, browser, db), maxResults)
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", label, err)
+	}
+
+	contactedCount, err := newClient(cfg, db).RecordSearch(session, excludeContacted)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s completed!\n", label)
+	fmt.Printf("Found %d %s in %v\n", len(session.Results), noun, session.Duration)
+	if excludeContacted {
+		fmt.Printf("Excluded (already contacted): %d\n", contactedCount)
+	} else if contactedCount > 0 {
+		fmt.Printf("Already contacted: %d\n", contactedCount)
+	}
+	if session.StoppedAtLimit {
+		fmt.Printf("Stopped at search rate limit; results may be incomplete\n")
+	}
+
+	if output != "" {
+		if err := saveSearchResults(session, output); err != nil {
+			logger.GetLogger().WithError(err).Error("Failed to save results")
+		} else {
+			fmt.Printf("Results saved to: %s\n", output)
+		}
+	} else {
+		for i, result := range session.Results {
+			fmt.Printf("%d. %s - %s\n", i+1, result.Name, result.Title)
+			fmt.Printf("   %s\n", result.ProfileURL)
+		}
+	}
+
+	return searchExit(session)
+}
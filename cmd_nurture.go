@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/client"
+	"linkedin-automation/logger"
+	"linkedin-automation/message"
+	"linkedin-automation/nurture"
+	"linkedin-automation/personalize"
+	"linkedin-automation/templates"
+)
+
+// defaultNurtureTemplates are the built-in message templates for each catch-up kind
+var defaultNurtureTemplates = map[string]string{
+	nurture.KindJobChange:       "congrats_job_change",
+	nurture.KindWorkAnniversary: "congrats_work_anniversary",
+	nurture.KindBirthday:        "congrats_birthday",
+}
+
+func createNurtureCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "nurture",
+		Short: "Congratulate connections on job changes, work anniversaries and birthdays",
+		Long: `Read the "Catch up" tab of My Network for job changes, work anniversaries
+and birthdays among 1st-degree connections and send each a templated
+congratulation message. Messages go through the message rate limits and are
+recorded with type "nurture" in the messages table.`,
+	}
+
+	cmd.AddCommand(createNurtureListCmd())
+	cmd.AddCommand(createNurtureSendCmd())
+
+	return cmd
+}
+
+func createNurtureListCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "list",
+		Short: "List catch-up events without sending anything",
+		RunE:  runNurtureList,
+	}
+
+	addNurtureFlags(cmd)
+
+	return cmd
+}
+
+func createNurtureSendCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "send",
+		Short: "Send congratulation messages for catch-up events",
+		Long: `Send a congratulation message for each catch-up event. A connection who was
+sent the same template within --cooldown is skipped, so re-running the command
+while an event is still listed does not message them twice.`,
+		RunE: runNurtureSend,
+	}
+
+	addNurtureFlags(cmd)
+	cmd.Flags().String("job-change-template", defaultNurtureTemplates[nurture.KindJobChange], "Message template for job changes")
+	cmd.Flags().String("anniversary-template", defaultNurtureTemplates[nurture.KindWorkAnniversary], "Message template for work anniversaries")
+	cmd.Flags().String("birthday-template", defaultNurtureTemplates[nurture.KindBirthday], "Message template for birthdays")
+	cmd.Flags().Duration("cooldown", 30*24*time.Hour, "Skip connections sent the same template within this period")
+
+	return cmd
+}
+
+func addNurtureFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("kinds", nil, "Event kinds: job-change, work-anniversary, birthday (default all)")
+	cmd.Flags().Int("max-results", 20, "Maximum events per kind (0 for all)")
+}
+
+func nurtureFlags(cmd *cobra.Command) ([]string, int, error) {
+	kinds, _ := cmd.Flags().GetStringSlice("kinds")
+	maxResults, _ := cmd.Flags().GetInt("max-results")
+
+	if maxResults < 0 {
+		return nil, 0, fmt.Errorf("--max-results must not be negative")
+	}
+	kinds, err := nurture.ParseKinds(kinds)
+	if err != nil {
+		return nil, 0, err
+	}
+	return kinds, maxResults, nil
+}
+
+func runNurtureList(cmd *cobra.Command, args []string) error {
+	kinds, maxResults, err := nurtureFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := cmd.Context()
+
+	browser, err := openBrowserSession(ctx, cfg, db)
+	if err != nil {
+		return err
+	}
+	defer browser.Close()
+
+	events, err := newNurtureManager(cfg, browser, db).CatchUp(ctx, kinds, maxResults)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Catch-up Events\n")
+	fmt.Printf("===============\n")
+	fmt.Printf("Total: %d\n\n", len(events))
+	for _, event := range events {
+		fmt.Printf("%-16s  %s (%s)\n", event.Kind, event.Name, event.ProfileURL)
+		if event.Detail != "" {
+			fmt.Printf("                  %s\n", event.Detail)
+		}
+	}
+
+	return nil
+}
+
+func runNurtureSend(cmd *cobra.Command, args []string) error {
+	kinds, maxResults, err := nurtureFlags(cmd)
+	if err != nil {
+		return err
+	}
+	cooldown, _ := cmd.Flags().GetDuration("cooldown")
+
+	templateFlags := map[string]string{
+		nurture.KindJobChange:       "job-change-template",
+		nurture.KindWorkAnniversary: "anniversary-template",
+		nurture.KindBirthday:        "birthday-template",
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Load every template up front so a misspelled name fails before the browser starts
+	templateManager := templates.NewManager(db, logger.GetLogger())
+	contents := make(map[string]string, len(kinds))
+	names := make(map[string]string, len(kinds))
+	for _, kind := range kinds {
+		name, _ := cmd.Flags().GetString(templateFlags[kind])
+		t, err := templateManager.Get(templates.KindMessage, name)
+		if err != nil {
+			return fmt.Errorf("failed to load %s template: %w", kind, err)
+		}
+		if err := personalize.Validate(t.Content); err != nil {
+			return fmt.Errorf("%s template: %w", kind, err)
+		}
+		contents[kind], names[kind] = t.Content, name
+	}
+	texts := make([]string, 0, len(contents))
+	for _, content := range contents {
+		texts = append(texts, content)
+	}
+	recordOpenerPreview, err := checkAIOpenerPreview(cfg, db, false, texts...)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+
+	browser, err := openBrowserSession(ctx, cfg, db)
+	if err != nil {
+		return err
+	}
+	defer browser.Close()
+
+	browser.WarmUp(ctx)
+
+	events, err := newNurtureManager(cfg, browser, db).CatchUp(ctx, kinds, maxResults)
+	if err != nil {
+		return err
+	}
+
+	since := time.Now().Add(-cooldown)
+	recipients := make(map[string][]string, len(kinds))
+	recent := 0
+	for _, event := range events {
+		sent, err := db.HasMessageSince(event.ProfileURL, nurture.MessageType, names[event.Kind], since)
+		if err != nil {
+			return err
+		}
+		if sent {
+			recent++
+			continue
+		}
+		recipients[event.Kind] = append(recipients[event.Kind], event.ProfileURL)
+	}
+
+	messageManager := newMessageManager(cfg, browser, db)
+
+	sent, failed := 0, 0
+	var results []*message.MessageResult
+	var stopErr error
+	for _, kind := range kinds {
+		if len(recipients[kind]) == 0 {
+			continue
+		}
+
+		batch, err := messageManager.BatchSendMessages(ctx, recipients[kind], contents[kind], message.BatchOptions{
+			BatchID:        client.DeriveBatchID("nurture-"+kind, recipients[kind]),
+			IncludeReplied: true,
+		})
+		if batch != nil {
+			if err := client.RecordMessages(db, nurture.MessageType, names[kind], batch.Results); err != nil {
+				logger.GetLogger().WithError(err).Warn("Failed to store messages")
+			}
+			results = append(results, batch.Results...)
+		}
+		if err != nil {
+			return fmt.Errorf("nurture messaging failed: %w", err)
+		}
+
+		for _, result := range batch.Results {
+			if result.Success {
+				sent++
+			} else if !result.Skipped {
+				failed++
+			}
+		}
+		if batch.StoppedAtLimit {
+			fmt.Printf("Stopped at limit: %s\n", batch.StopReason)
+			stopErr = batch.StopErr
+			break
+		}
+		if batch.Interrupted {
+			fmt.Printf("Interrupted; run again to congratulate the rest\n")
+			break
+		}
+	}
+
+	reportDryRunMessages(results)
+	recordOpenerPreview()
+
+	fmt.Printf("Catch-up events: %d\n", len(events))
+	fmt.Printf("Congratulated: %d\n", sent)
+	fmt.Printf("Skipped (messaged within %s): %d\n", cooldown, recent)
+	fmt.Printf("Failed: %d\n", failed)
+	return batchExit("messages", failed, len(results), stopErr)
+}
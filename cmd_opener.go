@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"linkedin-automation/config"
+	"linkedin-automation/logger"
+	"linkedin-automation/personalize"
+	"linkedin-automation/queue"
+	"linkedin-automation/storage"
+)
+
+// errAIOpenerNotPreviewed is returned for {{ai_opener}} texts sent unattended
+// before any preview with the current AI settings
+var errAIOpenerNotPreviewed = errors.New("{{ai_opener}} has not been previewed with the current ai settings")
+
+// checkAIOpenerPreview makes sure texts inserting {{ai_opener}} are only sent
+// unattended once the lines written with the current AI settings have been
+// seen: the first run must be a dry run or use --review. It returns a function
+// recording the preview, to call once a previewing run went through.
+func checkAIOpenerPreview(cfg *config.Config, db *storage.Database, reviewing bool, texts ...string) (func(), error) {
+	uses := false
+	for _, text := range texts {
+		if personalize.UsesOpener(text) {
+			uses = true
+		}
+	}
+	if !uses {
+		return func() {}, nil
+	}
+
+	writer := newClient(cfg, db).OpenerWriter()
+	if writer == nil {
+		logger.GetLogger().Warn("{{ai_opener}} is used but no ai.provider is configured; it will be left empty")
+		return func() {}, nil
+	}
+	key := writer.Key()
+
+	if dryRun || reviewing {
+		mode := "review"
+		if dryRun {
+			mode = "dry-run"
+		}
+		return func() {
+			if err := db.SaveAIOpenerPreview(key, mode); err != nil {
+				logger.GetLogger().WithError(err).Warn("Failed to record AI opener preview")
+			}
+		}, nil
+	}
+
+	previewed, err := db.AIOpenerPreviewed(key)
+	if err != nil {
+		return nil, err
+	}
+	if !previewed {
+		return nil, fmt.Errorf("%w; run once with --dry-run or --review to check the lines it writes", errAIOpenerNotPreviewed)
+	}
+	return func() {}, nil
+}
+
+// checkQueuedAIOpener applies checkAIOpenerPreview to a queued task's texts,
+// whether the task came from --queue, a sequence, a reminder or nurture. A
+// task sent before the preview is held rather than failed, and 'queue run
+// --dry-run' previews it.
+func checkQueuedAIOpener(cfg *config.Config, db *storage.Database, texts ...string) (func(), error) {
+	record, err := checkAIOpenerPreview(cfg, db, false, texts...)
+	if errors.Is(err, errAIOpenerNotPreviewed) {
+		return nil, fmt.Errorf("%w: %s; run 'queue run --dry-run' to check the lines it writes", queue.ErrHeld, errAIOpenerNotPreviewed)
+	}
+	return record, err
+}
@@ -0,0 +1,622 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/analytics"
+	"linkedin-automation/browse"
+	"linkedin-automation/client"
+	"linkedin-automation/config"
+	"linkedin-automation/connect"
+	"linkedin-automation/errs"
+	"linkedin-automation/logger"
+	"linkedin-automation/message"
+	"linkedin-automation/queue"
+	"linkedin-automation/sequence"
+	"linkedin-automation/storage"
+	"linkedin-automation/web"
+)
+
+func createQueueCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "queue",
+		Short: "Manage queued tasks",
+		Long: `List, retry, cancel and run connect, message and search tasks queued with --queue,
+and the steps of drip sequences.`,
+	}
+
+	cmd.AddCommand(createQueueListCmd())
+	cmd.AddCommand(createQueueRetryCmd())
+	cmd.AddCommand(createQueueCancelCmd())
+	cmd.AddCommand(createQueueRunCmd())
+
+	return cmd
+}
+
+func createQueueListCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "list",
+		Short: "List queued tasks",
+		RunE:  runQueueList,
+	}
+
+	cmd.Flags().String("status", "", "Only list tasks with this status (pending, running, done, failed, cancelled)")
+	cmd.Flags().Bool("upcoming", false, "Only list pending tasks scheduled for later, soonest first")
+
+	return cmd
+}
+
+func createQueueRetryCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "retry",
+		Short: "Re-queue a failed or cancelled task",
+		RunE:  runQueueRetry,
+	}
+
+	cmd.Flags().Int("id", 0, "Task ID")
+	cmd.MarkFlagRequired("id")
+
+	return cmd
+}
+
+func createQueueCancelCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel a pending task",
+		RunE:  runQueueCancel,
+	}
+
+	cmd.Flags().Int("id", 0, "Task ID")
+	cmd.MarkFlagRequired("id")
+
+	return cmd
+}
+
+func createQueueRunCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "run",
+		Short: "Run due tasks under the configured rate limits",
+		Long: `Log in once and drain due tasks in priority order. Tasks that hit a rate limit are deferred rather than failed.
+
+With --listen, a web dashboard with campaigns, prospects and activity charts is
+served on the given address while the queue runs. Campaigns can be paused and
+failed tasks retried from it. Listening beyond localhost requires web.token.
+
+With --follow, edits of the config file, the selectors file and the sequences
+directory are picked up without a restart: limits, rate limits, the session
+schedule, sequence sync settings and logging apply from the next action, and a
+diff of the changed settings is logged. Templates live in the database
+and are always read fresh.
+
+With --follow and a retention period configured, old message text is cleared
+and inactive people are anonymized at start and every retention.interval.
+With reminders.interval set, connections who accepted and never replied are
+queued their nudges the same way, as 'reminders queue' does.`,
+		RunE: runQueueRun,
+	}
+
+	cmd.Flags().Bool("follow", false, "Keep running and poll for newly due tasks")
+	cmd.Flags().Int("max-attempts", 3, "Attempts before a failing task is marked failed")
+	cmd.Flags().Duration("limit-backoff", 30*time.Minute, "How long to defer a task that hit a rate limit")
+	cmd.Flags().Int("browse-every", 0, "Browse the feed after every N tasks (0 disables)")
+	cmd.Flags().Duration("browse-duration", 3*time.Minute, "How long each interleaved feed browse lasts")
+	cmd.Flags().String("listen", "", "Serve the web dashboard on this address, e.g. localhost:8080")
+	cmd.Flags().Duration("reload-interval", 5*time.Second, "How often --follow checks the config, selectors and sequences for edits (0 disables)")
+
+	return cmd
+}
+
+// addQueueFlags adds the flags that let a command enqueue its work instead of running it
+func addQueueFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("queue", false, "Enqueue the work for 'queue run' instead of executing it now")
+	cmd.Flags().Int("priority", 0, "Queue priority (higher runs first)")
+	cmd.Flags().String("scheduled-at", "", "Earliest time a queued task may run (RFC 3339, e.g. 2024-05-01T09:00:00Z)")
+	cmd.Flags().String("at", "", `Queue the work to run at this local time, e.g. "2024-07-01 09:30"; implies --queue`)
+}
+
+// atLayouts are the local time formats --at accepts
+var atLayouts = []string{"2006-01-02 15:04", "2006-01-02 15:04:05", "2006-01-02T15:04", "2006-01-02T15:04:05"}
+
+// queueRequested reports whether the command is to enqueue its work, with
+// --queue or --at
+func queueRequested(cmd *cobra.Command) bool {
+	enqueue, _ := cmd.Flags().GetBool("queue")
+	at, _ := cmd.Flags().GetString("at")
+	return enqueue || at != ""
+}
+
+// queueOptionsFromFlags reads the queue flags, reporting whether --queue or --at was set
+func queueOptionsFromFlags(cmd *cobra.Command) (queue.Options, bool, error) {
+	enqueue := queueRequested(cmd)
+	priority, _ := cmd.Flags().GetInt("priority")
+	scheduledAt, _ := cmd.Flags().GetString("scheduled-at")
+	at, _ := cmd.Flags().GetString("at")
+
+	opts := queue.Options{Priority: priority}
+	if enqueue && dryRun {
+		return opts, false, fmt.Errorf("--dry-run cannot be combined with --queue; use 'queue run --dry-run' to try queued tasks")
+	}
+	if scheduledAt != "" && at != "" {
+		return opts, false, fmt.Errorf("--at cannot be combined with --scheduled-at")
+	}
+	if scheduledAt != "" {
+		if !enqueue {
+			return opts, false, fmt.Errorf("--scheduled-at requires --queue")
+		}
+		at, err := time.Parse(time.RFC3339, scheduledAt)
+		if err != nil {
+			return opts, false, fmt.Errorf("invalid --scheduled-at: %w", err)
+		}
+		opts.ScheduledAt = at
+	}
+	if at != "" {
+		scheduled, err := parseAt(at)
+		if err != nil {
+			return opts, false, err
+		}
+		if !scheduled.After(time.Now()) {
+			return opts, false, fmt.Errorf("--at %s is in the past", at)
+		}
+		opts.ScheduledAt = scheduled
+	}
+
+	return opts, enqueue, nil
+}
+
+// parseAt reads an --at time in the local timezone, or in the one it names
+func parseAt(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at, nil
+	}
+	for _, layout := range atLayouts {
+		if at, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return at, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid --at %q, expected e.g. \"2024-07-01 09:30\"", value)
+}
+
+// openDatabase loads the config and opens the database
+func openDatabase() (*config.Config, *storage.Database, error) {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := setupLogger(cfg.Logging); err != nil {
+		return nil, nil, fmt.Errorf("failed to setup logger: %w", err)
+	}
+
+	db, err := openStorage(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+
+	return cfg, db, nil
+}
+
+func runQueueList(cmd *cobra.Command, args []string) error {
+	status, _ := cmd.Flags().GetString("status")
+	upcoming, _ := cmd.Flags().GetBool("upcoming")
+
+	if upcoming && status != "" {
+		return fmt.Errorf("--upcoming cannot be combined with --status")
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if upcoming {
+		return listUpcomingTasks(cfg, db)
+	}
+
+	tasks, err := db.ListTasks(status)
+	if err != nil {
+		return err
+	}
+
+	if len(tasks) == 0 {
+		fmt.Printf("No tasks\n")
+		return nil
+	}
+
+	fmt.Printf("%-6s %-8s %-10s %-8s %-8s %-20s %s\n", "ID", "KIND", "STATUS", "PRIORITY", "ATTEMPTS", "SCHEDULED", "PAYLOAD")
+	for _, task := range tasks {
+		fmt.Printf("%-6d %-8s %-10s %-8d %-8d %-20s %s\n", task.ID, task.Kind, task.Status, task.Priority,
+			task.Attempts, task.ScheduledAt.Local().Format("2006-01-02 15:04:05"), task.Payload)
+		if task.LastError != "" && task.Status != storage.TaskDone {
+			fmt.Printf("       last error: %s\n", task.LastError)
+		}
+	}
+
+	return nil
+}
+
+// listUpcomingTasks prints the tasks scheduled for later in the order they
+// become due, and when the prospect's working hours hold them back further or
+// never fall within the business hours at all
+func listUpcomingTasks(cfg *config.Config, db *storage.Database) error {
+	now := time.Now()
+	tasks, err := db.ListUpcomingTasks(now)
+	if err != nil {
+		return err
+	}
+
+	if len(tasks) == 0 {
+		fmt.Printf("No upcoming tasks\n")
+		return nil
+	}
+
+	hours := newClient(cfg, db).ProspectHours()
+	window := newClient(cfg, db).OperatorHours()
+	fmt.Printf("%-6s %-8s %-20s %-12s %-8s %s\n", "ID", "KIND", "SCHEDULED", "IN", "PRIORITY", "PROSPECT")
+	for _, task := range tasks {
+		prospect := queue.Prospect(task)
+		fmt.Printf("%-6d %-8s %-20s %-12s %-8d %s\n", task.ID, task.Kind, task.ScheduledAt.Local().Format("2006-01-02 15:04:05"),
+			analytics.FormatDuration(task.ScheduledAt.Sub(now)), task.Priority, prospect)
+		if window != nil {
+			if at := window.Next(task.ScheduledAt); at.After(task.ScheduledAt) {
+				fmt.Printf("       waits for your business hours until %s\n", at.Local().Format("2006-01-02 15:04"))
+			}
+		}
+		if hours == nil || prospect == "" {
+			continue
+		}
+		if window != nil {
+			if _, _, err := queue.NextRun(window, hours, prospect, task.ScheduledAt); errors.Is(err, queue.ErrNoOverlap) {
+				fmt.Printf("       unschedulable: %v, the queue will fail it\n", err)
+				continue
+			}
+		}
+		if at, err := hours.Next(prospect, task.ScheduledAt); err == nil && at.After(task.ScheduledAt) {
+			fmt.Printf("       waits for the prospect's working hours until %s\n", at.Local().Format("2006-01-02 15:04"))
+		}
+	}
+
+	return nil
+}
+
+func runQueueRetry(cmd *cobra.Command, args []string) error {
+	id, _ := cmd.Flags().GetInt("id")
+
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	retried, err := db.RetryTask(id)
+	if err != nil {
+		return err
+	}
+	if !retried {
+		return fmt.Errorf("task %d not found or not failed/cancelled", id)
+	}
+
+	fmt.Printf("Task %d re-queued\n", id)
+	return nil
+}
+
+func runQueueCancel(cmd *cobra.Command, args []string) error {
+	id, _ := cmd.Flags().GetInt("id")
+
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	cancelled, err := db.CancelTask(id)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return fmt.Errorf("task %d not found or not pending", id)
+	}
+
+	fmt.Printf("Task %d cancelled\n", id)
+	return nil
+}
+
+func runQueueRun(cmd *cobra.Command, args []string) error {
+	follow, _ := cmd.Flags().GetBool("follow")
+	maxAttempts, _ := cmd.Flags().GetInt("max-attempts")
+	limitBackoff, _ := cmd.Flags().GetDuration("limit-backoff")
+	browseEvery, _ := cmd.Flags().GetInt("browse-every")
+	browseDuration, _ := cmd.Flags().GetDuration("browse-duration")
+	listen, _ := cmd.Flags().GetString("listen")
+	reloadInterval, _ := cmd.Flags().GetDuration("reload-interval")
+
+	if browseEvery < 0 {
+		return fmt.Errorf("--browse-every must not be negative")
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	if listen != "" {
+		if err := startWebServer(ctx, cfg, db, listen); err != nil {
+			return err
+		}
+	}
+
+	browser, err := openBrowserSession(ctx, cfg, db)
+	if err != nil {
+		return err
+	}
+	defer browser.Close()
+
+	browser.WarmUp(ctx)
+
+	connectManager := newConnectManager(cfg, browser, db)
+	messageManager := newMessageManager(cfg, browser, db)
+	// Variables a task was queued with, e.g. from --input-csv, are set on it as it runs
+	personalizer := newClient(cfg, db).Personalizer(browser)
+	connectManager.SetPersonalizer(personalizer)
+	messageManager.SetPersonalizer(personalizer)
+	searchManager := newSearchManager(cfg, browser, db)
+
+	worker := queue.NewWorker(db, logger.GetLogger())
+	worker.SetMaxAttempts(maxAttempts)
+	worker.SetLimitBackoff(limitBackoff)
+	worker.SetDryRun(dryRun)
+	applySchedule(worker, cfg, db)
+	if browseEvery > 0 {
+		feedBrowser := newFeedBrowser(cfg, browser, db)
+		worker.SetInterleave(browseEvery, func(ctx context.Context) error {
+			_, err := feedBrowser.Browse(ctx, browse.Options{
+				Duration:         browseDuration,
+				ReactProbability: defaultReactProbability,
+				HoverProbability: defaultHoverProbability,
+			})
+			return err
+		})
+	}
+
+	sequences, err := sequence.LoadDir(cfg.Sequences.Dir)
+	if err != nil {
+		return err
+	}
+	var engine *sequence.Engine
+	if len(sequences) > 0 {
+		engine = newSequenceEngine(cfg, browser, db, sequences)
+		worker.SetScheduler(engine)
+	}
+
+	worker.Register(queue.KindConnect, func(ctx context.Context, task *storage.QueueTask) error {
+		var payload queue.ConnectPayload
+		if err := queue.Decode(task, &payload); err != nil {
+			return err
+		}
+		if payload.Campaign != "" {
+			if paused, err := db.IsCampaignPaused(payload.Campaign); err != nil {
+				return err
+			} else if paused {
+				return queue.ErrPaused
+			}
+		}
+
+		recordOpenerPreview, err := checkQueuedAIOpener(cfg, db, payload.Message)
+		if err != nil {
+			return err
+		}
+
+		personalizer.SetVariables(payload.ProfileURL, payload.Variables)
+		batch, err := connectManager.BatchSendConnectionRequests(ctx, []string{payload.ProfileURL}, payload.Message, connect.BatchOptions{
+			BatchID: fmt.Sprintf("queue-%d", task.ID),
+		})
+		if err != nil {
+			return err
+		}
+		if batch.StoppedAtLimit {
+			return batch.StopErr
+		}
+		if batch.Interrupted {
+			return ctx.Err()
+		}
+		batch.Results[0].Variant = payload.Variant
+		if err := client.RecordConnections(db, payload.Campaign, payload.Template, batch.Results); err != nil {
+			logger.GetLogger().WithError(err).Warn("Failed to store connection requests")
+		}
+		if result := batch.Results[0]; !result.Success && !result.EmailRequired && !result.Blacklisted {
+			if result.Err != nil {
+				return result.Err
+			}
+			return errors.New(result.ErrorMessage)
+		}
+		recordOpenerPreview()
+		return nil
+	})
+
+	worker.Register(queue.KindMessage, func(ctx context.Context, task *storage.QueueTask) error {
+		var payload queue.MessagePayload
+		if err := queue.Decode(task, &payload); err != nil {
+			return err
+		}
+
+		recordOpenerPreview, err := checkQueuedAIOpener(cfg, db, payload.Content)
+		if err != nil {
+			return err
+		}
+
+		personalizer.SetVariables(payload.RecipientURL, payload.Variables)
+		batch, err := messageManager.BatchSendMessages(ctx, []string{payload.RecipientURL}, payload.Content, message.BatchOptions{
+			BatchID: fmt.Sprintf("queue-%d", task.ID),
+		})
+		if err != nil {
+			return err
+		}
+		if batch.StoppedAtLimit {
+			return batch.StopErr
+		}
+		if batch.Interrupted {
+			return ctx.Err()
+		}
+		if err := client.RecordMessages(db, "direct", payload.Template, batch.Results); err != nil {
+			logger.GetLogger().WithError(err).Warn("Failed to store messages")
+		}
+		if result := batch.Results[0]; !result.Success && !result.Replied && !result.Blacklisted {
+			if result.Err != nil {
+				return result.Err
+			}
+			return errors.New(result.ErrorMessage)
+		}
+		recordOpenerPreview()
+		return nil
+	})
+
+	worker.Register(queue.KindSearch, func(ctx context.Context, task *storage.QueueTask) error {
+		var payload queue.SearchPayload
+		if err := queue.Decode(task, &payload); err != nil {
+			return err
+		}
+
+		session, err := searchManager.SearchUsers(ctx, payload.Query)
+		if err != nil {
+			return err
+		}
+		if _, err := newClient(cfg, db).RecordSearch(session, payload.ExcludeContacted); err != nil {
+			return err
+		}
+		if payload.Output != "" {
+			return saveSearchResults(session, payload.Output)
+		}
+		return nil
+	})
+
+	worker.Register(queue.KindSync, func(ctx context.Context, task *storage.QueueTask) error {
+		var payload queue.SyncPayload
+		if err := queue.Decode(task, &payload); err != nil {
+			return err
+		}
+
+		pending, err := db.GetPendingConnectionRequests()
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			if _, err := syncAcceptedConnections(ctx, messageManager, db, pending); err != nil {
+				return err
+			}
+		}
+
+		if _, err := messageManager.SyncInbox(ctx, payload.InboxLimit); err != nil {
+			return fmt.Errorf("inbox sync failed: %w", err)
+		}
+		return nil
+	})
+
+	if follow && reloadInterval > 0 {
+		reloader := &configReloader{cfg: cfg, limiter: browser.RateLimiter(), engine: engine, worker: worker, db: db}
+		go reloader.watch(ctx, reloadInterval)
+	}
+	if follow && !dryRun {
+		go newRetentionManager(cfg, db).Schedule(ctx)
+		go newRemindersManager(cfg, db).Schedule(ctx)
+	}
+
+	stats, err := worker.Run(ctx, follow)
+	if err != nil {
+		return fmt.Errorf("queue worker failed: %w", err)
+	}
+
+	if dryRun {
+		fmt.Printf("Dry run: nothing was sent and every task is still pending\n")
+	}
+	fmt.Printf("Queue run completed!\n")
+	fmt.Printf("Completed: %d\n", stats.Completed)
+	fmt.Printf("Retried later: %d\n", stats.Retried)
+	fmt.Printf("Deferred (rate limited): %d\n", stats.Deferred)
+	fmt.Printf("Failed: %d\n", stats.Failed)
+	if stats.SessionEnded {
+		fmt.Printf("Stopped at the session limit; run again to continue\n")
+	}
+	if stats.LoggedOut {
+		fmt.Printf("Stopped because the LinkedIn session expired; log in again and rerun\n")
+	}
+	if stats.Restricted {
+		fmt.Printf("Stopped because LinkedIn restricted the account; see 'restrictions list'\n")
+	}
+
+	switch {
+	case stats.Restricted:
+		return &exitError{code: exitRestricted, err: errs.ErrRestricted, reported: true}
+	case stats.LoggedOut:
+		return &exitError{code: exitAuth, err: errs.ErrSessionExpired, reported: true}
+	case stats.SessionEnded:
+		return batchExit("tasks", stats.Failed, 0, errors.New("session limit reached"))
+	case stats.Limited > 0:
+		return batchExit("tasks", stats.Failed, 0, fmt.Errorf("%d tasks deferred at a rate limit", stats.Limited))
+	}
+	return batchExit("tasks", stats.Failed, stats.Completed+stats.Failed+stats.Retried, nil)
+}
+
+// applySchedule sets the operator window and prospect timing of worker from
+// the stealth schedule in cfg, clearing whichever the schedule turns off
+func applySchedule(worker *queue.Worker, cfg *config.Config, db *storage.Database) {
+	c := newClient(cfg, db)
+	if hours := c.ProspectHours(); hours != nil {
+		worker.SetTiming(hours)
+	} else {
+		worker.SetTiming(nil)
+	}
+	if window := c.OperatorHours(); window != nil {
+		worker.SetWindow(window)
+	} else {
+		worker.SetWindow(nil)
+	}
+}
+
+// startWebServer serves the web dashboard on addr until ctx is cancelled. The
+// address is bound before returning so a port in use fails the command.
+func startWebServer(ctx context.Context, cfg *config.Config, db *storage.Database, addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid --listen address: %w", err)
+	}
+	if cfg.Web.Token == "" && !isLoopback(host) {
+		return fmt.Errorf("set web.token to serve the dashboard beyond localhost")
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	log := logger.GetLogger()
+	server := web.NewServer(db, cfg.Storage.ProgressDir, cfg.Web.Token, log)
+	go func() {
+		if err := server.Serve(ctx, listener); err != nil {
+			log.WithError(err).Error("Web dashboard stopped")
+		}
+	}()
+
+	log.WithField("url", "http://"+listener.Addr().String()).Info("Web dashboard listening")
+	return nil
+}
+
+// isLoopback reports whether host only accepts connections from this machine
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/analytics"
+	"linkedin-automation/config"
+	"linkedin-automation/reminders"
+)
+
+func createRemindersCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "reminders",
+		Short: "Follow up with connections who accepted but never replied",
+		Long: `List the people who accepted a connection request and never replied, and
+queue a nudge from the reminders.template message template for each one who has
+been silent for reminders.after since the acceptance or the last message. Nobody
+gets more than reminders.max_nudges nudges; each is recorded when it is queued,
+so running the command again never messages anyone twice. People who opted out
+or are still in a sequence are left alone, and the queue skips anyone who
+replied or was blacklisted before their nudge runs.`,
+	}
+
+	cmd.AddCommand(createRemindersListCmd())
+	cmd.AddCommand(createRemindersQueueCmd())
+
+	return cmd
+}
+
+func createRemindersListCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "list",
+		Short: "List connections who never replied and when they are due a nudge",
+		RunE:  runRemindersList,
+	}
+
+	addRemindersFlags(cmd)
+	cmd.Flags().Bool("due", false, "Only list the connections due a nudge now")
+
+	return cmd
+}
+
+func createRemindersQueueCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "queue",
+		Short: "Queue a nudge for every connection due one",
+		Long: `Queue a message task with the nudge for every connection due one; 'queue run'
+sends them within the message limits and the prospects' working hours. With
+--dry-run nothing is queued and the nudges that would be are listed. Set
+reminders.interval to have 'queue run --follow' do the same on its own.`,
+		RunE: runRemindersQueue,
+	}
+
+	addRemindersFlags(cmd)
+	cmd.Flags().Int("limit", 0, "Queue at most this many nudges (0 for all)")
+
+	return cmd
+}
+
+func addRemindersFlags(cmd *cobra.Command) {
+	cmd.Flags().Duration("after", 0, "Silence before a nudge is due (default reminders.after)")
+	cmd.Flags().Int("max-nudges", 0, "Nudges per prospect (default reminders.max_nudges)")
+	cmd.Flags().String("template", "", "Message template of the nudges (default reminders.template)")
+}
+
+// remindersConfig applies the flags overriding the reminders settings to cfg
+func remindersConfig(cmd *cobra.Command, cfg *config.Config) error {
+	if cmd.Flags().Changed("after") {
+		cfg.Reminders.After, _ = cmd.Flags().GetDuration("after")
+	}
+	if cmd.Flags().Changed("max-nudges") {
+		cfg.Reminders.MaxNudges, _ = cmd.Flags().GetInt("max-nudges")
+	}
+	if cmd.Flags().Changed("template") {
+		cfg.Reminders.Template, _ = cmd.Flags().GetString("template")
+	}
+
+	if cfg.Reminders.After <= 0 {
+		return fmt.Errorf("--after must be positive")
+	}
+	if cfg.Reminders.MaxNudges < 0 {
+		return fmt.Errorf("--max-nudges must not be negative")
+	}
+	return nil
+}
+
+func runRemindersList(cmd *cobra.Command, args []string) error {
+	dueOnly, _ := cmd.Flags().GetBool("due")
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := remindersConfig(cmd, cfg); err != nil {
+		return err
+	}
+
+	list, err := newRemindersManager(cfg, db).List()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if dueOnly {
+		due := make([]*reminders.Reminder, 0, len(list))
+		for _, reminder := range list {
+			if reminder.Due(now) {
+				due = append(due, reminder)
+			}
+		}
+		list = due
+	}
+	if jsonOutput {
+		return printJSON(list)
+	}
+
+	dueNow := 0
+	for _, reminder := range list {
+		if reminder.Due(now) {
+			dueNow++
+		}
+	}
+
+	fmt.Printf("Connections Who Never Replied\n")
+	fmt.Printf("=============================\n")
+	fmt.Printf("Total: %d (due a nudge now: %d)\n\n", len(list), dueNow)
+	if len(list) == 0 {
+		return nil
+	}
+
+	fmt.Printf("%-20s %-7s %-19s %s\n", "ACCEPTED", "NUDGES", "NEXT NUDGE", "PROSPECT")
+	for _, reminder := range list {
+		next := "now"
+		switch {
+		case reminder.Held != "":
+			next = reminder.Held
+		case reminder.DueAt.After(now):
+			next = "in " + analytics.FormatDuration(reminder.DueAt.Sub(now))
+		}
+		prospect := reminder.ProfileURL
+		if reminder.Name != "" {
+			prospect = fmt.Sprintf("%s (%s)", reminder.Name, reminder.ProfileURL)
+		}
+		fmt.Printf("%-20s %-7s %-19s %s\n", reminder.AcceptedAt.Local().Format("2006-01-02 15:04:05"),
+			fmt.Sprintf("%d/%d", reminder.Nudges, cfg.Reminders.MaxNudges), next, prospect)
+	}
+
+	return nil
+}
+
+func runRemindersQueue(cmd *cobra.Command, args []string) error {
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := remindersConfig(cmd, cfg); err != nil {
+		return err
+	}
+
+	manager := newRemindersManager(cfg, db)
+	if !manager.Enabled() {
+		return fmt.Errorf("reminders are off; set reminders.max_nudges and reminders.template")
+	}
+	queued, err := manager.Queue(time.Now(), limit, dryRun)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		return printJSON(struct {
+			DryRun   bool                  `json:"dry_run"`
+			Template string                `json:"template"`
+			Queued   []*reminders.Reminder `json:"queued"`
+		}{dryRun, cfg.Reminders.Template, queued})
+	}
+
+	if len(queued) == 0 {
+		fmt.Printf("No connections are due a nudge\n")
+		return nil
+	}
+	if dryRun {
+		fmt.Printf("Dry run: nothing was queued\n")
+		for _, reminder := range queued {
+			fmt.Printf("Would queue nudge %d of %d for %s\n", reminder.Nudges+1, cfg.Reminders.MaxNudges, reminder.ProfileURL)
+		}
+		return nil
+	}
+	for _, reminder := range queued {
+		fmt.Printf("Task %-6d nudge %d of %d for %s\n", reminder.TaskID, reminder.Nudges, cfg.Reminders.MaxNudges, reminder.ProfileURL)
+	}
+	fmt.Printf("Queued %d nudges from template %s; run 'queue run' to send them\n", len(queued), cfg.Reminders.Template)
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/resolve"
+	"linkedin-automation/storage"
+)
+
+func createResolveCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "resolve",
+		Short: "Look up LinkedIn IDs for search filters",
+		Long: `Show how names used as search filters resolve to LinkedIn IDs through the
+typeahead, to check why a filter matches the wrong company or none.`,
+	}
+
+	cmd.AddCommand(createResolveCompanyCmd())
+	return cmd
+}
+
+func createResolveCompanyCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "company <name>",
+		Short: "Resolve a company name to its company URN",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runResolveCompany,
+	}
+
+	cmd.Flags().Bool("refresh", false, "Look the name up again instead of using the cached result")
+
+	return cmd
+}
+
+func runResolveCompany(cmd *cobra.Command, args []string) error {
+	refresh, _ := cmd.Flags().GetBool("refresh")
+	name := strings.Join(args, " ")
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if !refresh {
+		cached, err := db.GetCompanyURN(name)
+		if err != nil {
+			return err
+		}
+		if cached != nil && time.Since(cached.ResolvedAt) < resolve.CacheTTL {
+			printCompanyURN(name, cached)
+			fmt.Printf("Cached %s; use --refresh to look it up again\n", cached.ResolvedAt.Local().Format("2006-01-02 15:04"))
+			return nil
+		}
+	}
+
+	ctx := cmd.Context()
+
+	// The typeahead needs session cookies; log in for them unless they are configured
+	resolver := newCompanyResolver(cfg, nil, db)
+	if resolver == nil {
+		browser, err := openBrowserSession(ctx, cfg, db)
+		if err != nil {
+			return err
+		}
+		defer browser.Close()
+
+		if resolver = newCompanyResolver(cfg, browser, db); resolver == nil {
+			return fmt.Errorf("no session cookies available for the typeahead")
+		}
+	}
+
+	company, candidates, err := resolver.Lookup(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	printCompanyURN(name, company)
+	if len(candidates) > 1 {
+		fmt.Printf("\nOther suggestions:\n")
+		for _, candidate := range candidates[1:] {
+			fmt.Printf("  %-12s %s", candidate.ID, candidate.Name)
+			if candidate.Subtitle != "" {
+				fmt.Printf(" (%s)", candidate.Subtitle)
+			}
+			fmt.Printf("\n")
+		}
+	}
+
+	return nil
+}
+
+func printCompanyURN(name string, company *storage.CompanyURN) {
+	fmt.Printf("%s -> %s (%s)\n", name, company.URN(), company.CompanyName)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func createRestrictionsCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "restrictions",
+		Short: "Review LinkedIn restrictions on the account",
+		Long: `List the restrictions and temporary blocks LinkedIn put on the account, such
+as after flagging unusual activity. Once one is noticed, automation stops
+and no browser session opens until restrictions.cooldown has passed.`,
+	}
+
+	cmd.AddCommand(createRestrictionsListCmd())
+	cmd.AddCommand(createRestrictionsClearCmd())
+
+	return cmd
+}
+
+func createRestrictionsListCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "list",
+		Short: "List restrictions noticed recently",
+		RunE:  runRestrictionsList,
+	}
+
+	cmd.Flags().Duration("since", 90*24*time.Hour, "How far back to list restrictions")
+
+	return cmd
+}
+
+func createRestrictionsClearCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "clear",
+		Short: "End the cool-down early",
+		Long: `End the cool-down after a restriction, e.g. once LinkedIn lifted it after
+verifying the account. The restriction stays on record.`,
+		RunE: runRestrictionsClear,
+	}
+
+	cmd.Flags().String("account", "", "Clear the cool-down of this entry under accounts")
+
+	return cmd
+}
+
+func runRestrictionsList(cmd *cobra.Command, args []string) error {
+	since, _ := cmd.Flags().GetDuration("since")
+
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	now := time.Now()
+	restrictions, err := db.ListRestrictions(now.Add(-since))
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		return printJSON(restrictions)
+	}
+
+	fmt.Printf("Restrictions\n")
+	fmt.Printf("============\n\n")
+	if len(restrictions) == 0 {
+		fmt.Printf("None noticed\n")
+		return nil
+	}
+	for _, restriction := range restrictions {
+		fmt.Printf("%s", restriction.DetectedAt.Local().Format("2006-01-02 15:04"))
+		if restriction.Account != "" {
+			fmt.Printf("  [%s]", restriction.Account)
+		}
+		fmt.Printf("  %s\n", restriction.Reason)
+		if now.Before(restriction.CooldownUntil) {
+			fmt.Printf("    Cooling down until %s\n", restriction.CooldownUntil.Local().Format("2006-01-02 15:04"))
+		}
+	}
+	fmt.Printf("\nTotal: %d\n", len(restrictions))
+
+	return nil
+}
+
+func runRestrictionsClear(cmd *cobra.Command, args []string) error {
+	account, _ := cmd.Flags().GetString("account")
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if account != "" {
+		if _, err := cfg.ForAccount(account); err != nil {
+			return err
+		}
+	}
+
+	ended, err := db.EndRestrictionCooldown(account, time.Now())
+	if err != nil {
+		return err
+	}
+	if ended == 0 {
+		fmt.Printf("No cool-down in progress\n")
+		return nil
+	}
+	fmt.Printf("Cool-down ended; automation may run again\n")
+	return nil
+}
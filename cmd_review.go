@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/errs"
+)
+
+// terminalReviewer shows each personalized note or message on the terminal
+// and lets the user approve, edit or skip it before it is sent
+type terminalReviewer struct {
+	in         *bufio.Reader
+	limit      int // Review only the first limit recipients; 0 reviews every one
+	reviewed   int
+	approveAll bool
+	decisions  map[string]review // Earlier answers, reused when a failed send is retried
+}
+
+type review struct {
+	content string
+	send    bool
+}
+
+// addReviewFlags adds the flags that enable review before sending
+func addReviewFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("review", false, "Show each personalized text for approval, editing or skipping before it is sent")
+	cmd.Flags().Int("review-limit", 0, "With --review, only review the first N recipients and send the rest unreviewed (0 reviews all)")
+}
+
+// reviewerFromFlags returns a terminal reviewer if --review was set, or nil
+func reviewerFromFlags(cmd *cobra.Command) (*terminalReviewer, error) {
+	enabled, _ := cmd.Flags().GetBool("review")
+	limit, _ := cmd.Flags().GetInt("review-limit")
+	if !enabled {
+		if limit != 0 {
+			return nil, fmt.Errorf("--review-limit requires --review")
+		}
+		return nil, nil
+	}
+	if queueRequested(cmd) {
+		return nil, fmt.Errorf("--review cannot be combined with --queue or --at")
+	}
+	if limit < 0 {
+		return nil, fmt.Errorf("--review-limit must not be negative")
+	}
+
+	return &terminalReviewer{
+		in:        bufio.NewReader(os.Stdin),
+		limit:     limit,
+		decisions: make(map[string]review),
+	}, nil
+}
+
+// Review implements connect.Reviewer and message.Reviewer
+func (r *terminalReviewer) Review(recipientURL, content string) (string, bool, error) {
+	if earlier, ok := r.decisions[recipientURL]; ok {
+		return earlier.content, earlier.send, nil
+	}
+	if r.approveAll || (r.limit > 0 && r.reviewed >= r.limit) {
+		return content, true, nil
+	}
+	r.reviewed++
+
+	for {
+		fmt.Printf("\nTo: %s\n", recipientURL)
+		fmt.Println(strings.Repeat("-", 60))
+		if content == "" {
+			fmt.Println("(empty)")
+		} else {
+			fmt.Println(content)
+		}
+		fmt.Println(strings.Repeat("-", 60))
+		fmt.Print("[a]pprove, [e]dit, [s]kip, approve a[l]l remaining, [q]uit: ")
+
+		answer, err := r.in.ReadString('\n')
+		if err != nil && (err != io.EOF || answer == "") {
+			return "", false, fmt.Errorf("%w: no answer to review prompt", errs.ErrAborted)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "a", "approve", "y", "yes":
+			r.decisions[recipientURL] = review{content: content, send: true}
+			return content, true, nil
+		case "l", "all":
+			r.approveAll = true
+			r.decisions[recipientURL] = review{content: content, send: true}
+			return content, true, nil
+		case "s", "skip", "n", "no":
+			r.decisions[recipientURL] = review{content: content, send: false}
+			return content, false, nil
+		case "e", "edit":
+			edited, err := r.edit(content)
+			if err != nil {
+				fmt.Printf("Edit failed: %v\n", err)
+				continue
+			}
+			content = edited
+		case "q", "quit":
+			return "", false, errs.ErrAborted
+		}
+	}
+}
+
+// edit opens $EDITOR on the text, or reads replacement lines from the
+// terminal when no editor is configured
+func (r *terminalReviewer) edit(content string) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		return r.readLines()
+	}
+
+	file, err := os.CreateTemp("", "linkedin-review-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(content); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	// EDITOR may carry arguments, such as "code --wait"
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], file.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor failed: %w", err)
+	}
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func (r *terminalReviewer) readLines() (string, error) {
+	fmt.Println("Enter the new text; finish with a line containing only a single '.'")
+
+	var lines []string
+	for {
+		line, err := r.in.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if line == "." {
+			break
+		}
+		lines = append(lines, line)
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/client"
+	"linkedin-automation/config"
+	"linkedin-automation/logger"
+	"linkedin-automation/personalize"
+	"linkedin-automation/queue"
+	"linkedin-automation/search"
+	"linkedin-automation/storage"
+	"linkedin-automation/templates"
+)
+
+// defaultSavedSearchTemplate is the connection template for queued requests when a saved search names none
+const defaultSavedSearchTemplate = "professional"
+
+func createSearchSavedCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "saved",
+		Short: "Manage saved searches and report new profiles",
+		Long: `Save a people search under a name and re-run it to see only the profiles
+that earlier runs did not find. A saved search with a campaign queues
+connection requests to its new profiles.`,
+	}
+
+	cmd.AddCommand(createSearchSavedAddCmd())
+	cmd.AddCommand(createSearchSavedListCmd())
+	cmd.AddCommand(createSearchSavedRemoveCmd())
+	cmd.AddCommand(createSearchSavedRunCmd())
+
+	return cmd
+}
+
+func createSearchSavedAddCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "add",
+		Short: "Save a search, or update the one with this name",
+		RunE:  runSearchSavedAdd,
+	}
+
+	cmd.Flags().String("name", "", "Name to run the search by")
+	addSearchQueryFlags(cmd)
+	cmd.Flags().String("campaign", "", "Queue connection requests to new profiles in this campaign")
+	cmd.Flags().String("template", "", fmt.Sprintf("Connection template for queued requests (default %q)", defaultSavedSearchTemplate))
+	cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+func createSearchSavedListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List saved searches",
+		RunE:  runSearchSavedList,
+	}
+}
+
+func createSearchSavedRemoveCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "remove",
+		Short: "Delete a saved search and the profiles it found",
+		RunE:  runSearchSavedRemove,
+	}
+
+	cmd.Flags().String("name", "", "Saved search name")
+	cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+func createSearchSavedRunCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "run <name>",
+		Short: "Re-run a saved search and report the profiles it had not found before",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runSearchSavedRun,
+	}
+
+	cmd.Flags().Bool("no-queue", false, "Report new profiles without queuing connection requests to them")
+	cmd.Flags().Bool("restart", false, "Start from the first results page instead of where the last run stopped")
+	cmd.Flags().Bool("no-cache", false, "Search LinkedIn even if the same search ran within storage.search_cache_ttl")
+
+	return cmd
+}
+
+func runSearchSavedAdd(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	campaign, _ := cmd.Flags().GetString("campaign")
+	template, _ := cmd.Flags().GetString("template")
+
+	query, err := searchQueryFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if query.Keywords == "" && query.Title == "" && query.Company == "" && query.Location == "" {
+		return fmt.Errorf("give at least one of --keywords, --query-file, --title, --company or --location")
+	}
+	if template != "" && campaign == "" {
+		return fmt.Errorf("--template requires --campaign")
+	}
+
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if parsed, err := search.ParseKeywords(query.Keywords); err == nil {
+		for _, warning := range parsed.Warnings {
+			fmt.Printf("Warning: %s\n", warning)
+		}
+	}
+
+	if campaign != "" {
+		if _, err := savedSearchTemplate(db, template); err != nil {
+			return err
+		}
+	}
+
+	encoded, err := json.Marshal(query)
+	if err != nil {
+		return fmt.Errorf("failed to encode search: %w", err)
+	}
+
+	name = strings.TrimSpace(name)
+	if err := db.SaveSavedSearch(&storage.SavedSearch{
+		Name:     name,
+		Query:    string(encoded),
+		Campaign: campaign,
+		Template: template,
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Saved search %q; run it with 'search saved run %s'\n", name, name)
+	return nil
+}
+
+func runSearchSavedList(cmd *cobra.Command, args []string) error {
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	searches, err := db.ListSavedSearches()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Saved Searches\n")
+	fmt.Printf("==============\n\n")
+	if len(searches) == 0 {
+		fmt.Printf("No saved searches\n")
+		return nil
+	}
+	for _, s := range searches {
+		lastRun := "never run"
+		if s.LastRunAt != nil {
+			lastRun = "last run " + s.LastRunAt.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Printf("%-25s %d profiles, %s\n", s.Name, s.Profiles, lastRun)
+		fmt.Printf("    %s\n", describeSearchQuery(s.Query))
+		if s.Campaign != "" {
+			fmt.Printf("    queues new profiles in campaign %q\n", s.Campaign)
+		}
+		if s.NextPage > 1 {
+			fmt.Printf("    resumes at results page %d\n", s.NextPage)
+		}
+	}
+	fmt.Printf("\nTotal: %d\n", len(searches))
+
+	return nil
+}
+
+func runSearchSavedRemove(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	deleted, err := db.DeleteSavedSearch(name)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("saved search %q not found", name)
+	}
+
+	fmt.Printf("Deleted saved search %q\n", name)
+	return nil
+}
+
+func runSearchSavedRun(cmd *cobra.Command, args []string) error {
+	noQueue, _ := cmd.Flags().GetBool("no-queue")
+	restart, _ := cmd.Flags().GetBool("restart")
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := setupLogger(cfg.Logging); err != nil {
+		return fmt.Errorf("failed to setup logger: %w", err)
+	}
+
+	db, err := openStorage(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer db.Close()
+
+	saved, err := db.GetSavedSearch(args[0])
+	if err != nil {
+		return err
+	}
+	if saved == nil {
+		return fmt.Errorf("saved search %q not found", args[0])
+	}
+
+	var query search.SearchQuery
+	if err := json.Unmarshal([]byte(saved.Query), &query); err != nil {
+		return fmt.Errorf("failed to decode saved search %q: %w", saved.Name, err)
+	}
+	// Deep searches continue from where the last run stopped
+	if !restart && saved.NextPage > 1 {
+		query.StartPage = saved.NextPage
+		fmt.Printf("Resuming at results page %d\n", saved.NextPage)
+	}
+
+	found, err := newClient(cfg, db).Search(cmd.Context(), query, client.SearchOptions{NoCache: noCache})
+	if err != nil {
+		return err
+	}
+	session := found.SearchSession
+	if found.CachedAt != nil {
+		fmt.Printf("Cached results of the same search at %s; --no-cache searches again\n", found.CachedAt.Local().Format("2006-01-02 15:04"))
+	}
+
+	firstRun := saved.LastRunAt == nil
+	added, err := db.RecordSavedSearchRun(saved.ID, session.Profiles, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := db.SetSavedSearchNextPage(saved.ID, session.NextPage); err != nil {
+		return err
+	}
+
+	fmt.Printf("Saved search %q found %d profiles\n", saved.Name, len(session.Profiles))
+	if session.StoppedAtLimit {
+		fmt.Printf("Stopped at search rate limit; results may be incomplete\n")
+	}
+	if session.NextPage > 0 {
+		fmt.Printf("The next run resumes at results page %d\n", session.NextPage)
+	} else if query.StartPage > 1 {
+		fmt.Printf("Reached the last results page; the next run starts over\n")
+	}
+	if firstRun {
+		fmt.Printf("First run: %d profiles recorded; later runs report only new ones\n", len(added))
+	} else {
+		fmt.Printf("New since the last run: %d\n", len(added))
+	}
+
+	results := make(map[string]*search.SearchResult, len(session.Results))
+	for _, result := range session.Results {
+		results[result.ProfileURL] = result
+	}
+	var toQueue []string
+	for i, profileURL := range added {
+		result := results[profileURL]
+		if result == nil {
+			fmt.Printf("%d. %s\n", i+1, profileURL)
+			continue
+		}
+		fmt.Printf("%d. %s - %s\n", i+1, result.Name, result.Title)
+		fmt.Printf("   %s\n", profileURL)
+		if result.AlreadyContacted {
+			fmt.Printf("   already contacted\n")
+			continue
+		}
+		toQueue = append(toQueue, profileURL)
+	}
+
+	if saved.Campaign == "" || noQueue || len(toQueue) == 0 {
+		return nil
+	}
+	if dryRun {
+		fmt.Printf("Dry run: would queue %d connection requests in campaign %q\n", len(toQueue), saved.Campaign)
+		return nil
+	}
+
+	t, err := savedSearchTemplate(db, saved.Template)
+	if err != nil {
+		return err
+	}
+	for _, profileURL := range toQueue {
+		if _, err := queue.Enqueue(db, queue.KindConnect, queue.ConnectPayload{
+			ProfileURL: profileURL,
+			Message:    t.Content,
+			Campaign:   saved.Campaign,
+			Template:   t.Name,
+		}, queue.Options{}); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Queued %d connection requests in campaign %q\n", len(toQueue), saved.Campaign)
+
+	return nil
+}
+
+// savedSearchTemplate loads the connection template for a saved search's queued requests
+func savedSearchTemplate(db *storage.Database, name string) (*storage.Template, error) {
+	if name == "" {
+		name = defaultSavedSearchTemplate
+	}
+	t, err := templates.NewManager(db, logger.GetLogger()).Get(templates.KindConnection, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load template: %w", err)
+	}
+	if err := personalize.Validate(t.Content); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// describeSearchQuery summarizes an encoded search query's filters
+func describeSearchQuery(encoded string) string {
+	var query search.SearchQuery
+	if err := json.Unmarshal([]byte(encoded), &query); err != nil {
+		return "unreadable query"
+	}
+
+	var parts []string
+	for _, filter := range []struct{ name, value string }{
+		{"keywords", query.Keywords},
+		{"title", query.Title},
+		{"company", query.Company},
+		{"location", query.Location},
+		{"degree", strings.Join(query.Network, ",")},
+		{"industry", strings.Join(query.Industries, ",")},
+		{"school", strings.Join(query.Schools, ",")},
+		{"profile-language", strings.Join(query.ProfileLanguages, ",")},
+		{"past-company", strings.Join(query.PastCompanies, ",")},
+	} {
+		if filter.value != "" {
+			parts = append(parts, fmt.Sprintf("%s=%q", filter.name, filter.value))
+		}
+	}
+	if query.MinMutual > 0 {
+		parts = append(parts, fmt.Sprintf("min-mutual=%d", query.MinMutual))
+	}
+	for _, badge := range []struct {
+		name string
+		set  bool
+	}{{"open-to-work", query.OpenToWork}, {"hiring", query.Hiring}, {"premium", query.Premium}} {
+		if badge.set {
+			parts = append(parts, badge.name)
+		}
+	}
+	return strings.Join(parts, " ")
+}
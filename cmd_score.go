@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/config"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/scoring"
+	"linkedin-automation/storage"
+)
+
+func createScoreCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "score",
+		Short: "Score stored profiles against the ideal customer profile",
+		Long: `Score every stored profile again with the rules under scoring, e.g. after
+changing them, and list the best fits. Profiles are also scored as they are
+scraped, and connect to-profiles and message send take --min-score to only
+reach profiles scoring at least that much, best fits first.`,
+		RunE: runScore,
+	}
+
+	cmd.Flags().Int("top", 20, "Number of best-scoring profiles to list (0 for all)")
+	cmd.Flags().Bool("explain", false, "Show the rules each listed profile meets")
+	addTagFilterFlag(cmd, "profiles")
+
+	return cmd
+}
+
+func runScore(cmd *cobra.Command, args []string) error {
+	top, _ := cmd.Flags().GetInt("top")
+	explain, _ := cmd.Flags().GetBool("explain")
+	tags, _ := cmd.Flags().GetStringArray("tag")
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	model, err := scoring.New(cfg.Scoring.Rules)
+	if err != nil {
+		return err
+	}
+	if model.Empty() {
+		return fmt.Errorf("no scoring rules configured; add them under scoring.rules")
+	}
+
+	profiles, err := db.RescoreProfiles(model.Score)
+	if err != nil {
+		return err
+	}
+
+	if len(tags) > 0 {
+		tagged, err := applyTagFilter(db, nil, tags)
+		if err != nil {
+			return err
+		}
+		hasTags := make(map[string]bool, len(tagged))
+		for _, profileURL := range tagged {
+			hasTags[profileURL] = true
+		}
+		filtered := profiles[:0]
+		for _, profile := range profiles {
+			if hasTags[profile.URL] {
+				filtered = append(filtered, profile)
+			}
+		}
+		profiles = filtered
+	}
+
+	sort.SliceStable(profiles, func(i, j int) bool {
+		return profiles[i].Score > profiles[j].Score
+	})
+	if top > 0 && len(profiles) > top {
+		profiles = profiles[:top]
+	}
+
+	if jsonOutput {
+		type scoredProfile struct {
+			URL     string   `json:"url"`
+			Name    string   `json:"name"`
+			Title   string   `json:"title"`
+			Company string   `json:"company"`
+			Score   int      `json:"score"`
+			Rules   []string `json:"rules"`
+		}
+		out := make([]scoredProfile, 0, len(profiles))
+		for _, profile := range profiles {
+			_, rules := model.Explain(profile)
+			out = append(out, scoredProfile{profile.URL, profile.Name, profile.Title, profile.Company, profile.Score, rules})
+		}
+		return printJSON(out)
+	}
+
+	fmt.Printf("Profiles scored!\n")
+	for i, profile := range profiles {
+		fmt.Printf("%d. [%d] %s - %s\n", i+1, profile.Score, profile.Name, profile.Title)
+		fmt.Printf("   %s\n", profile.URL)
+		if explain {
+			if _, rules := model.Explain(profile); len(rules) > 0 {
+				fmt.Printf("   Rules: %s\n", strings.Join(rules, ", "))
+			}
+		}
+	}
+
+	return nil
+}
+
+// addMinScoreFlag adds the --min-score flag applyMinScore reads
+func addMinScoreFlag(cmd *cobra.Command, noun string) {
+	cmd.Flags().Int("min-score", 0, fmt.Sprintf("Only include %s scoring at least this much under the scoring rules, best fits first", noun))
+}
+
+// applyMinScore drops the profiles scoring below --min-score under the
+// current scoring rules and orders the rest from the best fit down, keeping
+// the given order among equal scores. Profiles never scraped score 0. It
+// returns the profiles unchanged when the flag is not set.
+func applyMinScore(cmd *cobra.Command, cfg *config.Config, db *storage.Database, profiles []string) ([]string, int, error) {
+	if !cmd.Flags().Changed("min-score") {
+		return profiles, 0, nil
+	}
+	minScore, _ := cmd.Flags().GetInt("min-score")
+
+	model, err := scoring.New(cfg.Scoring.Rules)
+	if err != nil {
+		return nil, 0, err
+	}
+	if model.Empty() {
+		return nil, 0, fmt.Errorf("--min-score needs scoring rules; add them under scoring.rules")
+	}
+
+	scores := make(map[string]int, len(profiles))
+	kept := make([]string, 0, len(profiles))
+	for _, profileURL := range profiles {
+		profile, err := db.GetProfile(profileurl.Canonicalize(profileURL))
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get profile: %w", err)
+		}
+		score := 0
+		if profile != nil {
+			score = model.Score(profile)
+		}
+		if score < minScore {
+			continue
+		}
+		scores[profileURL] = score
+		kept = append(kept, profileURL)
+	}
+
+	sort.SliceStable(kept, func(i, j int) bool {
+		return scores[kept[i]] > scores[kept[j]]
+	})
+	return kept, len(profiles) - len(kept), nil
+}
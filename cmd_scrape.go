@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/logger"
+	"linkedin-automation/scrape"
+	"linkedin-automation/storage"
+)
+
+func createScrapeCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "scrape",
+		Short: "Scrape prospects from LinkedIn pages",
+		Long:  `Collect prospects from sources other than keyword search, such as company pages.`,
+	}
+
+	cmd.AddCommand(createScrapeCompanyCmd())
+	return cmd
+}
+
+func createScrapeCompanyCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "company",
+		Short: "Scrape a company page's employees and recent posts",
+		Long: `Read the People tab of a company page, loading more results until --max-results
+employees are found, and optionally its most recent posts.
+
+Employees are stored as profiles with search query "company:<name>" so they can
+be used like search results. Each page of results counts against the search
+rate limit.`,
+		RunE: runScrapeCompany,
+	}
+
+	cmd.Flags().String("company", "", "Company page URL (e.g. https://www.linkedin.com/company/acme/)")
+	cmd.Flags().Int("max-results", 100, "Maximum number of employees")
+	cmd.Flags().Int("posts", 10, "Number of recent posts to store (0 to skip posts)")
+	cmd.Flags().String("output", "", "Output file path")
+	cmd.MarkFlagRequired("company")
+
+	return cmd
+}
+
+func runScrapeCompany(cmd *cobra.Command, args []string) error {
+	company, _ := cmd.Flags().GetString("company")
+	maxResults, _ := cmd.Flags().GetInt("max-results")
+	posts, _ := cmd.Flags().GetInt("posts")
+	output, _ := cmd.Flags().GetString("output")
+
+	if scrape.CompanySlug(company) == "" {
+		return fmt.Errorf("--company must be a company page URL")
+	}
+	if maxResults < 1 {
+		return fmt.Errorf("--max-results must be at least 1")
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := cmd.Context()
+
+	browser, err := openBrowserSession(ctx, cfg, db)
+	if err != nil {
+		return err
+	}
+	defer browser.Close()
+
+	result, err := newScrapeManager(cfg, browser, db).ScrapeCompany(ctx, company, scrape.CompanyOptions{
+		MaxResults: maxResults,
+		Posts:      posts,
+	})
+	if err != nil {
+		return fmt.Errorf("company scrape failed: %w", err)
+	}
+
+	if err := saveCompanyResult(db, result); err != nil {
+		logger.GetLogger().WithError(err).Warn("Failed to store company scrape")
+	}
+
+	fmt.Printf("Company scrape completed!\n")
+	if result.Name != "" {
+		fmt.Printf("Company: %s\n", result.Name)
+	}
+	if result.Size > 0 {
+		fmt.Printf("Company size: %d+ employees\n", result.Size)
+	}
+	fmt.Printf("Employees: %d\n", len(result.Employees))
+	fmt.Printf("Posts: %d\n", len(result.Posts))
+	if result.StoppedAtLimit {
+		fmt.Printf("Stopped at search rate limit; results may be incomplete\n")
+	}
+
+	if output != "" {
+		if err := saveCompanyResultFile(result, output); err != nil {
+			logger.GetLogger().WithError(err).Error("Failed to save results")
+		} else {
+			fmt.Printf("Results saved to: %s\n", output)
+		}
+	} else {
+		for i, employee := range result.Employees {
+			fmt.Printf("%d. %s - %s\n", i+1, employee.Name, employee.Headline)
+			fmt.Printf("   %s\n", employee.ProfileURL)
+		}
+	}
+
+	return nil
+}
+
+// saveCompanyResult stores scraped employees as profiles and the company's posts
+func saveCompanyResult(db *storage.Database, result *scrape.CompanyResult) error {
+	searchQuery := "company:" + scrape.CompanySlug(result.CompanyURL)
+
+	scrapedAt := time.Now()
+	for _, employee := range result.Employees {
+		profile := &storage.Profile{
+			URL:         employee.ProfileURL,
+			Name:        employee.Name,
+			Headline:    employee.Headline,
+			Company:     result.Name,
+			CompanySize: result.Size,
+			SearchQuery: searchQuery,
+		}
+		// Someone listed among the employees works there now, whatever was stored before
+		if _, err := db.RecordProfileChanges(profile, scrapedAt); err != nil {
+			return err
+		}
+		if err := mergeAndSaveProfile(db, profile); err != nil {
+			return err
+		}
+	}
+
+	for _, post := range result.Posts {
+		if err := db.SaveCompanyPost(&storage.CompanyPost{
+			CompanyURL: result.CompanyURL,
+			PostURN:    post.URN,
+			AuthorName: post.AuthorName,
+			Content:    post.Text,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func saveCompanyResultFile(result *scrape.CompanyResult, outputPath string) error {
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal company results: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	return os.WriteFile(outputPath, jsonData, 0644)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"linkedin-automation/config"
+	"linkedin-automation/selectors"
+)
+
+func createSelectorsCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "selectors",
+		Short: "Inspect page selectors",
+		Long: `Show the CSS selectors used to find LinkedIn page elements.
+
+When LinkedIn changes its pages, copy the affected keys into the file set by
+browser.selectors_file (./selectors.yaml by default) and edit them; each key
+takes a selector or a list of fallbacks tried in order.`,
+	}
+
+	cmd.AddCommand(createSelectorsListCmd())
+	return cmd
+}
+
+func createSelectorsListCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "list",
+		Short: "Print the selectors in effect as YAML",
+		RunE:  runSelectorsList,
+	}
+
+	cmd.Flags().Bool("defaults", false, "Ignore the selectors file and print the built-in selectors")
+
+	return cmd
+}
+
+func runSelectorsList(cmd *cobra.Command, args []string) error {
+	defaults, _ := cmd.Flags().GetBool("defaults")
+
+	if !defaults {
+		cfg, err := config.LoadConfig(configFile)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if err := loadSelectors(cfg); err != nil {
+			return err
+		}
+	}
+
+	// Keys are printed in sorted order so the output can be diffed
+	all := selectors.All()
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range selectors.Keys() {
+		var value yaml.Node
+		if err := value.Encode(all[key]); err != nil {
+			return err
+		}
+		doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: string(key)}, &value)
+	}
+
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode selectors: %w", err)
+	}
+	return encoder.Close()
+}
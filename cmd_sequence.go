@@ -0,0 +1,317 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/config"
+	"linkedin-automation/logger"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/sequence"
+	"linkedin-automation/storage"
+	"linkedin-automation/templates"
+)
+
+func createSequenceCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "sequence",
+		Short: "Manage multi-step drip sequences",
+		Long: `Enroll prospects in drip sequences defined as YAML files in the sequences
+directory. 'queue run' queues each step once it is due, so keep
+'queue run --follow' running to advance them.`,
+	}
+
+	cmd.AddCommand(createSequenceListCmd())
+	cmd.AddCommand(createSequenceEnrollCmd())
+	cmd.AddCommand(createSequenceStatusCmd())
+	cmd.AddCommand(createSequenceStopCmd())
+
+	return cmd
+}
+
+func createSequenceListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List defined sequences and their steps",
+		RunE:  runSequenceList,
+	}
+}
+
+func createSequenceEnrollCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "enroll",
+		Short: "Start prospects on a sequence",
+		RunE:  runSequenceEnroll,
+	}
+
+	cmd.Flags().String("sequence", "", "Sequence name")
+	cmd.Flags().String("profiles", "", "Comma-separated profile URLs")
+	cmd.Flags().Bool("exclude-contacted", false, "Skip profiles that were already sent a connection request or message")
+	cmd.MarkFlagRequired("sequence")
+	cmd.MarkFlagRequired("profiles")
+
+	return cmd
+}
+
+func createSequenceStatusCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show where enrolled prospects are in their sequences",
+		RunE:  runSequenceStatus,
+	}
+
+	cmd.Flags().String("sequence", "", "Only show this sequence")
+	cmd.Flags().String("status", "", "Only show enrollments with this status (active, running, completed, replied, paused, stopped, failed)")
+
+	return cmd
+}
+
+func createSequenceStopCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "stop",
+		Short: "Stop prospects' sequences and cancel their queued steps",
+		RunE:  runSequenceStop,
+	}
+
+	cmd.Flags().String("sequence", "", "Sequence name")
+	cmd.Flags().String("profiles", "", "Comma-separated profile URLs")
+	cmd.MarkFlagRequired("sequence")
+	cmd.MarkFlagRequired("profiles")
+
+	return cmd
+}
+
+// openSequences loads the config, the database and the defined sequences
+func openSequences() (*config.Config, *storage.Database, map[string]*sequence.Sequence, error) {
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	sequences, err := sequence.LoadDir(cfg.Sequences.Dir)
+	if err != nil {
+		db.Close()
+		return nil, nil, nil, err
+	}
+
+	return cfg, db, sequences, nil
+}
+
+func runSequenceList(cmd *cobra.Command, args []string) error {
+	cfg, db, sequences, err := openSequences()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if len(sequences) == 0 {
+		fmt.Printf("No sequences defined in %s\n", cfg.Sequences.Dir)
+		return nil
+	}
+
+	for _, name := range sequence.Names(sequences) {
+		seq := sequences[name]
+
+		enrollments, err := db.ListSequenceEnrollments(name, "")
+		if err != nil {
+			return err
+		}
+		counts := make(map[string]int)
+		for _, enrollment := range enrollments {
+			counts[enrollment.Status]++
+		}
+
+		fmt.Printf("%s (%d enrolled: %d in progress, %d completed, %d replied, %d paused)\n", name, len(enrollments),
+			counts[storage.EnrollmentActive]+counts[storage.EnrollmentRunning], counts[storage.EnrollmentCompleted], counts[storage.EnrollmentReplied],
+			counts[storage.EnrollmentPaused])
+		for i, step := range seq.Steps {
+			fmt.Printf("  %d. %s\n", i+1, describeStep(step))
+		}
+		if !seq.StopsOnReply() {
+			fmt.Printf("  Continues after a reply\n")
+		}
+		fmt.Printf("\n")
+	}
+
+	return nil
+}
+
+// describeStep summarizes a step for the list command
+func describeStep(step sequence.Step) string {
+	description := step.Action
+	if step.Template != "" {
+		description += fmt.Sprintf(" with template %q", step.Template)
+	} else if step.Message != "" {
+		description += " with a custom message"
+	}
+
+	after := time.Duration(step.After)
+	switch step.When {
+	case sequence.WhenAccepted:
+		description += fmt.Sprintf(", %s after acceptance", formatDelay(after))
+	case sequence.WhenNoReply:
+		description += fmt.Sprintf(", %s later if no reply", formatDelay(after))
+	default:
+		if after > 0 {
+			description += fmt.Sprintf(", %s later", formatDelay(after))
+		}
+	}
+	return description
+}
+
+// formatDelay prints whole days as "2d" and anything else as a Go duration
+func formatDelay(d time.Duration) string {
+	if d > 0 && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
+
+func runSequenceEnroll(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("sequence")
+	profiles, _ := cmd.Flags().GetString("profiles")
+	excludeContacted, _ := cmd.Flags().GetBool("exclude-contacted")
+
+	_, db, sequences, err := openSequences()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	seq, ok := sequences[name]
+	if !ok {
+		return fmt.Errorf("unknown sequence %q", name)
+	}
+	// Catch a misspelled template now rather than when its step comes due
+	if err := sequence.CheckTemplates(templates.NewManager(db, logger.GetLogger()), seq); err != nil {
+		return err
+	}
+
+	profileList := profileurl.Dedupe(parseCommaSeparated(profiles))
+	if len(profileList) == 0 {
+		return fmt.Errorf("no profiles provided")
+	}
+
+	var contacted map[string]bool
+	if excludeContacted {
+		contacted, err = db.GetContactedProfiles()
+		if err != nil {
+			return fmt.Errorf("failed to load contacted profiles: %w", err)
+		}
+	}
+
+	enrolled, already, excluded := 0, 0, 0
+	for _, profileURL := range profileList {
+		if contacted[profileURL] {
+			excluded++
+			continue
+		}
+		added, err := db.EnrollInSequence(name, profileURL)
+		if err != nil {
+			return err
+		}
+		if added {
+			enrolled++
+		} else {
+			already++
+		}
+	}
+
+	fmt.Printf("Enrolled in %s: %d\n", name, enrolled)
+	fmt.Printf("Already enrolled: %d\n", already)
+	if excludeContacted {
+		fmt.Printf("Skipped (already contacted): %d\n", excluded)
+	}
+	fmt.Printf("Run 'queue run --follow' to advance the sequence\n")
+
+	return nil
+}
+
+func runSequenceStatus(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("sequence")
+	status, _ := cmd.Flags().GetString("status")
+
+	_, db, sequences, err := openSequences()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	enrollments, err := db.ListSequenceEnrollments(name, status)
+	if err != nil {
+		return err
+	}
+	if len(enrollments) == 0 {
+		fmt.Printf("No enrollments\n")
+		return nil
+	}
+
+	fmt.Printf("%-20s %-6s %-10s %-20s %s\n", "SEQUENCE", "STEP", "STATUS", "NEXT RUN", "PROFILE")
+	for _, enrollment := range enrollments {
+		step := fmt.Sprintf("%d", enrollment.Step+1)
+		if seq, ok := sequences[enrollment.Sequence]; ok {
+			if enrollment.Step >= len(seq.Steps) {
+				step = "done"
+			} else {
+				step = fmt.Sprintf("%d/%d", enrollment.Step+1, len(seq.Steps))
+			}
+		}
+
+		nextRun := "-"
+		if enrollment.Status == storage.EnrollmentActive {
+			nextRun = enrollment.NextRunAt.Local().Format("2006-01-02 15:04:05")
+		} else if enrollment.Status == storage.EnrollmentRunning {
+			nextRun = fmt.Sprintf("task %d", enrollment.TaskID)
+		}
+
+		fmt.Printf("%-20s %-6s %-10s %-20s %s\n", enrollment.Sequence, step, enrollment.Status, nextRun, enrollment.ProfileURL)
+		if enrollment.LastError != "" {
+			fmt.Printf("       last error: %s\n", enrollment.LastError)
+		}
+	}
+
+	return nil
+}
+
+func runSequenceStop(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("sequence")
+	profiles, _ := cmd.Flags().GetString("profiles")
+
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	for _, profileURL := range profileurl.Dedupe(parseCommaSeparated(profiles)) {
+		enrollment, err := db.GetSequenceEnrollment(name, profileURL)
+		if err != nil {
+			return err
+		}
+		if enrollment == nil {
+			fmt.Printf("Not enrolled: %s\n", profileURL)
+			continue
+		}
+		if enrollment.Status != storage.EnrollmentActive && enrollment.Status != storage.EnrollmentRunning &&
+			enrollment.Status != storage.EnrollmentPaused {
+			fmt.Printf("Already %s: %s\n", enrollment.Status, profileURL)
+			continue
+		}
+
+		if enrollment.Status == storage.EnrollmentRunning {
+			if _, err := db.CancelTask(enrollment.TaskID); err != nil {
+				return err
+			}
+		}
+		enrollment.Status = storage.EnrollmentStopped
+		enrollment.LastError = "stopped by hand"
+		if err := db.UpdateSequenceEnrollment(enrollment); err != nil {
+			return err
+		}
+		fmt.Printf("Stopped: %s\n", profileURL)
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/auth"
+	"linkedin-automation/config"
+)
+
+func createSessionCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "session",
+		Short: "Import and export the saved login session",
+		Long: `Manage the session cookies kept in browser.session_dir, which later runs log
+in with instead of the password. Importing the li_at cookie of a browser
+already logged in to LinkedIn bootstraps the session without a password;
+exporting it moves the session to another machine.`,
+	}
+
+	cmd.AddCommand(createSessionImportCmd())
+	cmd.AddCommand(createSessionExportCmd())
+
+	return cmd
+}
+
+func createSessionImportCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "import",
+		Short: "Log in with a session cookie or an exported session",
+		Long: `Save a session for later runs to log in with. --cookie takes the value of
+the li_at cookie, found under linkedin.com in the cookie list of a browser's
+developer tools; --file takes the output of 'session export'.
+
+The browser is opened once to check that LinkedIn accepts the session, and
+the cookies it then holds are saved.`,
+		RunE: runSessionImport,
+	}
+
+	cmd.Flags().String("cookie", "", "Value of the li_at cookie")
+	cmd.Flags().String("jsessionid", "", "Value of the JSESSIONID cookie, if known")
+	cmd.Flags().String("file", "", "Session file written by 'session export'")
+	cmd.Flags().String("account", "", "Save the session of this entry under accounts")
+	cmd.MarkFlagsMutuallyExclusive("cookie", "file")
+
+	return cmd
+}
+
+func createSessionExportCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "export",
+		Short: "Write the saved session for another machine",
+		Long: `Write the saved session's cookies as JSON, to standard output or --output,
+for 'session import --file' on another machine. The cookies log in as the
+account, so treat the file like a password.`,
+		RunE: runSessionExport,
+	}
+
+	cmd.Flags().StringP("output", "o", "", "File to write instead of standard output")
+	cmd.Flags().String("account", "", "Export the session of this entry under accounts")
+
+	return cmd
+}
+
+// sessionConfig loads the configuration of the account named by --account, or
+// the top-level one
+func sessionConfig(cmd *cobra.Command) (*config.Config, error) {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := setupLogger(cfg.Logging); err != nil {
+		return nil, fmt.Errorf("failed to setup logger: %w", err)
+	}
+	if account, _ := cmd.Flags().GetString("account"); account != "" {
+		return cfg.ForAccount(account)
+	}
+	return cfg, nil
+}
+
+func runSessionImport(cmd *cobra.Command, args []string) error {
+	cookie, _ := cmd.Flags().GetString("cookie")
+	jsessionID, _ := cmd.Flags().GetString("jsessionid")
+	file, _ := cmd.Flags().GetString("file")
+
+	var session *auth.SavedSession
+	switch {
+	case cookie != "":
+		var err error
+		if session, err = auth.CookieSession(cookie, jsessionID); err != nil {
+			return err
+		}
+	case file != "":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read session file: %w", err)
+		}
+		if session, err = auth.ParseSession(data); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("--cookie or --file is required")
+	}
+
+	cfg, err := sessionConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	authManager := newAuthManager(cfg)
+	if err := authManager.InitializeBrowser(headless, cfg.Browser.UserAgent); err != nil {
+		return fmt.Errorf("failed to initialize browser: %w", err)
+	}
+	defer authManager.Close()
+
+	if err := authManager.ImportSession(cmd.Context(), session); err != nil {
+		return fmt.Errorf("failed to import session: %w", err)
+	}
+
+	fmt.Printf("Session imported to %s\n", cfg.Browser.SessionDir)
+	return nil
+}
+
+func runSessionExport(cmd *cobra.Command, args []string) error {
+	output, _ := cmd.Flags().GetString("output")
+
+	cfg, err := sessionConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	session, err := auth.ReadSession(cfg.Browser.SessionDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no saved session in %s; log in or run 'session import' first", cfg.Browser.SessionDir)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read session: %w", err)
+	}
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+	data = append(data, '\n')
+
+	if output == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if dir := filepath.Dir(output); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(output, data, 0600); err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	fmt.Printf("Session exported to %s\n", output)
+	return nil
+}
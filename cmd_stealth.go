@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/config"
+	"linkedin-automation/logger"
+	"linkedin-automation/stealth"
+)
+
+func createStealthCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "stealth",
+		Short: "Check stealth settings",
+		Long:  `Verify that stealth techniques hide browser automation before running against LinkedIn.`,
+	}
+
+	cmd.AddCommand(createStealthTestCmd())
+	return cmd
+}
+
+func createStealthTestCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "test",
+		Short: "Report automation indicators still visible to pages",
+		Long: `Launch the browser with the configured stealth settings, open a bundled
+fingerprint checker and report which automation indicators a page can still
+see: the webdriver flag, headless user agent hints, plugin count, WebGL
+vendor and renderer, and more. LinkedIn is not contacted.
+
+Use --url to run the same checks on public bot-detection pages as well.`,
+		RunE: runStealthTest,
+	}
+
+	cmd.Flags().StringSlice("url", nil, "Additional test pages to check (repeatable)")
+	cmd.Flags().Bool("no-stealth", false, "Skip stealth patches to see the unprotected baseline")
+
+	return cmd
+}
+
+func runStealthTest(cmd *cobra.Command, args []string) error {
+	urls, _ := cmd.Flags().GetStringSlice("url")
+	noStealth, _ := cmd.Flags().GetBool("no-stealth")
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := setupLogger(cfg.Logging); err != nil {
+		return fmt.Errorf("failed to setup logger: %w", err)
+	}
+
+	server, err := stealth.StartSelfTestServer()
+	if err != nil {
+		return err
+	}
+	defer server.Close()
+
+	authManager := newAuthManager(cfg)
+	if err := authManager.InitializeBrowser(headless, cfg.Browser.UserAgent); err != nil {
+		return fmt.Errorf("failed to initialize browser: %w", err)
+	}
+	defer authManager.Close()
+
+	page, err := authManager.NewPage()
+	if err != nil {
+		return err
+	}
+	defer page.Close()
+
+	if !noStealth {
+		stealthManager := newStealthManager(cmd.Context(), cfg)
+		if err := stealthManager.ApplyStealth(page); err != nil {
+			logger.GetLogger().WithError(err).Warn("Failed to apply some stealth features")
+		}
+	}
+
+	exposed := 0
+	for _, pageURL := range append([]string{server.URL}, urls...) {
+		report, err := stealth.RunSelfTest(page, pageURL)
+		if err != nil {
+			fmt.Printf("%s: %v\n\n", pageURL, err)
+			continue
+		}
+		printSelfTestReport(report)
+		exposed += len(report.Exposed())
+	}
+
+	if exposed > 0 {
+		fmt.Printf("%d automation indicator(s) exposed\n", exposed)
+	} else {
+		fmt.Printf("No automation indicators exposed\n")
+	}
+	return nil
+}
+
+func printSelfTestReport(report *stealth.SelfTestReport) {
+	fmt.Printf("%s\n", report.URL)
+	for _, check := range report.Checks {
+		result := "ok"
+		if check.Exposed {
+			result = "EXPOSED"
+		}
+		fmt.Printf("  %-8s %-24s %s\n", result, check.Name, check.Value)
+	}
+	fmt.Println()
+}
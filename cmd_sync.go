@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/config"
+	"linkedin-automation/integrations"
+	"linkedin-automation/logger"
+	"linkedin-automation/storage"
+)
+
+func createSyncCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "sync",
+		Short: "Sync data with external systems",
+	}
+
+	cmd.AddCommand(createSyncCRMCmd())
+	return cmd
+}
+
+func createSyncCRMCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "crm",
+		Short: "Push accepted connections to the configured CRMs",
+		Long: `Push profiles whose connection requests were accepted into HubSpot and/or
+Pipedrive. Each profile is pushed to a connector once; failed pushes are
+retried on the next run. Run 'connect sync-accepted' first to pick up new
+acceptances.`,
+		RunE: runSyncCRM,
+	}
+
+	cmd.Flags().String("connector", "", "Only sync this connector (hubspot, pipedrive)")
+
+	return cmd
+}
+
+func runSyncCRM(cmd *cobra.Command, args []string) error {
+	only, _ := cmd.Flags().GetString("connector")
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	syncer, err := newCRMSyncer(cfg, db, only)
+	if err != nil {
+		return err
+	}
+
+	return runCRMSync(cmd.Context(), syncer)
+}
+
+// newCRMSyncer builds a syncer for the enabled connectors, optionally limited to one
+func newCRMSyncer(cfg *config.Config, db *storage.Database, only string) (*integrations.Syncer, error) {
+	var connectors []integrations.Connector
+	if cfg.Integrations.HubSpot.Enabled {
+		connectors = append(connectors, integrations.NewHubSpot(cfg.Integrations.HubSpot.APIKey, cfg.Integrations.HubSpot.BaseURL))
+	}
+	if cfg.Integrations.Pipedrive.Enabled {
+		connectors = append(connectors, integrations.NewPipedrive(cfg.Integrations.Pipedrive.APIToken, cfg.Integrations.Pipedrive.BaseURL, logger.GetLogger()))
+	}
+
+	if only != "" {
+		var selected []integrations.Connector
+		for _, connector := range connectors {
+			if connector.Name() == only {
+				selected = append(selected, connector)
+			}
+		}
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("connector %q is not enabled in the integrations config", only)
+		}
+		connectors = selected
+	}
+
+	if len(connectors) == 0 {
+		return nil, fmt.Errorf("no CRM connectors enabled; configure integrations.hubspot or integrations.pipedrive")
+	}
+
+	return integrations.NewSyncer(db, connectors, logger.GetLogger()), nil
+}
+
+func runCRMSync(ctx context.Context, syncer *integrations.Syncer) error {
+	result, err := syncer.Sync(ctx)
+	if err != nil {
+		return fmt.Errorf("CRM sync failed: %w", err)
+	}
+
+	pushed := 0
+	for _, push := range result.Results {
+		if push.Error != nil {
+			fmt.Printf("  %s: %s failed: %v\n", push.Connector, push.ProfileURL, push.Error)
+			continue
+		}
+		pushed++
+	}
+
+	fmt.Printf("CRM sync completed!\n")
+	fmt.Printf("Pushed: %d\n", pushed)
+	fmt.Printf("Already synced: %d\n", result.Skipped)
+	fmt.Printf("Failed: %d\n", len(result.Results)-pushed)
+
+	return nil
+}
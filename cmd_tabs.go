@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/logger"
+)
+
+// maxTabs caps concurrent tabs; each one multiplies how busy the account looks
+const maxTabs = 5
+
+// addTabsFlag adds the flag that spreads a batch over several browser tabs
+func addTabsFlag(cmd *cobra.Command) {
+	cmd.Flags().Int("tabs", 1, fmt.Sprintf("Work the batch in up to %d browser tabs at once (faster, but riskier)", maxTabs))
+}
+
+// tabsFromFlags returns the number of tabs set with --tabs
+func tabsFromFlags(cmd *cobra.Command) (int, error) {
+	tabs, _ := cmd.Flags().GetInt("tabs")
+	if tabs < 1 || tabs > maxTabs {
+		return 0, fmt.Errorf("--tabs must be between 1 and %d", maxTabs)
+	}
+	if tabs == 1 {
+		return 1, nil
+	}
+	if review, _ := cmd.Flags().GetBool("review"); review {
+		return 0, fmt.Errorf("--review cannot be combined with --tabs")
+	}
+	if queueRequested(cmd) {
+		return 0, fmt.Errorf("--tabs cannot be combined with --queue or --at")
+	}
+
+	logger.GetLogger().WithField("tabs", tabs).Warn("Working the batch in several tabs at once; activity this dense is easier for LinkedIn to spot")
+	return tabs, nil
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/profileurl"
+	"linkedin-automation/storage"
+)
+
+func createTagCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "tag",
+		Short: "Tag prospects to segment them",
+		Long: `Label prospects with tags such as "conference-2024", "warm" or
+"decision-maker", then target or export a segment with --tag on the connect,
+message and export commands.`,
+	}
+
+	cmd.AddCommand(createTagAddCmd())
+	cmd.AddCommand(createTagRemoveCmd())
+	cmd.AddCommand(createTagListCmd())
+
+	return cmd
+}
+
+func createTagAddCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "add",
+		Short: "Add a tag to profiles",
+		RunE:  runTagAdd,
+	}
+
+	cmd.Flags().String("name", "", "Tag name")
+	cmd.Flags().String("profiles", "", "Comma-separated list of profile URLs")
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("profiles")
+
+	return cmd
+}
+
+func createTagRemoveCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "remove",
+		Short: "Remove a tag from profiles, or delete it with --all",
+		RunE:  runTagRemove,
+	}
+
+	cmd.Flags().String("name", "", "Tag name")
+	cmd.Flags().String("profiles", "", "Comma-separated list of profile URLs")
+	cmd.Flags().Bool("all", false, "Remove the tag from every profile and delete it")
+	cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+func createTagListCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "list",
+		Short: "List tags, the profiles with a tag, or the tags of a profile",
+		RunE:  runTagList,
+	}
+
+	cmd.Flags().StringArray("name", nil, "List the profiles with this tag (repeatable; profiles must have every tag)")
+	cmd.Flags().String("profile", "", "List the tags of this profile")
+
+	return cmd
+}
+
+func runTagAdd(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	profiles, _ := cmd.Flags().GetString("profiles")
+
+	profileList := profileurl.Dedupe(parseCommaSeparated(profiles))
+	if len(profileList) == 0 {
+		return fmt.Errorf("no profiles provided")
+	}
+
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	added, err := db.TagProspects(name, profileList)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Tagged %d profiles with %q (%d already had it)\n", added, storage.NormalizeTag(name), len(profileList)-added)
+	return nil
+}
+
+func runTagRemove(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	profiles, _ := cmd.Flags().GetString("profiles")
+	all, _ := cmd.Flags().GetBool("all")
+
+	profileList := profileurl.Dedupe(parseCommaSeparated(profiles))
+	if all == (len(profileList) > 0) {
+		return fmt.Errorf("give either --profiles or --all")
+	}
+
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if all {
+		deleted, err := db.DeleteTag(name)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return fmt.Errorf("tag %q not found", storage.NormalizeTag(name))
+		}
+		fmt.Printf("Deleted tag %q\n", storage.NormalizeTag(name))
+		return nil
+	}
+
+	removed, err := db.UntagProspects(name, profileList)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed %q from %d profiles\n", storage.NormalizeTag(name), removed)
+	return nil
+}
+
+func runTagList(cmd *cobra.Command, args []string) error {
+	names, _ := cmd.Flags().GetStringArray("name")
+	profile, _ := cmd.Flags().GetString("profile")
+
+	_, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if profile != "" {
+		tags, err := db.GetProspectTags(profile)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Tags of %s\n\n", profileurl.Canonicalize(profile))
+		if len(tags) == 0 {
+			fmt.Printf("No tags\n")
+		}
+		for _, tag := range tags {
+			fmt.Printf("%s\n", tag)
+		}
+		return nil
+	}
+
+	if len(names) > 0 {
+		profiles, err := db.GetTaggedProfiles(names)
+		if err != nil {
+			return err
+		}
+		for _, profileURL := range profiles {
+			fmt.Printf("%s\n", profileURL)
+		}
+		fmt.Printf("\nTotal: %d\n", len(profiles))
+		return nil
+	}
+
+	tags, err := db.ListTags()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Tags\n")
+	fmt.Printf("====\n\n")
+	if len(tags) == 0 {
+		fmt.Printf("No tags\n")
+		return nil
+	}
+	for _, tag := range tags {
+		fmt.Printf("%-30s %d profiles\n", tag.Name, tag.Prospects)
+	}
+	fmt.Printf("\nTotal: %d\n", len(tags))
+
+	return nil
+}
+
+// addTagFilterFlag adds the --tag flag that narrows the given list flag to a segment
+func addTagFilterFlag(cmd *cobra.Command, listFlag string) {
+	cmd.Flags().StringArray("tag", nil, fmt.Sprintf("Only include profiles with this tag (repeatable; every tag must match). Without --%s, every profile with the tags is used", listFlag))
+}
+
+// applyTagFilter narrows profiles to those with every tag. Without profiles,
+// every profile with the tags is returned, so a segment can be targeted by
+// tag alone.
+func applyTagFilter(db *storage.Database, profiles, tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return profiles, nil
+	}
+
+	tagged, err := db.GetTaggedProfiles(tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return tagged, nil
+	}
+
+	hasTags := make(map[string]bool, len(tagged))
+	for _, profileURL := range tagged {
+		hasTags[profileURL] = true
+	}
+	filtered := make([]string, 0, len(profiles))
+	for _, profileURL := range profiles {
+		if hasTags[profileurl.Canonicalize(profileURL)] {
+			filtered = append(filtered, profileURL)
+		}
+	}
+	return filtered, nil
+}
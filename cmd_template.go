@@ -0,0 +1,302 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/logger"
+	"linkedin-automation/personalize"
+	"linkedin-automation/storage"
+	"linkedin-automation/templates"
+)
+
+func createTemplateCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "template",
+		Short: "Manage connection and message templates",
+		Long:  `List, add, edit, delete and preview the templates used for connection notes and messages.`,
+	}
+
+	cmd.AddCommand(createTemplateListCmd())
+	cmd.AddCommand(createTemplateAddCmd())
+	cmd.AddCommand(createTemplateEditCmd())
+	cmd.AddCommand(createTemplateDeleteCmd())
+	cmd.AddCommand(createTemplatePreviewCmd())
+
+	return cmd
+}
+
+func createTemplateListCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "list",
+		Short: "List templates",
+		RunE:  runTemplateList,
+	}
+
+	cmd.Flags().String("kind", "", "Only list templates of this kind (connection, message or comment)")
+
+	return cmd
+}
+
+func createTemplateAddCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "add",
+		Short: "Add a template",
+		RunE:  runTemplateAdd,
+	}
+
+	addTemplateFlags(cmd)
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("kind")
+	cmd.MarkFlagRequired("content")
+
+	return cmd
+}
+
+func createTemplateEditCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "edit",
+		Short: "Edit a template (editing a built-in template stores an override)",
+		RunE:  runTemplateEdit,
+	}
+
+	addTemplateFlags(cmd)
+	cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+func createTemplateDeleteCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a stored template",
+		RunE:  runTemplateDelete,
+	}
+
+	cmd.Flags().String("name", "", "Template name")
+	cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+func createTemplatePreviewCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "preview",
+		Short: "Preview a template with variables filled in",
+		RunE:  runTemplatePreview,
+	}
+
+	cmd.Flags().String("name", "", "Template name")
+	cmd.Flags().String("kind", "", "Template kind (connection, message or comment)")
+	cmd.Flags().StringArray("var", nil, "Variable value as key=value (repeatable)")
+	cmd.Flags().String("profile", "", "Fill variables from this stored profile URL")
+	cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+func addTemplateFlags(cmd *cobra.Command) {
+	cmd.Flags().String("name", "", "Template name")
+	cmd.Flags().String("kind", "", "Template kind (connection, message or comment)")
+	cmd.Flags().String("content", "", "Template content, e.g. \"Hi {{name}}, ...\"")
+	cmd.Flags().String("type", "", "Template type (e.g. follow_up, introduction)")
+	cmd.Flags().String("variables", "", "Comma-separated variables the template uses (derived from content if empty)")
+	cmd.Flags().Int("limit", 0, "Character limit (defaults to 300 for connection notes)")
+}
+
+// openTemplateManager opens the database and returns a template manager backed by it
+func openTemplateManager() (*templates.Manager, *storage.Database, error) {
+	_, db, err := openDatabase()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return templates.NewManager(db, logger.GetLogger()), db, nil
+}
+
+func runTemplateList(cmd *cobra.Command, args []string) error {
+	kind, _ := cmd.Flags().GetString("kind")
+
+	manager, db, err := openTemplateManager()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	list, err := manager.List(kind)
+	if err != nil {
+		return fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	fmt.Printf("Templates\n")
+	fmt.Printf("=========\n\n")
+	for _, t := range list {
+		source := "stored"
+		if t.BuiltIn {
+			source = "built-in"
+		}
+		fmt.Printf("%s (%s, %s)\n", t.Name, t.Kind, source)
+		if t.Type != "" {
+			fmt.Printf("  Type: %s\n", t.Type)
+		}
+		if len(t.Variables) > 0 {
+			fmt.Printf("  Variables: %s\n", strings.Join(t.Variables, ", "))
+		}
+		if t.CharacterLimit > 0 {
+			fmt.Printf("  Limit: %d characters\n", t.CharacterLimit)
+		}
+		fmt.Printf("  %s\n\n", strings.ReplaceAll(t.Content, "\n", "\n  "))
+	}
+
+	return nil
+}
+
+func runTemplateAdd(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+
+	manager, db, err := openTemplateManager()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	existing, err := db.GetTemplate(name)
+	if err != nil {
+		return fmt.Errorf("failed to check template: %w", err)
+	}
+	if existing != nil {
+		return fmt.Errorf("template %q already exists, use 'template edit' to change it", name)
+	}
+
+	t := &storage.Template{Name: name}
+	applyTemplateFlags(cmd, t)
+
+	if err := manager.Save(t); err != nil {
+		return err
+	}
+
+	fmt.Printf("Template %q added\n", name)
+	return nil
+}
+
+func runTemplateEdit(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	kind, _ := cmd.Flags().GetString("kind")
+
+	manager, db, err := openTemplateManager()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	t, err := manager.Get(kind, name)
+	if err != nil {
+		return err
+	}
+
+	// Re-derive variables from the new content unless they are given explicitly
+	if cmd.Flags().Changed("content") && !cmd.Flags().Changed("variables") {
+		t.Variables = nil
+	}
+	applyTemplateFlags(cmd, t)
+	t.BuiltIn = false
+
+	if err := manager.Save(t); err != nil {
+		return err
+	}
+
+	fmt.Printf("Template %q updated\n", name)
+	return nil
+}
+
+func runTemplateDelete(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+
+	manager, db, err := openTemplateManager()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := manager.Delete(name); err != nil {
+		return err
+	}
+
+	fmt.Printf("Template %q deleted\n", name)
+	return nil
+}
+
+func runTemplatePreview(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	kind, _ := cmd.Flags().GetString("kind")
+	vars, _ := cmd.Flags().GetStringArray("var")
+	profileURL, _ := cmd.Flags().GetString("profile")
+
+	manager, db, err := openTemplateManager()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	t, err := manager.Get(kind, name)
+	if err != nil {
+		return err
+	}
+
+	variables := make(map[string]string)
+	if profileURL != "" {
+		data := personalize.NewPersonalizer(db, logger.GetLogger()).ProfileData(cmd.Context(), nil, profileURL)
+		for key, value := range data.Variables() {
+			variables[key] = value
+		}
+	}
+	for _, v := range vars {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid variable %q, expected key=value", v)
+		}
+		variables[strings.TrimSpace(parts[0])] = parts[1]
+	}
+
+	preview, err := personalize.Render(t.Content, variables)
+	if err != nil {
+		return err
+	}
+	length := len([]rune(preview))
+
+	fmt.Printf("%s\n\n", preview)
+	if t.CharacterLimit > 0 {
+		fmt.Printf("Length: %d/%d characters\n", length, t.CharacterLimit)
+		if length > t.CharacterLimit {
+			fmt.Printf("Warning: preview exceeds the character limit by %d characters\n", length-t.CharacterLimit)
+		}
+	} else {
+		fmt.Printf("Length: %d characters\n", length)
+	}
+
+	return nil
+}
+
+// applyTemplateFlags copies the template flags that were set onto t
+func applyTemplateFlags(cmd *cobra.Command, t *storage.Template) {
+	flags := cmd.Flags()
+
+	if flags.Changed("kind") {
+		t.Kind, _ = flags.GetString("kind")
+	}
+	if flags.Changed("content") {
+		t.Content, _ = flags.GetString("content")
+	}
+	if flags.Changed("type") {
+		t.Type, _ = flags.GetString("type")
+	}
+	if flags.Changed("variables") {
+		variables, _ := flags.GetString("variables")
+		t.Variables = parseCommaSeparated(variables)
+	}
+	if flags.Changed("limit") {
+		t.CharacterLimit, _ = flags.GetInt("limit")
+	}
+}
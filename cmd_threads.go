@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/message"
+	"linkedin-automation/personalize"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/storage"
+)
+
+func createGroupMessageCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "group",
+		Short: "Start a group conversation",
+		Long: `Start one conversation with several connections and send the first message.
+Template variables are not filled in, since the message goes to everyone in the
+conversation.`,
+		RunE: runGroupMessage,
+	}
+
+	cmd.Flags().String("recipients", "", "Comma-separated list of profile URLs (at least two)")
+	cmd.Flags().String("message", "", "Message to send")
+	cmd.MarkFlagRequired("recipients")
+	cmd.MarkFlagRequired("message")
+
+	return cmd
+}
+
+func createReplyCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "reply",
+		Short: "Reply into an existing conversation",
+		Long:  `Send a message into an existing one-to-one or group conversation, given its URL or ID as shown by message threads.`,
+		RunE:  runReply,
+	}
+
+	cmd.Flags().String("thread", "", "Conversation URL or ID")
+	cmd.Flags().String("message", "", "Message to send")
+	cmd.MarkFlagRequired("thread")
+	cmd.MarkFlagRequired("message")
+
+	return cmd
+}
+
+func createThreadsCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "threads",
+		Short: "List recent conversations",
+		Long:  `List recent conversations from the messaging sidebar with their IDs, for use with message reply.`,
+		RunE:  runThreads,
+	}
+
+	cmd.Flags().Int("limit", 20, "Maximum number of conversations to list")
+
+	return cmd
+}
+
+func runGroupMessage(cmd *cobra.Command, args []string) error {
+	recipients, _ := cmd.Flags().GetString("recipients")
+	content, _ := cmd.Flags().GetString("message")
+
+	if len(personalize.Placeholders(content)) > 0 {
+		return fmt.Errorf("group messages cannot use template variables")
+	}
+
+	recipientList := profileurl.Dedupe(parseCommaSeparated(recipients))
+	if len(recipientList) < 2 {
+		return fmt.Errorf("a group conversation needs at least two recipients")
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := cmd.Context()
+
+	browser, err := openBrowserSession(ctx, cfg, db)
+	if err != nil {
+		return err
+	}
+	defer browser.Close()
+
+	if err := browser.RateLimiter().WaitForPermission(ctx, ratelimit.ActionMessage); err != nil {
+		return err
+	}
+
+	result, err := newMessageManager(cfg, browser, db).SendGroupMessage(ctx, recipientList, content)
+	if err != nil {
+		return fmt.Errorf("failed to send group message: %w", err)
+	}
+	if result.Blacklisted {
+		return fmt.Errorf("group message not sent: %s", result.ErrorMessage)
+	}
+
+	saveThreadMessages(db, result, recipientList, "group")
+
+	if result.DryRun {
+		fmt.Printf("Dry run: would start a conversation with %s:\n", strings.Join(recipientList, ", "))
+		printIndented(result.Content)
+		return nil
+	}
+
+	fmt.Printf("Group message sent to %d recipients\n", len(recipientList))
+	if result.ThreadID != "" {
+		fmt.Printf("Conversation: %s\n", message.ThreadURL(result.ThreadID))
+	}
+
+	return nil
+}
+
+func runReply(cmd *cobra.Command, args []string) error {
+	thread, _ := cmd.Flags().GetString("thread")
+	content, _ := cmd.Flags().GetString("message")
+
+	if _, err := message.ParseThreadID(thread); err != nil {
+		return err
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := cmd.Context()
+
+	browser, err := openBrowserSession(ctx, cfg, db)
+	if err != nil {
+		return err
+	}
+	defer browser.Close()
+
+	if err := browser.RateLimiter().WaitForPermission(ctx, ratelimit.ActionMessage); err != nil {
+		return err
+	}
+
+	result, err := newMessageManager(cfg, browser, db).ReplyToThread(ctx, thread, content)
+	if err != nil {
+		return fmt.Errorf("failed to reply: %w", err)
+	}
+	if result.Blacklisted {
+		return fmt.Errorf("reply not sent: %s", result.ErrorMessage)
+	}
+
+	saveThreadMessages(db, result, []string{result.RecipientURL}, "reply")
+
+	if result.DryRun {
+		fmt.Printf("Dry run: would reply to %s:\n", result.RecipientURL)
+		printIndented(result.Content)
+		return nil
+	}
+
+	fmt.Printf("Reply sent to %s\n", result.RecipientURL)
+	return nil
+}
+
+func runThreads(cmd *cobra.Command, args []string) error {
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := cmd.Context()
+
+	browser, err := openBrowserSession(ctx, cfg, db)
+	if err != nil {
+		return err
+	}
+	defer browser.Close()
+
+	threads, err := newMessageManager(cfg, browser, db).ListThreads(ctx, limit)
+	if err != nil {
+		return fmt.Errorf("failed to list conversations: %w", err)
+	}
+
+	if len(threads) == 0 {
+		fmt.Println("No conversations found")
+		return nil
+	}
+
+	for _, thread := range threads {
+		kind := "direct"
+		if thread.Group {
+			kind = "group"
+		}
+		fmt.Printf("%s  [%s] %s\n", thread.ID, kind, thread.ParticipantName)
+		if thread.Snippet != "" {
+			fmt.Printf("    %s\n", strings.TrimSpace(thread.Snippet))
+		}
+	}
+
+	return nil
+}
+
+// saveThreadMessages records a message sent into a conversation once per recipient
+func saveThreadMessages(db *storage.Database, result *message.MessageResult, recipients []string, messageType string) {
+	for _, recipientURL := range recipients {
+		if err := db.SaveMessage(&storage.Message{
+			RecipientURL: recipientURL,
+			Content:      result.Content,
+			Type:         messageType,
+			Status:       "sent",
+			SentAt:       result.SentAt,
+			DryRun:       result.DryRun,
+		}); err != nil {
+			fmt.Printf("Warning: failed to store message for %s: %v\n", recipientURL, err)
+		}
+	}
+}
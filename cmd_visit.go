@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/client"
+	"linkedin-automation/logger"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/storage"
+	"linkedin-automation/visit"
+)
+
+func createVisitCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "visit",
+		Short: "Visit profiles",
+		Long:  `View LinkedIn profiles with human-like reading behaviour, e.g. as a soft touch before connecting.`,
+	}
+
+	cmd.AddCommand(createVisitProfilesCmd())
+	return cmd
+}
+
+func createVisitProfilesCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "profiles",
+		Short: "Open a list of profiles",
+		Long:  `Open each profile, scroll through it for a random dwell time and record the visit.`,
+		RunE:  runVisitProfiles,
+	}
+
+	cmd.Flags().String("profiles", "", "Comma-separated list of profile URLs")
+	cmd.Flags().Duration("min-dwell", 15*time.Second, "Shortest time spent on a profile")
+	cmd.Flags().Duration("max-dwell", 45*time.Second, "Longest time spent on a profile")
+	cmd.Flags().Duration("skip-visited-within", 0, "Skip profiles already visited within this period (e.g. 168h)")
+	cmd.Flags().String("batch-id", "", "Batch identifier for progress tracking (derived from the profile list if empty)")
+	cmd.Flags().Bool("resume", false, "Skip profiles already visited in a previous run of the same batch")
+	cmd.MarkFlagRequired("profiles")
+
+	return cmd
+}
+
+func runVisitProfiles(cmd *cobra.Command, args []string) error {
+	profiles, _ := cmd.Flags().GetString("profiles")
+	minDwell, _ := cmd.Flags().GetDuration("min-dwell")
+	maxDwell, _ := cmd.Flags().GetDuration("max-dwell")
+	skipWithin, _ := cmd.Flags().GetDuration("skip-visited-within")
+	batchID, _ := cmd.Flags().GetString("batch-id")
+	resume, _ := cmd.Flags().GetBool("resume")
+
+	if maxDwell < minDwell {
+		return fmt.Errorf("--max-dwell must not be shorter than --min-dwell")
+	}
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	profileList := profileurl.Dedupe(parseCommaSeparated(profiles))
+	if len(profileList) == 0 {
+		return fmt.Errorf("no profiles provided")
+	}
+
+	recentCount := 0
+	if skipWithin > 0 {
+		visited, err := db.GetVisitedProfiles(time.Now().Add(-skipWithin))
+		if err != nil {
+			return err
+		}
+		remaining := make([]string, 0, len(profileList))
+		for _, profileURL := range profileList {
+			if visited[profileURL] {
+				recentCount++
+				continue
+			}
+			remaining = append(remaining, profileURL)
+		}
+		profileList = remaining
+		if len(profileList) == 0 {
+			fmt.Printf("All %d profiles were visited within %s\n", recentCount, skipWithin)
+			return nil
+		}
+	}
+
+	if batchID == "" {
+		batchID = client.DeriveBatchID("visit", profileList)
+	}
+
+	ctx := cmd.Context()
+
+	browser, err := openBrowserSession(ctx, cfg, db)
+	if err != nil {
+		return err
+	}
+	defer browser.Close()
+
+	batch, err := newVisitManager(cfg, browser, db).BatchVisitProfiles(ctx, profileList, visit.BatchOptions{
+		BatchID:  batchID,
+		Resume:   resume,
+		MinDwell: minDwell,
+		MaxDwell: maxDwell,
+	})
+	var jobChanges int
+	if batch != nil {
+		if jobChanges, err = recordVisitResults(db, batch.Results); err != nil {
+			logger.GetLogger().WithError(err).Warn("Failed to store profile visits")
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("batch visit failed: %w", err)
+	}
+
+	successCount := 0
+	skippedCount := 0
+	for _, result := range batch.Results {
+		if result.Skipped {
+			skippedCount++
+		} else if result.Success {
+			successCount++
+		}
+	}
+
+	fmt.Printf("Profile visits completed!\n")
+	fmt.Printf("Batch ID: %s\n", batchID)
+	fmt.Printf("Total profiles: %d\n", len(profileList))
+	fmt.Printf("Visited: %d\n", successCount)
+	fmt.Printf("Skipped (already processed): %d\n", skippedCount)
+	if skipWithin > 0 {
+		fmt.Printf("Skipped (visited within %s): %d\n", skipWithin, recentCount)
+	}
+	failedCount := len(batch.Results) - successCount - skippedCount
+	fmt.Printf("Failed: %d\n", failedCount)
+	if jobChanges > 0 {
+		fmt.Printf("New titles or companies: %d (see 'analytics job-changes')\n", jobChanges)
+	}
+	if batch.StoppedAtLimit {
+		fmt.Printf("Stopped at limit: %s\n", batch.StopReason)
+		fmt.Printf("Not attempted: %d (re-run with --resume once the limit resets)\n", len(profileList)-len(batch.Results))
+	}
+
+	return batchExit("profile visits", failedCount, len(batch.Results), batch.StopErr)
+}
+
+// recordVisitResults stores completed visits and the profile details read
+// during them, returning how many profiles showed a new title or company
+func recordVisitResults(db *storage.Database, results []*visit.VisitResult) (int, error) {
+	jobChanges := 0
+	for _, result := range results {
+		if result.Skipped || !result.Success {
+			continue
+		}
+		if err := db.RecordProfileVisit(&storage.ProfileVisit{
+			ProfileURL: result.ProfileURL,
+			Dwell:      result.Dwell,
+			VisitedAt:  result.VisitedAt,
+		}); err != nil {
+			return jobChanges, err
+		}
+
+		if result.Profile == nil {
+			continue
+		}
+		profile := &storage.Profile{
+			URL:      result.ProfileURL,
+			Name:     result.Profile.Name,
+			Title:    result.Profile.Title,
+			Headline: result.Profile.Headline,
+			Company:  result.Profile.Company,
+			Location: result.Profile.Location,
+		}
+		// Compare what the page showed before stored details fill the gaps
+		changes, err := db.RecordProfileChanges(profile, result.VisitedAt)
+		if err != nil {
+			return jobChanges, err
+		}
+		if hasJobChange(changes) {
+			jobChanges++
+		}
+		// Keep details from search results that the profile page did not show
+		if err := mergeAndSaveProfile(db, profile); err != nil {
+			return jobChanges, err
+		}
+	}
+	return jobChanges, nil
+}
+
+// mergeAndSaveProfile saves a profile, keeping stored details for fields the
+// new data leaves empty. SaveProfile replaces the whole row, so this is needed
+// whenever only part of a profile was read.
+func mergeAndSaveProfile(db *storage.Database, profile *storage.Profile) error {
+	if existing, err := db.GetProfile(profile.URL); err == nil && existing != nil {
+		for _, field := range []struct{ scraped, stored *string }{
+			{&profile.Name, &existing.Name},
+			{&profile.Title, &existing.Title},
+			{&profile.Headline, &existing.Headline},
+			{&profile.Company, &existing.Company},
+			{&profile.Location, &existing.Location},
+			{&profile.SearchQuery, &existing.SearchQuery},
+		} {
+			if *field.scraped == "" {
+				*field.scraped = *field.stored
+			}
+		}
+	}
+	return db.SaveProfile(profile)
+}
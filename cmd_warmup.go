@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/config"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/storage"
+	"linkedin-automation/warmup"
+)
+
+func createWarmupCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "warmup",
+		Short: "Review the account warm-up",
+		Long: `With limits.warmup.enabled, a new or long idle account starts at low daily
+limits that grow each week until the configured limits apply. The warm-up
+starts with the account's first session and is kept in the database.`,
+	}
+
+	cmd.AddCommand(createWarmupStatusCmd())
+	cmd.AddCommand(createWarmupRestartCmd())
+	cmd.AddCommand(createWarmupFinishCmd())
+
+	return cmd
+}
+
+func createWarmupStatusCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show the warm-up week and this week's limits",
+		RunE:  runWarmupStatus,
+	}
+
+	cmd.Flags().String("account", "", "Show the warm-up of this entry under accounts")
+
+	return cmd
+}
+
+func createWarmupRestartCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "restart",
+		Short: "Start the warm-up over with the next session",
+		RunE:  runWarmupRestart,
+	}
+
+	cmd.Flags().String("account", "", "Restart the warm-up of this entry under accounts")
+
+	return cmd
+}
+
+func createWarmupFinishCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "finish",
+		Short: "End the warm-up early, applying the configured limits",
+		RunE:  runWarmupFinish,
+	}
+
+	cmd.Flags().String("account", "", "Finish the warm-up of this entry under accounts")
+
+	return cmd
+}
+
+// warmupStatus is how far the account has warmed up, as shown by 'status'
+// and 'warmup status'
+type warmupStatus struct {
+	*storage.Warmup
+	Weeks             int  `json:"weeks"`
+	Started           bool `json:"started"` // False until the account's first session
+	DailyConnections  int  `json:"daily_connections"`
+	DailyMessages     int  `json:"daily_messages"`
+	HourlyConnections int  `json:"hourly_connections"`
+}
+
+// currentQuotas returns the quotas in force for cfg's account at now, lowered
+// while it warms up, and how far it has, which is nil without a warm-up
+func currentQuotas(cfg *config.Config, db *storage.Database, now time.Time) (ratelimit.Config, *warmupStatus, error) {
+	quotas := cfg.RateLimiterConfig()
+	if !cfg.Limits.Warmup.Enabled {
+		return quotas, nil, nil
+	}
+
+	stored, err := db.GetWarmup(cfg.Account)
+	if err != nil {
+		return quotas, nil, err
+	}
+	progress := warmup.Advance(stored, cfg.Account, cfg.WarmupConfig(), now)
+	quotas = warmup.Limits(quotas, progress, cfg.WarmupConfig())
+	return quotas, &warmupStatus{
+		Warmup:            progress,
+		Weeks:             cfg.Limits.Warmup.Weeks,
+		Started:           stored != nil && stored.StartedAt.Equal(progress.StartedAt),
+		DailyConnections:  quotas.DailyConnects,
+		DailyMessages:     quotas.DailyMessages,
+		HourlyConnections: quotas.HourlyConnects,
+	}, nil
+}
+
+// warmupConfig opens the database for the account named by --account, or the
+// top-level one
+func warmupConfig(cmd *cobra.Command) (*config.Config, *storage.Database, error) {
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return nil, nil, err
+	}
+	if account, _ := cmd.Flags().GetString("account"); account != "" {
+		if cfg, err = cfg.ForAccount(account); err != nil {
+			db.Close()
+			return nil, nil, err
+		}
+	}
+	return cfg, db, nil
+}
+
+func runWarmupStatus(cmd *cobra.Command, args []string) error {
+	cfg, db, err := warmupConfig(cmd)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, status, err := currentQuotas(cfg, db, time.Now())
+	if err != nil {
+		return err
+	}
+	if jsonOutput {
+		return printJSON(status)
+	}
+
+	if status == nil {
+		fmt.Printf("Warm-up is off; set limits.warmup.enabled to start new accounts at low limits\n")
+		return nil
+	}
+	printWarmupStatus(status)
+	return nil
+}
+
+// printWarmupStatus prints the warm-up week and the limits it sets
+func printWarmupStatus(status *warmupStatus) {
+	switch {
+	case status.CompletedAt != nil:
+		fmt.Printf("  Complete since %s; the configured limits apply\n", status.CompletedAt.Local().Format("2006-01-02"))
+		return
+	case !status.Started:
+		fmt.Printf("  Starts with the next session\n")
+	default:
+		fmt.Printf("  Week %d of %d, started %s\n", status.Week, status.Weeks, status.StartedAt.Local().Format("2006-01-02"))
+	}
+	fmt.Printf("  Daily connections: %d\n", status.DailyConnections)
+	fmt.Printf("  Hourly connections: %d\n", status.HourlyConnections)
+	fmt.Printf("  Daily messages: %d\n", status.DailyMessages)
+}
+
+func runWarmupRestart(cmd *cobra.Command, args []string) error {
+	cfg, db, err := warmupConfig(cmd)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if _, err := db.DeleteWarmup(cfg.Account); err != nil {
+		return err
+	}
+	fmt.Printf("The warm-up starts over with the next session\n")
+	return nil
+}
+
+func runWarmupFinish(cmd *cobra.Command, args []string) error {
+	cfg, db, err := warmupConfig(cmd)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	now := time.Now()
+	progress, err := db.GetWarmup(cfg.Account)
+	if err != nil {
+		return err
+	}
+	if progress == nil {
+		progress = &storage.Warmup{Account: cfg.Account, StartedAt: now, Week: 1, LastActiveAt: now}
+	}
+	progress.CompletedAt = &now
+	if err := db.SaveWarmup(progress); err != nil {
+		return err
+	}
+	fmt.Printf("Warm-up finished; the configured limits apply\n")
+	return nil
+}
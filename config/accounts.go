@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// AccountConfig is one of several LinkedIn accounts a campaign spreads its
+// prospects over. Empty fields inherit the top-level settings.
+type AccountConfig struct {
+	Name       string        `yaml:"name"`
+	Email      string        `yaml:"email"`
+	Password   string        `yaml:"password"`
+	Proxy      string        `yaml:"proxy"`
+	SessionDir string        `yaml:"session_dir"` // Defaults to <browser.session_dir>/<name>
+	Limits     AccountLimits `yaml:"limits"`
+}
+
+// AccountLimits overrides the action limits for one account; zero keeps the
+// top-level limit
+type AccountLimits struct {
+	DailyConnections  int `yaml:"daily_connections"`
+	HourlyConnections int `yaml:"hourly_connections"`
+	WeeklyConnections int `yaml:"weekly_connections"`
+	DailyMessages     int `yaml:"daily_messages"`
+	HourlyMessages    int `yaml:"hourly_messages"`
+}
+
+// ForAccount returns a copy of the configuration that logs in as the named
+// account, with its proxy, session directory and limits applied
+func (c *Config) ForAccount(name string) (*Config, error) {
+	var account *AccountConfig
+	for i := range c.Accounts {
+		if c.Accounts[i].Name == name {
+			account = &c.Accounts[i]
+			break
+		}
+	}
+	if account == nil {
+		return nil, fmt.Errorf("account %q is not configured under accounts", name)
+	}
+
+	cfg := *c
+	cfg.Account = account.Name
+	cfg.LinkedIn.Email = account.Email
+	cfg.LinkedIn.Password = account.Password
+	// Cookies in the configuration belong to the top-level account
+	cfg.API.LiAt, cfg.API.JSessionID = "", ""
+
+	if account.Proxy != "" {
+		cfg.Browser.Proxy = account.Proxy
+	}
+	cfg.Browser.SessionDir = account.SessionDir
+	if cfg.Browser.SessionDir == "" {
+		cfg.Browser.SessionDir = filepath.Join(c.Browser.SessionDir, account.Name)
+	}
+
+	if account.Limits.DailyConnections > 0 {
+		cfg.Limits.DailyConnections = account.Limits.DailyConnections
+	}
+	if account.Limits.HourlyConnections > 0 {
+		cfg.Limits.HourlyConnections = account.Limits.HourlyConnections
+	}
+	if account.Limits.WeeklyConnections > 0 {
+		cfg.Limits.WeeklyConnections = account.Limits.WeeklyConnections
+	}
+	if account.Limits.DailyMessages > 0 {
+		cfg.Limits.DailyMessages = account.Limits.DailyMessages
+	}
+	if account.Limits.HourlyMessages > 0 {
+		cfg.Limits.HourlyMessages = account.Limits.HourlyMessages
+	}
+
+	return &cfg, nil
+}
+
+// validateAccounts checks that every account can log in and has a unique name
+func validateAccounts(accounts []AccountConfig) error {
+	seen := make(map[string]bool, len(accounts))
+	for i, account := range accounts {
+		if account.Name == "" {
+			return fmt.Errorf("accounts[%d].name is required", i)
+		}
+		if seen[account.Name] {
+			return fmt.Errorf("account %q is configured twice", account.Name)
+		}
+		seen[account.Name] = true
+		if account.Email == "" {
+			return fmt.Errorf("account %q needs an email", account.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,437 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// masked replaces secrets in the configuration printed by 'config check'
+const masked = "********"
+
+// CheckResult is what checking a configuration found
+type CheckResult struct {
+	Config      *Config // Effective configuration; nil when it could not be decoded
+	Errors      []string
+	Warnings    []string // Unknown keys and settings that work but are probably unintended
+	Credentials []Credential
+}
+
+// Credential tells where a secret the configuration needs is read from
+type Credential struct {
+	Key    string
+	Source string // "environment (VAR)", "config file" or "missing"
+}
+
+// Check reads the configuration like LoadConfig, but reports every problem
+// instead of the first, warns about keys the file sets that no setting reads
+// and never creates a missing file
+func Check(configPath string) *CheckResult {
+	result := &CheckResult{}
+
+	if err := readConfig(configPath, false); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+
+	if data, err := os.ReadFile(configPath); err == nil {
+		var raw map[string]interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("failed to parse config file: %v", err))
+			return result
+		}
+		for _, key := range unknownKeys("", raw, reflect.TypeOf(Config{})) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("unknown key %s is ignored", key))
+		}
+	}
+
+	config, err := decodeConfig()
+	if err != nil {
+		var decodeErr *mapstructure.Error
+		if errors.As(err, &decodeErr) {
+			result.Errors = append(result.Errors, decodeErr.Errors...)
+		} else {
+			result.Errors = append(result.Errors, err.Error())
+		}
+		return result
+	}
+	result.Config = config
+
+	for _, problem := range checkConfig(config) {
+		result.Errors = append(result.Errors, problem.Error())
+	}
+	result.Warnings = append(result.Warnings, checkUnlimited(config)...)
+	result.Credentials = credentials(config)
+
+	return result
+}
+
+// checkRanges reports settings outside their range and minimums above maximums
+func checkRanges(config *Config) []error {
+	var problems []error
+	fail := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+	minMax := func(key string, min, max float64) {
+		if min > max {
+			fail("%s: min must not be above max", key)
+		}
+	}
+	probability := func(key string, value float64) {
+		if value < 0 || value > 1 {
+			fail("%s must be between 0 and 1", key)
+		}
+	}
+
+	schedule := config.Stealth.Schedule
+	if schedule.StartHour < 0 || schedule.StartHour > 23 {
+		fail("stealth.schedule.start_hour must be between 0 and 23")
+	}
+	if schedule.EndHour < 1 || schedule.EndHour > 24 {
+		fail("stealth.schedule.end_hour must be between 1 and 24")
+	}
+	if (schedule.BusinessHoursOnly || schedule.ProspectTimezone) && schedule.StartHour >= schedule.EndHour {
+		fail("stealth.schedule.start_hour must be before end_hour")
+	}
+	if _, err := time.LoadLocation(schedule.Timezone); err != nil {
+		fail("stealth.schedule.timezone: %w", err)
+	}
+
+	timing := config.Stealth.Timing
+	minMax("stealth.timing.min_delay/max_delay", float64(timing.MinDelay), float64(timing.MaxDelay))
+	typing := config.Stealth.Typing
+	minMax("stealth.typing.min_speed/max_speed", float64(typing.MinSpeed), float64(typing.MaxSpeed))
+	probability("stealth.typing.typo_rate", typing.TypoRate)
+	mouse := config.Stealth.MouseMovement
+	minMax("stealth.mouse_movement.min_speed/max_speed", mouse.MinSpeed, mouse.MaxSpeed)
+	probability("stealth.mouse_movement.idle_probability", mouse.IdleProbability)
+	scrolling := config.Stealth.Scrolling
+	minMax("stealth.scrolling.min_speed/max_speed", float64(scrolling.MinSpeed), float64(scrolling.MaxSpeed))
+	fingerprint := config.Stealth.Fingerprint
+	minMax("stealth.fingerprint.min_viewport_width/max_viewport_width", float64(fingerprint.MinViewportWidth), float64(fingerprint.MaxViewportWidth))
+	minMax("stealth.fingerprint.min_viewport_height/max_viewport_height", float64(fingerprint.MinViewportHeight), float64(fingerprint.MaxViewportHeight))
+	warmUp := config.Stealth.WarmUp
+	minMax("stealth.warm_up.min_duration/max_duration", float64(warmUp.MinDuration), float64(warmUp.MaxDuration))
+	probability("stealth.warm_up.probability", warmUp.Probability)
+	probability("stealth.warm_up.notification_probability", warmUp.NotificationProbability)
+	reading := config.Stealth.Reading
+	minMax("stealth.reading.min_dwell/max_dwell", float64(reading.MinDwell), float64(reading.MaxDwell))
+	if reading.WordsPerMinute < 0 {
+		fail("stealth.reading.words_per_minute must not be negative")
+	}
+	navigation := config.Stealth.Navigation
+	if navigation.Mode != "direct" && navigation.Mode != "click" {
+		fail("stealth.navigation.mode must be direct or click")
+	}
+	for command, mode := range navigation.Commands {
+		if mode != "direct" && mode != "click" {
+			fail("stealth.navigation.commands.%s must be direct or click", command)
+		}
+	}
+
+	// Durations of rate_limit are strings that ToRateLimitConfig would quietly read as 0
+	delays := make(map[string]time.Duration)
+	rateLimit := config.RateLimit
+	for key, value := range map[string]string{
+		"min_delay": rateLimit.MinDelay, "max_delay": rateLimit.MaxDelay, "search_delay": rateLimit.SearchDelay,
+		"connect_delay": rateLimit.ConnectDelay, "message_delay": rateLimit.MessageDelay,
+		"visit_delay": rateLimit.VisitDelay, "burst_window": rateLimit.BurstWindow,
+	} {
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			fail("rate_limit.%s: %w", key, err)
+			continue
+		}
+		delays[key] = d
+	}
+	minMax("rate_limit.min_delay/max_delay", float64(delays["min_delay"]), float64(delays["max_delay"]))
+	if rateLimit.JitterPercent < 0 || rateLimit.JitterPercent > 100 {
+		fail("rate_limit.jitter_percent must be between 0 and 100")
+	}
+
+	limits := map[string]int{
+		"limits.daily_messages": config.Limits.DailyMessages, "limits.hourly_messages": config.Limits.HourlyMessages,
+		"limits.daily_likes": config.Limits.DailyLikes, "limits.daily_comments": config.Limits.DailyComments,
+		"limits.daily_endorsements": config.Limits.DailyEndorsements, "limits.search_results": config.Limits.SearchResults,
+		"limits.weekly_connections": config.Limits.WeeklyConnections,
+		"rate_limit.daily_searches": rateLimit.DailySearches, "rate_limit.daily_visits": rateLimit.DailyVisits,
+		"rate_limit.hourly_searches": rateLimit.HourlySearches, "rate_limit.hourly_visits": rateLimit.HourlyVisits,
+		"rate_limit.burst_limit": rateLimit.BurstLimit,
+	}
+	for _, key := range sortedKeys(limits) {
+		if limits[key] < 0 {
+			fail("%s must not be negative", key)
+		}
+	}
+	if config.Limits.HourlyConnections > config.Limits.DailyConnections {
+		fail("limits.hourly_connections must not be above daily_connections")
+	}
+	if config.Limits.HourlyMessages > config.Limits.DailyMessages && config.Limits.DailyMessages > 0 {
+		fail("limits.hourly_messages must not be above daily_messages")
+	}
+	if warmup := config.Limits.Warmup; warmup.Enabled {
+		if warmup.Weeks < 1 {
+			fail("limits.warmup.weeks must be at least 1")
+		}
+		if warmup.StartConnections < 1 || warmup.StartMessages < 1 {
+			fail("limits.warmup.start_connections and start_messages must be at least 1")
+		}
+		if warmup.RestartAfter < 0 {
+			fail("limits.warmup.restart_after must not be negative")
+		}
+	}
+
+	waits := config.Browser.Waits
+	if waits.ElementTimeout < 0 || waits.PollInterval < 0 || waits.Settle < 0 {
+		fail("browser.waits durations must not be negative")
+	}
+	if waits.PollInterval > waits.ElementTimeout {
+		fail("browser.waits.poll_interval must not be above element_timeout")
+	}
+	if config.Restrictions.Cooldown < 0 {
+		fail("restrictions.cooldown must not be negative")
+	}
+	if webhook := config.Restrictions.WebhookURL; webhook != "" && !strings.HasPrefix(webhook, "http://") && !strings.HasPrefix(webhook, "https://") {
+		fail("restrictions.webhook_url must be an http or https URL")
+	}
+	sessionCheck := config.Browser.SessionCheck
+	if sessionCheck.Interval < 0 || sessionCheck.RetryDelay < 0 || sessionCheck.Attempts < 0 {
+		fail("browser.session_check values must not be negative")
+	}
+
+	retry := config.Retry
+	minMax("retry.initial_delay/max_delay", float64(retry.InitialDelay), float64(retry.MaxDelay))
+	if retry.Multiplier < 1 {
+		fail("retry.multiplier must be at least 1")
+	}
+
+	recording := config.Recording
+	if recording.Quality < 0 || recording.Quality > 100 {
+		fail("recording.quality must be between 0 and 100")
+	}
+	if recording.Format != "images" && recording.Format != "webm" {
+		fail("recording.format must be images or webm")
+	}
+	if recording.EveryNthFrame < 1 {
+		fail("recording.every_nth_frame must be at least 1")
+	}
+
+	return problems
+}
+
+// checkUnlimited warns about action limits set to 0, which the rate limiter
+// reads as no limit at all
+func checkUnlimited(config *Config) []string {
+	limits := map[string]int{
+		"limits.daily_messages": config.Limits.DailyMessages, "limits.hourly_messages": config.Limits.HourlyMessages,
+		"limits.daily_likes": config.Limits.DailyLikes, "limits.daily_comments": config.Limits.DailyComments,
+		"limits.daily_endorsements": config.Limits.DailyEndorsements, "limits.weekly_connections": config.Limits.WeeklyConnections,
+		"rate_limit.daily_searches": config.RateLimit.DailySearches, "rate_limit.daily_visits": config.RateLimit.DailyVisits,
+		"rate_limit.hourly_searches": config.RateLimit.HourlySearches, "rate_limit.hourly_visits": config.RateLimit.HourlyVisits,
+	}
+
+	var warnings []string
+	for _, key := range sortedKeys(limits) {
+		if limits[key] == 0 {
+			warnings = append(warnings, fmt.Sprintf("%s is 0, which means no limit", key))
+		}
+	}
+	return warnings
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// unknownKeys returns the keys of a decoded YAML value that no field of t reads
+func unknownKeys(path string, value interface{}, t reflect.Type) []string {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return unknownKeys(path, value, t.Elem())
+
+	case reflect.Slice:
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil // Wrong types are reported when decoding
+		}
+		var unknown []string
+		for i, item := range items {
+			unknown = append(unknown, unknownKeys(fmt.Sprintf("%s[%d]", path, i), item, t.Elem())...)
+		}
+		return unknown
+
+	case reflect.Struct:
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		fields := make(map[string]reflect.Type, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+			if name != "" && name != "-" {
+				fields[name] = t.Field(i).Type
+			}
+		}
+
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var unknown []string
+		for _, key := range keys {
+			child := key
+			if path != "" {
+				child = path + "." + key
+			}
+			// viper matches keys without regard to case
+			field, ok := fields[strings.ToLower(key)]
+			if !ok {
+				unknown = append(unknown, child)
+				continue
+			}
+			unknown = append(unknown, unknownKeys(child, values[key], field)...)
+		}
+		return unknown
+	}
+
+	return nil
+}
+
+// credentials lists the secrets the configuration uses and where each is read from
+func credentials(config *Config) []Credential {
+	list := []Credential{
+		{"linkedin.email", credentialSource("linkedin.email", "LINKEDIN_EMAIL")},
+		{"linkedin.password", credentialSource("linkedin.password", "LINKEDIN_PASSWORD")},
+	}
+	if config.Captcha.Provider != "" {
+		list = append(list, Credential{"captcha.api_key", credentialSource("captcha.api_key", "CAPTCHA_API_KEY")})
+	}
+	if config.IMAP.Host != "" {
+		list = append(list, Credential{"imap.password", credentialSource("imap.password", "IMAP_PASSWORD")})
+	}
+	if config.Integrations.HubSpot.Enabled {
+		list = append(list, Credential{"integrations.hubspot.api_key", credentialSource("integrations.hubspot.api_key", "HUBSPOT_API_KEY")})
+	}
+	if config.Integrations.Pipedrive.Enabled {
+		list = append(list, Credential{"integrations.pipedrive.api_token", credentialSource("integrations.pipedrive.api_token", "PIPEDRIVE_API_TOKEN")})
+	}
+	names, _ := config.Enrich.EmailFinders()
+	for _, name := range names {
+		key := "enrich." + name + ".api_key"
+		list = append(list, Credential{key, credentialSource(key, strings.ToUpper(name)+"_API_KEY")})
+	}
+	if env := config.AI.APIKeyEnv(); env != "" {
+		list = append(list, Credential{"ai.api_key", credentialSource("ai.api_key", env)})
+	}
+	if config.Web.Token != "" {
+		list = append(list, Credential{"web.token", credentialSource("web.token", "")})
+	}
+	for _, account := range config.Accounts {
+		source := "config file"
+		if account.Email == "" || account.Password == "" {
+			source = "missing"
+		}
+		list = append(list, Credential{"accounts." + account.Name, source})
+	}
+	return list
+}
+
+// credentialSource tells where viper found a secret: the variable overrideFromEnv
+// reads, the file, or the LINKEDIN_<SECTION>_<KEY> variable
+func credentialSource(key, envVar string) string {
+	switch {
+	case viper.GetString(key) == "":
+		return "missing"
+	case envVar != "" && os.Getenv(envVar) != "":
+		return "environment (" + envVar + ")"
+	case viper.InConfig(key):
+		return "config file"
+	}
+	return "environment (LINKEDIN_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_")) + ")"
+}
+
+// userinfoPattern matches the password of a URL or a user:password@host DSN
+var userinfoPattern = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.-]*://)?([^:@/]*):([^@]*)@`)
+
+// Masked returns a copy of the configuration with passwords, keys, tokens and
+// cookies replaced, safe to print or attach to a bug report
+func (c *Config) Masked() *Config {
+	cfg := *c
+
+	mask := func(value *string) {
+		if *value != "" {
+			*value = masked
+		}
+	}
+	mask(&cfg.LinkedIn.Password)
+	mask(&cfg.Captcha.APIKey)
+	mask(&cfg.IMAP.Password)
+	mask(&cfg.API.LiAt)
+	mask(&cfg.API.JSessionID)
+	mask(&cfg.Integrations.HubSpot.APIKey)
+	mask(&cfg.Integrations.Pipedrive.APIToken)
+	mask(&cfg.Enrich.Hunter.APIKey)
+	mask(&cfg.Enrich.Apollo.APIKey)
+	mask(&cfg.Enrich.Dropcontact.APIKey)
+	mask(&cfg.AI.APIKey)
+	mask(&cfg.Web.Token)
+	cfg.Storage.DSN = maskUserinfo(cfg.Storage.DSN)
+	cfg.Browser.Proxy = maskUserinfo(cfg.Browser.Proxy)
+	cfg.Browser.RemoteDebuggingURL = maskQueryToken(cfg.Browser.RemoteDebuggingURL)
+	if cfg.Restrictions.WebhookURL != "" {
+		// Chat webhooks carry their secret in the path
+		if u, err := url.Parse(cfg.Restrictions.WebhookURL); err == nil {
+			cfg.Restrictions.WebhookURL = u.Scheme + "://" + u.Host + "/" + masked
+		}
+	}
+
+	cfg.Accounts = make([]AccountConfig, len(c.Accounts))
+	for i, account := range c.Accounts {
+		mask(&account.Password)
+		account.Proxy = maskUserinfo(account.Proxy)
+		cfg.Accounts[i] = account
+	}
+
+	return &cfg
+}
+
+// maskUserinfo hides the password in a proxy URL or database DSN
+func maskUserinfo(value string) string {
+	return userinfoPattern.ReplaceAllString(value, "${1}${2}:"+masked+"@")
+}
+
+// maskQueryToken hides the token parameter of a remote debugging URL
+func maskQueryToken(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.Query().Get("token") == "" {
+		return value
+	}
+	query := u.Query()
+	query.Set("token", masked)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
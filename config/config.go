@@ -1,14 +1,24 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"regexp"
+	"strings"
 	"time"
 
+	"github.com/mitchellh/mapstructure"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
 	
+	"linkedin-automation/pause"
 	"linkedin-automation/ratelimit"
+	"linkedin-automation/retry"
+	"linkedin-automation/scoring"
+	"linkedin-automation/uitext"
+	"linkedin-automation/warmup"
 )
 
 // Config represents the application configuration
@@ -20,15 +30,151 @@ type Config struct {
 	RateLimit  RateLimitConfig  `yaml:"rate_limit"`
 	Storage    StorageConfig    `yaml:"storage"`
 	Logging    LoggingConfig    `yaml:"logging"`
+	Integrations IntegrationsConfig `yaml:"integrations"`
+	Enrich     EnrichConfig     `yaml:"enrich"`
+	Scoring    ScoringConfig    `yaml:"scoring"`
+	AI         AIConfig         `yaml:"ai"`
+	API        APIConfig        `yaml:"api"`
+	Captcha    CaptchaConfig    `yaml:"captcha"`
+	IMAP       IMAPConfig       `yaml:"imap"`
+	Retry      RetryConfig      `yaml:"retry"`
+	Inbox      InboxConfig      `yaml:"inbox"`
+	Invitations InvitationsConfig `yaml:"invitations"`
+	Endorse    EndorseConfig    `yaml:"endorse"`
+	Connect    ConnectConfig    `yaml:"connect"`
+	Sequences  SequencesConfig  `yaml:"sequences"`
+	Audit      AuditConfig      `yaml:"audit"`
+	Capture    CaptureConfig    `yaml:"capture"`
+	Recording  RecordingConfig  `yaml:"recording"`
+	Accounts   []AccountConfig  `yaml:"accounts"`
+	Web        WebConfig        `yaml:"web"`
+	Restrictions RestrictionsConfig `yaml:"restrictions"`
+	Retention  RetentionConfig  `yaml:"retention"`
+	Reminders  RemindersConfig  `yaml:"reminders"`
+
+	Account    string           `yaml:"-"` // Set by ForAccount to the account this configuration is for
+}
+
+// RetryConfig controls how batch operations retry transient failures such as
+// slow pages or dialogs that did not open. Limits and expired sessions are
+// never retried.
+type RetryConfig struct {
+	MaxAttempts  int           `yaml:"max_attempts"` // Tries per item including the first
+	InitialDelay time.Duration `yaml:"initial_delay"`
+	MaxDelay     time.Duration `yaml:"max_delay"`
+	Multiplier   float64       `yaml:"multiplier"`
+}
+
+// InboxConfig contains settings for inbox syncs
+type InboxConfig struct {
+	OptOutPhrases []string `yaml:"opt_out_phrases"` // Replies containing one of these blacklist the sender; empty disables
+	Classify      ReplyClassifyConfig `yaml:"classify"`
+}
+
+// ReplyClassifyConfig contains the phrases that label new replies as
+// interested, not interested or later. Not interested is checked first, then
+// later, then interested.
+type ReplyClassifyConfig struct {
+	Enabled       bool          `yaml:"enabled"`
+	Interested    []string      `yaml:"interested"`
+	NotInterested []string      `yaml:"not_interested"`
+	Later         []string      `yaml:"later"`
+	RevisitAfter  time.Duration `yaml:"revisit_after"` // How long until a prospect who replied later is contacted again
+	UseAI         bool          `yaml:"use_ai"`        // Ask the ai provider about replies no phrase matches
+}
+
+// InvitationsConfig contains the rules 'invitations process' applies to
+// incoming connection invitations. The first matching rule decides; unmatched
+// invitations are left pending.
+type InvitationsConfig struct {
+	Rules []InvitationRule `yaml:"rules"`
+}
+
+// InvitationRule accepts or ignores invitations matching all of its conditions
+type InvitationRule struct {
+	Action      string `yaml:"action"`       // "accept" or "ignore"
+	Headline    string `yaml:"headline"`     // Regular expression matched against the sender's headline
+	Name        string `yaml:"name"`         // Regular expression matched against the sender's name
+	NoPhoto     bool   `yaml:"no_photo"`     // Only senders without a profile photo
+	MinMutual   int    `yaml:"min_mutual"`   // Only senders with at least this many mutual connections
+	WithMessage bool   `yaml:"with_message"` // Only invitations that include a note
+}
+
+// EndorseConfig contains the skills the endorse command endorses
+type EndorseConfig struct {
+	Skills    []string `yaml:"skills"`     // Skills to endorse, in order of preference; empty endorses the top listed skills
+	MaxSkills int      `yaml:"max_skills"` // Skills endorsed per connection
+}
+
+// ConnectConfig contains what connection requests do on profiles without a
+// Connect button, such as creator-mode profiles that lead with Follow
+type ConnectConfig struct {
+	Fallbacks          []string `yaml:"fallbacks"`            // Tried in order: "follow" and/or "message" (open profiles only); empty fails as before
+	OpenProfileSubject string   `yaml:"open_profile_subject"` // Subject line of open profile messages; may use template variables
+}
+
+// SequencesConfig contains settings for drip sequences
+type SequencesConfig struct {
+	Dir          string        `yaml:"dir"`           // Directory of sequence definitions (*.yaml)
+	SyncInterval time.Duration `yaml:"sync_interval"` // How often 'queue run' syncs acceptances and replies while sequences run; 0 disables
+	InboxLimit   int           `yaml:"inbox_limit"`   // Conversations scanned by each of those syncs
+}
+
+// AuditConfig controls the log of every navigation, click and keystroke batch
+// the browser performs
+type AuditConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	File    string `yaml:"file"` // JSONL file to write instead of the database's audit_log table
+}
+
+// CaptureConfig controls the screenshots and HTML snapshots saved to debug
+// failed actions and broken selectors
+type CaptureConfig struct {
+	Dir     string `yaml:"dir"`
+	OnError bool   `yaml:"on_error"` // Capture the page whenever an action fails
+	All     bool   `yaml:"all"`      // Capture every navigation and click too, like --capture-all
+}
+
+// RecordingConfig controls the screencast saved of each browser session
+type RecordingConfig struct {
+	Enabled       bool   `yaml:"enabled"`
+	Dir           string `yaml:"dir"`             // Each run gets its own directory below this one
+	Format        string `yaml:"format"`          // "images" (JPEG frames) or "webm" (needs ffmpeg)
+	Quality       int    `yaml:"quality"`         // JPEG quality of the frames, 0-100
+	MaxWidth      int    `yaml:"max_width"`       // Frames are scaled down to fit
+	MaxHeight     int    `yaml:"max_height"`
+	EveryNthFrame int    `yaml:"every_nth_frame"` // Keep one of every n rendered frames
+}
+
+// CaptchaConfig selects a solver service for login CAPTCHAs. Without a
+// provider, challenges must be solved by hand in a visible browser window.
+type CaptchaConfig struct {
+	Provider string        `yaml:"provider"` // "2captcha" or "anticaptcha"
+	APIKey   string        `yaml:"api_key"`
+	Timeout  time.Duration `yaml:"timeout"` // How long to wait for a solution
+}
+
+// IMAPConfig points at the mailbox receiving the account's LinkedIn emails,
+// so checkpoint verification codes can be entered automatically
+type IMAPConfig struct {
+	Host     string        `yaml:"host"` // Empty disables the integration
+	Port     int           `yaml:"port"`
+	Username string        `yaml:"username"`
+	Password string        `yaml:"password"`
+	Mailbox  string        `yaml:"mailbox"`
+	Timeout  time.Duration `yaml:"timeout"` // How long to wait for the email
 }
 
 // LinkedInConfig contains LinkedIn-specific settings
 type LinkedInConfig struct {
-	Email      string `yaml:"email"`
-	Password   string `yaml:"password"`
-	BaseURL    string `yaml:"base_url"`
-	LoginURL   string `yaml:"login_url"`
-	SearchURL  string `yaml:"search_url"`
+	Email      string              `yaml:"email"`
+	Password   string              `yaml:"password"`   // Optional once a session cookie is imported
+	BaseURL    string              `yaml:"base_url"`
+	LoginURL   string              `yaml:"login_url"`
+	SearchURL  string              `yaml:"search_url"`
+	UILanguage string              `yaml:"ui_language"` // Language the account uses LinkedIn in, e.g. de; English wording is always tried too
+	UIText     map[string][]string `yaml:"ui_text"`     // Replaces the built-in wording of a button or label, or supplies it for another language
+	Premium    bool                `yaml:"premium"`     // The account has Premium, which allows 300 character connection notes instead of 200
 }
 
 // BrowserConfig contains browser automation settings
@@ -41,6 +187,29 @@ type BrowserConfig struct {
 	ExecutablePath    string        `yaml:"executable_path"`
 	ProfileDir        string        `yaml:"profile_dir"`
 	DisableWebSecurity bool         `yaml:"disable_web_security"`
+	SelectorsFile     string        `yaml:"selectors_file"` // YAML overrides for page selectors; ignored if missing
+	Proxy             string        `yaml:"proxy"`          // e.g. http://host:port or socks5://host:port
+	RemoteDebuggingURL string       `yaml:"remote_debugging_url"` // Attach to a running browser, e.g. ws://chrome:3000?token=... or http://localhost:9222
+	Container         bool          `yaml:"container"`      // Launch with flags that work inside Docker: headless, no sandbox
+	SessionDir        string        `yaml:"session_dir"`    // Where browser profiles and session data are kept
+	Waits             WaitsConfig   `yaml:"waits"`
+	SessionCheck      SessionCheckConfig `yaml:"session_check"`
+}
+
+// SessionCheckConfig controls how a running batch or the queue notices that
+// LinkedIn logged the account out, and how it logs in again
+type SessionCheckConfig struct {
+	Interval   time.Duration `yaml:"interval"`    // Between background checks of the session; 0 disables them
+	Attempts   int           `yaml:"attempts"`    // Logins to try before stopping; 0 stops at once
+	RetryDelay time.Duration `yaml:"retry_delay"` // Pause between login attempts, e.g. to import a fresh cookie
+}
+
+// WaitsConfig controls how long browser steps wait for the page; raise them
+// for slow connections or machines
+type WaitsConfig struct {
+	ElementTimeout time.Duration `yaml:"element_timeout"` // How long to wait for an element before giving up
+	PollInterval   time.Duration `yaml:"poll_interval"`   // How often to look for it meanwhile
+	Settle         time.Duration `yaml:"settle"`          // Pause after a click or page change for the page to update
 }
 
 // StealthConfig contains anti-bot detection settings
@@ -52,6 +221,56 @@ type StealthConfig struct {
 	Scrolling         ScrollingConfig       `yaml:"scrolling"`
 	Schedule          ScheduleConfig        `yaml:"schedule"`
 	Fingerprint       FingerprintConfig     `yaml:"fingerprint"`
+	Locale            LocaleConfig          `yaml:"locale"`
+	WarmUp            WarmUpConfig          `yaml:"warm_up"`
+	Reading           ReadingConfig         `yaml:"reading"`
+	Navigation        NavigationConfig      `yaml:"navigation"`
+}
+
+// NavigationConfig controls how profiles are opened: "direct" navigates to
+// their URL, "click" searches for the prospect and clicks their result
+type NavigationConfig struct {
+	Mode     string            `yaml:"mode"`
+	Commands map[string]string `yaml:"commands"` // Mode per command, e.g. "connect" or "message send"
+}
+
+// ModeFor returns the navigation mode of command, a command path such as
+// "connect to-profiles", falling back from the full path to its first word
+// and then to Mode
+func (n NavigationConfig) ModeFor(command string) string {
+	if mode, ok := n.Commands[command]; ok {
+		return mode
+	}
+	if first, _, found := strings.Cut(command, " "); found {
+		if mode, ok := n.Commands[first]; ok {
+			return mode
+		}
+	}
+	return n.Mode
+}
+
+// ReadingConfig controls how long profiles are read before connecting or
+// messaging, in proportion to the length of their about and experience sections
+type ReadingConfig struct {
+	WordsPerMinute int           `yaml:"words_per_minute"` // 0 pauses a random delay instead
+	MinDwell       time.Duration `yaml:"min_dwell"`
+	MaxDwell       time.Duration `yaml:"max_dwell"`
+}
+
+// WarmUpConfig controls feed browsing before connect and message batches
+type WarmUpConfig struct {
+	Probability             float64       `yaml:"probability"` // 0 disables warm-up
+	MinDuration             time.Duration `yaml:"min_duration"`
+	MaxDuration             time.Duration `yaml:"max_duration"`
+	NotificationProbability float64       `yaml:"notification_probability"`
+}
+
+// LocaleConfig sets the timezone and language the browser presents. Explicit
+// timezone and locale values override those derived from the region.
+type LocaleConfig struct {
+	Region   string `yaml:"region"`   // ISO country code, or "auto" to geo-locate the proxy or public IP
+	Timezone string `yaml:"timezone"` // IANA timezone, e.g. Europe/Berlin
+	Locale   string `yaml:"locale"`   // Language tag, e.g. de-DE
 }
 
 // MouseMovementConfig for realistic mouse behavior
@@ -83,6 +302,9 @@ type TypingConfig struct {
 	CorrectionDelay   time.Duration `yaml:"correction_delay"`
 	MinSpeed          time.Duration `yaml:"min_speed"`
 	MaxSpeed          time.Duration `yaml:"max_speed"`
+	MaxComposeTime    time.Duration `yaml:"max_compose_time"`
+	ReviewPause       time.Duration `yaml:"review_pause"`
+	ReadDelay         time.Duration `yaml:"read_delay"`
 }
 
 // ScrollingConfig for realistic scrolling behavior
@@ -97,12 +319,16 @@ type ScrollingConfig struct {
 
 // ScheduleConfig for activity scheduling
 type ScheduleConfig struct {
-	BusinessHoursOnly bool          `yaml:"business_hours_only"`
+	BusinessHoursOnly bool          `yaml:"business_hours_only"` // Run queued tasks only from start_hour to end_hour on weekdays in timezone
 	StartHour         int           `yaml:"start_hour"`
 	EndHour           int           `yaml:"end_hour"`
 	BreakDuration     time.Duration `yaml:"break_duration"`
 	BreakFrequency    time.Duration `yaml:"break_frequency"`
 	Timezone          string        `yaml:"timezone"`
+	ProspectTimezone  bool          `yaml:"prospect_timezone"` // Time connection requests and messages to start_hour-end_hour in the prospect's timezone, from their location
+	MaxSessionDuration time.Duration `yaml:"max_session_duration"` // Active time per browser session; 0 is unlimited
+	MaxSessionActions  int           `yaml:"max_session_actions"`  // Actions per browser session; 0 is unlimited
+	OnSessionLimit     string        `yaml:"on_session_limit"`     // "break" to rest and continue, "exit" to stop
 }
 
 // FingerprintConfig for browser fingerprint masking
@@ -114,16 +340,36 @@ type FingerprintConfig struct {
 	MinViewportHeight int      `yaml:"min_viewport_height"`
 	MaxViewportHeight int      `yaml:"max_viewport_height"`
 	UserAgents        []string `yaml:"user_agents"`
+	SpoofCanvas       bool     `yaml:"spoof_canvas"`  // Add deterministic noise to canvas reads
+	SpoofWebGL        bool     `yaml:"spoof_webgl"`   // Report a consumer GPU as the WebGL vendor and renderer
+	SpoofAudio        bool     `yaml:"spoof_audio"`   // Add deterministic noise to AudioContext output
+	File              string   `yaml:"file"`          // Per-account spoofed values, reused across runs
 }
 
 // LimitsConfig contains basic rate limiting settings
 type LimitsConfig struct {
 	DailyConnections   int           `yaml:"daily_connections"`
 	HourlyConnections   int           `yaml:"hourly_connections"`
+	WeeklyConnections  int           `yaml:"weekly_connections"` // Connection requests in any 7 days; 0 is unlimited
+	SpreadWeekly       bool          `yaml:"spread_weekly"`      // Hold each day to an even share of weekly_connections
 	DailyMessages      int           `yaml:"daily_messages"`
 	HourlyMessages     int           `yaml:"hourly_messages"`
+	DailyLikes         int           `yaml:"daily_likes"`
+	DailyComments      int           `yaml:"daily_comments"`
+	DailyEndorsements  int           `yaml:"daily_endorsements"`
 	SearchResults      int           `yaml:"search_results"`
 	CooldownPeriod     time.Duration `yaml:"cooldown_period"`
+	Warmup             WarmupConfig  `yaml:"warmup"`
+}
+
+// WarmupConfig starts a new or idle account at low daily limits, ramping up
+// to the ones above over the given weeks
+type WarmupConfig struct {
+	Enabled          bool          `yaml:"enabled"`
+	Weeks            int           `yaml:"weeks"`             // Length of the ramp
+	StartConnections int           `yaml:"start_connections"` // Daily connection requests in the first week
+	StartMessages    int           `yaml:"start_messages"`    // Daily messages in the first week
+	RestartAfter     time.Duration `yaml:"restart_after"`     // Warm up again after this long without actions; 0 never does
 }
 
 // RateLimitConfig contains comprehensive rate limiting settings
@@ -136,16 +382,19 @@ type RateLimitConfig struct {
 	SearchDelay    string `yaml:"search_delay"`     // Duration string
 	ConnectDelay   string `yaml:"connect_delay"`    // Duration string
 	MessageDelay   string `yaml:"message_delay"`    // Duration string
+	VisitDelay     string `yaml:"visit_delay"`      // Duration string
 	
 	// Daily limits
 	DailySearches  int    `yaml:"daily_searches"`   // Max searches per day
 	DailyConnects  int    `yaml:"daily_connects"`   // Max connection requests per day
 	DailyMessages  int    `yaml:"daily_messages"`   // Max messages per day
+	DailyVisits    int    `yaml:"daily_visits"`     // Max profile visits per day
 	
 	// Hourly limits
 	HourlySearches int    `yaml:"hourly_searches"`  // Max searches per hour
 	HourlyConnects int    `yaml:"hourly_connects"`  // Max connection requests per hour
 	HourlyMessages int    `yaml:"hourly_messages"`  // Max messages per hour
+	HourlyVisits   int    `yaml:"hourly_visits"`    // Max profile visits per hour
 	
 	// Burst protection
 	BurstLimit     int    `yaml:"burst_limit"`      // Max actions in burst window
@@ -158,10 +407,15 @@ type RateLimitConfig struct {
 
 // StorageConfig contains database settings
 type StorageConfig struct {
-	Type     string `yaml:"type"`
-	Path     string `yaml:"path"`
+	Type     string `yaml:"type"` // sqlite, postgres or mysql
+	Path     string `yaml:"path"` // SQLite database file
+	DSN      string `yaml:"dsn"`  // Postgres or MySQL connection string
 	Backup   bool   `yaml:"backup"`
 	Interval time.Duration `yaml:"backup_interval"`
+	BackupDir  string `yaml:"backup_dir"`
+	BackupKeep int    `yaml:"backup_keep"` // Number of backups to keep; 0 keeps all
+	ProgressDir string `yaml:"progress_dir"` // Where running batches report their progress to status
+	SearchCacheTTL time.Duration `yaml:"search_cache_ttl"` // Identical searches this soon reuse the stored results; 0 always searches
 }
 
 // LoggingConfig contains logging settings
@@ -169,13 +423,180 @@ type LoggingConfig struct {
 	Level      string `yaml:"level"`
 	Format     string `yaml:"format"`
 	Output     string `yaml:"output"`
-	MaxSize    int    `yaml:"max_size"`
-	MaxBackups int    `yaml:"max_backups"`
-	MaxAge     int    `yaml:"max_age"`
+	MaxSize    int    `yaml:"max_size"`    // Megabytes a log file grows to before it is rotated; 0 never rotates
+	MaxBackups int    `yaml:"max_backups"` // Rotated log files to keep; 0 keeps all
+	MaxAge     int    `yaml:"max_age"`     // Days to keep rotated log files; 0 keeps them
+	ErrorFile  string `yaml:"error_file"`  // Also writes errors to this file; empty disables it
+	Modules    map[string]string `yaml:"modules"` // Levels of single packages, e.g. stealth: debug
+	RevealSensitive bool `yaml:"reveal_sensitive"` // Logs emails, passwords, cookies and message text unmasked, for debugging
+}
+
+// APIConfig contains settings for Voyager API mode, which fetches search and
+// profile data over HTTP instead of scraping pages
+type APIConfig struct {
+	Enabled          bool   `yaml:"enabled"`
+	LiAt             string `yaml:"li_at"`             // Session cookie; read from the browser when empty
+	JSessionID       string `yaml:"jsessionid"`        // CSRF cookie; read from the browser when empty
+	SearchQueryID    string `yaml:"search_query_id"`   // Override when LinkedIn rotates the search query ID
+	ResolveCompanies bool   `yaml:"resolve_companies"` // Look up company filter names through the typeahead, even with API mode off
+}
+
+// WebConfig contains settings for the web dashboard of 'queue run --listen'
+type WebConfig struct {
+	Token string `yaml:"token"` // Required by API requests; needed to listen beyond localhost
+}
+
+// RestrictionsConfig controls what happens once LinkedIn restricts the
+// account or flags unusual activity
+type RestrictionsConfig struct {
+	Cooldown   time.Duration `yaml:"cooldown"`    // How long no browser session may open afterwards
+	WebhookURL string        `yaml:"webhook_url"` // Receives a JSON post about each restriction; empty disables it
+}
+
+// RetentionConfig controls how long personal data is kept. 'queue run
+// --follow' enforces it every interval and 'data retention' on demand.
+type RetentionConfig struct {
+	Messages time.Duration `yaml:"messages"` // Clears the text of messages older than this; 0 keeps it
+	Profiles time.Duration `yaml:"profiles"` // Anonymizes people inactive for this long; 0 keeps them
+	Interval time.Duration `yaml:"interval"` // Between scheduled cleanups
+}
+
+// RemindersConfig controls the nudges sent to connections who accepted and
+// never replied. 'reminders queue' queues them on demand and 'queue run
+// --follow' every interval.
+type RemindersConfig struct {
+	After     time.Duration `yaml:"after"`      // Silence since the acceptance or the last message before a nudge
+	MaxNudges int           `yaml:"max_nudges"` // Nudges per prospect; 0 sends none
+	Template  string        `yaml:"template"`   // Message template of the nudges
+	Interval  time.Duration `yaml:"interval"`   // Between scheduled checks; 0 disables them
+}
+
+// IntegrationsConfig contains CRM connector settings
+type IntegrationsConfig struct {
+	AutoSync  bool            `yaml:"auto_sync"` // Push contacts whenever connect sync-accepted finds new acceptances
+	HubSpot   HubSpotConfig   `yaml:"hubspot"`
+	Pipedrive PipedriveConfig `yaml:"pipedrive"`
+}
+
+// HubSpotConfig contains HubSpot API settings
+type HubSpotConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	APIKey  string `yaml:"api_key"` // Private app access token
+	BaseURL string `yaml:"base_url"`
+}
+
+// PipedriveConfig contains Pipedrive API settings
+type PipedriveConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	APIToken string `yaml:"api_token"`
+	BaseURL  string `yaml:"base_url"`
+}
+
+// EnrichConfig contains the email finder APIs 'enrich emails' uses
+type EnrichConfig struct {
+	Providers   []string          `yaml:"providers"` // Tried in order until one finds an address; empty uses every provider with an API key
+	MinScore    int               `yaml:"min_score"` // Addresses a provider is less confident about (0-100) are ignored
+	Hunter      EmailFinderConfig `yaml:"hunter"`
+	Apollo      EmailFinderConfig `yaml:"apollo"`
+	Dropcontact EmailFinderConfig `yaml:"dropcontact"`
+}
+
+// ScoringConfig contains the rules prospects are scored by against the ideal
+// customer profile
+type ScoringConfig struct {
+	Rules []scoring.Rule `yaml:"rules"`
+}
+
+// AIConfig configures the language model that writes the {{ai_opener}}
+// first line of messages
+type AIConfig struct {
+	Provider         string `yaml:"provider"` // openai, anthropic or local (an OpenAI-compatible endpoint such as Ollama); empty leaves {{ai_opener}} empty
+	APIKey           string `yaml:"api_key"`
+	Model            string `yaml:"model"`              // Defaults to a small, inexpensive model of the provider
+	BaseURL          string `yaml:"base_url"`           // Defaults to the provider's API, or http://localhost:11434/v1 for local
+	MaxTokens        int    `yaml:"max_tokens"`         // Longest reply per opener
+	DailyTokenBudget int    `yaml:"daily_token_budget"` // Tokens all openers written in a day may use; 0 for no limit
+	Instructions     string `yaml:"instructions"`       // Replaces the default instructions given to the model
+}
+
+// APIKeyEnv names the environment variable the provider's API key is read from
+func (c *AIConfig) APIKeyEnv() string {
+	switch c.Provider {
+	case "openai":
+		return "OPENAI_API_KEY"
+	case "anthropic":
+		return "ANTHROPIC_API_KEY"
+	}
+	return ""
 }
 
+// EmailFinderConfig contains an email finder's API settings
+type EmailFinderConfig struct {
+	APIKey  string `yaml:"api_key"`
+	BaseURL string `yaml:"base_url"`
+}
+
+// EmailFinders returns the configured email finders by name, in the order
+// they are tried
+func (c *EnrichConfig) EmailFinders() ([]string, map[string]EmailFinderConfig) {
+	finders := map[string]EmailFinderConfig{
+		"hunter":      c.Hunter,
+		"apollo":      c.Apollo,
+		"dropcontact": c.Dropcontact,
+	}
+	names := c.Providers
+	if len(names) == 0 {
+		for _, name := range []string{"hunter", "apollo", "dropcontact"} {
+			if finders[name].APIKey != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names, finders
+}
+
+// ErrInvalid matches every error LoadConfig returns, whether the file cannot
+// be read or decoded or a setting is invalid
+var ErrInvalid = errors.New("invalid configuration")
+
+// loadError wraps a LoadConfig failure so it also matches ErrInvalid
+type loadError struct {
+	err error
+}
+
+func (e *loadError) Error() string   { return e.err.Error() }
+func (e *loadError) Unwrap() []error { return []error{e.err, ErrInvalid} }
+
 // LoadConfig loads configuration from file and environment variables
 func LoadConfig(configPath string) (*Config, error) {
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return nil, &loadError{err: err}
+	}
+	return config, nil
+}
+
+func loadConfig(configPath string) (*Config, error) {
+	if err := readConfig(configPath, true); err != nil {
+		return nil, err
+	}
+
+	config, err := decodeConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	// Validate configuration
+	if err := validateConfig(config); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return config, nil
+}
+
+// readConfig sets the defaults and reads the configuration file into viper,
+// creating a default file when create is set and none exists
+func readConfig(configPath string, create bool) error {
 	// Set default values
 	setDefaults()
 
@@ -183,50 +604,68 @@ func LoadConfig(configPath string) (*Config, error) {
 	viper.SetConfigFile(configPath)
 	viper.SetConfigType("yaml")
 
-	// Enable environment variable support
+	// Enable environment variable support; any key with a default can be set as
+	// LINKEDIN_<SECTION>_<KEY>, e.g. LINKEDIN_STORAGE_PATH for storage.path
 	viper.AutomaticEnv()
 	viper.SetEnvPrefix("LINKEDIN")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
 	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok && create {
 			// Config file not found, create default config
 			if err := createDefaultConfig(configPath); err != nil {
-				return nil, fmt.Errorf("failed to create default config: %w", err)
+				return fmt.Errorf("failed to create default config: %w", err)
 			}
-		} else {
-			return nil, fmt.Errorf("failed to read config file: %w", err)
+		} else if !os.IsNotExist(err) || !EnvOnly() {
+			// Without a file, the environment can hold the whole configuration, e.g. in a container
+			return fmt.Errorf("failed to read config file: %w", err)
 		}
 	}
 
 	// Override with environment variables
 	overrideFromEnv()
+	return nil
+}
 
+// decodeConfig builds the configuration from what viper has read
+func decodeConfig() (*Config, error) {
+	// Decode using the yaml tags so snake_case keys map onto struct fields
 	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	if err := viper.Unmarshal(&config, func(dc *mapstructure.DecoderConfig) {
+		dc.TagName = "yaml"
+	}); err != nil {
+		return nil, err
 	}
 
 	// Manually set limits from viper as workaround for unmarshal issue
 	config.Limits.DailyConnections = viper.GetInt("limits.daily_connections")
 	config.Limits.HourlyConnections = viper.GetInt("limits.hourly_connections")
+	config.Limits.WeeklyConnections = viper.GetInt("limits.weekly_connections")
+	config.Limits.SpreadWeekly = viper.GetBool("limits.spread_weekly")
 	config.Limits.DailyMessages = viper.GetInt("limits.daily_messages")
 	config.Limits.HourlyMessages = viper.GetInt("limits.hourly_messages")
+	config.Limits.DailyLikes = viper.GetInt("limits.daily_likes")
+	config.Limits.DailyComments = viper.GetInt("limits.daily_comments")
+	config.Limits.DailyEndorsements = viper.GetInt("limits.daily_endorsements")
 	config.Limits.SearchResults = viper.GetInt("limits.search_results")
 	config.Limits.CooldownPeriod = viper.GetDuration("limits.cooldown_period")
 
-	// Validate configuration
-	if err := validateConfig(&config); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
-	}
-
 	return &config, nil
 }
 
+// EnvOnly reports whether the credentials come from the environment, in which
+// case a missing configuration file is not created and defaults apply
+func EnvOnly() bool {
+	return os.Getenv("LINKEDIN_EMAIL") != "" && os.Getenv("LINKEDIN_PASSWORD") != ""
+}
+
 // setDefaults sets default configuration values
 func setDefaults() {
 	viper.SetDefault("linkedin.base_url", "https://www.linkedin.com")
 	viper.SetDefault("linkedin.login_url", "https://www.linkedin.com/login")
 	viper.SetDefault("linkedin.search_url", "https://www.linkedin.com/search/results/people/")
+	viper.SetDefault("linkedin.ui_language", "en")
+	viper.SetDefault("linkedin.premium", false)
 
 	viper.SetDefault("browser.headless", true)
 	viper.SetDefault("browser.slow_mo", "100ms")
@@ -234,6 +673,18 @@ func setDefaults() {
 	viper.SetDefault("browser.viewport_height", 1080)
 	viper.SetDefault("browser.user_agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
 	viper.SetDefault("browser.disable_web_security", false)
+	viper.SetDefault("browser.selectors_file", "./selectors.yaml")
+	viper.SetDefault("browser.executable_path", "")
+	viper.SetDefault("browser.proxy", "")
+	viper.SetDefault("browser.remote_debugging_url", "")
+	viper.SetDefault("browser.container", false)
+	viper.SetDefault("browser.session_dir", "./sessions")
+	viper.SetDefault("browser.waits.element_timeout", "10s")
+	viper.SetDefault("browser.waits.poll_interval", "500ms")
+	viper.SetDefault("browser.waits.settle", "1s")
+	viper.SetDefault("browser.session_check.interval", "15m")
+	viper.SetDefault("browser.session_check.attempts", 2)
+	viper.SetDefault("browser.session_check.retry_delay", "2m")
 
 	viper.SetDefault("stealth.enabled", false)
 	viper.SetDefault("stealth.mouse_movement.bezier_curves", true)
@@ -257,6 +708,9 @@ func setDefaults() {
 	viper.SetDefault("stealth.typing.correction_delay", "500ms")
 	viper.SetDefault("stealth.typing.min_speed", "50ms")
 	viper.SetDefault("stealth.typing.max_speed", "200ms")
+	viper.SetDefault("stealth.typing.max_compose_time", "2m")
+	viper.SetDefault("stealth.typing.review_pause", "1s")
+	viper.SetDefault("stealth.typing.read_delay", "0s")
 
 	viper.SetDefault("stealth.scrolling.variable_speed", true)
 	viper.SetDefault("stealth.scrolling.acceleration", true)
@@ -271,6 +725,9 @@ func setDefaults() {
 	viper.SetDefault("stealth.schedule.break_duration", "15m")
 	viper.SetDefault("stealth.schedule.break_frequency", "2h")
 	viper.SetDefault("stealth.schedule.timezone", "UTC")
+	viper.SetDefault("stealth.schedule.max_session_duration", "45m")
+	viper.SetDefault("stealth.schedule.max_session_actions", 0)
+	viper.SetDefault("stealth.schedule.on_session_limit", ratelimit.OnSessionLimitBreak)
 
 	viper.SetDefault("stealth.fingerprint.random_user_agent", true)
 	viper.SetDefault("stealth.fingerprint.random_viewport", true)
@@ -278,13 +735,35 @@ func setDefaults() {
 	viper.SetDefault("stealth.fingerprint.max_viewport_width", 2560)
 	viper.SetDefault("stealth.fingerprint.min_viewport_height", 768)
 	viper.SetDefault("stealth.fingerprint.max_viewport_height", 1440)
+	viper.SetDefault("stealth.fingerprint.spoof_canvas", true)
+	viper.SetDefault("stealth.fingerprint.spoof_webgl", true)
+	viper.SetDefault("stealth.fingerprint.spoof_audio", true)
+	viper.SetDefault("stealth.fingerprint.file", "./sessions/fingerprints.json")
+	viper.SetDefault("stealth.warm_up.probability", 0.7)
+	viper.SetDefault("stealth.warm_up.min_duration", "30s")
+	viper.SetDefault("stealth.warm_up.max_duration", "2m")
+	viper.SetDefault("stealth.warm_up.notification_probability", 0.3)
+	viper.SetDefault("stealth.reading.words_per_minute", 250)
+	viper.SetDefault("stealth.reading.min_dwell", "8s")
+	viper.SetDefault("stealth.reading.max_dwell", "45s")
+	viper.SetDefault("stealth.navigation.mode", "direct")
 
 	viper.SetDefault("limits.daily_connections", 50)
 	viper.SetDefault("limits.hourly_connections", 10)
+	viper.SetDefault("limits.weekly_connections", 100)
+	viper.SetDefault("limits.spread_weekly", true)
 	viper.SetDefault("limits.daily_messages", 100)
 	viper.SetDefault("limits.hourly_messages", 20)
+	viper.SetDefault("limits.daily_likes", 30)
+	viper.SetDefault("limits.daily_comments", 10)
+	viper.SetDefault("limits.daily_endorsements", 20)
 	viper.SetDefault("limits.search_results", 100)
 	viper.SetDefault("limits.cooldown_period", "30m")
+	viper.SetDefault("limits.warmup.enabled", false)
+	viper.SetDefault("limits.warmup.weeks", 4)
+	viper.SetDefault("limits.warmup.start_connections", 5)
+	viper.SetDefault("limits.warmup.start_messages", 10)
+	viper.SetDefault("limits.warmup.restart_after", "720h")
 
 	// Rate limiting defaults
 	viper.SetDefault("rate_limit.min_delay", "2s")
@@ -292,12 +771,15 @@ func setDefaults() {
 	viper.SetDefault("rate_limit.search_delay", "5s")
 	viper.SetDefault("rate_limit.connect_delay", "30s")
 	viper.SetDefault("rate_limit.message_delay", "60s")
+	viper.SetDefault("rate_limit.visit_delay", "20s")
 	viper.SetDefault("rate_limit.daily_searches", 100)
 	viper.SetDefault("rate_limit.daily_connects", 50)
 	viper.SetDefault("rate_limit.daily_messages", 30)
+	viper.SetDefault("rate_limit.daily_visits", 80)
 	viper.SetDefault("rate_limit.hourly_searches", 20)
 	viper.SetDefault("rate_limit.hourly_connects", 10)
 	viper.SetDefault("rate_limit.hourly_messages", 5)
+	viper.SetDefault("rate_limit.hourly_visits", 15)
 	viper.SetDefault("rate_limit.burst_limit", 3)
 	viper.SetDefault("rate_limit.burst_window", "30s")
 	viper.SetDefault("rate_limit.randomize_delay", true)
@@ -307,6 +789,10 @@ func setDefaults() {
 	viper.SetDefault("storage.path", "./data/linkedin.db")
 	viper.SetDefault("storage.backup", true)
 	viper.SetDefault("storage.backup_interval", "1h")
+	viper.SetDefault("storage.backup_dir", "./data/backups")
+	viper.SetDefault("storage.backup_keep", 24)
+	viper.SetDefault("storage.progress_dir", "./data/progress")
+	viper.SetDefault("storage.search_cache_ttl", "1h")
 
 	viper.SetDefault("logging.level", "info")
 	viper.SetDefault("logging.format", "json")
@@ -314,6 +800,80 @@ func setDefaults() {
 	viper.SetDefault("logging.max_size", 100)
 	viper.SetDefault("logging.max_backups", 3)
 	viper.SetDefault("logging.max_age", 28)
+	viper.SetDefault("logging.error_file", "")
+	viper.SetDefault("logging.reveal_sensitive", false)
+
+	viper.SetDefault("integrations.auto_sync", false)
+	viper.SetDefault("integrations.hubspot.base_url", "https://api.hubapi.com")
+	viper.SetDefault("integrations.pipedrive.base_url", "https://api.pipedrive.com")
+	viper.SetDefault("enrich.hunter.base_url", "https://api.hunter.io")
+	viper.SetDefault("enrich.apollo.base_url", "https://api.apollo.io")
+	viper.SetDefault("enrich.dropcontact.base_url", "https://api.dropcontact.io")
+	viper.SetDefault("ai.max_tokens", 100)
+	viper.SetDefault("ai.daily_token_budget", 20000)
+
+	viper.SetDefault("api.enabled", false)
+	viper.SetDefault("api.resolve_companies", true)
+	viper.SetDefault("web.token", "")
+	viper.SetDefault("restrictions.cooldown", "72h")
+	viper.SetDefault("restrictions.webhook_url", "")
+	viper.SetDefault("retention.messages", "0")
+	viper.SetDefault("retention.profiles", "0")
+	viper.SetDefault("retention.interval", "24h")
+	viper.SetDefault("reminders.after", "168h")
+	viper.SetDefault("reminders.max_nudges", 2)
+	viper.SetDefault("reminders.template", "follow_up_casual")
+	viper.SetDefault("reminders.interval", "0")
+
+	viper.SetDefault("captcha.timeout", "3m")
+
+	viper.SetDefault("retry.max_attempts", 3)
+	viper.SetDefault("retry.initial_delay", "5s")
+	viper.SetDefault("retry.max_delay", "1m")
+	viper.SetDefault("retry.multiplier", 2.0)
+
+	viper.SetDefault("inbox.opt_out_phrases", []string{
+		"not interested", "remove me", "unsubscribe", "stop messaging me", "stop contacting me",
+		"do not contact me", "don't contact me", "leave me alone",
+	})
+	viper.SetDefault("inbox.classify.enabled", true)
+	viper.SetDefault("inbox.classify.interested", []string{
+		"interested", "sounds good", "sounds great", "tell me more", "let's talk", "let's chat",
+		"happy to chat", "book a call", "schedule a call", "set up a call", "send me more",
+	})
+	viper.SetDefault("inbox.classify.not_interested", []string{
+		"not interested", "no thanks", "no thank you", "not a fit", "not for us", "we're all set",
+		"not looking",
+	})
+	viper.SetDefault("inbox.classify.later", []string{
+		"not right now", "not now", "maybe later", "reach out later", "circle back", "next quarter",
+		"next month", "next year", "after the holidays", "too busy", "bad timing", "check back",
+	})
+	viper.SetDefault("inbox.classify.revisit_after", "720h")
+
+	viper.SetDefault("endorse.max_skills", 3)
+
+	viper.SetDefault("connect.open_profile_subject", "Hello {{first_name}}")
+
+	viper.SetDefault("sequences.dir", "./sequences")
+	viper.SetDefault("sequences.sync_interval", "4h")
+	viper.SetDefault("sequences.inbox_limit", 20)
+
+	viper.SetDefault("audit.enabled", true)
+
+	viper.SetDefault("capture.dir", "./debug")
+	viper.SetDefault("capture.on_error", true)
+
+	viper.SetDefault("recording.dir", "./recordings")
+	viper.SetDefault("recording.format", "images")
+	viper.SetDefault("recording.quality", 60)
+	viper.SetDefault("recording.max_width", 1280)
+	viper.SetDefault("recording.max_height", 800)
+	viper.SetDefault("recording.every_nth_frame", 1)
+
+	viper.SetDefault("imap.port", 993)
+	viper.SetDefault("imap.mailbox", "INBOX")
+	viper.SetDefault("imap.timeout", "3m")
 }
 
 // createDefaultConfig creates a default configuration file
@@ -355,23 +915,181 @@ func overrideFromEnv() {
 	if password := os.Getenv("LINKEDIN_PASSWORD"); password != "" {
 		viper.Set("linkedin.password", password)
 	}
+	if apiKey := os.Getenv("HUBSPOT_API_KEY"); apiKey != "" {
+		viper.Set("integrations.hubspot.api_key", apiKey)
+	}
+	if apiToken := os.Getenv("PIPEDRIVE_API_TOKEN"); apiToken != "" {
+		viper.Set("integrations.pipedrive.api_token", apiToken)
+	}
+	if apiKey := os.Getenv("HUNTER_API_KEY"); apiKey != "" {
+		viper.Set("enrich.hunter.api_key", apiKey)
+	}
+	if apiKey := os.Getenv("APOLLO_API_KEY"); apiKey != "" {
+		viper.Set("enrich.apollo.api_key", apiKey)
+	}
+	if apiKey := os.Getenv("DROPCONTACT_API_KEY"); apiKey != "" {
+		viper.Set("enrich.dropcontact.api_key", apiKey)
+	}
+	ai := AIConfig{Provider: viper.GetString("ai.provider")}
+	if env := ai.APIKeyEnv(); env != "" && os.Getenv(env) != "" {
+		viper.Set("ai.api_key", os.Getenv(env))
+	}
+	if apiKey := os.Getenv("CAPTCHA_API_KEY"); apiKey != "" {
+		viper.Set("captcha.api_key", apiKey)
+	}
+	if password := os.Getenv("IMAP_PASSWORD"); password != "" {
+		viper.Set("imap.password", password)
+	}
+	if liAt := os.Getenv("LINKEDIN_LI_AT"); liAt != "" {
+		viper.Set("api.li_at", liAt)
+	}
+	if jsessionID := os.Getenv("LINKEDIN_JSESSIONID"); jsessionID != "" {
+		viper.Set("api.jsessionid", jsessionID)
+	}
 }
 
-// validateConfig validates the configuration
+// validateConfig validates the configuration, reporting the first problem
 func validateConfig(config *Config) error {
+	if problems := checkConfig(config); len(problems) > 0 {
+		return problems[0]
+	}
+	return nil
+}
+
+// checkConfig returns every problem with the configuration
+func checkConfig(config *Config) []error {
+	var problems []error
 	if config.LinkedIn.Email == "" {
-		return fmt.Errorf("linkedin email is required")
+		problems = append(problems, fmt.Errorf("linkedin email is required"))
 	}
-	if config.LinkedIn.Password == "" {
-		return fmt.Errorf("linkedin password is required")
+	if err := uitext.Check(config.LinkedIn.UILanguage, config.LinkedIn.UIText); err != nil {
+		problems = append(problems, fmt.Errorf("linkedin.ui_language: %w", err))
 	}
 	if config.Limits.DailyConnections <= 0 {
-		return fmt.Errorf("daily connections must be positive")
+		problems = append(problems, fmt.Errorf("daily connections must be positive"))
 	}
 	if config.Limits.HourlyConnections <= 0 {
-		return fmt.Errorf("hourly connections must be positive")
+		problems = append(problems, fmt.Errorf("hourly connections must be positive"))
 	}
-	return nil
+	switch config.Stealth.Schedule.OnSessionLimit {
+	case "", ratelimit.OnSessionLimitBreak, ratelimit.OnSessionLimitExit:
+	default:
+		problems = append(problems, fmt.Errorf("stealth.schedule.on_session_limit must be %q or %q", ratelimit.OnSessionLimitBreak, ratelimit.OnSessionLimitExit))
+	}
+	switch config.Storage.Type {
+	case "", "sqlite":
+	case "postgres", "mysql":
+		if config.Storage.DSN == "" {
+			problems = append(problems, fmt.Errorf("storage.dsn is required for the %s storage type", config.Storage.Type))
+		}
+	default:
+		problems = append(problems, fmt.Errorf("storage.type must be sqlite, postgres or mysql"))
+	}
+	if config.Storage.Backup && config.Storage.Interval <= 0 {
+		problems = append(problems, fmt.Errorf("storage.backup_interval must be positive when backups are enabled"))
+	}
+	if config.Storage.SearchCacheTTL < 0 {
+		problems = append(problems, fmt.Errorf("storage.search_cache_ttl must not be negative"))
+	}
+	for module, level := range config.Logging.Modules {
+		if _, err := logrus.ParseLevel(level); err != nil {
+			problems = append(problems, fmt.Errorf("logging.modules.%s: %w", module, err))
+		}
+	}
+	if config.Retention.Messages < 0 || config.Retention.Profiles < 0 {
+		problems = append(problems, fmt.Errorf("retention.messages and retention.profiles must not be negative"))
+	}
+	if (config.Retention.Messages > 0 || config.Retention.Profiles > 0) && config.Retention.Interval <= 0 {
+		problems = append(problems, fmt.Errorf("retention.interval must be positive when a retention period is set"))
+	}
+	if config.Reminders.After <= 0 {
+		problems = append(problems, fmt.Errorf("reminders.after must be positive"))
+	}
+	if config.Reminders.MaxNudges < 0 || config.Reminders.Interval < 0 {
+		problems = append(problems, fmt.Errorf("reminders.max_nudges and reminders.interval must not be negative"))
+	}
+	if config.Reminders.Interval > 0 && config.Reminders.Template == "" {
+		problems = append(problems, fmt.Errorf("reminders.template must be set when reminders.interval is"))
+	}
+	for i, rule := range config.Invitations.Rules {
+		if rule.Action != "accept" && rule.Action != "ignore" {
+			problems = append(problems, fmt.Errorf("invitations.rules[%d].action must be accept or ignore", i))
+		}
+		for field, pattern := range map[string]string{"headline": rule.Headline, "name": rule.Name} {
+			if _, err := regexp.Compile(pattern); err != nil {
+				problems = append(problems, fmt.Errorf("invitations.rules[%d].%s: %w", i, field, err))
+			}
+		}
+	}
+	if config.Browser.ExecutablePath != "" {
+		if _, err := os.Stat(config.Browser.ExecutablePath); err != nil {
+			problems = append(problems, fmt.Errorf("browser.executable_path: %w", err))
+		}
+	}
+	if err := validateAccounts(config.Accounts); err != nil {
+		problems = append(problems, err)
+	}
+	for i, fallback := range config.Connect.Fallbacks {
+		if fallback != "follow" && fallback != "message" {
+			problems = append(problems, fmt.Errorf("connect.fallbacks[%d] must be follow or message", i))
+		}
+	}
+	if config.Endorse.MaxSkills < 1 {
+		problems = append(problems, fmt.Errorf("endorse.max_skills must be at least 1"))
+	}
+	if config.Retry.MaxAttempts < 1 {
+		problems = append(problems, fmt.Errorf("retry.max_attempts must be at least 1"))
+	}
+	if config.Captcha.Provider != "" && config.Captcha.APIKey == "" {
+		problems = append(problems, fmt.Errorf("captcha api key is required when a provider is set"))
+	}
+	if config.IMAP.Host != "" && (config.IMAP.Username == "" || config.IMAP.Password == "") {
+		problems = append(problems, fmt.Errorf("imap username and password are required when a host is set"))
+	}
+	if config.Integrations.HubSpot.Enabled && config.Integrations.HubSpot.APIKey == "" {
+		problems = append(problems, fmt.Errorf("hubspot api key is required when the integration is enabled"))
+	}
+	if config.Integrations.Pipedrive.Enabled && config.Integrations.Pipedrive.APIToken == "" {
+		problems = append(problems, fmt.Errorf("pipedrive api token is required when the integration is enabled"))
+	}
+	names, finders := config.Enrich.EmailFinders()
+	for _, name := range names {
+		finder, ok := finders[name]
+		if !ok {
+			problems = append(problems, fmt.Errorf("enrich.providers: unknown provider %q (use hunter, apollo or dropcontact)", name))
+		} else if finder.APIKey == "" {
+			problems = append(problems, fmt.Errorf("enrich.%s.api_key is required when the provider is listed", name))
+		}
+	}
+	if config.Enrich.MinScore < 0 || config.Enrich.MinScore > 100 {
+		problems = append(problems, fmt.Errorf("enrich.min_score must be between 0 and 100"))
+	}
+	switch config.AI.Provider {
+	case "", "local":
+	case "openai", "anthropic":
+		if config.AI.APIKey == "" {
+			problems = append(problems, fmt.Errorf("ai.api_key is required for the %s provider", config.AI.Provider))
+		}
+	default:
+		problems = append(problems, fmt.Errorf("ai.provider must be openai, anthropic or local"))
+	}
+	if config.AI.Provider != "" && config.AI.MaxTokens < 1 {
+		problems = append(problems, fmt.Errorf("ai.max_tokens must be at least 1"))
+	}
+	if config.AI.DailyTokenBudget < 0 {
+		problems = append(problems, fmt.Errorf("ai.daily_token_budget must not be negative"))
+	}
+	if _, err := scoring.New(config.Scoring.Rules); err != nil {
+		problems = append(problems, fmt.Errorf("scoring: %w", err))
+	}
+	if config.Inbox.Classify.Enabled && config.Inbox.Classify.RevisitAfter <= 0 {
+		problems = append(problems, fmt.Errorf("inbox.classify.revisit_after must be positive"))
+	}
+	if config.Inbox.Classify.Enabled && config.Inbox.Classify.UseAI && config.AI.Provider == "" {
+		problems = append(problems, fmt.Errorf("inbox.classify.use_ai requires ai.provider"))
+	}
+	problems = append(problems, checkRanges(config)...)
+	return problems
 }
 
 // ToRateLimitConfig converts RateLimitConfig to ratelimit.Config
@@ -394,15 +1112,76 @@ func (c *RateLimitConfig) ToRateLimitConfig() (ratelimit.Config, error) {
 		SearchDelay:    parseDuration(c.SearchDelay),
 		ConnectDelay:   parseDuration(c.ConnectDelay),
 		MessageDelay:   parseDuration(c.MessageDelay),
+		VisitDelay:     parseDuration(c.VisitDelay),
 		DailySearches:  c.DailySearches,
 		DailyConnects:  c.DailyConnects,
 		DailyMessages:  c.DailyMessages,
+		DailyVisits:    c.DailyVisits,
 		HourlySearches: c.HourlySearches,
 		HourlyConnects: c.HourlyConnects,
 		HourlyMessages: c.HourlyMessages,
+		HourlyVisits:   c.HourlyVisits,
 		BurstLimit:     c.BurstLimit,
 		BurstWindow:    parseDuration(c.BurstWindow),
 		RandomizeDelay: c.RandomizeDelay,
 		JitterPercent:  c.JitterPercent,
 	}, nil
 }
+
+// RateLimiterConfig builds the rate limiter configuration, taking the
+// connection and message quotas from LimitsConfig
+func (c *Config) RateLimiterConfig() ratelimit.Config {
+	rlConfig, _ := c.RateLimit.ToRateLimitConfig()
+
+	rlConfig.DailyConnects = c.Limits.DailyConnections
+	rlConfig.HourlyConnects = c.Limits.HourlyConnections
+	rlConfig.WeeklyConnects = c.Limits.WeeklyConnections
+	rlConfig.SpreadWeekly = c.Limits.SpreadWeekly
+	rlConfig.DailyMessages = c.Limits.DailyMessages
+	rlConfig.HourlyMessages = c.Limits.HourlyMessages
+	rlConfig.DailyLikes = c.Limits.DailyLikes
+	rlConfig.DailyComments = c.Limits.DailyComments
+	rlConfig.DailyEndorsements = c.Limits.DailyEndorsements
+
+	return rlConfig
+}
+
+// WarmupConfig converts the warm-up settings
+func (c *Config) WarmupConfig() warmup.Config {
+	return warmup.Config{
+		Weeks:            c.Limits.Warmup.Weeks,
+		StartConnections: c.Limits.Warmup.StartConnections,
+		StartMessages:    c.Limits.Warmup.StartMessages,
+		RestartAfter:     c.Limits.Warmup.RestartAfter,
+	}
+}
+
+// SessionLimiterConfig builds the per-session caps from the stealth schedule
+func (c *Config) SessionLimiterConfig() ratelimit.SessionConfig {
+	return ratelimit.SessionConfig{
+		MaxActions:     c.Stealth.Schedule.MaxSessionActions,
+		MaxDuration:    c.Stealth.Schedule.MaxSessionDuration,
+		BreakFrequency: c.Stealth.Schedule.BreakFrequency,
+		BreakDuration:  c.Stealth.Schedule.BreakDuration,
+		OnLimit:        c.Stealth.Schedule.OnSessionLimit,
+	}
+}
+
+// PageWaits builds the browser step waits from the browser.waits settings
+func (c *Config) PageWaits() pause.Waits {
+	return pause.Waits{
+		ElementTimeout: c.Browser.Waits.ElementTimeout,
+		PollInterval:   c.Browser.Waits.PollInterval,
+		Settle:         c.Browser.Waits.Settle,
+	}
+}
+
+// RetryPolicy builds the batch retry policy from the retry settings
+func (c *Config) RetryPolicy() retry.Policy {
+	return retry.Policy{
+		MaxAttempts:  c.Retry.MaxAttempts,
+		InitialDelay: c.Retry.InitialDelay,
+		MaxDelay:     c.Retry.MaxDelay,
+		Multiplier:   c.Retry.Multiplier,
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Change is a setting that differs between two configurations
+type Change struct {
+	Key  string // YAML key, e.g. limits.daily_connections
+	From string
+	To   string
+}
+
+// Diff lists the settings that differ between two configurations. Secrets are
+// compared but shown masked.
+func Diff(old, new *Config) []Change {
+	var changes []Change
+	diffValues("", reflect.ValueOf(*old), reflect.ValueOf(*new),
+		reflect.ValueOf(*old.Masked()), reflect.ValueOf(*new.Masked()), &changes)
+	return changes
+}
+
+// HasPrefix reports whether the change is to key or to a setting below it
+func (c Change) HasPrefix(key string) bool {
+	return c.Key == key || strings.HasPrefix(c.Key, key+".") || strings.HasPrefix(c.Key, key+"[")
+}
+
+// diffValues compares a and b, shown as their masked counterparts ma and mb
+func diffValues(path string, a, b, ma, mb reflect.Value, changes *[]Change) {
+	if a.Kind() == reflect.Struct && a.Type() != reflect.TypeOf(time.Time{}) {
+		for i := 0; i < a.NumField(); i++ {
+			name := strings.Split(a.Type().Field(i).Tag.Get("yaml"), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+			if path != "" {
+				name = path + "." + name
+			}
+			diffValues(name, a.Field(i), b.Field(i), ma.Field(i), mb.Field(i), changes)
+		}
+		return
+	}
+
+	if reflect.DeepEqual(a.Interface(), b.Interface()) {
+		return
+	}
+	*changes = append(*changes, Change{Key: path, From: formatValue(ma), To: formatValue(mb)})
+}
+
+func formatValue(v reflect.Value) string {
+	switch {
+	case v.Kind() == reflect.String:
+		return fmt.Sprintf("%q", v.String())
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Struct:
+		return fmt.Sprintf("%d entries", v.Len())
+	}
+	return fmt.Sprint(v.Interface())
+}
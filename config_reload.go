@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/config"
+	"linkedin-automation/logger"
+	"linkedin-automation/pause"
+	"linkedin-automation/queue"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/reload"
+	"linkedin-automation/selectors"
+	"linkedin-automation/sequence"
+	"linkedin-automation/storage"
+)
+
+// liveSettings are the settings 'queue run --follow' applies as soon as the
+// config file is saved; changes to any other setting need a restart
+var liveSettings = []string{
+	"limits",
+	"rate_limit",
+	"browser.waits",
+	"stealth.schedule",
+	"sequences.sync_interval",
+	"sequences.inbox_limit",
+	"logging",
+}
+
+// restartSettings lie below a live setting but still need a restart
+var restartSettings = []string{
+	"limits.warmup",
+}
+
+// configReloader applies edits of the config, selectors and sequence files to
+// a running queue worker without interrupting the task in progress
+type configReloader struct {
+	cfg     *config.Config // As last loaded
+	limiter ratelimit.Limiter
+	engine  *sequence.Engine // Nil when no sequences were defined at start
+	worker  *queue.Worker
+	db      *storage.Database
+}
+
+// watch polls the files every interval until ctx is cancelled
+func (r *configReloader) watch(ctx context.Context, interval time.Duration) {
+	watcher := reload.NewWatcher(interval, configFile, r.cfg.Browser.SelectorsFile, r.cfg.Sequences.Dir)
+	selectorsFile, sequencesDir := r.cfg.Browser.SelectorsFile, r.cfg.Sequences.Dir
+
+	logger.GetLogger().WithField("interval", interval).Info("Watching config, selectors and sequences for changes")
+	watcher.Run(ctx, func(changed []string) {
+		for _, path := range changed {
+			switch path {
+			case configFile:
+				r.reloadConfig()
+			case selectorsFile:
+				r.reloadSelectors(selectorsFile)
+			case sequencesDir:
+				r.reloadSequences(sequencesDir)
+			}
+		}
+	})
+}
+
+func (r *configReloader) reloadConfig() {
+	log := logger.GetLogger()
+
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		log.WithError(err).Error("Config file changed but cannot be loaded, keeping the running configuration")
+		return
+	}
+
+	changes := config.Diff(r.cfg, cfg)
+	if len(changes) == 0 {
+		return
+	}
+	for _, change := range changes {
+		entry := log.WithFields(logrus.Fields{"key": change.Key, "from": change.From, "to": change.To})
+		if isLiveSetting(change) {
+			entry.Info("Config setting changed")
+		} else {
+			entry.Warn("Config setting changed, restart to apply it")
+		}
+	}
+
+	ratelimit.Reconfigure(r.limiter, cfg.RateLimiterConfig(), cfg.SessionLimiterConfig())
+	pause.SetWaits(cfg.PageWaits())
+	if r.engine != nil {
+		r.engine.SetSync(cfg.Sequences.SyncInterval, cfg.Sequences.InboxLimit)
+	}
+	applySchedule(r.worker, cfg, r.db)
+	if err := setupLogger(cfg.Logging); err != nil {
+		log.WithError(err).Warn("Failed to apply the new logging settings")
+	}
+	r.cfg = cfg
+}
+
+func isLiveSetting(change config.Change) bool {
+	for _, key := range restartSettings {
+		if change.HasPrefix(key) {
+			return false
+		}
+	}
+	for _, key := range liveSettings {
+		if change.HasPrefix(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *configReloader) reloadSelectors(path string) {
+	count, err := selectors.ReloadFile(path)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("Selectors file changed but cannot be loaded, keeping the current selectors")
+		return
+	}
+	logger.GetLogger().WithField("file", path).WithField("overrides", count).Info("Reloaded selector overrides")
+}
+
+func (r *configReloader) reloadSequences(dir string) {
+	log := logger.GetLogger()
+
+	sequences, err := sequence.LoadDir(dir)
+	if err != nil {
+		log.WithError(err).Error("Sequences changed but cannot be loaded, keeping the current definitions")
+		return
+	}
+	if r.engine == nil {
+		if len(sequences) > 0 {
+			log.Warn("Sequences were added, restart to run them")
+		}
+		return
+	}
+	r.engine.SetSequences(sequences)
+	log.WithField("sequences", sequence.Names(sequences)).Info("Reloaded sequences")
+}
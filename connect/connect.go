@@ -2,21 +2,53 @@ package connect
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand"
 	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
 	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/errs"
+	"linkedin-automation/pause"
+	"linkedin-automation/personalize"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/retry"
+	"linkedin-automation/selectors"
+	"linkedin-automation/storage"
+	"linkedin-automation/uitext"
 )
 
 // ConnectManager handles connection requests
 type ConnectManager struct {
-	page      *rod.Page
-	logger    *logrus.Logger
-	stealth   StealthManager
+	page       *rod.Page
+	logger     *logrus.Logger
+	stealth      StealthManager
+	batchStore   BatchStore
+	rateLimiter  RateLimiter
+	personalizer Personalizer
+	retryPolicy  retry.Policy
+	limitStore   LimitStore
+	dryRun       bool
+	noteLimit    int // Longest note the account may send; 0 for no limit
+	reviewer     Reviewer
+	blacklist    Blacklist
+	capturer     Capturer
+	fallbacks    []string
+	messenger    OpenProfileMessenger
+	openProfileSubject string
+	navigator    Navigator
+	interleave   func(ctx context.Context) error
+	interleaveN  int
+	interleaveIn int // Requests left before the next interleaved action
 }
 
+// weeklyLimitBlock is how long invitations are paused once LinkedIn reports
+// the weekly invitation limit; the limit is a rolling one-week window
+const weeklyLimitBlock = 7 * 24 * time.Hour
+
 // StealthManager interface for stealth operations
 type StealthManager interface {
 	HumanLikeMouseMove(page *rod.Page, fromX, fromY, toX, toY float64) error
@@ -24,6 +56,67 @@ type StealthManager interface {
 	HumanLikeType(page *rod.Page, text string) error
 	HumanLikeScroll(page *rod.Page, scrollAmount int) error
 	AddIdleMovement(page *rod.Page) error
+	ReadProfile(page *rod.Page) error
+	HumanClick(page *rod.Page, element *rod.Element) error
+}
+
+// Navigator opens profiles through in-page clicks rather than by URL
+type Navigator interface {
+	OpenProfile(page *rod.Page, profileURL string) error
+}
+
+// BatchStore persists per-profile batch progress so interrupted runs can be resumed
+type BatchStore interface {
+	IsBatchItemCompleted(batchID, itemURL string) (bool, error)
+	RecordBatchItem(batchID, action, itemURL, status, errorMessage string) error
+}
+
+// LimitStore remembers when LinkedIn blocks sending invitations
+type LimitStore interface {
+	GetActionBlock(action string) (time.Time, error)
+	SetActionBlock(action string, until time.Time, reason string) error
+}
+
+// RateLimiter gates actions against configured quotas
+type RateLimiter interface {
+	WaitForPermission(ctx context.Context, action ratelimit.ActionType) error
+}
+
+// Personalizer fills template variables for a specific profile
+type Personalizer interface {
+	Personalize(ctx context.Context, page *rod.Page, profileURL, content string) string
+}
+
+// Reviewer lets a person approve, edit or skip each personalized note before
+// it is sent. It returns the note to send and whether to send it at all.
+type Reviewer interface {
+	Review(recipientURL, content string) (string, bool, error)
+}
+
+// Blacklist tells why a profile must not be contacted, or returns an empty
+// string if it may be. The page is used to read the profile's name and company
+// when it is showing the profile.
+type Blacklist interface {
+	Blocked(ctx context.Context, page *rod.Page, profileURL string) (string, error)
+}
+
+// Capturer saves the page for debugging when an action fails
+type Capturer interface {
+	Failure(page *rod.Page, action string, err error)
+}
+
+// BatchOptions controls how a batch run is tracked
+type BatchOptions struct {
+	BatchID  string    // Identifier used to record progress in storage
+	Resume   bool      // Skip profiles already completed under the same batch ID
+	Variants []Variant // When set, each profile gets a randomly assigned variant instead of the batch message
+	Progress Progress  // Told about each profile as the batch works through it; may be nil
+}
+
+// Progress follows a running batch, e.g. so it can be watched from another terminal
+type Progress interface {
+	Processing(profileURL string)
+	Processed(success, skipped bool)
 }
 
 // ConnectionRequest represents a connection request
@@ -42,6 +135,27 @@ type ConnectionResult struct {
 	AlreadyConnected bool
 	RequestSent    bool
 	RequestID      string
+	Skipped        bool
+	Message        string // The note sent after personalization
+	Variant        string // Name of the A/B variant used, if any
+	Err            error  // Cause of a failed attempt, classified by package errs
+	WeeklyLimit    bool   // LinkedIn refused the request because the weekly invitation limit was reached
+	EmailRequired  bool   // Skipped because LinkedIn asks for the member's email address to connect
+	DryRun         bool   // The request was ready to send but not sent, because this is a dry run
+	Rejected       bool   // Skipped because the reviewer declined the note
+	Blacklisted    bool   // Skipped because the profile is on the blacklist
+	Action         string // What was done: ActionConnect, or the fallback taken when the profile offers no Connect button
+	Followed       bool   // The profile was followed instead, or was already followed
+	Messaged       bool   // The note went out as an open profile message instead
+}
+
+// BatchResult represents the outcome of a batch of connection requests
+type BatchResult struct {
+	Results        []*ConnectionResult
+	StoppedAtLimit bool   // The batch ended early because a quota was exhausted
+	StopReason     string
+	StopErr        error  // The limit error that ended the batch
+	Interrupted    bool   // The batch ended early because ctx was cancelled, e.g. by Ctrl-C
 }
 
 // MessageTemplate represents a connection message template
@@ -55,29 +169,104 @@ type MessageTemplate struct {
 // NewConnectManager creates a new connection manager
 func NewConnectManager(page *rod.Page, logger *logrus.Logger, stealth StealthManager) *ConnectManager {
 	return &ConnectManager{
-		page:    page,
-		logger:  logger,
-		stealth: stealth,
+		page:        page,
+		logger:      logger,
+		stealth:     stealth,
+		retryPolicy: retry.DefaultPolicy(),
 	}
 }
 
+// SetBatchStore enables per-profile progress tracking for batch operations
+func (c *ConnectManager) SetBatchStore(store BatchStore) {
+	c.batchStore = store
+}
+
+// SetRateLimiter enables quota enforcement for batch operations
+func (c *ConnectManager) SetRateLimiter(limiter RateLimiter) {
+	c.rateLimiter = limiter
+}
+
+// SetRetryPolicy controls how batch operations retry transient failures
+func (c *ConnectManager) SetRetryPolicy(policy retry.Policy) {
+	c.retryPolicy = policy
+}
+
+// SetLimitStore persists LinkedIn's weekly invitation limit, so later batches
+// stop without visiting profiles until it has passed
+func (c *ConnectManager) SetLimitStore(store LimitStore) {
+	c.limitStore = store
+}
+
+// SetDryRun makes requests go through the profile and dialog checks but stop
+// short of clicking send
+func (c *ConnectManager) SetDryRun(dryRun bool) {
+	c.dryRun = dryRun
+}
+
+// SetReviewer asks reviewer to approve every note before its request is sent
+func (c *ConnectManager) SetReviewer(reviewer Reviewer) {
+	c.reviewer = reviewer
+}
+
+// SetBlacklist makes every request check the do-not-contact list first
+func (c *ConnectManager) SetBlacklist(blacklist Blacklist) {
+	c.blacklist = blacklist
+}
+
+// SetPersonalizer enables filling template variables from profile data before sending
+func (c *ConnectManager) SetPersonalizer(personalizer Personalizer) {
+	c.personalizer = personalizer
+}
+
+// SetInterleave runs action after about every n requests of a batch, such as
+// browsing the feed, at random gaps between n/2 and 3n/2 so the batch does not
+// break off on a fixed beat
+func (c *ConnectManager) SetInterleave(n int, action func(ctx context.Context) error) {
+	c.interleaveN = n
+	c.interleave = action
+	c.interleaveIn = interleaveGap(n)
+}
+
+// SetNavigator makes profiles open through navigator instead of by URL
+func (c *ConnectManager) SetNavigator(navigator Navigator) {
+	c.navigator = navigator
+}
+
+// SetCapturer saves a screenshot and HTML snapshot of the page whenever a
+// request fails
+func (c *ConnectManager) SetCapturer(capturer Capturer) {
+	c.capturer = capturer
+}
+
 // SendConnectionRequest sends a connection request to a profile
-func (c *ConnectManager) SendConnectionRequest(ctx context.Context, profileURL, message string) (*ConnectionResult, error) {
+func (c *ConnectManager) SendConnectionRequest(ctx context.Context, profileURL, message string) (result *ConnectionResult, err error) {
 	c.logger.WithFields(logrus.Fields{
 		"profile_url": profileURL,
 		"has_message": message != "",
 	}).Info("Sending connection request")
+	defer c.withContext(ctx)()
+	defer func() { c.captureFailure("connect", err) }()
 
-	result := &ConnectionResult{
+	result = &ConnectionResult{
 		ProfileURL: profileURL,
 	}
 
+	// Profile URLs and stored profile data are checked before visiting the profile
+	if blocked, err := c.checkBlacklist(ctx, nil, result); blocked || err != nil {
+		return result, err
+	}
+
 	// Navigate to profile
 	if err := c.navigateToProfile(profileURL); err != nil {
 		result.ErrorMessage = fmt.Sprintf("Failed to navigate to profile: %v", err)
 		return result, err
 	}
 
+	// Names and companies missing from storage can now be read from the page
+	if blocked, err := c.checkBlacklist(ctx, c.page, result); blocked || err != nil {
+		return result, err
+	}
+
 	// Check if already connected
 	if connected, err := c.isAlreadyConnected(); err == nil && connected {
 		result.AlreadyConnected = true
@@ -86,24 +275,76 @@ func (c *ConnectManager) SendConnectionRequest(ctx context.Context, profileURL,
 		return result, nil
 	}
 
+	// Read the profile before acting on it, for longer the more there is to read
+	if err := c.stealth.ReadProfile(c.page); err != nil {
+		return result, err
+	}
+
+	// Fill template variables from the profile we're now viewing
+	if c.personalizer != nil {
+		message = c.personalizer.Personalize(ctx, c.page, profileURL, message)
+	}
+	message = c.fitNote(profileURL, message)
+	result.Message = message
+
+	// Requests without a note have nothing to review
+	if c.reviewer != nil && message != "" {
+		reviewed, send, err := c.reviewer.Review(profileURL, message)
+		if err != nil {
+			result.ErrorMessage = err.Error()
+			return result, err
+		}
+		if !send {
+			c.logger.Info("Connection request skipped in review")
+			result.Skipped = true
+			result.Rejected = true
+			result.ErrorMessage = "Skipped in review"
+			return result, nil
+		}
+		message = c.fitNote(profileURL, reviewed)
+		result.Message = message
+	}
+
 	// Find and click connect button
 	if err := c.clickConnectButton(); err != nil {
+		if errors.Is(err, errNoConnectButton) && len(c.fallbacks) > 0 {
+			return c.fallBack(ctx, result, message, err)
+		}
 		result.ErrorMessage = fmt.Sprintf("Failed to click connect button: %v", err)
 		return result, err
 	}
 
 	// Add random delay
-	time.Sleep(c.stealth.RandomDelay())
+	if err := pause.Sleep(ctx, c.stealth.RandomDelay()); err != nil {
+		return result, err
+	}
 
 	// Handle connection dialog
 	dialogResult, err := c.handleConnectionDialog(message)
 	if err != nil {
+		if errors.Is(err, errs.ErrEmailRequired) {
+			c.logger.Info("Connection requires the member's email address, skipping")
+			result.Skipped = true
+			result.EmailRequired = true
+			result.ErrorMessage = "LinkedIn requires the member's email address to connect"
+			c.dismissDialog()
+			return result, nil
+		}
+		if errors.Is(err, errs.ErrWeeklyInviteLimit) {
+			c.logger.Warn("LinkedIn weekly invitation limit reached")
+			result.WeeklyLimit = true
+			result.ErrorMessage = "LinkedIn weekly invitation limit reached"
+			c.recordWeeklyLimit()
+			return result, err
+		}
 		result.ErrorMessage = fmt.Sprintf("Failed to handle connection dialog: %v", err)
 		return result, err
 	}
 
-	result.RequestSent = dialogResult.Success
+	result.Action = ActionConnect
+	result.RequestSent = dialogResult.RequestSent
 	result.Success = dialogResult.Success
+	result.DryRun = dialogResult.DryRun
 	result.ErrorMessage = dialogResult.ErrorMessage
 
 	c.logger.WithFields(logrus.Fields{
@@ -124,8 +365,10 @@ func (c *ConnectManager) SendConnectionRequestWithTemplate(ctx context.Context,
 }
 
 // CheckConnectionStatus checks the connection status with a profile
-func (c *ConnectManager) CheckConnectionStatus(ctx context.Context, profileURL string) (string, error) {
+func (c *ConnectManager) CheckConnectionStatus(ctx context.Context, profileURL string) (status string, err error) {
 	c.logger.WithField("profile_url", profileURL).Debug("Checking connection status")
+	defer c.withContext(ctx)()
+	defer func() { c.captureFailure("check connection status", err) }()
 
 	if err := c.navigateToProfile(profileURL); err != nil {
 		return "unknown", fmt.Errorf("failed to navigate to profile: %w", err)
@@ -148,40 +391,153 @@ func (c *ConnectManager) CheckConnectionStatus(ctx context.Context, profileURL s
 }
 
 // BatchSendConnectionRequests sends multiple connection requests
-func (c *ConnectManager) BatchSendConnectionRequests(ctx context.Context, profiles []string, message string) ([]*ConnectionResult, error) {
-	c.logger.WithField("count", len(profiles)).Info("Starting batch connection requests")
+func (c *ConnectManager) BatchSendConnectionRequests(ctx context.Context, profiles []string, message string, opts BatchOptions) (*BatchResult, error) {
+	c.logger.WithFields(logrus.Fields{
+		"count":    len(profiles),
+		"batch_id": opts.BatchID,
+		"resume":   opts.Resume,
+	}).Info("Starting batch connection requests")
+	defer c.withContext(ctx)()
 
+	batch := &BatchResult{}
 	results := make([]*ConnectionResult, 0, len(profiles))
 
+	if until := c.weeklyLimitUntil(); time.Now().Before(until) {
+		err := fmt.Errorf("%w until %s", errs.ErrWeeklyInviteLimit, until.Local().Format("2006-01-02 15:04"))
+		c.logger.WithError(err).Warn("Not sending connection requests")
+		batch.Results = results
+		batch.StoppedAtLimit = true
+		batch.StopReason = err.Error()
+		batch.StopErr = err
+		return batch, nil
+	}
+
 	for i, profileURL := range profiles {
+		if ctx.Err() != nil {
+			c.logger.WithField("processed", i).Warn("Batch interrupted")
+			batch.Interrupted = true
+			break
+		}
+
 		c.logger.WithFields(logrus.Fields{
 			"current": i + 1,
 			"total":   len(profiles),
 			"profile": profileURL,
 		}).Debug("Processing profile")
+		if opts.Progress != nil {
+			opts.Progress.Processing(profileURL)
+		}
+
+		if opts.Resume && c.isBatchItemCompleted(opts.BatchID, profileURL) {
+			c.logger.WithField("profile", profileURL).Info("Skipping profile already processed in this batch")
+			results = append(results, &ConnectionResult{
+				ProfileURL: profileURL,
+				Skipped:    true,
+			})
+			reportProcessed(opts.Progress, results[len(results)-1])
+			continue
+		}
 
-		result, err := c.SendConnectionRequest(ctx, profileURL, message)
+		// Profiles the blacklist already names are skipped before waiting for a
+		// request; a failed check is left to the send, which checks again
+		skipped := &ConnectionResult{ProfileURL: profileURL}
+		if blocked, _ := c.checkBlacklist(ctx, nil, skipped); blocked {
+			results = append(results, skipped)
+			reportProcessed(opts.Progress, skipped)
+			continue
+		}
+
+		if c.rateLimiter != nil {
+			if err := c.rateLimiter.WaitForPermission(ctx, ratelimit.ActionConnect); err != nil {
+				if ctx.Err() != nil {
+					c.logger.WithField("processed", i).Warn("Batch interrupted")
+					batch.Interrupted = true
+					break
+				}
+				if !errors.Is(err, ratelimit.ErrLimitReached) {
+					batch.Results = results
+					return batch, err
+				}
+				c.logger.WithError(err).Warn("Stopping batch at rate limit")
+				batch.StoppedAtLimit = true
+				batch.StopReason = err.Error()
+				batch.StopErr = err
+				break
+			}
+		}
+
+		note := message
+		var variant string
+		if len(opts.Variants) > 0 {
+			picked := PickVariant(opts.Variants)
+			note, variant = picked.Content, picked.Name
+			c.logger.WithField("variant", variant).Debug("Assigned message variant")
+		}
+
+		var result *ConnectionResult
+		err := c.retryPolicy.Do(ctx, func() error {
+			var err error
+			result, err = c.SendConnectionRequest(ctx, profileURL, note)
+			return err
+		}, func(attempt int, err error, delay time.Duration) {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"attempt": attempt,
+				"delay":   delay.Round(time.Second),
+			}).Warn("Connection request failed, retrying")
+		})
+		if err != nil && ctx.Err() != nil {
+			// Left unrecorded so a resumed batch sends this request again
+			c.logger.WithField("processed", i).Warn("Batch interrupted")
+			batch.Interrupted = true
+			break
+		}
 		if err != nil {
+			if errors.Is(err, ratelimit.ErrLimitReached) {
+				if result.WeeklyLimit {
+					result.Err = err
+					result.Variant = variant
+					results = append(results, result)
+					c.recordBatchItem(opts.BatchID, result)
+					reportProcessed(opts.Progress, result)
+				}
+				c.logger.WithError(err).Warn("Stopping batch at LinkedIn limit")
+				batch.StoppedAtLimit = true
+				batch.StopReason = err.Error()
+				batch.StopErr = err
+				break
+			}
+			if errors.Is(err, errs.ErrSessionExpired) || errors.Is(err, errs.ErrAborted) {
+				batch.Results = append(results, result)
+				return batch, err
+			}
 			c.logger.WithError(err).Error("Failed to send connection request")
 		}
+		result.Err = err
+		result.Variant = variant
 
 		results = append(results, result)
+		c.recordBatchItem(opts.BatchID, result)
+		reportProcessed(opts.Progress, result)
 
 		// Add delay between requests
-		if i < len(profiles)-1 {
-			time.Sleep(c.stealth.RandomDelay())
-			
+		if i < len(profiles)-1 && pause.Sleep(ctx, c.stealth.RandomDelay()) == nil {
 			// Add idle movement
 			if err := c.stealth.AddIdleMovement(c.page); err != nil {
 				c.logger.WithError(err).Warn("Failed to add idle movement")
 			}
+			c.runInterleave(ctx)
 		}
 	}
 
 	// Count results
 	successCount := 0
 	alreadyConnectedCount := 0
+	skippedCount := 0
 	for _, result := range results {
+		if result.Skipped {
+			skippedCount++
+			continue
+		}
 		if result.Success {
 			successCount++
 		}
@@ -194,17 +550,148 @@ func (c *ConnectManager) BatchSendConnectionRequests(ctx context.Context, profil
 		"total": len(profiles),
 		"success": successCount,
 		"already_connected": alreadyConnectedCount,
+		"skipped": skippedCount,
+		"stopped_at_limit": batch.StoppedAtLimit,
 	}).Info("Batch connection requests completed")
 
-	return results, nil
+	batch.Results = results
+	return batch, nil
 }
 
 // Private helper methods
 
+// withContext binds the page to ctx until the returned function is called,
+// so navigation, element lookups and pauses stop once ctx is cancelled
+func (c *ConnectManager) withContext(ctx context.Context) func() {
+	page := c.page
+	c.page = page.Context(ctx)
+	return func() { c.page = page }
+}
+
+// captureFailure saves the page when an action failed with err
+func (c *ConnectManager) captureFailure(action string, err error) {
+	if err != nil && c.capturer != nil {
+		c.capturer.Failure(c.page, action, err)
+	}
+}
+
+// reportProcessed counts a finished profile towards the batch progress, if it is followed
+func reportProcessed(progress Progress, result *ConnectionResult) {
+	if progress == nil || result == nil {
+		return
+	}
+	skipped := result.Skipped || result.EmailRequired || result.Blacklisted || result.Rejected
+	progress.Processed(result.Success && !skipped, skipped)
+}
+
+func (c *ConnectManager) isBatchItemCompleted(batchID, profileURL string) bool {
+	if c.batchStore == nil || batchID == "" {
+		return false
+	}
+
+	completed, err := c.batchStore.IsBatchItemCompleted(batchID, profileURL)
+	if err != nil {
+		c.logger.WithError(err).Warn("Failed to check batch progress")
+		return false
+	}
+	return completed
+}
+
+// checkBlacklist marks result as skipped if the profile is on the blacklist
+func (c *ConnectManager) checkBlacklist(ctx context.Context, page *rod.Page, result *ConnectionResult) (bool, error) {
+	if c.blacklist == nil {
+		return false, nil
+	}
+
+	reason, err := c.blacklist.Blocked(ctx, page, result.ProfileURL)
+	if err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to check blacklist: %v", err)
+		return false, err
+	}
+	if reason == "" {
+		return false, nil
+	}
+
+	result.Skipped = true
+	result.Blacklisted = true
+	result.ErrorMessage = "On the blacklist: " + reason
+	return true, nil
+}
+
+func (c *ConnectManager) recordBatchItem(batchID string, result *ConnectionResult) {
+	// A dry run must not make a later real run skip the profile, and
+	// blacklisted profiles are checked again in case they were removed
+	if c.batchStore == nil || batchID == "" || c.dryRun || result.Blacklisted {
+		return
+	}
+
+	status := storage.BatchItemFailed
+	if result.Success {
+		status = storage.BatchItemSuccess
+	} else if result.EmailRequired || result.Rejected {
+		// Retrying cannot succeed or was declined, so resumed batches skip it as well
+		status = storage.BatchItemSkipped
+	}
+
+	if err := c.batchStore.RecordBatchItem(batchID, "connect", result.ProfileURL, status, result.ErrorMessage); err != nil {
+		c.logger.WithError(err).Warn("Failed to record batch progress")
+	}
+}
+
+// weeklyLimitUntil returns when the stored weekly limit block ends
+func (c *ConnectManager) weeklyLimitUntil() time.Time {
+	if c.limitStore == nil {
+		return time.Time{}
+	}
+
+	until, err := c.limitStore.GetActionBlock(string(ratelimit.ActionConnect))
+	if err != nil {
+		c.logger.WithError(err).Warn("Failed to check invitation block")
+		return time.Time{}
+	}
+	return until
+}
+
+func (c *ConnectManager) recordWeeklyLimit() {
+	if closeButton, _ := selectors.Find(c.page, selectors.InviteLimitClose); closeButton != nil {
+		if err := c.stealth.HumanClick(c.page, closeButton); err != nil {
+			c.logger.WithError(err).Debug("Failed to dismiss limit alert")
+		}
+	}
+
+	if c.limitStore == nil {
+		return
+	}
+	until := time.Now().Add(weeklyLimitBlock)
+	if err := c.limitStore.SetActionBlock(string(ratelimit.ActionConnect), until, errs.ErrWeeklyInviteLimit.Error()); err != nil {
+		c.logger.WithError(err).Warn("Failed to store invitation block")
+	}
+}
+
+// weeklyLimitReached reports whether LinkedIn is showing its weekly invitation limit alert
+func (c *ConnectManager) weeklyLimitReached() bool {
+	if alert, _ := selectors.Find(c.page, selectors.InviteLimitAlert); alert != nil {
+		return true
+	}
+
+	// The alert's classes change often; its wording is more stable
+	for _, selector := range selectors.Get(selectors.InviteDialog) {
+		element, err := c.page.Element(selector)
+		if err != nil || element == nil {
+			continue
+		}
+		text, err := element.Text()
+		if err == nil && uitext.Contains(text, uitext.WeeklyLimit) {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *ConnectManager) navigateToProfile(profileURL string) error {
 	c.logger.WithField("url", profileURL).Debug("Navigating to profile")
 
-	if err := c.page.Navigate(profileURL); err != nil {
+	if err := c.openProfile(profileURL); err != nil {
 		return fmt.Errorf("failed to navigate to profile: %w", err)
 	}
 
@@ -213,6 +700,12 @@ func (c *ConnectManager) navigateToProfile(profileURL string) error {
 		return fmt.Errorf("failed to wait for page load: %w", err)
 	}
 
+	if info, err := c.page.Info(); err == nil {
+		if err := errs.CheckPageURL(info.URL); err != nil {
+			return err
+		}
+	}
+
 	// Wait for profile content to load
 	if err := c.waitForProfileContent(); err != nil {
 		return fmt.Errorf("failed to wait for profile content: %w", err)
@@ -221,50 +714,51 @@ func (c *ConnectManager) navigateToProfile(profileURL string) error {
 	return nil
 }
 
-func (c *ConnectManager) waitForProfileContent() error {
-	selectors := []string{
-		".pv-profile-wrapper",
-		".profile-content",
-		".pv-top-card",
-		"[data-test-id='profile-wrapper']",
+// runInterleave runs the interleaved action once its gap of requests has passed
+func (c *ConnectManager) runInterleave(ctx context.Context) {
+	if c.interleave == nil || c.interleaveN <= 0 {
+		return
 	}
-
-	for _, selector := range selectors {
-		element, err := c.page.Element(selector)
-		if err == nil && element != nil {
-			c.logger.WithField("selector", selector).Debug("Found profile content")
-			return nil
-		}
+	if c.interleaveIn--; c.interleaveIn > 0 {
+		return
+	}
+	c.interleaveIn = interleaveGap(c.interleaveN)
+	if err := c.interleave(ctx); err != nil && ctx.Err() == nil {
+		c.logger.WithError(err).Warn("Interleaved action failed")
 	}
+}
 
-	// Wait a bit and try again
-	time.Sleep(2 * time.Second)
-	
-	for _, selector := range selectors {
-		element, err := c.page.Element(selector)
-		if err == nil && element != nil {
-			c.logger.WithField("selector", selector).Debug("Found profile content after delay")
-			return nil
-		}
+// interleaveGap draws the number of requests until the next interleaved action
+func interleaveGap(n int) int {
+	if n <= 1 {
+		return n
 	}
+	return n/2 + rand.Intn(n+1)
+}
 
-	return fmt.Errorf("profile content not found")
+// openProfile opens profileURL through the navigator, if one is set
+func (c *ConnectManager) openProfile(profileURL string) error {
+	if c.navigator == nil {
+		return c.page.Navigate(profileURL)
+	}
+	return c.navigator.OpenProfile(c.page, profileURL)
 }
 
-func (c *ConnectManager) isAlreadyConnected() (bool, error) {
-	selectors := []string{
-		".pv-s-profile-actions--connect.mutual",
-		"[data-test-id='profile-connect-button'][aria-label*='Connected']",
-		".pv-s-profile-actions--message",
-		"[data-test-id='profile-message-button']",
+func (c *ConnectManager) waitForProfileContent() error {
+	// Slow connections render the profile late, so keep looking for a while
+	if _, err := selectors.Wait(c.page, selectors.ProfileContent); err != nil {
+		return fmt.Errorf("profile content not found: %w", err)
 	}
+	return nil
+}
 
-	for _, selector := range selectors {
+func (c *ConnectManager) isAlreadyConnected() (bool, error) {
+	for _, selector := range selectors.Get(selectors.ProfileConnected) {
 		element, err := c.page.Element(selector)
 		if err == nil && element != nil {
 			// Check if it indicates connection
 			text, err := element.Text()
-			if err == nil && (strings.Contains(text, "Message") || strings.Contains(text, "Connected")) {
+			if err == nil && (uitext.Contains(text, uitext.Message) || uitext.Contains(text, uitext.Connected)) {
 				return true, nil
 			}
 		}
@@ -274,13 +768,7 @@ func (c *ConnectManager) isAlreadyConnected() (bool, error) {
 }
 
 func (c *ConnectManager) isRequestPending() (bool, error) {
-	selectors := []string{
-		".pv-s-profile-actions--connect.pending",
-		"[data-test-id='profile-connect-button'][aria-label*='Pending']",
-		".pv-s-profile-actions--withdraw",
-	}
-
-	for _, selector := range selectors {
+	for _, selector := range selectors.Get(selectors.ProfilePending) {
 		element, err := c.page.Element(selector)
 		if err == nil && element != nil {
 			return true, nil
@@ -291,19 +779,13 @@ func (c *ConnectManager) isRequestPending() (bool, error) {
 }
 
 func (c *ConnectManager) isNotConnected() (bool, error) {
-	selectors := []string{
-		".pv-s-profile-actions--connect:not(.pending):not(.mutual)",
-		"[data-test-id='profile-connect-button']",
-		".pvs-profile-actions__action",
-	}
-
-	for _, selector := range selectors {
+	for _, selector := range selectors.Get(selectors.ProfileActions) {
 		elements, err := c.page.Elements(selector)
 		if err == nil && len(elements) > 0 {
 			// Check if any element is a connect button
 			for _, element := range elements {
 				text, err := element.Text()
-				if err == nil && strings.Contains(text, "Connect") {
+				if err == nil && uitext.Contains(text, uitext.Connect) {
 					return true, nil
 				}
 			}
@@ -316,24 +798,15 @@ func (c *ConnectManager) isNotConnected() (bool, error) {
 func (c *ConnectManager) clickConnectButton() error {
 	c.logger.Debug("Looking for connect button")
 
-	// Try different selectors for connect button
-	selectors := []string{
-		".pv-s-profile-actions--connect",
-		"[data-test-id='profile-connect-button']",
-		".pvs-profile-actions__action",
-		"button[aria-label*='Connect']",
-		"button:contains('Connect')",
-	}
-
 	var connectButton *rod.Element
 	var usedSelector string
 
-	for _, selector := range selectors {
+	for _, selector := range selectors.Get(selectors.ProfileConnectButton) {
 		element, err := c.page.Element(selector)
 		if err == nil && element != nil {
 			// Verify it's actually a connect button
 			text, err := element.Text()
-			if err == nil && strings.Contains(text, "Connect") {
+			if err == nil && uitext.Contains(text, uitext.Connect) {
 				connectButton = element
 				usedSelector = selector
 				break
@@ -342,45 +815,118 @@ func (c *ConnectManager) clickConnectButton() error {
 	}
 
 	if connectButton == nil {
-		return fmt.Errorf("connect button not found")
+		// Profiles that lead with Follow or Message keep Connect in the More menu
+		item, err := c.findConnectInMoreMenu()
+		if err != nil {
+			return err
+		}
+		connectButton = item
+		usedSelector = string(selectors.ProfileMoreConnect)
 	}
 
 	c.logger.WithField("selector", usedSelector).Debug("Found connect button")
 
-	// Get button position for human-like mouse movement
-	shape, err := connectButton.Shape()
-	if err != nil {
-		return fmt.Errorf("failed to get button position: %w", err)
+	// Scroll to the button and hover it before clicking
+	if err := c.stealth.HumanClick(c.page, connectButton); err != nil {
+		return fmt.Errorf("failed to click connect button: %w", err)
 	}
-	box := shape.Box()
 
-	// Get viewport size
-	viewport, err := c.page.Eval("({width: window.innerWidth, height: window.innerHeight})")
-	if err != nil {
-		return fmt.Errorf("failed to get viewport: %w", err)
+	c.logger.Debug("Connect button clicked")
+	return nil
+}
+
+// findConnectInMoreMenu opens the profile's More menu and returns its Connect item
+func (c *ConnectManager) findConnectInMoreMenu() (*rod.Element, error) {
+	moreButton, _ := selectors.Find(c.page, selectors.ProfileMoreButton)
+	if moreButton == nil {
+		return nil, errNoConnectButton
+	}
+
+	c.logger.Debug("Connect button not shown, opening More menu")
+	if err := c.stealth.HumanClick(c.page, moreButton); err != nil {
+		return nil, fmt.Errorf("failed to open More menu: %w", err)
+	}
+	if err := pause.Page(c.page, c.stealth.RandomDelay()); err != nil {
+		return nil, err
+	}
+
+	for _, item := range selectors.FindAll(c.page, selectors.ProfileMoreConnect) {
+		label, _ := item.Attribute("aria-label")
+		text, _ := item.Text()
+		if (label != nil && uitext.Contains(*label, uitext.InviteToConnect)) || uitext.Equal(text, uitext.Connect) {
+			return item, nil
+		}
 	}
 
-	fromX := viewport.Value.Get("width").Num()
-	fromY := viewport.Value.Get("height").Num()
+	// Close the menu again so later steps see the profile as it was
+	if err := c.stealth.HumanClick(c.page, moreButton); err != nil {
+		c.logger.WithError(err).Debug("Failed to close More menu")
+	}
+	return nil, fmt.Errorf("%w in More menu", errNoConnectButton)
+}
 
-	// Move mouse to button
-	centerX := box.X + box.Width/2
-	centerY := box.Y + box.Height/2
+// handleDialogVariants deals with the steps LinkedIn may show before the
+// invitation dialog: "How do you know X?" is answered with Other, while a
+// request for the member's email address cannot be answered
+func (c *ConnectManager) handleDialogVariants() error {
+	if input, _ := selectors.Find(c.page, selectors.InviteEmailInput); input != nil {
+		return errs.ErrEmailRequired
+	}
 
-	// Human-like mouse movement
-	if err := c.stealth.HumanLikeMouseMove(c.page, fromX, fromY, centerX, centerY); err != nil {
-		c.logger.WithError(err).Warn("Failed to perform human-like mouse movement")
+	option, _ := selectors.Find(c.page, selectors.InviteHowKnow)
+	if option == nil {
+		return nil
 	}
 
-	// Click connect button
-	if err := connectButton.Click("left", 1); err != nil {
-		return fmt.Errorf("failed to click connect button: %w", err)
+	c.logger.Debug("Answering \"How do you know\" prompt")
+	if err := c.stealth.HumanClick(c.page, option); err != nil {
+		return fmt.Errorf("failed to choose relationship: %w", err)
+	}
+	if err := pause.Settle(c.page); err != nil {
+		return err
 	}
 
-	c.logger.Debug("Connect button clicked")
+	if next, _ := selectors.Find(c.page, selectors.InviteHowKnowNext); next != nil {
+		if err := c.stealth.HumanClick(c.page, next); err != nil {
+			return fmt.Errorf("failed to continue past relationship prompt: %w", err)
+		}
+		if err := pause.Settle(c.page); err != nil {
+			return err
+		}
+	}
+
+	// Some members also require an email once the relationship is chosen
+	if input, _ := selectors.Find(c.page, selectors.InviteEmailInput); input != nil {
+		return errs.ErrEmailRequired
+	}
 	return nil
 }
 
+// findSendButton returns the dialog's button sending the invitation, or
+// nil. Without a note "Send without a note" is tried first; once a note is
+// typed only the button sending it will do.
+func (c *ConnectManager) findSendButton(withoutNote bool) *rod.Element {
+	keys := []selectors.Key{selectors.InviteSendButton}
+	if withoutNote {
+		keys = []selectors.Key{selectors.InviteNoNote, selectors.InviteSendButton}
+	}
+	for _, key := range keys {
+		if button, _ := selectors.Find(c.page, key); button != nil {
+			return button
+		}
+	}
+	return nil
+}
+
+// dismissDialog closes an open invitation dialog without sending it
+func (c *ConnectManager) dismissDialog() {
+	if button, _ := selectors.Find(c.page, selectors.InviteDismiss); button != nil {
+		if err := c.stealth.HumanClick(c.page, button); err != nil {
+			c.logger.WithError(err).Debug("Failed to dismiss invitation dialog")
+		}
+	}
+}
+
 func (c *ConnectManager) handleConnectionDialog(message string) (*ConnectionResult, error) {
 	result := &ConnectionResult{
 		Success: false,
@@ -394,20 +940,38 @@ func (c *ConnectManager) handleConnectionDialog(message string) (*ConnectionResu
 		return result, err
 	}
 
-	// Check if message input is present
-	messageInput, err := c.page.Element("textarea[name='message']")
-	if err != nil {
-		messageInput, err = c.page.Element(".send-invite__message-input")
+	if err := c.handleDialogVariants(); err != nil {
+		result.ErrorMessage = err.Error()
+		return result, err
 	}
-	if err != nil {
-		messageInput, err = c.page.Element("textarea[placeholder*='add a note']")
+
+	// Check if message input is present, or the button opening it
+	messageInput, _ := selectors.Find(c.page, selectors.InviteNoteInput)
+	addNote, _ := selectors.Find(c.page, selectors.InviteAddNote)
+
+	if c.dryRun {
+		return c.dryRunDialog(result, messageInput != nil || addNote != nil, message)
+	}
+
+	// Newer dialogs ask whether to add a note before showing the input;
+	// without a note it is never opened
+	if messageInput == nil && addNote != nil && message != "" {
+		c.logger.Debug("Opening the note input")
+		if err := c.stealth.HumanClick(c.page, addNote); err != nil {
+			result.ErrorMessage = fmt.Sprintf("Failed to click add a note: %v", err)
+			return result, err
+		}
+		if err := pause.Settle(c.page); err != nil {
+			return result, err
+		}
+		messageInput, _ = selectors.Find(c.page, selectors.InviteNoteInput)
 	}
 
-	if err == nil && messageInput != nil && message != "" {
+	if messageInput != nil && message != "" {
 		c.logger.Debug("Found message input, typing message")
 
 		// Click message input
-		if err := messageInput.Click("left", 1); err != nil {
+		if err := c.stealth.HumanClick(c.page, messageInput); err != nil {
 			result.ErrorMessage = fmt.Sprintf("Failed to click message input: %v", err)
 			return result, err
 		}
@@ -422,31 +986,32 @@ func (c *ConnectManager) handleConnectionDialog(message string) (*ConnectionResu
 	}
 
 	// Find and click send button
-	sendButton, err := c.page.Element("button[aria-label*='Send invitation']")
-	if err != nil {
-		sendButton, err = c.page.Element(".send-invite__button")
-	}
-	if err != nil {
-		sendButton, err = c.page.Element("button[type='submit']")
-	}
-
-	if err != nil {
+	sendButton := c.findSendButton(message == "" || messageInput == nil)
+	if sendButton == nil {
 		result.ErrorMessage = "Send button not found"
-		return result, err
+		return result, fmt.Errorf("%w: send button not found", errs.ErrDialogNotFound)
 	}
 
 	c.logger.Debug("Clicking send button")
 
 	// Click send button
-	if err := sendButton.Click("left", 1); err != nil {
+	if err := c.stealth.HumanClick(c.page, sendButton); err != nil {
 		result.ErrorMessage = fmt.Sprintf("Failed to click send button: %v", err)
 		return result, err
 	}
 
-	// Wait for dialog to close
-	time.Sleep(1 * time.Second)
+	// Wait for dialog to close. The click went through, so a run cancelled
+	// meanwhile still records the request rather than sending it again.
+	if pause.Settle(c.page) != nil {
+		result.Success = true
+		result.RequestSent = true
+		return result, nil
+	}
 
 	// Check if request was sent successfully
+	if c.weeklyLimitReached() {
+		return result, errs.ErrWeeklyInviteLimit
+	}
 	if c.isRequestSentSuccessfully() {
 		result.Success = true
 		result.RequestSent = true
@@ -459,38 +1024,51 @@ func (c *ConnectManager) handleConnectionDialog(message string) (*ConnectionResu
 	return result, nil
 }
 
-func (c *ConnectManager) waitForConnectionDialog() error {
-	selectors := []string{
-		".send-invite-modal",
-		".modal__content",
-		"[data-test-id='connection-dialog']",
-		".artdeco-modal",
-	}
-
-	for i := 0; i < 10; i++ {
-		for _, selector := range selectors {
-			element, err := c.page.Element(selector)
-			if err == nil && element != nil {
-				c.logger.WithField("selector", selector).Debug("Found connection dialog")
-				return nil
-			}
-		}
-		time.Sleep(500 * time.Millisecond)
+// dryRunDialog checks that the invitation could be sent, then closes the
+// dialog without sending it
+func (c *ConnectManager) dryRunDialog(result *ConnectionResult, hasNoteInput bool, message string) (*ConnectionResult, error) {
+	// The note input is not opened, so the dialog still offers sending without one
+	if sendButton := c.findSendButton(true); sendButton == nil {
+		result.ErrorMessage = "Send button not found"
+		return result, fmt.Errorf("%w: send button not found", errs.ErrDialogNotFound)
 	}
+	if message != "" && !hasNoteInput {
+		// A real run sends the invitation without the note in this case
+		c.logger.Warn("Dry run: note input not found, the request would go out without a note")
+	}
+
+	c.dismissDialog()
 
-	return fmt.Errorf("connection dialog not found after waiting")
+	result.Success = true
+	result.DryRun = true
+	c.logger.WithField("message_length", len(message)).Info("Dry run: connection request not sent")
+	return result, nil
 }
 
-func (c *ConnectManager) isRequestSentSuccessfully() bool {
-	// Look for success indicators
-	selectors := []string{
-		".pv-s-profile-actions--connect.pending",
-		"[data-test-id='profile-connect-button'][aria-label*='Pending']",
-		".pv-s-profile-actions--withdraw",
-		".success-indicator",
+func (c *ConnectManager) waitForConnectionDialog() error {
+	limited := false
+	err := pause.Until(c.page, pause.Current().ElementTimeout, func() bool {
+		// The limit alert opens in place of the invitation dialog
+		if limited = c.weeklyLimitReached(); limited {
+			return true
+		}
+		element, selector := selectors.Find(c.page, selectors.InviteDialog)
+		if element != nil {
+			c.logger.WithField("selector", selector).Debug("Found connection dialog")
+		}
+		return element != nil
+	})
+	switch {
+	case limited:
+		return errs.ErrWeeklyInviteLimit
+	case errors.Is(err, pause.ErrTimeout):
+		return fmt.Errorf("%w: connection dialog did not appear", errs.ErrDialogNotFound)
 	}
+	return err
+}
 
-	for _, selector := range selectors {
+func (c *ConnectManager) isRequestSentSuccessfully() bool {
+	for _, selector := range selectors.Get(selectors.InviteSent) {
 		element, err := c.page.Element(selector)
 		if err == nil && element != nil {
 			return true
@@ -501,8 +1079,13 @@ func (c *ConnectManager) isRequestSentSuccessfully() bool {
 }
 
 func (c *ConnectManager) processTemplate(template string, variables map[string]string) string {
-	result := template
-	
+	result, err := personalize.Render(template, variables)
+	if err == nil {
+		return result
+	}
+	c.logger.WithError(err).Warn("Failed to render template, falling back to plain substitution")
+
+	result = template
 	for key, value := range variables {
 		placeholder := fmt.Sprintf("{{%s}}", key)
 		result = strings.ReplaceAll(result, placeholder, value)
@@ -0,0 +1,187 @@
+package connect
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/pause"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/selectors"
+	"linkedin-automation/uitext"
+)
+
+const connectionsURL = "https://www.linkedin.com/mynetwork/invite-connect/connections/"
+
+// maxConnectionScrolls bounds the scrolling through the connection list, which
+// loads about 40 connections at a time
+const maxConnectionScrolls = 500
+
+// relativeConnectedPattern matches dates such as "Connected 3 weeks ago"
+var relativeConnectedPattern = regexp.MustCompile(`(?i)(\d+|an?)\s+(minute|hour|day|week|month|year)s?\s+ago`)
+
+// Connection is a 1st-degree connection from the account's connection list
+type Connection struct {
+	Name        string
+	Headline    string
+	ProfileURL  string
+	ConnectedAt *time.Time // Nil when the card shows no date
+}
+
+// ExportConnections scrolls through the account's connection list and returns
+// up to maxResults connections, or all of them when maxResults is 0, most
+// recently connected first
+func (c *ConnectManager) ExportConnections(ctx context.Context, maxResults int) (connections []*Connection, err error) {
+	c.logger.WithField("max_results", maxResults).Info("Exporting connections")
+	defer c.withContext(ctx)()
+	defer func() { c.captureFailure("export connections", err) }()
+
+	if err := c.page.Navigate(connectionsURL); err != nil {
+		return nil, fmt.Errorf("failed to navigate to connections page: %w", err)
+	}
+	if err := c.page.WaitLoad(); err != nil {
+		return nil, fmt.Errorf("failed to wait for page load: %w", err)
+	}
+	if _, err := selectors.Wait(c.page, selectors.NetworkConnectionCard); err != nil {
+		return nil, fmt.Errorf("no connections found on the connections page: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	now := time.Now()
+	for scroll := 0; scroll < maxConnectionScrolls; scroll++ {
+		select {
+		case <-ctx.Done():
+			return connections, ctx.Err()
+		default:
+		}
+
+		added := 0
+		for _, card := range selectors.FindAll(c.page, selectors.NetworkConnectionCard) {
+			if maxResults > 0 && len(connections) >= maxResults {
+				break
+			}
+			connection := c.extractConnection(card, now)
+			if connection == nil || seen[connection.ProfileURL] {
+				continue
+			}
+			seen[connection.ProfileURL] = true
+			connections = append(connections, connection)
+			added++
+		}
+
+		c.logger.WithFields(logrus.Fields{
+			"scroll": scroll + 1,
+			"added":  added,
+			"total":  len(connections),
+		}).Debug("Extracted connections")
+
+		if maxResults > 0 && len(connections) >= maxResults {
+			break
+		}
+		if !c.loadMoreConnections() {
+			break
+		}
+	}
+
+	c.logger.WithField("count", len(connections)).Info("Connections exported")
+	return connections, nil
+}
+
+// loadMoreConnections scrolls to the end of the list and clicks "Show more
+// results" if it is shown, reporting whether more connections were loaded
+func (c *ConnectManager) loadMoreConnections() bool {
+	before := len(selectors.FindAll(c.page, selectors.NetworkConnectionCard))
+
+	if err := c.stealth.HumanLikeScroll(c.page, 2000); err != nil {
+		c.logger.WithError(err).Debug("Failed to scroll connection list")
+	}
+	if button, _ := selectors.Find(c.page, selectors.ShowMoreButton); button != nil {
+		if err := c.stealth.HumanClick(c.page, button); err != nil {
+			c.logger.WithError(err).Debug("Failed to click show more button")
+		}
+	}
+
+	// Wait for the next chunk to render
+	for wait := 0; wait < 5; wait++ {
+		if pause.Page(c.page, c.stealth.RandomDelay()) != nil {
+			return false
+		}
+		if len(selectors.FindAll(c.page, selectors.NetworkConnectionCard)) > before {
+			return true
+		}
+	}
+	return false
+}
+
+// extractConnection reads a connection card, or returns nil if it has no profile link
+func (c *ConnectManager) extractConnection(card *rod.Element, now time.Time) *Connection {
+	link := selectors.FindIn(card, selectors.ProfileLink)
+	if link == nil {
+		return nil
+	}
+	href, err := link.Attribute("href")
+	if err != nil || href == nil || profileurl.Slug(*href) == "" {
+		return nil
+	}
+
+	return &Connection{
+		Name:        selectors.TextIn(card, selectors.NetworkConnectionName),
+		Headline:    selectors.TextIn(card, selectors.NetworkConnectionHeadline),
+		ProfileURL:  profileurl.Canonicalize(*href),
+		ConnectedAt: parseConnectedDate(selectors.TextIn(card, selectors.NetworkConnectionDate), now),
+	}
+}
+
+// parseConnectedDate reads a connection card's date, such as "Connected on
+// March 5, 2024" or "Connected 2 weeks ago", relative to now
+func parseConnectedDate(text string, now time.Time) *time.Time {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+
+	if i := strings.Index(strings.ToLower(text), "connected on"); i >= 0 {
+		date := strings.TrimSpace(text[i+len("connected on"):])
+		for _, layout := range []string{"January 2, 2006", "Jan 2, 2006", "2 January 2006", "January 2006"} {
+			if t, err := time.ParseInLocation(layout, date, now.Location()); err == nil {
+				return &t
+			}
+		}
+		return nil
+	}
+
+	match := relativeConnectedPattern.FindStringSubmatch(text)
+	if match == nil {
+		if uitext.Contains(text, uitext.Today) || uitext.Contains(text, uitext.JustNow) {
+			return &now
+		}
+		return nil
+	}
+
+	n := 1
+	if parsed, err := strconv.Atoi(match[1]); err == nil {
+		n = parsed
+	}
+	var t time.Time
+	switch strings.ToLower(match[2]) {
+	case "minute":
+		t = now.Add(-time.Duration(n) * time.Minute)
+	case "hour":
+		t = now.Add(-time.Duration(n) * time.Hour)
+	case "day":
+		t = now.AddDate(0, 0, -n)
+	case "week":
+		t = now.AddDate(0, 0, -7*n)
+	case "month":
+		t = now.AddDate(0, -n, 0)
+	case "year":
+		t = now.AddDate(-n, 0, 0)
+	}
+	return &t
+}
@@ -0,0 +1,162 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/pause"
+	"linkedin-automation/selectors"
+	"linkedin-automation/uitext"
+)
+
+// Actions a connection attempt can take on a profile
+const (
+	ActionConnect = "connect"
+	ActionFollow  = "follow"
+	ActionMessage = "message"
+)
+
+// errNoConnectButton means the profile offers no way to connect, e.g. because
+// it is in creator mode and leads with Follow
+var errNoConnectButton = errors.New("connect button not found")
+
+// OpenProfileMessenger messages members outside the network whose open
+// profile takes messages without an InMail credit. It returns
+// errs.ErrNotOpenProfile, without sending anything, for other profiles.
+type OpenProfileMessenger interface {
+	SendOpenProfileMessage(ctx context.Context, profileURL, subject, body string) error
+}
+
+// SetFallbacks sets what to do, in order, on profiles that offer no Connect
+// button: ActionFollow follows the profile, and ActionMessage sends the note
+// with subject as its subject line if the profile is an open profile. The
+// first one that succeeds is recorded as the result's action.
+func (c *ConnectManager) SetFallbacks(actions []string, messenger OpenProfileMessenger, subject string) {
+	c.fallbacks = actions
+	c.messenger = messenger
+	c.openProfileSubject = subject
+}
+
+// fallBack tries the configured fallbacks on a profile without a Connect
+// button, returning cause if none of them applies
+func (c *ConnectManager) fallBack(ctx context.Context, result *ConnectionResult, message string, cause error) (*ConnectionResult, error) {
+	// A request already sent hides the Connect button too
+	if pending, err := c.isRequestPending(); err == nil && pending {
+		result.ErrorMessage = fmt.Sprintf("Failed to click connect button: %v", cause)
+		return result, cause
+	}
+
+	for _, action := range c.fallbacks {
+		var err error
+		switch action {
+		case ActionFollow:
+			err = c.follow(result)
+		case ActionMessage:
+			err = c.messageOpenProfile(ctx, result, message)
+		default:
+			err = fmt.Errorf("unknown fallback %q", action)
+		}
+		if err == nil {
+			result.Action = action
+			result.Success = true
+			result.ErrorMessage = ""
+			c.logger.WithFields(logrus.Fields{
+				"action":  action,
+				"dry_run": result.DryRun,
+			}).Info("Connect unavailable, fallback completed")
+			return result, nil
+		}
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		c.logger.WithError(err).WithField("action", action).Debug("Fallback not possible")
+	}
+
+	result.ErrorMessage = fmt.Sprintf("Failed to click connect button: %v", cause)
+	return result, cause
+}
+
+// follow clicks the profile's Follow button, which creator mode shows in
+// place of Connect or keeps in the More menu
+func (c *ConnectManager) follow(result *ConnectionResult) error {
+	button := c.findFollowButton()
+	if button == nil {
+		if moreButton, _ := selectors.Find(c.page, selectors.ProfileMoreButton); moreButton != nil {
+			if err := c.stealth.HumanClick(c.page, moreButton); err != nil {
+				return fmt.Errorf("failed to open More menu: %w", err)
+			}
+			if err := pause.Page(c.page, c.stealth.RandomDelay()); err != nil {
+				return err
+			}
+			button = c.findFollowButton()
+		}
+	}
+	if button == nil {
+		return fmt.Errorf("follow button not found")
+	}
+
+	// Followed profiles show Following, or Unfollow in the More menu
+	if followLabel(button, uitext.Following) || followLabel(button, uitext.Unfollow) {
+		c.logger.Info("Already following profile")
+		result.Followed = true
+		return nil
+	}
+
+	if c.dryRun {
+		result.DryRun = true
+		return nil
+	}
+
+	if err := c.stealth.HumanClick(c.page, button); err != nil {
+		return fmt.Errorf("failed to click follow button: %w", err)
+	}
+	result.Followed = true
+	// The button turns into Following; nothing else needs to be confirmed
+	if err := pause.Settle(c.page); err != nil {
+		c.logger.WithError(err).Debug("Interrupted after following")
+	}
+	return nil
+}
+
+func (c *ConnectManager) findFollowButton() *rod.Element {
+	for _, element := range selectors.FindAll(c.page, selectors.ProfileFollowButton) {
+		if followLabel(element, uitext.Follow) {
+			return element
+		}
+	}
+	return nil
+}
+
+// followLabel reports whether an element's aria-label or text contains the wording for key
+func followLabel(element *rod.Element, key uitext.Key) bool {
+	if label, _ := element.Attribute("aria-label"); label != nil && uitext.Contains(*label, key) {
+		return true
+	}
+	text, _ := element.Text()
+	return uitext.Contains(text, key)
+}
+
+// messageOpenProfile sends the note as a free message if the profile is open
+func (c *ConnectManager) messageOpenProfile(ctx context.Context, result *ConnectionResult, message string) error {
+	if c.messenger == nil {
+		return fmt.Errorf("open profile messaging not configured")
+	}
+	if message == "" {
+		return fmt.Errorf("no note to send")
+	}
+
+	subject := c.openProfileSubject
+	if c.personalizer != nil {
+		subject = c.personalizer.Personalize(ctx, c.page, result.ProfileURL, subject)
+	}
+	if err := c.messenger.SendOpenProfileMessage(ctx, result.ProfileURL, subject, message); err != nil {
+		return err
+	}
+	result.Messaged = true
+	result.DryRun = c.dryRun
+	return nil
+}
@@ -0,0 +1,52 @@
+package connect
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SetNoteLimit sets the longest note LinkedIn accepts from the account,
+// 200 characters for free accounts and 300 for Premium. Longer notes are
+// shortened to fit before they are typed; 0 leaves them as they are.
+func (c *ConnectManager) SetNoteLimit(limit int) {
+	c.noteLimit = limit
+}
+
+// fitNote shortens note to the account's note limit, warning when it does
+func (c *ConnectManager) fitNote(profileURL, note string) string {
+	length := len([]rune(note))
+	if c.noteLimit <= 0 || length <= c.noteLimit {
+		return note
+	}
+
+	fitted := truncateNote(note, c.noteLimit)
+	c.logger.WithFields(logrus.Fields{
+		"profile_url":      profileURL,
+		"original_length":  length,
+		"truncated_length": len([]rune(fitted)),
+		"limit":            c.noteLimit,
+	}).Warn("Connection note truncated to the account's character limit")
+	return fitted
+}
+
+// truncateNote cuts note to at most limit characters, at the last word
+// boundary when there is one in the second half of the note
+func truncateNote(note string, limit int) string {
+	runes := []rune(note)
+	if len(runes) <= limit {
+		return note
+	}
+
+	cut := limit
+	for i := limit; i > limit/2; i-- {
+		if unicode.IsSpace(runes[i]) {
+			cut = i
+			break
+		}
+	}
+	return strings.TrimRightFunc(string(runes[:cut]), func(r rune) bool {
+		return unicode.IsSpace(r) || r == ',' || r == ';' || r == ':' || r == '-'
+	})
+}
@@ -0,0 +1,84 @@
+package connect
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/ratelimit"
+)
+
+// ParallelBatchSendConnectionRequests spreads profiles over managers, each
+// working its own browser tab, and runs their batches concurrently. Profiles
+// are dealt out round-robin so no two tabs visit the same profile. Each tab's
+// context is marked with ratelimit.WithTab, so the rate limiter the managers
+// have in common paces every tab by connect_delay on its own while the daily,
+// weekly, hourly and burst quotas stay shared. Results come back in the order
+// of profiles.
+func ParallelBatchSendConnectionRequests(ctx context.Context, managers []*ConnectManager, profiles []string, message string, opts BatchOptions) (*BatchResult, error) {
+	if len(managers) <= 1 || len(profiles) <= 1 {
+		return managers[0].BatchSendConnectionRequests(ctx, profiles, message, opts)
+	}
+	if len(managers) > len(profiles) {
+		managers = managers[:len(profiles)]
+	}
+
+	// Deal profiles out so every tab gets a mix of the list
+	shares := make([][]string, len(managers))
+	order := make(map[string]int, len(profiles))
+	for i, profileURL := range profiles {
+		shares[i%len(managers)] = append(shares[i%len(managers)], profileURL)
+		order[profileURL] = i
+	}
+
+	managers[0].logger.WithFields(logrus.Fields{
+		"count": len(profiles),
+		"tabs":  len(managers),
+	}).Info("Starting parallel batch connection requests")
+
+	batches := make([]*BatchResult, len(managers))
+	failures := make([]error, len(managers))
+	var wg sync.WaitGroup
+	for t, manager := range managers {
+		wg.Add(1)
+		go func(t int, manager *ConnectManager) {
+			defer wg.Done()
+			tabCtx := ratelimit.WithTab(ctx, t)
+			// Stagger the tabs so their requests do not go out in lockstep
+			if t > 0 {
+				select {
+				case <-ctx.Done():
+				case <-time.After(time.Duration(t) * manager.stealth.RandomDelay() / time.Duration(len(managers))):
+				}
+			}
+			batches[t], failures[t] = manager.BatchSendConnectionRequests(tabCtx, shares[t], message, opts)
+		}(t, manager)
+	}
+	wg.Wait()
+
+	merged := &BatchResult{}
+	var err error
+	for t, batch := range batches {
+		if failures[t] != nil && err == nil {
+			err = failures[t]
+		}
+		if batch == nil {
+			continue
+		}
+		merged.Results = append(merged.Results, batch.Results...)
+		if batch.StoppedAtLimit && !merged.StoppedAtLimit {
+			merged.StoppedAtLimit = true
+			merged.StopReason = batch.StopReason
+			merged.StopErr = batch.StopErr
+		}
+		merged.Interrupted = merged.Interrupted || batch.Interrupted
+	}
+	sort.SliceStable(merged.Results, func(i, j int) bool {
+		return order[merged.Results[i].ProfileURL] < order[merged.Results[j].ProfileURL]
+	})
+
+	return merged, err
+}
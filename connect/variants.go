@@ -0,0 +1,17 @@
+package connect
+
+import "math/rand"
+
+// Variant is one of several alternative notes tested against each other in a campaign
+type Variant struct {
+	Name    string // Recorded with each request so acceptance can be compared per variant
+	Content string
+}
+
+// PickVariant randomly assigns one of the variants, giving each an equal share
+func PickVariant(variants []Variant) Variant {
+	if len(variants) == 0 {
+		return Variant{}
+	}
+	return variants[rand.Intn(len(variants))]
+}
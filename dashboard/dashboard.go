@@ -0,0 +1,168 @@
+// Package dashboard is a terminal UI that follows a long-running daemon from
+// another terminal: limit gauges, queue depth, running batches, recent
+// results, alerts that need an operator and the daemon's live log.
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"linkedin-automation/progress"
+)
+
+// maxAlerts and maxLogLines bound what the dashboard keeps in memory
+const (
+	maxAlerts   = 50
+	maxLogLines = 500
+)
+
+// Gauge is the use of one action's limits over the last day and hour
+type Gauge struct {
+	Name        string
+	Daily       int
+	DailyLimit  int
+	Hourly      int
+	HourlyLimit int // 0 when the action has no hourly limit
+}
+
+// Count is a named number, e.g. the queue tasks with one status
+type Count struct {
+	Name  string
+	Count int
+}
+
+// Result is the outcome of one profile of a batch
+type Result struct {
+	At         time.Time
+	Action     string
+	ProfileURL string
+	Status     string
+	Detail     string // Error message of a failure
+}
+
+// Alert is something an operator should look at, such as a checkpoint
+type Alert struct {
+	At      time.Time
+	Message string
+}
+
+// Snapshot is everything the dashboard shows apart from the log
+type Snapshot struct {
+	Gauges  []Gauge
+	Queue   []Count
+	Running []*progress.State
+	Results []Result
+	Alerts  []Alert // Current conditions, e.g. an active LinkedIn block
+}
+
+// Source supplies a fresh snapshot on every refresh
+type Source interface {
+	Snapshot() (*Snapshot, error)
+}
+
+// Options controls refreshing and where the daemon's log is read from
+type Options struct {
+	Interval time.Duration
+	LogFile  string // JSON or text log of the daemon; empty hides the log panel
+}
+
+// Run shows the dashboard until the user quits or ctx is cancelled
+func Run(ctx context.Context, source Source, opts Options) error {
+	m := &model{
+		source: source,
+		opts:   opts,
+	}
+	if opts.LogFile != "" {
+		m.tail = newLogTail(opts.LogFile)
+	}
+
+	_, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(ctx)).Run()
+	if errors.Is(err, tea.ErrProgramKilled) && ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+type tickMsg time.Time
+
+type refreshMsg struct {
+	snapshot *Snapshot
+	lines    []logLine
+	err      error
+}
+
+// model is the bubbletea model; refreshes run as commands so a slow database
+// never blocks key handling
+type model struct {
+	source   Source
+	opts     Options
+	tail     *logTail
+	snapshot *Snapshot
+	lines    []logLine
+	alerts   []Alert // Raised from the log; kept until the dashboard is closed
+	err      error
+	updated  time.Time
+	width    int
+	height   int
+}
+
+func (m *model) Init() tea.Cmd {
+	return m.refresh
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "c":
+			m.alerts = nil
+		}
+
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+
+	case tickMsg:
+		return m, m.refresh
+
+	case refreshMsg:
+		m.err = msg.err
+		if msg.snapshot != nil {
+			m.snapshot = msg.snapshot
+		}
+		for _, line := range msg.lines {
+			if alert, ok := line.alert(); ok {
+				m.alerts = append(m.alerts, alert)
+			}
+		}
+		if len(m.alerts) > maxAlerts {
+			m.alerts = m.alerts[len(m.alerts)-maxAlerts:]
+		}
+		m.lines = append(m.lines, msg.lines...)
+		if len(m.lines) > maxLogLines {
+			m.lines = m.lines[len(m.lines)-maxLogLines:]
+		}
+		m.updated = time.Now()
+		return m, tea.Tick(m.opts.Interval, func(t time.Time) tea.Msg { return tickMsg(t) })
+	}
+
+	return m, nil
+}
+
+// refresh reads a snapshot and any new log lines
+func (m *model) refresh() tea.Msg {
+	var msg refreshMsg
+	msg.snapshot, msg.err = m.source.Snapshot()
+	if m.tail != nil {
+		lines, err := m.tail.read()
+		if err != nil && msg.err == nil {
+			msg.err = err
+		}
+		msg.lines = lines
+	}
+	return msg
+}
@@ -0,0 +1,162 @@
+package dashboard
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// initialTail is how much of an existing log is shown when the dashboard starts
+const initialTail = 64 << 10
+
+// logLine is one entry of the daemon's log
+type logLine struct {
+	At    time.Time
+	Level string
+	Text  string // Message followed by its fields
+}
+
+// alertPatterns map words in a log entry to the alert raised for it
+var alertPatterns = []struct {
+	match   string
+	message string
+}{
+	{"checkpoint", "Checkpoint needed"},
+	{"captcha", "CAPTCHA needs attention"},
+	{"session expired", "LinkedIn session expired; log in again"},
+	{"weekly invitation limit", "Weekly invitation limit reached"},
+	{"restricted the account", "LinkedIn restricted the account"},
+}
+
+// alert reports whether the entry needs an operator: checkpoints, CAPTCHAs,
+// expired sessions and LinkedIn limits at warning level or above, and every error
+func (l logLine) alert() (Alert, bool) {
+	if l.Level != "warning" && l.Level != "error" && l.Level != "fatal" && l.Level != "panic" {
+		return Alert{}, false
+	}
+
+	text := strings.ToLower(l.Text)
+	for _, pattern := range alertPatterns {
+		if strings.Contains(text, pattern.match) {
+			return Alert{At: l.At, Message: pattern.message + ": " + l.Text}, true
+		}
+	}
+	if l.Level != "warning" {
+		return Alert{At: l.At, Message: l.Text}, true
+	}
+	return Alert{}, false
+}
+
+// logTail follows a log file as it grows
+type logTail struct {
+	path    string
+	offset  int64
+	started bool
+	partial string // Last line when it is not complete yet
+}
+
+func newLogTail(path string) *logTail {
+	return &logTail{path: path}
+}
+
+// read returns the lines appended since the last read. The first read returns
+// the end of the existing log; a truncated or rotated log is read from the start.
+func (t *logTail) read() ([]logLine, error) {
+	file, err := os.Open(t.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil // The daemon has not written it yet
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	skipFirst := false
+	switch {
+	case !t.started:
+		t.started = true
+		if info.Size() > initialTail {
+			t.offset, skipFirst = info.Size()-initialTail, true
+		}
+	case info.Size() < t.offset:
+		t.offset, t.partial = 0, ""
+	}
+	if _, err := file.Seek(t.offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	t.offset += int64(len(data))
+
+	text := t.partial + string(data)
+	t.partial = ""
+	if !strings.HasSuffix(text, "\n") {
+		if i := strings.LastIndexByte(text, '\n'); i >= 0 {
+			text, t.partial = text[:i+1], text[i+1:]
+		} else {
+			text, t.partial = "", text
+		}
+	}
+
+	var lines []logLine
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for scanner.Scan() {
+		if skipFirst {
+			skipFirst = false // Started in the middle of this line
+			continue
+		}
+		if line := scanner.Text(); strings.TrimSpace(line) != "" {
+			lines = append(lines, parseLogLine(line))
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// parseLogLine reads a logrus JSON entry; other lines are kept as they are
+func parseLogLine(line string) logLine {
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		return logLine{At: time.Now(), Level: "info", Text: line}
+	}
+
+	parsed := logLine{At: time.Now(), Level: "info"}
+	if level, ok := entry["level"].(string); ok {
+		parsed.Level = level
+	}
+	if at, ok := entry["time"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, at); err == nil {
+			parsed.At = t
+		}
+	}
+
+	msg, _ := entry["msg"].(string)
+	var keys []string
+	for key := range entry {
+		if key != "level" && key != "time" && key != "msg" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	fields := make([]string, 0, len(keys)+1)
+	fields = append(fields, msg)
+	for _, key := range keys {
+		fields = append(fields, fmt.Sprintf("%s=%v", key, entry[key]))
+	}
+	parsed.Text = strings.Join(fields, " ")
+	return parsed
+}
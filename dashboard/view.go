@@ -0,0 +1,159 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// gaugeWidth is the number of cells in a limit bar
+const gaugeWidth = 20
+
+// Rows shown per section before the log takes the rest of the screen
+const (
+	maxResultRows = 8
+	maxAlertRows  = 5
+)
+
+func (m *model) View() string {
+	var b strings.Builder
+
+	header := "LinkedIn Automation Dashboard"
+	if !m.updated.IsZero() {
+		header += "  (updated " + m.updated.Format("15:04:05") + ")"
+	}
+	b.WriteString(header + "\n\n")
+
+	if m.snapshot == nil {
+		if m.err != nil {
+			fmt.Fprintf(&b, "Error: %v\n", m.err)
+		} else {
+			b.WriteString("Loading...\n")
+		}
+		return m.fit(b.String())
+	}
+	s := m.snapshot
+
+	b.WriteString("Limits\n")
+	for _, gauge := range s.Gauges {
+		line := fmt.Sprintf("  %-8s %s %4d/%-4d today", gauge.Name, bar(gauge.Daily, gauge.DailyLimit), gauge.Daily, gauge.DailyLimit)
+		if gauge.HourlyLimit > 0 {
+			line += fmt.Sprintf("   %d/%d this hour", gauge.Hourly, gauge.HourlyLimit)
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("\n")
+
+	queue := make([]string, 0, len(s.Queue))
+	for _, count := range s.Queue {
+		queue = append(queue, fmt.Sprintf("%s %d", count.Name, count.Count))
+	}
+	b.WriteString("Queue: " + strings.Join(queue, ", ") + "\n\n")
+
+	b.WriteString("Running Batches\n")
+	if len(s.Running) == 0 {
+		b.WriteString("  None\n")
+	}
+	for _, state := range s.Running {
+		line := fmt.Sprintf("  %-28s %s %d/%d  %d ok, %d failed, %d skipped", state.BatchID, state.Action,
+			state.Processed, state.Total, state.Succeeded, state.Failed, state.Skipped)
+		if eta, ok := state.ETA(); ok {
+			line += "  ETA " + eta.Round(time.Minute).String()
+		}
+		if wait := state.Waiting(); wait > 0 {
+			line += fmt.Sprintf("  waiting: %s %s", state.WaitReason, wait.Round(time.Second))
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString("Recent Results\n")
+	if len(s.Results) == 0 {
+		b.WriteString("  None\n")
+	}
+	for i, result := range s.Results {
+		if i == maxResultRows {
+			break
+		}
+		line := fmt.Sprintf("  %s %-8s %-8s %s", result.At.Local().Format("15:04"), result.Action, result.Status, result.ProfileURL)
+		if result.Detail != "" {
+			line += "  (" + result.Detail + ")"
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("\n")
+
+	alerts := append(append([]Alert{}, s.Alerts...), m.alerts...)
+	fmt.Fprintf(&b, "Alerts (%d)\n", len(alerts))
+	if len(alerts) == 0 {
+		b.WriteString("  None\n")
+	}
+	if len(alerts) > maxAlertRows {
+		alerts = alerts[len(alerts)-maxAlertRows:]
+	}
+	for _, alert := range alerts {
+		fmt.Fprintf(&b, "  ! %s %s\n", alert.At.Local().Format("15:04"), alert.Message)
+	}
+	b.WriteString("\n")
+
+	if m.err != nil {
+		fmt.Fprintf(&b, "Refresh failed: %v\n\n", m.err)
+	}
+
+	footer := "q quit  c clear alerts"
+	if m.tail == nil {
+		b.WriteString("Log: pass --log-file with the daemon's log to follow it here\n\n")
+		b.WriteString(footer)
+		return m.fit(b.String())
+	}
+
+	b.WriteString("Log (" + m.opts.LogFile + ")\n")
+	used := strings.Count(b.String(), "\n") + 2 // Room for the footer
+	rows := len(m.lines)
+	if m.height > 0 {
+		rows = m.height - used
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	lines := m.lines
+	if len(lines) > rows {
+		lines = lines[len(lines)-rows:]
+	}
+	for _, line := range lines {
+		fmt.Fprintf(&b, "  %s %-7s %s\n", line.At.Local().Format("15:04:05"), strings.ToUpper(line.Level), line.Text)
+	}
+	for i := len(lines); i < rows; i++ {
+		b.WriteString("\n")
+	}
+	b.WriteString(footer)
+
+	return m.fit(b.String())
+}
+
+// fit cuts lines to the terminal width so long log entries do not wrap
+func (m *model) fit(view string) string {
+	if m.width <= 0 {
+		return view
+	}
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		if utf8.RuneCountInString(line) > m.width {
+			lines[i] = string([]rune(line)[:m.width])
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// bar draws how much of a limit is used
+func bar(used, limit int) string {
+	filled := 0
+	if limit > 0 {
+		filled = used * gaugeWidth / limit
+	}
+	if filled > gaugeWidth {
+		filled = gaugeWidth
+	}
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", gaugeWidth-filled) + "]"
+}
@@ -0,0 +1,12 @@
+package main
+
+import "syscall"
+
+// shmSize returns the size of /dev/shm, which Chrome uses for shared memory
+func shmSize() (int64, bool) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs("/dev/shm", &stat); err != nil {
+		return 0, false
+	}
+	return int64(stat.Blocks) * stat.Bsize, true
+}
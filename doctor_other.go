@@ -0,0 +1,8 @@
+//go:build !linux
+
+package main
+
+// shmSize reports no /dev/shm outside Linux, where Chrome does not depend on it
+func shmSize() (int64, bool) {
+	return 0, false
+}
@@ -0,0 +1,373 @@
+// Package endorse endorses the skills of 1st-degree connections from their
+// profile's skills page.
+package endorse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/pause"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/selectors"
+	"linkedin-automation/uitext"
+)
+
+// EndorseManager endorses skills on connections' profiles
+type EndorseManager struct {
+	page        *rod.Page
+	logger      *logrus.Logger
+	stealth     StealthManager
+	rateLimiter RateLimiter
+	store       Store
+	dryRun      bool
+	capturer    Capturer
+}
+
+// StealthManager interface for stealth operations
+type StealthManager interface {
+	HumanLikeMouseMove(page *rod.Page, fromX, fromY, toX, toY float64) error
+	RandomDelay() time.Duration
+	HumanLikeScroll(page *rod.Page, scrollAmount int) error
+	AddIdleMovement(page *rod.Page) error
+}
+
+// RateLimiter gates actions against configured quotas
+type RateLimiter interface {
+	WaitForPermission(ctx context.Context, action ratelimit.ActionType) error
+}
+
+// Store reports earlier endorsements so skills are not endorsed twice
+type Store interface {
+	HasEndorsed(profileURL, skill string) (bool, error)
+}
+
+// Capturer saves the page for debugging when an action fails
+type Capturer interface {
+	Failure(page *rod.Page, action string, err error)
+}
+
+// Options controls which skills are endorsed on each profile
+type Options struct {
+	Skills    []string // Skills to endorse in order of preference, matched case-insensitively; empty means the top listed skills
+	MaxSkills int      // Skills endorsed per profile
+}
+
+// EndorsementResult represents the outcome of endorsing one skill
+type EndorsementResult struct {
+	Success      bool
+	ProfileURL   string
+	Skill        string // Empty when the skills page could not be read
+	ErrorMessage string
+	Skipped      bool // Endorsed in an earlier run, or already endorsed on LinkedIn
+	DryRun       bool // The skill was ready to endorse but not endorsed, because this is a dry run
+	CreatedAt    time.Time
+}
+
+// BatchResult represents the outcome of endorsing a list of profiles
+type BatchResult struct {
+	Results        []*EndorsementResult
+	StoppedAtLimit bool // The batch ended early because a quota was exhausted
+	StopReason     string
+	StopErr        error // The limit error that ended the batch
+}
+
+// skill is an entry on a profile's skills page
+type skill struct {
+	name     string
+	endorsed bool // Already endorsed by the account
+	button   *rod.Element
+}
+
+// NewEndorseManager creates a new endorsement manager
+func NewEndorseManager(page *rod.Page, logger *logrus.Logger, stealth StealthManager) *EndorseManager {
+	return &EndorseManager{
+		page:    page,
+		logger:  logger,
+		stealth: stealth,
+	}
+}
+
+// SetRateLimiter enables quota enforcement for endorsements
+func (e *EndorseManager) SetRateLimiter(limiter RateLimiter) {
+	e.rateLimiter = limiter
+}
+
+// SetDryRun makes endorsements find their buttons without clicking them
+func (e *EndorseManager) SetDryRun(dryRun bool) {
+	e.dryRun = dryRun
+}
+
+// SetStore enables skipping skills endorsed in earlier runs
+func (e *EndorseManager) SetStore(store Store) {
+	e.store = store
+}
+
+// SetCapturer saves a screenshot and HTML snapshot of the page whenever
+// loading skills or endorsing one fails
+func (e *EndorseManager) SetCapturer(capturer Capturer) {
+	e.capturer = capturer
+}
+
+// Endorse endorses up to opts.MaxSkills skills on each profile
+func (e *EndorseManager) Endorse(ctx context.Context, profiles []string, opts Options) (*BatchResult, error) {
+	e.logger.WithFields(logrus.Fields{
+		"profiles":   len(profiles),
+		"skills":     len(opts.Skills),
+		"max_skills": opts.MaxSkills,
+	}).Info("Starting endorsements")
+	defer e.withContext(ctx)()
+
+	batch := &BatchResult{}
+
+	for i, profileURL := range profiles {
+		// Stop between profiles when interrupted, keeping what was done so far
+		if err := ctx.Err(); err != nil {
+			return batch, err
+		}
+
+		skills, err := e.loadSkills(profileURL)
+		if err != nil && ctx.Err() != nil {
+			return batch, ctx.Err()
+		}
+		if err != nil {
+			e.logger.WithError(err).WithField("profile_url", profileURL).Warn("Failed to load skills")
+			e.captureFailure("load skills", err)
+			batch.Results = append(batch.Results, &EndorsementResult{
+				ProfileURL:   profileURL,
+				ErrorMessage: err.Error(),
+				CreatedAt:    time.Now(),
+			})
+			continue
+		}
+
+		for _, s := range pickSkills(skills, opts) {
+			result, err := e.endorse(ctx, profileURL, s)
+			if result != nil {
+				batch.Results = append(batch.Results, result)
+			}
+			if err != nil {
+				if errors.Is(err, ratelimit.ErrLimitReached) {
+					e.logger.WithError(err).Warn("Stopping endorsements at rate limit")
+					batch.StoppedAtLimit = true
+					batch.StopReason = err.Error()
+					batch.StopErr = err
+					return batch, nil
+				}
+				return batch, err
+			}
+		}
+
+		if i < len(profiles)-1 && pause.Sleep(ctx, e.stealth.RandomDelay()) == nil {
+			if err := e.stealth.AddIdleMovement(e.page); err != nil {
+				e.logger.WithError(err).Debug("Failed to add idle movement")
+			}
+		}
+	}
+
+	e.logger.WithField("endorsements", len(batch.Results)).Info("Endorsements completed")
+	return batch, nil
+}
+
+// loadSkills opens a profile's skills page and reads every listed skill
+func (e *EndorseManager) loadSkills(profileURL string) ([]*skill, error) {
+	slug := profileurl.Slug(profileURL)
+	if slug == "" {
+		return nil, fmt.Errorf("not a profile URL: %s", profileURL)
+	}
+
+	if err := e.page.Navigate("https://www.linkedin.com/in/" + slug + "/details/skills/"); err != nil {
+		return nil, fmt.Errorf("failed to navigate to skills: %w", err)
+	}
+	if err := e.page.WaitLoad(); err != nil {
+		return nil, fmt.Errorf("failed to wait for skills load: %w", err)
+	}
+	if _, err := selectors.Wait(e.page, selectors.SkillItem); err != nil {
+		return nil, fmt.Errorf("no skills listed: %w", err)
+	}
+
+	// Bring lazily rendered entries in
+	if err := e.stealth.HumanLikeScroll(e.page, 1200); err != nil {
+		e.logger.WithError(err).Debug("Failed to scroll skills page")
+	}
+	if err := pause.Page(e.page, e.stealth.RandomDelay()); err != nil {
+		return nil, err
+	}
+
+	var skills []*skill
+	seen := make(map[string]bool)
+	for _, item := range selectors.FindAll(e.page, selectors.SkillItem) {
+		name := selectors.TextIn(item, selectors.SkillName)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+
+		s := &skill{name: name}
+		if button := selectors.FindIn(item, selectors.SkillEndorseButton); button != nil {
+			s.button = button
+			s.endorsed = isEndorsed(button)
+		}
+		skills = append(skills, s)
+	}
+
+	e.logger.WithFields(logrus.Fields{
+		"profile_url": profileURL,
+		"skills":      len(skills),
+	}).Debug("Loaded skills")
+	return skills, nil
+}
+
+// endorse endorses one skill; a nil result means the batch stopped before acting
+func (e *EndorseManager) endorse(ctx context.Context, profileURL string, s *skill) (*EndorsementResult, error) {
+	result := &EndorsementResult{
+		ProfileURL: profileURL,
+		Skill:      s.name,
+		CreatedAt:  time.Now(),
+	}
+
+	if s.endorsed || e.hasEndorsed(profileURL, s.name) {
+		result.Skipped = true
+		return result, nil
+	}
+	if s.button == nil {
+		result.ErrorMessage = "endorse button not found"
+		return result, nil
+	}
+
+	if e.rateLimiter != nil {
+		if err := e.rateLimiter.WaitForPermission(ctx, ratelimit.ActionEndorse); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.button.ScrollIntoView(); err != nil {
+		e.logger.WithError(err).Debug("Failed to scroll skill into view")
+	}
+	if err := pause.Sleep(ctx, e.stealth.RandomDelay()); err != nil {
+		return nil, err
+	}
+
+	if !e.dryRun {
+		if err := e.click(s.button); err != nil {
+			result.ErrorMessage = err.Error()
+			e.logger.WithError(err).WithFields(logrus.Fields{
+				"profile_url": profileURL,
+				"skill":       s.name,
+			}).Warn("Endorsement failed")
+			e.captureFailure("endorse", err)
+			return result, nil
+		}
+	}
+
+	result.Success = true
+	result.DryRun = e.dryRun
+	e.logger.WithFields(logrus.Fields{
+		"profile_url": profileURL,
+		"skill":       s.name,
+	}).Info("Skill endorsed")
+	return result, nil
+}
+
+func (e *EndorseManager) click(button *rod.Element) error {
+	shape, err := button.Shape()
+	if err != nil {
+		return fmt.Errorf("failed to get button position: %w", err)
+	}
+	box := shape.Box()
+
+	viewport, err := e.page.Eval("({width: window.innerWidth, height: window.innerHeight})")
+	if err != nil {
+		return fmt.Errorf("failed to get viewport: %w", err)
+	}
+	fromX := viewport.Value.Get("width").Num() / 2
+	fromY := viewport.Value.Get("height").Num() / 2
+
+	if err := e.stealth.HumanLikeMouseMove(e.page, fromX, fromY, box.X+box.Width/2, box.Y+box.Height/2); err != nil {
+		e.logger.WithError(err).Warn("Failed to perform human-like mouse movement")
+	}
+
+	if err := button.Click("left", 1); err != nil {
+		return fmt.Errorf("failed to click button: %w", err)
+	}
+	return nil
+}
+
+func (e *EndorseManager) hasEndorsed(profileURL, skill string) bool {
+	if e.store == nil {
+		return false
+	}
+
+	endorsed, err := e.store.HasEndorsed(profileURL, skill)
+	if err != nil {
+		e.logger.WithError(err).Warn("Failed to check earlier endorsements")
+		return false
+	}
+	return endorsed
+}
+
+// withContext binds the page to ctx until the returned function is called,
+// so navigation, element lookups and pauses stop once ctx is cancelled
+func (e *EndorseManager) withContext(ctx context.Context) func() {
+	page := e.page
+	e.page = page.Context(ctx)
+	return func() { e.page = page }
+}
+
+// captureFailure saves the page when an action failed with err
+func (e *EndorseManager) captureFailure(action string, err error) {
+	if e.capturer != nil {
+		e.capturer.Failure(e.page, action, err)
+	}
+}
+
+// pickSkills chooses up to opts.MaxSkills skills: the configured skills in
+// their order of preference, or the top listed skills when none are configured.
+// Skills already endorsed are kept so they are reported as skipped, but do not
+// count against the maximum.
+func pickSkills(skills []*skill, opts Options) []*skill {
+	var candidates []*skill
+	if len(opts.Skills) == 0 {
+		candidates = skills
+	} else {
+		for _, wanted := range opts.Skills {
+			for _, s := range skills {
+				if strings.EqualFold(strings.TrimSpace(wanted), s.name) {
+					candidates = append(candidates, s)
+					break
+				}
+			}
+		}
+	}
+
+	var picked []*skill
+	count := 0
+	for _, s := range candidates {
+		if opts.MaxSkills > 0 && count >= opts.MaxSkills {
+			break
+		}
+		picked = append(picked, s)
+		if !s.endorsed {
+			count++
+		}
+	}
+	return picked
+}
+
+// isEndorsed reports whether an endorse button shows the skill as already endorsed
+func isEndorsed(button *rod.Element) bool {
+	if pressed, err := button.Attribute("aria-pressed"); err == nil && pressed != nil && *pressed == "true" {
+		return true
+	}
+	if label, err := button.Attribute("aria-label"); err == nil && label != nil && uitext.HasPrefix(*label, uitext.Endorsed) {
+		return true
+	}
+	text, err := button.Text()
+	return err == nil && uitext.Equal(text, uitext.Endorsed)
+}
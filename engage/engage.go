@@ -0,0 +1,486 @@
+package engage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/pause"
+	"linkedin-automation/personalize"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/selectors"
+)
+
+// Engagement actions
+const (
+	ActionLike    = "like"
+	ActionComment = "comment"
+)
+
+// EngageManager likes and comments on recent posts of target profiles and company pages
+type EngageManager struct {
+	page        *rod.Page
+	logger      *logrus.Logger
+	stealth     StealthManager
+	rateLimiter RateLimiter
+	store       Store
+	dryRun      bool
+	capturer    Capturer
+}
+
+// StealthManager interface for stealth operations
+type StealthManager interface {
+	HumanLikeMouseMove(page *rod.Page, fromX, fromY, toX, toY float64) error
+	RandomDelay() time.Duration
+	HumanLikeType(page *rod.Page, text string) error
+	HumanLikeScroll(page *rod.Page, scrollAmount int) error
+	AddIdleMovement(page *rod.Page) error
+}
+
+// RateLimiter gates actions against configured quotas
+type RateLimiter interface {
+	WaitForPermission(ctx context.Context, action ratelimit.ActionType) error
+}
+
+// Store reports earlier engagements so posts are not liked or commented on twice
+type Store interface {
+	HasEngaged(postURN, action string) (bool, error)
+}
+
+// Capturer saves the page for debugging when an action fails
+type Capturer interface {
+	Failure(page *rod.Page, action string, err error)
+}
+
+// Options controls what is done with each target's posts
+type Options struct {
+	Posts   int    // Number of recent posts per target
+	Like    bool   // Like each post
+	Comment string // Comment template; empty means no comments
+}
+
+// Post is a post found in a target's activity feed
+type Post struct {
+	URN        string
+	AuthorName string
+	Text       string
+	element    *rod.Element
+}
+
+// EngagementResult represents the outcome of one action on one post
+type EngagementResult struct {
+	Success      bool
+	TargetURL    string
+	PostURN      string
+	Action       string // like, comment
+	Content      string // The comment posted, after personalization
+	ErrorMessage string
+	Skipped      bool // Already done in an earlier run, or already liked on LinkedIn
+	DryRun       bool // The action was ready but not performed, because this is a dry run
+	CreatedAt    time.Time
+}
+
+// BatchResult represents the outcome of engaging with a list of targets
+type BatchResult struct {
+	Results        []*EngagementResult
+	StoppedAtLimit bool // The batch ended early because a quota was exhausted
+	StopReason     string
+	StopErr        error // The limit error that ended the batch
+}
+
+// CommentTemplate represents a post comment template
+type CommentTemplate struct {
+	ID        string
+	Name      string
+	Content   string
+	Variables []string
+}
+
+// NewEngageManager creates a new engagement manager
+func NewEngageManager(page *rod.Page, logger *logrus.Logger, stealth StealthManager) *EngageManager {
+	return &EngageManager{
+		page:    page,
+		logger:  logger,
+		stealth: stealth,
+	}
+}
+
+// SetRateLimiter enables quota enforcement for likes and comments
+func (e *EngageManager) SetRateLimiter(limiter RateLimiter) {
+	e.rateLimiter = limiter
+}
+
+// SetDryRun makes likes and comments find their buttons without clicking them
+func (e *EngageManager) SetDryRun(dryRun bool) {
+	e.dryRun = dryRun
+}
+
+// SetStore enables skipping posts engaged with in earlier runs
+func (e *EngageManager) SetStore(store Store) {
+	e.store = store
+}
+
+// SetCapturer saves a screenshot and HTML snapshot of the page whenever
+// loading posts or engaging with one fails
+func (e *EngageManager) SetCapturer(capturer Capturer) {
+	e.capturer = capturer
+}
+
+// Engage likes and/or comments on the recent posts of each target
+func (e *EngageManager) Engage(ctx context.Context, targets []string, opts Options) (*BatchResult, error) {
+	e.logger.WithFields(logrus.Fields{
+		"targets": len(targets),
+		"posts":   opts.Posts,
+		"like":    opts.Like,
+		"comment": opts.Comment != "",
+	}).Info("Starting post engagement")
+	defer e.withContext(ctx)()
+
+	batch := &BatchResult{}
+
+	for i, target := range targets {
+		// Stop between profiles when interrupted, keeping what was done so far
+		if err := ctx.Err(); err != nil {
+			return batch, err
+		}
+
+		posts, err := e.RecentPosts(ctx, target, opts.Posts)
+		if err != nil && ctx.Err() != nil {
+			return batch, ctx.Err()
+		}
+		if err != nil {
+			e.logger.WithError(err).WithField("target", target).Warn("Failed to load posts")
+			e.captureFailure("load posts", err)
+			batch.Results = append(batch.Results, &EngagementResult{
+				TargetURL:    target,
+				ErrorMessage: err.Error(),
+				CreatedAt:    time.Now(),
+			})
+			continue
+		}
+
+		for _, post := range posts {
+			if opts.Like {
+				result, err := e.act(ctx, target, post, ActionLike, "")
+				if result != nil {
+					batch.Results = append(batch.Results, result)
+				}
+				if stop := e.stopBatch(batch, err); stop != nil {
+					return batch, stop
+				}
+				if batch.StoppedAtLimit {
+					return batch, nil
+				}
+			}
+
+			if opts.Comment != "" {
+				result, err := e.act(ctx, target, post, ActionComment, opts.Comment)
+				if result != nil {
+					batch.Results = append(batch.Results, result)
+				}
+				if stop := e.stopBatch(batch, err); stop != nil {
+					return batch, stop
+				}
+				if batch.StoppedAtLimit {
+					return batch, nil
+				}
+			}
+		}
+
+		if i < len(targets)-1 {
+			pause.Sleep(ctx, e.stealth.RandomDelay())
+		}
+	}
+
+	e.logger.WithField("actions", len(batch.Results)).Info("Post engagement completed")
+	return batch, nil
+}
+
+// RecentPosts opens a target's activity feed and returns up to limit posts
+func (e *EngageManager) RecentPosts(ctx context.Context, target string, limit int) ([]*Post, error) {
+	defer e.withContext(ctx)()
+
+	feedURL, err := activityURL(target)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.page.Navigate(feedURL); err != nil {
+		return nil, fmt.Errorf("failed to navigate to activity: %w", err)
+	}
+	if err := e.page.WaitLoad(); err != nil {
+		return nil, fmt.Errorf("failed to wait for activity load: %w", err)
+	}
+
+	var posts []*Post
+	seen := make(map[string]bool)
+	for attempt := 0; attempt < 10 && len(posts) < limit; attempt++ {
+		for _, element := range selectors.FindAll(e.page, selectors.Post) {
+			urn, err := element.Attribute("data-urn")
+			if err != nil || urn == nil || seen[*urn] {
+				continue
+			}
+			seen[*urn] = true
+			posts = append(posts, &Post{
+				URN:        *urn,
+				AuthorName: selectors.TextIn(element, selectors.PostAuthor),
+				Text:       selectors.TextIn(element, selectors.PostText),
+				element:    element,
+			})
+			if len(posts) == limit {
+				break
+			}
+		}
+
+		if len(posts) < limit {
+			// Load more of the feed
+			if err := e.stealth.HumanLikeScroll(e.page, 800); err != nil {
+				e.logger.WithError(err).Debug("Failed to scroll activity feed")
+			}
+			select {
+			case <-ctx.Done():
+				return posts, ctx.Err()
+			case <-time.After(time.Second):
+			}
+		}
+	}
+
+	e.logger.WithFields(logrus.Fields{
+		"target": target,
+		"posts":  len(posts),
+	}).Debug("Found recent posts")
+	return posts, nil
+}
+
+// act performs one action on a post; a nil result means the batch stopped before acting
+func (e *EngageManager) act(ctx context.Context, target string, post *Post, action, comment string) (*EngagementResult, error) {
+	result := &EngagementResult{
+		TargetURL: target,
+		PostURN:   post.URN,
+		Action:    action,
+		CreatedAt: time.Now(),
+	}
+
+	if e.hasEngaged(post.URN, action) {
+		result.Skipped = true
+		return result, nil
+	}
+
+	if e.rateLimiter != nil {
+		if err := e.rateLimiter.WaitForPermission(ctx, ratelimit.ActionType(action)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := post.element.ScrollIntoView(); err != nil {
+		e.logger.WithError(err).Debug("Failed to scroll post into view")
+	}
+	if err := pause.Sleep(ctx, e.stealth.RandomDelay()); err != nil {
+		return nil, err
+	}
+
+	var err error
+	switch action {
+	case ActionLike:
+		var alreadyLiked bool
+		alreadyLiked, err = e.likePost(post)
+		result.Skipped = alreadyLiked
+	case ActionComment:
+		result.Content = personalize.Fill(comment, commentVariables(post))
+		err = e.commentOnPost(post, result.Content)
+	}
+
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		e.logger.WithError(err).WithFields(logrus.Fields{
+			"post":   post.URN,
+			"action": action,
+		}).Warn("Engagement failed")
+		e.captureFailure(action, err)
+		return result, nil
+	}
+
+	result.Success = !result.Skipped
+	result.DryRun = e.dryRun && result.Success
+	e.logger.WithFields(logrus.Fields{
+		"post":    post.URN,
+		"action":  action,
+		"skipped": result.Skipped,
+	}).Info("Engaged with post")
+	return result, nil
+}
+
+// withContext binds the page to ctx until the returned function is called,
+// so navigation, element lookups and pauses stop once ctx is cancelled
+func (e *EngageManager) withContext(ctx context.Context) func() {
+	page := e.page
+	e.page = page.Context(ctx)
+	return func() { e.page = page }
+}
+
+// captureFailure saves the page when an action failed with err
+func (e *EngageManager) captureFailure(action string, err error) {
+	if e.capturer != nil {
+		e.capturer.Failure(e.page, action, err)
+	}
+}
+
+// stopBatch records a rate limit stop on the batch, returning any other error
+func (e *EngageManager) stopBatch(batch *BatchResult, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, ratelimit.ErrLimitReached) {
+		e.logger.WithError(err).Warn("Stopping engagement at rate limit")
+		batch.StoppedAtLimit = true
+		batch.StopReason = err.Error()
+		batch.StopErr = err
+		return nil
+	}
+	return err
+}
+
+func (e *EngageManager) likePost(post *Post) (bool, error) {
+	button := selectors.FindIn(post.element, selectors.PostLikeButton)
+	if button == nil {
+		return false, fmt.Errorf("like button not found")
+	}
+
+	if pressed, err := button.Attribute("aria-pressed"); err == nil && pressed != nil && *pressed == "true" {
+		return true, nil
+	}
+	if e.dryRun {
+		return false, nil
+	}
+
+	return false, e.click(button)
+}
+
+func (e *EngageManager) commentOnPost(post *Post, content string) error {
+	button := selectors.FindIn(post.element, selectors.PostCommentButton)
+	if button == nil {
+		return fmt.Errorf("comment button not found")
+	}
+	if e.dryRun {
+		return nil
+	}
+	if err := e.click(button); err != nil {
+		return err
+	}
+
+	var editor *rod.Element
+	for i := 0; i < 10 && editor == nil; i++ {
+		if err := pause.Page(e.page, 500*time.Millisecond); err != nil {
+			return err
+		}
+		editor = selectors.FindIn(post.element, selectors.PostCommentEditor)
+	}
+	if editor == nil {
+		return fmt.Errorf("comment box not found")
+	}
+
+	if err := editor.Click("left", 1); err != nil {
+		return fmt.Errorf("failed to focus comment box: %w", err)
+	}
+	if err := e.stealth.HumanLikeType(e.page, content); err != nil {
+		return fmt.Errorf("failed to type comment: %w", err)
+	}
+	if err := pause.Page(e.page, e.stealth.RandomDelay()); err != nil {
+		return err
+	}
+
+	submit := selectors.FindIn(post.element, selectors.PostCommentSubmit)
+	if submit == nil {
+		return fmt.Errorf("comment submit button not found")
+	}
+	return e.click(submit)
+}
+
+func (e *EngageManager) click(button *rod.Element) error {
+	shape, err := button.Shape()
+	if err != nil {
+		return fmt.Errorf("failed to get button position: %w", err)
+	}
+	box := shape.Box()
+
+	viewport, err := e.page.Eval("({width: window.innerWidth, height: window.innerHeight})")
+	if err != nil {
+		return fmt.Errorf("failed to get viewport: %w", err)
+	}
+	fromX := viewport.Value.Get("width").Num() / 2
+	fromY := viewport.Value.Get("height").Num() / 2
+
+	if err := e.stealth.HumanLikeMouseMove(e.page, fromX, fromY, box.X+box.Width/2, box.Y+box.Height/2); err != nil {
+		e.logger.WithError(err).Warn("Failed to perform human-like mouse movement")
+	}
+
+	if err := button.Click("left", 1); err != nil {
+		return fmt.Errorf("failed to click button: %w", err)
+	}
+	return nil
+}
+
+func (e *EngageManager) hasEngaged(postURN, action string) bool {
+	if e.store == nil {
+		return false
+	}
+
+	engaged, err := e.store.HasEngaged(postURN, action)
+	if err != nil {
+		e.logger.WithError(err).Warn("Failed to check earlier engagements")
+		return false
+	}
+	return engaged
+}
+
+// activityURL returns the feed of recent posts for a profile or company page
+func activityURL(target string) (string, error) {
+	if slug := profileurl.Slug(target); slug != "" {
+		return "https://www.linkedin.com/in/" + slug + "/recent-activity/all/", nil
+	}
+
+	if i := strings.Index(target, "/company/"); i >= 0 {
+		rest := strings.Trim(target[i+len("/company/"):], "/")
+		if name := strings.SplitN(rest, "/", 2)[0]; name != "" {
+			return "https://www.linkedin.com/company/" + name + "/posts/", nil
+		}
+	}
+
+	return "", fmt.Errorf("not a profile or company page URL: %s", target)
+}
+
+// commentVariables exposes the post author and text to comment templates
+func commentVariables(post *Post) map[string]string {
+	vars := map[string]string{
+		"name":   post.AuthorName,
+		"author": post.AuthorName,
+	}
+	if fields := strings.Fields(post.AuthorName); len(fields) > 0 {
+		vars["first_name"] = fields[0]
+	}
+	return vars
+}
+
+// GetDefaultCommentTemplates returns default post comment templates
+func GetDefaultCommentTemplates() []CommentTemplate {
+	return []CommentTemplate{
+		{
+			ID:        "comment_insightful",
+			Name:      "Insightful",
+			Content:   "{Great|Really good|Insightful} point, {{first_name}}. {Thanks for sharing!|Appreciate you sharing this.}",
+			Variables: []string{"first_name"},
+		},
+		{
+			ID:        "comment_agree",
+			Name:      "Agree",
+			Content:   "{Completely agree|Couldn't agree more}, {{first_name}} - {this matches what I've seen too.|well put.}",
+			Variables: []string{"first_name"},
+		},
+	}
+}
@@ -0,0 +1,68 @@
+package enrich
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Apollo finds addresses through the Apollo.io People Enrichment API
+type Apollo struct {
+	apiKey  string
+	baseURL string
+}
+
+// NewApollo creates an Apollo.io provider
+func NewApollo(apiKey, baseURL string) *Apollo {
+	return &Apollo{
+		apiKey:  apiKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Name returns the provider name
+func (a *Apollo) Name() string {
+	return "apollo"
+}
+
+// FindEmail matches the person against Apollo.io's people database, which
+// also knows them by their LinkedIn profile URL
+func (a *Apollo) FindEmail(ctx context.Context, person *Person) (*Match, error) {
+	request := map[string]interface{}{
+		"first_name":             person.FirstName,
+		"last_name":              person.LastName,
+		"organization_name":      person.Company,
+		"linkedin_url":           person.ProfileURL,
+		"reveal_personal_emails": false,
+	}
+
+	var response struct {
+		Person *struct {
+			Email       string `json:"email"`
+			EmailStatus string `json:"email_status"`
+		} `json:"person"`
+	}
+	err := doJSON(ctx, http.MethodPost, a.baseURL+"/api/v1/people/match",
+		map[string]string{"X-Api-Key": a.apiKey}, request, &response)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("apollo: failed to match person: %w", err)
+	}
+
+	// Addresses the plan cannot reveal come back as a placeholder
+	if response.Person == nil || response.Person.Email == "" || strings.Contains(response.Person.Email, "not_unlocked") {
+		return nil, nil
+	}
+
+	match := &Match{Email: response.Person.Email}
+	switch response.Person.EmailStatus {
+	case "verified":
+		match.Score = 100
+	case "guessed", "likely to engage":
+		match.Score = 50
+	}
+	return match, nil
+}
@@ -0,0 +1,119 @@
+package enrich
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// dropcontactPoll is how often a submitted Dropcontact request is checked for
+// its result, and dropcontactWait how long it is waited for
+const (
+	dropcontactPoll = 10 * time.Second
+	dropcontactWait = 3 * time.Minute
+)
+
+// Dropcontact finds addresses through the Dropcontact batch API, which works
+// asynchronously: a request is submitted and its result fetched once ready
+type Dropcontact struct {
+	apiKey  string
+	baseURL string
+}
+
+// NewDropcontact creates a Dropcontact provider
+func NewDropcontact(apiKey, baseURL string) *Dropcontact {
+	return &Dropcontact{
+		apiKey:  apiKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Name returns the provider name
+func (d *Dropcontact) Name() string {
+	return "dropcontact"
+}
+
+// FindEmail submits the person and waits for Dropcontact to enrich them
+func (d *Dropcontact) FindEmail(ctx context.Context, person *Person) (*Match, error) {
+	headers := map[string]string{"X-Access-Token": d.apiKey}
+
+	var submitted struct {
+		RequestID string `json:"request_id"`
+		Success   bool   `json:"success"`
+		Reason    string `json:"reason"`
+	}
+	err := doJSON(ctx, http.MethodPost, d.baseURL+"/batch", headers, map[string]interface{}{
+		"data": []map[string]string{{
+			"first_name": person.FirstName,
+			"last_name":  person.LastName,
+			"company":    person.Company,
+			"linkedin":   person.ProfileURL,
+		}},
+		"siren": false,
+	}, &submitted)
+	if err != nil {
+		return nil, fmt.Errorf("dropcontact: failed to submit request: %w", err)
+	}
+	if !submitted.Success || submitted.RequestID == "" {
+		return nil, fmt.Errorf("dropcontact: request rejected: %s", submitted.Reason)
+	}
+
+	deadline := time.Now().Add(dropcontactWait)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(dropcontactPoll):
+		}
+
+		var result struct {
+			Success bool   `json:"success"`
+			Error   bool   `json:"error"`
+			Reason  string `json:"reason"`
+			Data    []struct {
+				Email []dropcontactEmail `json:"email"`
+			} `json:"data"`
+		}
+		if err := doJSON(ctx, http.MethodGet, d.baseURL+"/batch/"+submitted.RequestID, headers, nil, &result); err != nil {
+			return nil, fmt.Errorf("dropcontact: failed to get result: %w", err)
+		}
+		if result.Error {
+			return nil, fmt.Errorf("dropcontact: %s", result.Reason)
+		}
+		if result.Success {
+			var emails []dropcontactEmail
+			for _, contact := range result.Data {
+				emails = append(emails, contact.Email...)
+			}
+			return dropcontactMatch(emails), nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("dropcontact: no result after %s", dropcontactWait)
+		}
+	}
+}
+
+// dropcontactEmail is an address as Dropcontact qualifies it, e.g.
+// nominative@pro for a verified firstname.lastname@company address
+type dropcontactEmail struct {
+	Email         string `json:"email"`
+	Qualification string `json:"qualification"`
+}
+
+// dropcontactMatch picks a professional address, preferring a nominative one
+func dropcontactMatch(emails []dropcontactEmail) *Match {
+	var match *Match
+	for _, email := range emails {
+		switch email.Qualification {
+		case "nominative@pro":
+			return &Match{Email: email.Email, Score: 100}
+		case "catch_all@pro":
+			if match == nil {
+				match = &Match{Email: email.Email, Score: 50}
+			}
+		}
+	}
+	return match
+}
@@ -0,0 +1,193 @@
+// Package enrich looks up work email addresses for stored profiles through
+// email finder APIs, so prospects can also be reached outside LinkedIn.
+package enrich
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/storage"
+)
+
+// Person is who a provider is asked to find an address for
+type Person struct {
+	ProfileURL string
+	FirstName  string
+	LastName   string
+	Company    string
+}
+
+// Match is an address a provider found
+type Match struct {
+	Email string
+	Score int // The provider's confidence from 0 to 100, or 0 if it gives none
+}
+
+// Provider finds work email addresses from a name and company
+type Provider interface {
+	Name() string
+	FindEmail(ctx context.Context, person *Person) (*Match, error) // Returns nil when the provider knows no address
+}
+
+// Store supplies the scraped profiles and keeps the addresses found
+type Store interface {
+	GetProfile(url string) (*storage.Profile, error)
+	SaveProfileEmail(profileURL, email, provider string) error
+}
+
+// Options control an enrichment run
+type Options struct {
+	MinScore int  // Matches the provider is less confident about are ignored
+	Recheck  bool // Look up profiles again that earlier runs found no address for
+}
+
+// Lookup is the outcome of looking up one profile
+type Lookup struct {
+	ProfileURL string
+	Email      string
+	Provider   string
+	Score      int
+	Skipped    string // Why the profile was not looked up, if it was not
+	Error      error  // Set when every provider failed, rather than finding nothing
+}
+
+// Result summarizes an enrichment run
+type Result struct {
+	Lookups  []*Lookup
+	Found    int
+	NotFound int
+	Skipped  int
+	Failed   int
+}
+
+// Enricher looks up addresses with each provider in turn until one finds one
+type Enricher struct {
+	store     Store
+	providers []Provider
+	logger    *logrus.Logger
+}
+
+// NewEnricher creates an enricher trying providers in the given order
+func NewEnricher(store Store, providers []Provider, logger *logrus.Logger) *Enricher {
+	return &Enricher{
+		store:     store,
+		providers: providers,
+		logger:    logger,
+	}
+}
+
+// Enrich looks up an address for each profile that does not have one yet.
+// Profiles need a stored name and company; a profile every provider failed
+// on is reported and tried again on the next run.
+func (e *Enricher) Enrich(ctx context.Context, profileURLs []string, opts Options) (*Result, error) {
+	e.logger.WithFields(logrus.Fields{
+		"profiles":  len(profileURLs),
+		"providers": len(e.providers),
+	}).Info("Starting email enrichment")
+
+	result := &Result{}
+	for _, profileURL := range profileURLs {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		lookup, err := e.lookup(ctx, profileURL, opts)
+		if err != nil {
+			return result, err
+		}
+		result.Lookups = append(result.Lookups, lookup)
+
+		switch {
+		case lookup.Skipped != "":
+			e.logger.WithField("profile", profileURL).WithField("reason", lookup.Skipped).Debug("Profile not looked up")
+			result.Skipped++
+		case lookup.Error != nil:
+			result.Failed++
+		case lookup.Email != "":
+			result.Found++
+		default:
+			result.NotFound++
+		}
+	}
+
+	e.logger.WithFields(logrus.Fields{
+		"found":     result.Found,
+		"not_found": result.NotFound,
+		"skipped":   result.Skipped,
+		"failed":    result.Failed,
+	}).Info("Email enrichment completed")
+	return result, nil
+}
+
+// lookup tries the providers on one profile, returning an error only when
+// the store fails
+func (e *Enricher) lookup(ctx context.Context, profileURL string, opts Options) (*Lookup, error) {
+	lookup := &Lookup{ProfileURL: profileURL}
+
+	profile, err := e.store.GetProfile(profileURL)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case profile == nil:
+		lookup.Skipped = "profile not scraped"
+		return lookup, nil
+	case profile.Email != "":
+		lookup.Email, lookup.Provider = profile.Email, profile.EmailProvider
+		lookup.Skipped = "already enriched"
+		return lookup, nil
+	case profile.EmailCheckedAt != nil && !opts.Recheck:
+		lookup.Skipped = "no address found before"
+		return lookup, nil
+	}
+
+	person := personFromProfile(profile)
+	if person.FirstName == "" || person.LastName == "" || person.Company == "" {
+		lookup.Skipped = "name or company missing"
+		return lookup, nil
+	}
+
+	var failures []string
+	for _, provider := range e.providers {
+		match, err := provider.FindEmail(ctx, person)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			e.logger.WithError(err).WithFields(logrus.Fields{
+				"provider": provider.Name(),
+				"profile":  profileURL,
+			}).Warn("Email lookup failed")
+			failures = append(failures, err.Error())
+			continue
+		}
+		if match == nil || match.Email == "" || (match.Score > 0 && match.Score < opts.MinScore) {
+			continue
+		}
+
+		lookup.Email, lookup.Provider, lookup.Score = match.Email, provider.Name(), match.Score
+		return lookup, e.store.SaveProfileEmail(profileURL, match.Email, provider.Name())
+	}
+
+	// Only a lookup that completed with at least one provider counts as checked
+	if len(failures) == len(e.providers) {
+		lookup.Error = fmt.Errorf("every provider failed: %s", strings.Join(failures, "; "))
+		return lookup, nil
+	}
+	return lookup, e.store.SaveProfileEmail(profileURL, "", "")
+}
+
+func personFromProfile(profile *storage.Profile) *Person {
+	parts := strings.Fields(profile.Name)
+	person := &Person{ProfileURL: profile.URL, Company: strings.TrimSpace(profile.Company)}
+	if len(parts) > 0 {
+		person.FirstName = parts[0]
+	}
+	if len(parts) > 1 {
+		person.LastName = strings.Join(parts[1:], " ")
+	}
+	return person
+}
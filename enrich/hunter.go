@@ -0,0 +1,57 @@
+package enrich
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Hunter finds addresses through the Hunter.io Email Finder API
+type Hunter struct {
+	apiKey  string
+	baseURL string
+}
+
+// NewHunter creates a Hunter.io provider
+func NewHunter(apiKey, baseURL string) *Hunter {
+	return &Hunter{
+		apiKey:  apiKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Name returns the provider name
+func (h *Hunter) Name() string {
+	return "hunter"
+}
+
+// FindEmail looks up the address Hunter.io guesses or knows for the person
+func (h *Hunter) FindEmail(ctx context.Context, person *Person) (*Match, error) {
+	query := url.Values{
+		"first_name": {person.FirstName},
+		"last_name":  {person.LastName},
+		"company":    {person.Company},
+		"api_key":    {h.apiKey},
+	}
+
+	var response struct {
+		Data struct {
+			Email string `json:"email"`
+			Score int    `json:"score"`
+		} `json:"data"`
+	}
+	err := doJSON(ctx, http.MethodGet, h.baseURL+"/v2/email-finder?"+query.Encode(), nil, nil, &response)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("hunter: failed to find email: %w", err)
+	}
+
+	if response.Data.Email == "" {
+		return nil, nil
+	}
+	return &Match{Email: response.Data.Email, Score: response.Data.Score}, nil
+}
@@ -0,0 +1,93 @@
+// Package errs defines the failures that automation flows report, so callers
+// can tell transient problems worth retrying from ones that will not go away.
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"linkedin-automation/ratelimit"
+)
+
+var (
+	// ErrNotConnected means the recipient can only be messaged once connected
+	ErrNotConnected = errors.New("recipient is not a connection")
+	// ErrProfileUnavailable means the profile does not exist or is hidden from the account
+	ErrProfileUnavailable = errors.New("profile unavailable")
+	// ErrDialogNotFound means an expected dialog or form did not appear, usually
+	// because the page was slow to render
+	ErrDialogNotFound = errors.New("dialog not found")
+	// ErrNotClickable means an element is hidden, disabled or covered by an
+	// overlay such as a toast or pop-up, so clicking it would miss
+	ErrNotClickable = errors.New("element not clickable")
+	// ErrEmailRequired means LinkedIn only accepts an invitation to this member
+	// together with their email address
+	ErrEmailRequired = errors.New("connection requires the member's email address")
+	// ErrNotOpenProfile means messaging the member outside the network would
+	// spend an InMail credit, because their profile is not an open profile
+	ErrNotOpenProfile = errors.New("not an open profile")
+	// ErrSessionExpired means LinkedIn sent the account back to the login page
+	// or rejected its session cookies
+	ErrSessionExpired = errors.New("linkedin session expired")
+	// ErrLoginFailed means the account could not log in, e.g. because of wrong
+	// credentials or a CAPTCHA or verification checkpoint left unsolved
+	ErrLoginFailed = errors.New("authentication failed")
+	// ErrAborted means the user stopped the run, for example while reviewing messages
+	ErrAborted = errors.New("aborted by user")
+	// ErrRateLimited means LinkedIn itself throttled the account; it matches
+	// ratelimit.ErrLimitReached so batches stop as they do at a local quota
+	ErrRateLimited = fmt.Errorf("%w: throttled by linkedin", ratelimit.ErrLimitReached)
+	// ErrNoInMailCredits means the account has used up its InMail credits
+	ErrNoInMailCredits = fmt.Errorf("%w: no InMail credits left", ratelimit.ErrLimitReached)
+	// ErrWeeklyInviteLimit means LinkedIn's weekly invitation cap was reached
+	ErrWeeklyInviteLimit = fmt.Errorf("%w: weekly invitation limit reached", ratelimit.ErrLimitReached)
+	// ErrRestricted means LinkedIn restricted or temporarily blocked the
+	// account, e.g. after flagging unusual activity. Nothing may run until the
+	// cool-down ends; it matches ratelimit.ErrLimitReached so every batch stops.
+	ErrRestricted = fmt.Errorf("%w: linkedin restricted the account", ratelimit.ErrLimitReached)
+)
+
+// Retryable reports whether trying the same action again may succeed. Limits,
+// cancellation and failures tied to the profile or session are final; anything
+// else, such as a slow page or a missing dialog, is worth another attempt.
+func Retryable(err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	case errors.Is(err, ratelimit.ErrLimitReached):
+		return false
+	case errors.Is(err, ErrSessionExpired), errors.Is(err, ErrLoginFailed), errors.Is(err, ErrNotConnected), errors.Is(err, ErrProfileUnavailable),
+		errors.Is(err, ErrEmailRequired), errors.Is(err, ErrNotOpenProfile), errors.Is(err, ErrAborted):
+		return false
+	}
+	return true
+}
+
+// CheckPageURL classifies the URL LinkedIn landed on after a navigation:
+// restriction pages mean the account was restricted, login and other
+// checkpoint pages that the session expired, and the unavailable profile
+// page that the profile cannot be viewed
+func CheckPageURL(pageURL string) error {
+	switch {
+	case IsRestrictedURL(pageURL):
+		return fmt.Errorf("%w: redirected to %s", ErrRestricted, pageURL)
+	case strings.Contains(pageURL, "linkedin.com/login"),
+		strings.Contains(pageURL, "linkedin.com/uas/login"),
+		strings.Contains(pageURL, "linkedin.com/authwall"),
+		strings.Contains(pageURL, "linkedin.com/checkpoint"):
+		return fmt.Errorf("%w: redirected to %s", ErrSessionExpired, pageURL)
+	case strings.Contains(pageURL, "linkedin.com/in/unavailable"):
+		return ErrProfileUnavailable
+	}
+	return nil
+}
+
+// IsRestrictedURL reports whether pageURL is one of the pages LinkedIn shows
+// a restricted or temporarily blocked account instead of the one requested
+func IsRestrictedURL(pageURL string) bool {
+	return strings.Contains(pageURL, "linkedin.com/checkpoint") && strings.Contains(pageURL, "restrict")
+}
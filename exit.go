@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"linkedin-automation/config"
+	"linkedin-automation/errs"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/search"
+)
+
+// Exit codes, so scripts and cron jobs can tell how a command ended
+const (
+	exitFailed      = 1 // Any error not covered below
+	exitPartial     = 2 // The batch ran to the end, but some profiles failed
+	exitRateLimited = 3 // A quota, LinkedIn's throttling or the session budget stopped the run
+	exitAuth        = 4 // Logging in failed, or LinkedIn ended the session or asks for a checkpoint
+	exitConfig      = 5 // The configuration cannot be loaded or is invalid
+	exitRestricted  = 6 // LinkedIn restricted the account, which is cooling down
+)
+
+// exitError ends the command with a specific exit code
+type exitError struct {
+	code     int
+	err      error
+	reported bool // The command already printed the outcome, e.g. in its summary
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+
+func (e *exitError) Unwrap() error { return e.err }
+
+// exitCode returns the exit code for the error a command returned
+func exitCode(err error) int {
+	var exit *exitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exit):
+		return exit.code
+	case errors.Is(err, config.ErrInvalid):
+		return exitConfig
+	case errors.Is(err, errs.ErrRestricted):
+		return exitRestricted
+	case errors.Is(err, errs.ErrLoginFailed), errors.Is(err, errs.ErrSessionExpired):
+		return exitAuth
+	case errors.Is(err, ratelimit.ErrLimitReached):
+		return exitRateLimited
+	}
+	return exitFailed
+}
+
+// batchExit turns the summary of a finished batch into the command's
+// result: an error with exitRateLimited when a limit stopped it early, with
+// exitPartial when some of the attempted profiles failed, and nil otherwise.
+// what names the items, e.g. "connection requests", and stopErr is the error
+// that ended the batch early, if one did.
+func batchExit(what string, failed, attempted int, stopErr error) error {
+	switch {
+	case errors.Is(stopErr, errs.ErrRestricted):
+		return &exitError{code: exitRestricted, err: fmt.Errorf("stopped: %w", stopErr), reported: true}
+	case stopErr != nil:
+		return &exitError{code: exitRateLimited, err: fmt.Errorf("stopped at limit: %w", stopErr), reported: true}
+	case failed > 0:
+		return &exitError{code: exitPartial, err: fmt.Errorf("%d of %d %s failed", failed, attempted, what), reported: true}
+	}
+	return nil
+}
+
+// searchExit reports a search that the search quota cut short with
+// exitRateLimited, after its partial results were printed
+func searchExit(session *search.SearchSession) error {
+	if session.StoppedAtLimit {
+		return &exitError{code: exitRateLimited, err: errors.New("stopped at search rate limit; results may be incomplete"), reported: true}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"linkedin-automation/storage"
+)
+
+// Supported output formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatXLSX = "xlsx"
+)
+
+// Exportable entities
+const (
+	EntityProfiles    = "profiles"
+	EntityConnections = "connections"
+	EntityMessages    = "messages"
+	EntityNetwork     = "network" // 1st-degree connections, as exported by 'connections export'
+)
+
+// Table is one exported entity, flattened into rows for CSV and XLSX and kept
+// as the original records for JSON
+type Table struct {
+	Name    string
+	Columns []string
+	Rows    [][]string
+	Records interface{}
+}
+
+// ProfilesTable flattens profiles into a table
+func ProfilesTable(profiles []*storage.Profile) *Table {
+	table := &Table{
+		Name:    EntityProfiles,
+		Columns: []string{"url", "name", "title", "headline", "company", "location", "email", "search_query", "source", "created_at", "updated_at"},
+		Records: profiles,
+	}
+	for _, p := range profiles {
+		table.Rows = append(table.Rows, []string{
+			p.URL, p.Name, p.Title, p.Headline, p.Company, p.Location, p.Email, p.SearchQuery, p.Source,
+			formatTime(p.CreatedAt), formatTime(p.UpdatedAt),
+		})
+	}
+	return table
+}
+
+// ConnectionsTable flattens connection requests into a table
+func ConnectionsTable(requests []*storage.ConnectionRequest) *Table {
+	table := &Table{
+		Name:    EntityConnections,
+		Columns: []string{"profile_url", "status", "message", "campaign", "variant", "sent_at", "accepted_at"},
+		Records: requests,
+	}
+	for _, r := range requests {
+		acceptedAt := ""
+		if r.AcceptedAt != nil {
+			acceptedAt = formatTime(*r.AcceptedAt)
+		}
+		table.Rows = append(table.Rows, []string{
+			r.ProfileURL, r.Status, r.Message, r.Campaign, r.Variant, formatTime(r.SentAt), acceptedAt,
+		})
+	}
+	return table
+}
+
+// NetworkTable flattens 1st-degree connections into a table
+func NetworkTable(connections []*storage.NetworkConnection) *Table {
+	table := &Table{
+		Name:    EntityNetwork,
+		Columns: []string{"profile_url", "name", "headline", "connected_at"},
+		Records: connections,
+	}
+	for _, c := range connections {
+		connectedAt := ""
+		if c.ConnectedAt != nil {
+			connectedAt = formatTime(*c.ConnectedAt)
+		}
+		table.Rows = append(table.Rows, []string{c.ProfileURL, c.Name, c.Headline, connectedAt})
+	}
+	return table
+}
+
+// MessagesTable flattens messages into a table
+func MessagesTable(messages []*storage.Message) *Table {
+	table := &Table{
+		Name:    EntityMessages,
+		Columns: []string{"recipient_url", "type", "status", "content", "sent_at", "connection_id"},
+		Records: messages,
+	}
+	for _, m := range messages {
+		connectionID := ""
+		if m.ConnectionID != nil {
+			connectionID = strconv.Itoa(*m.ConnectionID)
+		}
+		table.Rows = append(table.Rows, []string{
+			m.RecipientURL, m.Type, m.Status, m.Content, formatTime(m.SentAt), connectionID,
+		})
+	}
+	return table
+}
+
+// WriteCSV writes a single table as CSV with a header row
+func WriteCSV(w io.Writer, table *Table) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(table.Columns); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	if err := writer.WriteAll(table.Rows); err != nil {
+		return fmt.Errorf("failed to write CSV rows: %w", err)
+	}
+	return nil
+}
+
+// WriteJSON writes the tables as a JSON object keyed by entity name
+func WriteJSON(w io.Writer, tables []*Table) error {
+	data := make(map[string]interface{}, len(tables))
+	for _, table := range tables {
+		data[table.Name] = table.Records
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
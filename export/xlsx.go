@@ -0,0 +1,124 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteXLSX writes the tables as an Excel workbook with one sheet per table.
+// Only the parts Excel requires are generated, with every cell stored as an
+// inline string so values are imported exactly as exported.
+func WriteXLSX(w io.Writer, tables []*Table) error {
+	archive := zip.NewWriter(w)
+
+	var sheets, sheetRels, sheetTypes bytes.Buffer
+	for i, table := range tables {
+		id := strconv.Itoa(i + 1)
+		fmt.Fprintf(&sheets, `<sheet name="%s" sheetId="%s" r:id="rId%s"/>`, escapeXML(table.Name), id, id)
+		fmt.Fprintf(&sheetRels, `<Relationship Id="rId%s" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%s.xml"/>`, id, id)
+		fmt.Fprintf(&sheetTypes, `<Override PartName="/xl/worksheets/sheet%s.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, id)
+	}
+	stylesID := strconv.Itoa(len(tables) + 1)
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+			sheetTypes.String() + `</Types>`},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` +
+			sheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			sheetRels.String() +
+			`<Relationship Id="rId` + stylesID + `" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+			`</Relationships>`},
+		// Style 1 bolds the header row
+		{"xl/styles.xml", xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+			`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+			`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+			`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+			`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+			`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+			`</styleSheet>`},
+	}
+
+	for _, part := range parts {
+		if err := writeZipPart(archive, part.name, []byte(part.content)); err != nil {
+			return err
+		}
+	}
+
+	for i, table := range tables {
+		if err := writeZipPart(archive, fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), worksheetXML(table)); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to finish workbook: %w", err)
+	}
+	return nil
+}
+
+func worksheetXML(table *Table) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	writeRow(&buf, 1, table.Columns, ` s="1"`)
+	for i, row := range table.Rows {
+		writeRow(&buf, i+2, row, "")
+	}
+
+	buf.WriteString(`</sheetData></worksheet>`)
+	return buf.Bytes()
+}
+
+func writeRow(buf *bytes.Buffer, number int, values []string, style string) {
+	fmt.Fprintf(buf, `<row r="%d">`, number)
+	for col, value := range values {
+		fmt.Fprintf(buf, `<c r="%s%d" t="inlineStr"%s><is><t xml:space="preserve">%s</t></is></c>`,
+			columnName(col), number, style, escapeXML(value))
+	}
+	buf.WriteString(`</row>`)
+}
+
+// columnName converts a zero-based column index to its spreadsheet letters (0 -> A, 26 -> AA)
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+func escapeXML(value string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(value))
+	return buf.String()
+}
+
+func writeZipPart(archive *zip.Writer, name string, content []byte) error {
+	part, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to workbook: %w", name, err)
+	}
+	if _, err := part.Write(content); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+// Package geo infers prospects' timezones from the location on their profile,
+// such as "Berlin, Germany" or "Greater Boston Area", so actions can be timed
+// to the prospect's working hours rather than the operator's.
+package geo
+
+import (
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"linkedin-automation/storage"
+)
+
+// fillers are words LinkedIn adds around metro area names
+var fillers = map[string]bool{
+	"greater":      true,
+	"area":         true,
+	"metropolitan": true,
+	"metro":        true,
+	"metroplex":    true,
+	"region":       true,
+}
+
+var (
+	zonesMu sync.Mutex
+	zones   = make(map[string]*time.Location)
+)
+
+// Timezone returns the timezone of a profile location. Each comma-separated
+// part is tried as a city, then the parts from last to first as a state or
+// province, then as a country.
+func Timezone(location string) (*time.Location, bool) {
+	var parts [][]string
+	for _, part := range strings.Split(location, ",") {
+		if words := normalize(part); len(words) > 0 {
+			parts = append(parts, words)
+		}
+	}
+
+	for _, words := range parts {
+		// "Raleigh-Durham-Chapel Hill" still finds "raleigh durham"
+		for n := len(words); n > 0; n-- {
+			if name, ok := cities[strings.Join(words[:n], " ")]; ok {
+				return load(name)
+			}
+		}
+	}
+	for _, table := range []map[string]string{regions, countries} {
+		for i := len(parts) - 1; i >= 0; i-- {
+			if name, ok := table[strings.Join(parts[i], " ")]; ok {
+				return load(name)
+			}
+		}
+	}
+	return nil, false
+}
+
+// normalize lower-cases part and splits it into words, dropping punctuation and filler words
+func normalize(part string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(part), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !fillers[word] {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+func load(name string) (*time.Location, bool) {
+	zonesMu.Lock()
+	defer zonesMu.Unlock()
+
+	if loc, ok := zones[name]; ok {
+		return loc, loc != nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		// The system's timezone database lacks the zone
+		loc = nil
+	}
+	zones[name] = loc
+	return loc, loc != nil
+}
+
+// Hours are the working hours actions are timed to, on weekdays
+type Hours struct {
+	Start int // Hour the working day starts
+	End   int // Hour the working day ends
+}
+
+// Next returns now if it falls in working hours in loc, or else when they
+// next begin
+func (h Hours) Next(now time.Time, loc *time.Location) time.Time {
+	day := now.In(loc)
+	for i := 0; i < 8; i++ {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			start := time.Date(day.Year(), day.Month(), day.Day(), h.Start, 0, 0, 0, loc)
+			end := time.Date(day.Year(), day.Month(), day.Day(), h.End, 0, 0, 0, loc)
+			if now.Before(start) {
+				return start
+			}
+			if now.Before(end) {
+				return now
+			}
+		}
+		day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+	}
+	return now
+}
+
+// Profiles looks up the stored profile of a prospect
+type Profiles interface {
+	GetProfile(url string) (*storage.Profile, error)
+}
+
+// ProspectHours times actions to each prospect's working hours, in the
+// timezone of their profile location or fallback when it is unknown
+type ProspectHours struct {
+	profiles Profiles
+	hours    Hours
+	fallback *time.Location
+}
+
+// NewProspectHours creates working hours per prospect
+func NewProspectHours(profiles Profiles, hours Hours, fallback *time.Location) *ProspectHours {
+	return &ProspectHours{
+		profiles: profiles,
+		hours:    hours,
+		fallback: fallback,
+	}
+}
+
+// Zone returns the prospect's timezone and whether it comes from their
+// location rather than the fallback
+func (p *ProspectHours) Zone(profileURL string) (*time.Location, bool, error) {
+	profile, err := p.profiles.GetProfile(profileURL)
+	if err != nil {
+		return nil, false, err
+	}
+	if profile != nil {
+		if loc, ok := Timezone(profile.Location); ok {
+			return loc, true, nil
+		}
+	}
+	return p.fallback, false, nil
+}
+
+// Next returns now if it falls in the prospect's working hours, or else when
+// they next begin
+func (p *ProspectHours) Next(profileURL string, now time.Time) (time.Time, error) {
+	loc, _, err := p.Zone(profileURL)
+	if err != nil {
+		return now, err
+	}
+	return p.hours.Next(now, loc), nil
+}
+
+// OperatorHours times every action to the operator's own working hours, from
+// stealth.schedule.business_hours_only, whoever the action is for
+type OperatorHours struct {
+	hours Hours
+	loc   *time.Location
+}
+
+// NewOperatorHours creates the operator's working hours in loc
+func NewOperatorHours(hours Hours, loc *time.Location) *OperatorHours {
+	return &OperatorHours{hours: hours, loc: loc}
+}
+
+// Next returns now if it falls in the operator's working hours, or else when
+// they next begin
+func (o *OperatorHours) Next(now time.Time) time.Time {
+	return o.hours.Next(now, o.loc)
+}
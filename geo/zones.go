@@ -0,0 +1,195 @@
+package geo
+
+// cities maps metro areas to their timezone. They are checked before regions
+// and countries, so "Tbilisi, Georgia" is not read as the US state; a bare
+// "Washington" is the state, though.
+var cities = map[string]string{
+	// North America
+	"new york":              "America/New_York",
+	"new york city":         "America/New_York",
+	"boston":                "America/New_York",
+	"philadelphia":          "America/New_York",
+	"washington dc":         "America/New_York",
+	"washington d c":        "America/New_York",
+	"atlanta":               "America/New_York",
+	"miami":                 "America/New_York",
+	"miami fort lauderdale": "America/New_York",
+	"charlotte":             "America/New_York",
+	"raleigh durham":        "America/New_York",
+	"pittsburgh":            "America/New_York",
+	"detroit":               "America/Detroit",
+	"toronto":               "America/Toronto",
+	"montreal":              "America/Toronto",
+	"ottawa":                "America/Toronto",
+	"chicago":               "America/Chicago",
+	"dallas":                "America/Chicago",
+	"dallas fort worth":     "America/Chicago",
+	"houston":               "America/Chicago",
+	"austin":                "America/Chicago",
+	"minneapolis st paul":   "America/Chicago",
+	"nashville":             "America/Chicago",
+	"winnipeg":              "America/Winnipeg",
+	"mexico city":           "America/Mexico_City",
+	"denver":                "America/Denver",
+	"salt lake city":        "America/Denver",
+	"calgary":               "America/Edmonton",
+	"phoenix":               "America/Phoenix",
+	"los angeles":           "America/Los_Angeles",
+	"san francisco":         "America/Los_Angeles",
+	"san francisco bay":     "America/Los_Angeles",
+	"san jose":              "America/Los_Angeles",
+	"san diego":             "America/Los_Angeles",
+	"silicon valley":        "America/Los_Angeles",
+	"seattle":               "America/Los_Angeles",
+	"portland":              "America/Los_Angeles",
+	"vancouver":             "America/Vancouver",
+	// South America
+	"sao paulo":      "America/Sao_Paulo",
+	"são paulo":      "America/Sao_Paulo",
+	"rio de janeiro": "America/Sao_Paulo",
+	"buenos aires":   "America/Argentina/Buenos_Aires",
+	"bogota":         "America/Bogota",
+	"bogotá":         "America/Bogota",
+	"santiago":       "America/Santiago",
+	"lima":           "America/Lima",
+	// Europe
+	"london":     "Europe/London",
+	"manchester": "Europe/London",
+	"edinburgh":  "Europe/London",
+	"dublin":     "Europe/Dublin",
+	"lisbon":     "Europe/Lisbon",
+	"paris":      "Europe/Paris",
+	"berlin":     "Europe/Berlin",
+	"munich":     "Europe/Berlin",
+	"hamburg":    "Europe/Berlin",
+	"frankfurt":  "Europe/Berlin",
+	"amsterdam":  "Europe/Amsterdam",
+	"brussels":   "Europe/Brussels",
+	"zurich":     "Europe/Zurich",
+	"geneva":     "Europe/Zurich",
+	"vienna":     "Europe/Vienna",
+	"madrid":     "Europe/Madrid",
+	"barcelona":  "Europe/Madrid",
+	"milan":      "Europe/Rome",
+	"rome":       "Europe/Rome",
+	"stockholm":  "Europe/Stockholm",
+	"copenhagen": "Europe/Copenhagen",
+	"oslo":       "Europe/Oslo",
+	"helsinki":   "Europe/Helsinki",
+	"warsaw":     "Europe/Warsaw",
+	"prague":     "Europe/Prague",
+	"budapest":   "Europe/Budapest",
+	"bucharest":  "Europe/Bucharest",
+	"athens":     "Europe/Athens",
+	"kyiv":       "Europe/Kiev",
+	"istanbul":   "Europe/Istanbul",
+	"moscow":     "Europe/Moscow",
+	"tbilisi":    "Asia/Tbilisi",
+	// Middle East and Africa
+	"dubai":        "Asia/Dubai",
+	"abu dhabi":    "Asia/Dubai",
+	"riyadh":       "Asia/Riyadh",
+	"tel aviv":     "Asia/Jerusalem",
+	"cairo":        "Africa/Cairo",
+	"lagos":        "Africa/Lagos",
+	"nairobi":      "Africa/Nairobi",
+	"johannesburg": "Africa/Johannesburg",
+	"cape town":    "Africa/Johannesburg",
+	// Asia and Oceania
+	"bengaluru":        "Asia/Kolkata",
+	"bangalore":        "Asia/Kolkata",
+	"mumbai":           "Asia/Kolkata",
+	"delhi":            "Asia/Kolkata",
+	"new delhi":        "Asia/Kolkata",
+	"hyderabad":        "Asia/Kolkata",
+	"pune":             "Asia/Kolkata",
+	"chennai":          "Asia/Kolkata",
+	"karachi":          "Asia/Karachi",
+	"lahore":           "Asia/Karachi",
+	"dhaka":            "Asia/Dhaka",
+	"singapore":        "Asia/Singapore",
+	"kuala lumpur":     "Asia/Kuala_Lumpur",
+	"jakarta":          "Asia/Jakarta",
+	"bangkok":          "Asia/Bangkok",
+	"manila":           "Asia/Manila",
+	"ho chi minh city": "Asia/Ho_Chi_Minh",
+	"hong kong":        "Asia/Hong_Kong",
+	"shanghai":         "Asia/Shanghai",
+	"beijing":          "Asia/Shanghai",
+	"shenzhen":         "Asia/Shanghai",
+	"taipei":           "Asia/Taipei",
+	"seoul":            "Asia/Seoul",
+	"tokyo":            "Asia/Tokyo",
+	"osaka":            "Asia/Tokyo",
+	"sydney":           "Australia/Sydney",
+	"melbourne":        "Australia/Melbourne",
+	"brisbane":         "Australia/Brisbane",
+	"perth":            "Australia/Perth",
+	"adelaide":         "Australia/Adelaide",
+	"auckland":         "Pacific/Auckland",
+	"wellington":       "Pacific/Auckland",
+}
+
+// regions maps the states and provinces of countries that span several
+// timezones
+var regions = map[string]string{
+	// United States
+	"alabama": "America/Chicago", "alaska": "America/Anchorage", "arizona": "America/Phoenix",
+	"arkansas": "America/Chicago", "california": "America/Los_Angeles", "colorado": "America/Denver",
+	"connecticut": "America/New_York", "delaware": "America/New_York", "district of columbia": "America/New_York",
+	"florida": "America/New_York", "georgia": "America/New_York", "hawaii": "Pacific/Honolulu",
+	"idaho": "America/Boise", "illinois": "America/Chicago", "indiana": "America/Indiana/Indianapolis",
+	"iowa": "America/Chicago", "kansas": "America/Chicago", "kentucky": "America/New_York",
+	"louisiana": "America/Chicago", "maine": "America/New_York", "maryland": "America/New_York",
+	"massachusetts": "America/New_York", "michigan": "America/Detroit", "minnesota": "America/Chicago",
+	"mississippi": "America/Chicago", "missouri": "America/Chicago", "montana": "America/Denver",
+	"nebraska": "America/Chicago", "nevada": "America/Los_Angeles", "new hampshire": "America/New_York",
+	"new jersey": "America/New_York", "new mexico": "America/Denver", "north carolina": "America/New_York",
+	"north dakota": "America/Chicago", "ohio": "America/New_York", "oklahoma": "America/Chicago",
+	"oregon": "America/Los_Angeles", "pennsylvania": "America/New_York", "rhode island": "America/New_York",
+	"south carolina": "America/New_York", "south dakota": "America/Chicago", "tennessee": "America/Chicago",
+	"texas": "America/Chicago", "utah": "America/Denver", "vermont": "America/New_York",
+	"virginia": "America/New_York", "washington": "America/Los_Angeles", "west virginia": "America/New_York",
+	"wisconsin": "America/Chicago", "wyoming": "America/Denver",
+	// Canada
+	"alberta": "America/Edmonton", "british columbia": "America/Vancouver", "manitoba": "America/Winnipeg",
+	"new brunswick": "America/Moncton", "newfoundland and labrador": "America/St_Johns", "nova scotia": "America/Halifax",
+	"ontario": "America/Toronto", "quebec": "America/Toronto", "saskatchewan": "America/Regina",
+	// Australia
+	"new south wales": "Australia/Sydney", "victoria": "Australia/Melbourne", "queensland": "Australia/Brisbane",
+	"western australia": "Australia/Perth", "south australia": "Australia/Adelaide", "tasmania": "Australia/Hobart",
+	"australian capital territory": "Australia/Sydney", "northern territory": "Australia/Darwin",
+	// Brazil
+	"amazonas": "America/Manaus",
+}
+
+// countries maps countries to their timezone, or to the zone most of their
+// business is done in for those spanning several
+var countries = map[string]string{
+	"united states": "America/Chicago", "united states of america": "America/Chicago", "usa": "America/Chicago",
+	"canada": "America/Toronto", "mexico": "America/Mexico_City", "brazil": "America/Sao_Paulo",
+	"argentina": "America/Argentina/Buenos_Aires", "chile": "America/Santiago", "colombia": "America/Bogota",
+	"peru":           "America/Lima",
+	"united kingdom": "Europe/London", "uk": "Europe/London", "england": "Europe/London",
+	"scotland": "Europe/London", "wales": "Europe/London", "northern ireland": "Europe/London",
+	"ireland": "Europe/Dublin", "portugal": "Europe/Lisbon", "spain": "Europe/Madrid", "france": "Europe/Paris",
+	"belgium": "Europe/Brussels", "netherlands": "Europe/Amsterdam", "the netherlands": "Europe/Amsterdam",
+	"luxembourg": "Europe/Luxembourg", "germany": "Europe/Berlin", "switzerland": "Europe/Zurich",
+	"austria": "Europe/Vienna", "italy": "Europe/Rome", "denmark": "Europe/Copenhagen", "norway": "Europe/Oslo",
+	"sweden": "Europe/Stockholm", "finland": "Europe/Helsinki", "estonia": "Europe/Tallinn",
+	"latvia": "Europe/Riga", "lithuania": "Europe/Vilnius", "poland": "Europe/Warsaw",
+	"czechia": "Europe/Prague", "czech republic": "Europe/Prague", "slovakia": "Europe/Bratislava",
+	"hungary": "Europe/Budapest", "romania": "Europe/Bucharest", "bulgaria": "Europe/Sofia",
+	"greece": "Europe/Athens", "croatia": "Europe/Zagreb", "serbia": "Europe/Belgrade",
+	"slovenia": "Europe/Ljubljana", "ukraine": "Europe/Kiev", "turkey": "Europe/Istanbul",
+	"türkiye": "Europe/Istanbul", "russia": "Europe/Moscow", "israel": "Asia/Jerusalem",
+	"united arab emirates": "Asia/Dubai", "uae": "Asia/Dubai", "saudi arabia": "Asia/Riyadh",
+	"qatar": "Asia/Qatar", "egypt": "Africa/Cairo", "morocco": "Africa/Casablanca", "nigeria": "Africa/Lagos",
+	"kenya": "Africa/Nairobi", "south africa": "Africa/Johannesburg",
+	"india": "Asia/Kolkata", "pakistan": "Asia/Karachi", "bangladesh": "Asia/Dhaka", "sri lanka": "Asia/Colombo",
+	"singapore": "Asia/Singapore", "malaysia": "Asia/Kuala_Lumpur", "indonesia": "Asia/Jakarta",
+	"thailand": "Asia/Bangkok", "vietnam": "Asia/Ho_Chi_Minh", "philippines": "Asia/Manila",
+	"hong kong": "Asia/Hong_Kong", "china": "Asia/Shanghai", "taiwan": "Asia/Taipei",
+	"south korea": "Asia/Seoul", "korea": "Asia/Seoul", "japan": "Asia/Tokyo",
+	"australia": "Australia/Sydney", "new zealand": "Pacific/Auckland",
+}
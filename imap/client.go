@@ -0,0 +1,177 @@
+// Package imap reads LinkedIn verification codes from a mailbox. It speaks just
+// enough IMAP4rev1 over TLS to search for and fetch messages.
+package imap
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// client is a minimal IMAP client for one authenticated session
+type client struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	tag    int
+}
+
+// dial connects to an IMAP server over TLS and reads its greeting
+func dial(address string, timeout time.Duration) (*client, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IMAP address %q: %w", address, err)
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", address, &tls.Config{ServerName: host})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+
+	c := &client{conn: conn, reader: bufio.NewReader(conn)}
+	greeting, err := c.readLine()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read greeting: %w", err)
+	}
+	if !strings.HasPrefix(greeting, "* OK") {
+		conn.Close()
+		return nil, fmt.Errorf("unexpected greeting: %s", greeting)
+	}
+	return c, nil
+}
+
+// login authenticates with a username and password
+func (c *client) login(username, password string) error {
+	_, err := c.command("LOGIN " + quote(username) + " " + quote(password))
+	if err != nil {
+		return fmt.Errorf("login failed: %w", err)
+	}
+	return nil
+}
+
+// selectMailbox opens a mailbox read-only
+func (c *client) selectMailbox(mailbox string) error {
+	if _, err := c.command("EXAMINE " + quote(mailbox)); err != nil {
+		return fmt.Errorf("failed to open %s: %w", mailbox, err)
+	}
+	return nil
+}
+
+// search returns the sequence numbers of messages matching criteria
+func (c *client) search(criteria string) ([]int, error) {
+	responses, err := c.command("SEARCH " + criteria)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+
+	var ids []int
+	for _, response := range responses {
+		if !strings.HasPrefix(response.line, "* SEARCH") {
+			continue
+		}
+		for _, field := range strings.Fields(strings.TrimPrefix(response.line, "* SEARCH")) {
+			if id, err := strconv.Atoi(field); err == nil {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// fetchMessage returns the full RFC 822 source of a message without marking it read
+func (c *client) fetchMessage(id int) ([]byte, error) {
+	responses, err := c.command(fmt.Sprintf("FETCH %d BODY.PEEK[]", id))
+	if err != nil {
+		return nil, fmt.Errorf("fetch failed: %w", err)
+	}
+
+	for _, response := range responses {
+		if response.literal != nil {
+			return response.literal, nil
+		}
+	}
+	return nil, fmt.Errorf("message %d has no body", id)
+}
+
+// logout ends the session and closes the connection
+func (c *client) logout() error {
+	c.command("LOGOUT")
+	return c.conn.Close()
+}
+
+// response is an untagged server response, with the literal it carried if any
+type response struct {
+	line    string
+	literal []byte
+}
+
+// command sends a tagged command and collects the untagged responses until
+// the matching tagged status, which must be OK
+func (c *client) command(command string) ([]response, error) {
+	c.tag++
+	tag := fmt.Sprintf("a%d", c.tag)
+
+	c.conn.SetDeadline(time.Now().Add(time.Minute))
+	if _, err := fmt.Fprintf(c.conn, "%s %s\r\n", tag, command); err != nil {
+		return nil, err
+	}
+
+	var responses []response
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.HasPrefix(line, tag+" ") {
+			status := strings.TrimPrefix(line, tag+" ")
+			if !strings.HasPrefix(status, "OK") {
+				return nil, fmt.Errorf("server replied %s", status)
+			}
+			return responses, nil
+		}
+
+		current := response{line: line}
+		// A line ending in {n} is followed by n bytes of literal data and the rest of the line
+		if size, ok := literalSize(line); ok {
+			current.literal = make([]byte, size)
+			if _, err := io.ReadFull(c.reader, current.literal); err != nil {
+				return nil, err
+			}
+			if _, err := c.readLine(); err != nil {
+				return nil, err
+			}
+		}
+		responses = append(responses, current)
+	}
+}
+
+func (c *client) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func literalSize(line string) (int, bool) {
+	if !strings.HasSuffix(line, "}") {
+		return 0, false
+	}
+	start := strings.LastIndex(line, "{")
+	if start < 0 {
+		return 0, false
+	}
+	size, err := strconv.Atoi(line[start+1 : len(line)-1])
+	return size, err == nil
+}
+
+// quote encodes s as an IMAP quoted string
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,171 @@
+package imap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Config holds the mailbox the LinkedIn account's emails arrive in
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	Mailbox  string
+	Timeout  time.Duration // How long to wait for the email
+}
+
+// CodeReader polls a mailbox for LinkedIn verification codes
+type CodeReader struct {
+	config Config
+	logger *logrus.Logger
+}
+
+// codePattern matches the six-digit PIN in LinkedIn's verification emails
+var codePattern = regexp.MustCompile(`\b(\d{6})\b`)
+
+// checkInterval is how often the mailbox is checked while waiting
+const checkInterval = 10 * time.Second
+
+// NewCodeReader creates a code reader for the mailbox in config
+func NewCodeReader(config Config, logger *logrus.Logger) *CodeReader {
+	if config.Port == 0 {
+		config.Port = 993
+	}
+	if config.Mailbox == "" {
+		config.Mailbox = "INBOX"
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 3 * time.Minute
+	}
+	return &CodeReader{config: config, logger: logger}
+}
+
+// WaitForCode polls the mailbox until a LinkedIn verification email sent after
+// since arrives, and returns its code
+func (r *CodeReader) WaitForCode(ctx context.Context, since time.Time) (string, error) {
+	deadline := time.Now().Add(r.config.Timeout)
+	r.logger.WithField("mailbox", r.config.Username).Info("Waiting for verification email")
+
+	for {
+		code, err := r.findCode(since)
+		if err != nil {
+			r.logger.WithError(err).Warn("Failed to check mailbox")
+		} else if code != "" {
+			return code, nil
+		}
+
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("no verification email received within %v", r.config.Timeout)
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(checkInterval):
+		}
+	}
+}
+
+// findCode returns the code from the newest matching email, or "" if none has arrived
+func (r *CodeReader) findCode(since time.Time) (string, error) {
+	c, err := dial(fmt.Sprintf("%s:%d", r.config.Host, r.config.Port), 30*time.Second)
+	if err != nil {
+		return "", err
+	}
+	defer c.logout()
+
+	if err := c.login(r.config.Username, r.config.Password); err != nil {
+		return "", err
+	}
+	if err := c.selectMailbox(r.config.Mailbox); err != nil {
+		return "", err
+	}
+
+	// SINCE only has day granularity; exact times are checked against the Date header
+	ids, err := c.search(fmt.Sprintf(`FROM "linkedin.com" SINCE %s`, since.AddDate(0, 0, -1).Format("02-Jan-2006")))
+	if err != nil {
+		return "", err
+	}
+
+	// Newest messages have the highest sequence numbers
+	for i := len(ids) - 1; i >= 0; i-- {
+		raw, err := c.fetchMessage(ids[i])
+		if err != nil {
+			return "", err
+		}
+
+		message, err := mail.ReadMessage(bytes.NewReader(raw))
+		if err != nil {
+			continue
+		}
+		// Allow for clock skew between this machine and the mail server
+		if date, err := message.Header.Date(); err == nil && date.Before(since.Add(-time.Minute)) {
+			break
+		}
+
+		if code := extractCode(message); code != "" {
+			return code, nil
+		}
+	}
+
+	return "", nil
+}
+
+// extractCode finds a verification code in a message's subject or text
+func extractCode(message *mail.Message) string {
+	subject, _ := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	if !strings.Contains(strings.ToLower(subject), "code") && !strings.Contains(strings.ToLower(subject), "pin") {
+		return ""
+	}
+	if match := codePattern.FindStringSubmatch(subject); match != nil {
+		return match[1]
+	}
+
+	text := messageText(message.Header.Get("Content-Type"), message.Header.Get("Content-Transfer-Encoding"), message.Body)
+	if match := codePattern.FindStringSubmatch(text); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// messageText returns the decoded text parts of a message body
+func messageText(contentType, encoding string, body io.Reader) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		var text strings.Builder
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			text.WriteString(messageText(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part))
+			text.WriteString("\n")
+		}
+		return text.String()
+	}
+
+	if !strings.HasPrefix(mediaType, "text/") {
+		return ""
+	}
+	if strings.EqualFold(encoding, "quoted-printable") {
+		body = quotedprintable.NewReader(body)
+	}
+	data, _ := io.ReadAll(io.LimitReader(body, 1<<20))
+	return string(data)
+}
@@ -0,0 +1,54 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// HubSpot creates contacts through the HubSpot CRM v3 API
+type HubSpot struct {
+	apiKey  string
+	baseURL string
+}
+
+// NewHubSpot creates a HubSpot connector authenticated with a private app access token
+func NewHubSpot(apiKey, baseURL string) *HubSpot {
+	return &HubSpot{
+		apiKey:  apiKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Name returns the connector name
+func (h *HubSpot) Name() string {
+	return "hubspot"
+}
+
+// PushContact creates a HubSpot contact; the LinkedIn profile URL is stored as the website
+func (h *HubSpot) PushContact(ctx context.Context, contact *Contact) (string, error) {
+	properties := map[string]string{"website": contact.ProfileURL}
+	for key, value := range map[string]string{
+		"firstname": contact.FirstName,
+		"lastname":  contact.LastName,
+		"jobtitle":  contact.Title,
+		"company":   contact.Company,
+		"city":      contact.Location,
+	} {
+		if value != "" {
+			properties[key] = value
+		}
+	}
+
+	var response struct {
+		ID string `json:"id"`
+	}
+	err := postJSON(ctx, h.baseURL+"/crm/v3/objects/contacts",
+		map[string]string{"Authorization": "Bearer " + h.apiKey},
+		map[string]interface{}{"properties": properties}, &response)
+	if err != nil {
+		return "", fmt.Errorf("hubspot: failed to create contact: %w", err)
+	}
+
+	return response.ID, nil
+}
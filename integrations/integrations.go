@@ -0,0 +1,163 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/storage"
+)
+
+// Contact is a LinkedIn connection as pushed to a CRM
+type Contact struct {
+	ProfileURL string
+	FirstName  string
+	LastName   string
+	Title      string
+	Headline   string
+	Company    string
+	Location   string
+}
+
+// Connector pushes contacts into an external CRM
+type Connector interface {
+	Name() string
+	PushContact(ctx context.Context, contact *Contact) (string, error) // Returns the CRM's record ID
+}
+
+// Store supplies accepted connections and remembers which were already pushed
+type Store interface {
+	GetAcceptedProfiles() ([]*storage.Profile, error)
+	IsCRMSynced(connector, profileURL string) (bool, error)
+	RecordCRMSync(connector, profileURL, externalID string) error
+}
+
+// PushResult is the outcome of pushing one contact to one connector
+type PushResult struct {
+	Connector  string
+	ProfileURL string
+	ExternalID string
+	Error      error
+}
+
+// SyncResult summarizes a CRM sync
+type SyncResult struct {
+	Results []*PushResult
+	Skipped int // Contacts already pushed in an earlier sync
+}
+
+// Syncer pushes accepted connections to every configured connector
+type Syncer struct {
+	store      Store
+	connectors []Connector
+	logger     *logrus.Logger
+}
+
+// NewSyncer creates a new CRM syncer
+func NewSyncer(store Store, connectors []Connector, logger *logrus.Logger) *Syncer {
+	return &Syncer{
+		store:      store,
+		connectors: connectors,
+		logger:     logger,
+	}
+}
+
+// Sync pushes accepted connections that a connector has not received yet.
+// A failed push is reported and retried on the next sync.
+func (s *Syncer) Sync(ctx context.Context) (*SyncResult, error) {
+	profiles, err := s.store.GetAcceptedProfiles()
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"profiles":   len(profiles),
+		"connectors": len(s.connectors),
+	}).Info("Starting CRM sync")
+
+	result := &SyncResult{}
+	for _, connector := range s.connectors {
+		for _, profile := range profiles {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+
+			synced, err := s.store.IsCRMSynced(connector.Name(), profile.URL)
+			if err != nil {
+				return result, err
+			}
+			if synced {
+				result.Skipped++
+				continue
+			}
+
+			push := &PushResult{Connector: connector.Name(), ProfileURL: profile.URL}
+			push.ExternalID, push.Error = connector.PushContact(ctx, ContactFromProfile(profile))
+			result.Results = append(result.Results, push)
+
+			if push.Error != nil {
+				s.logger.WithError(push.Error).WithFields(logrus.Fields{
+					"connector": connector.Name(),
+					"profile":   profile.URL,
+				}).Warn("Failed to push contact")
+				continue
+			}
+
+			if err := s.store.RecordCRMSync(connector.Name(), profile.URL, push.ExternalID); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	s.logger.WithField("attempted", len(result.Results)).Info("CRM sync completed")
+	return result, nil
+}
+
+// ContactFromProfile converts a stored profile into a CRM contact
+func ContactFromProfile(profile *storage.Profile) *Contact {
+	first, last := splitName(profile.Name)
+	return &Contact{
+		ProfileURL: profile.URL,
+		FirstName:  first,
+		LastName:   last,
+		Title:      profile.Title,
+		Headline:   profile.Headline,
+		Company:    profile.Company,
+		Location:   profile.Location,
+	}
+}
+
+// FullName returns the contact's display name
+func (c *Contact) FullName() string {
+	return strings.TrimSpace(c.FirstName + " " + c.LastName)
+}
+
+// summary describes the contact for CRMs that keep extra details in a note
+func (c *Contact) summary() string {
+	lines := []string{"LinkedIn: " + c.ProfileURL}
+	for _, field := range []struct{ label, value string }{
+		{"Title", c.Title},
+		{"Headline", c.Headline},
+		{"Company", c.Company},
+		{"Location", c.Location},
+	} {
+		if field.value != "" {
+			lines = append(lines, fmt.Sprintf("%s: %s", field.label, field.value))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func splitName(name string) (string, string) {
+	parts := strings.Fields(name)
+	switch len(parts) {
+	case 0:
+		return "", ""
+	case 1:
+		return parts[0], ""
+	default:
+		return parts[0], strings.Join(parts[1:], " ")
+	}
+}
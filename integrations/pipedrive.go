@@ -0,0 +1,65 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Pipedrive creates persons through the Pipedrive v1 API
+type Pipedrive struct {
+	apiToken string
+	baseURL  string
+	logger   *logrus.Logger
+}
+
+// NewPipedrive creates a Pipedrive connector authenticated with an API token
+func NewPipedrive(apiToken, baseURL string, logger *logrus.Logger) *Pipedrive {
+	return &Pipedrive{
+		apiToken: apiToken,
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		logger:   logger,
+	}
+}
+
+// Name returns the connector name
+func (p *Pipedrive) Name() string {
+	return "pipedrive"
+}
+
+// PushContact creates a Pipedrive person and attaches the scraped profile details as a note
+func (p *Pipedrive) PushContact(ctx context.Context, contact *Contact) (string, error) {
+	name := contact.FullName()
+	if name == "" {
+		name = contact.ProfileURL
+	}
+
+	var person struct {
+		Data struct {
+			ID int `json:"id"`
+		} `json:"data"`
+	}
+	if err := postJSON(ctx, p.endpoint("/v1/persons"), nil, map[string]interface{}{"name": name}, &person); err != nil {
+		return "", fmt.Errorf("pipedrive: failed to create person: %w", err)
+	}
+
+	id := strconv.Itoa(person.Data.ID)
+	note := map[string]interface{}{
+		"person_id": person.Data.ID,
+		"content":   strings.ReplaceAll(contact.summary(), "\n", "<br>"),
+	}
+	if err := postJSON(ctx, p.endpoint("/v1/notes"), nil, note, nil); err != nil {
+		// The person exists; report it as pushed rather than creating a duplicate next time
+		p.logger.WithError(err).WithField("person_id", id).Warn("Pipedrive person created without profile note")
+	}
+
+	return id, nil
+}
+
+func (p *Pipedrive) endpoint(path string) string {
+	return p.baseURL + path + "?api_token=" + url.QueryEscape(p.apiToken)
+}
@@ -0,0 +1,295 @@
+// Package invitations lists the connection invitations the account has
+// received and accepts or ignores them, by hand or by configured rules.
+package invitations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/pause"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/selectors"
+	"linkedin-automation/storage"
+	"linkedin-automation/uitext"
+)
+
+// Actions an invitation can be answered with
+const (
+	ActionAccept = "accept"
+	ActionIgnore = "ignore"
+)
+
+const invitationsURL = "https://www.linkedin.com/mynetwork/invitation-manager/"
+
+// maxInvitationScrolls bounds the scrolling through the invitation list
+const maxInvitationScrolls = 100
+
+// Invitation is a pending incoming connection invitation
+type Invitation struct {
+	Name       string
+	Headline   string
+	ProfileURL string
+	Message    string // Note sent with the invitation, if any
+	Mutual     int    // Mutual connections
+	HasPhoto   bool
+}
+
+// StealthManager interface for stealth operations
+type StealthManager interface {
+	RandomDelay() time.Duration
+	HumanLikeScroll(page *rod.Page, scrollAmount int) error
+}
+
+// Store records answered invitations
+type Store interface {
+	SaveInvitationAction(action *storage.InvitationAction) error
+}
+
+// Capturer saves the page for debugging when an action fails
+type Capturer interface {
+	Failure(page *rod.Page, action string, err error)
+}
+
+// Manager answers incoming invitations on the invitation manager page
+type Manager struct {
+	page     *rod.Page
+	logger   *logrus.Logger
+	stealth  StealthManager
+	store    Store
+	capturer Capturer
+	dryRun   bool
+}
+
+// Result is the outcome of answering one invitation
+type Result struct {
+	Invitation *Invitation
+	Action     string // Empty when no rule matched and the invitation was left pending
+	Rule       string
+	DryRun     bool
+	Err        error
+}
+
+// NewManager creates an invitation manager
+func NewManager(page *rod.Page, logger *logrus.Logger, stealth StealthManager) *Manager {
+	return &Manager{
+		page:    page,
+		logger:  logger,
+		stealth: stealth,
+	}
+}
+
+// SetStore records every answered invitation in store
+func (m *Manager) SetStore(store Store) {
+	m.store = store
+}
+
+// SetCapturer saves the page whenever answering an invitation fails
+func (m *Manager) SetCapturer(capturer Capturer) {
+	m.capturer = capturer
+}
+
+// SetDryRun decides invitations without clicking Accept or Ignore
+func (m *Manager) SetDryRun(dryRun bool) {
+	m.dryRun = dryRun
+}
+
+// List returns up to maxResults pending invitations, or all of them when maxResults is 0
+func (m *Manager) List(ctx context.Context, maxResults int) (invitations []*Invitation, err error) {
+	defer m.withContext(ctx)()
+	defer func() { m.captureFailure("list invitations", err) }()
+
+	if err := m.page.Navigate(invitationsURL); err != nil {
+		return nil, fmt.Errorf("failed to navigate to invitations: %w", err)
+	}
+	if err := m.page.WaitLoad(); err != nil {
+		return nil, fmt.Errorf("failed to wait for page load: %w", err)
+	}
+	if _, err := selectors.Wait(m.page, selectors.InvitationCard); err != nil {
+		m.logger.Info("No pending invitations")
+		return nil, nil
+	}
+
+	seen := make(map[string]bool)
+	for scroll := 0; scroll < maxInvitationScrolls; scroll++ {
+		if err := ctx.Err(); err != nil {
+			return invitations, err
+		}
+
+		for _, card := range selectors.FindAll(m.page, selectors.InvitationCard) {
+			if maxResults > 0 && len(invitations) >= maxResults {
+				break
+			}
+			invitation := extractInvitation(card)
+			if invitation == nil || seen[invitation.ProfileURL] {
+				continue
+			}
+			seen[invitation.ProfileURL] = true
+			invitations = append(invitations, invitation)
+		}
+
+		if (maxResults > 0 && len(invitations) >= maxResults) || !m.loadMore() {
+			break
+		}
+	}
+
+	m.logger.WithField("count", len(invitations)).Info("Listed pending invitations")
+	return invitations, nil
+}
+
+// Respond accepts or ignores the pending invitation from profileURL and
+// records it with the rule that decided, if any
+func (m *Manager) Respond(ctx context.Context, invitation *Invitation, action, rule string) (err error) {
+	if action != ActionAccept && action != ActionIgnore {
+		return fmt.Errorf("unknown invitation action %q", action)
+	}
+	defer m.withContext(ctx)()
+	defer func() { m.captureFailure(action+" invitation", err) }()
+
+	if !m.dryRun {
+		card := m.findCard(invitation.ProfileURL)
+		if card == nil {
+			return fmt.Errorf("no pending invitation from %s", invitation.ProfileURL)
+		}
+
+		key := selectors.InvitationAccept
+		if action == ActionIgnore {
+			key = selectors.InvitationIgnore
+		}
+		button := selectors.FindIn(card, key)
+		if button == nil {
+			return fmt.Errorf("%s button not found for %s", action, invitation.ProfileURL)
+		}
+		if err := button.ScrollIntoView(); err != nil {
+			m.logger.WithError(err).Debug("Failed to scroll to invitation")
+		}
+		if err := pause.Sleep(ctx, m.stealth.RandomDelay()); err != nil {
+			return err
+		}
+		if err := button.Click("left", 1); err != nil {
+			return fmt.Errorf("failed to %s invitation: %w", action, err)
+		}
+		// Answered by now, so it is recorded even if the run is cancelled
+		pause.Sleep(ctx, m.stealth.RandomDelay())
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"profile_url": invitation.ProfileURL,
+		"action":      action,
+		"rule":        rule,
+		"dry_run":     m.dryRun,
+	}).Info("Invitation answered")
+
+	if m.store != nil {
+		if err := m.store.SaveInvitationAction(&storage.InvitationAction{
+			ProfileURL: invitation.ProfileURL,
+			Name:       invitation.Name,
+			Headline:   invitation.Headline,
+			Action:     action,
+			Rule:       rule,
+			DryRun:     m.dryRun,
+		}); err != nil {
+			m.logger.WithError(err).Warn("Failed to record invitation action")
+		}
+	}
+	return nil
+}
+
+// Process lists up to maxResults pending invitations and answers each with the
+// first rule that matches it. Invitations no rule matches are left pending.
+func (m *Manager) Process(ctx context.Context, rules []*Rule, maxResults int) ([]*Result, error) {
+	invitations, err := m.List(ctx, maxResults)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*Result, 0, len(invitations))
+	for _, invitation := range invitations {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		result := &Result{Invitation: invitation, DryRun: m.dryRun}
+		if rule := Match(rules, invitation); rule != nil {
+			result.Action = rule.Action
+			result.Rule = rule.String()
+			result.Err = m.Respond(ctx, invitation, rule.Action, result.Rule)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// findCard returns the card of the pending invitation from profileURL, or nil
+func (m *Manager) findCard(profileURL string) *rod.Element {
+	canonical := profileurl.Canonicalize(profileURL)
+	for _, card := range selectors.FindAll(m.page, selectors.InvitationCard) {
+		if invitation := extractInvitation(card); invitation != nil && invitation.ProfileURL == canonical {
+			return card
+		}
+	}
+	return nil
+}
+
+// loadMore scrolls down and clicks "Show more" if shown, reporting whether more invitations appeared
+func (m *Manager) loadMore() bool {
+	before := len(selectors.FindAll(m.page, selectors.InvitationCard))
+
+	if err := m.stealth.HumanLikeScroll(m.page, 1500); err != nil {
+		m.logger.WithError(err).Debug("Failed to scroll invitation list")
+	}
+	if button, _ := selectors.Find(m.page, selectors.ShowMoreButton); button != nil {
+		if err := button.Click("left", 1); err != nil {
+			m.logger.WithError(err).Debug("Failed to click show more button")
+		}
+	}
+
+	for wait := 0; wait < 3; wait++ {
+		if pause.Page(m.page, m.stealth.RandomDelay()) != nil {
+			return false
+		}
+		if len(selectors.FindAll(m.page, selectors.InvitationCard)) > before {
+			return true
+		}
+	}
+	return false
+}
+
+// withContext binds the page to ctx until the returned function is called,
+// so navigation, element lookups and pauses stop once ctx is cancelled
+func (m *Manager) withContext(ctx context.Context) func() {
+	page := m.page
+	m.page = page.Context(ctx)
+	return func() { m.page = page }
+}
+
+func (m *Manager) captureFailure(action string, err error) {
+	if err != nil && m.capturer != nil {
+		m.capturer.Failure(m.page, action, err)
+	}
+}
+
+// extractInvitation reads an invitation card, or returns nil if it is not from a member
+func extractInvitation(card *rod.Element) *Invitation {
+	link := selectors.FindIn(card, selectors.ProfileLink)
+	if link == nil {
+		return nil
+	}
+	href, err := link.Attribute("href")
+	if err != nil || href == nil || profileurl.Slug(*href) == "" {
+		return nil
+	}
+
+	return &Invitation{
+		Name:       selectors.TextIn(card, selectors.InvitationName),
+		Headline:   selectors.TextIn(card, selectors.InvitationHeadline),
+		ProfileURL: profileurl.Canonicalize(*href),
+		Message:    selectors.TextIn(card, selectors.InvitationMessage),
+		Mutual:     uitext.MutualCount(selectors.TextIn(card, selectors.InvitationMutual)),
+		HasPhoto:   selectors.FindIn(card, selectors.InvitationPhoto) != nil,
+	}
+}
@@ -0,0 +1,104 @@
+package invitations
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"linkedin-automation/config"
+)
+
+// Rule accepts or ignores invitations matching all of its conditions
+type Rule struct {
+	Action      string
+	Headline    *regexp.Regexp // Nil matches any headline
+	Name        *regexp.Regexp // Nil matches any name
+	NoPhoto     bool
+	MinMutual   int
+	WithMessage bool
+}
+
+// RulesFromConfig compiles the configured rules in order
+func RulesFromConfig(configured []config.InvitationRule) ([]*Rule, error) {
+	rules := make([]*Rule, 0, len(configured))
+	for i, c := range configured {
+		rule := &Rule{
+			Action:      c.Action,
+			NoPhoto:     c.NoPhoto,
+			MinMutual:   c.MinMutual,
+			WithMessage: c.WithMessage,
+		}
+		if rule.Action != ActionAccept && rule.Action != ActionIgnore {
+			return nil, fmt.Errorf("invitation rule %d: action must be %s or %s", i+1, ActionAccept, ActionIgnore)
+		}
+
+		var err error
+		if c.Headline != "" {
+			if rule.Headline, err = regexp.Compile(c.Headline); err != nil {
+				return nil, fmt.Errorf("invitation rule %d: invalid headline pattern: %w", i+1, err)
+			}
+		}
+		if c.Name != "" {
+			if rule.Name, err = regexp.Compile(c.Name); err != nil {
+				return nil, fmt.Errorf("invitation rule %d: invalid name pattern: %w", i+1, err)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Matches reports whether the invitation meets every condition of the rule
+func (r *Rule) Matches(invitation *Invitation) bool {
+	if r.Headline != nil && !r.Headline.MatchString(invitation.Headline) {
+		return false
+	}
+	if r.Name != nil && !r.Name.MatchString(invitation.Name) {
+		return false
+	}
+	if r.NoPhoto && invitation.HasPhoto {
+		return false
+	}
+	if invitation.Mutual < r.MinMutual {
+		return false
+	}
+	if r.WithMessage && invitation.Message == "" {
+		return false
+	}
+	return true
+}
+
+// String describes the rule, e.g. "accept if headline ~ (?i)recruiter"; it is
+// recorded with each invitation the rule answers
+func (r *Rule) String() string {
+	var conditions []string
+	if r.Headline != nil {
+		conditions = append(conditions, "headline ~ "+r.Headline.String())
+	}
+	if r.Name != nil {
+		conditions = append(conditions, "name ~ "+r.Name.String())
+	}
+	if r.NoPhoto {
+		conditions = append(conditions, "no photo")
+	}
+	if r.MinMutual > 0 {
+		conditions = append(conditions, fmt.Sprintf("%d+ mutual", r.MinMutual))
+	}
+	if r.WithMessage {
+		conditions = append(conditions, "with message")
+	}
+	if len(conditions) == 0 {
+		return r.Action + " all"
+	}
+	return r.Action + " if " + strings.Join(conditions, " and ")
+}
+
+// Match returns the first rule the invitation matches, or nil if none does
+func Match(rules []*Rule, invitation *Invitation) *Rule {
+	for _, rule := range rules {
+		if rule.Matches(invitation) {
+			return rule
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"linkedin-automation/config"
+	"linkedin-automation/queue"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/storage"
+)
+
+// weeklyInvites is the weekly invitation budget with the queued connection
+// requests it is planned for
+type weeklyInvites struct {
+	*ratelimit.WeeklyPlan
+	PendingByCampaign map[string]int `json:"pending_by_campaign,omitempty"`
+}
+
+// inviteBudget plans the weekly invitation budget for the connection requests
+// waiting in the queue, or returns nil if limits.weekly_connections is 0.
+// Queued requests are spread over the days until they run out.
+func inviteBudget(cfg *config.Config, db *storage.Database, now time.Time) (*weeklyInvites, error) {
+	if cfg.Limits.WeeklyConnections <= 0 {
+		return nil, nil
+	}
+
+	sent, err := db.GetRateLimitEvents(string(ratelimit.ActionConnect), now.Add(-ratelimit.Week))
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := db.ListTasks(storage.TaskPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queued tasks: %w", err)
+	}
+	pending, byCampaign := 0, make(map[string]int)
+	for _, task := range tasks {
+		if task.Kind != queue.KindConnect {
+			continue
+		}
+		var payload queue.ConnectPayload
+		if err := queue.Decode(task, &payload); err != nil {
+			continue
+		}
+		pending++
+		byCampaign[payload.Campaign]++
+	}
+
+	// With nothing queued, show the room each day has for requests sent directly
+	planned := pending
+	if planned == 0 {
+		planned = -1
+	}
+	plan := ratelimit.PlanWeek(cfg.Limits.WeeklyConnections, sent, planned, now)
+	plan.Pending = pending
+	return &weeklyInvites{WeeklyPlan: plan, PendingByCampaign: byCampaign}, nil
+}
+
+// printInviteBudget writes the plan for the status report, one line per day
+func printInviteBudget(invites *weeklyInvites) {
+	fmt.Printf("  Sent: %d/%d (last 7 days), %d remaining\n", invites.Sent, invites.Limit, invites.Remaining)
+	if invites.Pending > 0 {
+		fmt.Printf("  Queued connection requests: %d\n", invites.Pending)
+		campaigns := make([]string, 0, len(invites.PendingByCampaign))
+		for campaign := range invites.PendingByCampaign {
+			campaigns = append(campaigns, campaign)
+		}
+		sort.Strings(campaigns)
+		for _, campaign := range campaigns {
+			name := campaign
+			if name == "" {
+				name = "(no campaign)"
+			}
+			fmt.Printf("    %s: %d\n", name, invites.PendingByCampaign[campaign])
+		}
+	}
+	fmt.Printf("  Daily targets:\n")
+	for i, day := range invites.Days {
+		label := day.Date.Format("Mon 01-02")
+		if i == 0 {
+			fmt.Printf("    %s  %d/%d (today)\n", label, day.Sent, day.Target)
+			continue
+		}
+		fmt.Printf("    %s  %d\n", label, day.Target)
+	}
+}
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/profileurl"
+)
+
+// runID identifies this invocation of the command in logs and stored rows
+var runID = newRunID()
+
+// prospectFields are the entry fields naming the prospect an entry is about,
+// in the order they are looked at
+var prospectFields = []string{"profile_url", "recipient_url", "recipient", "url", "profile", "sender", "target_url"}
+
+// RunID returns the ID of this command invocation, which every log entry
+// carries as run_id and stored rows as their run_id column
+func RunID() string {
+	return runID
+}
+
+// newRunID returns a random ID, or one from the clock if no randomness is
+// available
+func newRunID() string {
+	id := make([]byte, 6)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(id)
+}
+
+// correlationHook adds the run ID to every entry and, to entries about a
+// prospect, the prospect's profile slug, so one prospect's journey can be
+// followed across runs by a single field whichever field named them
+type correlationHook struct{}
+
+func (correlationHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (correlationHook) Fire(entry *logrus.Entry) error {
+	entry.Data["run_id"] = runID
+	if _, ok := entry.Data["prospect"]; ok {
+		return nil
+	}
+	for _, field := range prospectFields {
+		value, ok := entry.Data[field].(string)
+		if !ok {
+			continue
+		}
+		if slug := profileurl.Slug(value); slug != "" {
+			entry.Data["prospect"] = slug
+			return nil
+		}
+	}
+	return nil
+}
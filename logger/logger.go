@@ -1,68 +1,143 @@
 package logger
 
 import (
+	"io"
 	"os"
-	"path/filepath"
 
 	"github.com/sirupsen/logrus"
 )
 
 var Logger *logrus.Logger
 
-// InitLogger initializes the global logger with the specified configuration
-func InitLogger(level, format, output string, maxSize, maxBackups, maxAge int) error {
-	Logger = logrus.New()
+// files are the log files the logger writes to, closed when it is set up again
+var files []io.Closer
+
+// Options configures the logger
+type Options struct {
+	Level      string
+	Format     string            // json or text
+	Output     string            // stdout, stderr or a file path
+	MaxSize    int               // Megabytes a log file grows to before it is rotated; 0 never rotates
+	MaxBackups int               // Rotated files to keep; 0 keeps all
+	MaxAge     int               // Days to keep rotated files; 0 keeps them
+	ErrorFile  string            // Also writes errors to this file, rotated like the log file; empty disables it
+	Modules    map[string]string // Levels of single packages, e.g. stealth: debug
+	Reveal     bool              // Logs emails, passwords, session cookies and message text unmasked
+}
 
-	// Set log level
-	logLevel, err := logrus.ParseLevel(level)
+// InitLogger sets up the global logger. Set up again, the same logger is
+// reconfigured, so components holding it follow the new settings.
+func InitLogger(opts Options) error {
+	logLevel, err := logrus.ParseLevel(opts.Level)
 	if err != nil {
 		logLevel = logrus.InfoLevel
 	}
-	Logger.SetLevel(logLevel)
 
-	// Set formatter
-	switch format {
-	case "json":
-		Logger.SetFormatter(&logrus.JSONFormatter{
-			TimestampFormat: "2006-01-02T15:04:05.000Z07:00",
-		})
+	var formatter logrus.Formatter
+	switch opts.Format {
 	case "text":
-		Logger.SetFormatter(&logrus.TextFormatter{
+		formatter = &logrus.TextFormatter{
 			FullTimestamp:   true,
 			TimestampFormat: "2006-01-02T15:04:05.000Z07:00",
-		})
+		}
 	default:
-		Logger.SetFormatter(&logrus.JSONFormatter{
+		formatter = &logrus.JSONFormatter{
 			TimestampFormat: "2006-01-02T15:04:05.000Z07:00",
-		})
+		}
 	}
 
-	// Set output
-	switch output {
+	var opened []io.Closer
+	var output io.Writer
+	switch opts.Output {
 	case "stdout":
-		Logger.SetOutput(os.Stdout)
+		output = os.Stdout
 	case "stderr":
-		Logger.SetOutput(os.Stderr)
+		output = os.Stderr
 	default:
-		// File output
-		if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		file, err := openRotatingFile(opts.Output, opts.MaxSize, opts.MaxBackups, opts.MaxAge)
+		if err != nil {
 			return err
 		}
-		file, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+		output = file
+		opened = append(opened, file)
+	}
+
+	hooks := make(logrus.LevelHooks)
+	hooks.Add(correlationHook{})
+	if !opts.Reveal {
+		hooks.Add(redactHook{})
+	}
+	if opts.ErrorFile != "" {
+		file, err := openRotatingFile(opts.ErrorFile, opts.MaxSize, opts.MaxBackups, opts.MaxAge)
 		if err != nil {
+			closeAll(opened)
 			return err
 		}
-		Logger.SetOutput(file)
+		hooks.Add(&errorFileHook{file: file, formatter: &logrus.JSONFormatter{TimestampFormat: "2006-01-02T15:04:05.000Z07:00"}})
+		opened = append(opened, file)
 	}
 
+	// Entries are logged down to the most verbose level any package has, and
+	// the formatter drops those below their package's level
+	if len(opts.Modules) > 0 {
+		levels := make(map[string]logrus.Level, len(opts.Modules))
+		verbosest := logLevel
+		for module, level := range opts.Modules {
+			parsed, err := logrus.ParseLevel(level)
+			if err != nil {
+				continue
+			}
+			levels[module] = parsed
+			verbosest = max(verbosest, parsed)
+		}
+		formatter = moduleFormatter{Formatter: formatter, levels: levels, fallback: logLevel}
+		logLevel = verbosest
+	}
+
+	if Logger == nil {
+		Logger = logrus.New()
+	}
+	Logger.SetLevel(logLevel)
+	Logger.SetFormatter(formatter)
+	Logger.SetOutput(output)
+	Logger.ReplaceHooks(hooks)
+
+	closeAll(files)
+	files = opened
 	return nil
 }
 
+// closeAll closes the log files
+func closeAll(closers []io.Closer) {
+	for _, c := range closers {
+		c.Close()
+	}
+}
+
+// errorFileHook writes errors to a separate log file as well
+type errorFileHook struct {
+	file      io.Writer
+	formatter logrus.Formatter
+}
+
+func (h *errorFileHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel}
+}
+
+func (h *errorFileHook) Fire(entry *logrus.Entry) error {
+	data, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	_, err = h.file.Write(data)
+	return err
+}
+
 // GetLogger returns the global logger instance
 func GetLogger() *logrus.Logger {
 	if Logger == nil {
 		// Initialize with default settings if not already initialized
-		InitLogger("info", "json", "stdout", 100, 3, 28)
+		InitLogger(Options{Level: "info", Format: "json", Output: "stdout"})
 	}
 	return Logger
 }
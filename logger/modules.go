@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"runtime"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// modulePrefix starts the import path of this repository's packages
+const modulePrefix = "linkedin-automation/"
+
+// moduleFormatter drops the entries below the level set for the package
+// logging them, such as stealth or search, and formats the rest with the
+// formatter it wraps. The logger's own level is the most verbose of them.
+type moduleFormatter struct {
+	logrus.Formatter
+	levels   map[string]logrus.Level
+	fallback logrus.Level // For packages without a level of their own
+}
+
+func (f moduleFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	level, ok := f.levels[callerModule()]
+	if !ok {
+		level = f.fallback
+	}
+	if entry.Level > level {
+		return nil, nil
+	}
+	return f.Formatter.Format(entry)
+}
+
+// callerModule returns the package that logged the entry being formatted,
+// without the repository's import path, such as stealth or main
+func callerModule() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		pkg := packageOf(frame.Function)
+		if !strings.HasPrefix(pkg, "github.com/sirupsen/logrus") && pkg != modulePrefix+"logger" {
+			return strings.TrimPrefix(pkg, modulePrefix)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// packageOf returns the import path of the package of a function named as
+// in runtime.Frame, e.g. linkedin-automation/stealth.(*StealthManager).Click
+func packageOf(function string) string {
+	slash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[slash+1:], "."); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+	return function
+}
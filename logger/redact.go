@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// secretFields are parts of field names whose values are never logged
+var secretFields = []string{"password", "secret", "token", "api_key", "apikey", "cookie", "li_at", "jsessionid", "authorization", "csrf"}
+
+// contentFields are field names holding the text of messages, notes and
+// comments, of which only the length is logged
+var contentFields = map[string]bool{
+	"content": true, "message": true, "note": true, "body": true, "text": true, "reply": true,
+	"snippet": true, "comment": true, "opener": true, "subject": true, "draft": true, "prompt": true,
+}
+
+var (
+	// emailPattern finds email addresses, kept as their first letter and domain
+	emailPattern = regexp.MustCompile(`([A-Za-z0-9._%+-])[A-Za-z0-9._%+-]*@([A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+
+	// cookiePattern finds session cookies set or sent as name=value, or as
+	// JSON "name": "value"
+	cookiePattern = regexp.MustCompile(`(?i)\b(li_at|li_a|jsessionid|bcookie|bscookie|lidc|li_rm)("?\s*[=:]\s*"?)[^;\s"&,]+`)
+
+	// bearerPattern finds bearer tokens in authorization headers
+	bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]+`)
+
+	// sessionPattern finds li_at values on their own
+	sessionPattern = regexp.MustCompile(`\bAQED[A-Za-z0-9_-]{20,}`)
+)
+
+// redactHook masks emails, passwords, session cookies and message text in
+// every entry before it is written
+type redactHook struct{}
+
+func (redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (redactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = redact(entry.Message)
+	for key, value := range entry.Data {
+		var s string
+		switch v := value.(type) {
+		case string:
+			s = v
+		case error:
+			s = v.Error()
+		case fmt.Stringer:
+			s = v.String()
+		default:
+			continue
+		}
+		// Errors and other values stay as they are unless they are masked
+		if redacted := redactField(key, s); redacted != s {
+			entry.Data[key] = redacted
+		}
+	}
+	return nil
+}
+
+// redactField masks value as the field named key is logged
+func redactField(key, value string) string {
+	if value == "" {
+		return value
+	}
+	name := strings.ToLower(key)
+	for _, secret := range secretFields {
+		if strings.Contains(name, secret) {
+			return "[redacted]"
+		}
+	}
+	if contentFields[name] {
+		return fmt.Sprintf("[redacted %d chars]", len([]rune(value)))
+	}
+	return redact(value)
+}
+
+// redact masks the email addresses, session cookies and bearer tokens in s
+func redact(s string) string {
+	s = emailPattern.ReplaceAllString(s, "${1}***@${2}")
+	s = cookiePattern.ReplaceAllString(s, "${1}${2}[redacted]")
+	s = bearerPattern.ReplaceAllString(s, "${1}[redacted]")
+	return sessionPattern.ReplaceAllString(s, "[redacted]")
+}
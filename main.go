@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,20 +12,31 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"linkedin-automation/auth"
+	"linkedin-automation/browse"
+	"linkedin-automation/client"
 	"linkedin-automation/config"
 	"linkedin-automation/connect"
 	"linkedin-automation/logger"
 	"linkedin-automation/message"
+	"linkedin-automation/personalize"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/progress"
+	"linkedin-automation/queue"
+	"linkedin-automation/ratelimit"
 	"linkedin-automation/search"
-	"linkedin-automation/stealth"
 	"linkedin-automation/storage"
+	"linkedin-automation/templates"
 )
 
 var (
 	configFile string
 	verbose    bool
 	headless   bool
+	dryRun     bool
+	captureAll bool
+	jsonOutput bool
+	navigation string
+	commandPath string // Path of the running command below the root, e.g. "connect to-profiles"
 )
 
 func main() {
@@ -38,16 +50,79 @@ func main() {
 	rootCmd.PersistentFlags().StringVar(&configFile, "config", "./config/config.yaml", "Configuration file path")
 	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Enable verbose logging")
 	rootCmd.PersistentFlags().BoolVar(&headless, "headless", true, "Run browser in headless mode")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Go through actions without sending, liking or commenting anything")
+	rootCmd.PersistentFlags().BoolVar(&captureAll, "capture-all", false, "Save a screenshot and HTML snapshot of every navigation and click")
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Print machine-readable JSON on stdout and send logs to stderr")
+	rootCmd.PersistentFlags().StringVar(&navigation, "navigation", "", "How profiles are opened: direct (by URL) or click (search and click through); overrides stealth.navigation")
+
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if navigation != "" && navigation != "direct" && navigation != "click" {
+			return fmt.Errorf("--navigation must be direct or click")
+		}
+		commandPath = strings.TrimPrefix(cmd.CommandPath(), rootCmd.Name()+" ")
+		return nil
+	}
+
+	cobra.OnInitialize(func() {
+		if jsonOutput {
+			// Errors are printed as JSON below, and usage text would corrupt the output
+			rootCmd.SilenceErrors = true
+			rootCmd.SilenceUsage = true
+			logger.InitLogger(logger.Options{Level: "info", Format: "json", Output: "stderr"})
+		}
+	})
 
 	// Add subcommands
 	rootCmd.AddCommand(createSearchCmd())
+	rootCmd.AddCommand(createResolveCmd())
 	rootCmd.AddCommand(createConnectCmd())
+	rootCmd.AddCommand(createConnectionsCmd())
+	rootCmd.AddCommand(createInvitationsCmd())
 	rootCmd.AddCommand(createMessageCmd())
 	rootCmd.AddCommand(createStatusCmd())
-
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	rootCmd.AddCommand(createTemplateCmd())
+	rootCmd.AddCommand(createBlacklistCmd())
+	rootCmd.AddCommand(createTagCmd())
+	rootCmd.AddCommand(createSequenceCmd())
+	rootCmd.AddCommand(createAnalyticsCmd())
+	rootCmd.AddCommand(createAuditCmd())
+	rootCmd.AddCommand(createQueueCmd())
+	rootCmd.AddCommand(createDBCmd())
+	rootCmd.AddCommand(createExportCmd())
+	rootCmd.AddCommand(createSyncCmd())
+	rootCmd.AddCommand(createEnrichCmd())
+	rootCmd.AddCommand(createScoreCmd())
+	rootCmd.AddCommand(createVisitCmd())
+	rootCmd.AddCommand(createEngageCmd())
+	rootCmd.AddCommand(createBrowseCmd())
+	rootCmd.AddCommand(createEndorseCmd())
+	rootCmd.AddCommand(createNurtureCmd())
+	rootCmd.AddCommand(createRemindersCmd())
+	rootCmd.AddCommand(createScrapeCmd())
+	rootCmd.AddCommand(createSelectorsCmd())
+	rootCmd.AddCommand(createStealthCmd())
+	rootCmd.AddCommand(createDoctorCmd())
+	rootCmd.AddCommand(createCampaignCmd())
+	rootCmd.AddCommand(createDashboardCmd())
+	rootCmd.AddCommand(createConfigCmd())
+	rootCmd.AddCommand(createSessionCmd())
+	rootCmd.AddCommand(createRestrictionsCmd())
+	rootCmd.AddCommand(createWarmupCmd())
+	rootCmd.AddCommand(createDataCmd())
+
+	ctx, stop := signalContext()
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	if err != nil {
+		var exit *exitError
+		switch {
+		case !jsonOutput:
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		case !errors.As(err, &exit) || !exit.reported:
+			// A reported outcome is already part of the JSON the command printed
+			printJSON(errorOutput{Error: err.Error()})
+		}
+		os.Exit(exitCode(err))
 	}
 }
 
@@ -55,23 +130,18 @@ func createSearchCmd() *cobra.Command {
 	var cmd = &cobra.Command{
 		Use:   "search",
 		Short: "Search for LinkedIn users",
-		Long:  `Search for LinkedIn users based on keywords, title, company, and location.`,
+		Long:  `Search for LinkedIn users based on keywords, title, company, and location, list group members and event attendees, or find profiles similar to one.`,
 	}
 
 	cmd.AddCommand(createSearchUsersCmd())
+	cmd.AddCommand(createSearchGroupMembersCmd())
+	cmd.AddCommand(createSearchEventAttendeesCmd())
+	cmd.AddCommand(createSearchSimilarCmd())
+	cmd.AddCommand(createSearchSavedCmd())
 	return cmd
 }
 
 func createSearchUsersCmd() *cobra.Command {
-	var (
-		keywords   string
-		title      string
-		company    string
-		location   string
-		maxResults int
-		output     string
-	)
-
 	var cmd = &cobra.Command{
 		Use:   "users",
 		Short: "Search for LinkedIn users by criteria",
@@ -79,16 +149,91 @@ func createSearchUsersCmd() *cobra.Command {
 		RunE:  runSearchUsers,
 	}
 
-	cmd.Flags().StringVar(&keywords, "keywords", "", "Search keywords")
-	cmd.Flags().StringVar(&title, "title", "", "Job title filter")
-	cmd.Flags().StringVar(&company, "company", "", "Company filter")
-	cmd.Flags().StringVar(&location, "location", "", "Location filter")
-	cmd.Flags().IntVar(&maxResults, "max-results", 100, "Maximum number of results")
-	cmd.Flags().StringVar(&output, "output", "", "Output file path")
+	addSearchQueryFlags(cmd)
+	cmd.Flags().String("output", "", "Output file path")
+	cmd.Flags().Bool("exclude-contacted", false, "Drop profiles already sent a connection request or message")
+	cmd.Flags().Int("start-page", 1, "Results page to start from, e.g. the next page a stopped search reported")
+	cmd.Flags().Bool("no-cache", false, "Search LinkedIn even if the same search ran within storage.search_cache_ttl")
+	addQueueFlags(cmd)
 
 	return cmd
 }
 
+// addSearchQueryFlags adds the people search filters
+func addSearchQueryFlags(cmd *cobra.Command) {
+	cmd.Flags().String("keywords", "", "Search keywords; supports AND, OR, NOT, \"quoted phrases\" and parentheses")
+	cmd.Flags().String("query-file", "", "Read the keywords from a file, for long boolean searches")
+	cmd.Flags().String("title", "", "Job title filter")
+	cmd.Flags().String("company", "", "Company filter (name or company ID)")
+	cmd.Flags().String("location", "", "Location filter (geo ID or text)")
+	cmd.Flags().String("degree", "", "Connection degrees, comma-separated (1st,2nd,3rd)")
+	cmd.Flags().String("industry", "", "Industry IDs, comma-separated")
+	cmd.Flags().String("school", "", "School IDs or names, comma-separated")
+	cmd.Flags().String("profile-language", "", "Profile language codes, comma-separated (e.g. en,de)")
+	cmd.Flags().String("past-company", "", "Past company IDs or names, comma-separated")
+	cmd.Flags().Int("min-mutual", 0, "Only results with at least this many mutual connections")
+	cmd.Flags().Bool("open-to-work", false, "Only results showing the open-to-work badge")
+	cmd.Flags().Bool("hiring", false, "Only results showing the hiring badge")
+	cmd.Flags().Bool("premium", false, "Only results with a Premium badge")
+	cmd.Flags().Int("max-results", 100, "Maximum number of results")
+}
+
+// searchQueryFromFlags builds a people search from its filter flags,
+// validating the keywords as a boolean expression
+func searchQueryFromFlags(cmd *cobra.Command) (search.SearchQuery, error) {
+	keywords, _ := cmd.Flags().GetString("keywords")
+	queryFile, _ := cmd.Flags().GetString("query-file")
+	title, _ := cmd.Flags().GetString("title")
+	company, _ := cmd.Flags().GetString("company")
+	location, _ := cmd.Flags().GetString("location")
+	maxResults, _ := cmd.Flags().GetInt("max-results")
+	degree, _ := cmd.Flags().GetString("degree")
+	industry, _ := cmd.Flags().GetString("industry")
+	school, _ := cmd.Flags().GetString("school")
+	language, _ := cmd.Flags().GetString("profile-language")
+	pastCompany, _ := cmd.Flags().GetString("past-company")
+	minMutual, _ := cmd.Flags().GetInt("min-mutual")
+	openToWork, _ := cmd.Flags().GetBool("open-to-work")
+	hiring, _ := cmd.Flags().GetBool("hiring")
+	premium, _ := cmd.Flags().GetBool("premium")
+
+	if minMutual < 0 {
+		return search.SearchQuery{}, fmt.Errorf("--min-mutual must not be negative")
+	}
+	if queryFile != "" {
+		if keywords != "" {
+			return search.SearchQuery{}, fmt.Errorf("--keywords and --query-file cannot be combined")
+		}
+		data, err := os.ReadFile(queryFile)
+		if err != nil {
+			return search.SearchQuery{}, fmt.Errorf("failed to read query file: %w", err)
+		}
+		keywords = string(data)
+	}
+
+	parsed, err := search.ParseKeywords(keywords)
+	if err != nil {
+		return search.SearchQuery{}, err
+	}
+
+	return search.SearchQuery{
+		Keywords:         parsed.Expression,
+		Title:            title,
+		Company:          company,
+		Location:         location,
+		MaxResults:       maxResults,
+		Network:          parseCommaSeparated(degree),
+		Industries:       parseCommaSeparated(industry),
+		Schools:          parseCommaSeparated(school),
+		ProfileLanguages: parseCommaSeparated(language),
+		PastCompanies:    parseCommaSeparated(pastCompany),
+		MinMutual:        minMutual,
+		OpenToWork:       openToWork,
+		Hiring:           hiring,
+		Premium:          premium,
+	}, nil
+}
+
 func createConnectCmd() *cobra.Command {
 	var cmd = &cobra.Command{
 		Use:   "connect",
@@ -97,26 +242,65 @@ func createConnectCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(createConnectToProfilesCmd())
+	cmd.AddCommand(createSyncAcceptedCmd())
 	return cmd
 }
 
 func createConnectToProfilesCmd() *cobra.Command {
 	var (
-		profiles string
-		message  string
-		template string
+		profiles         string
+		message          string
+		template         string
+		batchID          string
+		resume           bool
+		excludeContacted bool
+		variants         string
+		campaign         string
 	)
 
 	var cmd = &cobra.Command{
 		Use:   "to-profiles",
 		Short: "Send connection requests to specific profiles",
-		Long:  `Send connection requests to a list of LinkedIn profile URLs.`,
-		RunE:  runConnectToProfiles,
+		Long: `Send connection requests to a list of LinkedIn profile URLs.
+
+Notes are limited to 200 characters, or 300 with linkedin.premium set for a
+Premium account. A note over the limit before its variables are filled is
+refused; one that personalization takes past it is shortened at a word
+boundary, with a warning. --no-note sends the invitations without opening
+the note field at all.`,
+		RunE: runConnectToProfiles,
 	}
 
 	cmd.Flags().StringVar(&profiles, "profiles", "", "Comma-separated list of profile URLs")
 	cmd.Flags().StringVar(&message, "message", "", "Connection message")
 	cmd.Flags().StringVar(&template, "template", "professional", "Message template")
+	cmd.Flags().Bool("no-note", false, "Send the invitations without a note")
+	cmd.Flags().StringVar(&batchID, "batch-id", "", "Batch identifier for progress tracking (derived from the profile list if empty)")
+	cmd.Flags().BoolVar(&resume, "resume", false, "Skip profiles already processed in a previous run of the same batch")
+	cmd.Flags().BoolVar(&excludeContacted, "exclude-contacted", false, "Skip profiles already sent a connection request or message")
+	cmd.Flags().StringVar(&variants, "variants", "", "Comma-separated connection templates to A/B test; each profile is assigned one at random")
+	cmd.Flags().StringVar(&campaign, "campaign", "", "Campaign name for grouping variant stats (defaults to the batch ID)")
+	cmd.Flags().Int("browse-every", 0, "Browse the feed after about every N requests, at random gaps (0 disables)")
+	cmd.Flags().Duration("browse-duration", 3*time.Minute, "How long each interleaved feed browse lasts")
+	addFromSearchFlags(cmd, "profiles")
+	addInputCSVFlag(cmd, "profiles")
+	addTagFilterFlag(cmd, "profiles")
+	addMinScoreFlag(cmd, "profiles")
+	addBatchOrderFlags(cmd, "profiles")
+	addQueueFlags(cmd)
+	addReviewFlags(cmd)
+	addTabsFlag(cmd)
+
+	return cmd
+}
+
+func createSyncAcceptedCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "sync-accepted",
+		Short: "Mark pending connection requests that have been accepted",
+		Long:  `Check the connections page for profiles with pending requests and mark them accepted, so variant acceptance rates stay current.`,
+		RunE:  runSyncAccepted,
+	}
 
 	return cmd
 }
@@ -129,6 +313,11 @@ func createMessageCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(createSendMessageCmd())
+	cmd.AddCommand(createSyncInboxCmd())
+	cmd.AddCommand(createInMailCmd())
+	cmd.AddCommand(createGroupMessageCmd())
+	cmd.AddCommand(createReplyCmd())
+	cmd.AddCommand(createThreadsCmd())
 	return cmd
 }
 
@@ -137,6 +326,8 @@ func createSendMessageCmd() *cobra.Command {
 		recipients string
 		message   string
 		template  string
+		batchID   string
+		resume    bool
 	)
 
 	var cmd = &cobra.Command{
@@ -149,6 +340,30 @@ func createSendMessageCmd() *cobra.Command {
 	cmd.Flags().StringVar(&recipients, "recipients", "", "Comma-separated list of recipient URLs")
 	cmd.Flags().StringVar(&message, "message", "", "Message content")
 	cmd.Flags().StringVar(&template, "template", "follow_up_professional", "Message template")
+	cmd.Flags().StringVar(&batchID, "batch-id", "", "Batch identifier for progress tracking (derived from the recipient list if empty)")
+	cmd.Flags().BoolVar(&resume, "resume", false, "Skip recipients already processed in a previous run of the same batch")
+	cmd.Flags().Bool("exclude-messaged", false, "Skip recipients already sent a message")
+	addFromSearchFlags(cmd, "recipients")
+	addInputCSVFlag(cmd, "recipients")
+	addTagFilterFlag(cmd, "recipients")
+	addMinScoreFlag(cmd, "recipients")
+	addBatchOrderFlags(cmd, "recipients")
+	addQueueFlags(cmd)
+	addReviewFlags(cmd)
+	addTabsFlag(cmd)
+
+	return cmd
+}
+
+func createSyncInboxCmd() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "sync-inbox",
+		Short: "Fetch replies from the inbox",
+		Long:  `Scan recent conversations, store new incoming messages and stop messaging prospects who replied.`,
+		RunE:  runSyncInbox,
+	}
+
+	cmd.Flags().Int("limit", 20, "Maximum number of recent conversations to scan")
 
 	return cmd
 }
@@ -157,10 +372,16 @@ func createStatusCmd() *cobra.Command {
 	var cmd = &cobra.Command{
 		Use:   "status",
 		Short: "Show status and statistics",
-		Long:  `Display current status, statistics, and configuration information.`,
+		Long:  `Display current status, statistics, and configuration information.
+
+Batches running in other terminals report their progress, which status shows
+under Running Batches; --watch keeps redrawing it until interrupted.`,
 		RunE:  runStatus,
 	}
 
+	cmd.Flags().Bool("watch", false, "Keep showing the progress of running batches")
+	cmd.Flags().Duration("interval", 2*time.Second, "How often --watch refreshes")
+
 	return cmd
 }
 
@@ -172,76 +393,100 @@ func runSearchUsers(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if err := setupLogger(cfg.Logging.Level); err != nil {
+	if err := setupLogger(cfg.Logging); err != nil {
 		return fmt.Errorf("failed to setup logger: %w", err)
 	}
 
 	// Get flags
-	keywords, _ := cmd.Flags().GetString("keywords")
-	title, _ := cmd.Flags().GetString("title")
-	company, _ := cmd.Flags().GetString("company")
-	location, _ := cmd.Flags().GetString("location")
-	maxResults, _ := cmd.Flags().GetInt("maxResults")
 	output, _ := cmd.Flags().GetString("output")
+	excludeContacted, _ := cmd.Flags().GetBool("exclude-contacted")
+	noCache, _ := cmd.Flags().GetBool("no-cache")
 
-	ctx := context.Background()
-	
-	// Initialize auth manager
-	authManager := auth.NewAuthManager(cfg.LinkedIn.Email, cfg.LinkedIn.Password, "./sessions", logger.GetLogger())
-	
-	// Initialize browser
-	if err := authManager.InitializeBrowser(headless, cfg.Browser.UserAgent); err != nil {
-		return fmt.Errorf("failed to initialize browser: %w", err)
-	}
-	defer authManager.Close()
+	ctx := cmd.Context()
 
-	// Authenticate
-	loginResult, err := authManager.Login(ctx)
+	// Initialize database for persistent rate limiting
+	db, err := openStorage(cfg)
 	if err != nil {
-		return fmt.Errorf("authentication failed: %w", err)
-	}
-	if !loginResult.Success {
-		return fmt.Errorf("authentication unsuccessful: %s", loginResult.ErrorMessage)
+		return fmt.Errorf("failed to initialize database: %w", err)
 	}
+	defer db.Close()
 
-	// Get authenticated page
-	page, err := authManager.GetAuthenticatedPage(ctx)
+	// Create search query
+	query, err := searchQueryFromFlags(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to get authenticated page: %w", err)
+		return err
 	}
-	defer page.Close()
-
-	// Initialize stealth manager
-	stealthConfig := convertConfigToStealth(cfg.Stealth)
-	stealthManager := stealth.NewStealthManager(stealthConfig, logger.GetLogger())
-	
-	// Apply stealth
-	if err := stealthManager.ApplyStealth(page); err != nil {
-		logger.GetLogger().WithError(err).Warn("Failed to apply some stealth features")
+	if query.StartPage, _ = cmd.Flags().GetInt("start-page"); query.StartPage < 1 {
+		return fmt.Errorf("--start-page must be at least 1")
 	}
 
-	// Initialize search manager
-	searchManager := search.NewSearchManager(page, logger.GetLogger())
-
-	// Create search query
-	query := search.SearchQuery{
-		Keywords:   keywords,
-		Title:      title,
-		Company:    company,
-		Location:   location,
-		MaxResults: maxResults,
+	queueOpts, enqueue, err := queueOptionsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if enqueue {
+		task, err := queue.Enqueue(db, queue.KindSearch, queue.SearchPayload{
+			Query:            query,
+			Output:           output,
+			ExcludeContacted: excludeContacted,
+		}, queueOpts)
+		if err != nil {
+			return err
+		}
+		if jsonOutput {
+			queued := newQueuedOutput(queueOpts)
+			queued.Queued, queued.TaskIDs = 1, []int{task.ID}
+			return printJSON(queued)
+		}
+		fmt.Printf("Search queued as task %d%s\n", task.ID, scheduledFor(queueOpts))
+		return nil
 	}
 
-	// Perform search
-	session, err := searchManager.SearchUsers(ctx, query)
+	found, err := newClient(cfg, db).Search(ctx, query, client.SearchOptions{
+		ExcludeContacted: excludeContacted,
+		NoCache:          noCache,
+	})
 	if err != nil {
-		return fmt.Errorf("search failed: %w", err)
+		return err
+	}
+	session, contactedCount := found.SearchSession, found.Contacted
+
+	if jsonOutput {
+		out := newSearchOutput(session, contactedCount, excludeContacted)
+		out.CachedAt = found.CachedAt
+		if output != "" {
+			if err := saveSearchResults(session, output); err != nil {
+				return fmt.Errorf("failed to save results: %w", err)
+			}
+			out.Output = output
+		}
+		if err := printJSON(out); err != nil {
+			return err
+		}
+		return searchExit(session)
 	}
 
 	// Output results
 	fmt.Printf("Search completed successfully!\n")
+	if found.CachedAt != nil {
+		fmt.Printf("Cached results of the same search at %s; --no-cache searches again\n", found.CachedAt.Local().Format("2006-01-02 15:04"))
+	}
 	fmt.Printf("Found %d results in %v\n", len(session.Results), session.Duration)
 	fmt.Printf("Unique profiles: %d\n", len(session.Profiles))
+	if excludeContacted {
+		fmt.Printf("Excluded (already contacted): %d\n", contactedCount)
+	} else if contactedCount > 0 {
+		fmt.Printf("Already contacted: %d\n", contactedCount)
+	}
+	if session.Filtered > 0 {
+		fmt.Printf("Left out by the degree, mutual connection and badge filters: %d\n", session.Filtered)
+	}
+	if session.StoppedAtLimit {
+		fmt.Printf("Stopped at search rate limit; results may be incomplete\n")
+	}
+	if session.NextPage > 0 {
+		fmt.Printf("More results from page %d on; continue with --start-page %d\n", session.NextPage, session.NextPage)
+	}
 
 	if output != "" {
 		if err := saveSearchResults(session, output); err != nil {
@@ -254,10 +499,13 @@ func runSearchUsers(cmd *cobra.Command, args []string) error {
 		for i, result := range session.Results {
 			fmt.Printf("%d. %s - %s\n", i+1, result.Name, result.Title)
 			fmt.Printf("   %s\n", result.ProfileURL)
+			if details := resultDetails(result); details != "" {
+				fmt.Printf("   %s\n", details)
+			}
 		}
 	}
 
-	return nil
+	return searchExit(session)
 }
 
 func runConnectToProfiles(cmd *cobra.Command, args []string) error {
@@ -266,100 +514,407 @@ func runConnectToProfiles(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if err := setupLogger(cfg.Logging.Level); err != nil {
+	if err := setupLogger(cfg.Logging); err != nil {
 		return fmt.Errorf("failed to setup logger: %w", err)
 	}
 
 	// Get flags
 	profiles, _ := cmd.Flags().GetString("profiles")
+	excludeContacted, _ := cmd.Flags().GetBool("exclude-contacted")
 	message, _ := cmd.Flags().GetString("message")
 	template, _ := cmd.Flags().GetString("template")
-
-	ctx := context.Background()
-	
-	// Initialize auth
-	authManager := auth.NewAuthManager(cfg.LinkedIn.Email, cfg.LinkedIn.Password, "./sessions", logger.GetLogger())
-	
-	if err := authManager.InitializeBrowser(headless, cfg.Browser.UserAgent); err != nil {
-		return fmt.Errorf("failed to initialize browser: %w", err)
+	batchID, _ := cmd.Flags().GetString("batch-id")
+	resume, _ := cmd.Flags().GetBool("resume")
+	variantNames, _ := cmd.Flags().GetString("variants")
+	campaign, _ := cmd.Flags().GetString("campaign")
+	tags, _ := cmd.Flags().GetStringArray("tag")
+	browseEvery, _ := cmd.Flags().GetInt("browse-every")
+	browseDuration, _ := cmd.Flags().GetDuration("browse-duration")
+	noNote, _ := cmd.Flags().GetBool("no-note")
+
+	if noNote && (message != "" || variantNames != "" || cmd.Flags().Changed("template")) {
+		return fmt.Errorf("--no-note cannot be combined with --message, --template or --variants")
 	}
-	defer authManager.Close()
-
-	loginResult, err := authManager.Login(ctx)
+	if browseEvery < 0 {
+		return fmt.Errorf("--browse-every must not be negative")
+	}
+	if limit, _ := cmd.Flags().GetInt("limit"); limit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+	searched, err := profilesFromSearch(cmd)
 	if err != nil {
-		return fmt.Errorf("authentication failed: %w", err)
+		return err
 	}
-	if !loginResult.Success {
-		return fmt.Errorf("authentication unsuccessful: %s", loginResult.ErrorMessage)
+	csvFile, err := inputCSV(cmd)
+	if err != nil {
+		return err
+	}
+	if csvFile != nil {
+		searched = append(searched, csvFile.Profiles...)
 	}
 
-	page, err := authManager.GetAuthenticatedPage(ctx)
+	reviewer, err := reviewerFromFlags(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to get authenticated page: %w", err)
+		return err
 	}
-	defer page.Close()
-
-	// Initialize stealth
-	stealthConfig := convertConfigToStealth(cfg.Stealth)
-	stealthManager := stealth.NewStealthManager(stealthConfig, logger.GetLogger())
-	
-	if err := stealthManager.ApplyStealth(page); err != nil {
-		logger.GetLogger().WithError(err).Warn("Failed to apply some stealth features")
+	tabs, err := tabsFromFlags(cmd)
+	if err != nil {
+		return err
 	}
 
-	// Initialize connect manager
-	connectManager := connect.NewConnectManager(page, logger.GetLogger(), stealthManager)
+	ctx := cmd.Context()
+
+	// Initialize database for batch progress tracking and rate limiting
+	db, err := openStorage(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer db.Close()
 
 	// Parse profiles
-	profileList := parseCommaSeparated(profiles)
+	profileList, err := applyTagFilter(db, profileurl.Dedupe(append(parseCommaSeparated(profiles), searched...)), tags)
+	if err != nil {
+		return err
+	}
 	if len(profileList) == 0 {
+		if len(tags) > 0 {
+			return fmt.Errorf("no profiles with tags %s", strings.Join(tags, ", "))
+		}
 		return fmt.Errorf("no profiles provided")
 	}
 
+	excludedCount := 0
+	if excludeContacted {
+		contacted, err := db.GetContactedProfiles()
+		if err != nil {
+			return fmt.Errorf("failed to load contacted profiles: %w", err)
+		}
+		remaining := make([]string, 0, len(profileList))
+		for _, profileURL := range profileList {
+			if contacted[profileURL] {
+				excludedCount++
+				continue
+			}
+			remaining = append(remaining, profileURL)
+		}
+		profileList = remaining
+		if len(profileList) == 0 {
+			if jsonOutput {
+				out := newBatchOutput(batchID, 0)
+				out.Counts["excluded"] = excludedCount
+				return printJSON(out)
+			}
+			fmt.Printf("All %d profiles have already been contacted\n", excludedCount)
+			return nil
+		}
+	}
+
+	profileList, belowScoreCount, err := applyMinScore(cmd, cfg, db, profileList)
+	if err != nil {
+		return err
+	}
+	if len(profileList) == 0 {
+		return fmt.Errorf("no profiles meet the minimum score")
+	}
+	profileList = applyLimit(cmd, profileList)
+
 	// Get message template
 	connectionMessage := message
-	if connectionMessage == "" {
-		templates := connect.GetDefaultTemplates()
-		for _, t := range templates {
-			if t.ID == template {
-				connectionMessage = t.Content
-				break
+	templateName := ""
+	variableNames := personalize.Placeholders(connectionMessage)
+	if connectionMessage == "" && variantNames == "" && !noNote {
+		t, err := templates.NewManager(db, logger.GetLogger()).Get(templates.KindConnection, template)
+		if err != nil {
+			return fmt.Errorf("failed to load template: %w", err)
+		}
+		connectionMessage, templateName, variableNames = t.Content, template, t.Variables
+	}
+	if err := personalize.Validate(connectionMessage); err != nil {
+		return err
+	}
+
+	var variants []connect.Variant
+	if variantNames != "" {
+		if message != "" {
+			return fmt.Errorf("--variants cannot be combined with --message")
+		}
+		variants, err = newClient(cfg, db).Variants(parseCommaSeparated(variantNames))
+		if err != nil {
+			return err
+		}
+	}
+
+	openerTexts := []string{connectionMessage}
+	for _, variant := range variants {
+		openerTexts = append(openerTexts, variant.Content)
+		variableNames = append(variableNames, personalize.Placeholders(variant.Content)...)
+	}
+	if err := newClient(cfg, db).CheckNotes(openerTexts...); err != nil {
+		return err
+	}
+	if err := csvFile.Check(profileList, variableNames); err != nil {
+		return err
+	}
+	recordOpenerPreview, err := checkAIOpenerPreview(cfg, db, reviewer != nil, openerTexts...)
+	if err != nil {
+		return err
+	}
+
+	if batchID == "" {
+		batchID = client.DeriveBatchID("connect", profileList)
+	}
+	if campaign == "" && len(variants) > 0 {
+		campaign = batchID
+	}
+
+	queueOpts, enqueue, err := queueOptionsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if enqueue {
+		queued := newQueuedOutput(queueOpts)
+		for _, profileURL := range profileList {
+			payload := queue.ConnectPayload{
+				ProfileURL: profileURL,
+				Message:    connectionMessage,
+				Campaign:   campaign,
+				Template:   templateName,
+				Variables:  csvFile.Of(profileURL),
+			}
+			// Assign the variant now so the split is fixed when the task is queued
+			if len(variants) > 0 {
+				variant := connect.PickVariant(variants)
+				payload.Message, payload.Variant = variant.Content, variant.Name
+			}
+			task, err := queue.Enqueue(db, queue.KindConnect, payload, queueOpts)
+			if err != nil {
+				return err
 			}
+			queued.TaskIDs = append(queued.TaskIDs, task.ID)
 		}
-		if connectionMessage == "" {
-			connectionMessage = "Hi, I'd like to connect with you on LinkedIn."
+		if jsonOutput {
+			queued.Queued = len(queued.TaskIDs)
+			return printJSON(queued)
+		}
+		fmt.Printf("Queued %d connection requests%s\n", len(profileList), scheduledFor(queueOpts))
+		return nil
+	}
+
+	// Left out after the batch ID is derived, so --resume later finds the same batch
+	profileList, outsideHoursCount, err := applyProspectHours(cfg, db, profileList)
+	if err != nil {
+		return err
+	}
+	if len(profileList) == 0 {
+		if jsonOutput {
+			out := newBatchOutput(batchID, 0)
+			out.Counts["outside_hours"] = outsideHoursCount
+			return printJSON(out)
 		}
+		fmt.Printf("All %d profiles are outside their working hours; re-run later with --resume\n", outsideHoursCount)
+		return nil
+	}
+	profileList, randomSkipCount, err := applyBatchOrder(cmd, profileList)
+	if err != nil {
+		return err
+	}
+
+	ctx, tracker := startProgress(ctx, cfg, batchID, "connect", len(profileList))
+	defer tracker.Finish()
+
+	opts := client.ConnectOptions{
+		NoNote:   noNote,
+		Message:  connectionMessage,
+		Template: templateName,
+		Variants: variants,
+		Campaign: campaign,
+		BatchID:  batchID,
+		Resume:   resume,
+		Tabs:     tabs,
+		Progress: tracker,
+
+		Variables: csvFile.For(profileList),
+
+		BrowseEvery: browseEvery,
+		Browse: browse.Options{
+			Duration:         browseDuration,
+			ReactProbability: defaultReactProbability,
+			HoverProbability: defaultHoverProbability,
+		},
+	}
+	if reviewer != nil {
+		opts.Reviewer = reviewer
 	}
 
 	// Send connection requests
-	results, err := connectManager.BatchSendConnectionRequests(ctx, profileList, connectionMessage)
+	batch, err := newClient(cfg, db).Connect(ctx, profileList, opts)
 	if err != nil {
-		return fmt.Errorf("batch connection failed: %w", err)
+		return err
+	}
+	recordOpenerPreview()
+
+	if jsonOutput {
+		out := newConnectOutput(batchID, len(profileList), batch)
+		out.Campaign = campaign
+		if excludeContacted {
+			out.Counts["excluded"] = excludedCount
+		}
+		if cmd.Flags().Changed("min-score") {
+			out.Counts["below_min_score"] = belowScoreCount
+		}
+		if outsideHoursCount > 0 {
+			out.Counts["outside_hours"] = outsideHoursCount
+		}
+		if randomSkipCount > 0 {
+			out.Counts["skipped_at_random"] = randomSkipCount
+		}
+		if err := printJSON(out); err != nil {
+			return err
+		}
+		return batchExit("connection requests", out.Counts["failed"], len(batch.Results), batch.StopErr)
 	}
 
+	reportDryRunConnections(batch.Results)
+
 	// Report results
 	successCount := 0
-	for _, result := range results {
-		if result.Success {
+	skippedCount := 0
+	emailRequiredCount := 0
+	rejectedCount := 0
+	blacklistedCount := 0
+	for _, result := range batch.Results {
+		if result.EmailRequired {
+			emailRequiredCount++
+		} else if result.Blacklisted {
+			blacklistedCount++
+		} else if result.Rejected {
+			rejectedCount++
+		} else if result.Skipped {
+			skippedCount++
+		} else if result.Success {
 			successCount++
 		}
 	}
 
 	fmt.Printf("Connection requests completed!\n")
+	fmt.Printf("Batch ID: %s\n", batchID)
+	if len(variants) > 0 {
+		fmt.Printf("Campaign: %s (%d variants)\n", campaign, len(variants))
+	}
 	fmt.Printf("Total profiles: %d\n", len(profileList))
 	fmt.Printf("Successful: %d\n", successCount)
-	fmt.Printf("Failed: %d\n", len(results)-successCount)
+	fmt.Printf("Skipped (already processed): %d\n", skippedCount)
+	fmt.Printf("Skipped (email required): %d\n", emailRequiredCount)
+	fmt.Printf("Skipped (blacklisted): %d\n", blacklistedCount)
+	if reviewer != nil {
+		fmt.Printf("Skipped (in review): %d\n", rejectedCount)
+	}
+	if excludeContacted {
+		fmt.Printf("Excluded (already contacted): %d\n", excludedCount)
+	}
+	if cmd.Flags().Changed("min-score") {
+		fmt.Printf("Excluded (below minimum score): %d\n", belowScoreCount)
+	}
+	if outsideHoursCount > 0 {
+		fmt.Printf("Deferred (outside working hours): %d (re-run later with --resume)\n", outsideHoursCount)
+	}
+	if randomSkipCount > 0 {
+		fmt.Printf("Deferred (skipped at random): %d (re-run later with --resume)\n", randomSkipCount)
+	}
+	failedCount := len(batch.Results)-successCount-skippedCount-emailRequiredCount-rejectedCount-blacklistedCount
+	fmt.Printf("Failed: %d\n", failedCount)
+	if batch.StoppedAtLimit {
+		fmt.Printf("Stopped at limit: %s\n", batch.StopReason)
+		fmt.Printf("Not attempted: %d (re-run with --resume once the limit resets)\n", len(profileList)-len(batch.Results))
+	}
+	if batch.Interrupted {
+		printCheckpoint(cmd, batchID, len(batch.Results), len(profileList))
+	}
+
+	return batchExit("connection requests", failedCount, len(batch.Results), batch.StopErr)
+}
+
+func runSyncAccepted(cmd *cobra.Command, args []string) error {
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	pending, err := db.GetPendingConnectionRequests()
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		fmt.Printf("No pending connection requests\n")
+		return nil
+	}
+
+	ctx := cmd.Context()
+
+	browser, err := openBrowserSession(ctx, cfg, db)
+	if err != nil {
+		return err
+	}
+	defer browser.Close()
+
+	acceptedCount, err := syncAcceptedConnections(ctx, newMessageManager(cfg, browser, db), db, pending)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Accepted connections synced!\n")
+	fmt.Printf("Pending requests: %d\n", len(pending))
+	fmt.Printf("Newly accepted: %d\n", acceptedCount)
+
+	// Post-acceptance hook: push the new connections straight to the CRM
+	if cfg.Integrations.AutoSync && acceptedCount > 0 {
+		syncer, err := newCRMSyncer(cfg, db, "")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\n")
+		return runCRMSync(ctx, syncer)
+	}
 
 	return nil
 }
 
+// syncAcceptedConnections marks the pending requests that appear among the
+// connections made since the oldest of them as accepted and returns how many were
+func syncAcceptedConnections(ctx context.Context, messageManager *message.MessageManager, db *storage.Database, pending []*storage.ConnectionRequest) (int, error) {
+	since := pending[0].SentAt
+	for _, request := range pending {
+		if request.SentAt.Before(since) {
+			since = request.SentAt
+		}
+	}
+
+	connections, err := messageManager.GetNewlyAcceptedConnections(ctx, since)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get connections: %w", err)
+	}
+
+	acceptedCount := 0
+	for _, connectionURL := range connections {
+		accepted, err := db.MarkConnectionAccepted(connectionURL)
+		if err != nil {
+			return acceptedCount, err
+		}
+		if accepted {
+			acceptedCount++
+		}
+	}
+	return acceptedCount, nil
+}
+
 func runSendMessage(cmd *cobra.Command, args []string) error {
 	cfg, err := config.LoadConfig(configFile)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if err := setupLogger(cfg.Logging.Level); err != nil {
+	if err := setupLogger(cfg.Logging); err != nil {
 		return fmt.Errorf("failed to setup logger: %w", err)
 	}
 
@@ -367,81 +922,305 @@ func runSendMessage(cmd *cobra.Command, args []string) error {
 	recipients, _ := cmd.Flags().GetString("recipients")
 	messageText, _ := cmd.Flags().GetString("message")
 	template, _ := cmd.Flags().GetString("template")
+	batchID, _ := cmd.Flags().GetString("batch-id")
+	resume, _ := cmd.Flags().GetBool("resume")
+	tags, _ := cmd.Flags().GetStringArray("tag")
+	excludeMessaged, _ := cmd.Flags().GetBool("exclude-messaged")
 
-	ctx := context.Background()
-	
-	// Initialize auth
-	authManager := auth.NewAuthManager(cfg.LinkedIn.Email, cfg.LinkedIn.Password, "./sessions", logger.GetLogger())
-	
-	if err := authManager.InitializeBrowser(headless, cfg.Browser.UserAgent); err != nil {
-		return fmt.Errorf("failed to initialize browser: %w", err)
+	if limit, _ := cmd.Flags().GetInt("limit"); limit < 0 {
+		return fmt.Errorf("--limit must not be negative")
 	}
-	defer authManager.Close()
-
-	loginResult, err := authManager.Login(ctx)
+	searched, err := profilesFromSearch(cmd)
+	if err != nil {
+		return err
+	}
+	csvFile, err := inputCSV(cmd)
 	if err != nil {
-		return fmt.Errorf("authentication failed: %w", err)
+		return err
 	}
-	if !loginResult.Success {
-		return fmt.Errorf("authentication unsuccessful: %s", loginResult.ErrorMessage)
+	if csvFile != nil {
+		searched = append(searched, csvFile.Profiles...)
 	}
 
-	page, err := authManager.GetAuthenticatedPage(ctx)
+	reviewer, err := reviewerFromFlags(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to get authenticated page: %w", err)
+		return err
 	}
-	defer page.Close()
-
-	// Initialize stealth
-	stealthConfig := convertConfigToStealth(cfg.Stealth)
-	stealthManager := stealth.NewStealthManager(stealthConfig, logger.GetLogger())
-	
-	if err := stealthManager.ApplyStealth(page); err != nil {
-		logger.GetLogger().WithError(err).Warn("Failed to apply some stealth features")
+	tabs, err := tabsFromFlags(cmd)
+	if err != nil {
+		return err
 	}
 
-	// Initialize message manager
-	messageManager := message.NewMessageManager(page, logger.GetLogger(), stealthManager)
+	ctx := cmd.Context()
+
+	// Initialize database for batch progress tracking and rate limiting
+	db, err := openStorage(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer db.Close()
 
 	// Parse recipients
-	recipientList := parseCommaSeparated(recipients)
+	recipientList, err := applyTagFilter(db, profileurl.Dedupe(append(parseCommaSeparated(recipients), searched...)), tags)
+	if err != nil {
+		return err
+	}
 	if len(recipientList) == 0 {
+		if len(tags) > 0 {
+			return fmt.Errorf("no recipients with tags %s", strings.Join(tags, ", "))
+		}
 		return fmt.Errorf("no recipients provided")
 	}
 
+	recipientList, belowScoreCount, err := applyMinScore(cmd, cfg, db, recipientList)
+	if err != nil {
+		return err
+	}
+	if len(recipientList) == 0 {
+		return fmt.Errorf("no recipients meet the minimum score")
+	}
+
+	excludedCount := 0
+	if excludeMessaged {
+		messaged, err := db.GetMessagedProfiles()
+		if err != nil {
+			return err
+		}
+		remaining := make([]string, 0, len(recipientList))
+		for _, recipientURL := range recipientList {
+			if messaged[profileurl.Canonicalize(recipientURL)] {
+				excludedCount++
+				continue
+			}
+			remaining = append(remaining, recipientURL)
+		}
+		recipientList = remaining
+		if len(recipientList) == 0 {
+			if jsonOutput {
+				out := newBatchOutput(batchID, 0)
+				out.Counts["excluded"] = excludedCount
+				return printJSON(out)
+			}
+			fmt.Printf("All %d recipients have already been sent a message\n", excludedCount)
+			return nil
+		}
+	}
+	recipientList = applyLimit(cmd, recipientList)
+
 	// Get message template
 	messageContent := messageText
+	templateName := ""
+	variableNames := personalize.Placeholders(messageContent)
 	if messageContent == "" {
-		templates := message.GetDefaultMessageTemplates()
-		for _, t := range templates {
-			if t.ID == template {
-				messageContent = t.Content
-				break
+		t, err := templates.NewManager(db, logger.GetLogger()).Get(templates.KindMessage, template)
+		if err != nil {
+			return fmt.Errorf("failed to load template: %w", err)
+		}
+		messageContent, templateName, variableNames = t.Content, template, t.Variables
+	}
+	if err := personalize.Validate(messageContent); err != nil {
+		return err
+	}
+	if err := csvFile.Check(recipientList, variableNames); err != nil {
+		return err
+	}
+
+	queueOpts, enqueue, err := queueOptionsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if enqueue {
+		queued := newQueuedOutput(queueOpts)
+		for _, recipientURL := range recipientList {
+			task, err := queue.Enqueue(db, queue.KindMessage, queue.MessagePayload{
+				RecipientURL: recipientURL,
+				Content:      messageContent,
+				Template:     templateName,
+				Variables:    csvFile.Of(recipientURL),
+			}, queueOpts)
+			if err != nil {
+				return err
 			}
+			queued.TaskIDs = append(queued.TaskIDs, task.ID)
+		}
+		if jsonOutput {
+			queued.Queued = len(queued.TaskIDs)
+			return printJSON(queued)
 		}
-		if messageContent == "" {
-			messageContent = "Hi, thanks for connecting!"
+		fmt.Printf("Queued %d messages%s\n", len(recipientList), scheduledFor(queueOpts))
+		return nil
+	}
+
+	recordOpenerPreview, err := checkAIOpenerPreview(cfg, db, reviewer != nil, messageContent)
+	if err != nil {
+		return err
+	}
+
+	if batchID == "" {
+		batchID = client.DeriveBatchID("message", recipientList)
+	}
+
+	recipientList, outsideHoursCount, err := applyProspectHours(cfg, db, recipientList)
+	if err != nil {
+		return err
+	}
+	if len(recipientList) == 0 {
+		if jsonOutput {
+			out := newBatchOutput(batchID, 0)
+			out.Counts["outside_hours"] = outsideHoursCount
+			return printJSON(out)
 		}
+		fmt.Printf("All %d recipients are outside their working hours; re-run later with --resume\n", outsideHoursCount)
+		return nil
+	}
+	recipientList, randomSkipCount, err := applyBatchOrder(cmd, recipientList)
+	if err != nil {
+		return err
+	}
+
+	ctx, tracker := startProgress(ctx, cfg, batchID, "message", len(recipientList))
+	defer tracker.Finish()
+
+	opts := client.MessageOptions{
+		Message:  messageContent,
+		Template: templateName,
+		BatchID:  batchID,
+		Resume:   resume,
+		Tabs:     tabs,
+		Progress: tracker,
+
+		Variables: csvFile.For(recipientList),
+	}
+	if reviewer != nil {
+		opts.Reviewer = reviewer
 	}
 
 	// Send messages
-	results, err := messageManager.BatchSendMessages(ctx, recipientList, messageContent)
+	batch, err := newClient(cfg, db).Message(ctx, recipientList, opts)
 	if err != nil {
-		return fmt.Errorf("batch messaging failed: %w", err)
+		return err
 	}
+	recordOpenerPreview()
+
+	if jsonOutput {
+		out := newMessageOutput(batchID, len(recipientList), batch)
+		if excludeMessaged {
+			out.Counts["excluded"] = excludedCount
+		}
+		if cmd.Flags().Changed("min-score") {
+			out.Counts["below_min_score"] = belowScoreCount
+		}
+		if outsideHoursCount > 0 {
+			out.Counts["outside_hours"] = outsideHoursCount
+		}
+		if randomSkipCount > 0 {
+			out.Counts["skipped_at_random"] = randomSkipCount
+		}
+		if err := printJSON(out); err != nil {
+			return err
+		}
+		return batchExit("messages", out.Counts["failed"], len(batch.Results), batch.StopErr)
+	}
+
+	reportDryRunMessages(batch.Results)
 
 	// Report results
 	successCount := 0
-	for _, result := range results {
-		if result.Success {
+	skippedCount := 0
+	repliedCount := 0
+	rejectedCount := 0
+	blacklistedCount := 0
+	for _, result := range batch.Results {
+		if result.Replied {
+			repliedCount++
+		} else if result.Blacklisted {
+			blacklistedCount++
+		} else if result.Rejected {
+			rejectedCount++
+		} else if result.Skipped {
+			skippedCount++
+		} else if result.Success {
 			successCount++
 		}
 	}
 
 	fmt.Printf("Messages sent successfully!\n")
+	fmt.Printf("Batch ID: %s\n", batchID)
 	fmt.Printf("Total recipients: %d\n", len(recipientList))
 	fmt.Printf("Successful: %d\n", successCount)
-	fmt.Printf("Failed: %d\n", len(results)-successCount)
+	fmt.Printf("Skipped (already processed): %d\n", skippedCount)
+	fmt.Printf("Skipped (replied): %d\n", repliedCount)
+	fmt.Printf("Skipped (blacklisted): %d\n", blacklistedCount)
+	if reviewer != nil {
+		fmt.Printf("Skipped (in review): %d\n", rejectedCount)
+	}
+	if excludeMessaged {
+		fmt.Printf("Excluded (already messaged): %d\n", excludedCount)
+	}
+	if cmd.Flags().Changed("min-score") {
+		fmt.Printf("Excluded (below minimum score): %d\n", belowScoreCount)
+	}
+	if outsideHoursCount > 0 {
+		fmt.Printf("Deferred (outside working hours): %d (re-run later with --resume)\n", outsideHoursCount)
+	}
+	if randomSkipCount > 0 {
+		fmt.Printf("Deferred (skipped at random): %d (re-run later with --resume)\n", randomSkipCount)
+	}
+	failedCount := len(batch.Results)-successCount-skippedCount-repliedCount-rejectedCount-blacklistedCount
+	fmt.Printf("Failed: %d\n", failedCount)
+	if batch.StoppedAtLimit {
+		fmt.Printf("Stopped at limit: %s\n", batch.StopReason)
+		fmt.Printf("Not attempted: %d (re-run with --resume once the limit resets)\n", len(recipientList)-len(batch.Results))
+	}
+	if batch.Interrupted {
+		printCheckpoint(cmd, batchID, len(batch.Results), len(recipientList))
+	}
+
+	return batchExit("messages", failedCount, len(batch.Results), batch.StopErr)
+}
+
+func runSyncInbox(cmd *cobra.Command, args []string) error {
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	cfg, db, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := cmd.Context()
+
+	browser, err := openBrowserSession(ctx, cfg, db)
+	if err != nil {
+		return err
+	}
+	defer browser.Close()
+
+	result, err := newMessageManager(cfg, browser, db).SyncInbox(ctx, limit)
+	if err != nil {
+		return fmt.Errorf("inbox sync failed: %w", err)
+	}
+
+	fmt.Printf("Inbox synced!\n")
+	fmt.Printf("Conversations scanned: %d\n", result.ThreadsScanned)
+	fmt.Printf("Unchanged since last sync: %d\n", result.ThreadsUnchanged)
+	fmt.Printf("New messages: %d\n", len(result.NewMessages))
+	fmt.Printf("Prospects who replied: %d\n", len(result.RepliedProfiles))
+	fmt.Printf("Opted out (blacklisted): %d\n", len(result.OptedOut))
+	for _, profileURL := range result.OptedOut {
+		fmt.Printf("  %s\n", profileURL)
+	}
+	fmt.Printf("Replies classified: %d\n", len(result.Labels))
+	for _, profileURL := range result.RepliedProfiles {
+		if label, ok := result.Labels[profileURL]; ok {
+			fmt.Printf("  %s: %s\n", profileURL, label)
+		}
+	}
+
+	for _, received := range result.NewMessages {
+		fmt.Printf("\n%s (%s) %s\n", received.SenderName, received.SenderURL, received.SentLabel)
+		fmt.Printf("  %s\n", received.Content)
+	}
 
 	return nil
 }
@@ -452,110 +1231,253 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if err := setupLogger(cfg.Logging.Level); err != nil {
+	if err := setupLogger(cfg.Logging); err != nil {
 		return fmt.Errorf("failed to setup logger: %w", err)
 	}
 
+	if watch, _ := cmd.Flags().GetBool("watch"); watch {
+		if jsonOutput {
+			return fmt.Errorf("--watch cannot be combined with --json")
+		}
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if interval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+		return watchBatches(cmd.Context(), cfg.Storage.ProgressDir, interval)
+	}
+
 	// Initialize database
-	db, err := storage.NewDatabase(cfg.Storage.Path, logger.GetLogger())
+	db, err := openStorage(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to initialize database: %w", err)
 	}
 	defer db.Close()
 
-	// Get daily stats
-	stats, err := db.GetDailyStats(time.Now())
+	status, err := collectStatus(cfg, db)
 	if err != nil {
-		return fmt.Errorf("failed to get daily stats: %w", err)
+		return err
+	}
+	if jsonOutput {
+		return printJSON(status)
 	}
 
 	// Display status
 	fmt.Printf("LinkedIn Automation Status\n")
 	fmt.Printf("========================\n\n")
 	fmt.Printf("Configuration:\n")
-	fmt.Printf("  Config file: %s\n", configFile)
-	fmt.Printf("  Headless: %v\n", headless)
-	fmt.Printf("  LinkedIn email: %s\n", maskEmail(cfg.LinkedIn.Email))
+	fmt.Printf("  Config file: %s\n", status.ConfigFile)
+	fmt.Printf("  Headless: %v\n", status.Headless)
+	fmt.Printf("  LinkedIn email: %s\n", status.LinkedInEmail)
+	fmt.Printf("\n")
+	if status.Restricted != nil {
+		fmt.Printf("RESTRICTED: %s (noticed %s)\n", status.Restricted.Reason, status.Restricted.DetectedAt.Local().Format("2006-01-02 15:04"))
+		fmt.Printf("  Automation is paused until %s; 'restrictions clear' ends this early\n", status.Restricted.CooldownUntil.Local().Format("2006-01-02 15:04"))
+		fmt.Printf("\n")
+	}
+	if err := printRunningBatches(cfg.Storage.ProgressDir); err != nil {
+		return err
+	}
 	fmt.Printf("\n")
 	fmt.Printf("Daily Statistics:\n")
-	fmt.Printf("  Connections sent: %d\n", stats["connections_sent"])
-	fmt.Printf("  Connections accepted: %d\n", stats["connections_accepted"])
-	fmt.Printf("  Messages sent: %d\n", stats["messages_sent"])
+	fmt.Printf("  Connections sent: %d\n", status.Daily["connections_sent"])
+	fmt.Printf("  Connections accepted: %d\n", status.Daily["connections_accepted"])
+	fmt.Printf("  Messages sent: %d\n", status.Daily["messages_sent"])
 	fmt.Printf("\n")
 	fmt.Printf("Limits:\n")
-	fmt.Printf("  Daily connections: %d/%d\n", stats["connections_sent"], cfg.Limits.DailyConnections)
-	fmt.Printf("  Daily messages: %d/%d\n", stats["messages_sent"], cfg.Limits.DailyMessages)
+	fmt.Printf("  Daily connections: %d/%d\n", status.Daily["connections_sent"], status.Limits["daily_connections"])
+	fmt.Printf("  Daily messages: %d/%d\n", status.Daily["messages_sent"], status.Limits["daily_messages"])
+	fmt.Printf("\n")
+	if status.Warmup != nil && status.Warmup.CompletedAt == nil {
+		fmt.Printf("Account Warm-up:\n")
+		printWarmupStatus(status.Warmup)
+		fmt.Printf("\n")
+	}
+
+	// Sliding-window usage shared by all commands
+	fmt.Printf("Rate Limit Usage:\n")
+	for _, usage := range status.RateLimits {
+		if usage.HourlyLimit == 0 {
+			fmt.Printf("  %s: %d/%d (last 24h), %d (last hour)\n", usage.Action, usage.Daily, usage.DailyLimit, usage.Hourly)
+			continue
+		}
+		fmt.Printf("  %s: %d/%d (last 24h), %d/%d (last hour)\n", usage.Action, usage.Daily, usage.DailyLimit, usage.Hourly, usage.HourlyLimit)
+	}
+	if status.InvitesBlocked != nil {
+		fmt.Printf("  LinkedIn weekly invitation limit: blocked until %s\n", status.InvitesBlocked.Local().Format("2006-01-02 15:04"))
+	}
+	if status.InMailCredits != nil {
+		fmt.Printf("  InMail credits: %d (as of %s)\n", status.InMailCredits.Credits, status.InMailCredits.CheckedAt.Local().Format("2006-01-02 15:04"))
+	}
+	fmt.Printf("\n")
+
+	if status.WeeklyInvites != nil {
+		fmt.Printf("Weekly Invitation Budget:\n")
+		printInviteBudget(status.WeeklyInvites)
+		fmt.Printf("\n")
+	}
+
+	fmt.Printf("Queue:\n")
+	for _, taskStatus := range []string{storage.TaskPending, storage.TaskRunning, storage.TaskDone, storage.TaskFailed, storage.TaskCancelled} {
+		fmt.Printf("  %s: %d\n", taskStatus, status.Queue[taskStatus])
+	}
+	fmt.Printf("\n")
+
+	fmt.Printf("Inbox:\n")
+	fmt.Printf("  Replies received (last 24h): %d\n", status.Inbox.RepliesLastDay)
+	fmt.Printf("  Prospects who replied: %d\n", status.Inbox.Replied)
+
+	if len(status.Variants) > 0 {
+		fmt.Printf("\n")
+		fmt.Printf("A/B Variants:\n")
+		for _, vs := range status.Variants {
+			fmt.Printf("  %s / %s: %d sent, %d accepted (%.1f%%)\n", vs.Campaign, vs.Variant, vs.Sent, vs.Accepted, vs.AcceptanceRate())
+		}
+	}
 
 	return nil
 }
 
+// collectStatus gathers what the status command reports
+func collectStatus(cfg *config.Config, db *storage.Database) (*statusOutput, error) {
+	now := time.Now()
+	quotas, warmupProgress, err := currentQuotas(cfg, db, now)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &statusOutput{
+		ConfigFile:    configFile,
+		Headless:      headless,
+		LinkedInEmail: maskEmail(cfg.LinkedIn.Email),
+		Limits: map[string]int{
+			"daily_connections": quotas.DailyConnects,
+			"daily_messages":    quotas.DailyMessages,
+		},
+		Warmup: warmupProgress,
+	}
+
+	states, err := progress.List(cfg.Storage.ProgressDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch progress: %w", err)
+	}
+	status.RunningBatches = append([]*progress.State{}, states...)
+
+	// Get daily stats
+	if status.Daily, err = db.GetDailyStats(time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to get daily stats: %w", err)
+	}
+
+	for _, usage := range actionLimits(quotas) {
+		daily, err := db.CountRateLimitEvents(string(usage.action), now.Add(-24*time.Hour))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rate limit usage: %w", err)
+		}
+		hourly, err := db.CountRateLimitEvents(string(usage.action), now.Add(-time.Hour))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rate limit usage: %w", err)
+		}
+		status.RateLimits = append(status.RateLimits, rateLimitUsage{
+			Action:      string(usage.action),
+			Daily:       daily,
+			DailyLimit:  usage.daily,
+			Hourly:      hourly,
+			HourlyLimit: usage.hourly,
+		})
+	}
+	inviteBlock, err := db.GetActionBlock(string(ratelimit.ActionConnect))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitation block: %w", err)
+	}
+	if now.Before(inviteBlock) {
+		status.InvitesBlocked = &inviteBlock
+	}
+	if status.Restricted, err = db.GetActiveRestriction(cfg.Account, now); err != nil {
+		return nil, err
+	}
+	if status.WeeklyInvites, err = inviteBudget(cfg, db, now); err != nil {
+		return nil, fmt.Errorf("failed to plan the weekly invitation budget: %w", err)
+	}
+	if credits, checkedAt, err := db.GetInMailCredits(); err != nil {
+		return nil, fmt.Errorf("failed to get InMail credits: %w", err)
+	} else if credits >= 0 {
+		status.InMailCredits = &inMailCredits{Credits: credits, CheckedAt: checkedAt}
+	}
+
+	if status.Queue, err = db.CountTasksByStatus(); err != nil {
+		return nil, fmt.Errorf("failed to get queue status: %w", err)
+	}
+
+	recentReplies, err := db.GetReceivedMessages(now.Add(-24 * time.Hour))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inbox status: %w", err)
+	}
+	replied, err := db.GetRepliedProfiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inbox status: %w", err)
+	}
+	status.Inbox = inboxStatus{RepliesLastDay: len(recentReplies), Replied: len(replied)}
+
+	variantStats, err := db.GetVariantStats("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variant stats: %w", err)
+	}
+	status.Variants = append([]*storage.VariantStats{}, variantStats...)
+
+	return status, nil
+}
+
 // Helper functions
 
-func setupLogger(level string) error {
+// actionLimit is the daily and hourly limit of one action; hourly is 0 when
+// the action has no hourly limit
+type actionLimit struct {
+	action ratelimit.ActionType
+	daily  int
+	hourly int
+}
+
+// actionLimits lists the rate limited actions with their limits in rlConfig
+func actionLimits(rlConfig ratelimit.Config) []actionLimit {
+	return []actionLimit{
+		{ratelimit.ActionSearch, rlConfig.DailySearches, rlConfig.HourlySearches},
+		{ratelimit.ActionConnect, rlConfig.DailyConnects, rlConfig.HourlyConnects},
+		{ratelimit.ActionMessage, rlConfig.DailyMessages, rlConfig.HourlyMessages},
+		{ratelimit.ActionVisit, rlConfig.DailyVisits, rlConfig.HourlyVisits},
+		{ratelimit.ActionLike, rlConfig.DailyLikes, 0},
+		{ratelimit.ActionComment, rlConfig.DailyComments, 0},
+		{ratelimit.ActionEndorse, rlConfig.DailyEndorsements, 0},
+	}
+}
+
+func setupLogger(logging config.LoggingConfig) error {
 	logLevel := "info"
 	if verbose {
 		logLevel = "debug"
 	}
-	if level != "" {
-		logLevel = level
+	if logging.Level != "" {
+		logLevel = logging.Level
 	}
 
-	return logger.InitLogger(logLevel, "json", "stdout", 100, 3, 28)
-}
-
-func convertConfigToStealth(cfg config.StealthConfig) stealth.StealthConfig {
-	return stealth.StealthConfig{
-		MouseMovement: stealth.MouseMovementConfig{
-			BezierCurves:     cfg.MouseMovement.BezierCurves,
-			VariableSpeed:    cfg.MouseMovement.VariableSpeed,
-			Overshoot:        cfg.MouseMovement.Overshoot,
-			MicroCorrections: cfg.MouseMovement.MicroCorrections,
-			MinSpeed:         cfg.MouseMovement.MinSpeed,
-			MaxSpeed:         cfg.MouseMovement.MaxSpeed,
-			IdleMovements:    cfg.MouseMovement.IdleMovements,
-			IdleProbability:  cfg.MouseMovement.IdleProbability,
-		},
-		Timing: stealth.TimingConfig{
-			MinDelay:    cfg.Timing.MinDelay,
-			MaxDelay:    cfg.Timing.MaxDelay,
-			ThinkTime:   cfg.Timing.ThinkTime,
-			ScrollDelay: cfg.Timing.ScrollDelay,
-			ClickDelay:  cfg.Timing.ClickDelay,
-			TypeDelay:   cfg.Timing.TypeDelay,
-		},
-		Typing: stealth.TypingConfig{
-			VariableSpeed:   cfg.Typing.VariableSpeed,
-			TypoRate:       cfg.Typing.TypoRate,
-			CorrectionDelay: cfg.Typing.CorrectionDelay,
-			MinSpeed:       cfg.Typing.MinSpeed,
-			MaxSpeed:       cfg.Typing.MaxSpeed,
-		},
-		Scrolling: stealth.ScrollingConfig{
-			VariableSpeed: cfg.Scrolling.VariableSpeed,
-			Acceleration:  cfg.Scrolling.Acceleration,
-			Deceleration:  cfg.Scrolling.Deceleration,
-			ScrollBack:    cfg.Scrolling.ScrollBack,
-			MinSpeed:      cfg.Scrolling.MinSpeed,
-			MaxSpeed:      cfg.Scrolling.MaxSpeed,
-		},
-		Schedule: stealth.ScheduleConfig{
-			BusinessHoursOnly: cfg.Schedule.BusinessHoursOnly,
-			StartHour:        cfg.Schedule.StartHour,
-			EndHour:          cfg.Schedule.EndHour,
-			BreakDuration:    cfg.Schedule.BreakDuration,
-			BreakFrequency:   cfg.Schedule.BreakFrequency,
-			Timezone:         cfg.Schedule.Timezone,
-		},
-		Fingerprint: stealth.FingerprintConfig{
-			RandomUserAgent:    cfg.Fingerprint.RandomUserAgent,
-			RandomViewport:     cfg.Fingerprint.RandomViewport,
-			MinViewportWidth:   cfg.Fingerprint.MinViewportWidth,
-			MaxViewportWidth:   cfg.Fingerprint.MaxViewportWidth,
-			MinViewportHeight:  cfg.Fingerprint.MinViewportHeight,
-			MaxViewportHeight:  cfg.Fingerprint.MaxViewportHeight,
-			UserAgents:         cfg.Fingerprint.UserAgents,
-		},
+	// With --json, stdout carries the command's output
+	output := logging.Output
+	if output == "" || (jsonOutput && output == "stdout") {
+		output = "stdout"
+		if jsonOutput {
+			output = "stderr"
+		}
 	}
+
+	return logger.InitLogger(logger.Options{
+		Level:      logLevel,
+		Format:     logging.Format,
+		Output:     output,
+		MaxSize:    logging.MaxSize,
+		MaxBackups: logging.MaxBackups,
+		MaxAge:     logging.MaxAge,
+		ErrorFile:  logging.ErrorFile,
+		Modules:    logging.Modules,
+		Reveal:     logging.RevealSensitive,
+	})
 }
 
 func parseCommaSeparated(input string) []string {
@@ -0,0 +1,368 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/pause"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/selectors"
+	"linkedin-automation/storage"
+)
+
+// InboxStore persists messages received from prospects
+type InboxStore interface {
+	SaveReceivedMessage(message *storage.ReceivedMessage) (bool, error)
+	GetInboxThreadSnippet(threadID string) (string, error)
+	SaveInboxThread(threadID, participantURL, snippet string) error
+	HasReplied(profileURL string) (bool, error)
+}
+
+// OptOutHandler acts on a reply in which a prospect asks not to be contacted again
+type OptOutHandler interface {
+	OptOut(received *storage.ReceivedMessage, phrase string) error
+}
+
+// ReplyClassifier labels a new reply, returning an empty label for one it
+// cannot place
+type ReplyClassifier interface {
+	Classify(ctx context.Context, received *storage.ReceivedMessage) (string, error)
+}
+
+// InboxSyncResult summarizes an inbox sync
+type InboxSyncResult struct {
+	ThreadsScanned   int
+	ThreadsUnchanged int                        // Threads skipped because nothing changed since the last sync
+	NewMessages      []*storage.ReceivedMessage // Incoming messages not seen before
+	RepliedProfiles  []string                   // Prospects with new replies
+	OptedOut         []string                   // Prospects whose new replies contain an opt-out phrase
+	Labels           map[string]string          // Label of each prospect's latest classified new reply
+}
+
+// Thread is a conversation as listed in the messaging sidebar
+type Thread struct {
+	ID              string
+	URL             string
+	ParticipantName string   // Names as listed, e.g. "Ann Lee, Bo Chen and 2 others" for groups
+	Participants    []string // Individual names, where the listing spells them out
+	Group           bool     // More than one other participant
+	Snippet         string
+}
+
+var threadIDPattern = regexp.MustCompile(`/messaging/thread/([^/?#]+)`)
+
+// SetInboxStore enables inbox syncing and skipping recipients who have already replied
+func (m *MessageManager) SetInboxStore(store InboxStore) {
+	m.inboxStore = store
+}
+
+// SetOptOut makes inbox syncs hand new replies containing any of phrases to
+// handler. Phrases match case-insensitively on word boundaries.
+func (m *MessageManager) SetOptOut(phrases []string, handler OptOutHandler) {
+	m.optOutPhrases = phrases
+	m.optOut = handler
+}
+
+// SetReplyClassifier makes inbox syncs label new replies
+func (m *MessageManager) SetReplyClassifier(classifier ReplyClassifier) {
+	m.classifier = classifier
+}
+
+// SyncInbox scans up to limit recent conversations, stores incoming messages
+// not seen before and reports which prospects replied
+func (m *MessageManager) SyncInbox(ctx context.Context, limit int) (result *InboxSyncResult, err error) {
+	if m.inboxStore == nil {
+		return nil, fmt.Errorf("inbox store not configured")
+	}
+
+	m.logger.WithField("limit", limit).Info("Syncing inbox")
+	defer m.withContext(ctx)()
+	defer func() { m.captureFailure("sync inbox", err) }()
+
+	if err := m.navigateToMessaging(); err != nil {
+		return nil, fmt.Errorf("failed to navigate to messaging: %w", err)
+	}
+
+	if err := m.waitForConversationsList(); err != nil {
+		return nil, fmt.Errorf("failed to wait for conversations list: %w", err)
+	}
+
+	threads := m.extractInboxThreads()
+	if limit > 0 && len(threads) > limit {
+		threads = threads[:limit]
+	}
+
+	result = &InboxSyncResult{Labels: make(map[string]string)}
+	replied := make(map[string]bool)
+
+	for i, thread := range threads {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		result.ThreadsScanned++
+
+		lastSnippet, err := m.inboxStore.GetInboxThreadSnippet(thread.ID)
+		if err != nil {
+			return result, err
+		}
+		if lastSnippet != "" && lastSnippet == thread.Snippet {
+			result.ThreadsUnchanged++
+			continue
+		}
+
+		// Opening a thread marks it as read; don't do that the moment a message arrives
+		if err := pause.Sleep(ctx, m.stealth.ReadDelay()); err != nil {
+			return result, err
+		}
+
+		messages, participantURL, err := m.syncThread(thread)
+		if err != nil {
+			m.logger.WithError(err).WithField("thread_id", thread.ID).Warn("Failed to sync thread")
+			continue
+		}
+
+		for _, message := range messages {
+			saved, err := m.inboxStore.SaveReceivedMessage(message)
+			if err != nil {
+				return result, err
+			}
+			if !saved {
+				continue
+			}
+			result.NewMessages = append(result.NewMessages, message)
+			if message.SenderURL != "" && !replied[message.SenderURL] {
+				replied[message.SenderURL] = true
+				result.RepliedProfiles = append(result.RepliedProfiles, message.SenderURL)
+			}
+			if m.checkOptOut(message) {
+				result.OptedOut = append(result.OptedOut, message.SenderURL)
+			} else if label := m.classify(ctx, message); label != "" {
+				result.Labels[message.SenderURL] = label
+			}
+		}
+
+		if err := m.inboxStore.SaveInboxThread(thread.ID, participantURL, thread.Snippet); err != nil {
+			return result, err
+		}
+
+		if i < len(threads)-1 {
+			if err := pause.Sleep(ctx, m.stealth.RandomDelay()); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"threads":      result.ThreadsScanned,
+		"unchanged":    result.ThreadsUnchanged,
+		"new_messages": len(result.NewMessages),
+		"replied":      len(result.RepliedProfiles),
+		"opted_out":    len(result.OptedOut),
+		"classified":   len(result.Labels),
+	}).Info("Inbox sync completed")
+
+	return result, nil
+}
+
+// checkOptOut hands message to the opt-out handler if it contains an opt-out
+// phrase, reporting whether the sender was opted out
+func (m *MessageManager) checkOptOut(message *storage.ReceivedMessage) bool {
+	if m.optOut == nil || message.SenderURL == "" {
+		return false
+	}
+
+	phrase := MatchOptOut(message.Content, m.optOutPhrases)
+	if phrase == "" {
+		return false
+	}
+	if err := m.optOut.OptOut(message, phrase); err != nil {
+		m.logger.WithError(err).WithField("sender", message.SenderURL).Warn("Failed to opt out prospect")
+		return false
+	}
+	return true
+}
+
+// classify hands message to the reply classifier, returning its label
+func (m *MessageManager) classify(ctx context.Context, message *storage.ReceivedMessage) string {
+	if m.classifier == nil || message.SenderURL == "" {
+		return ""
+	}
+
+	label, err := m.classifier.Classify(ctx, message)
+	if err != nil {
+		m.logger.WithError(err).WithField("sender", message.SenderURL).Warn("Failed to classify reply")
+		return ""
+	}
+	return label
+}
+
+// MatchOptOut returns the first of phrases that content contains as whole
+// words, ignoring case, punctuation and the kind of apostrophe used, or an
+// empty string if it contains none
+func MatchOptOut(content string, phrases []string) string {
+	text := " " + optOutWords(content) + " "
+	for _, phrase := range phrases {
+		words := optOutWords(phrase)
+		if words != "" && strings.Contains(text, " "+words+" ") {
+			return phrase
+		}
+	}
+	return ""
+}
+
+// optOutWords lower-cases s and reduces it to words separated by single spaces
+func optOutWords(s string) string {
+	s = strings.NewReplacer("’", "'", "‘", "'").Replace(strings.ToLower(s))
+	return strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	}), " ")
+}
+
+// hasReplied reports whether a recipient has already replied to us
+func (m *MessageManager) hasReplied(recipientURL string) bool {
+	if m.inboxStore == nil {
+		return false
+	}
+
+	replied, err := m.inboxStore.HasReplied(recipientURL)
+	if err != nil {
+		m.logger.WithError(err).Warn("Failed to check for replies")
+		return false
+	}
+	return replied
+}
+
+func (m *MessageManager) extractInboxThreads() []*Thread {
+	elements := selectors.FindAll(m.page, selectors.MessagingConversation)
+
+	threads := make([]*Thread, 0, len(elements))
+	seen := make(map[string]bool)
+	for _, element := range elements {
+		conversation, err := m.extractConversationData(element)
+		if err != nil {
+			continue
+		}
+
+		// The list item's link points at the thread, not the participant
+		match := threadIDPattern.FindStringSubmatch(conversation.ParticipantURL)
+		if match == nil || seen[match[1]] {
+			continue
+		}
+		seen[match[1]] = true
+
+		participants, group := splitParticipants(conversation.ParticipantName)
+		threads = append(threads, &Thread{
+			ID:              match[1],
+			URL:             ThreadURL(match[1]),
+			ParticipantName: conversation.ParticipantName,
+			Participants:    participants,
+			Group:           group || selectors.FindIn(element, selectors.MessagingGroupFacepile) != nil,
+			Snippet:         conversation.LastMessage,
+		})
+	}
+
+	return threads
+}
+
+// syncThread opens a thread and returns the messages sent by the other participant
+func (m *MessageManager) syncThread(thread *Thread) ([]*storage.ReceivedMessage, string, error) {
+	if err := m.page.Navigate(thread.URL); err != nil {
+		return nil, "", fmt.Errorf("failed to open thread: %w", err)
+	}
+	if err := m.page.WaitLoad(); err != nil {
+		return nil, "", fmt.Errorf("failed to wait for thread load: %w", err)
+	}
+
+	events := m.waitForMessageEvents()
+	participantURL := m.extractThreadParticipant()
+
+	messages := make([]*storage.ReceivedMessage, 0)
+	var senderURL, senderName, sentLabel string
+	var incoming bool
+
+	for _, event := range events {
+		// Consecutive messages from one sender share a group header; later
+		// events in the group omit it and inherit the previous sender
+		if link := selectors.FindIn(event, selectors.MessagingGroupProfileLink); link != nil {
+			if href, err := link.Attribute("href"); err == nil && href != nil {
+				senderURL = profileurl.Canonicalize(absoluteURL(*href))
+			}
+			senderName = selectors.TextIn(event, selectors.MessagingGroupName)
+			incoming = participantURL != "" && profileurl.Equal(senderURL, participantURL)
+		}
+		if label := selectors.TextIn(event, selectors.MessagingGroupTimestamp); label != "" {
+			sentLabel = label
+		}
+
+		for _, item := range selectors.FindAllIn(event, selectors.MessagingEventItem) {
+			class, _ := item.Attribute("class")
+			itemIncoming := incoming
+			if class != nil && strings.Contains(*class, "msg-s-event-listitem--other") {
+				itemIncoming = true
+			}
+			if !itemIncoming {
+				continue
+			}
+
+			body := selectors.TextIn(item, selectors.MessagingEventBody)
+			if body == "" {
+				continue
+			}
+
+			message := &storage.ReceivedMessage{
+				ThreadID:   thread.ID,
+				SenderURL:  senderURL,
+				SenderName: senderName,
+				Content:    body,
+				SentLabel:  sentLabel,
+			}
+			if message.SenderURL == "" {
+				message.SenderURL = participantURL
+			}
+			if message.SenderName == "" {
+				message.SenderName = thread.ParticipantName
+			}
+			messages = append(messages, message)
+		}
+	}
+
+	return messages, participantURL, nil
+}
+
+func (m *MessageManager) waitForMessageEvents() []*rod.Element {
+	var events []*rod.Element
+	pause.Until(m.page, pause.Current().ElementTimeout, func() bool {
+		events = selectors.FindAll(m.page, selectors.MessagingEvent)
+		return len(events) > 0
+	})
+	return events
+}
+
+func (m *MessageManager) extractThreadParticipant() string {
+	for _, selector := range selectors.Get(selectors.MessagingThreadParticipant) {
+		elements, err := m.page.Elements(selector)
+		if err != nil || len(elements) == 0 {
+			continue
+		}
+		href, err := elements[0].Attribute("href")
+		if err == nil && href != nil && profileurl.Slug(*href) != "" {
+			return profileurl.Canonicalize(absoluteURL(*href))
+		}
+	}
+
+	return ""
+}
+
+func absoluteURL(href string) string {
+	if strings.HasPrefix(href, "/") {
+		return "https://www.linkedin.com" + href
+	}
+	return href
+}
@@ -0,0 +1,188 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/errs"
+	"linkedin-automation/pause"
+	"linkedin-automation/selectors"
+)
+
+// CreditStore records the InMail credit balance shown in the compose form
+type CreditStore interface {
+	RecordInMailCredits(remaining int) error
+}
+
+// InMailResult represents the result of sending an InMail
+type InMailResult struct {
+	MessageResult
+	Subject          string
+	CreditsRemaining int // Credits left after sending; -1 if LinkedIn did not show them
+}
+
+var creditsPattern = regexp.MustCompile(`(\d+)\s*(?:InMail\s+)?credits?`)
+
+// SetCreditStore enables storing the InMail credit balance after each InMail
+func (m *MessageManager) SetCreditStore(store CreditStore) {
+	m.creditStore = store
+}
+
+// SendInMail sends an InMail to a profile the account is not connected to.
+// This needs a Premium or Sales Navigator account with credits left.
+func (m *MessageManager) SendInMail(ctx context.Context, profileURL, subject, body string) (result *InMailResult, err error) {
+	m.logger.WithFields(logrus.Fields{
+		"recipient_url":  profileURL,
+		"content_length": len(body),
+	}).Info("Sending InMail")
+	defer m.withContext(ctx)()
+	defer func() { m.captureFailure("send inmail", err) }()
+
+	result = &InMailResult{
+		MessageResult: MessageResult{
+			RecipientURL: profileURL,
+			SentAt:       time.Now(),
+		},
+		CreditsRemaining: -1,
+	}
+
+	if blocked, err := m.checkBlacklist(ctx, profileURL, &result.MessageResult); blocked || err != nil {
+		return result, err
+	}
+
+	if m.personalizer != nil {
+		subject = m.personalizer.Personalize(ctx, m.page, profileURL, subject)
+		body = m.personalizer.Personalize(ctx, m.page, profileURL, body)
+	}
+	result.Subject = subject
+
+	body, send, err := m.review(profileURL, body)
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		return result, err
+	}
+	if !send {
+		m.logger.Info("InMail skipped in review")
+		result.Skipped = true
+		result.Rejected = true
+		result.ErrorMessage = "Skipped in review"
+		return result, nil
+	}
+	result.Content = body
+
+	if err := m.openInMailForm(profileURL); err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to open InMail form: %v", err)
+		return result, err
+	}
+
+	credits := m.readInMailCredits()
+	if credits == 0 {
+		m.recordInMailCredits(credits)
+		result.CreditsRemaining = 0
+		result.ErrorMessage = "No InMail credits left"
+		return result, errs.ErrNoInMailCredits
+	}
+
+	if !m.dryRun {
+		if err := m.typeInMailSubject(subject); err != nil {
+			result.ErrorMessage = fmt.Sprintf("Failed to enter subject: %v", err)
+			return result, err
+		}
+	}
+
+	if err := m.sendDirectMessage(body); err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to send InMail: %v", err)
+		return result, err
+	}
+
+	if m.dryRun {
+		result.CreditsRemaining = credits
+		result.Success = true
+		result.DryRun = true
+		return result, nil
+	}
+
+	// The balance in the form updates once the InMail is sent
+	pause.Settle(m.page)
+	if after := m.readInMailCredits(); after >= 0 {
+		result.CreditsRemaining = after
+	} else if credits > 0 {
+		result.CreditsRemaining = credits - 1
+	}
+	m.recordInMailCredits(result.CreditsRemaining)
+
+	result.Success = true
+	m.logger.WithField("credits_remaining", result.CreditsRemaining).Info("InMail sent successfully")
+
+	return result, nil
+}
+
+// openInMailForm opens the InMail compose form from a profile's Message button
+func (m *MessageManager) openInMailForm(profileURL string) error {
+	if err := m.openProfile(profileURL); err != nil {
+		return fmt.Errorf("failed to navigate to profile: %w", err)
+	}
+	if err := m.page.WaitLoad(); err != nil {
+		return fmt.Errorf("failed to wait for profile load: %w", err)
+	}
+	if info, err := m.page.Info(); err == nil {
+		if err := errs.CheckPageURL(info.URL); err != nil {
+			return err
+		}
+	}
+
+	messageButton, err := selectors.Wait(m.page, selectors.ProfileMessageButton)
+	if err != nil {
+		return fmt.Errorf("no message button on profile; InMail may not be available for this account")
+	}
+	if err := m.stealth.ReadProfile(m.page); err != nil {
+		return err
+	}
+	if err := m.clickMessageButton(messageButton); err != nil {
+		return fmt.Errorf("failed to click message button: %w", err)
+	}
+
+	// Connections get the regular message overlay, which has no subject line
+	if _, err := selectors.Wait(m.page, selectors.InMailSubjectInput); err != nil {
+		return fmt.Errorf("%w: InMail subject field not found (already connected? use message send)", errs.ErrDialogNotFound)
+	}
+	return nil
+}
+
+func (m *MessageManager) typeInMailSubject(subject string) error {
+	input, _ := selectors.Find(m.page, selectors.InMailSubjectInput)
+	if input == nil {
+		return fmt.Errorf("%w: InMail subject field not found", errs.ErrDialogNotFound)
+	}
+	if err := m.stealth.HumanClick(m.page, input); err != nil {
+		return fmt.Errorf("failed to click subject field: %w", err)
+	}
+	return m.stealth.HumanLikeType(m.page, subject)
+}
+
+// readInMailCredits returns the credit balance shown in the form, or -1 if it is not shown
+func (m *MessageManager) readInMailCredits() int {
+	match := creditsPattern.FindStringSubmatch(selectors.Text(m.page, selectors.InMailCredits))
+	if match == nil {
+		return -1
+	}
+	credits, err := strconv.Atoi(match[1])
+	if err != nil {
+		return -1
+	}
+	return credits
+}
+
+func (m *MessageManager) recordInMailCredits(remaining int) {
+	if m.creditStore == nil || remaining < 0 {
+		return
+	}
+	if err := m.creditStore.RecordInMailCredits(remaining); err != nil {
+		m.logger.WithError(err).Warn("Failed to store InMail credits")
+	}
+}
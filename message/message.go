@@ -2,6 +2,7 @@ package message
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -9,13 +10,35 @@ import (
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/input"
 	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/errs"
+	"linkedin-automation/pause"
+	"linkedin-automation/personalize"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/retry"
+	"linkedin-automation/selectors"
+	"linkedin-automation/storage"
 )
 
 // MessageManager handles LinkedIn messaging
 type MessageManager struct {
-	page      *rod.Page
-	logger    *logrus.Logger
-	stealth   StealthManager
+	page       *rod.Page
+	logger     *logrus.Logger
+	stealth      StealthManager
+	batchStore   BatchStore
+	rateLimiter  RateLimiter
+	personalizer Personalizer
+	inboxStore   InboxStore
+	retryPolicy  retry.Policy
+	creditStore  CreditStore
+	dryRun       bool
+	reviewer     Reviewer
+	blacklist    Blacklist
+	optOut       OptOutHandler
+	optOutPhrases []string
+	classifier   ReplyClassifier
+	capturer     Capturer
+	navigator    Navigator
 }
 
 // StealthManager interface for stealth operations
@@ -25,6 +48,62 @@ type StealthManager interface {
 	HumanLikeType(page *rod.Page, text string) error
 	HumanLikeScroll(page *rod.Page, scrollAmount int) error
 	AddIdleMovement(page *rod.Page) error
+	ReviewDelay(text string) time.Duration
+	ReadDelay() time.Duration
+	ReadProfile(page *rod.Page) error
+	HumanClick(page *rod.Page, element *rod.Element) error
+}
+
+// Reviewer lets a person approve, edit or skip each personalized message
+// before it is sent. It returns the message to send and whether to send it at all.
+type Reviewer interface {
+	Review(recipientURL, content string) (string, bool, error)
+}
+
+// Blacklist tells why a profile must not be contacted, or returns an empty
+// string if it may be
+type Blacklist interface {
+	Blocked(ctx context.Context, page *rod.Page, profileURL string) (string, error)
+}
+
+// BatchStore persists per-recipient batch progress so interrupted runs can be resumed
+type BatchStore interface {
+	IsBatchItemCompleted(batchID, itemURL string) (bool, error)
+	RecordBatchItem(batchID, action, itemURL, status, errorMessage string) error
+}
+
+// RateLimiter gates actions against configured quotas
+type RateLimiter interface {
+	WaitForPermission(ctx context.Context, action ratelimit.ActionType) error
+}
+
+// Personalizer fills template variables for a specific profile
+type Personalizer interface {
+	Personalize(ctx context.Context, page *rod.Page, profileURL, content string) string
+}
+
+// Navigator opens profiles through in-page clicks rather than by URL
+type Navigator interface {
+	OpenProfile(page *rod.Page, profileURL string) error
+}
+
+// Capturer saves the page for debugging when an action fails
+type Capturer interface {
+	Failure(page *rod.Page, action string, err error)
+}
+
+// BatchOptions controls how a batch run is tracked
+type BatchOptions struct {
+	BatchID        string   // Identifier used to record progress in storage
+	Resume         bool     // Skip recipients already completed under the same batch ID
+	IncludeReplied bool     // Message recipients who have already replied, e.g. to congratulate existing connections
+	Progress       Progress // Told about each recipient as the batch works through it; may be nil
+}
+
+// Progress follows a running batch, e.g. so it can be watched from another terminal
+type Progress interface {
+	Processing(recipientURL string)
+	Processed(success, skipped bool)
 }
 
 // Message represents a LinkedIn message
@@ -43,6 +122,23 @@ type MessageResult struct {
 	ErrorMessage string
 	MessageID   string
 	SentAt      time.Time
+	Skipped     bool
+	Replied     bool   // Skipped because the recipient has already replied
+	Content     string // The message sent after personalization
+	Err         error  // Cause of a failed attempt, classified by package errs
+	ThreadID    string // Conversation the message went into, when known
+	DryRun      bool   // The message was ready to send but not sent, because this is a dry run
+	Rejected    bool   // Skipped because the reviewer declined the message
+	Blacklisted bool   // Skipped because the recipient is on the blacklist
+}
+
+// BatchResult represents the outcome of a batch of messages
+type BatchResult struct {
+	Results        []*MessageResult
+	StoppedAtLimit bool   // The batch ended early because a quota was exhausted
+	StopReason     string
+	StopErr        error  // The limit error that ended the batch
+	Interrupted    bool   // The batch ended early because ctx was cancelled, e.g. by Ctrl-C
 }
 
 // MessageTemplate represents a message template
@@ -67,32 +163,101 @@ type Conversation struct {
 // NewMessageManager creates a new message manager
 func NewMessageManager(page *rod.Page, logger *logrus.Logger, stealth StealthManager) *MessageManager {
 	return &MessageManager{
-		page:    page,
-		logger:  logger,
-		stealth: stealth,
+		page:        page,
+		logger:      logger,
+		stealth:     stealth,
+		retryPolicy: retry.DefaultPolicy(),
 	}
 }
 
+// SetBatchStore enables per-recipient progress tracking for batch operations
+func (m *MessageManager) SetBatchStore(store BatchStore) {
+	m.batchStore = store
+}
+
+// SetRateLimiter enables quota enforcement for batch operations
+func (m *MessageManager) SetRateLimiter(limiter RateLimiter) {
+	m.rateLimiter = limiter
+}
+
+// SetRetryPolicy controls how batch operations retry transient failures
+func (m *MessageManager) SetRetryPolicy(policy retry.Policy) {
+	m.retryPolicy = policy
+}
+
+// SetDryRun makes sends open the conversation and find the compose box but
+// stop short of typing and sending
+func (m *MessageManager) SetDryRun(dryRun bool) {
+	m.dryRun = dryRun
+}
+
+// SetReviewer asks reviewer to approve every personalized message before it is sent
+func (m *MessageManager) SetReviewer(reviewer Reviewer) {
+	m.reviewer = reviewer
+}
+
+// SetBlacklist makes every message check the do-not-contact list first
+func (m *MessageManager) SetBlacklist(blacklist Blacklist) {
+	m.blacklist = blacklist
+}
+
+// SetPersonalizer enables filling template variables from profile data before sending
+func (m *MessageManager) SetPersonalizer(personalizer Personalizer) {
+	m.personalizer = personalizer
+}
+
+// SetNavigator makes recipients' profiles open through navigator instead of by URL
+func (m *MessageManager) SetNavigator(navigator Navigator) {
+	m.navigator = navigator
+}
+
+// SetCapturer saves a screenshot and HTML snapshot of the page whenever
+// sending or syncing fails
+func (m *MessageManager) SetCapturer(capturer Capturer) {
+	m.capturer = capturer
+}
+
 // SendMessage sends a message to a LinkedIn user
-func (m *MessageManager) SendMessage(ctx context.Context, recipientURL, content string) (*MessageResult, error) {
+func (m *MessageManager) SendMessage(ctx context.Context, recipientURL, content string) (result *MessageResult, err error) {
 	m.logger.WithFields(logrus.Fields{
 		"recipient_url": recipientURL,
 		"content_length": len(content),
 	}).Info("Sending message")
+	defer m.withContext(ctx)()
+	defer func() { m.captureFailure("send message", err) }()
 
-	result := &MessageResult{
+	result = &MessageResult{
 		RecipientURL: recipientURL,
 		SentAt:       time.Now(),
 	}
 
-	// Navigate to messaging
-	if err := m.navigateToMessaging(); err != nil {
-		result.ErrorMessage = fmt.Sprintf("Failed to navigate to messaging: %v", err)
+	if blocked, err := m.checkBlacklist(ctx, recipientURL, result); blocked || err != nil {
 		return result, err
 	}
 
-	// Find or start conversation with recipient
-	if err := m.navigateToMessaging(); err != nil {
+	// Fill template variables from stored profile data
+	if m.personalizer != nil {
+		content = m.personalizer.Personalize(ctx, m.page, recipientURL, content)
+	}
+	result.Content = content
+
+	reviewed, send, err := m.review(recipientURL, content)
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		return result, err
+	}
+	if !send {
+		m.logger.Info("Message skipped in review")
+		result.Skipped = true
+		result.Rejected = true
+		result.ErrorMessage = "Skipped in review"
+		return result, nil
+	}
+	content = reviewed
+	result.Content = content
+
+	// Open the conversation from the recipient's profile
+	if err := m.openConversation(recipientURL); err != nil {
 		result.ErrorMessage = fmt.Sprintf("Failed to find/start conversation: %v", err)
 		return result, err
 	}
@@ -104,6 +269,7 @@ func (m *MessageManager) SendMessage(ctx context.Context, recipientURL, content
 	}
 
 	result.Success = true
+	result.DryRun = m.dryRun
 	m.logger.Info("Message sent successfully")
 
 	return result, nil
@@ -130,6 +296,7 @@ func (m *MessageManager) SendMessageWithTemplate(ctx context.Context, recipientU
 // SendFollowUpMessage sends a follow-up message to newly accepted connections
 func (m *MessageManager) SendFollowUpMessage(ctx context.Context, recipientURL, templateContent string, variables map[string]string) (*MessageResult, error) {
 	m.logger.WithField("recipient_url", recipientURL).Info("Sending follow-up message")
+	defer m.withContext(ctx)()
 
 	// Process template
 	content := m.processTemplate(templateContent, variables)
@@ -139,37 +306,12 @@ func (m *MessageManager) SendFollowUpMessage(ctx context.Context, recipientURL,
 		SentAt:       time.Now(),
 	}
 
-	// Navigate to recipient's profile first
-	if err := m.page.Navigate(recipientURL); err != nil {
-		result.ErrorMessage = fmt.Sprintf("Failed to navigate to profile: %v", err)
-		return result, err
-	}
-
-	// Wait for profile to load
-	if err := m.page.WaitLoad(); err != nil {
-		result.ErrorMessage = fmt.Sprintf("Failed to wait for profile load: %w", err)
-		return result, err
-	}
-
-	// Look for message button on profile
-	messageButton, err := m.page.Element("button[aria-label*='Message']")
-	if err != nil {
-		messageButton, err = m.page.Element(".pvs-profile-actions__action")
-	}
-	if err != nil {
-		result.ErrorMessage = fmt.Sprintf("Failed to find message button: %v", err)
-		return result, err
-	}
-
-	// Click message button
-	if err := m.clickMessageButton(messageButton); err != nil {
-		result.ErrorMessage = fmt.Sprintf("Failed to click message button: %v", err)
+	// Open the conversation from the recipient's profile
+	if err := m.openConversation(recipientURL); err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to open conversation: %v", err)
 		return result, err
 	}
 
-	// Wait for messaging interface to load
-	time.Sleep(m.stealth.RandomDelay())
-
 	// Send the message
 	if err := m.sendDirectMessage(content); err != nil {
 		result.ErrorMessage = fmt.Sprintf("Failed to send follow-up message: %v", err)
@@ -177,6 +319,7 @@ func (m *MessageManager) SendFollowUpMessage(ctx context.Context, recipientURL,
 	}
 
 	result.Success = true
+	result.DryRun = m.dryRun
 	m.logger.Info("Follow-up message sent successfully")
 
 	return result, nil
@@ -185,6 +328,7 @@ func (m *MessageManager) SendFollowUpMessage(ctx context.Context, recipientURL,
 // GetConversations retrieves all conversations
 func (m *MessageManager) GetConversations(ctx context.Context) ([]*Conversation, error) {
 	m.logger.Info("Retrieving conversations")
+	defer m.withContext(ctx)()
 
 	if err := m.navigateToMessaging(); err != nil {
 		return nil, fmt.Errorf("failed to navigate to messaging: %w", err)
@@ -208,6 +352,7 @@ func (m *MessageManager) GetConversations(ctx context.Context) ([]*Conversation,
 // GetNewlyAcceptedConnections finds connections that have recently accepted requests
 func (m *MessageManager) GetNewlyAcceptedConnections(ctx context.Context, since time.Time) ([]string, error) {
 	m.logger.WithField("since", since).Info("Finding newly accepted connections")
+	defer m.withContext(ctx)()
 
 	// Navigate to network/connections page
 	if err := m.page.Navigate("https://www.linkedin.com/mynetwork/invite-connect/connections/"); err != nil {
@@ -230,29 +375,121 @@ func (m *MessageManager) GetNewlyAcceptedConnections(ctx context.Context, since
 }
 
 // BatchSendMessages sends multiple messages
-func (m *MessageManager) BatchSendMessages(ctx context.Context, recipients []string, content string) ([]*MessageResult, error) {
-	m.logger.WithField("count", len(recipients)).Info("Starting batch message sending")
+func (m *MessageManager) BatchSendMessages(ctx context.Context, recipients []string, content string, opts BatchOptions) (*BatchResult, error) {
+	m.logger.WithFields(logrus.Fields{
+		"count":    len(recipients),
+		"batch_id": opts.BatchID,
+		"resume":   opts.Resume,
+	}).Info("Starting batch message sending")
+	defer m.withContext(ctx)()
 
+	batch := &BatchResult{}
 	results := make([]*MessageResult, 0, len(recipients))
 
 	for i, recipientURL := range recipients {
+		if ctx.Err() != nil {
+			m.logger.WithField("processed", i).Warn("Batch interrupted")
+			batch.Interrupted = true
+			break
+		}
+
 		m.logger.WithFields(logrus.Fields{
 			"current": i + 1,
 			"total":   len(recipients),
 			"recipient": recipientURL,
 		}).Debug("Processing recipient")
+		if opts.Progress != nil {
+			opts.Progress.Processing(recipientURL)
+		}
+
+		if opts.Resume && m.isBatchItemCompleted(opts.BatchID, recipientURL) {
+			m.logger.WithField("recipient", recipientURL).Info("Skipping recipient already processed in this batch")
+			results = append(results, &MessageResult{
+				RecipientURL: recipientURL,
+				Skipped:      true,
+			})
+			reportProcessed(opts.Progress, results[len(results)-1])
+			continue
+		}
+
+		if !opts.IncludeReplied && m.hasReplied(recipientURL) {
+			m.logger.WithField("recipient", recipientURL).Info("Skipping recipient who has already replied")
+			results = append(results, &MessageResult{
+				RecipientURL: recipientURL,
+				Skipped:      true,
+				Replied:      true,
+			})
+			reportProcessed(opts.Progress, results[len(results)-1])
+			continue
+		}
+
+		// Recipients on the blacklist are skipped before waiting for a message;
+		// a failed check is left to the send, which checks again
+		skipped := &MessageResult{RecipientURL: recipientURL, SentAt: time.Now()}
+		if blocked, _ := m.checkBlacklist(ctx, recipientURL, skipped); blocked {
+			results = append(results, skipped)
+			reportProcessed(opts.Progress, skipped)
+			continue
+		}
+
+		if m.rateLimiter != nil {
+			if err := m.rateLimiter.WaitForPermission(ctx, ratelimit.ActionMessage); err != nil {
+				if ctx.Err() != nil {
+					m.logger.WithField("processed", i).Warn("Batch interrupted")
+					batch.Interrupted = true
+					break
+				}
+				if !errors.Is(err, ratelimit.ErrLimitReached) {
+					batch.Results = results
+					return batch, err
+				}
+				m.logger.WithError(err).Warn("Stopping batch at rate limit")
+				batch.StoppedAtLimit = true
+				batch.StopReason = err.Error()
+				batch.StopErr = err
+				break
+			}
+		}
 
-		result, err := m.SendMessage(ctx, recipientURL, content)
+		var result *MessageResult
+		err := m.retryPolicy.Do(ctx, func() error {
+			var err error
+			result, err = m.SendMessage(ctx, recipientURL, content)
+			return err
+		}, func(attempt int, err error, delay time.Duration) {
+			m.logger.WithError(err).WithFields(logrus.Fields{
+				"attempt": attempt,
+				"delay":   delay.Round(time.Second),
+			}).Warn("Message failed, retrying")
+		})
+		if err != nil && ctx.Err() != nil {
+			// Left unrecorded so a resumed batch sends this message again
+			m.logger.WithField("processed", i).Warn("Batch interrupted")
+			batch.Interrupted = true
+			break
+		}
 		if err != nil {
+			if errors.Is(err, ratelimit.ErrLimitReached) {
+				m.logger.WithError(err).Warn("Stopping batch at LinkedIn limit")
+				batch.StoppedAtLimit = true
+				batch.StopReason = err.Error()
+				batch.StopErr = err
+				break
+			}
+			if errors.Is(err, errs.ErrSessionExpired) || errors.Is(err, errs.ErrAborted) {
+				batch.Results = append(results, result)
+				return batch, err
+			}
 			m.logger.WithError(err).Error("Failed to send message")
 		}
+		result.Err = err
 
 		results = append(results, result)
+		m.recordBatchItem(opts.BatchID, result)
+		reportProcessed(opts.Progress, result)
 
 		// Add delay between messages
-		if i < len(recipients)-1 {
-			time.Sleep(m.stealth.RandomDelay())
-			
+		if i < len(recipients)-1 && pause.Sleep(ctx, m.stealth.RandomDelay()) == nil {
 			// Add idle movement
 			if err := m.stealth.AddIdleMovement(m.page); err != nil {
 				m.logger.WithError(err).Warn("Failed to add idle movement")
@@ -262,8 +499,11 @@ func (m *MessageManager) BatchSendMessages(ctx context.Context, recipients []str
 
 	// Count results
 	successCount := 0
+	skippedCount := 0
 	for _, result := range results {
-		if result.Success {
+		if result.Skipped {
+			skippedCount++
+		} else if result.Success {
 			successCount++
 		}
 	}
@@ -271,13 +511,138 @@ func (m *MessageManager) BatchSendMessages(ctx context.Context, recipients []str
 	m.logger.WithFields(logrus.Fields{
 		"total": len(recipients),
 		"success": successCount,
+		"skipped": skippedCount,
+		"stopped_at_limit": batch.StoppedAtLimit,
 	}).Info("Batch message sending completed")
 
-	return results, nil
+	batch.Results = results
+	return batch, nil
 }
 
 // Private helper methods
 
+// withContext binds the page to ctx until the returned function is called,
+// so navigation, element lookups and pauses stop once ctx is cancelled
+func (m *MessageManager) withContext(ctx context.Context) func() {
+	page := m.page
+	m.page = page.Context(ctx)
+	return func() { m.page = page }
+}
+
+// captureFailure saves the page when an action failed with err
+func (m *MessageManager) captureFailure(action string, err error) {
+	if err != nil && m.capturer != nil {
+		m.capturer.Failure(m.page, action, err)
+	}
+}
+
+// reportProcessed counts a finished recipient towards the batch progress, if it is followed
+func reportProcessed(progress Progress, result *MessageResult) {
+	if progress != nil && result != nil {
+		progress.Processed(result.Success, result.Skipped)
+	}
+}
+
+func (m *MessageManager) isBatchItemCompleted(batchID, recipientURL string) bool {
+	if m.batchStore == nil || batchID == "" {
+		return false
+	}
+
+	completed, err := m.batchStore.IsBatchItemCompleted(batchID, recipientURL)
+	if err != nil {
+		m.logger.WithError(err).Warn("Failed to check batch progress")
+		return false
+	}
+	return completed
+}
+
+// review passes content to the reviewer, if one is set
+func (m *MessageManager) review(recipientURL, content string) (string, bool, error) {
+	if m.reviewer == nil {
+		return content, true, nil
+	}
+	return m.reviewer.Review(recipientURL, content)
+}
+
+// checkBlacklist marks result as skipped if recipientURL is on the blacklist
+func (m *MessageManager) checkBlacklist(ctx context.Context, recipientURL string, result *MessageResult) (bool, error) {
+	if m.blacklist == nil {
+		return false, nil
+	}
+
+	reason, err := m.blacklist.Blocked(ctx, nil, recipientURL)
+	if err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to check blacklist: %v", err)
+		return false, err
+	}
+	if reason == "" {
+		return false, nil
+	}
+
+	result.Skipped = true
+	result.Blacklisted = true
+	result.ErrorMessage = "On the blacklist: " + reason
+	return true, nil
+}
+
+func (m *MessageManager) recordBatchItem(batchID string, result *MessageResult) {
+	// A dry run must not make a later real run skip the recipient, and
+	// blacklisted recipients are checked again in case they were removed
+	if m.batchStore == nil || batchID == "" || m.dryRun || result.Blacklisted {
+		return
+	}
+
+	status := storage.BatchItemFailed
+	if result.Success {
+		status = storage.BatchItemSuccess
+	} else if result.Rejected {
+		status = storage.BatchItemSkipped
+	}
+
+	if err := m.batchStore.RecordBatchItem(batchID, "message", result.RecipientURL, status, result.ErrorMessage); err != nil {
+		m.logger.WithError(err).Warn("Failed to record batch progress")
+	}
+}
+
+// openConversation opens the message overlay from a recipient's profile. Only
+// connections get a Message button, so its absence means ErrNotConnected.
+func (m *MessageManager) openConversation(recipientURL string) error {
+	if err := m.openProfile(recipientURL); err != nil {
+		return fmt.Errorf("failed to navigate to profile: %w", err)
+	}
+	if err := m.page.WaitLoad(); err != nil {
+		return fmt.Errorf("failed to wait for profile load: %w", err)
+	}
+	if info, err := m.page.Info(); err == nil {
+		if err := errs.CheckPageURL(info.URL); err != nil {
+			return err
+		}
+	}
+
+	messageButton, err := selectors.Wait(m.page, selectors.ProfileMessageButton)
+	if err != nil {
+		return fmt.Errorf("%w: no message button on profile", errs.ErrNotConnected)
+	}
+	if err := m.stealth.ReadProfile(m.page); err != nil {
+		return err
+	}
+
+	if err := m.clickMessageButton(messageButton); err != nil {
+		return fmt.Errorf("failed to click message button: %w", err)
+	}
+
+	// Wait for messaging interface to load
+	return pause.Page(m.page, m.stealth.RandomDelay())
+}
+
+// openProfile opens profileURL through the navigator, if one is set
+func (m *MessageManager) openProfile(profileURL string) error {
+	if m.navigator == nil {
+		return m.page.Navigate(profileURL)
+	}
+	return m.navigator.OpenProfile(m.page, profileURL)
+}
+
 func (m *MessageManager) navigateToMessaging() error {
 	messagingURL := "https://www.linkedin.com/messaging/"
 	
@@ -300,16 +665,8 @@ func (m *MessageManager) navigateToMessaging() error {
 func (m *MessageManager) addRecipientToConversation(recipientURL string) error {
 	m.logger.Debug("Adding recipient to conversation")
 
-	// Look for recipient input field
-	selectors := []string{
-		"input[name='recipients']",
-		".msg-form__recipients-input",
-		"[data-test-id='recipients-input']",
-		"input[placeholder*='Recipients']",
-	}
-
 	var recipientInput *rod.Element
-	for _, selector := range selectors {
+	for _, selector := range selectors.Get(selectors.MessagingRecipientsInput) {
 		input, err := m.page.Element(selector)
 		if err == nil && input != nil {
 			recipientInput = input
@@ -322,7 +679,7 @@ func (m *MessageManager) addRecipientToConversation(recipientURL string) error {
 	}
 
 	// Click recipient input
-	if err := recipientInput.Click("left", 1); err != nil {
+	if err := m.stealth.HumanClick(m.page, recipientInput); err != nil {
 		return fmt.Errorf("failed to click recipient input: %w", err)
 	}
 
@@ -338,19 +695,14 @@ func (m *MessageManager) addRecipientToConversation(recipientURL string) error {
 	}
 
 	// Wait for suggestions to appear
-	time.Sleep(1 * time.Second)
-
-	// Look for and click on the first suggestion
-	selectors = []string{
-		".msg-suggestion-listitem",
-		".recipient-suggestion",
-		"[data-test-id='recipient-suggestion']",
+	if err := pause.Settle(m.page); err != nil {
+		return err
 	}
 
-	for _, selector := range selectors {
+	for _, selector := range selectors.Get(selectors.MessagingSuggestion) {
 		suggestions, err := m.page.Elements(selector)
 		if err == nil && len(suggestions) > 0 {
-			if err := suggestions[0].Click("left", 1); err == nil {
+			if err := m.stealth.HumanClick(m.page, suggestions[0]); err == nil {
 				m.logger.Debug("Selected recipient from suggestions")
 				return nil
 			}
@@ -370,17 +722,8 @@ func (m *MessageManager) addRecipientToConversation(recipientURL string) error {
 func (m *MessageManager) sendDirectMessage(content string) error {
 	m.logger.WithField("content_length", len(content)).Debug("Sending direct message")
 
-	// Look for message input field
-	selectors := []string{
-		"textarea[aria-label*='Write a message']",
-		"textarea[placeholder*='Write a message']",
-		".msg-form__contenteditable",
-		"[data-test-id='message-input']",
-		".msg-textarea",
-	}
-
 	var messageInput *rod.Element
-	for _, selector := range selectors {
+	for _, selector := range selectors.Get(selectors.MessagingComposeInput) {
 		input, err := m.page.Element(selector)
 		if err == nil && input != nil {
 			messageInput = input
@@ -389,29 +732,29 @@ func (m *MessageManager) sendDirectMessage(content string) error {
 	}
 
 	if messageInput == nil {
-		return fmt.Errorf("message input field not found")
+		return fmt.Errorf("%w: message input field not found", errs.ErrDialogNotFound)
 	}
 
-	// Click message input
-	if err := messageInput.Click("left", 1); err != nil {
-		return fmt.Errorf("failed to click message input: %w", err)
-	}
+	// A dry run leaves the compose box empty so no draft or typing indicator is left behind
+	if !m.dryRun {
+		// Click message input
+		if err := m.stealth.HumanClick(m.page, messageInput); err != nil {
+			return fmt.Errorf("failed to click message input: %w", err)
+		}
 
-	// Type message with human-like typing
-	if err := m.stealth.HumanLikeType(m.page, content); err != nil {
-		return fmt.Errorf("failed to type message: %w", err)
-	}
+		// Type message with human-like typing
+		if err := m.stealth.HumanLikeType(m.page, content); err != nil {
+			return fmt.Errorf("failed to type message: %w", err)
+		}
 
-	// Look for send button
-	selectors = []string{
-		"button[aria-label*='Send']",
-		".msg-form__send-button",
-		"[data-test-id='send-button']",
-		"button[type='submit']",
+		// Re-read the draft before sending; the typing indicator stays up meanwhile
+		if err := pause.Page(m.page, m.stealth.ReviewDelay(content)); err != nil {
+			return err
+		}
 	}
 
 	var sendButton *rod.Element
-	for _, selector := range selectors {
+	for _, selector := range selectors.Get(selectors.MessagingSendButton) {
 		button, err := m.page.Element(selector)
 		if err == nil && button != nil {
 			sendButton = button
@@ -423,8 +766,13 @@ func (m *MessageManager) sendDirectMessage(content string) error {
 		return fmt.Errorf("send button not found")
 	}
 
+	if m.dryRun {
+		m.logger.WithField("content_length", len(content)).Info("Dry run: message not sent")
+		return nil
+	}
+
 	// Click send button
-	if err := sendButton.Click("left", 1); err != nil {
+	if err := m.stealth.HumanClick(m.page, sendButton); err != nil {
 		return fmt.Errorf("failed to click send button: %w", err)
 	}
 
@@ -435,32 +783,8 @@ func (m *MessageManager) sendDirectMessage(content string) error {
 // ...
 
 func (m *MessageManager) clickMessageButton(button *rod.Element) error {
-	// Get button position for human-like mouse movement
-	shape, err := button.Shape()
-	if err != nil {
-		return fmt.Errorf("failed to get button position: %w", err)
-	}
-	box := shape.Box()
-
-	// Move mouse to button
-	centerX := box.X + box.Width/2
-	centerY := box.Y + box.Height/2
-
-	// Get current mouse position
-	viewport, err := m.page.Eval("({width: window.innerWidth, height: window.innerHeight})")
-	if err != nil {
-		return fmt.Errorf("failed to get viewport: %w", err)
-	}
-	fromX := viewport.Value.Get("width").Num()
-	fromY := viewport.Value.Get("height").Num()
-
-	// Human-like mouse movement
-	if err := m.stealth.HumanLikeMouseMove(m.page, fromX, fromY, centerX, centerY); err != nil {
-		m.logger.WithError(err).Warn("Failed to perform human-like mouse movement")
-	}
-
-	// Click button
-	if err := button.Click("left", 1); err != nil {
+	// Scroll to the button and hover it before clicking
+	if err := m.stealth.HumanClick(m.page, button); err != nil {
 		return fmt.Errorf("failed to click button: %w", err)
 	}
 
@@ -469,37 +793,17 @@ func (m *MessageManager) clickMessageButton(button *rod.Element) error {
 
 // ...
 func (m *MessageManager) waitForConversationsList() error {
-	selectors := []string{
-		".msg-conversations-container",
-		".conversation-list-container",
-		"[data-test-id='conversations-list']",
-	}
-
-	for i := 0; i < 10; i++ {
-		for _, selector := range selectors {
-			element, err := m.page.Element(selector)
-			if err == nil && element != nil {
-				return nil
-			}
-		}
-		time.Sleep(500 * time.Millisecond)
+	if _, err := selectors.Wait(m.page, selectors.MessagingConversationsList); err != nil {
+		return fmt.Errorf("conversations list not found after waiting: %w", err)
 	}
-
-	return fmt.Errorf("conversations list not found after waiting")
+	return nil
 }
 
 func (m *MessageManager) extractConversations() ([]*Conversation, error) {
 	conversations := make([]*Conversation, 0)
 
-	// Look for conversation items
-	selectors := []string{
-		".msg-conversation-listitem",
-		".conversation-list-item",
-		"[data-test-id='conversation-item']",
-	}
-
 	var conversationElements []*rod.Element
-	for _, selector := range selectors {
+	for _, selector := range selectors.Get(selectors.MessagingConversation) {
 		elements, err := m.page.Elements(selector)
 		if err == nil && len(elements) > 0 {
 			conversationElements = elements
@@ -523,28 +827,10 @@ func (m *MessageManager) extractConversationData(element *rod.Element) (*Convers
 	conversation := &Conversation{}
 
 	// Extract participant name
-	nameElement, err := element.Element(".msg-conversation-listitem__participant-names")
-	if err != nil {
-		nameElement, err = element.Element(".conversation-title")
-	}
-	if err == nil && nameElement != nil {
-		name, err := nameElement.Text()
-		if err == nil {
-			conversation.ParticipantName = strings.TrimSpace(name)
-		}
-	}
+	conversation.ParticipantName = selectors.TextIn(element, selectors.MessagingConversationName)
 
 	// Extract last message
-	messageElement, err := element.Element(".msg-conversation-listitem__last-message")
-	if err != nil {
-		messageElement, err = element.Element(".conversation-snippet")
-	}
-	if err == nil && messageElement != nil {
-		message, err := messageElement.Text()
-		if err == nil {
-			conversation.LastMessage = strings.TrimSpace(message)
-		}
-	}
+	conversation.LastMessage = selectors.TextIn(element, selectors.MessagingConversationText)
 
 	// Extract participant URL
 	linkElement, err := element.Element("a")
@@ -565,15 +851,8 @@ func (m *MessageManager) extractConversationData(element *rod.Element) (*Convers
 func (m *MessageManager) extractRecentConnections(since time.Time) ([]string, error) {
 	connections := make([]string, 0)
 
-	// Look for connection items
-	selectors := []string{
-		".mn-connections__connection-card",
-		".connection-card",
-		"[data-test-id='connection-item']",
-	}
-
 	var connectionElements []*rod.Element
-	for _, selector := range selectors {
+	for _, selector := range selectors.Get(selectors.NetworkConnectionCard) {
 		elements, err := m.page.Elements(selector)
 		if err == nil && len(elements) > 0 {
 			connectionElements = elements
@@ -640,8 +919,13 @@ func (m *MessageManager) extractNameFromURL(url string) string {
 }
 
 func (m *MessageManager) processTemplate(template string, variables map[string]string) string {
-	result := template
-	
+	result, err := personalize.Render(template, variables)
+	if err == nil {
+		return result
+	}
+	m.logger.WithError(err).Warn("Failed to render template, falling back to plain substitution")
+
+	result = template
 	for key, value := range variables {
 		placeholder := fmt.Sprintf("{{%s}}", key)
 		result = strings.ReplaceAll(result, placeholder, value)
@@ -677,5 +961,29 @@ func GetDefaultMessageTemplates() []MessageTemplate {
 			Variables:     []string{"name", "industry", "topic"},
 			CharacterLimit: 300,
 		},
+		{
+			ID:            "congrats_job_change",
+			Name:          "Job Change Congratulations",
+			Content:       "Congratulations on the new role, {{first_name}}! Wishing you a great start.",
+			Type:          "nurture",
+			Variables:     []string{"first_name"},
+			CharacterLimit: 300,
+		},
+		{
+			ID:            "congrats_work_anniversary",
+			Name:          "Work Anniversary Congratulations",
+			Content:       "Happy work anniversary, {{first_name}}! Congrats on another year{{if .Company}} at {{.Company}}{{end}}.",
+			Type:          "nurture",
+			Variables:     []string{"first_name", "company"},
+			CharacterLimit: 300,
+		},
+		{
+			ID:            "congrats_birthday",
+			Name:          "Birthday Wishes",
+			Content:       "Happy birthday, {{first_name}}! Hope you have a great year ahead.",
+			Type:          "nurture",
+			Variables:     []string{"first_name"},
+			CharacterLimit: 300,
+		},
 	}
 }
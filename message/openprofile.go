@@ -0,0 +1,74 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/errs"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/selectors"
+	"linkedin-automation/uitext"
+)
+
+// SendOpenProfileMessage messages a member outside the network whose open
+// profile takes messages without an InMail credit. Subject and body are sent
+// as given, without personalization or review, since callers use it for notes
+// that already went through both. It returns errs.ErrNotOpenProfile, without
+// typing anything, when the InMail form does not show the message as free.
+func (m *MessageManager) SendOpenProfileMessage(ctx context.Context, profileURL, subject, body string) (result *InMailResult, err error) {
+	m.logger.WithFields(logrus.Fields{
+		"recipient_url":  profileURL,
+		"content_length": len(body),
+	}).Info("Sending open profile message")
+	defer m.withContext(ctx)()
+	defer func() { m.captureFailure("send open profile message", err) }()
+
+	result = &InMailResult{
+		MessageResult: MessageResult{
+			RecipientURL: profileURL,
+			Content:      body,
+			SentAt:       time.Now(),
+		},
+		Subject:          subject,
+		CreditsRemaining: -1,
+	}
+
+	if m.rateLimiter != nil {
+		if err := m.rateLimiter.WaitForPermission(ctx, ratelimit.ActionMessage); err != nil {
+			result.ErrorMessage = err.Error()
+			return result, err
+		}
+	}
+
+	if err := m.openInMailForm(profileURL); err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to open InMail form: %v", err)
+		return result, err
+	}
+
+	if !uitext.Contains(selectors.Text(m.page, selectors.InMailOpenProfile), uitext.OpenProfile) {
+		result.ErrorMessage = "Not an open profile"
+		return result, errs.ErrNotOpenProfile
+	}
+
+	if !m.dryRun {
+		if err := m.typeInMailSubject(subject); err != nil {
+			result.ErrorMessage = fmt.Sprintf("Failed to enter subject: %v", err)
+			return result, err
+		}
+	}
+
+	if err := m.sendDirectMessage(body); err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to send open profile message: %v", err)
+		return result, err
+	}
+
+	result.Success = true
+	result.DryRun = m.dryRun
+	if !m.dryRun {
+		m.logger.Info("Open profile message sent successfully")
+	}
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package message
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/ratelimit"
+)
+
+// ParallelBatchSendMessages spreads recipients over managers, each working its
+// own browser tab, and runs their batches concurrently. Recipients are dealt
+// out round-robin so no two tabs message the same person. Each tab's context
+// is marked with ratelimit.WithTab, so the rate limiter the managers have in
+// common paces every tab by message_delay on its own while the daily, hourly
+// and burst quotas stay shared. Results come back in the order of recipients.
+func ParallelBatchSendMessages(ctx context.Context, managers []*MessageManager, recipients []string, content string, opts BatchOptions) (*BatchResult, error) {
+	if len(managers) <= 1 || len(recipients) <= 1 {
+		return managers[0].BatchSendMessages(ctx, recipients, content, opts)
+	}
+	if len(managers) > len(recipients) {
+		managers = managers[:len(recipients)]
+	}
+
+	// Deal recipients out so every tab gets a mix of the list
+	shares := make([][]string, len(managers))
+	order := make(map[string]int, len(recipients))
+	for i, recipientURL := range recipients {
+		shares[i%len(managers)] = append(shares[i%len(managers)], recipientURL)
+		order[recipientURL] = i
+	}
+
+	managers[0].logger.WithFields(logrus.Fields{
+		"count": len(recipients),
+		"tabs":  len(managers),
+	}).Info("Starting parallel batch message sending")
+
+	batches := make([]*BatchResult, len(managers))
+	failures := make([]error, len(managers))
+	var wg sync.WaitGroup
+	for t, manager := range managers {
+		wg.Add(1)
+		go func(t int, manager *MessageManager) {
+			defer wg.Done()
+			tabCtx := ratelimit.WithTab(ctx, t)
+			// Stagger the tabs so their messages do not go out in lockstep
+			if t > 0 {
+				select {
+				case <-ctx.Done():
+				case <-time.After(time.Duration(t) * manager.stealth.RandomDelay() / time.Duration(len(managers))):
+				}
+			}
+			batches[t], failures[t] = manager.BatchSendMessages(tabCtx, shares[t], content, opts)
+		}(t, manager)
+	}
+	wg.Wait()
+
+	merged := &BatchResult{}
+	var err error
+	for t, batch := range batches {
+		if failures[t] != nil && err == nil {
+			err = failures[t]
+		}
+		if batch == nil {
+			continue
+		}
+		merged.Results = append(merged.Results, batch.Results...)
+		if batch.StoppedAtLimit && !merged.StoppedAtLimit {
+			merged.StoppedAtLimit = true
+			merged.StopReason = batch.StopReason
+			merged.StopErr = batch.StopErr
+		}
+		merged.Interrupted = merged.Interrupted || batch.Interrupted
+	}
+	sort.SliceStable(merged.Results, func(i, j int) bool {
+		return order[merged.Results[i].RecipientURL] < order[merged.Results[j].RecipientURL]
+	})
+
+	return merged, err
+}
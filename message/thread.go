@@ -0,0 +1,222 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/errs"
+	"linkedin-automation/pause"
+)
+
+var (
+	// threadIDOnly matches a bare conversation ID such as 2-ZjQ5YjE0...
+	threadIDOnly = regexp.MustCompile(`^[A-Za-z0-9_=-]+$`)
+	// moreParticipants matches the "and 3 others" suffix of long group listings
+	moreParticipants = regexp.MustCompile(`\s+and\s+\d+\s+others?$`)
+)
+
+// ThreadURL returns the messaging URL of a conversation
+func ThreadURL(threadID string) string {
+	return "https://www.linkedin.com/messaging/thread/" + threadID + "/"
+}
+
+// ParseThreadID accepts a conversation URL or a bare conversation ID and returns the ID
+func ParseThreadID(thread string) (string, error) {
+	thread = strings.TrimSpace(thread)
+	if match := threadIDPattern.FindStringSubmatch(thread); match != nil {
+		return match[1], nil
+	}
+	if threadIDOnly.MatchString(thread) {
+		return thread, nil
+	}
+	return "", fmt.Errorf("not a conversation URL or ID: %s", thread)
+}
+
+// ListThreads returns up to limit recent conversations from the messaging sidebar
+func (m *MessageManager) ListThreads(ctx context.Context, limit int) ([]*Thread, error) {
+	defer m.withContext(ctx)()
+	if err := m.navigateToMessaging(); err != nil {
+		return nil, fmt.Errorf("failed to navigate to messaging: %w", err)
+	}
+	if err := m.waitForConversationsList(); err != nil {
+		return nil, fmt.Errorf("failed to wait for conversations list: %w", err)
+	}
+
+	threads := m.extractInboxThreads()
+	if limit > 0 && len(threads) > limit {
+		threads = threads[:limit]
+	}
+	return threads, nil
+}
+
+// SendGroupMessage starts a new conversation with several recipients and sends
+// the first message. The thread ID of the new conversation is reported when
+// LinkedIn navigates to it.
+func (m *MessageManager) SendGroupMessage(ctx context.Context, recipientURLs []string, content string) (result *MessageResult, err error) {
+	m.logger.WithFields(logrus.Fields{
+		"recipients":     len(recipientURLs),
+		"content_length": len(content),
+	}).Info("Sending group message")
+	defer m.withContext(ctx)()
+	defer func() { m.captureFailure("send group message", err) }()
+
+	result = &MessageResult{
+		RecipientURL: strings.Join(recipientURLs, ","),
+		SentAt:       time.Now(),
+		Content:      content,
+	}
+	if len(recipientURLs) < 2 {
+		result.ErrorMessage = "A group conversation needs at least two recipients"
+		return result, fmt.Errorf("a group conversation needs at least two recipients")
+	}
+
+	// One blacklisted participant keeps the whole conversation from being started
+	for _, recipientURL := range recipientURLs {
+		if blocked, err := m.checkBlacklist(ctx, recipientURL, result); blocked || err != nil {
+			return result, err
+		}
+	}
+
+	if err := m.openNewConversation(); err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to start conversation: %v", err)
+		return result, err
+	}
+
+	for _, recipientURL := range recipientURLs {
+		if err := m.addRecipientToConversation(recipientURL); err != nil {
+			result.ErrorMessage = fmt.Sprintf("Failed to add recipient %s: %v", recipientURL, err)
+			return result, err
+		}
+		if err := pause.Sleep(ctx, m.stealth.RandomDelay()); err != nil {
+			return result, err
+		}
+	}
+
+	if err := m.sendDirectMessage(content); err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to send message: %v", err)
+		return result, err
+	}
+
+	if !m.dryRun {
+		result.ThreadID = m.waitForThreadID()
+	}
+	result.Success = true
+	result.DryRun = m.dryRun
+	m.logger.WithField("thread_id", result.ThreadID).Info("Group message sent successfully")
+
+	return result, nil
+}
+
+// ReplyToThread sends a message into an existing conversation, given its URL or ID
+func (m *MessageManager) ReplyToThread(ctx context.Context, thread, content string) (result *MessageResult, err error) {
+	defer m.withContext(ctx)()
+	defer func() { m.captureFailure("reply to thread", err) }()
+
+	result = &MessageResult{
+		RecipientURL: thread,
+		SentAt:       time.Now(),
+		Content:      content,
+	}
+
+	threadID, err := ParseThreadID(thread)
+	if err != nil {
+		result.ErrorMessage = err.Error()
+		return result, err
+	}
+	result.ThreadID = threadID
+	result.RecipientURL = ThreadURL(threadID)
+
+	m.logger.WithField("thread_id", threadID).Info("Replying to conversation")
+
+	if err := m.page.Navigate(result.RecipientURL); err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to open conversation: %v", err)
+		return result, err
+	}
+	if err := m.page.WaitLoad(); err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to wait for conversation load: %v", err)
+		return result, err
+	}
+	if info, err := m.page.Info(); err == nil {
+		if err := errs.CheckPageURL(info.URL); err != nil {
+			result.ErrorMessage = err.Error()
+			return result, err
+		}
+		// LinkedIn falls back to the inbox for conversations the account cannot see
+		if !strings.Contains(info.URL, threadID) {
+			result.ErrorMessage = "Conversation not found"
+			return result, fmt.Errorf("conversation %s not found", threadID)
+		}
+	}
+
+	if participant := m.extractThreadParticipant(); participant != "" {
+		if blocked, err := m.checkBlacklist(ctx, participant, result); blocked || err != nil {
+			return result, err
+		}
+	}
+
+	if err := m.sendDirectMessage(content); err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to send message: %v", err)
+		return result, err
+	}
+
+	result.Success = true
+	result.DryRun = m.dryRun
+	m.logger.Info("Reply sent successfully")
+
+	return result, nil
+}
+
+func (m *MessageManager) openNewConversation() error {
+	if err := m.page.Navigate("https://www.linkedin.com/messaging/thread/new/"); err != nil {
+		return fmt.Errorf("failed to open new conversation: %w", err)
+	}
+	if err := m.page.WaitLoad(); err != nil {
+		return fmt.Errorf("failed to wait for page load: %w", err)
+	}
+	if info, err := m.page.Info(); err == nil {
+		if err := errs.CheckPageURL(info.URL); err != nil {
+			return err
+		}
+	}
+	return pause.Page(m.page, m.stealth.RandomDelay())
+}
+
+// waitForThreadID returns the ID of the conversation the page moved to after
+// sending, or "" if it did not move within the element timeout
+func (m *MessageManager) waitForThreadID() string {
+	threadID := ""
+	pause.Until(m.page, pause.Current().ElementTimeout, func() bool {
+		if info, err := m.page.Info(); err == nil {
+			if match := threadIDPattern.FindStringSubmatch(info.URL); match != nil && match[1] != "new" {
+				threadID = match[1]
+			}
+		}
+		return threadID != ""
+	})
+	return threadID
+}
+
+// splitParticipants splits a sidebar listing such as "Ann Lee, Bo Chen and 2
+// others" into names and reports whether it lists more than one person
+func splitParticipants(listing string) ([]string, bool) {
+	listing = strings.TrimSpace(listing)
+	if listing == "" {
+		return nil, false
+	}
+
+	group := moreParticipants.MatchString(listing)
+	listing = moreParticipants.ReplaceAllString(listing, "")
+
+	var names []string
+	for _, part := range strings.Split(strings.ReplaceAll(listing, " and ", ", "), ",") {
+		if name := strings.TrimSpace(part); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, group || len(names) > 1
+}
@@ -0,0 +1,185 @@
+// Package navigate opens profiles the way a member reaches them: by searching
+// for the prospect and clicking their search result, then going back to the
+// results before the next one, rather than jumping to each profile URL. The
+// browser's history and each page's referrer then read like a person's.
+package navigate
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/pause"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/selectors"
+	"linkedin-automation/storage"
+)
+
+// Navigation modes
+const (
+	ModeDirect = "direct" // Open profile URLs directly
+	ModeClick  = "click"  // Search for the prospect and click through
+)
+
+const searchURL = "https://www.linkedin.com/search/results/people/"
+
+// StealthManager clicks and types like a person
+type StealthManager interface {
+	HumanClick(page *rod.Page, element *rod.Element) error
+	HumanLikeType(page *rod.Page, text string) error
+}
+
+// Profiles looks up the stored profile of a prospect, whose name is searched for
+type Profiles interface {
+	GetProfile(url string) (*storage.Profile, error)
+}
+
+// Navigator opens profiles through in-page clicks. It belongs to one page and
+// remembers whether the profile open on it was reached by a click, which the
+// back button returns from.
+type Navigator struct {
+	stealth  StealthManager
+	profiles Profiles
+	logger   *logrus.Logger
+	clicked  bool
+}
+
+// NewNavigator creates a navigator searching for prospects by their stored
+// name, or by the name in their profile URL
+func NewNavigator(stealth StealthManager, profiles Profiles, logger *logrus.Logger) *Navigator {
+	return &Navigator{
+		stealth:  stealth,
+		profiles: profiles,
+		logger:   logger,
+	}
+}
+
+// OpenProfile opens profileURL on page. The profile is clicked where it is
+// linked from the current page, or else from the results of searching for
+// the prospect's name; when it is not among them either the URL is opened
+// directly. The caller waits for the profile to load as after page.Navigate.
+func (n *Navigator) OpenProfile(page *rod.Page, profileURL string) error {
+	target := profileurl.Canonicalize(profileURL)
+
+	// Back to the results the last profile was clicked from, which may list this one too
+	if n.clicked {
+		n.clicked = false
+		if err := n.back(page); err != nil {
+			n.logger.WithError(err).Debug("Failed to go back to search results")
+		}
+	}
+
+	if link := n.findLink(page, target); link != nil {
+		return n.follow(page, link, profileURL)
+	}
+
+	if err := n.search(page, profileURL); err != nil {
+		if page.GetContext().Err() != nil {
+			return err
+		}
+		n.logger.WithError(err).Debug("Failed to search for profile, opening it directly")
+		return page.Navigate(profileURL)
+	}
+	if link := n.findLink(page, target); link != nil {
+		return n.follow(page, link, profileURL)
+	}
+
+	n.logger.WithField("profile_url", profileURL).Debug("Profile not among the search results, opening it directly")
+	return page.Navigate(profileURL)
+}
+
+func (n *Navigator) back(page *rod.Page) error {
+	if err := page.NavigateBack(); err != nil {
+		return err
+	}
+	if err := page.WaitLoad(); err != nil {
+		return err
+	}
+	return pause.Settle(page)
+}
+
+// findLink returns a visible link on page to the profile target
+func (n *Navigator) findLink(page *rod.Page, target string) *rod.Element {
+	for _, link := range selectors.FindAll(page, selectors.ProfileLink) {
+		href, err := link.Attribute("href")
+		if err != nil || href == nil || profileurl.Canonicalize(*href) != target {
+			continue
+		}
+		if visible, err := link.Visible(); err == nil && visible {
+			return link
+		}
+	}
+	return nil
+}
+
+// follow clicks link, opening the profile directly if the click fails
+func (n *Navigator) follow(page *rod.Page, link *rod.Element, profileURL string) error {
+	n.logger.WithField("profile_url", profileURL).Debug("Clicking through to profile")
+	if err := n.stealth.HumanClick(page, link); err != nil {
+		if page.GetContext().Err() != nil {
+			return err
+		}
+		n.logger.WithError(err).Debug("Failed to click profile link, opening it directly")
+		return page.Navigate(profileURL)
+	}
+	n.clicked = true
+	return nil
+}
+
+// search looks the prospect up by name, typing it into the navigation bar's
+// search box. Pages without one, such as a blank tab, load the results by URL.
+func (n *Navigator) search(page *rod.Page, profileURL string) error {
+	name := n.name(profileURL)
+	if name == "" {
+		return fmt.Errorf("no name to search for")
+	}
+
+	box, _ := selectors.Find(page, selectors.NavSearch)
+	if box == nil {
+		if err := page.Navigate(searchURL + "?keywords=" + url.QueryEscape(name)); err != nil {
+			return fmt.Errorf("failed to open search results: %w", err)
+		}
+	} else {
+		if err := n.stealth.HumanClick(page, box); err != nil {
+			return fmt.Errorf("failed to click search box: %w", err)
+		}
+		if err := box.SelectAllText(); err != nil {
+			return fmt.Errorf("failed to clear search box: %w", err)
+		}
+		if err := n.stealth.HumanLikeType(page, name); err != nil {
+			return fmt.Errorf("failed to type search: %w", err)
+		}
+		if err := page.Keyboard.Type(input.Enter); err != nil {
+			return fmt.Errorf("failed to submit search: %w", err)
+		}
+	}
+
+	if err := page.WaitLoad(); err != nil {
+		return fmt.Errorf("failed to wait for search results: %w", err)
+	}
+	if _, err := selectors.Wait(page, selectors.SearchResult); err != nil {
+		return fmt.Errorf("search results not found: %w", err)
+	}
+	return pause.Settle(page)
+}
+
+// name returns the prospect's stored name, or the words of their profile URL
+// without the number LinkedIn appends to taken names, as in jane-doe-4b2a1c
+func (n *Navigator) name(profileURL string) string {
+	if n.profiles != nil {
+		if profile, err := n.profiles.GetProfile(profileURL); err == nil && profile != nil && profile.Name != "" {
+			return profile.Name
+		}
+	}
+
+	words := strings.Split(profileurl.Slug(profileURL), "-")
+	if last := words[len(words)-1]; strings.IndexFunc(last, unicode.IsDigit) >= 0 {
+		words = words[:len(words)-1]
+	}
+	return strings.TrimSpace(strings.Join(words, " "))
+}
@@ -0,0 +1,201 @@
+// Package nurture reads the "Catch up" tab of My Network for job changes,
+// work anniversaries and birthdays among 1st-degree connections, so they can be
+// congratulated with a templated message.
+package nurture
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/pause"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/selectors"
+)
+
+// Kinds of catch-up events
+const (
+	KindJobChange       = "job-change"
+	KindWorkAnniversary = "work-anniversary"
+	KindBirthday        = "birthday"
+)
+
+// Kinds lists every catch-up event kind
+var Kinds = []string{KindJobChange, KindWorkAnniversary, KindBirthday}
+
+// catchUpURLs maps each kind to its filtered view of the Catch up tab
+var catchUpURLs = map[string]string{
+	KindJobChange:       "https://www.linkedin.com/mynetwork/catch-up/job_changes/",
+	KindWorkAnniversary: "https://www.linkedin.com/mynetwork/catch-up/work_anniversaries/",
+	KindBirthday:        "https://www.linkedin.com/mynetwork/catch-up/birthdays/",
+}
+
+// maxCatchUpScrolls bounds the scrolling through each view
+const maxCatchUpScrolls = 20
+
+// MessageType is the messages table type recorded for congratulation messages
+const MessageType = "nurture"
+
+// Event is a job change, work anniversary or birthday of a connection
+type Event struct {
+	Kind       string
+	Name       string
+	ProfileURL string
+	Detail     string // Card text, e.g. "Started a new position as CTO at Acme"
+}
+
+// StealthManager interface for stealth operations
+type StealthManager interface {
+	RandomDelay() time.Duration
+	HumanLikeScroll(page *rod.Page, scrollAmount int) error
+}
+
+// Capturer saves the page for debugging when an action fails
+type Capturer interface {
+	Failure(page *rod.Page, action string, err error)
+}
+
+// Manager reads catch-up events from My Network
+type Manager struct {
+	page     *rod.Page
+	logger   *logrus.Logger
+	stealth  StealthManager
+	capturer Capturer
+}
+
+// NewManager creates a catch-up reader
+func NewManager(page *rod.Page, logger *logrus.Logger, stealth StealthManager) *Manager {
+	return &Manager{
+		page:    page,
+		logger:  logger,
+		stealth: stealth,
+	}
+}
+
+// SetCapturer saves the page whenever reading the Catch up tab fails
+func (m *Manager) SetCapturer(capturer Capturer) {
+	m.capturer = capturer
+}
+
+// ParseKinds validates a list of event kinds; an empty list means every kind
+func ParseKinds(kinds []string) ([]string, error) {
+	if len(kinds) == 0 {
+		return Kinds, nil
+	}
+	for _, kind := range kinds {
+		if _, ok := catchUpURLs[kind]; !ok {
+			return nil, fmt.Errorf("unknown catch-up kind %q (want %s)", kind, strings.Join(Kinds, ", "))
+		}
+	}
+	return kinds, nil
+}
+
+// CatchUp returns up to maxResults events of each of the given kinds, or all
+// of them when maxResults is 0
+func (m *Manager) CatchUp(ctx context.Context, kinds []string, maxResults int) ([]*Event, error) {
+	defer m.withContext(ctx)()
+
+	var events []*Event
+	for _, kind := range kinds {
+		if err := ctx.Err(); err != nil {
+			return events, err
+		}
+		found, err := m.events(ctx, kind, maxResults)
+		events = append(events, found...)
+		if err != nil {
+			return events, err
+		}
+	}
+	return events, nil
+}
+
+// withContext binds the page to ctx until the returned function is called,
+// so navigation, element lookups and pauses stop once ctx is cancelled
+func (m *Manager) withContext(ctx context.Context) func() {
+	page := m.page
+	m.page = page.Context(ctx)
+	return func() { m.page = page }
+}
+
+// events reads one kind's view of the Catch up tab
+func (m *Manager) events(ctx context.Context, kind string, maxResults int) (events []*Event, err error) {
+	defer func() {
+		if err != nil && m.capturer != nil {
+			m.capturer.Failure(m.page, "catch up "+kind, err)
+		}
+	}()
+
+	if err := m.page.Navigate(catchUpURLs[kind]); err != nil {
+		return nil, fmt.Errorf("failed to navigate to catch-up %s: %w", kind, err)
+	}
+	if err := m.page.WaitLoad(); err != nil {
+		return nil, fmt.Errorf("failed to wait for page load: %w", err)
+	}
+	if _, err := selectors.Wait(m.page, selectors.CatchUpCard); err != nil {
+		m.logger.WithField("kind", kind).Info("No catch-up events")
+		return nil, nil
+	}
+
+	seen := make(map[string]bool)
+	for scroll := 0; scroll < maxCatchUpScrolls; scroll++ {
+		if err := ctx.Err(); err != nil {
+			return events, err
+		}
+
+		for _, card := range selectors.FindAll(m.page, selectors.CatchUpCard) {
+			if maxResults > 0 && len(events) >= maxResults {
+				break
+			}
+			event := extractEvent(card, kind)
+			if event == nil || seen[event.ProfileURL] {
+				continue
+			}
+			seen[event.ProfileURL] = true
+			events = append(events, event)
+		}
+
+		if maxResults > 0 && len(events) >= maxResults {
+			break
+		}
+
+		before := len(selectors.FindAll(m.page, selectors.CatchUpCard))
+		if err := m.stealth.HumanLikeScroll(m.page, 1500); err != nil {
+			m.logger.WithError(err).Debug("Failed to scroll catch-up view")
+		}
+		if err := pause.Sleep(ctx, m.stealth.RandomDelay()); err != nil {
+			return events, err
+		}
+		if len(selectors.FindAll(m.page, selectors.CatchUpCard)) <= before {
+			break
+		}
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"kind":  kind,
+		"count": len(events),
+	}).Info("Read catch-up events")
+	return events, nil
+}
+
+// extractEvent reads a catch-up card, or returns nil if it has no profile link
+func extractEvent(card *rod.Element, kind string) *Event {
+	link := selectors.FindIn(card, selectors.ProfileLink)
+	if link == nil {
+		return nil
+	}
+	href, err := link.Attribute("href")
+	if err != nil || href == nil || profileurl.Slug(*href) == "" {
+		return nil
+	}
+
+	return &Event{
+		Kind:       kind,
+		Name:       selectors.TextIn(card, selectors.CatchUpName),
+		ProfileURL: profileurl.Canonicalize(*href),
+		Detail:     selectors.TextIn(card, selectors.CatchUpDetail),
+	}
+}
@@ -0,0 +1,80 @@
+package opener
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Endpoint defaults
+const (
+	DefaultAnthropicURL   = "https://api.anthropic.com"
+	DefaultAnthropicModel = "claude-3-5-haiku-latest"
+)
+
+const anthropicVersion = "2023-06-01"
+
+// Anthropic writes through the Anthropic Messages API
+type Anthropic struct {
+	apiKey  string
+	model   string
+	baseURL string
+}
+
+// NewAnthropic creates a generator for the Anthropic API
+func NewAnthropic(apiKey, model, baseURL string) *Anthropic {
+	if model == "" {
+		model = DefaultAnthropicModel
+	}
+	if baseURL == "" {
+		baseURL = DefaultAnthropicURL
+	}
+	return &Anthropic{
+		apiKey:  apiKey,
+		model:   model,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Name returns the provider and model
+func (a *Anthropic) Name() string {
+	return "anthropic/" + a.model
+}
+
+// Generate completes the prompt
+func (a *Anthropic) Generate(ctx context.Context, instructions, prompt string, maxTokens int) (string, int, error) {
+	headers := map[string]string{
+		"x-api-key":         a.apiKey,
+		"anthropic-version": anthropicVersion,
+	}
+
+	var resp struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		Usage struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	err := postJSON(ctx, a.baseURL+"/v1/messages", headers, map[string]interface{}{
+		"model":      a.model,
+		"max_tokens": maxTokens,
+		"system":     instructions,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}, &resp)
+	if err != nil {
+		return "", 0, fmt.Errorf("anthropic: %w", err)
+	}
+
+	var text strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	return text.String(), resp.Usage.InputTokens + resp.Usage.OutputTokens, nil
+}
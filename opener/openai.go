@@ -0,0 +1,89 @@
+package opener
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Endpoint defaults
+const (
+	DefaultOpenAIURL   = "https://api.openai.com/v1"
+	DefaultOpenAIModel = "gpt-4o-mini"
+	DefaultLocalURL    = "http://localhost:11434/v1"
+	DefaultLocalModel  = "llama3.1"
+)
+
+// OpenAI writes through the OpenAI chat completions API, or any endpoint
+// compatible with it such as a local Ollama or LM Studio server
+type OpenAI struct {
+	provider string
+	apiKey   string
+	model    string
+	baseURL  string
+}
+
+// NewOpenAI creates a generator for the OpenAI API
+func NewOpenAI(apiKey, model, baseURL string) *OpenAI {
+	return newChatCompletions("openai", apiKey, model, DefaultOpenAIModel, baseURL, DefaultOpenAIURL)
+}
+
+// NewLocal creates a generator for a local OpenAI-compatible endpoint, which
+// usually needs no API key
+func NewLocal(apiKey, model, baseURL string) *OpenAI {
+	return newChatCompletions("local", apiKey, model, DefaultLocalModel, baseURL, DefaultLocalURL)
+}
+
+func newChatCompletions(provider, apiKey, model, defaultModel, baseURL, defaultURL string) *OpenAI {
+	if model == "" {
+		model = defaultModel
+	}
+	if baseURL == "" {
+		baseURL = defaultURL
+	}
+	return &OpenAI{
+		provider: provider,
+		apiKey:   apiKey,
+		model:    model,
+		baseURL:  strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Name returns the provider and model
+func (o *OpenAI) Name() string {
+	return o.provider + "/" + o.model
+}
+
+// Generate completes the prompt
+func (o *OpenAI) Generate(ctx context.Context, instructions, prompt string, maxTokens int) (string, int, error) {
+	headers := map[string]string{}
+	if o.apiKey != "" {
+		headers["Authorization"] = "Bearer " + o.apiKey
+	}
+
+	var resp struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			TotalTokens int `json:"total_tokens"`
+		} `json:"usage"`
+	}
+	err := postJSON(ctx, o.baseURL+"/chat/completions", headers, map[string]interface{}{
+		"model":      o.model,
+		"max_tokens": maxTokens,
+		"messages": []map[string]string{
+			{"role": "system", "content": instructions},
+			{"role": "user", "content": prompt},
+		},
+	}, &resp)
+	if err != nil {
+		return "", 0, fmt.Errorf("%s: %w", o.provider, err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", resp.Usage.TotalTokens, nil
+	}
+	return resp.Choices[0].Message.Content, resp.Usage.TotalTokens, nil
+}
@@ -0,0 +1,177 @@
+// Package opener writes a personalized first line for messages with a
+// language model, from the prospect's headline, about section and recent
+// post. Templates insert it with {{ai_opener}}.
+package opener
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/storage"
+)
+
+// DefaultInstructions tell the model what kind of line to write, unless
+// replaced through the configuration
+const DefaultInstructions = `You write the opening line of a short LinkedIn message to a prospect.
+Write one friendly, specific sentence of at most 25 words that refers to something from their profile or recent post.
+Do not greet them, do not use their name, hashtags or emojis, do not invent facts, and reply with the sentence only.`
+
+// maxFieldLength caps the about section and post text sent to the model, so
+// a long profile does not eat the token budget
+const maxFieldLength = 1000
+
+// ErrBudgetExhausted is returned once the day's tokens are spent
+var ErrBudgetExhausted = errors.New("daily AI token budget exhausted")
+
+// Prospect is what the model is told about the person
+type Prospect struct {
+	ProfileURL string
+	Name       string
+	Headline   string
+	Company    string
+	About      string
+	RecentPost string
+}
+
+// Generator completes a prompt with a language model
+type Generator interface {
+	Name() string // Provider and model, e.g. openai/gpt-4o-mini
+	Generate(ctx context.Context, instructions, prompt string, maxTokens int) (text string, tokens int, err error)
+}
+
+// Store caches written openers and counts the tokens spent on them
+type Store interface {
+	GetAIOpener(profileURL, configKey string) (string, error)
+	SaveAIOpener(opener *storage.AIOpener) error
+	AITokensUsedSince(since time.Time) (int, error)
+}
+
+// Options limit what writing openers costs
+type Options struct {
+	Instructions     string // Empty uses DefaultInstructions
+	MaxTokens        int    // Per opener
+	DailyTokenBudget int    // Across all openers written today; 0 for no limit
+}
+
+// Writer writes openers, reusing the one written for a prospect before
+type Writer struct {
+	generator Generator
+	store     Store
+	opts      Options
+	logger    *logrus.Logger
+}
+
+// NewWriter creates a writer
+func NewWriter(generator Generator, store Store, opts Options, logger *logrus.Logger) *Writer {
+	if opts.Instructions == "" {
+		opts.Instructions = DefaultInstructions
+	}
+	return &Writer{
+		generator: generator,
+		store:     store,
+		opts:      opts,
+		logger:    logger,
+	}
+}
+
+// Key identifies the model and instructions openers are written with, so
+// changing either writes new openers and needs a new preview
+func (w *Writer) Key() string {
+	hash := sha1.New()
+	hash.Write([]byte(w.generator.Name() + "\n" + w.opts.Instructions))
+	return hex.EncodeToString(hash.Sum(nil))[:12]
+}
+
+// Opener returns the opening line for a prospect, writing it if it was not
+// written before. It returns an empty line without calling the model when the
+// profile offers nothing to personalize from.
+func (w *Writer) Opener(ctx context.Context, prospect *Prospect) (string, error) {
+	key := w.Key()
+	cached, err := w.store.GetAIOpener(prospect.ProfileURL, key)
+	if err != nil {
+		return "", err
+	}
+	if cached != "" {
+		return cached, nil
+	}
+
+	if prospect.Headline == "" && prospect.About == "" && prospect.RecentPost == "" {
+		w.logger.WithField("profile_url", prospect.ProfileURL).Debug("Nothing to write an AI opener from")
+		return "", nil
+	}
+
+	if w.opts.DailyTokenBudget > 0 {
+		now := time.Now()
+		used, err := w.store.AITokensUsedSince(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+		if err != nil {
+			return "", err
+		}
+		if used >= w.opts.DailyTokenBudget {
+			return "", ErrBudgetExhausted
+		}
+	}
+
+	text, tokens, err := w.generator.Generate(ctx, w.opts.Instructions, prompt(prospect), w.opts.MaxTokens)
+	if err != nil {
+		return "", fmt.Errorf("failed to write AI opener: %w", err)
+	}
+	line := clean(text)
+	if line == "" {
+		return "", fmt.Errorf("failed to write AI opener: %s returned no text", w.generator.Name())
+	}
+
+	if err := w.store.SaveAIOpener(&storage.AIOpener{
+		ProfileURL: prospect.ProfileURL,
+		ConfigKey:  key,
+		Opener:     line,
+		Generator:  w.generator.Name(),
+		Tokens:     tokens,
+	}); err != nil {
+		return "", err
+	}
+
+	w.logger.WithFields(logrus.Fields{
+		"profile_url": prospect.ProfileURL,
+		"generator":   w.generator.Name(),
+		"tokens":      tokens,
+	}).Info("AI opener written")
+	return line, nil
+}
+
+// prompt describes the prospect to the model
+func prompt(prospect *Prospect) string {
+	var b strings.Builder
+	for _, field := range []struct{ label, value string }{
+		{"Name", prospect.Name},
+		{"Headline", prospect.Headline},
+		{"Company", prospect.Company},
+		{"About", prospect.About},
+		{"Recent post", prospect.RecentPost},
+	} {
+		value := strings.TrimSpace(field.value)
+		if value == "" {
+			continue
+		}
+		if runes := []rune(value); len(runes) > maxFieldLength {
+			value = string(runes[:maxFieldLength]) + "..."
+		}
+		fmt.Fprintf(&b, "%s: %s\n", field.label, value)
+	}
+	return b.String()
+}
+
+// clean keeps the first line of the model's reply without surrounding quotes
+func clean(text string) string {
+	text = strings.TrimSpace(text)
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = text[:i]
+	}
+	return strings.TrimSpace(strings.Trim(text, "\"'“”"))
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"linkedin-automation/connect"
+	"linkedin-automation/message"
+	"linkedin-automation/progress"
+	"linkedin-automation/queue"
+	"linkedin-automation/search"
+	"linkedin-automation/storage"
+)
+
+// With --json, commands print one JSON document on stdout instead of their
+// text report, and logs go to stderr so the two can be piped separately
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	return nil
+}
+
+// errorOutput is printed when a command fails in JSON mode
+type errorOutput struct {
+	Error string `json:"error"`
+}
+
+// queuedOutput reports the tasks added with --queue or --at
+type queuedOutput struct {
+	Queued      int        `json:"queued"`
+	TaskIDs     []int      `json:"task_ids"`
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"` // When the tasks become due, if not straight away
+}
+
+// newQueuedOutput starts the report of tasks queued with opts
+func newQueuedOutput(opts queue.Options) queuedOutput {
+	out := queuedOutput{TaskIDs: []int{}}
+	if !opts.ScheduledAt.IsZero() {
+		out.ScheduledAt = &opts.ScheduledAt
+	}
+	return out
+}
+
+// scheduledFor completes the line reporting queued tasks with when they become due
+func scheduledFor(opts queue.Options) string {
+	if opts.ScheduledAt.IsZero() {
+		return ""
+	}
+	return " for " + opts.ScheduledAt.Local().Format("2006-01-02 15:04")
+}
+
+type searchOutput struct {
+	Total            int            `json:"total"`
+	UniqueProfiles   int            `json:"unique_profiles"`
+	AlreadyContacted int            `json:"already_contacted"`
+	Excluded         int            `json:"excluded"` // With --exclude-contacted, dropped from the results
+	Filtered         int            `json:"filtered"` // Left out by the degree, mutual connection and badge filters
+	StoppedAtLimit   bool           `json:"stopped_at_limit"`
+	NextPage         int            `json:"next_page,omitempty"` // Results page to continue from with --start-page
+	CachedAt         *time.Time     `json:"cached_at,omitempty"` // When LinkedIn was searched, for results from the search cache
+	DurationSeconds  float64        `json:"duration_seconds"`
+	Output           string         `json:"output,omitempty"` // File the results were also saved to
+	Results          []searchResult `json:"results"`
+}
+
+type searchResult struct {
+	Name              string `json:"name"`
+	Title             string `json:"title"`
+	Company           string `json:"company"`
+	Location          string `json:"location"`
+	ProfileURL        string `json:"profile_url"`
+	Source            string `json:"source,omitempty"`
+	AlreadyContacted  bool   `json:"already_contacted"`
+	Degree            int    `json:"degree,omitempty"`
+	MutualConnections int    `json:"mutual_connections"`
+	OpenToWork        bool   `json:"open_to_work"`
+	Hiring            bool   `json:"hiring"`
+	Premium           bool   `json:"premium"`
+}
+
+func newSearchOutput(session *search.SearchSession, contactedCount int, excludeContacted bool) *searchOutput {
+	out := &searchOutput{
+		Total:           len(session.Results),
+		UniqueProfiles:  len(session.Profiles),
+		Filtered:        session.Filtered,
+		StoppedAtLimit:  session.StoppedAtLimit,
+		NextPage:        session.NextPage,
+		DurationSeconds: session.Duration.Seconds(),
+		Results:         make([]searchResult, 0, len(session.Results)),
+	}
+	if excludeContacted {
+		out.Excluded = contactedCount
+	} else {
+		out.AlreadyContacted = contactedCount
+	}
+	for _, result := range session.Results {
+		out.Results = append(out.Results, searchResult{
+			Name:             result.Name,
+			Title:            result.Title,
+			Company:          result.Company,
+			Location:         result.Location,
+			ProfileURL:       result.ProfileURL,
+			Source:           result.Source,
+			AlreadyContacted: result.AlreadyContacted,
+
+			Degree:            result.Degree,
+			MutualConnections: result.MutualConnections,
+			OpenToWork:        result.OpenToWork,
+			Hiring:            result.Hiring,
+			Premium:           result.Premium,
+		})
+	}
+	return out
+}
+
+// resultDetails summarizes the degree, mutual connections and badges a
+// search result showed, e.g. "2nd, 4 mutual connections, open to work"
+func resultDetails(result *search.SearchResult) string {
+	var details []string
+	if result.Degree > 0 {
+		details = append(details, []string{"1st", "2nd", "3rd"}[result.Degree-1])
+	}
+	switch {
+	case result.MutualConnections == 1:
+		details = append(details, "1 mutual connection")
+	case result.MutualConnections > 1:
+		details = append(details, fmt.Sprintf("%d mutual connections", result.MutualConnections))
+	}
+	for _, badge := range []struct {
+		name string
+		set  bool
+	}{{"open to work", result.OpenToWork}, {"hiring", result.Hiring}, {"Premium", result.Premium}} {
+		if badge.set {
+			details = append(details, badge.name)
+		}
+	}
+	return strings.Join(details, ", ")
+}
+
+// batchOutput reports a batch of connection requests or messages. Counts is
+// keyed by the status of the results, plus excluded for --exclude-contacted.
+type batchOutput struct {
+	BatchID        string         `json:"batch_id"`
+	Campaign       string         `json:"campaign,omitempty"`
+	DryRun         bool           `json:"dry_run"`
+	Total          int            `json:"total"`
+	Counts         map[string]int `json:"counts"`
+	StoppedAtLimit bool           `json:"stopped_at_limit"`
+	StopReason     string         `json:"stop_reason,omitempty"`
+	NotAttempted   int            `json:"not_attempted"`
+	Interrupted    bool           `json:"interrupted"`
+	Results        []batchResult  `json:"results"`
+}
+
+type batchResult struct {
+	ProfileURL string `json:"profile_url"`
+	Status     string `json:"status"` // sent, dry_run, skipped, failed or the reason it was skipped
+	Error      string `json:"error,omitempty"`
+	Message    string `json:"message,omitempty"` // Note or message after personalization
+	Variant    string `json:"variant,omitempty"`
+}
+
+func newBatchOutput(batchID string, total int) *batchOutput {
+	return &batchOutput{
+		BatchID: batchID,
+		DryRun:  dryRun,
+		Total:   total,
+		Counts:  map[string]int{},
+		Results: []batchResult{},
+	}
+}
+
+func (o *batchOutput) add(result batchResult) {
+	o.Counts[result.Status]++
+	o.Results = append(o.Results, result)
+}
+
+func newConnectOutput(batchID string, total int, batch *connect.BatchResult) *batchOutput {
+	out := newBatchOutput(batchID, total)
+	out.StoppedAtLimit, out.StopReason, out.Interrupted = batch.StoppedAtLimit, batch.StopReason, batch.Interrupted
+	out.NotAttempted = total - len(batch.Results)
+	for _, result := range batch.Results {
+		out.add(batchResult{
+			ProfileURL: result.ProfileURL,
+			Status:     connectStatus(result),
+			Error:      resultError(result.ErrorMessage, result.Err),
+			Message:    result.Message,
+			Variant:    result.Variant,
+		})
+	}
+	return out
+}
+
+func connectStatus(result *connect.ConnectionResult) string {
+	switch {
+	case result.EmailRequired:
+		return "email_required"
+	case result.Blacklisted:
+		return "blacklisted"
+	case result.Rejected:
+		return "rejected"
+	case result.Skipped:
+		return "skipped"
+	case result.DryRun:
+		return "dry_run"
+	case result.Followed:
+		return "followed"
+	case result.Messaged:
+		return "messaged"
+	case result.Success:
+		return "sent"
+	}
+	return "failed"
+}
+
+func newMessageOutput(batchID string, total int, batch *message.BatchResult) *batchOutput {
+	out := newBatchOutput(batchID, total)
+	out.StoppedAtLimit, out.StopReason, out.Interrupted = batch.StoppedAtLimit, batch.StopReason, batch.Interrupted
+	out.NotAttempted = total - len(batch.Results)
+	for _, result := range batch.Results {
+		out.add(batchResult{
+			ProfileURL: result.RecipientURL,
+			Status:     messageStatus(result),
+			Error:      resultError(result.ErrorMessage, result.Err),
+			Message:    result.Content,
+		})
+	}
+	return out
+}
+
+func messageStatus(result *message.MessageResult) string {
+	switch {
+	case result.Replied:
+		return "replied"
+	case result.Blacklisted:
+		return "blacklisted"
+	case result.Rejected:
+		return "rejected"
+	case result.Skipped:
+		return "skipped"
+	case result.DryRun:
+		return "dry_run"
+	case result.Success:
+		return "sent"
+	}
+	return "failed"
+}
+
+func resultError(message string, err error) string {
+	if message == "" && err != nil {
+		return err.Error()
+	}
+	return message
+}
+
+// statusOutput is what the status command reports
+type statusOutput struct {
+	ConfigFile     string                  `json:"config_file"`
+	Headless       bool                    `json:"headless"`
+	LinkedInEmail  string                  `json:"linkedin_email"` // Masked
+	RunningBatches []*progress.State       `json:"running_batches"`
+	Daily          map[string]int          `json:"daily"`
+	Limits         map[string]int          `json:"limits"`
+	RateLimits     []rateLimitUsage        `json:"rate_limits"`
+	InvitesBlocked *time.Time              `json:"invitations_blocked_until,omitempty"`
+	Restricted     *storage.Restriction    `json:"restricted,omitempty"` // The restriction the account is cooling down from
+	Warmup         *warmupStatus           `json:"warmup,omitempty"`
+	WeeklyInvites  *weeklyInvites          `json:"weekly_invites,omitempty"`
+	InMailCredits  *inMailCredits          `json:"inmail_credits,omitempty"`
+	Queue          map[string]int          `json:"queue"`
+	Inbox          inboxStatus             `json:"inbox"`
+	Variants       []*storage.VariantStats `json:"variants"`
+}
+
+type rateLimitUsage struct {
+	Action      string `json:"action"`
+	Daily       int    `json:"daily"` // Actions in the last 24 hours
+	DailyLimit  int    `json:"daily_limit"`
+	Hourly      int    `json:"hourly"` // Actions in the last hour
+	HourlyLimit int    `json:"hourly_limit,omitempty"`
+}
+
+type inMailCredits struct {
+	Credits   int       `json:"credits"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+type inboxStatus struct {
+	RepliesLastDay int `json:"replies_last_24h"`
+	Replied        int `json:"prospects_replied"`
+}
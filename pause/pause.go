@@ -0,0 +1,33 @@
+// Package pause waits between browser actions the way a person would, but
+// stops as soon as the run is cancelled, e.g. by Ctrl-C or a timeout.
+package pause
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+// Sleep waits for d or until ctx is done, returning ctx's error in that case
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Page waits for d or until the context of page is done. A page bound to the
+// run's context with page.Context(ctx) stops waiting when the run is cancelled.
+func Page(page *rod.Page, d time.Duration) error {
+	return Sleep(page.GetContext(), d)
+}
@@ -0,0 +1,79 @@
+package pause
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+// ErrTimeout is returned by Until when the condition did not hold in time
+var ErrTimeout = errors.New("timed out waiting for the page")
+
+// Waits are how long browser steps wait for the page. Slow connections and
+// machines need longer ones than the defaults.
+type Waits struct {
+	ElementTimeout time.Duration // How long to wait for an element before giving up
+	PollInterval   time.Duration // How often to look for it meanwhile
+	Settle         time.Duration // Pause after a click or page change for the page to update
+}
+
+// DefaultWaits are used for any wait left unset
+var DefaultWaits = Waits{
+	ElementTimeout: 10 * time.Second,
+	PollInterval:   500 * time.Millisecond,
+	Settle:         time.Second,
+}
+
+var (
+	mu      sync.RWMutex
+	current = DefaultWaits
+)
+
+// SetWaits replaces the waits in effect; zero fields keep their defaults
+func SetWaits(waits Waits) {
+	if waits.ElementTimeout <= 0 {
+		waits.ElementTimeout = DefaultWaits.ElementTimeout
+	}
+	if waits.PollInterval <= 0 {
+		waits.PollInterval = DefaultWaits.PollInterval
+	}
+	if waits.Settle <= 0 {
+		waits.Settle = DefaultWaits.Settle
+	}
+
+	mu.Lock()
+	current = waits
+	mu.Unlock()
+}
+
+// Current returns the waits in effect
+func Current() Waits {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Settle gives the page time to update after a click or page change
+func Settle(page *rod.Page) error {
+	return Page(page, Current().Settle)
+}
+
+// Until checks done every poll interval until it reports true, returning
+// ErrTimeout once timeout has passed without it, or the page context's error
+// if that ends first
+func Until(page *rod.Page, timeout time.Duration, done func() bool) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		if done() {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrTimeout
+		}
+		if err := Page(page, Current().PollInterval); err != nil {
+			return err
+		}
+	}
+}
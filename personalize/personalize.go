@@ -0,0 +1,442 @@
+package personalize
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/opener"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/selectors"
+	"linkedin-automation/storage"
+	"linkedin-automation/voyager"
+)
+
+// Personalizer fills template variables from stored or scraped profile data
+type Personalizer struct {
+	store   ProfileStore
+	fetcher ProfileFetcher
+	opener  OpenerWriter
+	custom  map[string]map[string]string // Variables given per profile, by canonical URL
+	logger  *logrus.Logger
+}
+
+// ProfileStore provides cached profile data
+type ProfileStore interface {
+	GetProfile(url string) (*storage.Profile, error)
+	SaveProfile(profile *storage.Profile) error
+	RecordProfileChanges(profile *storage.Profile, scrapedAt time.Time) ([]*storage.ProfileChange, error)
+}
+
+// ProfileFetcher loads profile data without visiting the profile page
+type ProfileFetcher interface {
+	GetProfile(ctx context.Context, profileURL string) (*voyager.Profile, error)
+}
+
+// OpenerWriter writes the {{ai_opener}} line for a prospect
+type OpenerWriter interface {
+	Opener(ctx context.Context, prospect *opener.Prospect) (string, error)
+}
+
+// ProfileData holds the profile fields available to templates
+type ProfileData struct {
+	URL        string
+	Name       string
+	FirstName  string
+	LastName   string
+	Headline   string
+	Title      string
+	Company    string
+	Location   string
+	Industry   string
+	About      string // Scraped for {{ai_opener}} only, and not stored
+	RecentPost string
+}
+
+// ProfileVariables are the variables filled from profile data
+var ProfileVariables = []string{"name", "first_name", "last_name", "full_name", "headline", "title", "company", "location", "industry", "field"}
+
+// IsProfileVariable reports whether name is filled from profile data or is
+// the AI opener, rather than having to be given per profile
+func IsProfileVariable(name string) bool {
+	if name == OpenerVariable {
+		return true
+	}
+	for _, variable := range ProfileVariables {
+		if name == variable {
+			return true
+		}
+	}
+	return false
+}
+
+// fallbacks are used when a variable cannot be resolved, so a note never goes
+// out with a literal placeholder in it
+var fallbacks = map[string]string{
+	"name":       "there",
+	"first_name": "there",
+	"company":    "your company",
+	"industry":   "your field",
+	"field":      "your field",
+	"title":      "your role",
+	"headline":   "your work",
+	"location":   "your area",
+}
+
+// NewPersonalizer creates a new personalizer
+func NewPersonalizer(store ProfileStore, logger *logrus.Logger) *Personalizer {
+	return &Personalizer{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// SetProfileFetcher fills gaps in stored profile data through the API before
+// falling back to scraping the page
+func (p *Personalizer) SetProfileFetcher(fetcher ProfileFetcher) {
+	p.fetcher = fetcher
+}
+
+// SetOpener enables {{ai_opener}}, a first line written by a language model.
+// Without it the variable is left empty.
+func (p *Personalizer) SetOpener(writer OpenerWriter) {
+	p.opener = writer
+}
+
+// SetVariables gives the profile at profileURL variables of its own, e.g.
+// the columns of its row in a CSV file. They are filled in over the ones from
+// profile data; nil removes them.
+func (p *Personalizer) SetVariables(profileURL string, variables map[string]string) {
+	key := profileurl.Canonicalize(profileURL)
+	if len(variables) == 0 {
+		delete(p.custom, key)
+		return
+	}
+	if p.custom == nil {
+		p.custom = make(map[string]map[string]string)
+	}
+	p.custom[key] = variables
+}
+
+// Personalize renders the template in content for the given profile.
+// Stored profile data is preferred; if it is incomplete and the page is
+// currently showing the profile, the page is scraped and the result cached.
+// The API fetch and the AI opener give up when ctx is done.
+func (p *Personalizer) Personalize(ctx context.Context, page *rod.Page, profileURL, content string) string {
+	if !strings.Contains(content, "{") {
+		return content
+	}
+
+	data := p.profileData(ctx, page, profileURL, usesIndustry(content))
+	variables := data.Variables()
+	for key, value := range p.custom[profileurl.Canonicalize(profileURL)] {
+		if value != "" {
+			variables[key] = value
+		}
+	}
+	withOpener := UsesOpener(content)
+	if withOpener {
+		if line := p.aiOpener(ctx, page, data); line != "" {
+			variables[OpenerVariable] = line
+		}
+	}
+
+	result, err := Render(content, variables)
+	if err != nil {
+		p.logger.WithError(err).Warn("Failed to render template, falling back to plain substitution")
+		result = Fill(content, variables)
+	}
+	if withOpener {
+		// An empty opener on its own line would leave the message starting blank
+		result = strings.TrimSpace(result)
+	}
+
+	p.logger.WithFields(logrus.Fields{
+		"profile_url": profileURL,
+		"name":        data.Name,
+		"company":     data.Company,
+	}).Debug("Personalized message")
+
+	return result
+}
+
+// ProfileData resolves profile data from storage, the API, the current page, or the profile URL
+func (p *Personalizer) ProfileData(ctx context.Context, page *rod.Page, profileURL string) *ProfileData {
+	return p.profileData(ctx, page, profileURL, false)
+}
+
+// usesIndustry reports whether content inserts the industry, which stored
+// profiles found by searches lack
+func usesIndustry(content string) bool {
+	for _, name := range Placeholders(content) {
+		if name == "industry" || name == "field" {
+			return true
+		}
+	}
+	return false
+}
+
+// profileData resolves profile data like ProfileData, also looking further
+// than storage when the industry is wanted and not stored
+func (p *Personalizer) profileData(ctx context.Context, page *rod.Page, profileURL string, industry bool) *ProfileData {
+	data := &ProfileData{URL: profileURL}
+	incomplete := func() bool {
+		return data.Name == "" || data.Company == "" || data.Headline == "" || (industry && data.Industry == "")
+	}
+
+	if p.store != nil {
+		profile, err := p.store.GetProfile(profileURL)
+		if err != nil {
+			p.logger.WithError(err).Warn("Failed to load stored profile")
+		} else if profile != nil {
+			data.Name = profile.Name
+			data.Title = profile.Title
+			data.Headline = profile.Headline
+			data.Company = profile.Company
+			data.Location = profile.Location
+			data.Industry = profile.Industry
+		}
+	}
+
+	if incomplete() && p.fetcher != nil {
+		fetchCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		profile, err := p.fetcher.GetProfile(fetchCtx, profileURL)
+		cancel()
+		if err != nil {
+			p.logger.WithError(err).Debug("Failed to fetch profile data from API")
+		} else {
+			fetched := &ProfileData{
+				Name:     profile.Name(),
+				Headline: profile.Headline,
+				Company:  profile.Company,
+				Location: profile.Location,
+				Industry: profile.Industry,
+			}
+			p.recordChanges(profileURL, fetched)
+			data.merge(fetched)
+			p.cache(data)
+		}
+	}
+
+	if incomplete() && page != nil && isOnProfile(page, profileURL) {
+		scraped, err := ScrapeProfile(page)
+		if err != nil {
+			p.logger.WithError(err).Debug("Failed to scrape profile data")
+		} else {
+			p.recordChanges(profileURL, scraped)
+			data.merge(scraped)
+			p.cache(data)
+		}
+	}
+
+	if data.Name == "" {
+		data.Name = nameFromURL(profileURL)
+	}
+	if data.Headline == "" {
+		data.Headline = data.Title
+	}
+	if data.Title == "" {
+		data.Title = data.Headline
+	}
+	data.FirstName, data.LastName = splitName(data.Name)
+
+	return data
+}
+
+// aiOpener writes the opener for a prospect, scraping the about section and
+// recent post when the page is showing the profile. Failures only leave the
+// opener empty, so the message still goes out.
+func (p *Personalizer) aiOpener(ctx context.Context, page *rod.Page, data *ProfileData) string {
+	if p.opener == nil {
+		return ""
+	}
+
+	if data.About == "" && data.RecentPost == "" && page != nil && isOnProfile(page, data.URL) {
+		data.About = firstText(page, selectors.Get(selectors.ProfileAbout)...)
+		data.RecentPost = firstText(page, selectors.Get(selectors.ProfileRecentPost)...)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 90*time.Second)
+	defer cancel()
+	line, err := p.opener.Opener(ctx, &opener.Prospect{
+		ProfileURL: data.URL,
+		Name:       data.Name,
+		Headline:   data.Headline,
+		Company:    data.Company,
+		About:      data.About,
+		RecentPost: data.RecentPost,
+	})
+	if err != nil {
+		p.logger.WithError(err).WithField("profile_url", data.URL).Warn("Failed to write AI opener, leaving it empty")
+		return ""
+	}
+	return line
+}
+
+// Variables returns the template variables for the profile, leaving unknown ones unset
+func (d *ProfileData) Variables() map[string]string {
+	vars := map[string]string{
+		"name":       d.FirstName,
+		"first_name": d.FirstName,
+		"last_name":  d.LastName,
+		"full_name":  d.Name,
+		"headline":   d.Headline,
+		"title":      d.Title,
+		"company":    d.Company,
+		"location":   d.Location,
+		"industry":   d.Industry,
+		"field":      d.Industry,
+	}
+
+	for key, value := range vars {
+		if value == "" {
+			delete(vars, key)
+		}
+	}
+	return vars
+}
+
+// Fill renders content with variables. If the content is not a valid
+// template, {{key}} placeholders are replaced literally instead, using neutral
+// fallbacks for well-known keys that could not be resolved.
+func Fill(content string, variables map[string]string) string {
+	if result, err := Render(content, variables); err == nil {
+		return result
+	}
+
+	result := content
+
+	for key, value := range variables {
+		result = strings.ReplaceAll(result, fmt.Sprintf("{{%s}}", key), value)
+	}
+	for key, value := range fallbacks {
+		result = strings.ReplaceAll(result, fmt.Sprintf("{{%s}}", key), value)
+	}
+
+	return result
+}
+
+// ScrapeProfile extracts profile data from a loaded profile page
+func ScrapeProfile(page *rod.Page) (*ProfileData, error) {
+	data := &ProfileData{
+		Name:     firstText(page, selectors.Get(selectors.ProfileName)...),
+		Headline: firstText(page, selectors.Get(selectors.ProfileHeadline)...),
+		Location: firstText(page, selectors.Get(selectors.ProfileLocation)...),
+		Company:  firstText(page, selectors.Get(selectors.ProfileCompany)...),
+		Industry: firstText(page, selectors.Get(selectors.ProfileIndustry)...),
+	}
+
+	if data.Name == "" {
+		return nil, fmt.Errorf("profile name not found on page")
+	}
+
+	return data, nil
+}
+
+// recordChanges records the title, company and location a fetch or scrape
+// just read, before stored values fill its gaps, so job changes show up in
+// the profile history
+func (p *Personalizer) recordChanges(profileURL string, fresh *ProfileData) {
+	if p.store == nil {
+		return
+	}
+
+	profile := &storage.Profile{URL: profileURL, Title: fresh.Title, Company: fresh.Company, Location: fresh.Location}
+	if _, err := p.store.RecordProfileChanges(profile, time.Now()); err != nil {
+		p.logger.WithError(err).Warn("Failed to record profile changes")
+	}
+}
+
+func (p *Personalizer) cache(data *ProfileData) {
+	if p.store == nil {
+		return
+	}
+
+	profile := &storage.Profile{
+		URL:      data.URL,
+		Name:     data.Name,
+		Title:    data.Title,
+		Headline: data.Headline,
+		Company:  data.Company,
+		Location: data.Location,
+		Industry: data.Industry,
+	}
+	if existing, err := p.store.GetProfile(data.URL); err == nil && existing != nil {
+		profile.SearchQuery = existing.SearchQuery
+	}
+
+	if err := p.store.SaveProfile(profile); err != nil {
+		p.logger.WithError(err).Warn("Failed to cache scraped profile")
+	}
+}
+
+func (d *ProfileData) merge(other *ProfileData) {
+	if d.Name == "" {
+		d.Name = other.Name
+	}
+	if d.Headline == "" {
+		d.Headline = other.Headline
+	}
+	if d.Company == "" {
+		d.Company = other.Company
+	}
+	if d.Location == "" {
+		d.Location = other.Location
+	}
+	if d.Industry == "" {
+		d.Industry = other.Industry
+	}
+}
+
+// firstText returns the trimmed text of the first matching selector without waiting
+func firstText(page *rod.Page, candidates ...string) string {
+	for _, selector := range candidates {
+		elements, err := page.Elements(selector)
+		if err != nil || len(elements) == 0 {
+			continue
+		}
+		text, err := elements[0].Text()
+		if err == nil && strings.TrimSpace(text) != "" {
+			return strings.TrimSpace(text)
+		}
+	}
+	return ""
+}
+
+func isOnProfile(page *rod.Page, profileURL string) bool {
+	info, err := page.Info()
+	if err != nil || info == nil {
+		return false
+	}
+	return profileurl.Slug(info.URL) != "" && profileurl.Slug(info.URL) == profileurl.Slug(profileURL)
+}
+
+// nameFromURL guesses a display name from a profile slug such as john-doe-1a2b3c
+func nameFromURL(profileURL string) string {
+	slug := profileurl.Slug(profileURL)
+	if slug == "" {
+		return ""
+	}
+
+	var words []string
+	for _, word := range strings.Split(slug, "-") {
+		// Skip the numeric/hex suffix LinkedIn appends to duplicate names
+		if word == "" || strings.ContainsAny(word, "0123456789") {
+			continue
+		}
+		words = append(words, strings.ToUpper(word[:1])+word[1:])
+	}
+	return strings.Join(words, " ")
+}
+
+func splitName(name string) (string, string) {
+	parts := strings.Fields(name)
+	if len(parts) == 0 {
+		return "", ""
+	}
+	return parts[0], strings.Join(parts[1:], " ")
+}
@@ -0,0 +1,209 @@
+package personalize
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+var (
+	// legacyPattern matches the original {{key}} placeholder syntax
+	legacyPattern = regexp.MustCompile(`{{\s*([a-z_][a-z0-9_]*)\s*}}`)
+	// actionPattern matches a text/template action
+	actionPattern = regexp.MustCompile(`(?s){{.*?}}`)
+	// fieldPattern matches a field reference such as .FirstName inside an action
+	fieldPattern = regexp.MustCompile(`\.([A-Za-z_][A-Za-z0-9_]*)`)
+	// stringPattern matches a quoted string literal inside an action
+	stringPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|` + "`[^`]*`")
+	// spinPattern matches an innermost {option A|option B} group
+	spinPattern = regexp.MustCompile(`{([^{}]*\|[^{}]*)}`)
+)
+
+// keywords are bare action words that must not be treated as legacy placeholders
+var keywords = map[string]bool{
+	"end":      true,
+	"else":     true,
+	"break":    true,
+	"continue": true,
+	"nil":      true,
+	"true":     true,
+	"false":    true,
+}
+
+var funcs = template.FuncMap{
+	"default": func(def string, value interface{}) string {
+		if s := fmt.Sprint(value); value != nil && s != "" {
+			return s
+		}
+		return def
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// Render executes content as a Go text/template against variables, then
+// resolves spin syntax such as {Hi|Hello}. Variables are available both by
+// their snake_case key ({{.first_name}}) and in CamelCase ({{.FirstName}});
+// the original {{first_name}} form is still accepted and falls back to
+// neutral wording when unresolved.
+func Render(content string, variables map[string]string) (string, error) {
+	tmpl, err := parse(content)
+	if err != nil {
+		return "", err
+	}
+
+	data := make(map[string]string, len(variables)*2)
+	for key, value := range variables {
+		data[key] = value
+		data[camelCase(key)] = value
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+
+	return Spin(buf.String()), nil
+}
+
+// OpenerVariable is the template variable the AI opener is inserted as
+const OpenerVariable = "ai_opener"
+
+// UsesOpener reports whether content inserts the AI opener
+func UsesOpener(content string) bool {
+	for _, name := range Placeholders(content) {
+		if name == OpenerVariable {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that content is a well-formed template
+func Validate(content string) error {
+	_, err := parse(content)
+	return err
+}
+
+// Spin picks one option at random from each {option A|option B} group,
+// resolving nested groups from the inside out
+func Spin(content string) string {
+	for {
+		result := spinPattern.ReplaceAllStringFunc(content, func(group string) string {
+			options := strings.Split(group[1:len(group)-1], "|")
+			return options[rand.Intn(len(options))]
+		})
+		if result == content {
+			return result
+		}
+		content = result
+	}
+}
+
+// Placeholders returns the distinct snake_case variable names referenced by
+// content, in order of appearance
+func Placeholders(content string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		name = snakeCase(name)
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	for _, action := range actionPattern.FindAllString(content, -1) {
+		if match := legacyPattern.FindStringSubmatch(action); match != nil {
+			if !keywords[match[1]] {
+				add(match[1])
+			}
+			continue
+		}
+		action = stringPattern.ReplaceAllString(action, "")
+		for _, match := range fieldPattern.FindAllStringSubmatch(action, -1) {
+			add(match[1])
+		}
+	}
+	return names
+}
+
+// StaticLength returns the length of content with template actions removed
+// and each spin group counted at its longest option
+func StaticLength(content string) int {
+	text := actionPattern.ReplaceAllString(content, "")
+	for {
+		result := spinPattern.ReplaceAllStringFunc(text, func(group string) string {
+			longest := ""
+			for _, option := range strings.Split(group[1:len(group)-1], "|") {
+				if len([]rune(option)) > len([]rune(longest)) {
+					longest = option
+				}
+			}
+			return longest
+		})
+		if result == text {
+			break
+		}
+		text = result
+	}
+	return len([]rune(text))
+}
+
+func parse(content string) (*template.Template, error) {
+	tmpl, err := template.New("message").
+		Funcs(funcs).
+		Option("missingkey=zero").
+		Parse(upgradeLegacy(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// upgradeLegacy rewrites {{key}} placeholders into template actions, keeping
+// the neutral fallbacks the original syntax had
+func upgradeLegacy(content string) string {
+	return legacyPattern.ReplaceAllStringFunc(content, func(placeholder string) string {
+		key := legacyPattern.FindStringSubmatch(placeholder)[1]
+		if keywords[key] {
+			return placeholder
+		}
+		if fallback, ok := fallbacks[key]; ok {
+			return fmt.Sprintf("{{or .%s %s}}", key, strconv.Quote(fallback))
+		}
+		return fmt.Sprintf("{{.%s}}", key)
+	})
+}
+
+// camelCase converts first_name to FirstName
+func camelCase(key string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(key, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// snakeCase converts FirstName to first_name
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 && name[i-1] != '_' {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,119 @@
+package personalize
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		variables map[string]string
+		want      string
+		wantErr   bool
+	}{
+		{"legacy placeholder", "Hi {{first_name}}!", map[string]string{"first_name": "Ann"}, "Hi Ann!", false},
+		{"legacy fallback", "Hi {{first_name}}, how is {{company}}?", nil, "Hi there, how is your company?", false},
+		{"legacy without fallback", "Hi{{nickname}}", nil, "Hi", false},
+		{"snake case field", "{{.first_name}} at {{.company}}", map[string]string{"first_name": "Ann", "company": "Acme"}, "Ann at Acme", false},
+		{"camel case field", "{{.FirstName}} at {{.Company}}", map[string]string{"first_name": "Ann", "company": "Acme"}, "Ann at Acme", false},
+		{"missing field", "Hi {{.first_name}}", nil, "Hi ", false},
+		{"or fallback", `{{or .ai_opener "Hello"}} Ann`, nil, "Hello Ann", false},
+		{"default func", `Hi {{default "friend" .first_name}}`, nil, "Hi friend", false},
+		{"upper and lower funcs", "{{upper .company}} {{lower .title}}", map[string]string{"company": "Acme", "title": "CTO"}, "ACME cto", false},
+		{"conditional", "{{if .company}}at {{.company}}{{else}}hi{{end}}", map[string]string{"company": "Acme"}, "at Acme", false},
+		{"spin with one outcome", "{Hi|Hi} {{first_name}}", map[string]string{"first_name": "Ann"}, "Hi Ann", false},
+		{"unclosed action", "{{if .company}}at {{.company}}", nil, "", true},
+		{"unknown function", "{{shout .company}}", nil, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.content, tt.variables)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render(%q) error = %v, wantErr %v", tt.content, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Render(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpin(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string // Any of these is a correct outcome
+	}{
+		{"no groups", "Hi there", []string{"Hi there"}},
+		{"braces without options", "Hi {there}", []string{"Hi {there}"}},
+		{"one group", "{Hi|Hello} there", []string{"Hi there", "Hello there"}},
+		{"two groups", "{Hi|Hey} {Ann|there}", []string{"Hi Ann", "Hi there", "Hey Ann", "Hey there"}},
+		{"nested group", "{Hi|{Hello|Hey}}!", []string{"Hi!", "Hello!", "Hey!"}},
+		{"empty option", "Hi{!|}", []string{"Hi!", "Hi"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Spin is random, so give every option a chance to come up
+			for i := 0; i < 20; i++ {
+				got := Spin(tt.content)
+				found := false
+				for _, want := range tt.want {
+					if got == want {
+						found = true
+					}
+				}
+				if !found {
+					t.Fatalf("Spin(%q) = %q, want one of %q", tt.content, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestPlaceholders(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"none", "Hi there", nil},
+		{"legacy", "Hi {{first_name}} at {{ company }}", []string{"first_name", "company"}},
+		{"fields in order", "{{.Company}} {{.first_name}} {{.company}}", []string{"company", "first_name"}},
+		{"camel case to snake case", "{{.FirstName}} {{.RecentPost}}", []string{"first_name", "recent_post"}},
+		{"inside actions", `{{if .title}}{{upper .title}}{{else}}{{default "there" .name}}{{end}}`, []string{"title", "name"}},
+		{"string literals ignored", `{{or .ai_opener "see .company"}}`, []string{"ai_opener"}},
+		{"keywords ignored", "{{end}} {{else}} {{nil}}", nil},
+		{"spin groups ignored", "{Hi|Hello} {{first_name}}", []string{"first_name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Placeholders(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Placeholders(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStaticLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{"plain text", "Hello", 5},
+		{"actions removed", "Hi {{first_name}}!", 4},
+		{"longest spin option", "{Hi|Hello} there", 11},
+		{"nested spin", "{a|{bb|ccc}}", 3},
+		{"counted in runes", "Grüße {{.name}}", 6},
+		{"actions inside spin", "{Hi {{.name}}|Hey}", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StaticLength(tt.content); got != tt.want {
+				t.Errorf("StaticLength(%q) = %d, want %d", tt.content, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package profileurl
+
+import (
+	"net/url"
+	"strings"
+)
+
+const baseURL = "https://www.linkedin.com/in/"
+
+// Canonicalize normalizes a LinkedIn profile URL so that variants such as
+// /in/john-doe/, /in/John-Doe?miniProfileUrn=... and uk.linkedin.com/in/john-doe
+// all map to https://www.linkedin.com/in/john-doe/. URLs that are not member
+// profiles are returned trimmed, without their query string or fragment.
+func Canonicalize(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+
+	if slug := Slug(raw); slug != "" {
+		return baseURL + url.PathEscape(slug) + "/"
+	}
+
+	parsed, err := parse(raw)
+	if err != nil {
+		return raw
+	}
+	parsed.RawQuery = ""
+	parsed.Fragment = ""
+	return parsed.String()
+}
+
+// Slug returns the lower-cased /in/<slug> part of a profile URL, or an empty
+// string if the URL is not a member profile
+func Slug(raw string) string {
+	parsed, err := parse(strings.TrimSpace(raw))
+	if err != nil {
+		return ""
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if host != "" && host != "linkedin.com" && !strings.HasSuffix(host, ".linkedin.com") {
+		return ""
+	}
+
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	for i, part := range parts {
+		if part == "in" && i+1 < len(parts) && parts[i+1] != "" {
+			return strings.ToLower(parts[i+1])
+		}
+	}
+	return ""
+}
+
+// Equal reports whether two URLs refer to the same profile
+func Equal(a, b string) bool {
+	return Canonicalize(a) == Canonicalize(b)
+}
+
+// Dedupe canonicalizes urls and drops duplicates, keeping the first occurrence
+func Dedupe(urls []string) []string {
+	result := make([]string, 0, len(urls))
+	seen := make(map[string]bool)
+	for _, raw := range urls {
+		canonical := Canonicalize(raw)
+		if canonical == "" || seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+		result = append(result, canonical)
+	}
+	return result
+}
+
+// parse accepts URLs with or without a scheme (e.g. linkedin.com/in/john-doe)
+func parse(raw string) (*url.URL, error) {
+	if !strings.Contains(raw, "://") && !strings.HasPrefix(raw, "/") {
+		raw = "https://" + raw
+	}
+	return url.Parse(raw)
+}
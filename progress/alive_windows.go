@@ -0,0 +1,14 @@
+package progress
+
+import "os"
+
+// processAlive reports whether a process with the ID is running; on Windows
+// finding a process opens it, which fails once it has exited
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	process.Release()
+	return true
+}
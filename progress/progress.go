@@ -0,0 +1,202 @@
+// Package progress keeps a state file per running batch, so other processes
+// such as 'status --watch' can show how far the batch has got.
+package progress
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// unsafeChars are replaced in batch IDs to form state file names
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// State is the progress of one running batch
+type State struct {
+	BatchID      string    `json:"batch_id"`
+	Action       string    `json:"action"` // connect or message
+	PID          int       `json:"pid"`
+	Total        int       `json:"total"`
+	Processed    int       `json:"processed"`
+	Succeeded    int       `json:"succeeded"`
+	Failed       int       `json:"failed"`
+	Skipped      int       `json:"skipped"`
+	Current      string    `json:"current,omitempty"` // Profile being worked on
+	WaitReason   string    `json:"wait_reason,omitempty"`
+	WaitingUntil time.Time `json:"waiting_until,omitempty"`
+	StartedAt    time.Time `json:"started_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ETA estimates the time left from the average time per attempted profile;
+// ok is false until a profile has been attempted
+func (s *State) ETA() (eta time.Duration, ok bool) {
+	attempted := s.Succeeded + s.Failed
+	if attempted == 0 {
+		return 0, false
+	}
+	perProfile := s.UpdatedAt.Sub(s.StartedAt) / time.Duration(attempted)
+	return perProfile * time.Duration(s.Total-s.Processed), true
+}
+
+// Waiting returns how long the batch is still held back by a limiter
+func (s *State) Waiting() time.Duration {
+	if s.WaitingUntil.IsZero() {
+		return 0
+	}
+	if wait := time.Until(s.WaitingUntil); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// Tracker updates the state file of a running batch. It is safe for the
+// concurrent use of batches worked in several tabs.
+type Tracker struct {
+	mu     sync.Mutex
+	path   string
+	state  State
+	logger *logrus.Logger
+	failed bool // A write failed; later failures are not logged again
+}
+
+// NewTracker starts tracking a batch of total profiles in dir
+func NewTracker(dir, batchID, action string, total int, logger *logrus.Logger) *Tracker {
+	now := time.Now()
+	t := &Tracker{
+		path: filepath.Join(dir, unsafeChars.ReplaceAllString(batchID, "_")+".json"),
+		state: State{
+			BatchID:   batchID,
+			Action:    action,
+			PID:       os.Getpid(),
+			Total:     total,
+			StartedAt: now,
+			UpdatedAt: now,
+		},
+		logger: logger,
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.warn(err)
+	}
+	t.mu.Lock()
+	t.save()
+	t.mu.Unlock()
+	return t
+}
+
+// Processing records the profile the batch is working on
+func (t *Tracker) Processing(profileURL string) {
+	t.update(func(s *State) {
+		s.Current = profileURL
+	})
+}
+
+// Processed counts a finished profile
+func (t *Tracker) Processed(success, skipped bool) {
+	t.update(func(s *State) {
+		s.Processed++
+		switch {
+		case skipped:
+			s.Skipped++
+		case success:
+			s.Succeeded++
+		default:
+			s.Failed++
+		}
+		s.WaitReason, s.WaitingUntil = "", time.Time{}
+	})
+}
+
+// Waiting records a limiter holding the batch back; it is a ratelimit.WaitObserver
+func (t *Tracker) Waiting(reason string, until time.Time) {
+	t.update(func(s *State) {
+		s.WaitReason, s.WaitingUntil = reason, until
+	})
+}
+
+// Finish removes the state file once the batch has ended
+func (t *Tracker) Finish() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err := os.Remove(t.path); err != nil && !os.IsNotExist(err) {
+		t.warn(err)
+	}
+}
+
+func (t *Tracker) update(change func(s *State)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	change(&t.state)
+	t.state.UpdatedAt = time.Now()
+	t.save()
+}
+
+// save writes the state through a temporary file, so readers never see half of it
+func (t *Tracker) save() {
+	data, err := json.MarshalIndent(t.state, "", "  ")
+	if err != nil {
+		t.warn(err)
+		return
+	}
+
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		t.warn(err)
+		return
+	}
+	if err := os.Rename(tmp, t.path); err != nil {
+		t.warn(err)
+	}
+}
+
+func (t *Tracker) warn(err error) {
+	if t.failed {
+		return
+	}
+	t.failed = true
+	t.logger.WithError(err).Warn("Failed to write batch progress; status will not show this batch")
+}
+
+// List returns the batches running in dir, oldest first. State files left by
+// processes that have exited are removed.
+func List(dir string) ([]*State, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var states []*State
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue // Finished since the directory was listed
+			}
+			return nil, err
+		}
+
+		var state State
+		if err := json.Unmarshal(data, &state); err != nil {
+			continue
+		}
+		if state.PID <= 0 || !processAlive(state.PID) {
+			os.Remove(path)
+			continue
+		}
+		states = append(states, &state)
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].StartedAt.Before(states[j].StartedAt)
+	})
+	return states, nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"linkedin-automation/config"
+	"linkedin-automation/logger"
+	"linkedin-automation/progress"
+	"linkedin-automation/ratelimit"
+)
+
+// startProgress begins reporting a batch to status. The returned context
+// reports the batch's rate limit waits and breaks, and Finish must be called
+// once the batch has ended.
+func startProgress(ctx context.Context, cfg *config.Config, batchID, action string, total int) (context.Context, *progress.Tracker) {
+	tracker := progress.NewTracker(cfg.Storage.ProgressDir, batchID, action, total, logger.GetLogger())
+	return ratelimit.WithWaitObserver(ctx, tracker.Waiting), tracker
+}
+
+// printRunningBatches prints the progress of batches running in other processes
+func printRunningBatches(dir string) error {
+	states, err := progress.List(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read batch progress: %w", err)
+	}
+
+	fmt.Printf("Running Batches:\n")
+	if len(states) == 0 {
+		fmt.Printf("  None\n")
+		return nil
+	}
+	for _, state := range states {
+		fmt.Printf("  %s (%s, pid %d, started %s)\n", state.BatchID, state.Action, state.PID, state.StartedAt.Local().Format("15:04"))
+		percent := 0.0
+		if state.Total > 0 {
+			percent = float64(state.Processed) / float64(state.Total) * 100
+		}
+		fmt.Printf("    Progress: %d/%d (%.0f%%), %d succeeded, %d failed, %d skipped\n",
+			state.Processed, state.Total, percent, state.Succeeded, state.Failed, state.Skipped)
+		if state.Current != "" && state.Processed < state.Total {
+			fmt.Printf("    Current: %s\n", state.Current)
+		}
+		if wait := state.Waiting(); wait > 0 {
+			fmt.Printf("    Waiting: %s, %s left\n", state.WaitReason, wait.Round(time.Second))
+		}
+		if eta, ok := state.ETA(); ok {
+			fmt.Printf("    ETA: %s\n", eta.Round(time.Minute))
+		} else {
+			fmt.Printf("    ETA: unknown until the first profile is done\n")
+		}
+	}
+	return nil
+}
+
+// watchBatches redraws the running batches every interval until ctx is done
+func watchBatches(ctx context.Context, dir string, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fmt.Printf("\033[H\033[2J")
+		fmt.Printf("Updated %s, press Ctrl-C to stop\n\n", time.Now().Format("15:04:05"))
+		if err := printRunningBatches(dir); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"time"
+
+	"linkedin-automation/config"
+	"linkedin-automation/storage"
+)
+
+// applyProspectHours leaves out the profiles outside their working hours when
+// stealth.schedule.prospect_timezone is on, returning the rest and how many
+// were left out. Running the batch again with --resume later picks those up.
+func applyProspectHours(cfg *config.Config, db *storage.Database, profiles []string) ([]string, int, error) {
+	hours := newClient(cfg, db).ProspectHours()
+	if hours == nil {
+		return profiles, 0, nil
+	}
+
+	now := time.Now()
+	kept := make([]string, 0, len(profiles))
+	for _, profileURL := range profiles {
+		next, err := hours.Next(profileURL, now)
+		if err != nil {
+			return nil, 0, err
+		}
+		if next.After(now) {
+			continue
+		}
+		kept = append(kept, profileURL)
+	}
+	return kept, len(profiles) - len(kept), nil
+}
@@ -0,0 +1,629 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/errs"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/search"
+	"linkedin-automation/storage"
+)
+
+// Task kinds
+const (
+	KindConnect = "connect"
+	KindMessage = "message"
+	KindSearch  = "search"
+	KindSync    = "sync"
+)
+
+// pausedRecheck is how long a task of a paused campaign waits before it is looked at again
+const pausedRecheck = 5 * time.Minute
+
+// A worker renews the lease on the task it runs every heartbeatInterval; one
+// not renewed for leaseTimeout belongs to a worker that died and is re-queued
+const (
+	heartbeatInterval = time.Minute
+	leaseTimeout      = 5 * time.Minute
+)
+
+// ErrPaused is returned by handlers for a task of a paused campaign. The task
+// is deferred without using up an attempt, so it runs once the campaign resumes.
+var ErrPaused = errors.New("campaign is paused")
+
+// ErrHeld is returned by handlers for a task that must wait for the operator,
+// such as one inserting an {{ai_opener}} not yet previewed. The task is
+// deferred like a paused one and looked at again later.
+var ErrHeld = errors.New("task is held")
+
+// ErrNoOverlap is returned by NextRun for a task whose prospect's working hours
+// never fall within the operator's business hours, so it could never run.
+var ErrNoOverlap = errors.New("the operator's business hours never overlap the prospect's working hours")
+
+// Store persists queued tasks
+type Store interface {
+	EnqueueTask(task *storage.QueueTask) error
+	ClaimNextTask(now time.Time, worker string) (*storage.QueueTask, error)
+	HeartbeatTask(id int, worker string, now time.Time) error
+	CompleteTask(id int) error
+	FailTask(id int, lastError string) error
+	RescheduleTask(id int, at time.Time, lastError string) error
+	DeferTask(id int, until time.Time, reason string) error
+	ResetRunningTasks(staleBefore time.Time) (int, error)
+	ListTasks(status string) ([]*storage.QueueTask, error)
+}
+
+// ConnectPayload holds the parameters of a queued connection request
+type ConnectPayload struct {
+	ProfileURL string `json:"profile_url"`
+	Message    string `json:"message"`
+	Campaign   string `json:"campaign,omitempty"`
+	Variant    string `json:"variant,omitempty"`  // A/B variant assigned when the task was queued
+	Template   string `json:"template,omitempty"` // Template the message was rendered from
+
+	Variables map[string]string `json:"variables,omitempty"` // Template variables of this profile, e.g. from --input-csv
+}
+
+// MessagePayload holds the parameters of a queued message
+type MessagePayload struct {
+	RecipientURL string `json:"recipient_url"`
+	Content      string `json:"content"`
+	Template     string `json:"template,omitempty"` // Template the content was rendered from
+
+	Variables map[string]string `json:"variables,omitempty"` // Template variables of this recipient, e.g. from --input-csv
+}
+
+// SearchPayload holds the parameters of a queued search
+type SearchPayload struct {
+	Query            search.SearchQuery `json:"query"`
+	Output           string             `json:"output,omitempty"`
+	ExcludeContacted bool               `json:"exclude_contacted,omitempty"`
+}
+
+// SyncPayload holds the parameters of a queued sync of accepted connections and the inbox
+type SyncPayload struct {
+	InboxLimit int `json:"inbox_limit,omitempty"` // Conversations to scan; 0 scans every listed one
+}
+
+// Options control when and in which order a task runs
+type Options struct {
+	Priority    int       // Higher priorities run first
+	ScheduledAt time.Time // Zero means as soon as possible
+}
+
+// Scheduler adds tasks that have become due, such as the steps of a sequence.
+// The worker calls it before claiming each task.
+type Scheduler interface {
+	Schedule(ctx context.Context, now time.Time) error
+}
+
+// Timing decides when an action for a prospect may run, such as in their
+// working hours. Next returns now if it may run straight away.
+type Timing interface {
+	Next(profileURL string, now time.Time) (time.Time, error)
+}
+
+// Window decides when the operator lets any task run, such as in their
+// business hours. Next returns now if it may run straight away.
+type Window interface {
+	Next(now time.Time) time.Time
+}
+
+// Handler executes a task. Returning an error wrapping ratelimit.ErrLimitReached
+// or ErrPaused defers the task instead of counting it as a failed attempt;
+// errors that errs.Retryable rejects fail the task without further attempts.
+type Handler func(ctx context.Context, task *storage.QueueTask) error
+
+// Worker drains due tasks from the queue
+type Worker struct {
+	id           string // Owner of the leases on the tasks it claims
+	store        Store
+	handlers     map[string]Handler
+	logger       *logrus.Logger
+	maxAttempts  int
+	retryDelay   time.Duration
+	limitBackoff time.Duration
+	pollInterval time.Duration
+	dryRun       bool
+	scheduler    Scheduler
+	mu           sync.Mutex // Guards timing and window, which a config reload may replace mid-run
+	timing       Timing
+	window       Window
+	interleave   func(ctx context.Context) error
+	interleaveN  int
+}
+
+// RunStats summarizes a worker run
+type RunStats struct {
+	Completed    int
+	Failed       int
+	Retried      int
+	Deferred     int
+	Limited      int  // Of the deferred tasks, those deferred at a rate limit
+	SessionEnded bool // The run stopped because the browser session's budget was used up
+	LoggedOut    bool // The run stopped because LinkedIn ended the login session
+	Restricted   bool // The run stopped because LinkedIn restricted the account
+}
+
+// Enqueue encodes payload and adds it to the queue as a task of the given kind
+func Enqueue(store Store, kind string, payload interface{}, opts Options) (*storage.QueueTask, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s task: %w", kind, err)
+	}
+
+	task := &storage.QueueTask{
+		Kind:        kind,
+		Payload:     string(data),
+		Priority:    opts.Priority,
+		ScheduledAt: opts.ScheduledAt,
+	}
+	if err := store.EnqueueTask(task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// Decode unmarshals a task's payload into v
+func Decode(task *storage.QueueTask, v interface{}) error {
+	if err := json.Unmarshal([]byte(task.Payload), v); err != nil {
+		return fmt.Errorf("failed to decode %s task %d: %w", task.Kind, task.ID, err)
+	}
+	return nil
+}
+
+// CancelStore finds and cancels queued tasks
+type CancelStore interface {
+	ListTasks(status string) ([]*storage.QueueTask, error)
+	CancelTask(id int) (bool, error)
+}
+
+// CancelForProfile cancels the pending connection requests and messages
+// addressed to profileURL, returning how many were cancelled
+func CancelForProfile(store CancelStore, profileURL string) (int, error) {
+	tasks, err := store.ListTasks(storage.TaskPending)
+	if err != nil {
+		return 0, err
+	}
+
+	cancelled := 0
+	for _, task := range tasks {
+		var target string
+		switch task.Kind {
+		case KindConnect:
+			var payload ConnectPayload
+			if err := Decode(task, &payload); err != nil {
+				continue
+			}
+			target = payload.ProfileURL
+		case KindMessage:
+			var payload MessagePayload
+			if err := Decode(task, &payload); err != nil {
+				continue
+			}
+			target = payload.RecipientURL
+		default:
+			continue
+		}
+		if !profileurl.Equal(target, profileURL) {
+			continue
+		}
+
+		ok, err := store.CancelTask(task.ID)
+		if err != nil {
+			return cancelled, err
+		}
+		if ok {
+			cancelled++
+		}
+	}
+	return cancelled, nil
+}
+
+// NewWorker creates a new queue worker
+func NewWorker(store Store, logger *logrus.Logger) *Worker {
+	host, _ := os.Hostname()
+	return &Worker{
+		id:           fmt.Sprintf("%s-%d-%d", host, os.Getpid(), time.Now().UnixNano()),
+		store:        store,
+		handlers:     make(map[string]Handler),
+		logger:       logger,
+		maxAttempts:  3,
+		retryDelay:   5 * time.Minute,
+		limitBackoff: 30 * time.Minute,
+		pollInterval: 30 * time.Second,
+	}
+}
+
+// Register sets the handler for a task kind
+func (w *Worker) Register(kind string, handler Handler) {
+	w.handlers[kind] = handler
+}
+
+// SetMaxAttempts sets how many times a failing task is tried before it is marked failed
+func (w *Worker) SetMaxAttempts(attempts int) {
+	w.maxAttempts = attempts
+}
+
+// SetLimitBackoff sets how long a task is deferred when its rate limit is reached
+func (w *Worker) SetLimitBackoff(backoff time.Duration) {
+	w.limitBackoff = backoff
+}
+
+// SetScheduler lets scheduler add due tasks while the worker runs
+func (w *Worker) SetScheduler(scheduler Scheduler) {
+	w.scheduler = scheduler
+}
+
+// SetTiming defers connection requests and messages until timing lets them run
+func (w *Worker) SetTiming(timing Timing) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.timing = timing
+}
+
+// SetWindow defers every task until window lets it run, whatever its
+// prospect's timing says
+func (w *Worker) SetWindow(window Window) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.window = window
+}
+
+// SetInterleave runs action after every n tasks, such as browsing the feed
+// between outreach so a run is not only connection requests and messages
+func (w *Worker) SetInterleave(n int, action func(ctx context.Context) error) {
+	w.interleaveN = n
+	w.interleave = action
+}
+
+// SetDryRun makes Run try each due task once without changing its state,
+// for use with handlers that do not send anything
+func (w *Worker) SetDryRun(dryRun bool) {
+	w.dryRun = dryRun
+}
+
+// Run processes due tasks until none are left. If follow is set it keeps
+// polling for newly due tasks until the context is cancelled.
+func (w *Worker) Run(ctx context.Context, follow bool) (*RunStats, error) {
+	stats := &RunStats{}
+	if w.dryRun {
+		return stats, w.dryRunTasks(ctx, stats)
+	}
+
+	processed := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return stats, nil
+		}
+
+		// Checked on every round, so a worker that died mid-task is noticed while others keep running
+		if reset, err := w.store.ResetRunningTasks(time.Now().Add(-leaseTimeout)); err != nil {
+			return stats, err
+		} else if reset > 0 {
+			w.logger.WithField("tasks", reset).Warn("Re-queued tasks left running by an interrupted worker")
+		}
+
+		if w.scheduler != nil {
+			if err := w.scheduler.Schedule(ctx, time.Now()); err != nil {
+				w.logger.WithError(err).Warn("Failed to schedule due tasks")
+			}
+		}
+
+		task, err := w.store.ClaimNextTask(time.Now(), w.id)
+		if err != nil {
+			return stats, err
+		}
+
+		if task == nil {
+			if !follow {
+				return stats, nil
+			}
+			select {
+			case <-ctx.Done():
+				return stats, nil
+			case <-time.After(w.pollInterval):
+			}
+			continue
+		}
+
+		if err := w.process(ctx, task, stats); err != nil {
+			return stats, err
+		}
+		if stats.SessionEnded {
+			w.logger.Info("Session limit reached, stopping worker")
+			return stats, nil
+		}
+		if stats.LoggedOut {
+			w.logger.Warn("Session expired, stopping worker")
+			return stats, nil
+		}
+		if stats.Restricted {
+			w.logger.Error("Account restricted, stopping worker")
+			return stats, nil
+		}
+
+		processed++
+		if w.interleave != nil && w.interleaveN > 0 && processed%w.interleaveN == 0 {
+			if err := w.interleave(ctx); err != nil {
+				w.logger.WithError(err).Warn("Interleaved action failed")
+			}
+		}
+	}
+}
+
+// dryRunTasks runs every due task once in queue order without claiming,
+// completing or rescheduling it, so the queue is left as it was
+func (w *Worker) dryRunTasks(ctx context.Context, stats *RunStats) error {
+	tasks, err := w.store.ListTasks(storage.TaskPending)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, task := range tasks {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if task.ScheduledAt.After(now) {
+			continue
+		}
+		at, reason, err := w.nextRun(task, now)
+		if err != nil {
+			stats.Failed++
+			w.logger.WithFields(logrus.Fields{"task_id": task.ID, "kind": task.Kind, "dry_run": true}).
+				WithError(err).Error("Queued task would fail, it can never run")
+			continue
+		}
+		if at.After(now) {
+			stats.Deferred++
+			w.logger.WithFields(logrus.Fields{"task_id": task.ID, "kind": task.Kind, "dry_run": true, "run_at": at}).
+				Info("Queued task would wait for " + reason)
+			continue
+		}
+
+		log := w.logger.WithFields(logrus.Fields{
+			"task_id": task.ID,
+			"kind":    task.Kind,
+			"dry_run": true,
+		})
+
+		handler, ok := w.handlers[task.Kind]
+		if !ok {
+			stats.Failed++
+			log.Errorf("No handler for task kind %q", task.Kind)
+			continue
+		}
+
+		err = handler(ctx, task)
+		switch {
+		case err == nil:
+			stats.Completed++
+			log.Info("Queued task would complete")
+		case errors.Is(err, ErrPaused):
+			stats.Deferred++
+			log.Info("Queued task would wait for its campaign to resume")
+		case errors.Is(err, errs.ErrRestricted):
+			stats.Restricted = true
+			log.WithError(err).Error("LinkedIn restricted the account, stopping dry run")
+			return nil
+		case errors.Is(err, ratelimit.ErrLimitReached):
+			stats.Deferred++
+			stats.Limited++
+			log.WithError(err).Info("Queued task would be deferred at a rate limit")
+		case errors.Is(err, errs.ErrSessionExpired):
+			stats.LoggedOut = true
+			log.WithError(err).Warn("LinkedIn session expired, stopping dry run")
+			return nil
+		default:
+			stats.Failed++
+			log.WithError(err).Warn("Queued task would fail")
+		}
+	}
+
+	return nil
+}
+
+func (w *Worker) process(ctx context.Context, task *storage.QueueTask, stats *RunStats) error {
+	log := w.logger.WithFields(logrus.Fields{
+		"task_id": task.ID,
+		"kind":    task.Kind,
+		"attempt": task.Attempts,
+	})
+
+	handler, ok := w.handlers[task.Kind]
+	if !ok {
+		stats.Failed++
+		return w.store.FailTask(task.ID, fmt.Sprintf("no handler for task kind %q", task.Kind))
+	}
+
+	now := time.Now()
+	at, reason, err := w.nextRun(task, now)
+	if err != nil {
+		stats.Failed++
+		log.WithError(err).Error("Queued task can never run, failing it")
+		return w.store.FailTask(task.ID, err.Error())
+	}
+	if at.After(now) {
+		stats.Deferred++
+		log.WithField("run_at", at).Info("Outside " + reason + ", deferring task")
+		return w.store.DeferTask(task.ID, at, "outside "+reason)
+	}
+
+	log.Info("Running queued task")
+	stopHeartbeat := w.heartbeat(task.ID)
+	err = handler(ctx, task)
+	stopHeartbeat()
+
+	switch {
+	case err == nil:
+		stats.Completed++
+		log.Info("Queued task completed")
+		return w.store.CompleteTask(task.ID)
+
+	case errors.Is(err, ErrPaused):
+		stats.Deferred++
+		log.Info("Campaign paused, deferring task")
+		return w.store.DeferTask(task.ID, time.Now().Add(pausedRecheck), err.Error())
+
+	case errors.Is(err, ErrHeld):
+		stats.Deferred++
+		log.WithError(err).Warn("Task held, deferring it")
+		return w.store.DeferTask(task.ID, time.Now().Add(pausedRecheck), err.Error())
+
+	case errors.Is(err, errs.ErrRestricted):
+		// Nothing may run until the cool-down ends; the task waits for then
+		stats.Deferred++
+		stats.Restricted = true
+		log.WithError(err).Error("LinkedIn restricted the account, deferring task")
+		return w.store.DeferTask(task.ID, time.Now(), err.Error())
+
+	case errors.Is(err, ratelimit.ErrLimitReached):
+		// Hitting a quota is not the task's fault; try again once it has had time to recover
+		stats.Deferred++
+		stats.Limited++
+		retryAt := time.Now().Add(w.limitBackoff)
+		if errors.Is(err, ratelimit.ErrSessionLimit) {
+			// Later tasks would hit the same limit; leave them for a new session
+			stats.SessionEnded = true
+			retryAt = time.Now()
+		}
+		log.WithError(err).WithField("retry_at", retryAt).Info("Rate limit reached, deferring task")
+		return w.store.DeferTask(task.ID, retryAt, err.Error())
+
+	case ctx.Err() != nil:
+		// Interrupted mid-task: put it back without using up an attempt
+		return w.store.DeferTask(task.ID, time.Now(), err.Error())
+
+	case errors.Is(err, errs.ErrSessionExpired):
+		// Every later task would fail the same way until the account logs in again
+		stats.Deferred++
+		stats.LoggedOut = true
+		log.WithError(err).Warn("LinkedIn session expired, deferring task")
+		return w.store.DeferTask(task.ID, time.Now(), err.Error())
+
+	case !errs.Retryable(err):
+		stats.Failed++
+		log.WithError(err).Error("Queued task failed permanently")
+		return w.store.FailTask(task.ID, err.Error())
+
+	case task.Attempts < w.maxAttempts:
+		stats.Retried++
+		retryAt := time.Now().Add(w.retryDelay * time.Duration(task.Attempts))
+		log.WithError(err).WithField("retry_at", retryAt).Warn("Queued task failed, will retry")
+		return w.store.RescheduleTask(task.ID, retryAt, err.Error())
+
+	default:
+		stats.Failed++
+		log.WithError(err).Error("Queued task failed")
+		return w.store.FailTask(task.ID, err.Error())
+	}
+}
+
+// Prospect returns the profile URL a connect or message task acts on, or ""
+// for other tasks
+func Prospect(task *storage.QueueTask) string {
+	switch task.Kind {
+	case KindConnect:
+		var payload ConnectPayload
+		if err := Decode(task, &payload); err == nil {
+			return payload.ProfileURL
+		}
+	case KindMessage:
+		var payload MessagePayload
+		if err := Decode(task, &payload); err == nil {
+			return payload.RecipientURL
+		}
+	}
+	return ""
+}
+
+// heartbeat renews the lease on a running task until the returned function is
+// called, so other workers do not take it for abandoned
+func (w *Worker) heartbeat(id int) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				if err := w.store.HeartbeatTask(id, w.id, now); err != nil {
+					w.logger.WithError(err).WithField("task_id", id).Warn("Failed to renew task lease")
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// Why nextRun holds a task back
+const (
+	reasonBusinessHours = "the operator's business hours"
+	reasonProspectHours = "the prospect's working hours"
+)
+
+// maxWait is how far ahead NextRun looks for the window and timing to agree.
+// Both repeat every week, so hours that agree at all do so within it.
+const maxWait = 8 * 24 * time.Hour
+
+// nextRun returns when both the window and timing let task run, or now if
+// neither holds it back, with the reason of whichever held it back last. It
+// returns ErrNoOverlap for a task they never both let run.
+func (w *Worker) nextRun(task *storage.QueueTask, now time.Time) (time.Time, string, error) {
+	w.mu.Lock()
+	timing, window := w.timing, w.window
+	w.mu.Unlock()
+
+	profileURL := ""
+	if timing != nil {
+		profileURL = Prospect(task)
+	}
+
+	at, reason, err := NextRun(window, timing, profileURL, now)
+	if err != nil && !errors.Is(err, ErrNoOverlap) {
+		w.logger.WithError(err).WithField("task_id", task.ID).Warn("Failed to check the prospect's working hours")
+		return at, reason, nil
+	}
+	return at, reason, err
+}
+
+// NextRun returns when window and timing both let a task for profileURL run,
+// or now if neither holds it back, with the reason of whichever held it back
+// last. Either may be nil, and timing is skipped without a profileURL.
+func NextRun(window Window, timing Timing, profileURL string, now time.Time) (time.Time, string, error) {
+	at, reason := now, ""
+	// Either window opening can fall outside the other, so move on until both agree
+	for at.Sub(now) <= maxWait {
+		moved := false
+		if window != nil {
+			if next := window.Next(at); next.After(at) {
+				at, reason, moved = next, reasonBusinessHours, true
+			}
+		}
+		if timing != nil && profileURL != "" {
+			next, err := timing.Next(profileURL, at)
+			if err != nil {
+				return at, reason, fmt.Errorf("failed to check the prospect's working hours: %w", err)
+			}
+			if next.After(at) {
+				at, reason, moved = next, reasonProspectHours, true
+			}
+		}
+		if !moved {
+			return at, reason, nil
+		}
+	}
+	return at, reason, ErrNoOverlap
+}
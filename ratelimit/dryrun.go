@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NewDryRunRateLimiter creates a limiter for dry runs. It enforces the daily
+// and hourly quotas against the persisted history as if every permitted
+// action had been performed, but never waits between actions and never
+// records them, so a dry run leaves the real quotas untouched.
+func NewDryRunRateLimiter(config Config, store EventStore, logger *logrus.Logger) *RateLimiter {
+	limiter := NewPersistentRateLimiter(withoutDelays(config), &dryRunStore{
+		store:     store,
+		simulated: make(map[string][]time.Time),
+	}, logger)
+	limiter.dryRun = true
+	return limiter
+}
+
+// withoutDelays keeps the quotas of config but drops every wait between actions
+func withoutDelays(config Config) Config {
+	config.MinDelay = 0
+	config.MaxDelay = 0
+	config.SearchDelay = 0
+	config.ConnectDelay = 0
+	config.MessageDelay = 0
+	config.VisitDelay = 0
+	config.BurstLimit = 0
+	config.RandomizeDelay = false
+	return config
+}
+
+// dryRunStore serves the persisted events plus those simulated during a dry
+// run, without writing anything back
+type dryRunStore struct {
+	store     EventStore
+	mu        sync.Mutex
+	simulated map[string][]time.Time
+}
+
+func (s *dryRunStore) RecordRateLimitEvent(action string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.simulated[action] = append(s.simulated[action], at)
+	return nil
+}
+
+func (s *dryRunStore) GetRateLimitEvents(action string, since time.Time) ([]time.Time, error) {
+	events, err := s.store.GetRateLimitEvents(action, since)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, at := range s.simulated[action] {
+		if !at.Before(since) {
+			events = append(events, at)
+		}
+	}
+	return events, nil
+}
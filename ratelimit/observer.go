@@ -0,0 +1,25 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// WaitObserver is told when a limiter holds an action back, and until when
+type WaitObserver func(reason string, until time.Time)
+
+type waitObserverKey struct{}
+
+// WithWaitObserver returns a context whose limiter waits are reported to
+// observer, e.g. so a running batch can show what it is waiting for
+func WithWaitObserver(ctx context.Context, observer WaitObserver) context.Context {
+	return context.WithValue(ctx, waitObserverKey{}, observer)
+}
+
+// NotifyWait reports a wait of delay to the context's observer, if any. Waits
+// outside the limiters, such as for a new login, report themselves with it.
+func NotifyWait(ctx context.Context, reason string, delay time.Duration) {
+	if observer, ok := ctx.Value(waitObserverKey{}).(WaitObserver); ok {
+		observer(reason, time.Now().Add(delay))
+	}
+}
@@ -2,9 +2,13 @@ package ratelimit
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -14,11 +18,23 @@ import (
 type RateLimiter struct {
 	logger           *logrus.Logger
 	config           Config
-	lastActionTime   map[string]time.Time
+	lastActionTime   map[string]time.Time   // By action, and by action and tab for tabs pacing themselves
+	burstTimes       map[string][]time.Time
+	reserved         map[string][]time.Time // When granted actions still waiting out their delay will happen
 	actionCounts     map[string]int
 	dailyCounts      map[string]int
 	mu               sync.RWMutex
 	dailyResetTime   time.Time
+	store            EventStore
+	pending          atomic.Pointer[Config] // Set by SetConfig, applied before the next action
+	dryRun           bool
+}
+
+// EventStore persists performed actions so quotas survive restarts and are
+// shared between commands using the same database
+type EventStore interface {
+	RecordRateLimitEvent(action string, at time.Time) error
+	GetRateLimitEvents(action string, since time.Time) ([]time.Time, error)
 }
 
 // Config defines rate limiting behavior
@@ -31,16 +47,26 @@ type Config struct {
 	SearchDelay    time.Duration `yaml:"search_delay"`     // Delay between searches
 	ConnectDelay   time.Duration `yaml:"connect_delay"`    // Delay between connection requests
 	MessageDelay   time.Duration `yaml:"message_delay"`    // Delay between messages
+	VisitDelay     time.Duration `yaml:"visit_delay"`      // Delay between profile visits
 	
 	// Daily limits
 	DailySearches  int           `yaml:"daily_searches"`   // Max searches per day
 	DailyConnects  int           `yaml:"daily_connects"`   // Max connection requests per day
 	DailyMessages  int           `yaml:"daily_messages"`   // Max messages per day
-	
+	DailyVisits    int           `yaml:"daily_visits"`     // Max profile visits per day
+	DailyLikes     int           `yaml:"daily_likes"`      // Max post likes per day
+	DailyComments  int           `yaml:"daily_comments"`   // Max post comments per day
+	DailyEndorsements int        `yaml:"daily_endorsements"` // Max skill endorsements per day
+	
+	// Weekly limits
+	WeeklyConnects int           `yaml:"weekly_connects"`  // Max connection requests in any 7 days; needs an event store
+	SpreadWeekly   bool          `yaml:"spread_weekly"`    // Hold each day to its share of the weekly connects
+
 	// Hourly limits
 	HourlySearches int           `yaml:"hourly_searches"`  // Max searches per hour
 	HourlyConnects int           `yaml:"hourly_connects"`  // Max connection requests per hour
 	HourlyMessages int           `yaml:"hourly_messages"`  // Max messages per hour
+	HourlyVisits   int           `yaml:"hourly_visits"`    // Max profile visits per hour
 	
 	// Burst protection
 	BurstLimit     int           `yaml:"burst_limit"`      // Max actions in burst window
@@ -51,6 +77,9 @@ type Config struct {
 	JitterPercent  float64       `yaml:"jitter_percent"`   // Percentage of jitter to add
 }
 
+// ErrLimitReached is returned when a daily or hourly quota has been exhausted
+var ErrLimitReached = errors.New("rate limit reached")
+
 // ActionType represents different types of LinkedIn actions
 type ActionType string
 
@@ -59,6 +88,10 @@ const (
 	ActionConnect ActionType = "connect"
 	ActionMessage ActionType = "message"
 	ActionBrowse  ActionType = "browse"
+	ActionVisit   ActionType = "visit"
+	ActionLike    ActionType = "like"
+	ActionComment ActionType = "comment"
+	ActionEndorse ActionType = "endorse"
 )
 
 // NewRateLimiter creates a new rate limiter with the given configuration
@@ -67,6 +100,8 @@ func NewRateLimiter(config Config, logger *logrus.Logger) *RateLimiter {
 		logger:         logger,
 		config:         config,
 		lastActionTime: make(map[string]time.Time),
+		burstTimes:     make(map[string][]time.Time),
+		reserved:       make(map[string][]time.Time),
 		actionCounts:   make(map[string]int),
 		dailyCounts:    make(map[string]int),
 		dailyResetTime: getNextMidnight(),
@@ -78,53 +113,151 @@ func NewRateLimiter(config Config, logger *logrus.Logger) *RateLimiter {
 	return rl
 }
 
-// WaitForPermission waits until the action can be performed
+// NewPersistentRateLimiter creates a rate limiter whose counters are backed by
+// an event store, using sliding daily and hourly windows
+func NewPersistentRateLimiter(config Config, store EventStore, logger *logrus.Logger) *RateLimiter {
+	return &RateLimiter{
+		logger:         logger,
+		config:         config,
+		lastActionTime: make(map[string]time.Time),
+		burstTimes:     make(map[string][]time.Time),
+		reserved:       make(map[string][]time.Time),
+		actionCounts:   make(map[string]int),
+		dailyCounts:    make(map[string]int),
+		dailyResetTime: getNextMidnight(),
+		store:          store,
+	}
+}
+
+// reservation is an action granted by the limiter that is waiting out its delay
+type reservation struct {
+	action   ActionType
+	pace     string    // Key of the pacing the action follows
+	at       time.Time // When the action will happen
+	previous time.Time // The pacing's last action before this one
+	delay    time.Duration
+}
+
+// WaitForPermission waits until the action can be performed. The action's
+// slot is reserved before the wait and the limiter is free while it runs, so
+// tabs marked with WithTab wait out their own delays side by side while
+// drawing on the same quotas.
 func (rl *RateLimiter) WaitForPermission(ctx context.Context, action ActionType) error {
+	r, err := rl.reserve(action, paceKey(ctx, action))
+	if err != nil {
+		return err
+	}
+	
+	// Wait if needed
+	if r.delay > 0 {
+		rl.logger.WithFields(logrus.Fields{
+			"action": string(action),
+			"delay":  r.delay,
+		}).Info("Rate limiting - waiting")
+		NotifyWait(ctx, string(action)+" rate limit", r.delay)
+		
+		select {
+		case <-time.After(r.delay):
+			// Continue
+		case <-ctx.Done():
+			rl.cancel(r)
+			return ctx.Err()
+		}
+	}
+	
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.unreserve(r)
+	rl.updateTracking(action, r.pace)
+	
+	return nil
+}
+
+// reserve checks the quotas, counting the actions already granted, and works
+// out the delay before the action, whose slot it holds until the action is
+// tracked or cancelled
+func (rl *RateLimiter) reserve(action ActionType, pace string) (*reservation, error) {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 	
+	if config := rl.pending.Swap(nil); config != nil {
+		rl.config = *config
+	}
+	
+	// Refresh counters from persistent storage
+	if rl.store != nil {
+		if err := rl.loadFromStore(action); err != nil {
+			return nil, err
+		}
+	}
+	
 	// Check daily limits
 	if err := rl.checkDailyLimits(action); err != nil {
-		return err
+		return nil, err
 	}
 	
 	// Check hourly limits
 	if err := rl.checkHourlyLimits(action); err != nil {
-		return err
+		return nil, err
 	}
-	
-	// Check burst protection
-	if err := rl.checkBurstProtection(action); err != nil {
-		return err
+
+	// Check the weekly invitation budget
+	if err := rl.checkWeeklyLimits(action); err != nil {
+		return nil, err
 	}
 	
-	// Calculate required delay
-	delay := rl.calculateDelay(action)
+	// Calculate required delay, extended to let a burst window pass
+	delay := rl.calculateDelay(action, pace)
+	if burstDelay := rl.burstDelay(action); burstDelay > delay {
+		delay = burstDelay
+	}
 	
 	// Add humanization
 	if rl.config.RandomizeDelay {
 		delay = rl.addJitter(delay)
 	}
 	
-	// Wait if needed
-	if delay > 0 {
-		rl.logger.WithFields(logrus.Fields{
-			"action": string(action),
-			"delay":  delay,
-		}).Info("Rate limiting - waiting")
-		
-		select {
-		case <-time.After(delay):
-			// Continue
-		case <-ctx.Done():
-			return ctx.Err()
-		}
+	r := &reservation{
+		action:   action,
+		pace:     pace,
+		at:       time.Now().Add(delay),
+		previous: rl.lastActionTime[pace],
+		delay:    delay,
 	}
+	rl.reserved[string(action)] = append(rl.reserved[string(action)], r.at)
+	rl.lastActionTime[pace] = r.at
+	return r, nil
+}
+
+// cancel gives up a reservation whose wait was interrupted
+func (rl *RateLimiter) cancel(r *reservation) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
 	
-	// Update tracking
-	rl.updateTracking(action)
-	
-	return nil
+	rl.unreserve(r)
+	if rl.lastActionTime[r.pace].Equal(r.at) {
+		rl.lastActionTime[r.pace] = r.previous
+	}
+}
+
+// unreserve drops a reservation from the granted actions; the caller must hold rl.mu
+func (rl *RateLimiter) unreserve(r *reservation) {
+	actionStr := string(r.action)
+	for i, at := range rl.reserved[actionStr] {
+		if at.Equal(r.at) {
+			rl.reserved[actionStr] = append(rl.reserved[actionStr][:i], rl.reserved[actionStr][i+1:]...)
+			return
+		}
+	}
+}
+
+// SetConfig replaces the delays and quotas. It does not wait for an action in
+// progress; the new configuration applies from the next action on.
+func (rl *RateLimiter) SetConfig(config Config) {
+	if rl.dryRun {
+		config = withoutDelays(config)
+	}
+	rl.pending.Store(&config)
 }
 
 // checkDailyLimits ensures we don't exceed daily quotas
@@ -139,20 +272,53 @@ func (rl *RateLimiter) checkDailyLimits(action ActionType) error {
 		dailyLimit = rl.config.DailyConnects
 	case ActionMessage:
 		dailyLimit = rl.config.DailyMessages
+	case ActionVisit:
+		dailyLimit = rl.config.DailyVisits
+	case ActionLike:
+		dailyLimit = rl.config.DailyLikes
+	case ActionComment:
+		dailyLimit = rl.config.DailyComments
+	case ActionEndorse:
+		dailyLimit = rl.config.DailyEndorsements
 	default:
 		return nil // No daily limit for other actions
 	}
 	
 	if dailyLimit > 0 {
-		current := rl.dailyCounts[actionStr]
+		current := rl.dailyCounts[actionStr] + len(rl.reserved[actionStr])
 		if current >= dailyLimit {
-			return fmt.Errorf("daily limit exceeded for %s: %d/%d", actionStr, current, dailyLimit)
+			return fmt.Errorf("%w: daily limit exceeded for %s: %d/%d", ErrLimitReached, actionStr, current, dailyLimit)
 		}
 	}
 	
 	return nil
 }
 
+// checkWeeklyLimits keeps connection requests within the weekly budget and,
+// when spreading it, within today's share
+func (rl *RateLimiter) checkWeeklyLimits(action ActionType) error {
+	if action != ActionConnect || rl.config.WeeklyConnects <= 0 || rl.store == nil {
+		return nil
+	}
+
+	now := time.Now()
+	events, err := rl.store.GetRateLimitEvents(string(action), now.Add(-Week))
+	if err != nil {
+		return fmt.Errorf("failed to load rate limit events: %w", err)
+	}
+
+	events = append(events, rl.reserved[string(action)]...)
+	plan := PlanWeek(rl.config.WeeklyConnects, events, -1, now)
+	if plan.Remaining == 0 {
+		return fmt.Errorf("%w: weekly limit exceeded for %s: %d/%d", ErrLimitReached, action, plan.Sent, plan.Limit)
+	}
+	if today := plan.Today(); rl.config.SpreadWeekly && today.Sent >= today.Target {
+		return fmt.Errorf("%w: today's share of the weekly limit used for %s: %d/%d", ErrLimitReached, action, today.Sent, today.Target)
+	}
+
+	return nil
+}
+
 // checkHourlyLimits ensures we don't exceed hourly quotas
 func (rl *RateLimiter) checkHourlyLimits(action ActionType) error {
 	actionStr := string(action)
@@ -165,6 +331,8 @@ func (rl *RateLimiter) checkHourlyLimits(action ActionType) error {
 		hourlyLimit = rl.config.HourlyConnects
 	case ActionMessage:
 		hourlyLimit = rl.config.HourlyMessages
+	case ActionVisit:
+		hourlyLimit = rl.config.HourlyVisits
 	default:
 		return nil // No hourly limit for other actions
 	}
@@ -173,42 +341,54 @@ func (rl *RateLimiter) checkHourlyLimits(action ActionType) error {
 		// Count actions in the last hour
 		// This is a simplified implementation - in production, you'd want a sliding window
 		// For now, we'll use the actionCounts which reset hourly
-		current := rl.actionCounts[actionStr]
+		current := rl.actionCounts[actionStr] + len(rl.reserved[actionStr])
 		if current >= hourlyLimit {
-			return fmt.Errorf("hourly limit exceeded for %s: %d/%d", actionStr, current, hourlyLimit)
+			return fmt.Errorf("%w: hourly limit exceeded for %s: %d/%d", ErrLimitReached, actionStr, current, hourlyLimit)
 		}
 	}
 	
 	return nil
 }
 
-// checkBurstProtection prevents rapid successive actions
-func (rl *RateLimiter) checkBurstProtection(action ActionType) error {
-	if rl.config.BurstLimit <= 0 {
-		return nil
+// burstDelay returns how long to wait so that no more than BurstLimit
+// actions of the same type happen within BurstWindow
+func (rl *RateLimiter) burstDelay(action ActionType) time.Duration {
+	if rl.config.BurstLimit <= 0 || rl.config.BurstWindow <= 0 {
+		return 0
 	}
 	
 	actionStr := string(action)
-	lastAction := rl.lastActionTime[actionStr]
-	
-	if !lastAction.IsZero() {
-		timeSinceLast := time.Since(lastAction)
-		if timeSinceLast < rl.config.BurstWindow {
-			// We're in the burst window
-			if rl.actionCounts[actionStr] >= rl.config.BurstLimit {
-				return fmt.Errorf("burst limit exceeded for %s: %d actions in %v", 
-					actionStr, rl.actionCounts[actionStr], rl.config.BurstWindow)
-			}
+	now := time.Now()
+	
+	// Drop timestamps that have left the window
+	recent := rl.burstTimes[actionStr][:0]
+	for _, t := range rl.burstTimes[actionStr] {
+		if now.Sub(t) < rl.config.BurstWindow {
+			recent = append(recent, t)
 		}
 	}
+	rl.burstTimes[actionStr] = recent
 	
-	return nil
+	// Actions granted but still waiting will be in the window too
+	times := append(append([]time.Time(nil), recent...), rl.reserved[actionStr]...)
+	if len(times) < rl.config.BurstLimit {
+		return 0
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	
+	// Wait until the oldest action in the window expires
+	oldest := times[len(times)-rl.config.BurstLimit]
+	return rl.config.BurstWindow - now.Sub(oldest)
 }
 
-// calculateDelay determines how long to wait before the next action
-func (rl *RateLimiter) calculateDelay(action ActionType) time.Duration {
+// calculateDelay determines how long to wait before the next action of a
+// pacing. A tab's first action follows the last one of any tab.
+func (rl *RateLimiter) calculateDelay(action ActionType, pace string) time.Duration {
 	actionStr := string(action)
-	lastAction := rl.lastActionTime[actionStr]
+	lastAction := rl.lastActionTime[pace]
+	if lastAction.IsZero() {
+		lastAction = rl.lastActionTime[actionStr]
+	}
 	
 	if lastAction.IsZero() {
 		return 0 // First action, no delay
@@ -225,6 +405,8 @@ func (rl *RateLimiter) calculateDelay(action ActionType) time.Duration {
 		requiredDelay = rl.config.ConnectDelay
 	case ActionMessage:
 		requiredDelay = rl.config.MessageDelay
+	case ActionVisit:
+		requiredDelay = rl.config.VisitDelay
 	default:
 		requiredDelay = rl.config.MinDelay
 	}
@@ -261,21 +443,63 @@ func (rl *RateLimiter) addJitter(delay time.Duration) time.Duration {
 }
 
 // updateTracking updates the internal tracking for rate limiting
-func (rl *RateLimiter) updateTracking(action ActionType) {
+func (rl *RateLimiter) updateTracking(action ActionType, pace string) {
 	actionStr := string(action)
 	now := time.Now()
 	
 	// Update last action time
 	rl.lastActionTime[actionStr] = now
+	rl.lastActionTime[pace] = now
+	rl.burstTimes[actionStr] = append(rl.burstTimes[actionStr], now)
 	
 	// Increment action counts
 	rl.actionCounts[actionStr]++
 	rl.dailyCounts[actionStr]++
 	
+	if rl.store != nil {
+		if err := rl.store.RecordRateLimitEvent(actionStr, now); err != nil {
+			rl.logger.WithError(err).Warn("Failed to persist rate limit event")
+		}
+		return
+	}
+	
 	// Start hourly reset goroutine if not already running
 	go rl.hourlyReset(actionStr)
 }
 
+// loadFromStore rebuilds the counters for an action from the sliding windows
+// of persisted events
+func (rl *RateLimiter) loadFromStore(action ActionType) error {
+	actionStr := string(action)
+	now := time.Now()
+	
+	events, err := rl.store.GetRateLimitEvents(actionStr, now.Add(-24*time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to load rate limit events: %w", err)
+	}
+	
+	hourly := 0
+	var recent []time.Time
+	for _, t := range events {
+		if now.Sub(t) < time.Hour {
+			hourly++
+		}
+		if rl.config.BurstWindow > 0 && now.Sub(t) < rl.config.BurstWindow {
+			recent = append(recent, t)
+		}
+	}
+	
+	rl.dailyCounts[actionStr] = len(events)
+	rl.actionCounts[actionStr] = hourly
+	rl.burstTimes[actionStr] = recent
+	// Actions of other commands pace this one, without undoing a reservation
+	if len(events) > 0 && events[len(events)-1].After(rl.lastActionTime[actionStr]) {
+		rl.lastActionTime[actionStr] = events[len(events)-1]
+	}
+	
+	return nil
+}
+
 // hourlyReset resets hourly counts for an action type
 func (rl *RateLimiter) hourlyReset(action string) {
 	time.Sleep(time.Hour)
@@ -322,9 +546,16 @@ func (rl *RateLimiter) GetStats() map[string]interface{} {
 	stats["daily_searches"] = rl.dailyCounts[string(ActionSearch)]
 	stats["daily_connects"] = rl.dailyCounts[string(ActionConnect)]
 	stats["daily_messages"] = rl.dailyCounts[string(ActionMessage)]
+	stats["daily_visits"] = rl.dailyCounts[string(ActionVisit)]
+	stats["daily_likes"] = rl.dailyCounts[string(ActionLike)]
+	stats["daily_comments"] = rl.dailyCounts[string(ActionComment)]
+	stats["daily_endorsements"] = rl.dailyCounts[string(ActionEndorse)]
 	
 	// Last action times
 	for action, lastTime := range rl.lastActionTime {
+		if strings.Contains(action, tabSeparator) {
+			continue // A tab's own pacing
+		}
 		stats["last_"+action] = lastTime.Format(time.RFC3339)
 	}
 	
@@ -342,12 +573,18 @@ func DefaultConfig() Config {
 		SearchDelay:    5 * time.Second,
 		ConnectDelay:   30 * time.Second,
 		MessageDelay:   60 * time.Second,
+		VisitDelay:     20 * time.Second,
 		DailySearches:  100,
 		DailyConnects:  50,
 		DailyMessages:  30,
+		DailyVisits:    80,
+		DailyLikes:     30,
+		DailyComments:  10,
+		DailyEndorsements: 20,
 		HourlySearches: 20,
 		HourlyConnects: 10,
 		HourlyMessages: 5,
+		HourlyVisits:   15,
 		BurstLimit:     3,
 		BurstWindow:    30 * time.Second,
 		RandomizeDelay: true,
@@ -0,0 +1,24 @@
+package ratelimit
+
+import "time"
+
+// scopedStore keeps one account's rate limit events apart from the others
+// sharing the same store
+type scopedStore struct {
+	store EventStore
+	scope string
+}
+
+// ScopedStore returns an event store whose events only count for scope, so
+// accounts sharing a database each get their own quotas
+func ScopedStore(store EventStore, scope string) EventStore {
+	return &scopedStore{store: store, scope: scope}
+}
+
+func (s *scopedStore) RecordRateLimitEvent(action string, at time.Time) error {
+	return s.store.RecordRateLimitEvent(s.scope+":"+action, at)
+}
+
+func (s *scopedStore) GetRateLimitEvents(action string, since time.Time) ([]time.Time, error) {
+	return s.store.GetRateLimitEvents(s.scope+":"+action, since)
+}
@@ -0,0 +1,192 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrSessionLimit is returned once a browser session has used up its action or
+// active time budget. It wraps ErrLimitReached so batches stop the same way.
+var ErrSessionLimit = fmt.Errorf("%w: session limit reached", ErrLimitReached)
+
+// Session limit behaviors
+const (
+	OnSessionLimitExit  = "exit"  // Refuse further actions in this session
+	OnSessionLimitBreak = "break" // Take a break, then start a fresh budget
+)
+
+// Limiter grants permission for actions
+type Limiter interface {
+	WaitForPermission(ctx context.Context, action ActionType) error
+}
+
+// SessionConfig caps the activity of a single browser session
+type SessionConfig struct {
+	MaxActions     int           // Actions allowed per session; 0 is unlimited
+	MaxDuration    time.Duration // Active time allowed per session, excluding breaks; 0 is unlimited
+	BreakFrequency time.Duration // Active time between breaks; 0 disables breaks
+	BreakDuration  time.Duration // Average break length, varied by 20%
+	OnLimit        string        // OnSessionLimitExit or OnSessionLimitBreak
+}
+
+// SessionLimiter enforces session caps and scheduled breaks on top of another limiter
+type SessionLimiter struct {
+	limiter   Limiter
+	config    SessionConfig
+	logger    *logrus.Logger
+	rng       *rand.Rand
+	mu        sync.Mutex
+	actions   int
+	active    time.Duration // Active time before the current stretch
+	stretch   time.Time     // Start of the current stretch of activity
+	lastBreak time.Time
+	pending   atomic.Pointer[SessionConfig] // Set by SetConfig, applied before the next action
+}
+
+// NewSessionLimiter wraps limiter with the session caps in config
+func NewSessionLimiter(limiter Limiter, config SessionConfig, logger *logrus.Logger) *SessionLimiter {
+	now := time.Now()
+	return &SessionLimiter{
+		limiter:   limiter,
+		config:    config,
+		logger:    logger,
+		rng:       rand.New(rand.NewSource(now.UnixNano())),
+		stretch:   now,
+		lastBreak: now,
+	}
+}
+
+// WaitForPermission takes any due break, checks the session budget and then
+// defers to the wrapped limiter. Breaks hold every tab of the session, but the
+// wrapped limiter's waits run without the lock so tabs pace side by side.
+func (s *SessionLimiter) WaitForPermission(ctx context.Context, action ActionType) error {
+	if err := s.reserve(ctx); err != nil {
+		return err
+	}
+
+	if err := s.limiter.WaitForPermission(ctx, action); err != nil {
+		s.mu.Lock()
+		if s.actions > 0 {
+			s.actions-- // Not performed after all
+		}
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// reserve takes any due break and counts the action against the session
+// budget, which it must fit in
+func (s *SessionLimiter) reserve(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if config := s.pending.Swap(nil); config != nil {
+		s.config = *config
+	}
+
+	if s.config.BreakFrequency > 0 && time.Since(s.lastBreak) >= s.config.BreakFrequency {
+		if err := s.takeBreak(ctx, "scheduled"); err != nil {
+			return err
+		}
+	}
+
+	if reason := s.exhausted(); reason != "" {
+		if s.config.OnLimit != OnSessionLimitBreak || s.config.BreakDuration <= 0 {
+			return fmt.Errorf("%w: %s", ErrSessionLimit, reason)
+		}
+		s.logger.WithField("reason", reason).Info("Session budget used up")
+		if err := s.takeBreak(ctx, "mandatory"); err != nil {
+			return err
+		}
+		s.actions = 0
+		s.active = 0
+	}
+
+	s.actions++
+	return nil
+}
+
+// SetConfig replaces the session caps. A break in progress runs its course;
+// the new caps apply from the next action on.
+func (s *SessionLimiter) SetConfig(config SessionConfig) {
+	s.pending.Store(&config)
+}
+
+// Wrapper is a limiter outside this package that adds to another one
+type Wrapper interface {
+	Unwrap() Limiter
+}
+
+// Adjuster is a Wrapper that changes the quotas of the limiter it wraps, such
+// as lowering them for a while; Reconfigure lets it adjust new quotas first
+type Adjuster interface {
+	Wrapper
+	AdjustQuotas(quotas Config) Config
+}
+
+// Reconfigure applies new quotas and session caps to a limiter created by this
+// package, including the quotas of the limiter a SessionLimiter or a Wrapper wraps
+func Reconfigure(limiter Limiter, quotas Config, session SessionConfig) {
+	switch l := limiter.(type) {
+	case *SessionLimiter:
+		l.SetConfig(session)
+		Reconfigure(l.limiter, quotas, session)
+	case *RateLimiter:
+		l.SetConfig(quotas)
+	case Adjuster:
+		Reconfigure(l.Unwrap(), l.AdjustQuotas(quotas), session)
+	case Wrapper:
+		Reconfigure(l.Unwrap(), quotas, session)
+	}
+}
+
+// Stats reports the session's usage so far
+func (s *SessionLimiter) Stats() (actions int, active time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.actions, s.active + time.Since(s.stretch)
+}
+
+// exhausted describes which budget has run out, or returns ""
+func (s *SessionLimiter) exhausted() string {
+	if s.config.MaxActions > 0 && s.actions >= s.config.MaxActions {
+		return fmt.Sprintf("%d actions performed", s.actions)
+	}
+	if active := s.active + time.Since(s.stretch); s.config.MaxDuration > 0 && active >= s.config.MaxDuration {
+		return fmt.Sprintf("active for %v", active.Round(time.Second))
+	}
+	return ""
+}
+
+// takeBreak pauses for about BreakDuration; the pause is not active time
+func (s *SessionLimiter) takeBreak(ctx context.Context, kind string) error {
+	variation := float64(s.config.BreakDuration) * 0.2
+	duration := s.config.BreakDuration - time.Duration(variation) + time.Duration(s.rng.Float64()*2*variation)
+
+	s.logger.WithFields(logrus.Fields{
+		"kind":     kind,
+		"duration": duration.Round(time.Second),
+	}).Info("Taking session break")
+	NotifyWait(ctx, kind+" session break", duration)
+
+	s.active += time.Since(s.stretch)
+	defer func() {
+		now := time.Now()
+		s.stretch = now
+		s.lastBreak = now
+	}()
+
+	select {
+	case <-time.After(duration):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,27 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+)
+
+// tabSeparator joins an action and a tab in the key of the tab's pacing
+const tabSeparator = "#"
+
+type tabKey struct{}
+
+// WithTab returns a context whose actions are paced as those of the given
+// browser tab. Tabs sharing a limiter each wait out the action delays on their
+// own, while the daily, weekly, hourly and burst quotas stay shared.
+func WithTab(ctx context.Context, tab int) context.Context {
+	return context.WithValue(ctx, tabKey{}, tab)
+}
+
+// paceKey is the key of the pacing an action in ctx follows: the action's own
+// unless ctx is a tab's
+func paceKey(ctx context.Context, action ActionType) string {
+	if tab, ok := ctx.Value(tabKey{}).(int); ok {
+		return string(action) + tabSeparator + strconv.Itoa(tab)
+	}
+	return string(action)
+}
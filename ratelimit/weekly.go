@@ -0,0 +1,74 @@
+package ratelimit
+
+import "time"
+
+// Week is the rolling window LinkedIn's invitation cap applies to
+const Week = 7 * 24 * time.Hour
+
+// WeeklyPlan spreads a weekly invitation budget over the days of the coming
+// week. Any seven days in a row may hold at most Limit invitations, so a day's
+// target is an even share of the limit, lowered where the invitations of the
+// six days before it, sent or planned, leave less room.
+type WeeklyPlan struct {
+	Limit     int         `json:"limit"`
+	Sent      int         `json:"sent"`      // Invitations in the last seven days
+	Remaining int         `json:"remaining"` // Invitations that may still go out now
+	Pending   int         `json:"pending"`   // Invitations waiting to go out; -1 when unknown
+	Days      []DayTarget `json:"days"`      // Today and the six days after it
+}
+
+// DayTarget is the number of invitations planned for one day
+type DayTarget struct {
+	Date   time.Time `json:"date"`   // Midnight, local time
+	Sent   int       `json:"sent"`   // Sent so far; only today has any
+	Target int       `json:"target"` // All invitations planned for the day, including those sent
+}
+
+// PlanWeek plans limit invitations a week from the times of those sent
+// recently, for pending invitations still to send or all the room there is
+// when pending is negative
+func PlanWeek(limit int, sent []time.Time, pending int, now time.Time) *WeeklyPlan {
+	plan := &WeeklyPlan{Limit: limit, Pending: pending}
+	for _, t := range sent {
+		if now.Sub(t) < Week {
+			plan.Sent++
+		}
+	}
+	plan.Remaining = max(limit-plan.Sent, 0)
+
+	pace := (limit + 6) / 7
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	left := pending
+	planned := 0
+	for k := 0; k < 7; k++ {
+		start := today.AddDate(0, 0, k)
+		end := start.AddDate(0, 0, 1)
+
+		// Invitations that will still count at the end of the day
+		inWindow, sentDay := planned, 0
+		for _, t := range sent {
+			switch {
+			case !t.Before(start) && t.Before(end):
+				sentDay++
+			case t.After(end.Add(-Week)) && t.Before(start):
+				inWindow++
+			}
+		}
+
+		target := min(pace-sentDay, limit-inWindow-sentDay)
+		if pending >= 0 {
+			target = min(target, left)
+			left -= max(target, 0)
+		}
+		target = max(target, 0)
+		planned += target
+
+		plan.Days = append(plan.Days, DayTarget{Date: start, Sent: sentDay, Target: sentDay + target})
+	}
+	return plan
+}
+
+// Today returns the target for the current day
+func (p *WeeklyPlan) Today() DayTarget {
+	return p.Days[0]
+}
@@ -0,0 +1,215 @@
+// Package recording saves a screencast of the automated browser page for each
+// run, as a sequence of JPEG frames or a WebM video, so what LinkedIn showed
+// during a batch can be replayed afterwards.
+package recording
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/sirupsen/logrus"
+)
+
+// Recording formats
+const (
+	FormatImages = "images" // JPEG frames with a frames.ffconcat timing file
+	FormatWebM   = "webm"   // Frames encoded with ffmpeg, which must be on the PATH
+)
+
+// lastFrameDuration is how long the final frame is shown, as it has no successor to time it
+const lastFrameDuration = time.Second
+
+// Options controls how a run is recorded
+type Options struct {
+	Dir           string // Each run is saved to its own directory below Dir
+	Format        string // FormatImages or FormatWebM
+	Quality       int    // JPEG quality, 0-100
+	MaxWidth      int    // Frames are scaled down to fit; 0 keeps the viewport size
+	MaxHeight     int
+	EveryNthFrame int // Keep one of every n frames the browser renders
+}
+
+// frame is one saved screencast frame
+type frame struct {
+	file string
+	at   time.Time
+}
+
+// Recorder captures the screencast of one page
+type Recorder struct {
+	page    *rod.Page
+	opts    Options
+	runDir  string
+	logger  *logrus.Logger
+	cancel  context.CancelFunc
+	done    chan struct{}
+	mu      sync.Mutex
+	frames  []frame
+	stopped bool
+}
+
+// Start begins recording page into a new directory for this run
+func Start(page *rod.Page, opts Options, logger *logrus.Logger) (*Recorder, error) {
+	switch opts.Format {
+	case FormatImages, FormatWebM:
+	default:
+		return nil, fmt.Errorf("unsupported recording format %q (use %s or %s)", opts.Format, FormatImages, FormatWebM)
+	}
+
+	runDir := filepath.Join(opts.Dir, "run-"+time.Now().Format("20060102-150405"))
+	if err := os.MkdirAll(runDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create recording directory: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Recorder{
+		page:   page,
+		opts:   opts,
+		runDir: runDir,
+		logger: logger,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	wait := page.Context(ctx).EachEvent(func(e *proto.PageScreencastFrame) {
+		r.saveFrame(e)
+	})
+	go func() {
+		defer close(r.done)
+		wait()
+	}()
+
+	start := proto.PageStartScreencast{Format: proto.PageStartScreencastFormatJpeg}
+	if opts.Quality > 0 {
+		start.Quality = &opts.Quality
+	}
+	if opts.MaxWidth > 0 {
+		start.MaxWidth = &opts.MaxWidth
+	}
+	if opts.MaxHeight > 0 {
+		start.MaxHeight = &opts.MaxHeight
+	}
+	if opts.EveryNthFrame > 1 {
+		start.EveryNthFrame = &opts.EveryNthFrame
+	}
+	if err := start.Call(page); err != nil {
+		cancel()
+		<-r.done
+		return nil, fmt.Errorf("failed to start screencast: %w", err)
+	}
+
+	logger.WithField("dir", runDir).Info("Recording browser session")
+	return r, nil
+}
+
+// saveFrame writes a frame to the run directory and asks the browser for the next one
+func (r *Recorder) saveFrame(e *proto.PageScreencastFrame) {
+	at := time.Now()
+	if e.Metadata != nil && e.Metadata.Timestamp > 0 {
+		at = e.Metadata.Timestamp.Time()
+	}
+
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		return
+	}
+	name := fmt.Sprintf("frame-%06d.jpg", len(r.frames)+1)
+	r.frames = append(r.frames, frame{file: name, at: at})
+	r.mu.Unlock()
+
+	if err := os.WriteFile(filepath.Join(r.runDir, name), e.Data, 0600); err != nil {
+		r.logger.WithError(err).Debug("Failed to save screencast frame")
+	}
+	if err := (proto.PageScreencastFrameAck{SessionID: e.SessionID}).Call(r.page); err != nil {
+		r.logger.WithError(err).Debug("Failed to acknowledge screencast frame")
+	}
+}
+
+// Stop ends the recording and returns where it was saved. For WebM, the
+// frames are replaced by the video once ffmpeg has encoded it; if that fails
+// they are kept.
+func (r *Recorder) Stop() (string, error) {
+	if err := (proto.PageStopScreencast{}).Call(r.page); err != nil {
+		r.logger.WithError(err).Debug("Failed to stop screencast")
+	}
+	r.cancel()
+	<-r.done
+
+	r.mu.Lock()
+	r.stopped = true
+	frames := r.frames
+	r.mu.Unlock()
+
+	if len(frames) == 0 {
+		os.Remove(r.runDir)
+		return "", fmt.Errorf("no frames were recorded")
+	}
+
+	concat := filepath.Join(r.runDir, "frames.ffconcat")
+	if err := writeConcat(concat, frames); err != nil {
+		return r.runDir, err
+	}
+	r.logger.WithFields(logrus.Fields{"dir": r.runDir, "frames": len(frames)}).Info("Recording saved")
+
+	if r.opts.Format != FormatWebM {
+		return r.runDir, nil
+	}
+	video, err := encodeWebM(r.runDir, concat)
+	if err != nil {
+		return r.runDir, fmt.Errorf("failed to encode recording, frames kept in %s: %w", r.runDir, err)
+	}
+	for _, f := range frames {
+		os.Remove(filepath.Join(r.runDir, f.file))
+	}
+	os.Remove(concat)
+	r.logger.WithField("file", video).Info("Recording encoded")
+	return video, nil
+}
+
+// writeConcat writes an ffmpeg concat list that shows each frame until the next one
+func writeConcat(path string, frames []frame) error {
+	var b strings.Builder
+	b.WriteString("ffconcat version 1.0\n")
+	for i, f := range frames {
+		duration := lastFrameDuration
+		if i+1 < len(frames) {
+			duration = frames[i+1].at.Sub(f.at)
+		}
+		if duration < 0 {
+			duration = 0
+		}
+		fmt.Fprintf(&b, "file %s\nduration %.3f\n", f.file, duration.Seconds())
+	}
+	// The concat demuxer ignores the duration of the last entry unless the file repeats
+	fmt.Fprintf(&b, "file %s\n", frames[len(frames)-1].file)
+
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write frame list: %w", err)
+	}
+	return nil
+}
+
+// encodeWebM turns the frame list into session.webm next to it
+func encodeWebM(runDir, concat string) (string, error) {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return "", fmt.Errorf("ffmpeg not found on the PATH")
+	}
+
+	video := filepath.Join(runDir, "session.webm")
+	cmd := exec.Command(ffmpeg, "-y", "-loglevel", "error", "-f", "concat", "-safe", "0", "-i", concat,
+		"-vf", "pad=ceil(iw/2)*2:ceil(ih/2)*2", "-c:v", "libvpx-vp9", "-pix_fmt", "yuv420p", "-vsync", "vfr", video)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return video, nil
+}
@@ -0,0 +1,95 @@
+// Package reload notices when the files a long-running command was started
+// with are edited, so their settings can be applied without a restart.
+package reload
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Watcher polls files and directories for changes. Polling, unlike file
+// system events, also sees files that editors replace rather than write.
+type Watcher struct {
+	interval time.Duration
+	paths    []string
+	stamps   map[string]string
+}
+
+// NewWatcher watches the given files, and the direct entries of the given
+// directories. Paths that do not exist yet are reported once they appear.
+func NewWatcher(interval time.Duration, paths ...string) *Watcher {
+	w := &Watcher{
+		interval: interval,
+		stamps:   make(map[string]string, len(paths)),
+	}
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		w.paths = append(w.paths, path)
+		w.stamps[path] = stamp(path)
+	}
+	return w
+}
+
+// Run calls onChange with the paths that changed since the last poll, until
+// ctx is cancelled
+func (w *Watcher) Run(ctx context.Context, onChange func(changed []string)) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var changed []string
+		for _, path := range w.paths {
+			if current := stamp(path); current != w.stamps[path] {
+				w.stamps[path] = current
+				changed = append(changed, path)
+			}
+		}
+		if len(changed) > 0 {
+			onChange(changed)
+		}
+	}
+}
+
+// stamp sums up a path's modification time and size, or those of every entry
+// of a directory, so any edit changes it
+func stamp(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	if !info.IsDir() {
+		return fileStamp(info)
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return ""
+	}
+	stamps := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		info, err := os.Stat(filepath.Join(path, entry.Name()))
+		if err != nil {
+			continue
+		}
+		stamps = append(stamps, entry.Name()+"="+fileStamp(info))
+	}
+	sort.Strings(stamps)
+	return strings.Join(stamps, ";")
+}
+
+func fileStamp(info os.FileInfo) string {
+	return fmt.Sprintf("%d/%d", info.ModTime().UnixNano(), info.Size())
+}
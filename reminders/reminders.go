@@ -0,0 +1,232 @@
+// Package reminders follows up with the people who accepted a connection
+// request and never replied. Once they have been silent for long enough
+// since the acceptance or the last message, a nudge rendered from a message
+// template is queued, up to a maximum per prospect. Every nudge is recorded
+// when it is queued, so re-running never messages anyone twice.
+package reminders
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/personalize"
+	"linkedin-automation/queue"
+	"linkedin-automation/storage"
+	"linkedin-automation/templates"
+)
+
+// Reasons a non-responder is not nudged
+const (
+	HeldMaxNudges = "max nudges reached"
+	HeldSequence  = "in a sequence"
+)
+
+// Store finds the non-responders and queues and records their nudges
+type Store interface {
+	queue.Store
+	GetNonResponders() ([]*storage.NonResponder, error)
+	GetProfileEnrollments(profileURL string) ([]*storage.SequenceEnrollment, error)
+	RecordReminderNudge(profileURL, template string, taskID int) error
+}
+
+// TemplateSource resolves the nudge template
+type TemplateSource interface {
+	Get(kind, name string) (*storage.Template, error)
+}
+
+// Options controls who is nudged, with what and how often
+type Options struct {
+	After     time.Duration // Silence since the acceptance or the last message before a nudge is due
+	MaxNudges int           // Nudges per prospect; 0 sends none
+	Template  string        // Message template of the nudges
+	Interval  time.Duration // Between scheduled checks; 0 leaves queuing to 'reminders queue'
+}
+
+// Reminder is a non-responder and when they are due a nudge
+type Reminder struct {
+	*storage.NonResponder
+	DueAt  time.Time `json:"due_at"`
+	Held   string    `json:"held,omitempty"`    // Why no nudge is queued however long they stay silent
+	TaskID int       `json:"task_id,omitempty"` // Queue task of the nudge, once queued
+}
+
+// Due reports whether a nudge should be queued for the reminder at now
+func (r *Reminder) Due(now time.Time) bool {
+	return r.Held == "" && !r.DueAt.After(now)
+}
+
+// Manager lists non-responders and queues their nudges
+type Manager struct {
+	store     Store
+	templates TemplateSource
+	opts      Options
+	logger    *logrus.Logger
+}
+
+// NewManager creates a manager nudging non-responders as opts says
+func NewManager(store Store, templates TemplateSource, opts Options, logger *logrus.Logger) *Manager {
+	return &Manager{
+		store:     store,
+		templates: templates,
+		opts:      opts,
+		logger:    logger,
+	}
+}
+
+// Enabled reports whether nudges are sent at all
+func (m *Manager) Enabled() bool {
+	return m.opts.After > 0 && m.opts.MaxNudges > 0 && m.opts.Template != ""
+}
+
+// List returns every non-responder with when their next nudge is due,
+// soonest first, and those held back last
+func (m *Manager) List() ([]*Reminder, error) {
+	people, err := m.store.GetNonResponders()
+	if err != nil {
+		return nil, err
+	}
+
+	reminders := make([]*Reminder, 0, len(people))
+	for _, person := range people {
+		reminder := &Reminder{NonResponder: person, DueAt: m.dueAt(person)}
+		if person.Nudges >= m.opts.MaxNudges {
+			reminder.Held = HeldMaxNudges
+		} else if enrolled, err := m.inSequence(person.ProfileURL); err != nil {
+			return nil, err
+		} else if enrolled {
+			reminder.Held = HeldSequence
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	sort.SliceStable(reminders, func(i, j int) bool {
+		if (reminders[i].Held == "") != (reminders[j].Held == "") {
+			return reminders[i].Held == ""
+		}
+		return reminders[i].DueAt.Before(reminders[j].DueAt)
+	})
+	return reminders, nil
+}
+
+// dueAt is when a non-responder has been silent for long enough since the
+// acceptance, the last message or the last nudge queued, whichever is latest
+func (m *Manager) dueAt(person *storage.NonResponder) time.Time {
+	last := person.AcceptedAt
+	for _, t := range []*time.Time{person.LastMessageAt, person.LastNudgeAt} {
+		if t != nil && t.After(last) {
+			last = *t
+		}
+	}
+	return last.Add(m.opts.After)
+}
+
+// inSequence reports whether a sequence is still messaging the prospect,
+// which takes precedence over reminders
+func (m *Manager) inSequence(profileURL string) (bool, error) {
+	enrollments, err := m.store.GetProfileEnrollments(profileURL)
+	if err != nil {
+		return false, err
+	}
+	for _, enrollment := range enrollments {
+		switch enrollment.Status {
+		case storage.EnrollmentActive, storage.EnrollmentRunning, storage.EnrollmentPaused:
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Content returns the nudge template, checked so a misspelled name or
+// placeholder fails before anything is queued
+func (m *Manager) Content() (string, error) {
+	t, err := m.templates.Get(templates.KindMessage, m.opts.Template)
+	if err != nil {
+		return "", fmt.Errorf("failed to load reminder template: %w", err)
+	}
+	if err := personalize.Validate(t.Content); err != nil {
+		return "", fmt.Errorf("reminder template %s: %w", m.opts.Template, err)
+	}
+	return t.Content, nil
+}
+
+// Queue queues a nudge as a message task for each non-responder due one at
+// now, at most limit of them unless limit is 0, and returns them. With
+// dryRun nothing is queued and the reminders that would be are returned.
+func (m *Manager) Queue(now time.Time, limit int, dryRun bool) ([]*Reminder, error) {
+	if !m.Enabled() {
+		return nil, nil
+	}
+	content, err := m.Content()
+	if err != nil {
+		return nil, err
+	}
+	reminders, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	queued := make([]*Reminder, 0)
+	for _, reminder := range reminders {
+		if limit > 0 && len(queued) >= limit {
+			break
+		}
+		if !reminder.Due(now) {
+			continue
+		}
+		if dryRun {
+			queued = append(queued, reminder)
+			continue
+		}
+
+		task, err := queue.Enqueue(m.store, queue.KindMessage, queue.MessagePayload{
+			RecipientURL: reminder.ProfileURL,
+			Content:      content,
+			Template:     m.opts.Template,
+		}, queue.Options{})
+		if err != nil {
+			return queued, err
+		}
+		if err := m.store.RecordReminderNudge(reminder.ProfileURL, m.opts.Template, task.ID); err != nil {
+			return queued, err
+		}
+		reminder.TaskID = task.ID
+		reminder.Nudges++
+		queued = append(queued, reminder)
+	}
+	return queued, nil
+}
+
+// Schedule queues the nudges due now and then every interval until ctx is
+// done, logging how many each check queued
+func (m *Manager) Schedule(ctx context.Context) {
+	if !m.Enabled() || m.opts.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+	for {
+		queued, err := m.Queue(time.Now(), 0, false)
+		if err != nil {
+			m.logger.WithError(err).Warn("Scheduled reminder check failed")
+		}
+		if len(queued) > 0 {
+			m.logger.WithFields(logrus.Fields{
+				"nudges":   len(queued),
+				"template": m.opts.Template,
+			}).Info("Queued reminders for connections who never replied")
+		} else if err == nil {
+			m.logger.Debug("No reminders due")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,194 @@
+// Package replies labels prospects' replies as interested, not interested or
+// later, from keyword rules or a language model, and acts on the label: an
+// interested prospect's sequences are paused for a person to take over, and a
+// prospect who asked to be contacted later is revisited then.
+package replies
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/message"
+	"linkedin-automation/opener"
+	"linkedin-automation/storage"
+)
+
+// Where a label came from
+const (
+	SourceKeyword = "keyword"
+	SourceAI      = "ai"
+)
+
+// Rules are the phrases that label a reply. A reply is labelled by the first
+// of not interested, later and interested that one of its phrases matches, so
+// "not interested" is not read as interested and "interested, but not right
+// now" as later.
+type Rules struct {
+	Interested    []string
+	NotInterested []string
+	Later         []string
+}
+
+// Store persists labels and the sequences they affect
+type Store interface {
+	SaveReplyLabel(label *storage.ReplyLabel) error
+	GetProfileEnrollments(profileURL string) ([]*storage.SequenceEnrollment, error)
+	UpdateSequenceEnrollment(enrollment *storage.SequenceEnrollment) error
+	CancelTask(id int) (bool, error)
+}
+
+// Classifier labels replies and updates the prospect's sequences
+type Classifier struct {
+	store        Store
+	rules        Rules
+	revisitAfter time.Duration
+	generator    opener.Generator
+	logger       *logrus.Logger
+}
+
+// NewClassifier creates a classifier. A prospect who replies later is
+// revisited after revisitAfter.
+func NewClassifier(store Store, rules Rules, revisitAfter time.Duration, logger *logrus.Logger) *Classifier {
+	return &Classifier{
+		store:        store,
+		rules:        rules,
+		revisitAfter: revisitAfter,
+		logger:       logger,
+	}
+}
+
+// SetGenerator asks a language model about replies no phrase matches
+func (c *Classifier) SetGenerator(generator opener.Generator) {
+	c.generator = generator
+}
+
+// Classify implements message.ReplyClassifier: it labels a new reply, records
+// the label and acts on it, returning an empty label for a reply it cannot
+// place
+func (c *Classifier) Classify(ctx context.Context, received *storage.ReceivedMessage) (string, error) {
+	label, source := c.match(received.Content), SourceKeyword
+	if label == "" && c.generator != nil {
+		var err error
+		if label, err = c.ask(ctx, received.Content); err != nil {
+			c.logger.WithError(err).WithField("sender", received.SenderURL).Warn("Failed to classify reply with AI")
+			return "", nil
+		}
+		source = SourceAI
+	}
+	if label == "" {
+		return "", nil
+	}
+
+	record := &storage.ReplyLabel{
+		ProfileURL: received.SenderURL,
+		Label:      label,
+		Source:     source,
+		MessageID:  received.ID,
+		Content:    received.Content,
+	}
+	if label == storage.ReplyLater {
+		revisitAt := time.Now().Add(c.revisitAfter)
+		record.RevisitAt = &revisitAt
+	}
+	if err := c.store.SaveReplyLabel(record); err != nil {
+		return "", err
+	}
+
+	if err := c.updateSequences(record); err != nil {
+		return "", err
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"sender": received.SenderURL,
+		"label":  label,
+		"source": source,
+	}).Info("Reply classified")
+	return label, nil
+}
+
+// match labels content by the phrase rules
+func (c *Classifier) match(content string) string {
+	for _, rule := range []struct {
+		label   string
+		phrases []string
+	}{
+		{storage.ReplyNotInterested, c.rules.NotInterested},
+		{storage.ReplyLater, c.rules.Later},
+		{storage.ReplyInterested, c.rules.Interested},
+	} {
+		if message.MatchOptOut(content, rule.phrases) != "" {
+			return rule.label
+		}
+	}
+	return ""
+}
+
+const classifyInstructions = `You sort the replies prospects send to LinkedIn outreach messages.
+Answer with exactly one of these words and nothing else:
+interested - they want to talk, learn more or meet
+not_interested - they decline or ask not to be contacted
+later - they might be interested but ask to be contacted again some time later
+unclear - none of these`
+
+// ask has the language model label content
+func (c *Classifier) ask(ctx context.Context, content string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	text, _, err := c.generator.Generate(ctx, classifyInstructions, "Reply: "+content, 10)
+	if err != nil {
+		return "", err
+	}
+
+	answer := strings.ToLower(strings.Trim(strings.TrimSpace(text), ".\"'"))
+	switch answer {
+	case storage.ReplyInterested, storage.ReplyNotInterested, storage.ReplyLater:
+		return answer, nil
+	case "unclear":
+		return "", nil
+	}
+	return "", fmt.Errorf("unexpected answer %q", text)
+}
+
+// updateSequences pauses an interested prospect's sequences, postpones those
+// of a prospect who replied later until the revisit, and stops those of a
+// prospect who is not interested
+func (c *Classifier) updateSequences(label *storage.ReplyLabel) error {
+	enrollments, err := c.store.GetProfileEnrollments(label.ProfileURL)
+	if err != nil {
+		return err
+	}
+
+	for _, enrollment := range enrollments {
+		if enrollment.Status != storage.EnrollmentActive && enrollment.Status != storage.EnrollmentRunning {
+			continue
+		}
+		if enrollment.Status == storage.EnrollmentRunning {
+			if _, err := c.store.CancelTask(enrollment.TaskID); err != nil {
+				return err
+			}
+			enrollment.TaskID = 0
+		}
+
+		switch label.Label {
+		case storage.ReplyInterested:
+			enrollment.Status = storage.EnrollmentPaused
+			enrollment.LastError = "replied interested"
+		case storage.ReplyNotInterested:
+			enrollment.Status = storage.EnrollmentStopped
+			enrollment.LastError = "replied not interested"
+		case storage.ReplyLater:
+			enrollment.Status = storage.EnrollmentActive
+			enrollment.NextRunAt = *label.RevisitAt
+			enrollment.LastError = ""
+		}
+		if err := c.store.UpdateSequenceEnrollment(enrollment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// Package resolve looks up LinkedIn IDs for names through the typeahead LinkedIn
+// uses to suggest search filters, caching what it finds in storage.
+package resolve
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/storage"
+	"linkedin-automation/voyager"
+)
+
+// CacheTTL is how long a resolved company is reused before it is looked up again
+const CacheTTL = 30 * 24 * time.Hour
+
+// Typeahead suggests companies for a name
+type Typeahead interface {
+	TypeaheadCompanies(ctx context.Context, keywords string) ([]*voyager.Company, error)
+}
+
+// Cache stores resolved companies
+type Cache interface {
+	GetCompanyURN(name string) (*storage.CompanyURN, error)
+	SaveCompanyURN(company *storage.CompanyURN) error
+}
+
+// Resolver resolves company names to company IDs
+type Resolver struct {
+	typeahead Typeahead
+	cache     Cache
+	logger    *logrus.Logger
+}
+
+// NewResolver creates a resolver that caches its results in cache
+func NewResolver(typeahead Typeahead, cache Cache, logger *logrus.Logger) *Resolver {
+	return &Resolver{
+		typeahead: typeahead,
+		cache:     cache,
+		logger:    logger,
+	}
+}
+
+// ResolveCompany returns the company ID for name
+func (r *Resolver) ResolveCompany(ctx context.Context, name string) (string, error) {
+	company, err := r.Company(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return company.CompanyID, nil
+}
+
+// Company returns the company for name, from the cache while it is fresh
+func (r *Resolver) Company(ctx context.Context, name string) (*storage.CompanyURN, error) {
+	cached, err := r.cache.GetCompanyURN(name)
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil && time.Since(cached.ResolvedAt) < CacheTTL {
+		return cached, nil
+	}
+
+	company, _, err := r.Lookup(ctx, name)
+	if err != nil {
+		if cached != nil {
+			r.logger.WithError(err).WithField("company", name).Warn("Company lookup failed, using expired cache entry")
+			return cached, nil
+		}
+		return nil, err
+	}
+	return company, nil
+}
+
+// Lookup queries the typeahead for name, bypassing the cache, and caches the
+// best match. It also returns every suggestion, best match first.
+func (r *Resolver) Lookup(ctx context.Context, name string) (*storage.CompanyURN, []*voyager.Company, error) {
+	if storage.NormalizeCompanyQuery(name) == "" {
+		return nil, nil, fmt.Errorf("company name is required")
+	}
+
+	candidates, err := r.typeahead.TypeaheadCompanies(ctx, strings.TrimSpace(name))
+	if err != nil {
+		return nil, nil, err
+	}
+	best := bestMatch(name, candidates)
+	if best == nil {
+		return nil, candidates, fmt.Errorf("no LinkedIn company matches %q", name)
+	}
+
+	company := &storage.CompanyURN{
+		Query:       storage.NormalizeCompanyQuery(name),
+		CompanyID:   best.ID,
+		CompanyName: best.Name,
+		ResolvedAt:  time.Now(),
+	}
+	if err := r.cache.SaveCompanyURN(company); err != nil {
+		return nil, candidates, err
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"company": name,
+		"id":      best.ID,
+		"match":   best.Name,
+	}).Debug("Company resolved")
+
+	// Put the best match first so callers can show it with the alternatives
+	ordered := []*voyager.Company{best}
+	for _, candidate := range candidates {
+		if candidate != best {
+			ordered = append(ordered, candidate)
+		}
+	}
+	return company, ordered, nil
+}
+
+// bestMatch prefers a suggestion named exactly like name and otherwise trusts
+// the typeahead's ranking
+func bestMatch(name string, candidates []*voyager.Company) *voyager.Company {
+	if len(candidates) == 0 {
+		return nil
+	}
+	wanted := storage.NormalizeCompanyQuery(name)
+	for _, candidate := range candidates {
+		if storage.NormalizeCompanyQuery(candidate.Name) == wanted {
+			return candidate
+		}
+	}
+	return candidates[0]
+}
@@ -0,0 +1,197 @@
+// Package restriction notices when LinkedIn restricts an account or blocks it
+// for a while, usually after flagging unusual activity. All automation then
+// stops at once, the restriction is recorded and reported to a webhook, and
+// no browser session opens for the account again until a cool-down passed.
+package restriction
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/errs"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/selectors"
+	"linkedin-automation/storage"
+	"linkedin-automation/uitext"
+)
+
+// maxReason caps how much of a notice's text is kept as the reason
+const maxReason = 200
+
+// Store keeps the restrictions noticed on each account
+type Store interface {
+	RecordRestriction(restriction *storage.Restriction) error
+	GetActiveRestriction(account string, now time.Time) (*storage.Restriction, error)
+}
+
+// Notifier reports a restriction to the operator
+type Notifier interface {
+	Notify(ctx context.Context, restriction *storage.Restriction) error
+}
+
+// Detect returns why page shows the account as restricted, or "" when it
+// does not: either LinkedIn sent it to a restriction page, or a banner or
+// heading on it speaks of unusual activity or a restricted account
+func Detect(page *rod.Page) string {
+	info, err := page.Info()
+	if err != nil {
+		return "" // Closed tabs are no longer looked at
+	}
+	if errs.IsRestrictedURL(info.URL) {
+		return "redirected to " + info.URL
+	}
+
+	for _, selector := range selectors.Get(selectors.RestrictionNotice) {
+		elements, err := page.Elements(selector)
+		if err != nil {
+			continue
+		}
+		for _, element := range elements {
+			text, err := element.Text()
+			if err != nil || !uitext.Contains(text, uitext.Restricted) {
+				continue
+			}
+			text = strings.Join(strings.Fields(text), " ")
+			if len(text) > maxReason {
+				text = text[:maxReason] + "..."
+			}
+			return text
+		}
+	}
+	return ""
+}
+
+// CheckCooldown returns an error matching errs.ErrRestricted while account
+// is cooling down from a restriction
+func CheckCooldown(store Store, account string, now time.Time) error {
+	restriction, err := store.GetActiveRestriction(account, now)
+	if err != nil {
+		return err
+	}
+	if restriction != nil {
+		return cooldownError(restriction)
+	}
+	return nil
+}
+
+func cooldownError(restriction *storage.Restriction) error {
+	return fmt.Errorf("%w (%s, noticed %s); automation is paused until %s",
+		errs.ErrRestricted, restriction.Reason,
+		restriction.DetectedAt.Local().Format("2006-01-02 15:04"),
+		restriction.CooldownUntil.Local().Format("2006-01-02 15:04"))
+}
+
+// Guard stops a browser session the moment LinkedIn restricts its account.
+// It wraps the session's rate limiter, which every action asks first, and
+// looks at the session's pages each time: once a restriction shows, it is
+// recorded with a cool-down and reported, and this and every later action
+// fails with errs.ErrRestricted.
+type Guard struct {
+	limiter  ratelimit.Limiter
+	store    Store
+	notifier Notifier
+	account  string
+	cooldown time.Duration
+	logger   *logrus.Logger
+	mu       sync.Mutex
+	pages    []*rod.Page
+	tripped  *storage.Restriction
+}
+
+// NewGuard watches the session of account, gating limiter, and records
+// restrictions in store with the given cool-down
+func NewGuard(limiter ratelimit.Limiter, store Store, account string, cooldown time.Duration, logger *logrus.Logger) *Guard {
+	return &Guard{
+		limiter:  limiter,
+		store:    store,
+		account:  account,
+		cooldown: cooldown,
+		logger:   logger,
+	}
+}
+
+// SetNotifier reports restrictions to notifier as well
+func (g *Guard) SetNotifier(notifier Notifier) {
+	g.notifier = notifier
+}
+
+// Watch adds a page the session works in
+func (g *Guard) Watch(page *rod.Page) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.pages = append(g.pages, page)
+}
+
+// Unwrap returns the limiter the guard gates
+func (g *Guard) Unwrap() ratelimit.Limiter {
+	return g.limiter
+}
+
+// WaitForPermission refuses the action once the account is restricted, then
+// defers to the wrapped limiter
+func (g *Guard) WaitForPermission(ctx context.Context, action ratelimit.ActionType) error {
+	if err := g.Check(ctx); err != nil {
+		return err
+	}
+	return g.limiter.WaitForPermission(ctx, action)
+}
+
+// Check looks for a restriction on the watched pages, recording and reporting
+// the first one found. After that it fails every time.
+func (g *Guard) Check(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.tripped == nil {
+		for _, page := range g.pages {
+			if reason := Detect(page); reason != "" {
+				url := ""
+				if info, err := page.Info(); err == nil {
+					url = info.URL
+				}
+				g.trip(ctx, reason, url)
+				break
+			}
+		}
+	}
+	if g.tripped != nil {
+		return cooldownError(g.tripped)
+	}
+	return nil
+}
+
+// trip records the restriction and reports it; failing to do either still
+// stops the session
+func (g *Guard) trip(ctx context.Context, reason, url string) {
+	now := time.Now()
+	g.tripped = &storage.Restriction{
+		Account:       g.account,
+		Reason:        reason,
+		URL:           url,
+		DetectedAt:    now,
+		CooldownUntil: now.Add(g.cooldown),
+	}
+	g.logger.WithFields(logrus.Fields{
+		"reason":         reason,
+		"url":            url,
+		"cooldown_until": g.tripped.CooldownUntil,
+	}).Error("LinkedIn restricted the account, stopping all automation")
+
+	if err := g.store.RecordRestriction(g.tripped); err != nil {
+		g.logger.WithError(err).Error("Failed to record restriction; the cool-down will not be enforced")
+	}
+	if g.notifier != nil {
+		// Reported even when the run was interrupted meanwhile
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		if err := g.notifier.Notify(ctx, g.tripped); err != nil {
+			g.logger.WithError(err).Warn("Failed to report restriction to webhook")
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package restriction
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"linkedin-automation/storage"
+)
+
+// Webhook posts restrictions as JSON to a URL
+type Webhook struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhook creates a notifier posting to url
+func NewWebhook(url string) *Webhook {
+	return &Webhook{url: url, httpClient: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// webhookPayload is what the webhook receives. Text makes chat incoming
+// webhooks, such as Slack's, show a readable message.
+type webhookPayload struct {
+	Event string `json:"event"`
+	Text  string `json:"text"`
+	*storage.Restriction
+}
+
+// Notify posts restriction to the webhook
+func (w *Webhook) Notify(ctx context.Context, restriction *storage.Restriction) error {
+	account := restriction.Account
+	if account == "" {
+		account = "the account"
+	}
+	data, err := json.Marshal(webhookPayload{
+		Event: "account_restricted",
+		Text: fmt.Sprintf("LinkedIn restricted %s: %s. Automation is paused until %s.",
+			account, restriction.Reason, restriction.CooldownUntil.Format(time.RFC3339)),
+		Restriction: restriction,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+// Package retention enforces how long personal data is kept: the text of
+// messages is cleared once it is older than the configured age, and people
+// with no activity for long enough are anonymized. The rows themselves stay,
+// so limits, analytics and opt-outs keep working and nobody is contacted
+// twice.
+package retention
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Database is the database the policy is enforced on
+type Database interface {
+	ClearMessageText(cutoff time.Time, dryRun bool) (map[string]int64, error)
+	InactiveProfiles(cutoff time.Time) ([]string, error)
+	AnonymizeProfile(url string, dryRun bool) (map[string]int64, error)
+}
+
+// Options controls how long data is kept and how often that is enforced
+type Options struct {
+	Messages time.Duration // Age after which message text is cleared; 0 keeps it
+	Profiles time.Duration // Inactivity after which people are anonymized; 0 keeps them
+	Interval time.Duration // Time between scheduled cleanups
+}
+
+// Report is what a cleanup cleared, or with DryRun would have
+type Report struct {
+	StartedAt  time.Time        `json:"started_at"`
+	DryRun     bool             `json:"dry_run"`
+	Messages   map[string]int64 `json:"messages"`   // Rows whose message text was cleared, per table
+	Profiles   []string         `json:"profiles"`   // People anonymized, by profile URL
+	Anonymized map[string]int64 `json:"anonymized"` // Rows anonymized, per table
+}
+
+// Empty reports whether the cleanup found nothing to clear
+func (r *Report) Empty() bool {
+	return len(r.Messages) == 0 && len(r.Anonymized) == 0
+}
+
+// Manager enforces the retention policy on a database
+type Manager struct {
+	db     Database
+	opts   Options
+	logger *logrus.Logger
+}
+
+// NewManager creates a manager enforcing opts on db
+func NewManager(db Database, opts Options, logger *logrus.Logger) *Manager {
+	return &Manager{
+		db:     db,
+		opts:   opts,
+		logger: logger,
+	}
+}
+
+// Enabled reports whether the policy clears anything
+func (m *Manager) Enabled() bool {
+	return m.opts.Messages > 0 || m.opts.Profiles > 0
+}
+
+// Run clears the message text older than the policy allows and anonymizes
+// the people inactive for longer than it does, as of now. With dryRun
+// nothing changes and the report lists what would.
+func (m *Manager) Run(now time.Time, dryRun bool) (*Report, error) {
+	report := &Report{
+		StartedAt:  now,
+		DryRun:     dryRun,
+		Messages:   make(map[string]int64),
+		Anonymized: make(map[string]int64),
+	}
+
+	if m.opts.Messages > 0 {
+		cleared, err := m.db.ClearMessageText(now.Add(-m.opts.Messages), dryRun)
+		if err != nil {
+			return nil, err
+		}
+		report.Messages = cleared
+	}
+
+	if m.opts.Profiles > 0 {
+		inactive, err := m.db.InactiveProfiles(now.Add(-m.opts.Profiles))
+		if err != nil {
+			return nil, err
+		}
+		for _, url := range inactive {
+			anonymized, err := m.db.AnonymizeProfile(url, dryRun)
+			if err != nil {
+				return nil, err
+			}
+			if len(anonymized) == 0 {
+				continue
+			}
+			report.Profiles = append(report.Profiles, url)
+			for table, count := range anonymized {
+				report.Anonymized[table] += count
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// Schedule runs the cleanup now and then every interval until ctx is done,
+// logging what each run cleared
+func (m *Manager) Schedule(ctx context.Context) {
+	if !m.Enabled() || m.opts.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+	for {
+		report, err := m.Run(time.Now(), false)
+		if err != nil {
+			m.logger.WithError(err).Warn("Scheduled retention cleanup failed")
+		} else {
+			m.log(report)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// log reports what a cleanup cleared
+func (m *Manager) log(report *Report) {
+	if report.Empty() {
+		m.logger.Debug("Retention cleanup found nothing to clear")
+		return
+	}
+
+	fields := logrus.Fields{"profiles_anonymized": len(report.Profiles)}
+	for table, count := range report.Messages {
+		fields["cleared_"+table] = count
+	}
+	for table, count := range report.Anonymized {
+		fields["anonymized_"+table] = count
+	}
+	m.logger.WithFields(fields).Info("Retention cleanup cleared old data")
+}
@@ -0,0 +1,75 @@
+// Package retry retries failed actions with exponential backoff, giving up
+// early on errors that another attempt cannot fix.
+package retry
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"linkedin-automation/errs"
+)
+
+// Policy controls how often and how quickly a failed action is retried
+type Policy struct {
+	MaxAttempts  int              // Total tries including the first; values below 1 mean a single try
+	InitialDelay time.Duration    // Wait before the second try
+	MaxDelay     time.Duration    // Upper bound for the wait between tries
+	Multiplier   float64          // Growth of the wait after each failure
+	Retryable    func(error) bool // Which errors are retried; defaults to errs.Retryable
+}
+
+// DefaultPolicy tries an action three times, waiting about 5s and then 10s
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts:  3,
+		InitialDelay: 5 * time.Second,
+		MaxDelay:     time.Minute,
+		Multiplier:   2,
+	}
+}
+
+// Do calls fn until it succeeds, returns an error that is not retryable, the
+// attempts run out or ctx is cancelled. onRetry, if set, is called before each
+// wait. The last error from fn is returned.
+func (p Policy) Do(ctx context.Context, fn func() error, onRetry func(attempt int, err error, delay time.Duration)) error {
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = errs.Retryable
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.MaxAttempts || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		delay := p.Backoff(attempt)
+		if onRetry != nil {
+			onRetry(attempt, err, delay)
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// Backoff returns the wait after the given failed attempt, counting from 1,
+// with up to 20% jitter so retries do not fall into a regular rhythm
+func (p Policy) Backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialDelay) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	delay *= 0.8 + rand.Float64()*0.4
+	return time.Duration(delay)
+}
@@ -0,0 +1,118 @@
+// Package scoring rates how well prospects fit the ideal customer profile
+// (ICP), from weighted rules on their title, seniority, company size and
+// location, so outreach can go to the best fits first.
+package scoring
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"linkedin-automation/storage"
+)
+
+// Rule adds its weight to the score of every profile that meets all of its
+// conditions. A condition left empty is not checked.
+type Rule struct {
+	Name           string   `yaml:"name"`
+	Weight         int      `yaml:"weight"`           // Negative to push poor fits down
+	Title          string   `yaml:"title"`            // Regular expression matched against the title and headline, ignoring case
+	Seniority      []string `yaml:"seniority"`        // Any of these words in the title or headline, e.g. VP, Head, Director
+	Location       string   `yaml:"location"`         // Regular expression matched against the location, ignoring case
+	MinCompanySize int      `yaml:"min_company_size"` // Profiles whose company size is unknown never meet a size condition
+	MaxCompanySize int      `yaml:"max_company_size"`
+}
+
+// Model scores profiles with a set of rules
+type Model struct {
+	rules []*rule
+}
+
+type rule struct {
+	Rule
+	title     *regexp.Regexp
+	seniority *regexp.Regexp
+	location  *regexp.Regexp
+}
+
+// New compiles rules into a model
+func New(rules []Rule) (*Model, error) {
+	model := &Model{}
+	for i, r := range rules {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("rules[%d]", i)
+		}
+		if r.Title == "" && len(r.Seniority) == 0 && r.Location == "" && r.MinCompanySize == 0 && r.MaxCompanySize == 0 {
+			return nil, fmt.Errorf("scoring rule %s has no conditions", name)
+		}
+		if r.MinCompanySize < 0 || r.MaxCompanySize < 0 || (r.MaxCompanySize > 0 && r.MinCompanySize > r.MaxCompanySize) {
+			return nil, fmt.Errorf("scoring rule %s: invalid company size range", name)
+		}
+
+		compiled := &rule{Rule: r}
+		var err error
+		if compiled.title, err = compile(r.Title); err != nil {
+			return nil, fmt.Errorf("scoring rule %s: title: %w", name, err)
+		}
+		if compiled.location, err = compile(r.Location); err != nil {
+			return nil, fmt.Errorf("scoring rule %s: location: %w", name, err)
+		}
+		if len(r.Seniority) > 0 {
+			words := make([]string, len(r.Seniority))
+			for j, word := range r.Seniority {
+				words[j] = regexp.QuoteMeta(strings.TrimSpace(word))
+			}
+			compiled.seniority = regexp.MustCompile(`(?i)\b(?:` + strings.Join(words, "|") + `)\b`)
+		}
+		compiled.Name = name
+		model.rules = append(model.rules, compiled)
+	}
+	return model, nil
+}
+
+func compile(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile("(?i)" + pattern)
+}
+
+// Empty reports whether the model has no rules, so every profile scores 0
+func (m *Model) Empty() bool {
+	return len(m.rules) == 0
+}
+
+// Score returns the sum of the weights of the rules the profile meets
+func (m *Model) Score(profile *storage.Profile) int {
+	score, _ := m.Explain(profile)
+	return score
+}
+
+// Explain returns the profile's score and the names of the rules it meets
+func (m *Model) Explain(profile *storage.Profile) (int, []string) {
+	role := profile.Title + "\n" + profile.Headline
+
+	score := 0
+	var matched []string
+	for _, r := range m.rules {
+		if r.title != nil && !r.title.MatchString(role) {
+			continue
+		}
+		if r.seniority != nil && !r.seniority.MatchString(role) {
+			continue
+		}
+		if r.location != nil && !r.location.MatchString(profile.Location) {
+			continue
+		}
+		if r.MinCompanySize > 0 || r.MaxCompanySize > 0 {
+			size := profile.CompanySize
+			if size == 0 || size < r.MinCompanySize || (r.MaxCompanySize > 0 && size > r.MaxCompanySize) {
+				continue
+			}
+		}
+		score += r.Weight
+		matched = append(matched, r.Name)
+	}
+	return score, matched
+}
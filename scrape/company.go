@@ -0,0 +1,374 @@
+package scrape
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/errs"
+	"linkedin-automation/pause"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/selectors"
+	"linkedin-automation/uitext"
+)
+
+// ScrapeManager extracts prospects and posts from LinkedIn pages other than search
+type ScrapeManager struct {
+	page        *rod.Page
+	logger      *logrus.Logger
+	stealth     StealthManager
+	rateLimiter RateLimiter
+	capturer    Capturer
+}
+
+// StealthManager interface for stealth operations
+type StealthManager interface {
+	RandomDelay() time.Duration
+	HumanLikeScroll(page *rod.Page, scrollAmount int) error
+}
+
+// RateLimiter gates actions against configured quotas
+type RateLimiter interface {
+	WaitForPermission(ctx context.Context, action ratelimit.ActionType) error
+}
+
+// Capturer saves the page for debugging when an action fails
+type Capturer interface {
+	Failure(page *rod.Page, action string, err error)
+}
+
+// CompanyOptions controls how much of a company page is scraped
+type CompanyOptions struct {
+	MaxResults int // Maximum number of employees
+	Posts      int // Maximum number of recent posts; 0 skips posts
+}
+
+// Employee is a person listed on a company's People tab
+type Employee struct {
+	Name       string
+	Headline   string
+	ProfileURL string
+}
+
+// CompanyPost is a post from a company page
+type CompanyPost struct {
+	URN        string
+	AuthorName string
+	Text       string
+}
+
+// CompanyResult represents everything scraped from one company page
+type CompanyResult struct {
+	CompanyURL     string // Canonical /company/<name>/ URL
+	Name           string
+	Size           int // Employees as the page states it, a range counting as its lower bound; 0 if not shown
+	Employees      []*Employee
+	Posts          []*CompanyPost
+	StoppedAtLimit bool // Pagination ended early because the search quota was exhausted
+	Duration       time.Duration
+}
+
+// NewScrapeManager creates a new scrape manager
+func NewScrapeManager(page *rod.Page, logger *logrus.Logger, stealth StealthManager) *ScrapeManager {
+	return &ScrapeManager{
+		page:    page,
+		logger:  logger,
+		stealth: stealth,
+	}
+}
+
+// SetRateLimiter enables quota enforcement; each page of results counts as a search
+func (s *ScrapeManager) SetRateLimiter(limiter RateLimiter) {
+	s.rateLimiter = limiter
+}
+
+// SetCapturer saves a screenshot and HTML snapshot of the page whenever
+// scraping fails
+func (s *ScrapeManager) SetCapturer(capturer Capturer) {
+	s.capturer = capturer
+}
+
+// ScrapeCompany extracts the employee list and recent posts of a company page
+func (s *ScrapeManager) ScrapeCompany(ctx context.Context, companyURL string, opts CompanyOptions) (result *CompanyResult, err error) {
+	slug := CompanySlug(companyURL)
+	if slug == "" {
+		return nil, fmt.Errorf("not a company page URL: %s", companyURL)
+	}
+	defer s.withContext(ctx)()
+	defer func() {
+		if err != nil && s.capturer != nil {
+			s.capturer.Failure(s.page, "scrape company", err)
+		}
+	}()
+
+	startTime := time.Now()
+	result = &CompanyResult{
+		CompanyURL: CompanyURL(slug),
+		Employees:  make([]*Employee, 0),
+		Posts:      make([]*CompanyPost, 0),
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"company":     slug,
+		"max_results": opts.MaxResults,
+		"posts":       opts.Posts,
+	}).Info("Starting company scrape")
+
+	if err := s.scrapeEmployees(ctx, result, opts.MaxResults); err != nil {
+		return nil, err
+	}
+
+	if opts.Posts > 0 && !result.StoppedAtLimit {
+		if err := s.scrapePosts(ctx, result, opts.Posts); err != nil {
+			s.logger.WithError(err).Warn("Failed to scrape company posts")
+		}
+	}
+
+	result.Duration = time.Since(startTime)
+
+	s.logger.WithFields(logrus.Fields{
+		"company":   slug,
+		"employees": len(result.Employees),
+		"posts":     len(result.Posts),
+		"duration":  result.Duration,
+	}).Info("Company scrape completed")
+
+	return result, nil
+}
+
+// scrapeEmployees reads the People tab, clicking "Show more results" until
+// enough employees are found or the list ends
+func (s *ScrapeManager) scrapeEmployees(ctx context.Context, result *CompanyResult, maxResults int) error {
+	if err := s.waitForPermission(ctx); err != nil {
+		return err
+	}
+
+	if err := s.open(result.CompanyURL + "people/"); err != nil {
+		return err
+	}
+	result.Name = selectors.Text(s.page, selectors.CompanyName)
+	for _, element := range selectors.FindAll(s.page, selectors.CompanySize) {
+		if text, err := element.Text(); err == nil && uitext.Contains(text, uitext.Employees) {
+			result.Size = ParseCompanySize(text)
+			break
+		}
+	}
+
+	seen := make(map[string]bool)
+	// Bounded to prevent an endless loop on a list that never ends
+	for pageNum := 1; pageNum <= 100 && len(result.Employees) < maxResults; pageNum++ {
+		found := s.extractEmployees(result, seen, maxResults)
+		s.logger.WithFields(logrus.Fields{
+			"page":  pageNum,
+			"found": found,
+			"total": len(result.Employees),
+		}).Debug("Extracted employees")
+
+		if len(result.Employees) >= maxResults {
+			break
+		}
+
+		button, _ := selectors.Find(s.page, selectors.ShowMoreButton)
+		if button == nil {
+			// Older layouts load more cards on scroll; stop once scrolling adds nothing
+			if found == 0 && pageNum > 1 {
+				break
+			}
+			if err := s.stealth.HumanLikeScroll(s.page, 1000); err != nil {
+				s.logger.WithError(err).Debug("Failed to scroll people list")
+			}
+			if err := pause.Sleep(ctx, s.stealth.RandomDelay()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Each page of results counts against the search quota
+		if err := s.waitForPermission(ctx); err != nil {
+			if errors.Is(err, ratelimit.ErrLimitReached) {
+				s.logger.WithError(err).Warn("Stopping company scrape at rate limit")
+				result.StoppedAtLimit = true
+				return nil
+			}
+			return err
+		}
+
+		if err := button.ScrollIntoView(); err != nil {
+			s.logger.WithError(err).Debug("Failed to scroll to load button")
+		}
+		if err := button.Click("left", 1); err != nil {
+			return fmt.Errorf("failed to load more employees: %w", err)
+		}
+		if err := pause.Sleep(ctx, s.stealth.RandomDelay()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// extractEmployees adds employee cards not seen before, returning how many were added
+func (s *ScrapeManager) extractEmployees(result *CompanyResult, seen map[string]bool, maxResults int) int {
+	cards := selectors.FindAll(s.page, selectors.CompanyPeopleCard)
+
+	added := 0
+	for _, card := range cards {
+		link := selectors.FindIn(card, selectors.ProfileLink)
+		if link == nil {
+			// Members outside the viewer's network are shown as "LinkedIn Member" without a link
+			continue
+		}
+		href, err := link.Attribute("href")
+		if err != nil || href == nil || profileurl.Slug(*href) == "" {
+			continue
+		}
+
+		profileURL := profileurl.Canonicalize(*href)
+		if seen[profileURL] {
+			continue
+		}
+		seen[profileURL] = true
+
+		result.Employees = append(result.Employees, &Employee{
+			Name:       selectors.TextIn(card, selectors.CompanyPeopleName),
+			Headline:   selectors.TextIn(card, selectors.CompanyPeopleDetail),
+			ProfileURL: profileURL,
+		})
+		added++
+
+		if len(result.Employees) >= maxResults {
+			break
+		}
+	}
+
+	return added
+}
+
+// scrapePosts reads the most recent posts from the company's Posts tab
+func (s *ScrapeManager) scrapePosts(ctx context.Context, result *CompanyResult, limit int) error {
+	if err := s.waitForPermission(ctx); err != nil {
+		if errors.Is(err, ratelimit.ErrLimitReached) {
+			result.StoppedAtLimit = true
+			return nil
+		}
+		return err
+	}
+
+	if err := s.open(result.CompanyURL + "posts/"); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for attempt := 0; attempt < 10 && len(result.Posts) < limit; attempt++ {
+		for _, element := range selectors.FindAll(s.page, selectors.Post) {
+			urn, err := element.Attribute("data-urn")
+			if err != nil || urn == nil || seen[*urn] {
+				continue
+			}
+			seen[*urn] = true
+
+			result.Posts = append(result.Posts, &CompanyPost{
+				URN:        *urn,
+				AuthorName: selectors.TextIn(element, selectors.PostAuthor),
+				Text:       selectors.TextIn(element, selectors.PostText),
+			})
+			if len(result.Posts) == limit {
+				return nil
+			}
+		}
+
+		// Load more of the feed
+		if err := s.stealth.HumanLikeScroll(s.page, 800); err != nil {
+			s.logger.WithError(err).Debug("Failed to scroll company posts")
+		}
+		if err := pause.Sleep(ctx, time.Second); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// withContext binds the page to ctx until the returned function is called,
+// so navigation, element lookups and pauses stop once ctx is cancelled
+func (s *ScrapeManager) withContext(ctx context.Context) func() {
+	page := s.page
+	s.page = page.Context(ctx)
+	return func() { s.page = page }
+}
+
+func (s *ScrapeManager) open(pageURL string) error {
+	if err := s.page.Navigate(pageURL); err != nil {
+		return fmt.Errorf("failed to navigate to %s: %w", pageURL, err)
+	}
+	if err := s.page.WaitLoad(); err != nil {
+		return fmt.Errorf("failed to wait for page load: %w", err)
+	}
+
+	info, err := s.page.Info()
+	if err != nil {
+		return err
+	}
+	if err := errs.CheckPageURL(info.URL); err != nil {
+		return err
+	}
+
+	return pause.Page(s.page, s.stealth.RandomDelay())
+}
+
+func (s *ScrapeManager) waitForPermission(ctx context.Context) error {
+	if s.rateLimiter == nil {
+		return nil
+	}
+	return s.rateLimiter.WaitForPermission(ctx, ratelimit.ActionSearch)
+}
+
+// CompanySlug returns the company name or ID from a /company/ URL, or "" if
+// the URL is not a company page
+func CompanySlug(raw string) string {
+	i := strings.Index(raw, "/company/")
+	if i < 0 {
+		return ""
+	}
+	rest := strings.Trim(raw[i+len("/company/"):], "/")
+	rest = strings.SplitN(rest, "?", 2)[0]
+	return strings.SplitN(rest, "/", 2)[0]
+}
+
+// CompanyURL returns the canonical company page URL for a slug
+func CompanyURL(slug string) string {
+	return "https://www.linkedin.com/company/" + slug + "/"
+}
+
+var companySizePattern = regexp.MustCompile(`(\d[\d,.\s\x{00a0}]*)([KkM])?`)
+
+// ParseCompanySize reads the first number of a company size such as
+// "201-500 employees", "10,001+ employees" or "1K-5K employees", or 0 if there
+// is none
+func ParseCompanySize(text string) int {
+	match := companySizePattern.FindStringSubmatch(text)
+	if match == nil {
+		return 0
+	}
+
+	digits := strings.NewReplacer(",", "", ".", "", " ", "", "\u00a0", "").Replace(match[1])
+	size, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0
+	}
+	switch match[2] {
+	case "K", "k":
+		size *= 1000
+	case "M":
+		size *= 1000000
+	}
+	return size
+}
@@ -0,0 +1,163 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/uitext"
+	"linkedin-automation/voyager"
+)
+
+// APIClient runs people searches through LinkedIn's internal API
+type APIClient interface {
+	SearchPeople(ctx context.Context, params voyager.SearchParams, start int) ([]*voyager.Person, error)
+}
+
+// apiFacets maps search URL parameters to their API filter names. Anything not
+// listed is either handled separately or has no API equivalent.
+var apiFacets = map[string]string{
+	"currentCompany":  "currentCompany",
+	"geoUrn":          "geoUrn",
+	"network":         "network",
+	"industry":        "industry",
+	"pastCompany":     "pastCompany",
+	"schoolFilter":    "schoolFilter",
+	"profileLanguage": "profileLanguage",
+	"titleFreeText":   "title",
+	"company":         "company",
+	"schoolFreetext":  "schoolFreetext",
+}
+
+// SetAPIClient makes SearchUsers try the API before scraping search pages
+func (s *SearchManager) SetAPIClient(client APIClient) {
+	s.apiClient = client
+}
+
+// searchAPI runs query through the API client, one page of results per search
+// quota unit, starting from the query's start page like the browser does
+func (s *SearchManager) searchAPI(ctx context.Context, query SearchQuery, session *SearchSession) error {
+	params, err := s.buildAPIParams(query)
+	if err != nil {
+		return err
+	}
+
+	searchQuery := fmt.Sprintf("keywords:%s,title:%s,company:%s,location:%s",
+		query.Keywords, query.Title, query.Company, query.Location)
+	seen := make(map[string]bool)
+	first := max(query.StartPage, 1)
+	for pageNum := first; pageNum <= maxSearchPages; pageNum++ {
+		if len(session.Results) >= query.MaxResults {
+			session.NextPage = pageNum
+			return nil
+		}
+		start := (pageNum - 1) * voyager.SearchPageSize
+
+		if err := s.waitForPermission(ctx); err != nil {
+			if pageNum > first && errors.Is(err, ratelimit.ErrLimitReached) {
+				s.logger.WithError(err).Warn("Stopping pagination at rate limit")
+				session.StoppedAtLimit = true
+				session.NextPage = pageNum
+				return nil
+			}
+			return err
+		}
+
+		people, err := s.apiClient.SearchPeople(ctx, params, start)
+		if err != nil {
+			return err
+		}
+
+		for _, person := range people {
+			if seen[person.ProfileURL] {
+				continue
+			}
+			seen[person.ProfileURL] = true
+			result := &SearchResult{
+				URL:         person.ProfileURL,
+				Name:        person.Name,
+				Title:       person.Headline,
+				Location:    person.Location,
+				ProfileURL:  person.ProfileURL,
+				SearchQuery: searchQuery,
+				Degree:      person.Degree,
+			}
+			for _, insight := range person.Insights {
+				if mutual := uitext.MutualCount(insight); mutual > 0 {
+					result.MutualConnections = mutual
+					break
+				}
+			}
+			if !query.Matches(result) {
+				session.Filtered++
+				continue
+			}
+			session.Results = append(session.Results, result)
+		}
+
+		s.logger.WithFields(logrus.Fields{
+			"start": start,
+			"found": len(people),
+			"total": len(session.Results),
+		}).Debug("Fetched API search page")
+
+		if len(session.Results) > query.MaxResults {
+			// Results were left on this page; a later run reads it again
+			session.NextPage = pageNum
+			return nil
+		}
+		if len(people) < voyager.SearchPageSize {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+
+	return nil
+}
+
+// buildAPIParams translates a query into API filters. It goes through
+// buildSearchURL so both modes validate and interpret queries identically.
+func (s *SearchManager) buildAPIParams(query SearchQuery) (voyager.SearchParams, error) {
+	searchURL, err := s.buildSearchURL(query)
+	if err != nil {
+		return voyager.SearchParams{}, err
+	}
+
+	parsed, err := url.Parse(searchURL)
+	if err != nil {
+		return voyager.SearchParams{}, fmt.Errorf("failed to parse search URL: %w", err)
+	}
+
+	params := voyager.SearchParams{Filters: make(map[string][]string)}
+	for key, values := range parsed.Query() {
+		if key == "keywords" {
+			params.Keywords = values[0]
+			continue
+		}
+
+		name, ok := apiFacets[key]
+		if !ok {
+			continue
+		}
+
+		// Facets are JSON lists of IDs; free-text parameters are plain strings
+		var ids []string
+		if err := json.Unmarshal([]byte(values[0]), &ids); err != nil {
+			ids = []string{values[0]}
+		}
+		params.Filters[name] = ids
+	}
+
+	return params, nil
+}
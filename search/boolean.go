@@ -0,0 +1,261 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Keywords is a keyword search parsed as a LinkedIn boolean expression: terms,
+// "quoted phrases", parentheses and the upper-case operators AND, OR and NOT.
+// Terms next to each other are implicitly ANDed.
+type Keywords struct {
+	Expression string   // normalized expression, as sent to LinkedIn
+	Boolean    bool     // uses operators or parentheses
+	Warnings   []string // syntax LinkedIn does not support and searches as plain text
+}
+
+type keywordTokenKind int
+
+const (
+	tokenTerm keywordTokenKind = iota
+	tokenPhrase
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+)
+
+type keywordToken struct {
+	kind  keywordTokenKind
+	value string
+}
+
+// unsupportedOperators maps syntax from other search engines to LinkedIn's operator
+var unsupportedOperators = map[string]string{
+	"&&": "AND",
+	"&":  "AND",
+	"||": "OR",
+	"|":  "OR",
+	"!":  "NOT",
+}
+
+// ParseKeywords validates a boolean keyword search and normalizes its spacing.
+// Unbalanced quotes or parentheses and operators without operands are errors;
+// syntax LinkedIn ignores is reported in Warnings.
+func ParseKeywords(keywords string) (*Keywords, error) {
+	tokens, warnings, err := tokenizeKeywords(keywords)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := &Keywords{Warnings: warnings}
+	if len(tokens) == 0 {
+		return parsed, nil
+	}
+
+	p := &keywordParser{tokens: tokens}
+	if err := p.parseOr(); err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		// parseOr only stops early at a closing parenthesis without a match
+		return nil, fmt.Errorf("invalid keywords: unmatched ')'")
+	}
+
+	var b strings.Builder
+	for i, token := range tokens {
+		if i > 0 && tokens[i-1].kind != tokenOpen && token.kind != tokenClose {
+			b.WriteByte(' ')
+		}
+		switch token.kind {
+		case tokenTerm:
+			b.WriteString(token.value)
+		case tokenPhrase:
+			b.WriteString(`"` + token.value + `"`)
+		case tokenAnd, tokenOr, tokenNot:
+			parsed.Boolean = true
+			b.WriteString(token.value)
+		case tokenOpen:
+			parsed.Boolean = true
+			b.WriteByte('(')
+		case tokenClose:
+			b.WriteByte(')')
+		}
+	}
+	parsed.Expression = b.String()
+
+	return parsed, nil
+}
+
+func tokenizeKeywords(keywords string) ([]keywordToken, []string, error) {
+	var tokens []keywordToken
+	var warnings []string
+
+	for i := 0; i < len(keywords); {
+		switch c := keywords[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"':
+			end := strings.IndexByte(keywords[i+1:], '"')
+			if end < 0 {
+				return nil, nil, fmt.Errorf("invalid keywords: unterminated quoted phrase starting at %q", keywords[i:])
+			}
+			phrase := strings.Join(strings.Fields(keywords[i+1:i+1+end]), " ")
+			if phrase == "" {
+				return nil, nil, fmt.Errorf("invalid keywords: empty quoted phrase")
+			}
+			tokens = append(tokens, keywordToken{kind: tokenPhrase, value: phrase})
+			i += end + 2
+		case c == '(':
+			tokens = append(tokens, keywordToken{kind: tokenOpen})
+			i++
+		case c == ')':
+			tokens = append(tokens, keywordToken{kind: tokenClose})
+			i++
+		default:
+			end := i
+			for end < len(keywords) && !strings.ContainsRune(" \t\n\r\"()", rune(keywords[end])) {
+				end++
+			}
+			word := keywords[i:end]
+			i = end
+
+			switch word {
+			case "AND":
+				tokens = append(tokens, keywordToken{kind: tokenAnd, value: word})
+				continue
+			case "OR":
+				tokens = append(tokens, keywordToken{kind: tokenOr, value: word})
+				continue
+			case "NOT":
+				tokens = append(tokens, keywordToken{kind: tokenNot, value: word})
+				continue
+			}
+
+			if operator, ok := unsupportedOperators[word]; ok {
+				warnings = append(warnings, fmt.Sprintf("%q is not a LinkedIn operator and is searched as text; use %s", word, operator))
+			} else if upper := strings.ToUpper(word); upper == "AND" || upper == "OR" || upper == "NOT" {
+				warnings = append(warnings, fmt.Sprintf("%q is searched as a word; operators must be upper case (%s)", word, upper))
+			} else if len(word) > 1 && (word[0] == '-' || word[0] == '+') {
+				warnings = append(warnings, fmt.Sprintf("the %q prefix in %q is not supported; use NOT to exclude and a plain term to require", word[:1], word))
+			} else if strings.Contains(word, "*") {
+				warnings = append(warnings, fmt.Sprintf("wildcards are not supported; %q is searched as written", word))
+			}
+			tokens = append(tokens, keywordToken{kind: tokenTerm, value: word})
+		}
+	}
+
+	return tokens, warnings, nil
+}
+
+// keywordParser checks tokens against the grammar
+//
+//	or      = and { "OR" and }
+//	and     = not { [ "AND" ] not }
+//	not     = "NOT" not | primary
+//	primary = term | phrase | "(" or ")"
+type keywordParser struct {
+	tokens []keywordToken
+	pos    int
+}
+
+func (p *keywordParser) peek() (keywordToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return keywordToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *keywordParser) parseOr() error {
+	if err := p.parseAnd(); err != nil {
+		return err
+	}
+	for {
+		token, ok := p.peek()
+		if !ok || token.kind != tokenOr {
+			return nil
+		}
+		p.pos++
+		if err := p.parseAnd(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *keywordParser) parseAnd() error {
+	if err := p.parseNot(); err != nil {
+		return err
+	}
+	for {
+		token, ok := p.peek()
+		if !ok || token.kind == tokenOr || token.kind == tokenClose {
+			return nil
+		}
+		if token.kind == tokenAnd {
+			p.pos++
+		}
+		if err := p.parseNot(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *keywordParser) parseNot() error {
+	if token, ok := p.peek(); ok && token.kind == tokenNot {
+		p.pos++
+		return p.parseNot()
+	}
+	return p.parsePrimary()
+}
+
+func (p *keywordParser) parsePrimary() error {
+	token, ok := p.peek()
+	if !ok {
+		return fmt.Errorf("invalid keywords: expected a term after %s", p.previous())
+	}
+
+	switch token.kind {
+	case tokenTerm, tokenPhrase:
+		p.pos++
+		return nil
+	case tokenOpen:
+		p.pos++
+		if next, ok := p.peek(); ok && next.kind == tokenClose {
+			return fmt.Errorf("invalid keywords: empty parentheses")
+		}
+		if err := p.parseOr(); err != nil {
+			return err
+		}
+		if next, ok := p.peek(); !ok || next.kind != tokenClose {
+			return fmt.Errorf("invalid keywords: unmatched '('")
+		}
+		p.pos++
+		return nil
+	case tokenClose:
+		if p.pos == 0 || p.tokens[p.pos-1].kind == tokenOpen {
+			return fmt.Errorf("invalid keywords: unmatched ')'")
+		}
+		return fmt.Errorf("invalid keywords: expected a term after %s", p.previous())
+	default:
+		if p.pos == 0 {
+			return fmt.Errorf("invalid keywords: %s needs a term before it", token.value)
+		}
+		return fmt.Errorf("invalid keywords: expected a term after %s, got %s", p.previous(), token.value)
+	}
+}
+
+// previous describes the token before the current position for error messages
+func (p *keywordParser) previous() string {
+	if p.pos == 0 {
+		return "the start"
+	}
+	switch token := p.tokens[p.pos-1]; token.kind {
+	case tokenOpen:
+		return "'('"
+	case tokenPhrase:
+		return `"` + token.value + `"`
+	default:
+		return token.value
+	}
+}
@@ -0,0 +1,136 @@
+package search
+
+import (
+	"testing"
+)
+
+func TestParseKeywords(t *testing.T) {
+	tests := []struct {
+		name         string
+		keywords     string
+		want         string
+		wantBoolean  bool
+		wantWarnings int
+		wantErr      bool
+	}{
+		{name: "empty", keywords: "  ", want: ""},
+		{name: "plain terms", keywords: "  go   developer ", want: "go developer"},
+		{name: "phrase", keywords: `"product   manager"`, want: `"product manager"`},
+		{name: "operators", keywords: "go AND (rust OR zig) NOT java", want: "go AND (rust OR zig) NOT java", wantBoolean: true},
+		{name: "spacing around parentheses", keywords: "( go OR rust )AND remote", want: "(go OR rust) AND remote", wantBoolean: true},
+		{name: "parentheses alone", keywords: "(go rust)", want: "(go rust)", wantBoolean: true},
+		{name: "phrase next to parenthesis", keywords: `("vp sales"OR cro)`, want: `("vp sales" OR cro)`, wantBoolean: true},
+		{name: "nested not", keywords: "NOT NOT go", want: "NOT NOT go", wantBoolean: true},
+		{name: "lower case operator", keywords: "go or rust", want: "go or rust", wantWarnings: 1},
+		{name: "other engines' operators", keywords: "go || rust && zig", want: "go || rust && zig", wantWarnings: 2},
+		{name: "exclusion prefix", keywords: "go -java", want: "go -java", wantWarnings: 1},
+		{name: "wildcard", keywords: "develop*", want: "develop*", wantWarnings: 1},
+		{name: "unterminated phrase", keywords: `"go developer`, wantErr: true},
+		{name: "empty phrase", keywords: `go ""`, wantErr: true},
+		{name: "unmatched open", keywords: "(go OR rust", wantErr: true},
+		{name: "unmatched close", keywords: "go OR rust)", wantErr: true},
+		{name: "empty parentheses", keywords: "go ()", wantErr: true},
+		{name: "leading operator", keywords: "OR go", wantErr: true},
+		{name: "trailing operator", keywords: "go AND", wantErr: true},
+		{name: "doubled operator", keywords: "go AND OR rust", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseKeywords(tt.keywords)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKeywords(%q) error = %v, wantErr %v", tt.keywords, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Expression != tt.want {
+				t.Errorf("ParseKeywords(%q).Expression = %q, want %q", tt.keywords, got.Expression, tt.want)
+			}
+			if got.Boolean != tt.wantBoolean {
+				t.Errorf("ParseKeywords(%q).Boolean = %v, want %v", tt.keywords, got.Boolean, tt.wantBoolean)
+			}
+			if len(got.Warnings) != tt.wantWarnings {
+				t.Errorf("ParseKeywords(%q).Warnings = %q, want %d", tt.keywords, got.Warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestBuildSearchURL(t *testing.T) {
+	const base = "https://www.linkedin.com/search/results/people/?"
+
+	tests := []struct {
+		name    string
+		query   SearchQuery
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "no filters",
+			query: SearchQuery{},
+			want:  base + "page=1",
+		},
+		{
+			name:  "keywords with spaces",
+			query: SearchQuery{Keywords: "go  developer"},
+			want:  base + "keywords=go%20developer&origin=FACETED_SEARCH&page=1",
+		},
+		{
+			name:  "boolean keywords",
+			query: SearchQuery{Keywords: `"vp sales" OR cro`},
+			want:  base + "keywords=%22vp%20sales%22%20OR%20cro&origin=FACETED_SEARCH&page=1",
+		},
+		{
+			name:  "location text after plain keywords",
+			query: SearchQuery{Keywords: "go", Location: "Berlin"},
+			want:  base + "keywords=go%20Berlin&origin=FACETED_SEARCH&page=1",
+		},
+		{
+			name:  "location text grouped with boolean keywords",
+			query: SearchQuery{Keywords: "go OR rust", Location: "Berlin"},
+			want:  base + "keywords=%28go%20OR%20rust%29%20Berlin&origin=FACETED_SEARCH&page=1",
+		},
+		{
+			name:  "facet IDs",
+			query: SearchQuery{Company: "urn:li:company:1441", Location: "103644278", Network: []string{"2nd", "1st", "2nd"}},
+			want:  base + "currentCompany=%5B%221441%22%5D&geoUrn=%5B%22103644278%22%5D&network=%5B%22S%22%2C%22F%22%5D&origin=FACETED_SEARCH&page=1",
+		},
+		{
+			name:  "title and company text",
+			query: SearchQuery{Title: "CTO", Company: "Acme"},
+			want:  base + "company=Acme&origin=FACETED_SEARCH&page=1&titleFreeText=CTO",
+		},
+		{
+			name:  "schools by ID and name",
+			query: SearchQuery{Schools: []string{"1792", " MIT ", ""}},
+			want:  base + "origin=FACETED_SEARCH&page=1&schoolFilter=%5B%221792%22%5D&schoolFreetext=MIT",
+		},
+		{
+			name:    "invalid keywords",
+			query:   SearchQuery{Keywords: "(go"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid degree",
+			query:   SearchQuery{Network: []string{"4th"}},
+			wantErr: true,
+		},
+		{
+			name:    "industry name instead of ID",
+			query:   SearchQuery{Industries: []string{"Software"}},
+			wantErr: true,
+		},
+	}
+	s := &SearchManager{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.buildSearchURL(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildSearchURL(%+v) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("buildSearchURL(%+v) =\n%s\nwant\n%s", tt.query, got, tt.want)
+			}
+		})
+	}
+}
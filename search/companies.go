@@ -0,0 +1,52 @@
+package search
+
+import (
+	"context"
+)
+
+// CompanyResolver turns a company name into its LinkedIn company ID
+type CompanyResolver interface {
+	ResolveCompany(ctx context.Context, name string) (string, error)
+}
+
+// SetCompanyResolver makes searches look up company names so they can be
+// filtered by the current and past company facets instead of as keywords
+func (s *SearchManager) SetCompanyResolver(resolver CompanyResolver) {
+	s.companyResolver = resolver
+}
+
+// resolveCompanies replaces company names in query with IDs where the resolver
+// finds them. Names it cannot resolve are left as they were.
+func (s *SearchManager) resolveCompanies(ctx context.Context, query SearchQuery) SearchQuery {
+	if s.companyResolver == nil {
+		return query
+	}
+
+	if query.Company != "" {
+		query.Company = s.resolveCompany(ctx, query.Company)
+	}
+	if len(query.PastCompanies) > 0 {
+		pastCompanies := make([]string, len(query.PastCompanies))
+		for i, company := range query.PastCompanies {
+			pastCompanies[i] = s.resolveCompany(ctx, company)
+		}
+		query.PastCompanies = pastCompanies
+	}
+
+	return query
+}
+
+func (s *SearchManager) resolveCompany(ctx context.Context, name string) string {
+	if _, ok := facetID(name); ok {
+		return name
+	}
+
+	id, err := s.companyResolver.ResolveCompany(ctx, name)
+	if err != nil {
+		s.logger.WithError(err).WithField("company", name).Warn("Could not resolve company, filtering by name")
+		return name
+	}
+
+	s.logger.WithField("company", name).WithField("id", id).Debug("Resolved company")
+	return id
+}
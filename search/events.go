@@ -0,0 +1,140 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/pause"
+	"linkedin-automation/ratelimit"
+)
+
+// eventIDPattern matches the numeric ID that ends an event URL's slug, e.g.
+// /events/growth-summit-7012345678901234567/ or /events/7012345678901234567/
+var eventIDPattern = regexp.MustCompile(`/events/(?:[^/?#]*-)?(\d+)`)
+
+// EventID returns the numeric ID from a LinkedIn event URL, or "" if the URL is not an event
+func EventID(eventURL string) string {
+	match := eventIDPattern.FindStringSubmatch(eventURL)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// GetEventAttendees lists up to maxResults attendees of an event the account
+// attends. LinkedIn shows attendees as a people search filtered by the event,
+// paginated by page number rather than a "Show more" button. Results are
+// tagged with search query "event:<id>".
+func (s *SearchManager) GetEventAttendees(ctx context.Context, eventURL string, maxResults int) (found *SearchSession, err error) {
+	eventID := EventID(eventURL)
+	if eventID == "" {
+		return nil, fmt.Errorf("not an event URL: %s", eventURL)
+	}
+	defer s.withContext(ctx)()
+	defer func() { s.captureFailure("event attendees", err) }()
+
+	s.logger.WithFields(logrus.Fields{
+		"event":       eventID,
+		"max_results": maxResults,
+	}).Info("Starting event attendee extraction")
+
+	startTime := time.Now()
+	session := &SearchSession{
+		Query: SearchQuery{
+			MaxResults: maxResults,
+			Event:      eventID,
+		},
+		Results:    make([]*SearchResult, 0),
+		Profiles:   make([]string, 0),
+		SearchTime: startTime,
+	}
+
+	for pageNum := 1; pageNum <= 100 && len(session.Results) < maxResults; pageNum++ {
+		// Each page counts against the search quota
+		if err := s.waitForPermission(ctx); err != nil {
+			if pageNum > 1 && errors.Is(err, ratelimit.ErrLimitReached) {
+				s.logger.WithError(err).Warn("Stopping event attendee extraction at rate limit")
+				session.StoppedAtLimit = true
+				break
+			}
+			return nil, err
+		}
+
+		if err := s.page.Navigate(eventAttendeesURL(eventID, pageNum)); err != nil {
+			return nil, fmt.Errorf("failed to navigate to event attendees: %w", err)
+		}
+		if err := s.page.WaitLoad(); err != nil {
+			return nil, fmt.Errorf("failed to wait for page load: %w", err)
+		}
+		if err := s.handleLoginRedirect(); err != nil {
+			return nil, fmt.Errorf("login redirect failed: %w", err)
+		}
+
+		if err := s.waitForSearchResults(); err != nil {
+			if pageNum == 1 {
+				return nil, fmt.Errorf("no event attendees found (is the account attending the event?): %w", err)
+			}
+			break
+		}
+
+		before := len(session.Results)
+		if _, err := s.extractResultsFromPage(session); err != nil {
+			// Past the last page LinkedIn shows an empty results container
+			s.logger.WithError(err).Debug("No more event attendees")
+			break
+		}
+		for _, result := range session.Results[before:] {
+			result.SearchQuery = "event:" + eventID
+		}
+
+		s.logger.WithFields(logrus.Fields{
+			"page":  pageNum,
+			"added": len(session.Results) - before,
+			"total": len(session.Results),
+		}).Debug("Extracted event attendees")
+
+		if len(session.Results) == before {
+			break
+		}
+
+		// Add delay between pages
+		if err := pause.Settle(s.page); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(session.Results) > maxResults {
+		session.Results = session.Results[:maxResults]
+	}
+	for _, result := range session.Results {
+		if result.ProfileURL != "" {
+			session.Profiles = append(session.Profiles, result.ProfileURL)
+		}
+	}
+	session.Duration = time.Since(startTime)
+
+	s.logger.WithFields(logrus.Fields{
+		"event":           eventID,
+		"unique_profiles": len(session.Profiles),
+		"duration":        session.Duration,
+	}).Info("Event attendee extraction completed")
+
+	return session, nil
+}
+
+func eventAttendeesURL(eventID string, pageNum int) string {
+	params := url.Values{}
+	params.Add("eventAttending", facetValue([]string{eventID}))
+	params.Add("origin", "EVENT_PAGE_CANNED_SEARCH")
+	if pageNum > 1 {
+		params.Add("page", strconv.Itoa(pageNum))
+	}
+	return "https://www.linkedin.com/search/results/people/?" + params.Encode()
+}
@@ -0,0 +1,89 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	numericIDPattern = regexp.MustCompile(`^\d+$`)
+	urnIDPattern     = regexp.MustCompile(`^urn:li:[a-zA-Z_]+:(\d+)$`)
+	languagePattern  = regexp.MustCompile(`^[a-z]{2}$`)
+)
+
+// networkDegrees maps accepted degree spellings to LinkedIn's network facet codes
+var networkDegrees = map[string]string{
+	"1":      "F",
+	"1st":    "F",
+	"first":  "F",
+	"f":      "F",
+	"2":      "S",
+	"2nd":    "S",
+	"second": "S",
+	"s":      "S",
+	"3":      "O",
+	"3rd":    "O",
+	"3rd+":   "O",
+	"third":  "O",
+	"o":      "O",
+}
+
+// facetValue encodes facet values the way LinkedIn's people search expects: ["a","b"]
+func facetValue(values []string) string {
+	encoded, _ := json.Marshal(values)
+	return string(encoded)
+}
+
+// facetID extracts a numeric facet ID from a bare ID or a urn:li:<type>:<id> URN
+func facetID(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if numericIDPattern.MatchString(value) {
+		return value, true
+	}
+	if match := urnIDPattern.FindStringSubmatch(value); match != nil {
+		return match[1], true
+	}
+	return "", false
+}
+
+func facetIDs(name string, values []string) ([]string, error) {
+	ids := make([]string, 0, len(values))
+	for _, value := range values {
+		id, ok := facetID(value)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s filter %q: expected a numeric LinkedIn ID or URN", name, value)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func networkFacet(degrees []string) ([]string, error) {
+	codes := make([]string, 0, len(degrees))
+	seen := make(map[string]bool)
+	for _, degree := range degrees {
+		code, ok := networkDegrees[strings.ToLower(strings.TrimSpace(degree))]
+		if !ok {
+			return nil, fmt.Errorf("invalid connection degree %q: expected 1st, 2nd or 3rd", degree)
+		}
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	return codes, nil
+}
+
+func languageFacet(languages []string) ([]string, error) {
+	codes := make([]string, 0, len(languages))
+	for _, language := range languages {
+		code := strings.ToLower(strings.TrimSpace(language))
+		if !languagePattern.MatchString(code) {
+			return nil, fmt.Errorf("invalid profile language %q: expected a two-letter language code such as en", language)
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
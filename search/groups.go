@@ -0,0 +1,162 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/pause"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/selectors"
+)
+
+var groupIDPattern = regexp.MustCompile(`/groups/([^/?#]+)`)
+
+// GroupID returns the ID from a LinkedIn group URL, or "" if the URL is not a group
+func GroupID(groupURL string) string {
+	match := groupIDPattern.FindStringSubmatch(groupURL)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// GetGroupMembers lists up to maxResults members of a group the account has
+// joined. Results are tagged with search query "group:<id>".
+func (s *SearchManager) GetGroupMembers(ctx context.Context, groupURL string, maxResults int) (found *SearchSession, err error) {
+	groupID := GroupID(groupURL)
+	if groupID == "" {
+		return nil, fmt.Errorf("not a group URL: %s", groupURL)
+	}
+	defer s.withContext(ctx)()
+	defer func() { s.captureFailure("group members", err) }()
+
+	s.logger.WithFields(logrus.Fields{
+		"group":       groupID,
+		"max_results": maxResults,
+	}).Info("Starting group member extraction")
+
+	startTime := time.Now()
+	session := &SearchSession{
+		Query: SearchQuery{
+			MaxResults: maxResults,
+			Group:      groupID,
+		},
+		Results:    make([]*SearchResult, 0),
+		Profiles:   make([]string, 0),
+		SearchTime: startTime,
+	}
+
+	if err := s.waitForPermission(ctx); err != nil {
+		return nil, err
+	}
+
+	membersURL := "https://www.linkedin.com/groups/" + groupID + "/members/"
+	if err := s.page.Navigate(membersURL); err != nil {
+		return nil, fmt.Errorf("failed to navigate to group members: %w", err)
+	}
+	if err := s.page.WaitLoad(); err != nil {
+		return nil, fmt.Errorf("failed to wait for page load: %w", err)
+	}
+	if err := s.handleLoginRedirect(); err != nil {
+		return nil, fmt.Errorf("login redirect failed: %w", err)
+	}
+
+	// The list is loaded in chunks by a "Show more results" button
+	for pageNum := 1; pageNum <= 100 && len(session.Results) < maxResults; pageNum++ {
+		added := s.extractGroupMembers(session, maxResults)
+		s.logger.WithFields(logrus.Fields{
+			"page":  pageNum,
+			"added": added,
+			"total": len(session.Results),
+		}).Debug("Extracted group members")
+
+		if len(session.Results) >= maxResults || (added == 0 && pageNum > 1) {
+			break
+		}
+
+		button, _ := selectors.Find(s.page, selectors.ShowMoreButton)
+		if button == nil {
+			if pageNum == 1 && added == 0 {
+				return nil, fmt.Errorf("no group members found (is the account a member of the group?)")
+			}
+			s.logger.Debug("No more group members available")
+			break
+		}
+
+		// Each chunk counts against the search quota
+		if err := s.waitForPermission(ctx); err != nil {
+			if errors.Is(err, ratelimit.ErrLimitReached) {
+				s.logger.WithError(err).Warn("Stopping group member extraction at rate limit")
+				session.StoppedAtLimit = true
+				break
+			}
+			return nil, err
+		}
+
+		if err := s.click(button); err != nil {
+			s.logger.WithError(err).Warn("Failed to load more group members")
+			break
+		}
+
+		// Add delay between pages
+		if err := pause.Settle(s.page); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, result := range session.Results {
+		session.Profiles = append(session.Profiles, result.ProfileURL)
+	}
+	session.Duration = time.Since(startTime)
+
+	s.logger.WithFields(logrus.Fields{
+		"group":           groupID,
+		"unique_profiles": len(session.Profiles),
+		"duration":        session.Duration,
+	}).Info("Group member extraction completed")
+
+	return session, nil
+}
+
+// extractGroupMembers adds member entries not seen before, returning how many were added
+func (s *SearchManager) extractGroupMembers(session *SearchSession, maxResults int) int {
+	items := selectors.FindAll(s.page, selectors.GroupMember)
+
+	added := 0
+	for _, item := range items {
+		if len(session.Results) >= maxResults {
+			break
+		}
+
+		link := selectors.FindIn(item, selectors.ProfileLink)
+		if link == nil {
+			continue
+		}
+		href, err := link.Attribute("href")
+		if err != nil || href == nil || profileurl.Slug(*href) == "" {
+			continue
+		}
+
+		profileURL := profileurl.Canonicalize(*href)
+		if hasProfile(session, profileURL) {
+			continue
+		}
+
+		session.Results = append(session.Results, &SearchResult{
+			URL:         profileURL,
+			ProfileURL:  profileURL,
+			Name:        selectors.TextIn(item, selectors.EntityTitle),
+			Title:       selectors.TextIn(item, selectors.EntitySubtitle),
+			SearchQuery: "group:" + session.Query.Group,
+		})
+		added++
+	}
+
+	return added
+}
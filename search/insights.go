@@ -0,0 +1,72 @@
+package search
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/selectors"
+	"linkedin-automation/uitext"
+)
+
+// degreePattern finds the degree in badges such as "• 2nd", "3rd+" or "2e"
+var degreePattern = regexp.MustCompile(`[1-3]`)
+
+// degreeCodes maps the network facet codes to connection degrees
+var degreeCodes = map[string]int{"F": 1, "S": 2, "O": 3}
+
+// ParseDegree reads the connection degree from a result's badge, 0 when it
+// shows none
+func ParseDegree(badge string) int {
+	degree, _ := strconv.Atoi(degreePattern.FindString(badge))
+	return degree
+}
+
+// extractInsights reads the degree, mutual connections and badges shown on
+// a result card
+func extractInsights(card *rod.Element, result *SearchResult) {
+	result.Degree = ParseDegree(selectors.TextIn(card, selectors.SearchResultDegree))
+	for _, insight := range selectors.FindAllIn(card, selectors.SearchResultInsight) {
+		text, err := insight.Text()
+		if err != nil {
+			continue
+		}
+		if mutual := uitext.MutualCount(text); mutual > 0 {
+			result.MutualConnections = mutual
+			break
+		}
+	}
+	result.OpenToWork = selectors.FindIn(card, selectors.SearchResultOpenToWork) != nil
+	result.Hiring = selectors.FindIn(card, selectors.SearchResultHiring) != nil
+	result.Premium = selectors.FindIn(card, selectors.SearchResultPremium) != nil
+}
+
+// NeedsBrowser reports whether the query filters on badges, which only the
+// result cards of the search page show
+func (q SearchQuery) NeedsBrowser() bool {
+	return q.OpenToWork || q.Hiring || q.Premium
+}
+
+// Matches reports whether result passes the query's filters on the result
+// card: a degree among Network, where the card shows one, at least MinMutual
+// mutual connections and the required badges
+func (q SearchQuery) Matches(result *SearchResult) bool {
+	if len(q.Network) > 0 && result.Degree > 0 {
+		codes, _ := networkFacet(q.Network)
+		inNetwork := false
+		for _, code := range codes {
+			inNetwork = inNetwork || degreeCodes[code] == result.Degree
+		}
+		if !inNetwork {
+			return false
+		}
+	}
+	if result.MutualConnections < q.MinMutual {
+		return false
+	}
+	if (q.OpenToWork && !result.OpenToWork) || (q.Hiring && !result.Hiring) || (q.Premium && !result.Premium) {
+		return false
+	}
+	return true
+}
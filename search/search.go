@@ -2,39 +2,88 @@ package search
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
 	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/errs"
+	"linkedin-automation/pause"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/selectors"
 )
 
 // SearchManager handles LinkedIn user search
 type SearchManager struct {
-	page   *rod.Page
-	logger *logrus.Logger
+	page            *rod.Page
+	logger          *logrus.Logger
+	rateLimiter     RateLimiter
+	apiClient       APIClient
+	capturer        Capturer
+	companyResolver CompanyResolver
+	clicker         Clicker
+}
+
+// RateLimiter gates actions against configured quotas
+type RateLimiter interface {
+	WaitForPermission(ctx context.Context, action ratelimit.ActionType) error
+}
+
+// Clicker clicks elements the way a person would, scrolling them into view
+// and hovering them first
+type Clicker interface {
+	HumanClick(page *rod.Page, element *rod.Element) error
+}
+
+// Capturer saves the page for debugging when an action fails
+type Capturer interface {
+	Failure(page *rod.Page, action string, err error)
 }
 
 // SearchQuery represents a search query
 type SearchQuery struct {
-	Keywords    string
-	Title       string
-	Company     string
-	Location    string
-	MaxResults  int
+	Keywords         string
+	Title            string
+	Company          string   // company name, or a numeric ID/URN for the current company facet
+	Location         string   // geo ID/URN, or free text matched as a keyword
+	MaxResults       int
+	Network          []string // connection degrees: 1st, 2nd, 3rd
+	Industries       []string // industry IDs
+	Schools          []string // school IDs or names
+	PastCompanies    []string // company IDs, or names when a company resolver is set
+	ProfileLanguages []string // two-letter language codes such as "en"
+	Group            string   // group ID, set when members were listed from a group instead
+	Event            string   // event ID, set when attendees were listed from an event instead
+	SimilarTo        string   // seed profile URL, set when its recommendations were listed instead
+	MinMutual        int      // only results with at least this many mutual connections
+	OpenToWork       bool     // only results showing the open-to-work badge
+	Hiring           bool     // only results showing the hiring badge
+	Premium          bool     // only results with a Premium badge
+	StartPage        int      // results page to start from, e.g. to resume a deep search; 1 when unset
 }
 
 // SearchResult represents a search result
 type SearchResult struct {
-	URL         string
-	Name        string
-	Title       string
-	Company     string
-	Location    string
-	ProfileURL  string
-	SearchQuery string
+	URL               string
+	Name              string
+	Title             string
+	Company           string
+	Location          string
+	ProfileURL        string
+	SearchQuery       string
+	Source            string // Where the profile was found when not by a search, e.g. "also-viewed:<seed URL>"
+	AlreadyContacted  bool   // Storage shows a prior connection request or message
+	Degree            int    // 1, 2 or 3 for a 1st, 2nd or 3rd-degree connection; 0 when not shown
+	MutualConnections int
+	OpenToWork        bool
+	Hiring            bool
+	Premium           bool
 }
 
 // SearchSession represents a complete search session
@@ -44,8 +93,14 @@ type SearchSession struct {
 	Profiles     []string // Unique profile URLs
 	SearchTime   time.Time
 	Duration     time.Duration
+	StoppedAtLimit bool // Pagination ended early because the search quota was exhausted
+	Filtered     int  // Results left out by the query's filters on degree, mutual connections and badges
+	NextPage     int  // Results page a later run resumes from; 0 once the last page was read
 }
 
+// maxSearchPages is the last results page LinkedIn shows for a search
+const maxSearchPages = 100
+
 // NewSearchManager creates a new search manager
 func NewSearchManager(page *rod.Page, logger *logrus.Logger) *SearchManager {
 	return &SearchManager{
@@ -54,14 +109,65 @@ func NewSearchManager(page *rod.Page, logger *logrus.Logger) *SearchManager {
 	}
 }
 
+// SetClicker makes pagination click through clicker rather than straight away
+func (s *SearchManager) SetClicker(clicker Clicker) {
+	s.clicker = clicker
+}
+
+// click clicks element through the clicker, if one is set
+func (s *SearchManager) click(element *rod.Element) error {
+	if s.clicker == nil {
+		return element.Click("left", 1)
+	}
+	return s.clicker.HumanClick(s.page, element)
+}
+
+// SetRateLimiter enables quota enforcement for searches and pagination
+func (s *SearchManager) SetRateLimiter(limiter RateLimiter) {
+	s.rateLimiter = limiter
+}
+
+// SetCapturer saves a screenshot and HTML snapshot of the page whenever a
+// browser search fails
+func (s *SearchManager) SetCapturer(capturer Capturer) {
+	s.capturer = capturer
+}
+
+// withContext binds the page, if there is one, to ctx until the returned
+// function is called, so navigation, element lookups and pauses stop once
+// ctx is cancelled
+func (s *SearchManager) withContext(ctx context.Context) func() {
+	page := s.page
+	if page == nil {
+		return func() {}
+	}
+	s.page = page.Context(ctx)
+	return func() { s.page = page }
+}
+
+// captureFailure saves the page when a search failed with err
+func (s *SearchManager) captureFailure(action string, err error) {
+	if err != nil && s.capturer != nil {
+		s.capturer.Failure(s.page, action, err)
+	}
+}
+
 // SearchUsers searches for LinkedIn users based on query parameters
-func (s *SearchManager) SearchUsers(ctx context.Context, query SearchQuery) (*SearchSession, error) {
+func (s *SearchManager) SearchUsers(ctx context.Context, query SearchQuery) (found *SearchSession, err error) {
 	s.logger.WithFields(logrus.Fields{
 		"keywords": query.Keywords,
 		"title":    query.Title,
 		"company":  query.Company,
 		"location": query.Location,
 	}).Info("Starting user search")
+	defer s.withContext(ctx)()
+	defer func() { s.captureFailure("search", err) }()
+
+	if parsed, err := ParseKeywords(query.Keywords); err == nil {
+		for _, warning := range parsed.Warnings {
+			s.logger.WithField("keywords", query.Keywords).Warn(warning)
+		}
+	}
 
 	startTime := time.Now()
 	session := &SearchSession{
@@ -70,46 +176,47 @@ func (s *SearchManager) SearchUsers(ctx context.Context, query SearchQuery) (*Se
 		Profiles:   make([]string, 0),
 		SearchTime: startTime,
 	}
+	query = s.resolveCompanies(ctx, query)
 
-	// Build search URL
-	searchURL := s.buildSearchURL(query)
-	s.logger.WithField("url", searchURL).Debug("Navigating to search page")
-
-	// Navigate to search page
-	if err := s.page.Navigate(searchURL); err != nil {
-		return nil, fmt.Errorf("failed to navigate to search page: %w", err)
-	}
-
-	// Wait for page to load
-	if err := s.page.WaitLoad(); err != nil {
-		return nil, fmt.Errorf("failed to wait for page load: %w", err)
+	// Prefer the API when available; the browser remains the fallback and
+	// the only way to see badges
+	if s.apiClient != nil && !query.NeedsBrowser() {
+		err := s.searchAPI(ctx, query, session)
+		if err == nil {
+			return s.finishSession(session, startTime), nil
+		}
+		if s.page == nil || errors.Is(err, ratelimit.ErrLimitReached) || ctx.Err() != nil {
+			return nil, err
+		}
+		s.logger.WithError(err).Warn("API search failed, falling back to browser")
+		session.Results = session.Results[:0]
+		session.StoppedAtLimit = false
+		session.Filtered = 0
+		session.NextPage = 0
 	}
 
-	// Handle potential login redirect
-	if err := s.handleLoginRedirect(); err != nil {
-		return nil, fmt.Errorf("login redirect failed: %w", err)
+	if s.page == nil {
+		return nil, fmt.Errorf("filtering on badges needs a browser session")
 	}
 
-	// Wait for search results to load
-	if err := s.waitForSearchResults(); err != nil {
-		return nil, fmt.Errorf("failed to wait for search results: %w", err)
+	// Build search URL
+	searchURL, err := s.buildSearchURL(query)
+	if err != nil {
+		return nil, err
 	}
 
-	// Extract results from current page
-	if err := s.extractResultsFromPage(session); err != nil {
-		return nil, fmt.Errorf("failed to extract results: %w", err)
+	if err := s.handlePagination(ctx, session, searchURL); err != nil {
+		return nil, err
 	}
 
-	// Handle pagination if needed
-	if len(session.Results) < query.MaxResults {
-		if err := s.handlePagination(session, query.MaxResults); err != nil {
-			s.logger.WithError(err).Warn("Failed to handle pagination")
-		}
-	}
+	return s.finishSession(session, startTime), nil
+}
 
+// finishSession trims a session to the requested size and collects its profile URLs
+func (s *SearchManager) finishSession(session *SearchSession, startTime time.Time) *SearchSession {
 	// Limit results to max requested
-	if len(session.Results) > query.MaxResults {
-		session.Results = session.Results[:query.MaxResults]
+	if len(session.Results) > session.Query.MaxResults {
+		session.Results = session.Results[:session.Query.MaxResults]
 	}
 
 	// Extract unique profile URLs
@@ -127,12 +234,14 @@ func (s *SearchManager) SearchUsers(ctx context.Context, query SearchQuery) (*Se
 		"duration": session.Duration,
 	}).Info("Search completed successfully")
 
-	return session, nil
+	return session
 }
 
 // SearchByURL searches for users using a direct search URL
-func (s *SearchManager) SearchByURL(ctx context.Context, searchURL string, maxResults int) (*SearchSession, error) {
+func (s *SearchManager) SearchByURL(ctx context.Context, searchURL string, maxResults int) (found *SearchSession, err error) {
 	s.logger.WithField("url", searchURL).Info("Starting search by URL")
+	defer s.withContext(ctx)()
+	defer func() { s.captureFailure("search", err) }()
 
 	startTime := time.Now()
 	session := &SearchSession{
@@ -144,36 +253,13 @@ func (s *SearchManager) SearchByURL(ctx context.Context, searchURL string, maxRe
 		SearchTime: startTime,
 	}
 
-	// Navigate to search URL
-	if err := s.page.Navigate(searchURL); err != nil {
-		return nil, fmt.Errorf("failed to navigate to search URL: %w", err)
+	// Start from the page the URL names, if any
+	if parsed, err := url.Parse(searchURL); err == nil {
+		session.Query.StartPage, _ = strconv.Atoi(parsed.Query().Get("page"))
 	}
 
-	// Wait for page to load
-	if err := s.page.WaitLoad(); err != nil {
-		return nil, fmt.Errorf("failed to wait for page load: %w", err)
-	}
-
-	// Handle potential login redirect
-	if err := s.handleLoginRedirect(); err != nil {
-		return nil, fmt.Errorf("login redirect failed: %w", err)
-	}
-
-	// Wait for search results to load
-	if err := s.waitForSearchResults(); err != nil {
-		return nil, fmt.Errorf("failed to wait for search results: %w", err)
-	}
-
-	// Extract results from current page
-	if err := s.extractResultsFromPage(session); err != nil {
-		return nil, fmt.Errorf("failed to extract results: %w", err)
-	}
-
-	// Handle pagination if needed
-	if len(session.Results) < maxResults {
-		if err := s.handlePagination(session, maxResults); err != nil {
-			s.logger.WithError(err).Warn("Failed to handle pagination")
-		}
+	if err := s.handlePagination(ctx, session, searchURL); err != nil {
+		return nil, err
 	}
 
 	// Limit results to max requested
@@ -199,6 +285,34 @@ func (s *SearchManager) SearchByURL(ctx context.Context, searchURL string, maxRe
 	return session, nil
 }
 
+// MarkContacted flags results whose profile is in contacted (keyed by canonical
+// URL) and, if exclude is set, removes them from the session. It returns the
+// number of contacted profiles found.
+func (session *SearchSession) MarkContacted(contacted map[string]bool, exclude bool) int {
+	count := 0
+	results := make([]*SearchResult, 0, len(session.Results))
+	for _, result := range session.Results {
+		if result.ProfileURL != "" && contacted[profileurl.Canonicalize(result.ProfileURL)] {
+			result.AlreadyContacted = true
+			count++
+			if exclude {
+				continue
+			}
+		}
+		results = append(results, result)
+	}
+
+	session.Results = results
+	session.Profiles = make([]string, 0, len(results))
+	for _, result := range results {
+		if result.ProfileURL != "" {
+			session.Profiles = append(session.Profiles, result.ProfileURL)
+		}
+	}
+
+	return count
+}
+
 // GetProfileURLsFromSearch extracts profile URLs from search results
 func (s *SearchManager) GetProfileURLsFromSearch(ctx context.Context, query SearchQuery) ([]string, error) {
 	session, err := s.SearchUsers(ctx, query)
@@ -211,41 +325,112 @@ func (s *SearchManager) GetProfileURLsFromSearch(ctx context.Context, query Sear
 
 // Private helper methods
 
-func (s *SearchManager) buildSearchURL(query SearchQuery) string {
+func (s *SearchManager) waitForPermission(ctx context.Context) error {
+	if s.rateLimiter == nil {
+		return nil
+	}
+	return s.rateLimiter.WaitForPermission(ctx, ratelimit.ActionSearch)
+}
+
+func (s *SearchManager) buildSearchURL(query SearchQuery) (string, error) {
 	baseURL := "https://www.linkedin.com/search/results/people/"
 	params := url.Values{}
 
-	if query.Keywords != "" {
-		params.Add("keywords", query.Keywords)
+	parsed, err := ParseKeywords(query.Keywords)
+	if err != nil {
+		return "", err
 	}
+	keywords := parsed.Expression
 
-	// Build filters
-	filters := make([]string, 0)
-
+	// Title and free-text company are keyword fields, not facets
 	if query.Title != "" {
-		filters = append(filters, fmt.Sprintf("currentCompany:%s", query.Title))
+		params.Add("titleFreeText", query.Title)
 	}
 
 	if query.Company != "" {
-		filters = append(filters, fmt.Sprintf("currentCompany:%s", query.Company))
+		if id, ok := facetID(query.Company); ok {
+			params.Add("currentCompany", facetValue([]string{id}))
+		} else {
+			params.Add("company", query.Company)
+		}
 	}
 
+	// geoUrn only accepts IDs; fall back to matching location text as a keyword
 	if query.Location != "" {
-		filters = append(filters, fmt.Sprintf("geoUrn:%s", query.Location))
+		if id, ok := facetID(query.Location); ok {
+			params.Add("geoUrn", facetValue([]string{id}))
+		} else if parsed.Boolean {
+			// Group the expression so an OR in it does not swallow the location
+			keywords = "(" + keywords + ") " + query.Location
+		} else {
+			keywords = strings.TrimSpace(keywords + " " + query.Location)
+		}
 	}
 
-	if len(filters) > 0 {
-		params.Add("filters", strings.Join(filters, ","))
+	if keywords != "" {
+		params.Add("keywords", keywords)
 	}
 
-	// Add pagination
-	params.Add("page", "1")
+	if len(query.Network) > 0 {
+		network, err := networkFacet(query.Network)
+		if err != nil {
+			return "", err
+		}
+		params.Add("network", facetValue(network))
+	}
+
+	facets := []struct {
+		param  string
+		name   string
+		values []string
+	}{
+		{"industry", "industry", query.Industries},
+		{"pastCompany", "past company", query.PastCompanies},
+	}
+	for _, facet := range facets {
+		if len(facet.values) == 0 {
+			continue
+		}
+		ids, err := facetIDs(facet.name, facet.values)
+		if err != nil {
+			return "", err
+		}
+		params.Add(facet.param, facetValue(ids))
+	}
+
+	// Schools can be given as IDs or as free text
+	var schoolIDs, schoolNames []string
+	for _, school := range query.Schools {
+		if id, ok := facetID(school); ok {
+			schoolIDs = append(schoolIDs, id)
+		} else if strings.TrimSpace(school) != "" {
+			schoolNames = append(schoolNames, strings.TrimSpace(school))
+		}
+	}
+	if len(schoolIDs) > 0 {
+		params.Add("schoolFilter", facetValue(schoolIDs))
+	}
+	if len(schoolNames) > 0 {
+		params.Add("schoolFreetext", strings.Join(schoolNames, " "))
+	}
+
+	if len(query.ProfileLanguages) > 0 {
+		languages, err := languageFacet(query.ProfileLanguages)
+		if err != nil {
+			return "", err
+		}
+		params.Add("profileLanguage", facetValue(languages))
+	}
 
 	if len(params) > 0 {
-		return baseURL + "?" + params.Encode()
+		params.Add("origin", "FACETED_SEARCH")
 	}
 
-	return baseURL
+	// Add pagination
+	params.Add("page", "1")
+
+	// Spaces as %20 rather than +, so boolean expressions reach LinkedIn intact
+	return baseURL + "?" + strings.ReplaceAll(params.Encode(), "+", "%20"), nil
 }
 
 func (s *SearchManager) handleLoginRedirect() error {
@@ -255,57 +440,25 @@ func (s *SearchManager) handleLoginRedirect() error {
 		return err
 	}
 
-	if strings.Contains(currentURL.URL, "linkedin.com/login") {
-		return fmt.Errorf("redirected to login page - authentication required")
-	}
-
-	return nil
+	return errs.CheckPageURL(currentURL.URL)
 }
 
 func (s *SearchManager) waitForSearchResults() error {
-	// Try multiple possible selectors
-	selectors := []string{
-		".search-results__container",
-		".reusable-search__result-container",
-		".search-results-page",
-		"[data-test-id='search-results-container']",
-	}
-
-	for _, sel := range selectors {
-		element, err := s.page.Element(sel)
-		if err == nil && element != nil {
-			s.logger.WithField("selector", sel).Debug("Found search results container")
-			return nil
-		}
-	}
-
-	// Wait a bit and try again
-	time.Sleep(2 * time.Second)
-	
-	for _, sel := range selectors {
-		element, err := s.page.Element(sel)
-		if err == nil && element != nil {
-			s.logger.WithField("selector", sel).Debug("Found search results container after delay")
-			return nil
-		}
+	// Slow connections render the results late, so keep looking for a while
+	if _, err := selectors.Wait(s.page, selectors.SearchResultsContainer); err != nil {
+		return fmt.Errorf("search results container not found: %w", err)
 	}
-
-	return fmt.Errorf("search results container not found")
+	return nil
 }
 
-func (s *SearchManager) extractResultsFromPage(session *SearchSession) error {
+// extractResultsFromPage adds the current page's results to session until it
+// holds the query's MaxResults, reporting whether it got through the page
+func (s *SearchManager) extractResultsFromPage(session *SearchSession) (bool, error) {
 	// Try different selectors for search results
-	resultSelectors := []string{
-		".search-result__info",
-		".reusable-search__result-container",
-		".people-search-card",
-		"[data-test-id='search-result']",
-	}
-
 	var results []*rod.Element
 	var usedSelector string
 
-	for _, selector := range resultSelectors {
+	for _, selector := range selectors.Get(selectors.SearchResult) {
 		elements, err := s.page.Elements(selector)
 		if err == nil && len(elements) > 0 {
 			results = elements
@@ -319,12 +472,14 @@ func (s *SearchManager) extractResultsFromPage(session *SearchSession) error {
 	}
 
 	if len(results) == 0 {
-		return fmt.Errorf("no search results found")
+		return false, fmt.Errorf("no search results found")
 	}
 
 	// Extract data from each result
+	complete := true
 	for i, element := range results {
-		if i >= session.Query.MaxResults {
+		if len(session.Results) >= session.Query.MaxResults {
+			complete = false
 			break
 		}
 
@@ -334,6 +489,15 @@ func (s *SearchManager) extractResultsFromPage(session *SearchSession) error {
 			continue
 		}
 
+		// The same profile can appear on more than one results page
+		if result.ProfileURL != "" && hasProfile(session, result.ProfileURL) {
+			continue
+		}
+		if !session.Query.Matches(result) {
+			session.Filtered++
+			continue
+		}
+
 		result.SearchQuery = fmt.Sprintf("keywords:%s,title:%s,company:%s,location:%s",
 			session.Query.Keywords, session.Query.Title, session.Query.Company, session.Query.Location)
 		
@@ -345,153 +509,148 @@ func (s *SearchManager) extractResultsFromPage(session *SearchSession) error {
 		"extracted": len(session.Results),
 	}).Debug("Extracted search results")
 
-	return nil
+	return complete, nil
 }
 
-func (s *SearchManager) extractResultData(element *rod.Element) (*SearchResult, error) {
-	result := &SearchResult{}
-
-	// Extract name
-	nameElement, err := element.Element(".name span")
-	if err != nil {
-		// Try alternative selectors
-		nameElement, err = element.Element("span[aria-hidden='true']")
-		if err != nil {
-			nameElement, err = element.Element(".entity-result__title-text")
-		}
-	}
-	
-	if err == nil && nameElement != nil {
-		name, err := nameElement.Text()
-		if err == nil {
-			result.Name = strings.TrimSpace(name)
-		}
-	}
-
-	// Extract title
-	titleElement, err := element.Element(".subline-level-1")
-	if err != nil {
-		titleElement, err = element.Element(".entity-result__primary-subtitle")
-	}
-	
-	if err == nil && titleElement != nil {
-		title, err := titleElement.Text()
-		if err == nil {
-			result.Title = strings.TrimSpace(title)
-		}
-	}
-
-	// Extract company
-	companyElement, err := element.Element(".subline-level-2")
-	if err != nil {
-		companyElement, err = element.Element(".entity-result__secondary-subtitle")
-	}
-	
-	if err == nil && companyElement != nil {
-		company, err := companyElement.Text()
-		if err == nil {
-			result.Company = strings.TrimSpace(company)
+func hasProfile(session *SearchSession, profileURL string) bool {
+	for _, result := range session.Results {
+		if result.ProfileURL == profileURL {
+			return true
 		}
 	}
+	return false
+}
 
-	// Extract location
-	locationElement, err := element.Element(".entity-result__simple-insight-text")
-	if err == nil && locationElement != nil {
-		location, err := locationElement.Text()
-		if err == nil {
-			result.Location = strings.TrimSpace(location)
-		}
+func (s *SearchManager) extractResultData(element *rod.Element) (*SearchResult, error) {
+	result := &SearchResult{
+		Name:     selectors.TextIn(element, selectors.SearchResultName),
+		Title:    selectors.TextIn(element, selectors.SearchResultTitle),
+		Company:  selectors.TextIn(element, selectors.SearchResultCompany),
+		Location: selectors.TextIn(element, selectors.SearchResultLocation),
 	}
 
 	// Extract profile URL
-	linkElement, err := element.Element("a")
-	if err == nil && linkElement != nil {
+	linkElement := selectors.FindIn(element, selectors.SearchResultLink)
+	if linkElement != nil {
 		href, err := linkElement.Attribute("href")
 		if err == nil && href != nil && *href != "" {
-			if strings.HasPrefix(*href, "https://www.linkedin.com/in/") {
-				profileURL := *href
-				result.ProfileURL = profileURL
+			if profileurl.Slug(*href) != "" {
+				result.ProfileURL = profileurl.Canonicalize(*href)
 			}
 		}
 	}
+	extractInsights(element, result)
 
 	return result, nil
 }
 
-func (s *SearchManager) handlePagination(session *SearchSession, maxResults int) error {
-	pageNum := 2
-	
-	for len(session.Results) < maxResults {
-		s.logger.WithFields(logrus.Fields{
-			"current_results": len(session.Results),
-			"target_results": maxResults,
-			"page": pageNum,
-		}).Debug("Handling pagination")
+// handlePagination reads the results pages of searchURL, navigating by its
+// page parameter from the query's start page, until the session holds
+// MaxResults, the pages run out or the search quota does. The session's
+// NextPage is where a later run can pick up.
+func (s *SearchManager) handlePagination(ctx context.Context, session *SearchSession, searchURL string) error {
+	first := max(session.Query.StartPage, 1)
 
-		// Look for next page button
-		nextButton, err := s.page.Element("button[aria-label*='Next']")
-		if err != nil {
-			nextButton, err = s.page.Element(".pagination__next")
-		}
-		if err != nil {
-			nextButton, err = s.page.Element(".artdeco-pagination__button--next")
+	for pageNum := first; pageNum <= maxSearchPages; pageNum++ {
+		if len(session.Results) >= session.Query.MaxResults {
+			session.NextPage = pageNum
+			return nil
 		}
 
-		if err != nil || nextButton == nil {
-			s.logger.Debug("No more pages available")
-			break
+		// Each page counts against the search quota
+		if err := s.waitForPermission(ctx); err != nil {
+			if pageNum > first && errors.Is(err, ratelimit.ErrLimitReached) {
+				s.logger.WithError(err).Warn("Stopping pagination at rate limit")
+				session.StoppedAtLimit = true
+				session.NextPage = pageNum
+				return nil
+			}
+			return err
 		}
 
-		// Check if button is disabled
-		disabled, err := nextButton.Attribute("disabled")
-		if err == nil && disabled != nil && *disabled != "" {
-			s.logger.Debug("Next page button is disabled")
-			break
-		}
+		pageURL := searchPageURL(searchURL, pageNum)
+		s.logger.WithFields(logrus.Fields{
+			"url":             pageURL,
+			"page":            pageNum,
+			"current_results": len(session.Results),
+			"target_results":  session.Query.MaxResults,
+		}).Debug("Navigating to search page")
 
-		// Click next button
-		if err := nextButton.Click("left", 1); err != nil {
-			return fmt.Errorf("failed to click next button: %w", err)
+		if err := s.page.Navigate(pageURL); err != nil {
+			return fmt.Errorf("failed to navigate to search page: %w", err)
 		}
-
-		// Wait for page to load
 		if err := s.page.WaitLoad(); err != nil {
 			return fmt.Errorf("failed to wait for page load: %w", err)
 		}
+		if err := s.handleLoginRedirect(); err != nil {
+			return fmt.Errorf("login redirect failed: %w", err)
+		}
 
-		// Wait for search results
+		// Past the last page LinkedIn shows no results
 		if err := s.waitForSearchResults(); err != nil {
-			return fmt.Errorf("failed to wait for search results: %w", err)
+			if pageNum == first {
+				return fmt.Errorf("failed to wait for search results: %w", err)
+			}
+			s.logger.Debug("No more pages available")
+			break
 		}
-
-		// Extract results from this page
-		pageResults := make([]*SearchResult, 0)
-		if err := s.extractResultsFromPage(&SearchSession{
-			Query:   session.Query,
-			Results: pageResults,
-		}); err != nil {
-			s.logger.WithError(err).Warn("Failed to extract results from page")
+		complete, err := s.extractResultsFromPage(session)
+		if err != nil {
+			if pageNum == first {
+				return fmt.Errorf("failed to extract results: %w", err)
+			}
+			s.logger.WithError(err).Debug("No more pages available")
 			break
 		}
+		if !complete {
+			// Results were left on this page; a later run reads it again
+			session.NextPage = pageNum
+			return nil
+		}
 
-		// Add new results
-		session.Results = append(session.Results, pageResults...)
-
-		pageNum++
-
-		// Safety check to prevent infinite loop
-		if pageNum > 100 {
+		if s.lastPage() {
+			s.logger.Debug("Next page button is disabled")
+			break
+		}
+		if pageNum == maxSearchPages {
 			s.logger.Warn("Reached maximum page limit")
 			break
 		}
 
 		// Add delay between pages
-		time.Sleep(1 * time.Second)
+		if err := pause.Settle(s.page); err != nil {
+			return err
+		}
 	}
 
+	session.NextPage = 0
 	return nil
 }
 
+// lastPage reports whether the current results page shows its next page
+// button disabled
+func (s *SearchManager) lastPage() bool {
+	nextButton, _ := selectors.Find(s.page, selectors.SearchNextButton)
+	if nextButton == nil {
+		return false
+	}
+	disabled, err := nextButton.Attribute("disabled")
+	return err == nil && disabled != nil
+}
+
+// searchPageURL returns searchURL pointed at the given results page
+func searchPageURL(searchURL string, page int) string {
+	parsed, err := url.Parse(searchURL)
+	if err != nil {
+		return searchURL
+	}
+	params := parsed.Query()
+	params.Set("page", strconv.Itoa(page))
+	// Spaces as %20 rather than +, as in buildSearchURL
+	parsed.RawQuery = strings.ReplaceAll(params.Encode(), "+", "%20")
+	return parsed.String()
+}
+
 // GetSearchStats returns statistics about the search
 func (s *SearchManager) GetSearchStats(session *SearchSession) map[string]interface{} {
 	stats := map[string]interface{}{
@@ -0,0 +1,165 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/pause"
+	"linkedin-automation/profileurl"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/selectors"
+)
+
+// Recommendation modules on a profile page, used as the provenance of the
+// profiles found in them
+const (
+	SourceAlsoViewed = "also-viewed" // "People also viewed"
+	SourceMayKnow    = "may-know"    // "People you may know"
+)
+
+// SimilarSources lists the recommendation modules GetSimilarProfiles can harvest
+var SimilarSources = []string{SourceAlsoViewed, SourceMayKnow}
+
+// similarModules maps each recommendation module to its selector
+var similarModules = map[string]selectors.Key{
+	SourceAlsoViewed: selectors.ProfileAlsoViewed,
+	SourceMayKnow:    selectors.ProfileMayKnow,
+}
+
+// GetSimilarProfiles visits a seed profile and collects up to maxResults
+// profiles from its recommendation modules. Results are tagged with search
+// query "similar:<seed>" and the module they came from, e.g.
+// "also-viewed:<seed>", as their source. The visit counts against the profile
+// visit rate limit.
+func (s *SearchManager) GetSimilarProfiles(ctx context.Context, seedURL string, sources []string, maxResults int) (found *SearchSession, err error) {
+	if profileurl.Slug(seedURL) == "" {
+		return nil, fmt.Errorf("not a profile URL: %s", seedURL)
+	}
+	seedURL = profileurl.Canonicalize(seedURL)
+	if len(sources) == 0 {
+		sources = SimilarSources
+	}
+	for _, source := range sources {
+		if _, ok := similarModules[source]; !ok {
+			return nil, fmt.Errorf("unknown recommendation module %q: expected %s", source, strings.Join(SimilarSources, " or "))
+		}
+	}
+	defer s.withContext(ctx)()
+	defer func() { s.captureFailure("similar profiles", err) }()
+
+	s.logger.WithFields(logrus.Fields{
+		"profile":     seedURL,
+		"sources":     sources,
+		"max_results": maxResults,
+	}).Info("Starting similar profile extraction")
+
+	startTime := time.Now()
+	session := &SearchSession{
+		Query: SearchQuery{
+			MaxResults: maxResults,
+			SimilarTo:  seedURL,
+		},
+		Results:    make([]*SearchResult, 0),
+		Profiles:   make([]string, 0),
+		SearchTime: startTime,
+	}
+
+	if s.rateLimiter != nil {
+		if err := s.rateLimiter.WaitForPermission(ctx, ratelimit.ActionVisit); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.page.Navigate(seedURL); err != nil {
+		return nil, fmt.Errorf("failed to navigate to profile: %w", err)
+	}
+	if err := s.page.WaitLoad(); err != nil {
+		return nil, fmt.Errorf("failed to wait for page load: %w", err)
+	}
+	if err := s.handleLoginRedirect(); err != nil {
+		return nil, fmt.Errorf("login redirect failed: %w", err)
+	}
+
+	// The side modules are rendered once the page is scrolled
+	for i := 0; i < 4; i++ {
+		if err := s.page.Mouse.Scroll(0, 600, 6); err != nil {
+			s.logger.WithError(err).Debug("Failed to scroll profile")
+			break
+		}
+		if err := pause.Settle(s.page); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, source := range sources {
+		added := s.extractRecommendations(session, source, maxResults)
+		s.logger.WithFields(logrus.Fields{
+			"source": source,
+			"added":  added,
+		}).Debug("Extracted recommendations")
+	}
+	if len(session.Results) == 0 {
+		return nil, fmt.Errorf("no recommended profiles found on %s", seedURL)
+	}
+
+	for _, result := range session.Results {
+		session.Profiles = append(session.Profiles, result.ProfileURL)
+	}
+	session.Duration = time.Since(startTime)
+
+	s.logger.WithFields(logrus.Fields{
+		"profile":         seedURL,
+		"unique_profiles": len(session.Profiles),
+		"duration":        session.Duration,
+	}).Info("Similar profile extraction completed")
+
+	return session, nil
+}
+
+// extractRecommendations adds the profiles listed in one recommendation module,
+// returning how many were added
+func (s *SearchManager) extractRecommendations(session *SearchSession, source string, maxResults int) int {
+	module, _ := selectors.Find(s.page, similarModules[source])
+	if module == nil {
+		s.logger.WithField("source", source).Debug("Recommendation module not shown")
+		return 0
+	}
+
+	seed := session.Query.SimilarTo
+	added := 0
+	for _, item := range selectors.FindAllIn(module, selectors.ProfileRecommendation) {
+		if len(session.Results) >= maxResults {
+			break
+		}
+
+		link := selectors.FindIn(item, selectors.ProfileLink)
+		if link == nil {
+			continue
+		}
+		href, err := link.Attribute("href")
+		if err != nil || href == nil || profileurl.Slug(*href) == "" {
+			continue
+		}
+
+		profileURL := profileurl.Canonicalize(*href)
+		if profileURL == seed || hasProfile(session, profileURL) {
+			continue
+		}
+
+		session.Results = append(session.Results, &SearchResult{
+			URL:         profileURL,
+			ProfileURL:  profileURL,
+			Name:        selectors.TextIn(item, selectors.ProfileRecommendationName),
+			Title:       selectors.TextIn(item, selectors.ProfileRecommendationHeadline),
+			SearchQuery: "similar:" + profileurl.Slug(seed),
+			Source:      source + ":" + seed,
+		})
+		added++
+	}
+
+	return added
+}
@@ -0,0 +1,595 @@
+package selectors
+
+// Login page
+const (
+	LoginEmail     Key = "login.email"
+	LoginPassword  Key = "login.password"
+	LoginSubmit    Key = "login.submit"
+	LoginPin       Key = "login.pin"
+	LoginPinSubmit Key = "login.pin_submit"
+	LoginError     Key = "login.error"
+)
+
+// Login CAPTCHA challenge
+const (
+	CaptchaFrame       Key = "captcha.frame"
+	CaptchaTokenInput  Key = "captcha.token_input"
+	CaptchaForm        Key = "captcha.form"
+	CaptchaImage       Key = "captcha.image"
+	CaptchaImageInput  Key = "captcha.image_input"
+	CaptchaImageSubmit Key = "captcha.image_submit"
+)
+
+// People search results
+const (
+	SearchResultsContainer Key = "search.results_container"
+	SearchResult           Key = "search.result"
+	SearchResultName       Key = "search.result_name"
+	SearchResultTitle      Key = "search.result_title"
+	SearchResultCompany    Key = "search.result_company"
+	SearchResultLocation   Key = "search.result_location"
+	SearchResultLink       Key = "search.result_link"
+	SearchResultDegree     Key = "search.result_degree"
+	SearchResultInsight    Key = "search.result_insight" // Mutual connections and similar notes under the result
+	SearchResultOpenToWork Key = "search.result_open_to_work"
+	SearchResultHiring     Key = "search.result_hiring"
+	SearchResultPremium    Key = "search.result_premium"
+	SearchNextButton       Key = "search.next_button"
+)
+
+// Lists loaded in chunks (group members, company people)
+const (
+	ShowMoreButton Key = "list.show_more_button"
+	EntityTitle    Key = "list.entity_title"
+	EntitySubtitle Key = "list.entity_subtitle"
+	ProfileLink    Key = "list.profile_link"
+	GroupMember    Key = "group.member"
+)
+
+// Profile page
+const (
+	ProfileContent       Key = "profile.content"
+	ProfileName          Key = "profile.name"
+	ProfileHeadline      Key = "profile.headline"
+	ProfileLocation      Key = "profile.location"
+	ProfileCompany       Key = "profile.company"
+	ProfileIndustry      Key = "profile.industry"
+	ProfileAbout         Key = "profile.about"
+	ProfileExperience    Key = "profile.experience"
+	ProfileRecentPost    Key = "profile.recent_post"
+	ProfileConnected     Key = "profile.connected"
+	ProfilePending       Key = "profile.pending"
+	ProfileActions       Key = "profile.actions"
+	ProfileConnectButton Key = "profile.connect_button"
+	ProfileMessageButton Key = "profile.message_button"
+	ProfileMoreButton    Key = "profile.more_button"
+	ProfileMoreConnect   Key = "profile.more_connect"
+	ProfileFollowButton  Key = "profile.follow_button"
+)
+
+// Profile page recommendation modules
+const (
+	ProfileAlsoViewed             Key = "profile.also_viewed"
+	ProfileMayKnow                Key = "profile.may_know"
+	ProfileRecommendation         Key = "profile.recommendation"
+	ProfileRecommendationName     Key = "profile.recommendation_name"
+	ProfileRecommendationHeadline Key = "profile.recommendation_headline"
+)
+
+// Profile skills page
+const (
+	SkillItem          Key = "skills.item"
+	SkillName          Key = "skills.name"
+	SkillEndorseButton Key = "skills.endorse_button"
+)
+
+// Connection invitation dialog
+const (
+	InviteDialog      Key = "invite.dialog"
+	InviteNoteInput   Key = "invite.note_input"
+	InviteAddNote     Key = "invite.add_note"
+	InviteSendButton  Key = "invite.send_button"
+	InviteNoNote      Key = "invite.send_without_note"
+	InviteSent        Key = "invite.sent"
+	InviteLimitAlert  Key = "invite.limit_alert"
+	InviteLimitClose  Key = "invite.limit_close"
+	InviteHowKnow     Key = "invite.how_know_option"
+	InviteHowKnowNext Key = "invite.how_know_next"
+	InviteEmailInput  Key = "invite.email_input"
+	InviteDismiss     Key = "invite.dismiss"
+)
+
+// Messaging
+const (
+	MessagingRecipientsInput   Key = "messaging.recipients_input"
+	MessagingSuggestion        Key = "messaging.recipient_suggestion"
+	MessagingComposeInput      Key = "messaging.compose_input"
+	MessagingSendButton        Key = "messaging.send_button"
+	MessagingConversationsList Key = "messaging.conversations_list"
+	MessagingConversation      Key = "messaging.conversation"
+	MessagingConversationName  Key = "messaging.conversation_name"
+	MessagingConversationText  Key = "messaging.conversation_snippet"
+	MessagingEvent             Key = "messaging.event"
+	MessagingEventItem         Key = "messaging.event_item"
+	MessagingEventBody         Key = "messaging.event_body"
+	MessagingGroupProfileLink  Key = "messaging.group_profile_link"
+	MessagingGroupName         Key = "messaging.group_name"
+	MessagingGroupTimestamp    Key = "messaging.group_timestamp"
+	MessagingThreadParticipant Key = "messaging.thread_participant"
+	MessagingGroupFacepile     Key = "messaging.group_facepile"
+)
+
+// InMail compose form
+const (
+	InMailSubjectInput Key = "inmail.subject_input"
+	InMailCredits      Key = "inmail.credits"
+	InMailOpenProfile  Key = "inmail.open_profile"
+)
+
+// My Network
+const (
+	NetworkConnectionCard     Key = "network.connection_card"
+	NetworkConnectionName     Key = "network.connection_name"
+	NetworkConnectionHeadline Key = "network.connection_headline"
+	NetworkConnectionDate     Key = "network.connection_date"
+)
+
+// Catch up tab
+const (
+	CatchUpCard   Key = "catchup.card"
+	CatchUpName   Key = "catchup.name"
+	CatchUpDetail Key = "catchup.detail"
+)
+
+// Received invitations
+const (
+	InvitationCard     Key = "invitations.card"
+	InvitationName     Key = "invitations.name"
+	InvitationHeadline Key = "invitations.headline"
+	InvitationMessage  Key = "invitations.message"
+	InvitationMutual   Key = "invitations.mutual"
+	InvitationPhoto    Key = "invitations.photo"
+	InvitationAccept   Key = "invitations.accept"
+	InvitationIgnore   Key = "invitations.ignore"
+)
+
+// Global navigation bar
+const (
+	NavNotifications Key = "nav.notifications"
+	NavSearch        Key = "nav.search"
+)
+
+// Account restriction notices
+const (
+	RestrictionNotice Key = "restriction.notice" // Checked for uitext.Restricted wording
+)
+
+// Feed posts
+const (
+	Post              Key = "post.container"
+	PostAuthor        Key = "post.author"
+	PostAuthorLink    Key = "post.author_link"
+	PostText          Key = "post.text"
+	PostLikeButton    Key = "post.like_button"
+	PostCommentButton Key = "post.comment_button"
+	PostCommentEditor Key = "post.comment_editor"
+	PostCommentSubmit Key = "post.comment_submit"
+)
+
+// Company pages
+const (
+	CompanyName         Key = "company.name"
+	CompanySize         Key = "company.size"
+	CompanyPeopleCard   Key = "company.people_card"
+	CompanyPeopleName   Key = "company.people_name"
+	CompanyPeopleDetail Key = "company.people_headline"
+)
+
+var defaults = map[Key][]string{
+	LoginEmail:     {"input[name='session_key']", "input#username"},
+	LoginPassword:  {"input[name='session_password']", "input#password"},
+	LoginSubmit:    {"button[type='submit']"},
+	LoginPin:       {"input[name='pin']"},
+	LoginPinSubmit: {"button#email-pin-submit-button", "button#two-step-submit-button", "form#email-pin-challenge button[type='submit']"},
+	LoginError: {
+		".alert-error",
+		".login__form-error",
+		".form-error",
+		"[data-test-id='error']",
+	},
+
+	CaptchaFrame: {
+		"iframe#captcha-internal",
+		"iframe[src*='arkoselabs']",
+		"iframe[src*='funcaptcha']",
+	},
+	CaptchaTokenInput: {
+		"input[name='captchaUserResponseToken']",
+		"input#captchaUserResponseToken",
+		"input[name='fc-token']",
+	},
+	CaptchaForm:        {"form#captcha-challenge", "form[action*='checkpoint']"},
+	CaptchaImage:       {"img#captcha-image", "img[src*='captcha']"},
+	CaptchaImageInput:  {"input[name='captcha']", "input#captcha-input"},
+	CaptchaImageSubmit: {"form[action*='checkpoint'] button[type='submit']", "button[type='submit']"},
+
+	SearchResultsContainer: {
+		".search-results__container",
+		".reusable-search__result-container",
+		".search-results-page",
+		"[data-test-id='search-results-container']",
+	},
+	SearchResult: {
+		".search-result__info",
+		".reusable-search__result-container",
+		".people-search-card",
+		"[data-test-id='search-result']",
+	},
+	SearchResultName: {
+		".name span",
+		"span[aria-hidden='true']",
+		".entity-result__title-text",
+	},
+	SearchResultTitle:    {".subline-level-1", ".entity-result__primary-subtitle"},
+	SearchResultCompany:  {".subline-level-2", ".entity-result__secondary-subtitle"},
+	SearchResultLocation: {".entity-result__simple-insight-text"},
+	SearchResultLink:     {"a"},
+	SearchResultDegree: {
+		".entity-result__badge-text",
+		".entity-result__badge",
+		"[class*='distance-badge']",
+	},
+	SearchResultInsight: {
+		".entity-result__insights",
+		".entity-result__simple-insight-text",
+		".reusable-search-simple-insight__text",
+	},
+	SearchResultOpenToWork: {
+		"img[alt*='#OPEN_TO_WORK']",
+		"[class*='open-to-work']",
+		"[class*='open_to_work']",
+	},
+	SearchResultHiring: {
+		"img[alt*='#HIRING']",
+		"[class*='hiring-frame']",
+		"[class*='hiring_frame']",
+	},
+	SearchResultPremium: {
+		"[data-test-icon*='premium']",
+		"li-icon[type*='premium']",
+		".premium-icon",
+	},
+	SearchNextButton: {
+		"button[aria-label*='{next}']",
+		".pagination__next",
+		".artdeco-pagination__button--next",
+	},
+
+	ShowMoreButton: {
+		"button.scaffold-finite-scroll__load-button",
+		"button[aria-label*='{show_more_results}']",
+	},
+	EntityTitle:    {".artdeco-entity-lockup__title"},
+	EntitySubtitle: {".artdeco-entity-lockup__subtitle"},
+	ProfileLink:    {"a[href*='/in/']"},
+	GroupMember: {
+		".groups-members-list__typeahead-result",
+		".groups-members-list li.artdeco-list__item",
+		"li.artdeco-list__item",
+	},
+
+	ProfileContent: {
+		".pv-profile-wrapper",
+		".profile-content",
+		".pv-top-card",
+		"[data-test-id='profile-wrapper']",
+	},
+	ProfileName:     {"h1.text-heading-xlarge", ".pv-top-card--list li:first-child", "h1"},
+	ProfileHeadline: {".pv-text-details__left-panel .text-body-medium", ".text-body-medium.break-words"},
+	ProfileLocation: {".pv-text-details__left-panel .text-body-small.inline", ".pv-top-card--list-bullet li"},
+	ProfileCompany:  {"button[aria-label^='Current company'] span", ".pv-text-details__right-panel-item-text"},
+	ProfileIndustry: {"[data-field='industry']", ".pv-top-card--industry", ".top-card-layout__industry"},
+	ProfileAbout: {
+		"section:has(#about) .inline-show-more-text span[aria-hidden='true']",
+		"section:has(#about) .pv-shared-text-with-see-more span[aria-hidden='true']",
+		".pv-about__summary-text",
+	},
+	ProfileExperience: {
+		"section:has(#experience) .pvs-list__outer-container",
+		"section:has(#experience)",
+		".pv-profile-section.experience-section",
+	},
+	ProfileRecentPost: {
+		"section:has(#content_collections) .update-components-text",
+		"section:has(#content_collections) .feed-shared-update-v2__description",
+		".pv-recent-activity-section .feed-shared-text",
+	},
+	ProfileConnected: {
+		".pv-s-profile-actions--connect.mutual",
+		"[data-test-id='profile-connect-button'][aria-label*='{connected}']",
+		".pv-s-profile-actions--message",
+		"[data-test-id='profile-message-button']",
+	},
+	ProfilePending: {
+		".pv-s-profile-actions--connect.pending",
+		"[data-test-id='profile-connect-button'][aria-label*='{pending}']",
+		".pv-s-profile-actions--withdraw",
+	},
+	ProfileActions: {
+		".pv-s-profile-actions--connect:not(.pending):not(.mutual)",
+		"[data-test-id='profile-connect-button']",
+		".pvs-profile-actions__action",
+	},
+	ProfileConnectButton: {
+		".pv-s-profile-actions--connect",
+		"[data-test-id='profile-connect-button']",
+		".pvs-profile-actions__action",
+		"button[aria-label*='{connect}']",
+	},
+	ProfileMessageButton: {"button[aria-label*='{message}']", ".pvs-profile-actions__action"},
+	ProfileMoreButton: {
+		"button[aria-label='{more_actions}']",
+		".pvs-profile-actions__overflow-toggle",
+		".pv-s-profile-actions__overflow-toggle",
+	},
+	ProfileMoreConnect: {
+		".artdeco-dropdown__content div[role='button'][aria-label*='{invite_to_connect}']",
+		".pvs-overflow-actions-dropdown__content div[role='button']",
+		".artdeco-dropdown__item",
+	},
+	ProfileFollowButton: {
+		"button[aria-label*='{follow}']",
+		".pvs-profile-actions__action[aria-label*='{follow}']",
+		".artdeco-dropdown__content div[role='button'][aria-label*='{follow}']",
+	},
+
+	ProfileAlsoViewed: {
+		"section.artdeco-card:has(#browsemap_recommendation)",
+		"section.pv-browsemap-section",
+	},
+	ProfileMayKnow: {
+		"section.artdeco-card:has([id^='pymk_recommendation'])",
+		"section.pv-pymk-section",
+	},
+	ProfileRecommendation: {"li.artdeco-list__item", "li.pvs-list__item--line-separated", "li"},
+	ProfileRecommendationName: {
+		".t-bold span[aria-hidden='true']",
+		".artdeco-entity-lockup__title",
+		".t-bold",
+	},
+	ProfileRecommendationHeadline: {
+		".t-14.t-normal span[aria-hidden='true']",
+		".artdeco-entity-lockup__subtitle",
+		".t-14.t-normal",
+	},
+
+	SkillItem: {
+		"li.pvs-list__paged-list-item",
+		"li.artdeco-list__item",
+		".pv-skill-category-entity",
+	},
+	SkillName: {
+		".t-bold span[aria-hidden='true']",
+		".pv-skill-category-entity__name-text",
+		".t-bold",
+	},
+	SkillEndorseButton: {
+		"button[aria-label^='{endorse}']",
+		"button.pv-skill-entity__endorse-button",
+	},
+
+	InviteDialog: {
+		".send-invite-modal",
+		".modal__content",
+		"[data-test-id='connection-dialog']",
+		".artdeco-modal",
+	},
+	InviteNoteInput: {
+		"textarea[name='message']",
+		".send-invite__message-input",
+		"textarea[placeholder*='add a note']",
+	},
+	InviteAddNote: {
+		".artdeco-modal button[aria-label='{add_note}']",
+		"button[aria-label*='{add_note}']",
+	},
+	InviteSendButton: {
+		"button[aria-label*='{send_invitation}']",
+		".send-invite__button",
+		"button[type='submit']",
+	},
+	InviteNoNote: {
+		".artdeco-modal button[aria-label='{send_without_note}']",
+		"button[aria-label*='{send_without_note}']",
+	},
+	InviteSent: {
+		".pv-s-profile-actions--connect.pending",
+		"[data-test-id='profile-connect-button'][aria-label*='{pending}']",
+		".pv-s-profile-actions--withdraw",
+		".success-indicator",
+	},
+	InviteLimitAlert: {
+		".ip-fuse-limit-alert",
+		"[data-test-modal-id='fuse-limit-alert']",
+		".artdeco-modal[aria-labelledby*='ip-fuse-limit-alert']",
+	},
+	InviteHowKnow: {
+		"button[aria-label='{other}']",
+		".send-invite__howKnowOption button",
+		"input[type='radio'][value='OTHER'] + label",
+	},
+	InviteHowKnowNext: {
+		".artdeco-modal button[aria-label='{connect}']",
+		".artdeco-modal .artdeco-button--primary",
+	},
+	InviteEmailInput: {
+		".artdeco-modal input[name='email']",
+		".artdeco-modal input[type='email']",
+		"#email",
+	},
+	InviteDismiss: {
+		".artdeco-modal__dismiss",
+		"button[aria-label='{dismiss}']",
+	},
+	InviteLimitClose: {
+		".ip-fuse-limit-alert__primary-action",
+		".artdeco-modal button[aria-label='Got it']",
+		".artdeco-modal__dismiss",
+	},
+
+	MessagingRecipientsInput: {
+		"input[name='recipients']",
+		".msg-form__recipients-input",
+		"[data-test-id='recipients-input']",
+		"input[placeholder*='Recipients']",
+	},
+	MessagingSuggestion: {
+		".msg-suggestion-listitem",
+		".recipient-suggestion",
+		"[data-test-id='recipient-suggestion']",
+	},
+	MessagingComposeInput: {
+		"textarea[aria-label*='{write_message}']",
+		"textarea[placeholder*='{write_message}']",
+		".msg-form__contenteditable",
+		"[data-test-id='message-input']",
+		".msg-textarea",
+	},
+	MessagingSendButton: {
+		"button[aria-label*='{send}']",
+		".msg-form__send-button",
+		"[data-test-id='send-button']",
+		"button[type='submit']",
+	},
+	InMailSubjectInput: {
+		"input[name='subject']",
+		".msg-form__subject",
+		"input[placeholder*='Subject']",
+	},
+	InMailCredits: {
+		".msg-inmail-credits-display",
+		".msg-form__inmail-credits",
+		"[data-test-inmail-credits]",
+	},
+	InMailOpenProfile: {
+		".msg-form__open-profile",
+		".msg-inmail-credits-display",
+		".msg-form__inmail-credits",
+	},
+	MessagingConversationsList: {
+		".msg-conversations-container",
+		".conversation-list-container",
+		"[data-test-id='conversations-list']",
+	},
+	MessagingConversation: {
+		".msg-conversation-listitem",
+		".conversation-list-item",
+		"[data-test-id='conversation-item']",
+	},
+	MessagingConversationName: {".msg-conversation-listitem__participant-names", ".conversation-title"},
+	MessagingConversationText: {".msg-conversation-listitem__last-message", ".conversation-snippet"},
+	MessagingEvent:            {".msg-s-message-list__event"},
+	MessagingEventItem:        {".msg-s-event-listitem"},
+	MessagingEventBody:        {".msg-s-event-listitem__body"},
+	MessagingGroupProfileLink: {"a.msg-s-message-group__profile-link"},
+	MessagingGroupName:        {".msg-s-message-group__name"},
+	MessagingGroupFacepile:    {".msg-facepile-grid--group-size-2", ".msg-facepile-grid--group-size-3", ".msg-facepile-grid--group-size-4"},
+	MessagingGroupTimestamp:   {".msg-s-message-group__timestamp"},
+	MessagingThreadParticipant: {
+		"a.msg-thread__link-to-profile",
+		".msg-entity-lockup a[href*='/in/']",
+		".msg-title-bar a[href*='/in/']",
+	},
+
+	NetworkConnectionCard: {
+		".mn-connections__connection-card",
+		".connection-card",
+		"[data-test-id='connection-item']",
+	},
+	NetworkConnectionName:     {".mn-connection-card__name", ".artdeco-entity-lockup__title"},
+	NetworkConnectionHeadline: {".mn-connection-card__occupation", ".artdeco-entity-lockup__subtitle"},
+	NetworkConnectionDate:     {"time.time-badge", ".mn-connection-card__time-badge", "time"},
+
+	CatchUpCard: {
+		".catch-up-card",
+		"[data-view-name='catch-up-card']",
+		".mn-catch-up-card",
+	},
+	CatchUpName:   {".catch-up-card__name", ".artdeco-entity-lockup__title"},
+	CatchUpDetail: {".catch-up-card__headline", ".catch-up-card__occasion", ".artdeco-entity-lockup__subtitle"},
+
+	InvitationCard: {
+		"li.invitation-card",
+		".invitation-card",
+		"[data-view-name='pending-invitation']",
+	},
+	InvitationName:     {".invitation-card__title", ".artdeco-entity-lockup__title"},
+	InvitationHeadline: {".invitation-card__subtitle", ".artdeco-entity-lockup__subtitle"},
+	InvitationMessage:  {".invitation-card__custom-message", ".invitation-card__message"},
+	InvitationMutual:   {".member-insights__reason", ".invitation-card__insights"},
+	InvitationPhoto:    {"img[src*='profile-displayphoto']"},
+	InvitationAccept:   {"button[aria-label^='Accept']"},
+	InvitationIgnore:   {"button[aria-label^='Ignore']"},
+
+	NavNotifications: {
+		"a.global-nav__primary-link[href*='/notifications/']",
+		"a[href*='/notifications/']",
+	},
+	NavSearch: {
+		"input.search-global-typeahead__input",
+		"#global-nav-typeahead input",
+		"header input[role='combobox']",
+	},
+
+	RestrictionNotice: {
+		".artdeco-global-alert",
+		".global-alert-banner",
+		".artdeco-modal[role='dialog'] h2",
+		"main h1",
+	},
+
+	Post: {"div[data-urn^='urn:li:activity:']"},
+	PostAuthor: {
+		".update-components-actor__name span[aria-hidden='true']",
+		".update-components-actor__title span[aria-hidden='true']",
+	},
+	PostAuthorLink: {
+		"a.update-components-actor__meta-link",
+		"a.update-components-actor__image",
+	},
+	PostText: {".update-components-text", ".feed-shared-update-v2__description"},
+	PostLikeButton: {
+		"button.react-button__trigger",
+		"button[aria-label^='React Like']",
+		"button[aria-label*='{like}']",
+	},
+	PostCommentButton: {
+		"button.comment-button",
+		"button[aria-label='{comment}']",
+		"button[aria-label*='{comment}']",
+	},
+	PostCommentEditor: {
+		".comments-comment-box__form .ql-editor[contenteditable='true']",
+		".comments-comment-texteditor .ql-editor",
+		"div[role='textbox'][contenteditable='true']",
+	},
+	PostCommentSubmit: {
+		"button.comments-comment-box__submit-button",
+		"button.comments-comment-box__submit-button--cr",
+		"form.comments-comment-box__form button[type='submit']",
+	},
+
+	CompanyName: {"h1.org-top-card-summary__title", ".org-top-card-summary__title", "h1"},
+	CompanySize: {
+		".org-top-card-summary-info-list__info-item",
+		".org-top-card-secondary-content__see-all",
+		".org-page-details__employees-on-linkedin-count",
+	},
+	CompanyPeopleCard: {
+		".org-people-profile-card",
+		"li.org-people-profile-card__profile-card-spacing",
+		"li.grid__col--lg-8",
+	},
+	CompanyPeopleName:   {".org-people-profile-card__profile-title", ".artdeco-entity-lockup__title"},
+	CompanyPeopleDetail: {".artdeco-entity-lockup__subtitle", ".lt-line-clamp--multi-line"},
+}
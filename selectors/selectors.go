@@ -0,0 +1,268 @@
+// Package selectors centralizes the CSS selectors used to find LinkedIn page
+// elements. Each element has a named key with an ordered list of fallback
+// selectors; the defaults can be overridden from a YAML file so broken
+// selectors can be fixed without recompiling.
+package selectors
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"gopkg.in/yaml.v3"
+
+	"linkedin-automation/pause"
+	"linkedin-automation/uitext"
+)
+
+// Key names a page element
+type Key string
+
+var (
+	mu       sync.RWMutex
+	registry = copyDefaults()
+)
+
+// Get returns the fallback selectors for key, most preferred first, with
+// each {uitext key} placeholder expanded into the wording of the configured
+// UI language and English
+func Get(key Key) []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	list := registry[key]
+	result := make([]string, 0, len(list))
+	for _, selector := range list {
+		result = append(result, uitext.Expand(selector)...)
+	}
+	return result
+}
+
+// CSS returns the fallbacks for key joined into one selector group, for
+// lookups that accept any match. Matches come back in document order rather
+// than fallback order.
+func CSS(key Key) string {
+	return strings.Join(Get(key), ", ")
+}
+
+// All returns every key with its current selectors
+func All() map[Key][]string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	result := make(map[Key][]string, len(registry))
+	for key, list := range registry {
+		result[key] = append([]string(nil), list...)
+	}
+	return result
+}
+
+// Keys returns every known key in sorted order
+func Keys() []Key {
+	keys := make([]Key, 0, len(defaults))
+	for key := range defaults {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// Override replaces the selectors for a known key
+func Override(key Key, list []string) error {
+	if _, ok := defaults[key]; !ok {
+		return fmt.Errorf("unknown selector key %q", key)
+	}
+	if len(list) == 0 {
+		return fmt.Errorf("selector key %q needs at least one selector", key)
+	}
+
+	mu.Lock()
+	registry[key] = append([]string(nil), list...)
+	mu.Unlock()
+	return nil
+}
+
+// Reset restores the compiled-in defaults
+func Reset() {
+	mu.Lock()
+	registry = copyDefaults()
+	mu.Unlock()
+}
+
+// LoadFile applies overrides from a YAML file mapping keys to a selector or a
+// list of selectors. A missing file is not an error; it returns the number of
+// keys overridden.
+func LoadFile(path string) (int, error) {
+	overrides, err := readFile(path)
+	if err != nil {
+		return 0, err
+	}
+	for key, list := range overrides {
+		if err := Override(key, list); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(overrides), nil
+}
+
+// ReloadFile replaces every override with those in the file, in one step so
+// lookups never see a mix of the old and new file. A bad file changes nothing.
+func ReloadFile(path string) (int, error) {
+	overrides, err := readFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	reloaded := copyDefaults()
+	for key, list := range overrides {
+		reloaded[key] = append([]string(nil), list...)
+	}
+	mu.Lock()
+	registry = reloaded
+	mu.Unlock()
+
+	return len(overrides), nil
+}
+
+// readFile reads and validates the overrides in a selectors file. A missing
+// file has none.
+func readFile(path string) (map[Key][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read selectors file: %w", err)
+	}
+
+	var raw map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse selectors file: %w", err)
+	}
+
+	overrides := make(map[Key][]string, len(raw))
+	for name, node := range raw {
+		var list []string
+		if node.Kind == yaml.ScalarNode {
+			list = []string{node.Value}
+		} else if err := node.Decode(&list); err != nil {
+			return nil, fmt.Errorf("selector key %q: expected a selector or list of selectors", name)
+		}
+		overrides[Key(name)] = list
+	}
+
+	// Validate everything before applying anything, so a bad file changes nothing
+	for key, list := range overrides {
+		if _, ok := defaults[key]; !ok {
+			return nil, fmt.Errorf("unknown selector key %q in %s", key, path)
+		}
+		if len(list) == 0 {
+			return nil, fmt.Errorf("selector key %q in %s needs at least one selector", key, path)
+		}
+		for _, selector := range list {
+			if err := uitext.CheckSelector(selector); err != nil {
+				return nil, fmt.Errorf("selector key %q in %s: %w", key, path, err)
+			}
+		}
+	}
+
+	return overrides, nil
+}
+
+// Find returns the first element matching key's selectors in fallback order,
+// without waiting, along with the selector that matched
+func Find(page *rod.Page, key Key) (*rod.Element, string) {
+	for _, selector := range Get(key) {
+		elements, err := page.Elements(selector)
+		if err == nil && len(elements) > 0 {
+			return elements[0], selector
+		}
+	}
+	return nil, ""
+}
+
+// Text returns the trimmed text of the first element matching key
+func Text(page *rod.Page, key Key) string {
+	element, _ := Find(page, key)
+	if element == nil {
+		return ""
+	}
+	text, err := element.Text()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(text)
+}
+
+// FindAll returns the elements matching the first of key's selectors that matches anything
+func FindAll(page *rod.Page, key Key) []*rod.Element {
+	for _, selector := range Get(key) {
+		elements, err := page.Elements(selector)
+		if err == nil && len(elements) > 0 {
+			return elements
+		}
+	}
+	return nil
+}
+
+// FindIn returns the first descendant of parent matching key's selectors
+func FindIn(parent *rod.Element, key Key) *rod.Element {
+	for _, selector := range Get(key) {
+		elements, err := parent.Elements(selector)
+		if err == nil && len(elements) > 0 {
+			return elements[0]
+		}
+	}
+	return nil
+}
+
+// FindAllIn returns the descendants of parent matching the first of key's selectors that matches anything
+func FindAllIn(parent *rod.Element, key Key) []*rod.Element {
+	for _, selector := range Get(key) {
+		elements, err := parent.Elements(selector)
+		if err == nil && len(elements) > 0 {
+			return elements
+		}
+	}
+	return nil
+}
+
+// TextIn returns the trimmed text of the first descendant of parent matching key
+func TextIn(parent *rod.Element, key Key) string {
+	element := FindIn(parent, key)
+	if element == nil {
+		return ""
+	}
+	text, err := element.Text()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(text)
+}
+
+// Wait polls for an element matching key until the configured element
+// timeout passes or the context of page is cancelled
+func Wait(page *rod.Page, key Key) (*rod.Element, error) {
+	var element *rod.Element
+	err := pause.Until(page, pause.Current().ElementTimeout, func() bool {
+		element, _ = Find(page, key)
+		return element != nil
+	})
+	if errors.Is(err, pause.ErrTimeout) {
+		return nil, fmt.Errorf("timeout waiting for %s", key)
+	}
+	return element, err
+}
+
+func copyDefaults() map[Key][]string {
+	result := make(map[Key][]string, len(defaults))
+	for key, list := range defaults {
+		result[key] = append([]string(nil), list...)
+	}
+	return result
+}
@@ -0,0 +1,383 @@
+package sequence
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/queue"
+	"linkedin-automation/storage"
+	"linkedin-automation/templates"
+)
+
+// Engine advances enrolled prospects through their sequences. Each step is
+// queued as a connect or message task once it is due, and the enrollment moves
+// on when that task is done, so steps share the queue's limits and retries.
+type Engine struct {
+	store        Store
+	templates    TemplateSource
+	blacklist    Blacklist
+	sequences    map[string]*Sequence
+	logger       *logrus.Logger
+	syncInterval time.Duration
+	inboxLimit   int
+	nextSync     time.Time
+	mu           sync.Mutex // Guards sequences and the sync settings against reloads
+}
+
+// Store persists enrollments and queues their steps
+type Store interface {
+	queue.Store
+	GetTask(id int) (*storage.QueueTask, error)
+	ListSequenceEnrollments(sequence, status string) ([]*storage.SequenceEnrollment, error)
+	UpdateSequenceEnrollment(enrollment *storage.SequenceEnrollment) error
+	HasReplied(profileURL string) (bool, error)
+	GetReplyLabel(profileURL string) (*storage.ReplyLabel, error)
+	GetConnectionStatus(profileURL string) (string, *time.Time, error)
+}
+
+// TemplateSource resolves the templates named by steps
+type TemplateSource interface {
+	Get(kind, name string) (*storage.Template, error)
+}
+
+// Blacklist tells why a profile must not be contacted, or returns an empty string if it may be
+type Blacklist interface {
+	Blocked(ctx context.Context, page *rod.Page, profileURL string) (string, error)
+}
+
+// NewEngine creates an engine for the given sequences
+func NewEngine(store Store, templates TemplateSource, sequences map[string]*Sequence, logger *logrus.Logger) *Engine {
+	return &Engine{
+		store:        store,
+		templates:    templates,
+		sequences:    sequences,
+		logger:       logger,
+		syncInterval: 4 * time.Hour,
+		inboxLimit:   20,
+	}
+}
+
+// SetBlacklist stops enrollments of prospects on the do-not-contact list
+func (e *Engine) SetBlacklist(blacklist Blacklist) {
+	e.blacklist = blacklist
+}
+
+// SetSync controls how often a sync of accepted connections and the inbox is
+// queued while enrollments are in progress, and how many conversations it
+// scans. An interval of 0 leaves syncing to the user.
+func (e *Engine) SetSync(interval time.Duration, inboxLimit int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.syncInterval = interval
+	e.inboxLimit = inboxLimit
+}
+
+// SetSequences replaces the sequence definitions, e.g. after their files were
+// edited. Enrollments in a sequence that no longer exists wait until it is back.
+func (e *Engine) SetSequences(sequences map[string]*Sequence) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.sequences = sequences
+}
+
+// Schedule implements queue.Scheduler: it moves enrollments whose step task
+// has finished on to the next step and queues the steps that have become due
+func (e *Engine) Schedule(ctx context.Context, now time.Time) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	running, err := e.store.ListSequenceEnrollments("", storage.EnrollmentRunning)
+	if err != nil {
+		return err
+	}
+	for _, enrollment := range running {
+		if err := e.checkTask(enrollment); err != nil {
+			return err
+		}
+	}
+
+	active, err := e.store.ListSequenceEnrollments("", storage.EnrollmentActive)
+	if err != nil {
+		return err
+	}
+	for _, enrollment := range active {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if enrollment.NextRunAt.After(now) {
+			continue
+		}
+		if err := e.advance(ctx, enrollment, now); err != nil {
+			return err
+		}
+	}
+
+	if len(running) == 0 && len(active) == 0 {
+		return nil
+	}
+	return e.scheduleSync(now)
+}
+
+// checkTask moves an enrollment on once the task running its current step has finished
+func (e *Engine) checkTask(enrollment *storage.SequenceEnrollment) error {
+	sequence := e.sequences[enrollment.Sequence]
+	if sequence == nil {
+		return nil
+	}
+
+	task, err := e.store.GetTask(enrollment.TaskID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case task == nil:
+		enrollment.Status = storage.EnrollmentStopped
+		enrollment.LastError = fmt.Sprintf("queued task %d no longer exists", enrollment.TaskID)
+	case task.Status == storage.TaskDone:
+		finished := time.Now()
+		if task.FinishedAt != nil {
+			finished = *task.FinishedAt
+		}
+		enrollment.Step++
+		enrollment.LastStepAt = &finished
+		enrollment.NextRunAt = finished
+		enrollment.TaskID = 0
+		enrollment.LastError = ""
+		enrollment.Status = storage.EnrollmentActive
+		if enrollment.Step >= len(sequence.Steps) {
+			enrollment.Status = storage.EnrollmentCompleted
+		}
+	case task.Status == storage.TaskFailed:
+		enrollment.Status = storage.EnrollmentFailed
+		enrollment.LastError = task.LastError
+	case task.Status == storage.TaskCancelled:
+		enrollment.Status = storage.EnrollmentStopped
+		enrollment.LastError = fmt.Sprintf("queued task %d was cancelled", task.ID)
+	default:
+		return nil
+	}
+
+	e.log(enrollment).Info("Sequence step finished")
+	return e.store.UpdateSequenceEnrollment(enrollment)
+}
+
+// advance evaluates the next step of an enrollment that has become due,
+// queueing it, postponing it or ending the enrollment
+func (e *Engine) advance(ctx context.Context, enrollment *storage.SequenceEnrollment, now time.Time) error {
+	sequence := e.sequences[enrollment.Sequence]
+	if sequence == nil {
+		e.logger.WithField("sequence", enrollment.Sequence).Debug("Sequence no longer defined, leaving enrollment as it is")
+		return nil
+	}
+	if enrollment.Step >= len(sequence.Steps) {
+		enrollment.Status = storage.EnrollmentCompleted
+		return e.store.UpdateSequenceEnrollment(enrollment)
+	}
+
+	if sequence.StopsOnReply() {
+		replied, err := e.replied(enrollment.ProfileURL)
+		if err != nil {
+			return err
+		}
+		if replied {
+			enrollment.Status = storage.EnrollmentReplied
+			e.log(enrollment).Info("Prospect replied, ending sequence")
+			return e.store.UpdateSequenceEnrollment(enrollment)
+		}
+	}
+
+	if e.blacklist != nil {
+		reason, err := e.blacklist.Blocked(ctx, nil, enrollment.ProfileURL)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			enrollment.Status = storage.EnrollmentStopped
+			enrollment.LastError = "On the blacklist: " + reason
+			return e.store.UpdateSequenceEnrollment(enrollment)
+		}
+	}
+
+	step := sequence.Steps[enrollment.Step]
+	base := enrollment.CreatedAt
+	if enrollment.LastStepAt != nil {
+		base = *enrollment.LastStepAt
+	}
+
+	switch step.When {
+	case WhenAccepted:
+		status, acceptedAt, err := e.store.GetConnectionStatus(enrollment.ProfileURL)
+		if err != nil {
+			return err
+		}
+		if acceptedAt != nil {
+			base = *acceptedAt
+		} else if status != "" {
+			// Still pending; look again after the next sync
+			enrollment.NextRunAt = now.Add(e.recheckInterval())
+			return e.store.UpdateSequenceEnrollment(enrollment)
+		}
+		// With no request on record the prospect was already a connection
+
+	case WhenNoReply:
+		replied, err := e.replied(enrollment.ProfileURL)
+		if err != nil {
+			return err
+		}
+		if replied {
+			enrollment.Step++
+			enrollment.NextRunAt = now
+			if enrollment.Step >= len(sequence.Steps) {
+				enrollment.Status = storage.EnrollmentCompleted
+			}
+			e.log(enrollment).Info("Prospect replied, skipping sequence step")
+			return e.store.UpdateSequenceEnrollment(enrollment)
+		}
+	}
+
+	if due := base.Add(time.Duration(step.After)); due.After(now) {
+		enrollment.NextRunAt = due
+		return e.store.UpdateSequenceEnrollment(enrollment)
+	}
+
+	task, err := e.enqueue(sequence, step, enrollment.ProfileURL)
+	if err != nil {
+		enrollment.Status = storage.EnrollmentFailed
+		enrollment.LastError = err.Error()
+		e.log(enrollment).WithError(err).Error("Failed to queue sequence step")
+		return e.store.UpdateSequenceEnrollment(enrollment)
+	}
+
+	enrollment.Status = storage.EnrollmentRunning
+	enrollment.TaskID = task.ID
+	e.log(enrollment).WithField("task_id", task.ID).Info("Queued sequence step")
+	return e.store.UpdateSequenceEnrollment(enrollment)
+}
+
+// enqueue adds the task that performs step for profileURL
+func (e *Engine) enqueue(sequence *Sequence, step Step, profileURL string) (*storage.QueueTask, error) {
+	content, err := Content(e.templates, step)
+	if err != nil {
+		return nil, err
+	}
+
+	if step.Action == ActionConnect {
+		return queue.Enqueue(e.store, queue.KindConnect, queue.ConnectPayload{
+			ProfileURL: profileURL,
+			Message:    content,
+			Campaign:   sequence.Name,
+			Template:   step.Template,
+		}, queue.Options{})
+	}
+	return queue.Enqueue(e.store, queue.KindMessage, queue.MessagePayload{
+		RecipientURL: profileURL,
+		Content:      content,
+		Template:     step.Template,
+	}, queue.Options{})
+}
+
+// scheduleSync queues a sync of accepted connections and the inbox, which the
+// accepted and reply conditions depend on, unless one ran recently or is queued
+func (e *Engine) scheduleSync(now time.Time) error {
+	if e.syncInterval <= 0 || now.Before(e.nextSync) {
+		return nil
+	}
+
+	var last time.Time
+	for _, status := range []string{storage.TaskPending, storage.TaskRunning, storage.TaskDone} {
+		tasks, err := e.store.ListTasks(status)
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			if task.Kind != queue.KindSync {
+				continue
+			}
+			if status != storage.TaskDone {
+				e.nextSync = now.Add(time.Minute)
+				return nil
+			}
+			if task.FinishedAt != nil && task.FinishedAt.After(last) {
+				last = *task.FinishedAt
+			}
+		}
+	}
+
+	if next := last.Add(e.syncInterval); next.After(now) {
+		e.nextSync = next
+		return nil
+	}
+
+	if _, err := queue.Enqueue(e.store, queue.KindSync, queue.SyncPayload{InboxLimit: e.inboxLimit}, queue.Options{}); err != nil {
+		return err
+	}
+	e.logger.Info("Queued a sync of accepted connections and the inbox for sequences")
+	e.nextSync = now.Add(e.syncInterval)
+	return nil
+}
+
+// recheckInterval is how long to wait before looking again for an acceptance
+func (e *Engine) recheckInterval() time.Duration {
+	if e.syncInterval > 0 {
+		return e.syncInterval
+	}
+	return time.Hour
+}
+
+func (e *Engine) log(enrollment *storage.SequenceEnrollment) *logrus.Entry {
+	return e.logger.WithFields(logrus.Fields{
+		"sequence":    enrollment.Sequence,
+		"profile_url": enrollment.ProfileURL,
+		"step":        enrollment.Step + 1,
+		"status":      enrollment.Status,
+	})
+}
+
+// Content returns the note or message a step sends
+func Content(source TemplateSource, step Step) (string, error) {
+	if step.Template == "" {
+		return step.Message, nil
+	}
+
+	kind := templates.KindMessage
+	if step.Action == ActionConnect {
+		kind = templates.KindConnection
+	}
+	t, err := source.Get(kind, step.Template)
+	if err != nil {
+		return "", err
+	}
+	return t.Content, nil
+}
+
+// CheckTemplates reports the first step whose template cannot be resolved
+func CheckTemplates(source TemplateSource, sequence *Sequence) error {
+	for i, step := range sequence.Steps {
+		if _, err := Content(source, step); err != nil {
+			return fmt.Errorf("step %d of %q: %w", i+1, sequence.Name, err)
+		}
+	}
+	return nil
+}
+
+// replied reports whether a prospect replied, not counting a reply asking to
+// be contacted again later: the enrollment was postponed until the revisit
+// and picks up from there
+func (e *Engine) replied(profileURL string) (bool, error) {
+	replied, err := e.store.HasReplied(profileURL)
+	if err != nil || !replied {
+		return false, err
+	}
+
+	label, err := e.store.GetReplyLabel(profileURL)
+	if err != nil {
+		return false, err
+	}
+	return label == nil || label.Label != storage.ReplyLater, nil
+}
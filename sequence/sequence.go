@@ -0,0 +1,186 @@
+package sequence
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Step actions
+const (
+	ActionConnect = "connect"
+	ActionMessage = "message"
+)
+
+// Step conditions
+const (
+	WhenAlways   = "always"   // Run once the delay after the previous step has passed
+	WhenAccepted = "accepted" // Wait for the connection request to be accepted, then for the delay
+	WhenNoReply  = "no_reply" // Skip the step if the prospect has replied
+)
+
+// Sequence is a multi-step drip campaign run for each enrolled prospect
+type Sequence struct {
+	Name        string `yaml:"name"`
+	StopOnReply *bool  `yaml:"stop_on_reply"` // Defaults to true
+	Steps       []Step `yaml:"steps"`
+}
+
+// Step is one action of a sequence
+type Step struct {
+	Action   string   `yaml:"action"`   // connect or message
+	Template string   `yaml:"template"` // Stored or built-in template to send
+	Message  string   `yaml:"message"`  // Literal note or message, instead of a template
+	When     string   `yaml:"when"`     // always (default), accepted or no_reply
+	After    Duration `yaml:"after"`    // Delay after the previous step, or after acceptance for when: accepted
+}
+
+// Duration is a time.Duration that also accepts whole days, e.g. "2d"
+type Duration time.Duration
+
+// UnmarshalYAML parses durations such as "2d", "36h" or "90m"
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	parsed, err := ParseDuration(node.Value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// ParseDuration parses a Go duration or a number of days such as "2d"
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(n * float64(24*time.Hour)), nil
+	}
+	return time.ParseDuration(value)
+}
+
+// StopsOnReply reports whether a reply ends the sequence for the prospect
+func (s *Sequence) StopsOnReply() bool {
+	return s.StopOnReply == nil || *s.StopOnReply
+}
+
+// Validate checks that every step can be run
+func (s *Sequence) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("sequence has no name")
+	}
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("sequence %q has no steps", s.Name)
+	}
+
+	connected := false
+	for i := range s.Steps {
+		step := &s.Steps[i]
+		if step.When == "" {
+			step.When = WhenAlways
+		}
+
+		switch step.Action {
+		case ActionConnect:
+			connected = true
+		case ActionMessage:
+		default:
+			return fmt.Errorf("step %d of %q: unknown action %q (want connect or message)", i+1, s.Name, step.Action)
+		}
+
+		switch step.When {
+		case WhenAlways, WhenNoReply:
+		case WhenAccepted:
+			if !connected {
+				return fmt.Errorf("step %d of %q waits for acceptance, but no earlier step connects", i+1, s.Name)
+			}
+		default:
+			return fmt.Errorf("step %d of %q: unknown condition %q (want always, accepted or no_reply)", i+1, s.Name, step.When)
+		}
+
+		if step.Template != "" && step.Message != "" {
+			return fmt.Errorf("step %d of %q has both a template and a message", i+1, s.Name)
+		}
+		// A connection request may go out without a note; a message may not
+		if step.Action == ActionMessage && step.Template == "" && step.Message == "" {
+			return fmt.Errorf("step %d of %q needs a template or a message", i+1, s.Name)
+		}
+		if step.After < 0 {
+			return fmt.Errorf("step %d of %q has a negative delay", i+1, s.Name)
+		}
+	}
+	return nil
+}
+
+// LoadFile reads and validates a sequence definition. A sequence without a
+// name is named after its file.
+func LoadFile(path string) (*Sequence, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var sequence Sequence
+	if err := yaml.Unmarshal(data, &sequence); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if sequence.Name == "" {
+		sequence.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if err := sequence.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &sequence, nil
+}
+
+// LoadDir reads every .yaml and .yml sequence in dir, keyed by name.
+// A missing directory holds no sequences.
+func LoadDir(dir string) (map[string]*Sequence, error) {
+	sequences := make(map[string]*Sequence)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return sequences, nil
+		}
+		return nil, fmt.Errorf("failed to read sequences directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		sequence, err := LoadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := sequences[sequence.Name]; ok {
+			return nil, fmt.Errorf("sequence %q is defined more than once in %s", sequence.Name, dir)
+		}
+		sequences[sequence.Name] = sequence
+	}
+	return sequences, nil
+}
+
+// Names returns the names of sequences in alphabetical order
+func Names(sequences map[string]*Sequence) []string {
+	names := make([]string, 0, len(sequences))
+	for name := range sequences {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
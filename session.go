@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+
+	"linkedin-automation/auth"
+	"linkedin-automation/backup"
+	"linkedin-automation/browse"
+	"linkedin-automation/client"
+	"linkedin-automation/config"
+	"linkedin-automation/connect"
+	"linkedin-automation/endorse"
+	"linkedin-automation/engage"
+	"linkedin-automation/invitations"
+	"linkedin-automation/logger"
+	"linkedin-automation/message"
+	"linkedin-automation/nurture"
+	"linkedin-automation/reminders"
+	"linkedin-automation/resolve"
+	"linkedin-automation/retention"
+	"linkedin-automation/scrape"
+	"linkedin-automation/search"
+	"linkedin-automation/sequence"
+	"linkedin-automation/stealth"
+	"linkedin-automation/storage"
+	"linkedin-automation/templates"
+	"linkedin-automation/visit"
+)
+
+// browserSession is an authenticated, stealth-patched page shared by the command runners
+type browserSession = client.Session
+
+// newClient creates a client for cfg and db that honours the global
+// --headless, --dry-run, --capture-all and --navigation flags
+func newClient(cfg *config.Config, db *storage.Database) *client.Client {
+	return client.New(cfg, db, client.Options{
+		Headless:   headless,
+		DryRun:     dryRun,
+		CaptureAll: captureAll,
+		Command:    commandPath,
+		Navigation: navigation,
+	})
+}
+
+// openBrowserSession launches the browser, logs in and applies stealth to the page
+func openBrowserSession(ctx context.Context, cfg *config.Config, db *storage.Database) (*browserSession, error) {
+	return newClient(cfg, db).OpenSession(ctx)
+}
+
+// openStorage connects to the configured database and, when backups are
+// enabled, backs it up if the last backup is older than the interval and
+// keeps doing so for as long as the command runs
+func openStorage(cfg *config.Config) (*storage.Database, error) {
+	db, err := connectStorage(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Storage.Backup {
+		backups := newBackupManager(cfg, db)
+		if err := backups.RunIfDue(); err != nil {
+			logger.GetLogger().WithError(err).Warn("Database backup failed")
+		}
+		go backups.Schedule(context.Background())
+	}
+	return db, nil
+}
+
+// connectStorage connects to the configured database without scheduling backups
+func connectStorage(cfg *config.Config) (*storage.Database, error) {
+	return client.ConnectStorage(cfg, logger.GetLogger())
+}
+
+// storageSource returns the SQLite path or the DSN of the configured database
+func storageSource(cfg *config.Config) string {
+	if cfg.Storage.Type == "" || cfg.Storage.Type == storage.BackendSQLite {
+		return cfg.Storage.Path
+	}
+	return cfg.Storage.DSN
+}
+
+// newBackupManager creates a manager for the backups of the configured database
+func newBackupManager(cfg *config.Config, db *storage.Database) *backup.Manager {
+	return backup.NewManager(db, backup.Options{
+		Dir:      cfg.Storage.BackupDir,
+		Keep:     cfg.Storage.BackupKeep,
+		Interval: cfg.Storage.Interval,
+	}, logger.GetLogger())
+}
+
+// newRetentionManager creates a manager enforcing the configured retention
+// on db
+func newRetentionManager(cfg *config.Config, db *storage.Database) *retention.Manager {
+	return retention.NewManager(db, retention.Options{
+		Messages: cfg.Retention.Messages,
+		Profiles: cfg.Retention.Profiles,
+		Interval: cfg.Retention.Interval,
+	}, logger.GetLogger())
+}
+
+// newRemindersManager creates a manager nudging the connections who never
+// replied as configured
+func newRemindersManager(cfg *config.Config, db *storage.Database) *reminders.Manager {
+	return reminders.NewManager(db, templates.NewManager(db, logger.GetLogger()), reminders.Options{
+		After:     cfg.Reminders.After,
+		MaxNudges: cfg.Reminders.MaxNudges,
+		Template:  cfg.Reminders.Template,
+		Interval:  cfg.Reminders.Interval,
+	}, logger.GetLogger())
+}
+
+func newAuthManager(cfg *config.Config) *auth.AuthManager {
+	return newClient(cfg, nil).AuthManager()
+}
+
+func newStealthManager(ctx context.Context, cfg *config.Config) *stealth.StealthManager {
+	return newClient(cfg, nil).StealthManager(ctx)
+}
+
+func loadSelectors(cfg *config.Config) error {
+	return newClient(cfg, nil).LoadSelectors()
+}
+
+func newCompanyResolver(cfg *config.Config, session *browserSession, db *storage.Database) *resolve.Resolver {
+	return newClient(cfg, db).CompanyResolver(session)
+}
+
+func newSearchManager(cfg *config.Config, session *browserSession, db *storage.Database) *search.SearchManager {
+	return newClient(cfg, db).SearchManager(session)
+}
+
+func newSequenceEngine(cfg *config.Config, session *browserSession, db *storage.Database, sequences map[string]*sequence.Sequence) *sequence.Engine {
+	return newClient(cfg, db).SequenceEngine(session, sequences)
+}
+
+func newScrapeManager(cfg *config.Config, session *browserSession, db *storage.Database) *scrape.ScrapeManager {
+	return newClient(cfg, db).ScrapeManager(session)
+}
+
+func newConnectManager(cfg *config.Config, session *browserSession, db *storage.Database) *connect.ConnectManager {
+	return newClient(cfg, db).ConnectManager(session)
+}
+
+func newInvitationManager(cfg *config.Config, session *browserSession, db *storage.Database) *invitations.Manager {
+	return newClient(cfg, db).InvitationManager(session)
+}
+
+func newMessageManager(cfg *config.Config, session *browserSession, db *storage.Database) *message.MessageManager {
+	return newClient(cfg, db).MessageManager(session)
+}
+
+func newNurtureManager(cfg *config.Config, session *browserSession, db *storage.Database) *nurture.Manager {
+	return newClient(cfg, db).NurtureManager(session)
+}
+
+func newVisitManager(cfg *config.Config, session *browserSession, db *storage.Database) *visit.VisitManager {
+	return newClient(cfg, db).VisitManager(session)
+}
+
+func newFeedBrowser(cfg *config.Config, session *browserSession, db *storage.Database) *browse.FeedBrowser {
+	return newClient(cfg, db).FeedBrowser(session)
+}
+
+func newEndorseManager(cfg *config.Config, session *browserSession, db *storage.Database) *endorse.EndorseManager {
+	return newClient(cfg, db).EndorseManager(session)
+}
+
+func newEngageManager(cfg *config.Config, session *browserSession, db *storage.Database) *engage.EngageManager {
+	return newClient(cfg, db).EngageManager(session)
+}
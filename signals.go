@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"linkedin-automation/logger"
+)
+
+// signalContext returns a context that is cancelled on the first SIGINT or
+// SIGTERM, so batches stop waiting on the browser, store their results and
+// close the browser before exiting. A second signal exits immediately.
+func signalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-signals:
+			logger.GetLogger().WithField("signal", sig.String()).Warn("Interrupted, stopping (press Ctrl-C again to force quit)")
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+
+		<-signals
+		fmt.Fprintf(os.Stderr, "Forced quit\n")
+		os.Exit(130)
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel()
+	}
+}
+
+// printCheckpoint tells how far an interrupted batch got and how to pick it up
+// again; items already processed are skipped when the batch is resumed
+func printCheckpoint(cmd *cobra.Command, batchID string, processed, total int) {
+	fmt.Printf("Interrupted after %d of %d\n", processed, total)
+	fmt.Printf("Resume with: %s <same arguments> --batch-id %s --resume\n", cmd.CommandPath(), batchID)
+}
@@ -0,0 +1,85 @@
+package stealth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+
+	"linkedin-automation/errs"
+	"linkedin-automation/pause"
+)
+
+// HumanClick clicks element the way a person would: it is scrolled into view
+// with HumanLikeScroll when outside the viewport, checked to be visible,
+// enabled and not covered by an overlay, and hovered briefly before the
+// click. An element that stays hidden or covered is not clicked and
+// errs.ErrNotClickable is returned. With stealth disabled it is clicked
+// straight away.
+func (s *StealthManager) HumanClick(page *rod.Page, element *rod.Element) error {
+	if !s.config.Enabled {
+		return element.Click(proto.InputMouseButtonLeft, 1)
+	}
+
+	if err := s.scrollIntoView(page, element); err != nil {
+		return err
+	}
+
+	point, err := element.Interactable()
+	var covered *rod.ErrCovered
+	if errors.As(err, &covered) {
+		// Toasts and menus that are closing cover the page only for a moment
+		if err := pause.Settle(page); err != nil {
+			return err
+		}
+		point, err = element.Interactable()
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", errs.ErrNotClickable, err)
+	}
+	if disabled, err := element.Disabled(); err == nil && disabled {
+		return fmt.Errorf("%w: element is disabled", errs.ErrNotClickable)
+	}
+
+	// Aim near the middle rather than at its exact center
+	if shape, err := element.Shape(); err == nil {
+		box := shape.Box()
+		point.X += (s.rng.Float64() - 0.5) * box.Width / 3
+		point.Y += (s.rng.Float64() - 0.5) * box.Height / 3
+	}
+	if err := page.Mouse.MoveLinear(*point, 8+s.rng.Intn(12)); err != nil {
+		return fmt.Errorf("failed to move to element: %w", err)
+	}
+	if err := pause.Page(page, 150*time.Millisecond+time.Duration(s.rng.Int63n(int64(500*time.Millisecond)))); err != nil {
+		return err
+	}
+
+	return page.Mouse.Click(proto.InputMouseButtonLeft, 1)
+}
+
+// scrollIntoView scrolls until the middle of element is well within the
+// viewport, centering it roughly. Elements inside scrolled containers the mouse wheel does not move
+// are brought into view directly.
+func (s *StealthManager) scrollIntoView(page *rod.Page, element *rod.Element) error {
+	for i := 0; i < 3; i++ {
+		offset, err := element.Eval(`() => {
+			const rect = this.getBoundingClientRect();
+			const middle = rect.top + rect.height / 2;
+			if (middle >= window.innerHeight * 0.1 && middle <= window.innerHeight * 0.9) return 0;
+			return Math.round(middle - window.innerHeight / 2);
+		}`)
+		if err != nil {
+			return fmt.Errorf("failed to locate element: %w", err)
+		}
+		amount := offset.Value.Int()
+		if amount == 0 {
+			return nil
+		}
+		if err := s.HumanLikeScroll(page, amount); err != nil {
+			return err
+		}
+	}
+	return element.ScrollIntoView()
+}
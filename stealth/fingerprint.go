@@ -0,0 +1,119 @@
+package stealth
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+//go:embed fingerprint.js
+var fingerprintScript string
+
+// Fingerprint holds the spoofed canvas, WebGL and audio values of one account.
+// It is persisted so detection scripts see the same device on every run.
+type Fingerprint struct {
+	CanvasSeed    uint32    `json:"canvas_seed"`
+	AudioSeed     uint32    `json:"audio_seed"`
+	WebGLVendor   string    `json:"webgl_vendor"`
+	WebGLRenderer string    `json:"webgl_renderer"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// webGLProfiles are vendor/renderer pairs reported by common desktop GPUs in Chrome
+var webGLProfiles = [][2]string{
+	{"Google Inc. (Intel)", "ANGLE (Intel, Intel(R) UHD Graphics 620 Direct3D11 vs_5_0 ps_5_0, D3D11)"},
+	{"Google Inc. (Intel)", "ANGLE (Intel, Intel(R) Iris(R) Xe Graphics Direct3D11 vs_5_0 ps_5_0, D3D11)"},
+	{"Google Inc. (NVIDIA)", "ANGLE (NVIDIA, NVIDIA GeForce GTX 1650 Direct3D11 vs_5_0 ps_5_0, D3D11)"},
+	{"Google Inc. (NVIDIA)", "ANGLE (NVIDIA, NVIDIA GeForce RTX 3060 Direct3D11 vs_5_0 ps_5_0, D3D11)"},
+	{"Google Inc. (AMD)", "ANGLE (AMD, AMD Radeon(TM) Graphics Direct3D11 vs_5_0 ps_5_0, D3D11)"},
+}
+
+// NewFingerprint generates a random fingerprint
+func NewFingerprint(rng *rand.Rand) *Fingerprint {
+	profile := webGLProfiles[rng.Intn(len(webGLProfiles))]
+	return &Fingerprint{
+		CanvasSeed:    rng.Uint32(),
+		AudioSeed:     rng.Uint32(),
+		WebGLVendor:   profile[0],
+		WebGLRenderer: profile[1],
+		CreatedAt:     time.Now().UTC(),
+	}
+}
+
+// LoadFingerprint returns the fingerprint stored for account in path, creating
+// and saving a new one if the account has none yet
+func LoadFingerprint(path, account string) (*Fingerprint, error) {
+	fingerprints := make(map[string]*Fingerprint)
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read fingerprints: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &fingerprints); err != nil {
+			return nil, fmt.Errorf("failed to parse fingerprints %s: %w", path, err)
+		}
+	}
+
+	key := strings.ToLower(strings.TrimSpace(account))
+	if fingerprint, ok := fingerprints[key]; ok {
+		return fingerprint, nil
+	}
+
+	fingerprint := NewFingerprint(rand.New(rand.NewSource(time.Now().UnixNano())))
+	fingerprints[key] = fingerprint
+
+	data, err = json.MarshalIndent(fingerprints, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create fingerprint directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to save fingerprints: %w", err)
+	}
+
+	return fingerprint, nil
+}
+
+// SetFingerprint sets the fingerprint to present; without one a random
+// fingerprint is used for the lifetime of the manager
+func (s *StealthManager) SetFingerprint(fingerprint *Fingerprint) {
+	s.fingerprint = fingerprint
+}
+
+// injectFingerprint installs the canvas, WebGL and audio patches on every
+// document the page loads from now on
+func (s *StealthManager) injectFingerprint(page *rod.Page) error {
+	if s.fingerprint == nil {
+		s.fingerprint = NewFingerprint(s.rng)
+	}
+
+	options, err := json.Marshal(map[string]interface{}{
+		"canvas":        s.config.Fingerprint.SpoofCanvas,
+		"webgl":         s.config.Fingerprint.SpoofWebGL,
+		"audio":         s.config.Fingerprint.SpoofAudio,
+		"canvasSeed":    s.fingerprint.CanvasSeed,
+		"audioSeed":     s.fingerprint.AudioSeed,
+		"webglVendor":   s.fingerprint.WebGLVendor,
+		"webglRenderer": s.fingerprint.WebGLRenderer,
+	})
+	if err != nil {
+		return err
+	}
+
+	script := "(" + strings.TrimSpace(fingerprintScript) + ")(" + string(options) + ");"
+	if _, err := page.EvalOnNewDocument(script); err != nil {
+		return fmt.Errorf("failed to inject fingerprint script: %w", err)
+	}
+
+	return nil
+}
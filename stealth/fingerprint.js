@@ -0,0 +1,89 @@
+function (options) {
+  // Deterministic PRNG so the same seed always produces the same noise
+  const prng = (seed) => () => {
+    seed |= 0;
+    seed = (seed + 0x6d2b79f5) | 0;
+    let t = Math.imul(seed ^ (seed >>> 15), 1 | seed);
+    t = (t + Math.imul(t ^ (t >>> 7), 61 | t)) ^ t;
+    return ((t ^ (t >>> 14)) >>> 0) / 4294967296;
+  };
+
+  // Replace a method while keeping its native-looking toString
+  const patch = (proto, name, make) => {
+    if (!proto || typeof proto[name] !== 'function') return;
+    const original = proto[name];
+    const replacement = make(original);
+    Object.defineProperty(replacement, 'toString', { value: () => original.toString() });
+    Object.defineProperty(proto, name, { value: replacement, writable: true, configurable: true });
+  };
+
+  if (options.canvas) {
+    const noisy = (canvas) => {
+      const ctx = canvas.getContext('2d');
+      if (!ctx || canvas.width === 0 || canvas.height === 0) return canvas;
+      const copy = document.createElement('canvas');
+      copy.width = canvas.width;
+      copy.height = canvas.height;
+      const copyCtx = copy.getContext('2d');
+      copyCtx.drawImage(canvas, 0, 0);
+      const image = originalGetImageData.call(copyCtx, 0, 0, copy.width, copy.height);
+      const rand = prng(options.canvasSeed);
+      for (let i = 0; i < image.data.length; i += 4 * (1 + Math.floor(rand() * 64))) {
+        image.data[i] = image.data[i] ^ 1;
+      }
+      copyCtx.putImageData(image, 0, 0);
+      return copy;
+    };
+
+    const originalGetImageData = CanvasRenderingContext2D.prototype.getImageData;
+    patch(HTMLCanvasElement.prototype, 'toDataURL', (original) => function (...args) {
+      return original.apply(noisy(this), args);
+    });
+    patch(HTMLCanvasElement.prototype, 'toBlob', (original) => function (...args) {
+      return original.apply(noisy(this), args);
+    });
+    patch(CanvasRenderingContext2D.prototype, 'getImageData', (original) => function (...args) {
+      const image = original.apply(this, args);
+      const rand = prng(options.canvasSeed);
+      for (let i = 0; i < image.data.length; i += 4 * (1 + Math.floor(rand() * 64))) {
+        image.data[i] = image.data[i] ^ 1;
+      }
+      return image;
+    });
+  }
+
+  if (options.webgl) {
+    const UNMASKED_VENDOR = 0x9245;
+    const UNMASKED_RENDERER = 0x9246;
+    const spoof = (original) => function (parameter) {
+      if (parameter === UNMASKED_VENDOR) return options.webglVendor;
+      if (parameter === UNMASKED_RENDERER) return options.webglRenderer;
+      return original.call(this, parameter);
+    };
+    patch(window.WebGLRenderingContext && WebGLRenderingContext.prototype, 'getParameter', spoof);
+    patch(window.WebGL2RenderingContext && WebGL2RenderingContext.prototype, 'getParameter', spoof);
+  }
+
+  if (options.audio) {
+    const noised = new WeakSet();
+    patch(window.AudioBuffer && AudioBuffer.prototype, 'getChannelData', (original) => function (...args) {
+      const data = original.apply(this, args);
+      // Only perturb each buffer once so repeated reads stay identical
+      if (!noised.has(data)) {
+        noised.add(data);
+        const rand = prng(options.audioSeed);
+        for (let i = 0; i < data.length; i += 100) {
+          data[i] += (rand() - 0.5) * 1e-7;
+        }
+      }
+      return data;
+    });
+    patch(window.AnalyserNode && AnalyserNode.prototype, 'getFloatFrequencyData', (original) => function (array) {
+      original.call(this, array);
+      const rand = prng(options.audioSeed);
+      for (let i = 0; i < array.length; i++) {
+        array[i] += (rand() - 0.5) * 1e-4;
+      }
+    });
+  }
+}
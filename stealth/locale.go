@@ -0,0 +1,166 @@
+package stealth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// geoIPURL returns the country and timezone of the requesting IP
+const geoIPURL = "http://ip-api.com/json/?fields=status,message,countryCode,timezone"
+
+// LocaleConfig is the timezone and language the browser presents
+type LocaleConfig struct {
+	Timezone string // IANA timezone, e.g. Europe/Berlin
+	Locale   string // BCP 47 language tag, e.g. de-DE
+}
+
+// regions maps ISO country codes to a representative locale
+var regions = map[string]LocaleConfig{
+	"AE": {"Asia/Dubai", "en-AE"},
+	"AU": {"Australia/Sydney", "en-AU"},
+	"BE": {"Europe/Brussels", "nl-BE"},
+	"BR": {"America/Sao_Paulo", "pt-BR"},
+	"CA": {"America/Toronto", "en-CA"},
+	"CH": {"Europe/Zurich", "de-CH"},
+	"DE": {"Europe/Berlin", "de-DE"},
+	"DK": {"Europe/Copenhagen", "da-DK"},
+	"ES": {"Europe/Madrid", "es-ES"},
+	"FI": {"Europe/Helsinki", "fi-FI"},
+	"FR": {"Europe/Paris", "fr-FR"},
+	"GB": {"Europe/London", "en-GB"},
+	"IE": {"Europe/Dublin", "en-IE"},
+	"IN": {"Asia/Kolkata", "en-IN"},
+	"IT": {"Europe/Rome", "it-IT"},
+	"JP": {"Asia/Tokyo", "ja-JP"},
+	"MX": {"America/Mexico_City", "es-MX"},
+	"NL": {"Europe/Amsterdam", "nl-NL"},
+	"NO": {"Europe/Oslo", "nb-NO"},
+	"PL": {"Europe/Warsaw", "pl-PL"},
+	"PT": {"Europe/Lisbon", "pt-PT"},
+	"SE": {"Europe/Stockholm", "sv-SE"},
+	"SG": {"Asia/Singapore", "en-SG"},
+	"US": {"America/New_York", "en-US"},
+}
+
+// RegionLocale returns the locale for an ISO country code
+func RegionLocale(country string) (LocaleConfig, bool) {
+	locale, ok := regions[strings.ToUpper(strings.TrimSpace(country))]
+	return locale, ok
+}
+
+// DetectLocale looks up the country and timezone of the public IP, going
+// through proxyURL if set, so the browser can match where its traffic exits
+func DetectLocale(ctx context.Context, proxyURL string) (LocaleConfig, error) {
+	transport := &http.Transport{}
+	if proxyURL != "" {
+		parsed, err := url.Parse(proxyURL)
+		if err != nil {
+			return LocaleConfig{}, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(parsed)
+	}
+	client := &http.Client{Transport: transport, Timeout: 10 * time.Second}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, geoIPURL, nil)
+	if err != nil {
+		return LocaleConfig{}, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return LocaleConfig{}, fmt.Errorf("geo-IP lookup failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Status      string `json:"status"`
+		Message     string `json:"message"`
+		CountryCode string `json:"countryCode"`
+		Timezone    string `json:"timezone"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return LocaleConfig{}, fmt.Errorf("failed to decode geo-IP response: %w", err)
+	}
+	if result.Status != "success" {
+		return LocaleConfig{}, fmt.Errorf("geo-IP lookup failed: %s", result.Message)
+	}
+
+	locale, ok := RegionLocale(result.CountryCode)
+	if !ok {
+		return LocaleConfig{}, fmt.Errorf("no locale known for country %s", result.CountryCode)
+	}
+	// The lookup knows the exact timezone, which beats the country default
+	if result.Timezone != "" {
+		locale.Timezone = result.Timezone
+	}
+	return locale, nil
+}
+
+// applyLocale overrides the timezone, Intl locale, Accept-Language header and
+// navigator.language(s) of the page
+func (s *StealthManager) applyLocale(page *rod.Page) error {
+	locale := s.config.Locale
+
+	if locale.Timezone != "" {
+		if err := (proto.EmulationSetTimezoneOverride{TimezoneID: locale.Timezone}).Call(page); err != nil {
+			return fmt.Errorf("failed to set timezone: %w", err)
+		}
+	}
+
+	if locale.Locale == "" {
+		return nil
+	}
+
+	if err := (proto.EmulationSetLocaleOverride{Locale: strings.ReplaceAll(locale.Locale, "-", "_")}).Call(page); err != nil {
+		return fmt.Errorf("failed to set locale: %w", err)
+	}
+
+	// The user agent override also sets Accept-Language and navigator.languages,
+	// so it must repeat whichever user agent is already in effect
+	userAgent := s.userAgent
+	if userAgent == "" {
+		version, err := proto.BrowserGetVersion{}.Call(page)
+		if err != nil {
+			return fmt.Errorf("failed to get user agent: %w", err)
+		}
+		userAgent = version.UserAgent
+	}
+	if err := page.SetUserAgent(&proto.NetworkSetUserAgentOverride{
+		UserAgent:      userAgent,
+		AcceptLanguage: acceptLanguage(locale.Locale),
+	}); err != nil {
+		return fmt.Errorf("failed to set accept language: %w", err)
+	}
+
+	return nil
+}
+
+// acceptLanguage builds an Accept-Language value preferring locale, then its
+// base language, then English
+func acceptLanguage(locale string) string {
+	languages := []string{locale}
+	base := strings.SplitN(locale, "-", 2)[0]
+	if base != locale {
+		languages = append(languages, base)
+	}
+	if base != "en" {
+		languages = append(languages, "en")
+	}
+
+	parts := make([]string, len(languages))
+	for i, language := range languages {
+		if i == 0 {
+			parts[i] = language
+		} else {
+			parts[i] = fmt.Sprintf("%s;q=%.1f", language, 1.0-0.1*float64(i))
+		}
+	}
+	return strings.Join(parts, ",")
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/go-rod/rod"
 	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/pause"
 )
 
 type MouseController struct {
@@ -29,14 +31,15 @@ func (mc *MouseController) IntelligentClick(page *rod.Page, selector string) err
 		return fmt.Errorf("element not found: %w", err)
 	}
 	
-	time.Sleep(time.Duration(mc.rng.Float64()*500+200) * time.Millisecond)
+	if err := pause.Page(page, time.Duration(mc.rng.Float64()*500+200)*time.Millisecond); err != nil {
+		return err
+	}
 	
 	if err := element.Click("left", 1); err != nil {
 		return fmt.Errorf("failed to click element: %w", err)
 	}
 	
-	time.Sleep(time.Duration(mc.rng.Float64()*200+100) * time.Millisecond)
-	return nil
+	return pause.Page(page, time.Duration(mc.rng.Float64()*200+100)*time.Millisecond)
 }
 
 func (mc *MouseController) IntelligentScroll(page *rod.Page, direction string, amount int) error {
@@ -44,16 +47,20 @@ func (mc *MouseController) IntelligentScroll(page *rod.Page, direction string, a
 	scrollsNeeded := amount / chunkSize
 	
 	for i := 0; i < scrollsNeeded; i++ {
-		time.Sleep(time.Duration(mc.rng.Float64()*200+100) * time.Millisecond)
+		if err := pause.Page(page, time.Duration(mc.rng.Float64()*200+100)*time.Millisecond); err != nil {
+			return err
+		}
 		
 		switch direction {
 		case "down":
-			page.Scroll(-float64(chunkSize), 0)
+			page.Mouse.Scroll(0, -float64(chunkSize), 0)
 		case "up":
-			page.Scroll(float64(chunkSize), 0)
+			page.Mouse.Scroll(0, float64(chunkSize), 0)
 		}
 		
-		time.Sleep(time.Duration(mc.rng.Float64()*100+50) * time.Millisecond)
+		if err := pause.Page(page, time.Duration(mc.rng.Float64()*100+50)*time.Millisecond); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -68,6 +75,5 @@ func (mc *MouseController) IntelligentHover(page *rod.Page, selector string, dur
 		return fmt.Errorf("failed to hover element: %w", err)
 	}
 	
-	time.Sleep(duration)
-	return nil
+	return pause.Page(page, duration)
 }
@@ -0,0 +1,136 @@
+package stealth
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/pause"
+	"linkedin-automation/selectors"
+)
+
+// ReadingConfig controls how long a profile is read before connecting to or
+// messaging its owner
+type ReadingConfig struct {
+	WordsPerMinute int // Skimming speed; 0 pauses a random delay instead
+	MinDwell       time.Duration
+	MaxDwell       time.Duration
+}
+
+// profileSections are the parts of a profile that are read, in page order
+var profileSections = []selectors.Key{
+	selectors.ProfileHeadline,
+	selectors.ProfileAbout,
+	selectors.ProfileExperience,
+}
+
+// ReadProfile stays on the open profile for as long as a person would need to
+// skim its headline, about and experience sections, scrolling down to each
+// and pausing there in proportion to its length, then scrolls back up to the
+// top card. Long profiles hold the reader longer, within min_dwell and
+// max_dwell.
+func (s *StealthManager) ReadProfile(page *rod.Page) error {
+	if !s.config.Enabled || s.config.Reading.WordsPerMinute <= 0 {
+		return pause.Page(page, s.RandomDelay())
+	}
+
+	var sections []*rod.Element
+	var words []int
+	total := 0
+	for _, key := range profileSections {
+		section, _ := selectors.Find(page, key)
+		if section == nil {
+			continue
+		}
+		text, err := section.Text()
+		if err != nil {
+			continue
+		}
+		n := len(strings.Fields(text))
+		sections = append(sections, section)
+		words = append(words, n)
+		total += n
+	}
+
+	dwell := s.readingTime(total)
+	s.logger.WithFields(logrus.Fields{"words": total, "dwell": dwell}).Debug("Reading profile")
+	if total == 0 {
+		// None of the sections were found; the page is still looked over
+		if err := s.skim(page, dwell); err != nil {
+			return err
+		}
+	}
+	for i, section := range sections {
+		if words[i] == 0 {
+			continue
+		}
+		s.scrollTo(page, section)
+		if err := s.skim(page, dwell*time.Duration(words[i])/time.Duration(total)); err != nil {
+			return err
+		}
+	}
+
+	// The top card holds the Connect and Message buttons
+	if scrollY, err := page.Eval("() => window.scrollY"); err == nil && scrollY.Value.Int() > 0 {
+		if err := s.HumanLikeScroll(page, -scrollY.Value.Int()); err != nil {
+			s.logger.WithError(err).Debug("Failed to scroll back to the top card")
+		}
+	}
+	return pause.Page(page, s.RandomDelay())
+}
+
+// readingTime returns how long skimming words takes, varied by up to 30% and
+// kept within min_dwell and max_dwell
+func (s *StealthManager) readingTime(words int) time.Duration {
+	reading := s.config.Reading
+	d := time.Duration(float64(words) / float64(reading.WordsPerMinute) * float64(time.Minute) * (0.7 + 0.6*s.rng.Float64()))
+	if d < reading.MinDwell {
+		d = reading.MinDwell + time.Duration(s.rng.Int63n(int64(reading.MinDwell/2)+1))
+	}
+	if reading.MaxDwell > 0 && d > reading.MaxDwell {
+		d = reading.MaxDwell
+	}
+	return d
+}
+
+// scrollTo scrolls until the top of section is a little below the top of the
+// viewport
+func (s *StealthManager) scrollTo(page *rod.Page, section *rod.Element) {
+	top, err := section.Eval("() => this.getBoundingClientRect().top - window.innerHeight / 5")
+	if err != nil {
+		return
+	}
+	if amount := top.Value.Int(); amount > 0 {
+		if err := s.HumanLikeScroll(page, amount); err != nil {
+			s.logger.WithError(err).Debug("Failed to scroll to profile section")
+		}
+	}
+}
+
+// skim pauses for d in short stretches, moving down the text a little and
+// shifting the mouse between them
+func (s *StealthManager) skim(page *rod.Page, d time.Duration) error {
+	deadline := time.Now().Add(d)
+	for {
+		step := time.Until(deadline)
+		if step <= 0 {
+			return nil
+		}
+		step = min(step, 1500*time.Millisecond+time.Duration(s.rng.Int63n(int64(2500*time.Millisecond))))
+		if err := pause.Page(page, step); err != nil {
+			return err
+		}
+		if time.Until(deadline) <= 0 {
+			return nil
+		}
+
+		if err := s.HumanLikeScroll(page, 60+s.rng.Intn(160)); err != nil {
+			s.logger.WithError(err).Debug("Failed to scroll while reading")
+		}
+		if err := s.AddIdleMovement(page); err != nil {
+			s.logger.WithError(err).Debug("Failed to add idle movement")
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package stealth
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+
+	"github.com/go-rod/rod"
+)
+
+//go:embed selftest
+var selfTestFiles embed.FS
+
+// Check is one automation indicator observed in the browser
+type Check struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Exposed bool   `json:"exposed"` // The value gives automation away
+}
+
+// SelfTestReport lists the indicators observed on one page
+type SelfTestReport struct {
+	URL    string
+	Checks []Check
+}
+
+// Exposed returns the checks that give automation away
+func (r *SelfTestReport) Exposed() []Check {
+	exposed := make([]Check, 0)
+	for _, check := range r.Checks {
+		if check.Exposed {
+			exposed = append(exposed, check)
+		}
+	}
+	return exposed
+}
+
+// SelfTestServer serves the bundled fingerprint checker on a local port
+type SelfTestServer struct {
+	URL      string
+	listener net.Listener
+}
+
+// StartSelfTestServer serves the bundled fingerprint checker on a random loopback port
+func StartSelfTestServer() (*SelfTestServer, error) {
+	files, err := fs.Sub(selfTestFiles, "selftest")
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start self-test server: %w", err)
+	}
+
+	go http.Serve(listener, http.FileServer(http.FS(files)))
+
+	return &SelfTestServer{
+		URL:      "http://" + listener.Addr().String() + "/",
+		listener: listener,
+	}, nil
+}
+
+// Close stops the server
+func (s *SelfTestServer) Close() error {
+	return s.listener.Close()
+}
+
+// RunSelfTest opens pageURL and reports which automation indicators the page can see
+func RunSelfTest(page *rod.Page, pageURL string) (*SelfTestReport, error) {
+	if err := page.Navigate(pageURL); err != nil {
+		return nil, fmt.Errorf("failed to navigate to %s: %w", pageURL, err)
+	}
+	if err := page.WaitLoad(); err != nil {
+		return nil, fmt.Errorf("failed to wait for page load: %w", err)
+	}
+
+	probe, err := selfTestFiles.ReadFile("selftest/probe.js")
+	if err != nil {
+		return nil, err
+	}
+
+	// The probe is injected so third-party test pages can be checked the same way
+	result, err := page.Eval("async () => {\n" + string(probe) + "\nreturn await stealthProbe();\n}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to run fingerprint probe: %w", err)
+	}
+
+	report := &SelfTestReport{URL: pageURL}
+	if err := result.Value.Unmarshal(&report.Checks); err != nil {
+		return nil, fmt.Errorf("failed to decode probe results: %w", err)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,59 @@
+// Collects the browser properties bot-detection scripts commonly inspect.
+// Each check reports the observed value and whether it gives automation away.
+async function stealthProbe() {
+  const checks = [];
+  const add = (name, value, exposed) => checks.push({ name: name, value: String(value), exposed: Boolean(exposed) });
+
+  add('navigator.webdriver', navigator.webdriver, navigator.webdriver === true);
+  add('user agent', navigator.userAgent, /HeadlessChrome/.test(navigator.userAgent));
+
+  const brands = navigator.userAgentData ? navigator.userAgentData.brands.map(b => b.brand).join(', ') : 'unavailable';
+  add('user agent client hints', brands, /HeadlessChrome/.test(brands));
+
+  add('plugins length', navigator.plugins.length, navigator.plugins.length === 0);
+  add('mimeTypes length', navigator.mimeTypes.length, navigator.mimeTypes.length === 0);
+  add('languages', (navigator.languages || []).join(', '), !navigator.languages || navigator.languages.length === 0);
+
+  // Informational: compare against the account's region
+  add('language', navigator.language, false);
+  add('timezone', Intl.DateTimeFormat().resolvedOptions().timeZone, false);
+
+  const isChrome = /Chrome/.test(navigator.userAgent);
+  add('window.chrome', typeof window.chrome, isChrome && !window.chrome);
+
+  let permission = 'unavailable';
+  let inconsistent = false;
+  try {
+    const status = await navigator.permissions.query({ name: 'notifications' });
+    permission = Notification.permission + ' / ' + status.state;
+    // Headless Chrome reports "denied" while the permissions API says "prompt"
+    inconsistent = Notification.permission === 'denied' && status.state === 'prompt';
+  } catch (e) {
+    permission = 'error: ' + e.message;
+  }
+  add('notification permission', permission, inconsistent);
+
+  let vendor = 'unavailable';
+  let renderer = 'unavailable';
+  try {
+    const gl = document.createElement('canvas').getContext('webgl');
+    if (gl) {
+      const info = gl.getExtension('WEBGL_debug_renderer_info');
+      vendor = info ? gl.getParameter(info.UNMASKED_VENDOR_WEBGL) : gl.getParameter(gl.VENDOR);
+      renderer = info ? gl.getParameter(info.UNMASKED_RENDERER_WEBGL) : gl.getParameter(gl.RENDERER);
+    }
+  } catch (e) {
+    renderer = 'error: ' + e.message;
+  }
+  const softwareGL = /SwiftShader|llvmpipe|Software/i.test(renderer);
+  add('WebGL vendor', vendor, vendor === 'unavailable');
+  add('WebGL renderer', renderer, renderer === 'unavailable' || softwareGL);
+
+  add('window outer size', window.outerWidth + 'x' + window.outerHeight, window.outerWidth === 0 || window.outerHeight === 0);
+  add('hardwareConcurrency', navigator.hardwareConcurrency, !navigator.hardwareConcurrency);
+
+  const driverKeys = Object.keys(window).concat(Object.keys(document)).filter(k => /^\$?cdc_|^__(webdriver|selenium|driver)/.test(k));
+  add('driver globals', driverKeys.length ? driverKeys.join(', ') : 'none', driverKeys.length > 0);
+
+  return checks;
+}
@@ -4,11 +4,16 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/pause"
 )
 
 // StealthManager implements anti-bot detection techniques
@@ -16,6 +21,8 @@ type StealthManager struct {
 	config          StealthConfig
 	logger          *logrus.Logger
 	rng             *rand.Rand
+	fingerprint     *Fingerprint
+	userAgent       string // User agent override in effect, if any
 }
 
 // StealthConfig contains stealth configuration
@@ -27,6 +34,9 @@ type StealthConfig struct {
 	Scrolling         ScrollingConfig
 	Schedule          ScheduleConfig
 	Fingerprint       FingerprintConfig
+	Locale            LocaleConfig
+	WarmUp            WarmUpConfig
+	Reading           ReadingConfig
 }
 
 // MouseMovementConfig for realistic mouse behavior
@@ -58,6 +68,9 @@ type TypingConfig struct {
 	CorrectionDelay   time.Duration
 	MinSpeed          time.Duration
 	MaxSpeed          time.Duration
+	MaxComposeTime    time.Duration // Typing stops and the rest is inserted at once after this long; 0 never stops
+	ReviewPause       time.Duration // Pause before sending, per 100 characters of the message
+	ReadDelay         time.Duration // Upper bound of the wait before opening a conversation with new activity
 }
 
 // ScrollingConfig for realistic scrolling behavior
@@ -89,6 +102,9 @@ type FingerprintConfig struct {
 	MinViewportHeight int
 	MaxViewportHeight int
 	UserAgents        []string
+	SpoofCanvas       bool
+	SpoofWebGL        bool
+	SpoofAudio        bool
 }
 
 // Point represents a 2D point
@@ -131,6 +147,12 @@ func (s *StealthManager) ApplyStealth(page *rod.Page) error {
 		stealthErrors = append(stealthErrors, "automation indicators")
 	}
 
+	// Match timezone and language to the account's region (optional)
+	if err := s.applyLocale(page); err != nil {
+		s.logger.WithError(err).Warn("Failed to apply locale")
+		stealthErrors = append(stealthErrors, "locale")
+	}
+
 	// Set random viewport (optional)
 	if s.config.Fingerprint.RandomViewport {
 		if err := s.setRandomViewport(page); err != nil {
@@ -157,7 +179,9 @@ func (s *StealthManager) HumanLikeMouseMove(page *rod.Page, fromX, fromY, toX, t
 	}).Debug("Starting human-like mouse movement")
 
 	// Simple delay to simulate human movement
-	time.Sleep(s.RandomDelay())
+	if err := pause.Page(page, s.RandomDelay()); err != nil {
+		return err
+	}
 
 	s.logger.Debug("Human-like mouse movement completed")
 	return nil
@@ -179,18 +203,109 @@ func (s *StealthManager) RandomDelay() time.Duration {
 	return delay
 }
 
-// HumanLikeType simulates human typing
+// HumanLikeType types text into the focused element one character at a time,
+// with variable keystroke delays and occasional corrected typos. Each
+// keystroke fires an input event, so the recipient sees the typing indicator
+// for as long as a person would take to write the text.
 func (s *StealthManager) HumanLikeType(page *rod.Page, text string) error {
 	s.logger.WithField("text_length", len(text)).Debug("Starting human-like typing")
 
 	// Add delay before typing
-	time.Sleep(s.RandomDelay())
+	if err := pause.Page(page, s.RandomDelay()); err != nil {
+		return err
+	}
+
+	if !s.config.Enabled {
+		return page.InsertText(text)
+	}
+
+	start := time.Now()
+	runes := []rune(text)
+	for i, char := range runes {
+		// Paste the rest of very long texts rather than typing for minutes
+		if limit := s.config.Typing.MaxComposeTime; limit > 0 && time.Since(start) > limit {
+			s.logger.WithField("remaining", len(runes)-i).Debug("Compose time limit reached, inserting the rest")
+			if err := page.InsertText(string(runes[i:])); err != nil {
+				return fmt.Errorf("failed to insert text: %w", err)
+			}
+			break
+		}
+
+		if unicode.IsLetter(char) && s.rng.Float64() < s.config.Typing.TypoRate {
+			if err := s.typeTypo(page, char); err != nil {
+				return err
+			}
+		}
+
+		if err := page.InsertText(string(char)); err != nil {
+			return fmt.Errorf("failed to type text: %w", err)
+		}
+		if err := pause.Page(page, s.keystrokeDelay(char)); err != nil {
+			return err
+		}
+	}
 
-	// For now, just simulate typing with delay - actual typing would be handled by caller
-	s.logger.Debug("Human-like typing completed")
+	s.logger.WithField("duration", time.Since(start)).Debug("Human-like typing completed")
 	return nil
 }
 
+// typeTypo types a wrong letter next to char and deletes it again
+func (s *StealthManager) typeTypo(page *rod.Page, char rune) error {
+	wrong := 'a' + rune(s.rng.Intn(26))
+	if unicode.IsUpper(char) {
+		wrong = unicode.ToUpper(wrong)
+	}
+	if err := page.InsertText(string(wrong)); err != nil {
+		return fmt.Errorf("failed to type text: %w", err)
+	}
+	// The typo is corrected even if the run is cancelled, so it is never sent
+	pause.Page(page, s.keystrokeDelay(wrong)+s.config.Typing.CorrectionDelay)
+	if err := page.Keyboard.Type(input.Backspace); err != nil {
+		return fmt.Errorf("failed to correct typo: %w", err)
+	}
+	return pause.Page(page, s.keystrokeDelay(char))
+}
+
+// keystrokeDelay returns the pause after typing char. Word and sentence
+// boundaries get longer pauses, as people hesitate there.
+func (s *StealthManager) keystrokeDelay(char rune) time.Duration {
+	minSpeed, maxSpeed := s.config.Typing.MinSpeed, s.config.Typing.MaxSpeed
+	delay := (minSpeed + maxSpeed) / 2
+	if s.config.Typing.VariableSpeed && maxSpeed > minSpeed {
+		delay = minSpeed + time.Duration(s.rng.Int63n(int64(maxSpeed-minSpeed)))
+	}
+
+	switch {
+	case strings.ContainsRune(".!?\n", char):
+		delay += time.Duration(s.rng.Float64() * float64(s.config.Timing.ThinkTime))
+	case char == ' ' && s.rng.Float64() < 0.1:
+		delay += delay * 2
+	}
+	return delay
+}
+
+// ReviewDelay returns how long to pause after composing text before sending
+// it, as a person re-reads a draft. It grows with the length of the text.
+func (s *StealthManager) ReviewDelay(text string) time.Duration {
+	if !s.config.Enabled || s.config.Typing.ReviewPause <= 0 {
+		return 0
+	}
+	chars := float64(len([]rune(text)))
+	delay := time.Duration(float64(s.config.Typing.ReviewPause) * (0.5 + chars/100) * (0.7 + 0.6*s.rng.Float64()))
+	s.logger.WithField("delay", delay).Debug("Reviewing draft before sending")
+	return delay
+}
+
+// ReadDelay returns how long to wait before opening a conversation with new
+// activity, so read receipts do not appear the moment a message arrives.
+// Returns 0 when read_delay is not configured.
+func (s *StealthManager) ReadDelay() time.Duration {
+	if !s.config.Enabled || s.config.Typing.ReadDelay <= 0 {
+		return 0
+	}
+	return s.config.Typing.ReadDelay/2 + time.Duration(s.rng.Int63n(int64(s.config.Typing.ReadDelay/2)+1))
+}
+
 // HumanLikeScroll implements realistic scrolling behavior
 func (s *StealthManager) HumanLikeScroll(page *rod.Page, scrollAmount int) error {
 	s.logger.WithField("amount", scrollAmount).Debug("Starting human-like scrolling")
@@ -229,7 +344,9 @@ func (s *StealthManager) HumanLikeScroll(page *rod.Page, scrollAmount int) error
 		}
 
 		remaining -= scrollSpeed
-		time.Sleep(s.config.Timing.ScrollDelay)
+		if err := pause.Page(page, s.config.Timing.ScrollDelay); err != nil {
+			return err
+		}
 	}
 
 	// Add scroll-back behavior
@@ -238,7 +355,9 @@ func (s *StealthManager) HumanLikeScroll(page *rod.Page, scrollAmount int) error
 		if err := page.Mouse.Scroll(0, float64(-scrollBack), 0); err != nil {
 			return fmt.Errorf("failed to scroll back: %w", err)
 		}
-		time.Sleep(s.config.Timing.ScrollDelay)
+		if err := pause.Page(page, s.config.Timing.ScrollDelay); err != nil {
+			return err
+		}
 		if err := page.Mouse.Scroll(0, float64(scrollBack), 0); err != nil {
 			return fmt.Errorf("failed to scroll forward: %w", err)
 		}
@@ -375,9 +494,18 @@ func (s *StealthManager) applyFingerprintMasking(page *rod.Page) error {
 		}); err != nil {
 			return fmt.Errorf("failed to set user agent: %w", err)
 		}
+		s.userAgent = userAgent
 		s.logger.WithField("user_agent", userAgent).Debug("Set random user agent")
 	}
 
+	// Canvas, WebGL and audio fingerprints are patched on every new document
+	if s.config.Fingerprint.SpoofCanvas || s.config.Fingerprint.SpoofWebGL || s.config.Fingerprint.SpoofAudio {
+		if err := s.injectFingerprint(page); err != nil {
+			return err
+		}
+		s.logger.WithField("webgl_renderer", s.fingerprint.WebGLRenderer).Debug("Injected fingerprint spoofing")
+	}
+
 	return nil
 }
 
@@ -432,25 +560,31 @@ func (s *StealthManager) setRandomViewport(page *rod.Page) error {
 // IntelligentClick performs a realistic click with human-like behavior
 func (s *StealthManager) IntelligentClick(page *rod.Page, selector string) error {
 	// Simple delay before click
-	time.Sleep(s.RandomDelay())
+	if err := pause.Page(page, s.RandomDelay()); err != nil {
+		return err
+	}
 	
 	element, err := page.Element(selector)
 	if err != nil {
 		return fmt.Errorf("element not found: %w", err)
 	}
 	
-	return element.Click("left", 1)
+	return s.HumanClick(page, element)
 }
 
 // IntelligentScroll performs realistic scrolling behavior
 func (s *StealthManager) IntelligentScroll(page *rod.Page, direction string, amount int) error {
-	time.Sleep(s.RandomDelay())
+	if err := pause.Page(page, s.RandomDelay()); err != nil {
+		return err
+	}
 	
 	chunkSize := 3
 	scrollsNeeded := amount / chunkSize
 	
 	for i := 0; i < scrollsNeeded; i++ {
-		time.Sleep(time.Duration(100+s.rng.Intn(200)) * time.Millisecond)
+		if err := pause.Page(page, time.Duration(100+s.rng.Intn(200))*time.Millisecond); err != nil {
+			return err
+		}
 		
 		switch direction {
 		case "down":
@@ -473,6 +607,5 @@ func (s *StealthManager) IntelligentHover(page *rod.Page, selector string, durat
 		return fmt.Errorf("failed to hover element: %w", err)
 	}
 	
-	time.Sleep(duration)
-	return nil
+	return pause.Page(page, duration)
 }
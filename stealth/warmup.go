@@ -0,0 +1,133 @@
+package stealth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+
+	"linkedin-automation/pause"
+	"linkedin-automation/selectors"
+)
+
+const feedURL = "https://www.linkedin.com/feed/"
+
+// WarmUpConfig controls the browsing done before a batch starts
+type WarmUpConfig struct {
+	Probability             float64 // Chance of warming up before a batch
+	MinDuration             time.Duration
+	MaxDuration             time.Duration
+	NotificationProbability float64 // Chance of also opening the notifications page
+}
+
+// ShouldWarmUp rolls the configured warm-up probability; it is always false
+// with stealth disabled
+func (s *StealthManager) ShouldWarmUp() bool {
+	return s.config.Enabled && s.config.WarmUp.Probability > 0 && s.rng.Float64() < s.config.WarmUp.Probability
+}
+
+// WarmUpSession browses the feed for a random period before any actions,
+// scrolling, pausing on posts and sometimes checking notifications, so a batch
+// does not start the moment the session opens
+func (s *StealthManager) WarmUpSession(page *rod.Page) error {
+	duration := s.warmUpDuration()
+	s.logger.WithField("duration", duration).Info("Warming up session")
+
+	if err := s.openFeed(page); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(duration)
+	for time.Now().Before(deadline) {
+		switch roll := s.rng.Float64(); {
+		case roll < 0.5:
+			if err := s.HumanLikeScroll(page, 300+s.rng.Intn(600)); err != nil {
+				return err
+			}
+		case roll < 0.8:
+			s.hoverRandomPost(page)
+		default:
+			if err := s.AddIdleMovement(page); err != nil {
+				s.logger.WithError(err).Debug("Failed to add idle movement")
+			}
+		}
+
+		// Reading time between actions
+		if err := pause.Page(page, s.RandomDelay()+time.Duration(s.rng.Int63n(int64(3*time.Second)))); err != nil {
+			return err
+		}
+	}
+
+	if s.rng.Float64() < s.config.WarmUp.NotificationProbability {
+		s.openNotifications(page)
+	}
+
+	s.logger.Info("Session warm-up completed")
+	return nil
+}
+
+func (s *StealthManager) warmUpDuration() time.Duration {
+	minDuration, maxDuration := s.config.WarmUp.MinDuration, s.config.WarmUp.MaxDuration
+	if maxDuration <= minDuration {
+		return minDuration
+	}
+	return minDuration + time.Duration(s.rng.Int63n(int64(maxDuration-minDuration)))
+}
+
+func (s *StealthManager) openFeed(page *rod.Page) error {
+	if err := page.Navigate(feedURL); err != nil {
+		return fmt.Errorf("failed to open feed: %w", err)
+	}
+	if err := page.WaitLoad(); err != nil {
+		return fmt.Errorf("failed to wait for feed: %w", err)
+	}
+
+	info, err := page.Info()
+	if err == nil && (strings.Contains(info.URL, "/login") || strings.Contains(info.URL, "/authwall")) {
+		return fmt.Errorf("redirected to login page - authentication required")
+	}
+
+	return pause.Page(page, s.RandomDelay())
+}
+
+// hoverRandomPost rests the mouse on one of the loaded posts as if reading it
+func (s *StealthManager) hoverRandomPost(page *rod.Page) {
+	posts := selectors.FindAll(page, selectors.Post)
+	if len(posts) == 0 {
+		return
+	}
+
+	post := posts[s.rng.Intn(len(posts))]
+	if visible, err := post.Visible(); err != nil || !visible {
+		return
+	}
+	if err := post.Hover(); err != nil {
+		s.logger.WithError(err).Debug("Failed to hover post")
+		return
+	}
+	pause.Page(page, time.Second+time.Duration(s.rng.Int63n(int64(3*time.Second))))
+}
+
+// openNotifications clicks through to the notifications page and skims it
+func (s *StealthManager) openNotifications(page *rod.Page) {
+	link, _ := selectors.Find(page, selectors.NavNotifications)
+	if link == nil {
+		return
+	}
+	if err := link.Click("left", 1); err != nil {
+		s.logger.WithError(err).Debug("Failed to open notifications")
+		return
+	}
+	if err := page.WaitLoad(); err != nil {
+		return
+	}
+
+	if pause.Page(page, s.RandomDelay()) != nil {
+		return
+	}
+	if err := s.HumanLikeScroll(page, 200+s.rng.Intn(400)); err != nil {
+		s.logger.WithError(err).Debug("Failed to scroll notifications")
+	}
+	pause.Page(page, s.RandomDelay())
+}
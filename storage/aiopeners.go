@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// AIOpener is an opening line a language model wrote for a prospect, kept so
+// the same prospect is never paid for twice with the same settings
+type AIOpener struct {
+	ProfileURL string    `json:"profile_url"`
+	ConfigKey  string    `json:"config_key"` // Identifies the model and instructions the line was written with
+	Opener     string    `json:"opener"`
+	Generator  string    `json:"generator"`
+	Tokens     int       `json:"tokens"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// GetAIOpener returns the opener written for a profile with the given
+// settings, or an empty string if there is none
+func (d *Database) GetAIOpener(profileURL, configKey string) (string, error) {
+	var opener string
+	err := d.db.QueryRow(`SELECT opener FROM ai_openers WHERE profile_url = ? AND config_key = ?`,
+		profileurl.Canonicalize(profileURL), configKey).Scan(&opener)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get AI opener: %w", err)
+	}
+	return opener, nil
+}
+
+// SaveAIOpener stores a written opener, replacing an earlier one for the
+// same profile and settings
+func (d *Database) SaveAIOpener(opener *AIOpener) error {
+	if opener.CreatedAt.IsZero() {
+		opener.CreatedAt = time.Now()
+	}
+	opener.ProfileURL = profileurl.Canonicalize(opener.ProfileURL)
+
+	query := `INSERT INTO ai_openers (profile_url, config_key, opener, generator, tokens, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?)
+			  ON CONFLICT(profile_url, config_key) DO UPDATE SET
+			  opener = excluded.opener, generator = excluded.generator,
+			  tokens = excluded.tokens, created_at = excluded.created_at`
+
+	if _, err := d.db.Exec(query, opener.ProfileURL, opener.ConfigKey, opener.Opener, opener.Generator,
+		opener.Tokens, opener.CreatedAt.UTC()); err != nil {
+		return fmt.Errorf("failed to save AI opener: %w", err)
+	}
+
+	d.logger.WithField("profile_url", opener.ProfileURL).Debug("AI opener saved")
+	return nil
+}
+
+// AITokensUsedSince returns the tokens spent on openers written since a time
+func (d *Database) AITokensUsedSince(since time.Time) (int, error) {
+	var tokens int
+	if err := d.db.QueryRow(`SELECT COALESCE(SUM(tokens), 0) FROM ai_openers WHERE created_at >= ?`, since.UTC()).Scan(&tokens); err != nil {
+		return 0, fmt.Errorf("failed to count AI tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// AIOpenerPreviewed reports whether openers written with the given settings
+// have been previewed in a dry run or review
+func (d *Database) AIOpenerPreviewed(configKey string) (bool, error) {
+	var count int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM ai_opener_previews WHERE config_key = ?`, configKey).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check AI opener previews: %w", err)
+	}
+	return count > 0, nil
+}
+
+// SaveAIOpenerPreview records that openers written with the given settings
+// were previewed, mode being how (dry-run or review)
+func (d *Database) SaveAIOpenerPreview(configKey, mode string) error {
+	query := `INSERT INTO ai_opener_previews (config_key, mode, previewed_at) VALUES (?, ?, ?)
+			  ON CONFLICT(config_key) DO UPDATE SET mode = excluded.mode, previewed_at = excluded.previewed_at`
+	if _, err := d.db.Exec(query, configKey, mode, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to save AI opener preview: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// OutreachTotals counts connection requests and messages sent since a date
+type OutreachTotals struct {
+	ConnectionsSent     int `json:"connections_sent"`
+	ConnectionsAccepted int `json:"connections_accepted"`
+	ProfilesMessaged    int `json:"profiles_messaged"`
+	ProfilesReplied     int `json:"profiles_replied"` // Messaged profiles that have replied
+	// Messaged profiles by the label of their latest classified reply
+	RepliedInterested    int `json:"replied_interested"`
+	RepliedNotInterested int `json:"replied_not_interested"`
+	RepliedLater         int `json:"replied_later"`
+}
+
+// TemplateStats summarizes the requests or messages sent with one template
+type TemplateStats struct {
+	Template string `json:"template"` // Empty for custom notes and messages
+	Sent     int    `json:"sent"`
+	Accepted int    `json:"accepted"` // Only counted for connection requests
+	Replied  int    `json:"replied"`
+}
+
+// AcceptanceRate returns the share of sent requests that were accepted, as a percentage
+func (s *TemplateStats) AcceptanceRate() float64 {
+	if s.Sent == 0 {
+		return 0
+	}
+	return float64(s.Accepted) * 100 / float64(s.Sent)
+}
+
+// ReplyRate returns the share of recipients who replied, as a percentage
+func (s *TemplateStats) ReplyRate() float64 {
+	if s.Sent == 0 {
+		return 0
+	}
+	return float64(s.Replied) * 100 / float64(s.Sent)
+}
+
+// DailyActivity counts the outreach of one day (UTC)
+type DailyActivity struct {
+	Date                string `json:"date"` // YYYY-MM-DD
+	ConnectionsSent     int    `json:"connections_sent"`
+	ConnectionsAccepted int    `json:"connections_accepted"`
+	MessagesSent        int    `json:"messages_sent"`
+	RepliesReceived     int    `json:"replies_received"`
+}
+
+// GetOutreachTotals counts the requests and messages sent since the given date
+func (d *Database) GetOutreachTotals(since time.Time) (*OutreachTotals, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM connection_requests WHERE dry_run = 0 AND COALESCE(action, 'connect') = 'connect' AND DATE(sent_at) >= DATE(?)),
+			(SELECT COUNT(*) FROM connection_requests WHERE status = 'accepted' AND dry_run = 0 AND DATE(sent_at) >= DATE(?)),
+			(SELECT COUNT(DISTINCT recipient_url) FROM messages WHERE dry_run = 0 AND DATE(sent_at) >= DATE(?)),
+			(SELECT COUNT(DISTINCT recipient_url) FROM messages WHERE dry_run = 0 AND DATE(sent_at) >= DATE(?)
+				AND recipient_url IN (SELECT sender_url FROM messages_received)),
+			(SELECT COUNT(DISTINCT recipient_url) FROM messages WHERE dry_run = 0 AND DATE(sent_at) >= DATE(?)
+				AND recipient_url IN (SELECT profile_url FROM reply_labels WHERE label = ?)),
+			(SELECT COUNT(DISTINCT recipient_url) FROM messages WHERE dry_run = 0 AND DATE(sent_at) >= DATE(?)
+				AND recipient_url IN (SELECT profile_url FROM reply_labels WHERE label = ?)),
+			(SELECT COUNT(DISTINCT recipient_url) FROM messages WHERE dry_run = 0 AND DATE(sent_at) >= DATE(?)
+				AND recipient_url IN (SELECT profile_url FROM reply_labels WHERE label = ?))
+	`
+
+	var totals OutreachTotals
+	err := d.db.QueryRow(query, since, since, since, since,
+		since, ReplyInterested, since, ReplyNotInterested, since, ReplyLater).Scan(&totals.ConnectionsSent, &totals.ConnectionsAccepted,
+		&totals.ProfilesMessaged, &totals.ProfilesReplied, &totals.RepliedInterested, &totals.RepliedNotInterested, &totals.RepliedLater)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outreach totals: %w", err)
+	}
+
+	return &totals, nil
+}
+
+// GetConnectionTemplateStats returns per-template request, acceptance and reply
+// counts for requests sent since the given date. A/B variants count as the
+// template they were named after.
+func (d *Database) GetConnectionTemplateStats(since time.Time) ([]*TemplateStats, error) {
+	query := `SELECT COALESCE(NULLIF(variant, ''), NULLIF(template, ''), '') AS name, COUNT(*),
+			  SUM(CASE WHEN status = 'accepted' THEN 1 ELSE 0 END),
+			  SUM(CASE WHEN profile_url IN (SELECT sender_url FROM messages_received) THEN 1 ELSE 0 END)
+			  FROM connection_requests WHERE dry_run = 0 AND COALESCE(action, 'connect') = 'connect' AND DATE(sent_at) >= DATE(?)
+			  GROUP BY name ORDER BY COUNT(*) DESC`
+
+	return d.queryTemplateStats(query, since)
+}
+
+// GetMessageTemplateStats returns per-template message and reply counts for
+// messages sent since the given date
+func (d *Database) GetMessageTemplateStats(since time.Time) ([]*TemplateStats, error) {
+	query := `SELECT COALESCE(template, '') AS name, COUNT(*), 0,
+			  SUM(CASE WHEN recipient_url IN (SELECT sender_url FROM messages_received) THEN 1 ELSE 0 END)
+			  FROM messages WHERE dry_run = 0 AND DATE(sent_at) >= DATE(?)
+			  GROUP BY name ORDER BY COUNT(*) DESC`
+
+	return d.queryTemplateStats(query, since)
+}
+
+func (d *Database) queryTemplateStats(query string, since time.Time) ([]*TemplateStats, error) {
+	rows, err := d.db.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*TemplateStats
+	for rows.Next() {
+		var s TemplateStats
+		if err := rows.Scan(&s.Template, &s.Sent, &s.Accepted, &s.Replied); err != nil {
+			return nil, fmt.Errorf("failed to scan template stats: %w", err)
+		}
+		stats = append(stats, &s)
+	}
+
+	return stats, nil
+}
+
+// GetAcceptanceDelays returns how long each request sent since the given date
+// took to be accepted
+func (d *Database) GetAcceptanceDelays(since time.Time) ([]time.Duration, error) {
+	query := `SELECT sent_at, accepted_at FROM connection_requests
+			  WHERE status = 'accepted' AND accepted_at IS NOT NULL AND dry_run = 0 AND DATE(sent_at) >= DATE(?)`
+
+	rows, err := d.db.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get acceptance delays: %w", err)
+	}
+	defer rows.Close()
+
+	var delays []time.Duration
+	for rows.Next() {
+		var sentAt, acceptedAt time.Time
+		if err := rows.Scan(&sentAt, &acceptedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan acceptance delay: %w", err)
+		}
+		delay := acceptedAt.Sub(sentAt)
+		if delay < 0 {
+			delay = 0
+		}
+		delays = append(delays, delay)
+	}
+
+	return delays, nil
+}
+
+// GetDailyActivity returns the outreach of every day since the given date that
+// had any, oldest first
+func (d *Database) GetDailyActivity(since time.Time) ([]*DailyActivity, error) {
+	days := make(map[string]*DailyActivity)
+	for _, series := range []struct {
+		query string
+		count func(*DailyActivity) *int
+	}{
+		{`SELECT DATE(sent_at), COUNT(*) FROM connection_requests WHERE dry_run = 0 AND COALESCE(action, 'connect') = 'connect'
+			AND DATE(sent_at) >= DATE(?) GROUP BY 1`,
+			func(a *DailyActivity) *int { return &a.ConnectionsSent }},
+		{`SELECT DATE(accepted_at), COUNT(*) FROM connection_requests WHERE status = 'accepted' AND accepted_at IS NOT NULL
+			AND dry_run = 0 AND DATE(accepted_at) >= DATE(?) GROUP BY 1`,
+			func(a *DailyActivity) *int { return &a.ConnectionsAccepted }},
+		{`SELECT DATE(sent_at), COUNT(*) FROM messages WHERE dry_run = 0 AND DATE(sent_at) >= DATE(?) GROUP BY 1`,
+			func(a *DailyActivity) *int { return &a.MessagesSent }},
+		{`SELECT DATE(received_at), COUNT(*) FROM messages_received WHERE DATE(received_at) >= DATE(?) GROUP BY 1`,
+			func(a *DailyActivity) *int { return &a.RepliesReceived }},
+	} {
+		rows, err := d.db.Query(series.query, since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get daily activity: %w", err)
+		}
+		for rows.Next() {
+			var date dateString
+			var count int
+			if err := rows.Scan(&date, &count); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan daily activity: %w", err)
+			}
+			day, ok := days[string(date)]
+			if !ok {
+				day = &DailyActivity{Date: string(date)}
+				days[string(date)] = day
+			}
+			*series.count(day) = count
+		}
+		rows.Close()
+	}
+
+	activity := make([]*DailyActivity, 0, len(days))
+	for _, day := range days {
+		activity = append(activity, day)
+	}
+	sort.Slice(activity, func(i, j int) bool { return activity[i].Date < activity[j].Date })
+
+	return activity, nil
+}
+
+// dateString scans a DATE() result, which SQLite returns as text and the other
+// backends as a time
+type dateString string
+
+func (d *dateString) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case time.Time:
+		*d = dateString(v.Format("2006-01-02"))
+	case []byte:
+		*d = dateString(v)
+	case string:
+		*d = dateString(v)
+	default:
+		return fmt.Errorf("unexpected date %v", value)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// Assignment records which account of a campaign owns a prospect. A prospect
+// has one owner across all campaigns, so no two accounts contact the same person.
+type Assignment struct {
+	ProfileURL string    `json:"profile_url"`
+	Account    string    `json:"account"`
+	Campaign   string    `json:"campaign"` // Campaign that first assigned the prospect
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+// SaveAssignment assigns a prospect to an account, keeping an earlier
+// assignment of the same prospect. It reports whether the assignment was stored.
+func (d *Database) SaveAssignment(assignment *Assignment) (bool, error) {
+	query := `INSERT OR IGNORE INTO account_assignments (profile_url, account, campaign, assigned_at) VALUES (?, ?, ?, ?)`
+
+	result, err := d.db.Exec(query, profileurl.Canonicalize(assignment.ProfileURL), assignment.Account, assignment.Campaign, time.Now().UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to save assignment: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get inserted rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// GetAssignment returns the owner of a prospect, or nil if no account has it
+func (d *Database) GetAssignment(profileURL string) (*Assignment, error) {
+	query := `SELECT profile_url, account, campaign, assigned_at FROM account_assignments WHERE profile_url = ?`
+
+	var assignment Assignment
+	err := d.db.QueryRow(query, profileurl.Canonicalize(profileURL)).Scan(&assignment.ProfileURL, &assignment.Account, &assignment.Campaign, &assignment.AssignedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get assignment: %w", err)
+	}
+
+	return &assignment, nil
+}
+
+// ListAssignments returns the prospects assigned by a campaign, or by every
+// campaign when campaign is empty, grouped by account
+func (d *Database) ListAssignments(campaign string) ([]*Assignment, error) {
+	query := `SELECT profile_url, account, campaign, assigned_at FROM account_assignments`
+	var args []interface{}
+	if campaign != "" {
+		query += ` WHERE campaign = ?`
+		args = append(args, campaign)
+	}
+	query += ` ORDER BY account, assigned_at`
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list assignments: %w", err)
+	}
+	defer rows.Close()
+
+	var assignments []*Assignment
+	for rows.Next() {
+		var assignment Assignment
+		if err := rows.Scan(&assignment.ProfileURL, &assignment.Account, &assignment.Campaign, &assignment.AssignedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan assignment: %w", err)
+		}
+		assignments = append(assignments, &assignment)
+	}
+
+	return assignments, rows.Err()
+}
@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// Audited browser actions
+const (
+	AuditNavigate = "navigate"
+	AuditClick    = "click"
+	AuditType     = "type"
+	AuditFailure  = "failure" // An action failed; the detail names the page capture saved for it
+)
+
+// Audit outcomes
+const (
+	AuditOK    = "ok"
+	AuditError = "error"
+)
+
+// AuditEntry records one browser action
+type AuditEntry struct {
+	ID         int           `json:"id,omitempty"`
+	OccurredAt time.Time     `json:"occurred_at"`
+	Action     string        `json:"action"`
+	Target     string        `json:"target,omitempty"`      // URL navigated to, or the element clicked or typed into
+	PageURL    string        `json:"page_url,omitempty"`    // Page the action happened on
+	ProfileURL string        `json:"profile_url,omitempty"` // Set when the page is a member profile
+	Detail     string        `json:"detail,omitempty"`
+	Outcome    string        `json:"outcome"`
+	Error      string        `json:"error,omitempty"`
+	Duration   time.Duration `json:"duration"`
+	RunID      string        `json:"run_id,omitempty"` // Command invocation the action happened in
+}
+
+// AuditFilter selects audit entries; zero fields match everything
+type AuditFilter struct {
+	Since      time.Time
+	Until      time.Time
+	ProfileURL string
+	Action     string
+	RunID      string
+	Limit      int // Most recent entries to return
+}
+
+// Matches reports whether an entry passes the filter
+func (f AuditFilter) Matches(entry *AuditEntry) bool {
+	if !f.Since.IsZero() && entry.OccurredAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !entry.OccurredAt.Before(f.Until) {
+		return false
+	}
+	if f.ProfileURL != "" && entry.ProfileURL != profileurl.Canonicalize(f.ProfileURL) {
+		return false
+	}
+	if f.Action != "" && !strings.EqualFold(entry.Action, f.Action) {
+		return false
+	}
+	if f.RunID != "" && entry.RunID != f.RunID {
+		return false
+	}
+	return true
+}
+
+// SaveAuditEntry stores an audit entry
+func (d *Database) SaveAuditEntry(entry *AuditEntry) error {
+	query := `INSERT INTO audit_log (occurred_at, action, target, page_url, profile_url, detail, outcome, error, duration_ms, run_id)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''))`
+
+	id, err := d.db.insert(query, entry.OccurredAt.UTC(), entry.Action, entry.Target, entry.PageURL, entry.ProfileURL,
+		entry.Detail, entry.Outcome, entry.Error, entry.Duration.Milliseconds(), entry.RunID)
+	if err != nil {
+		return fmt.Errorf("failed to save audit entry: %w", err)
+	}
+	entry.ID = int(id)
+	return nil
+}
+
+// ListAuditEntries returns the entries matching the filter, oldest first
+func (d *Database) ListAuditEntries(filter AuditFilter) ([]*AuditEntry, error) {
+	var conditions []string
+	var args []interface{}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "occurred_at >= ?")
+		args = append(args, filter.Since.UTC())
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "occurred_at < ?")
+		args = append(args, filter.Until.UTC())
+	}
+	if filter.ProfileURL != "" {
+		conditions = append(conditions, "profile_url = ?")
+		args = append(args, profileurl.Canonicalize(filter.ProfileURL))
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, strings.ToLower(filter.Action))
+	}
+	if filter.RunID != "" {
+		conditions = append(conditions, "run_id = ?")
+		args = append(args, filter.RunID)
+	}
+
+	query := `SELECT id, occurred_at, action, COALESCE(target, ''), COALESCE(page_url, ''), COALESCE(profile_url, ''),
+			  COALESCE(detail, ''), outcome, COALESCE(error, ''), duration_ms, COALESCE(run_id, '') FROM audit_log`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY occurred_at DESC, id DESC"
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*AuditEntry
+	for rows.Next() {
+		var entry AuditEntry
+		var durationMS int64
+		if err := rows.Scan(&entry.ID, &entry.OccurredAt, &entry.Action, &entry.Target, &entry.PageURL, &entry.ProfileURL,
+			&entry.Detail, &entry.Outcome, &entry.Error, &durationMS, &entry.RunID); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entry.Duration = time.Duration(durationMS) * time.Millisecond
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	// Newest were selected so the limit keeps the most recent; show them in order
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Markers the dump tools write last, so a truncated dump can be told apart from a complete one
+const (
+	postgresDumpComplete = "-- PostgreSQL database dump complete"
+	mysqlDumpComplete    = "-- Dump completed"
+)
+
+// BackupExtension returns the file extension of a backend's backups: a copy
+// of the database file for SQLite, an SQL dump for Postgres and MySQL
+func BackupExtension(backend string) string {
+	dialect, err := newDialect(backend)
+	if err == nil {
+		if _, ok := dialect.(sqliteDialect); ok {
+			return ".db"
+		}
+	}
+	return ".sql"
+}
+
+// Backend returns the storage backend the database is on
+func (d *Database) Backend() string {
+	return d.backend
+}
+
+// Backup writes a consistent copy of the database to path, which must not exist.
+// SQLite is copied with VACUUM INTO while the database stays usable; Postgres
+// and MySQL are dumped with pg_dump or mysqldump, which must be on the PATH.
+func (d *Database) Backup(path string) error {
+	var err error
+	switch d.db.dialect.(type) {
+	case postgresDialect:
+		err = runTool("pg_dump", nil, []string{"--clean", "--if-exists", "--no-owner", "--dbname=" + d.source, "--file=" + path}, nil)
+	case mysqlDialect:
+		var args []string
+		var env []string
+		if args, env, err = mysqlToolArgs(d.source); err == nil {
+			args = append([]string{"--single-transaction", "--routines", "--result-file=" + path}, args...)
+			err = runTool("mysqldump", env, args, nil)
+		}
+	default:
+		_, err = d.db.Exec(`VACUUM INTO ?`, path)
+	}
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+
+	d.logger.WithField("file", path).Debug("Database backed up")
+	return nil
+}
+
+// VerifyBackup checks that a backup of the backend is complete: an SQLite
+// copy must pass PRAGMA integrity_check, and a dump must end with the marker
+// its tool writes on success
+func VerifyBackup(backend, path string) error {
+	dialect, err := newDialect(backend)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("backup not found: %w", err)
+	}
+
+	switch dialect.(type) {
+	case postgresDialect:
+		return verifyDump(path, postgresDumpComplete)
+	case mysqlDialect:
+		return verifyDump(path, mysqlDumpComplete)
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRow(`PRAGMA integrity_check`).Scan(&result); err != nil {
+		return fmt.Errorf("failed to check backup: %w", err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("backup failed integrity check: %s", result)
+	}
+	return nil
+}
+
+// Restore replaces the backend's database with a backup. For SQLite the
+// database must not be open; the file at source is replaced. Dumps are loaded
+// with psql or mysql and drop the tables they recreate.
+func Restore(backend, source, path string) error {
+	dialect, err := newDialect(backend)
+	if err != nil {
+		return err
+	}
+
+	switch dialect.(type) {
+	case postgresDialect:
+		err = runTool("psql", nil, []string{"--quiet", "--single-transaction", "--set=ON_ERROR_STOP=1", "--dbname=" + source, "--file=" + path}, nil)
+	case mysqlDialect:
+		var args []string
+		var env []string
+		var dump *os.File
+		if args, env, err = mysqlToolArgs(source); err == nil {
+			if dump, err = os.Open(path); err == nil {
+				defer dump.Close()
+				err = runTool("mysql", env, args, dump)
+			}
+		}
+	default:
+		err = replaceFile(path, source)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore database: %w", err)
+	}
+	return nil
+}
+
+// replaceFile copies an SQLite backup over the database file, through a
+// temporary file so an interrupted restore leaves the database as it was
+func replaceFile(backup, dbPath string) error {
+	in, err := os.Open(backup)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp := dbPath + ".restore"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	// A journal left by the replaced database would be applied to the restored one
+	os.Remove(dbPath + "-journal")
+	os.Remove(dbPath + "-wal")
+	os.Remove(dbPath + "-shm")
+	return os.Rename(tmp, dbPath)
+}
+
+// verifyDump checks that the end of a dump holds the tool's completion marker
+func verifyDump(path, marker string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+	offset := info.Size() - 4096
+	if offset < 0 {
+		offset = 0
+	}
+	tail := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(tail, offset); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+	if !strings.Contains(string(tail), marker) {
+		return fmt.Errorf("backup is incomplete: %q not found at the end of the dump", marker)
+	}
+	return nil
+}
+
+// mysqlToolArgs turns a DSN into connection flags for mysqldump and mysql;
+// the password is passed in the environment rather than on the command line
+func mysqlToolArgs(dsn string) ([]string, []string, error) {
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid storage.dsn: %w", err)
+	}
+
+	var args []string
+	if cfg.User != "" {
+		args = append(args, "--user="+cfg.User)
+	}
+	if cfg.Net == "unix" {
+		args = append(args, "--socket="+cfg.Addr)
+	} else if cfg.Addr != "" {
+		host, port, err := net.SplitHostPort(cfg.Addr)
+		if err != nil {
+			host, port = cfg.Addr, ""
+		}
+		args = append(args, "--host="+host, "--protocol=tcp")
+		if port != "" {
+			args = append(args, "--port="+port)
+		}
+	}
+	args = append(args, cfg.DBName)
+
+	var env []string
+	if cfg.Passwd != "" {
+		env = append(env, "MYSQL_PWD="+cfg.Passwd)
+	}
+	return args, env, nil
+}
+
+// runTool runs a database client tool, reporting its output if it fails
+func runTool(name string, env, args []string, stdin io.Reader) error {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return fmt.Errorf("%s not found on the PATH", name)
+	}
+
+	cmd := exec.Command(path, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = stdin
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
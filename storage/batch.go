@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Batch item statuses
+const (
+	BatchItemSuccess = "success"
+	BatchItemFailed  = "failed"
+	BatchItemSkipped = "skipped" // Permanently skipped, e.g. LinkedIn requires an email to connect
+)
+
+// BatchItem represents the recorded outcome of a single item in a batch run
+type BatchItem struct {
+	ID           int       `json:"id"`
+	BatchID      string    `json:"batch_id"`
+	Action       string    `json:"action"` // connect, message
+	ItemURL      string    `json:"item_url"`
+	Status       string    `json:"status"` // success, failed, skipped
+	ErrorMessage string    `json:"error_message,omitempty"`
+	ProcessedAt  time.Time `json:"processed_at"`
+}
+
+// RecordBatchItem records the outcome of a batch item, replacing any earlier attempt
+func (d *Database) RecordBatchItem(batchID, action, itemURL, status, errorMessage string) error {
+	query := `INSERT INTO batch_items (batch_id, action, item_url, status, error_message, processed_at, run_id)
+			  VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''))
+			  ON CONFLICT(batch_id, item_url) DO UPDATE SET
+			  status = excluded.status, error_message = excluded.error_message, processed_at = excluded.processed_at,
+			  run_id = excluded.run_id`
+
+	if _, err := d.db.Exec(query, batchID, action, itemURL, status, errorMessage, time.Now(), d.runID); err != nil {
+		return fmt.Errorf("failed to record batch item: %w", err)
+	}
+
+	d.logger.WithFields(logrus.Fields{
+		"batch_id": batchID,
+		"item_url": itemURL,
+		"status":   status,
+	}).Debug("Batch item recorded")
+	return nil
+}
+
+// IsBatchItemCompleted reports whether an item was already completed successfully
+// or permanently skipped in a batch
+func (d *Database) IsBatchItemCompleted(batchID, itemURL string) (bool, error) {
+	query := `SELECT status FROM batch_items WHERE batch_id = ? AND item_url = ?`
+
+	var status string
+	err := d.db.QueryRow(query, batchID, itemURL).Scan(&status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get batch item: %w", err)
+	}
+
+	return status == BatchItemSuccess || status == BatchItemSkipped, nil
+}
+
+// GetBatchItems retrieves all recorded items for a batch
+func (d *Database) GetBatchItems(batchID string) ([]*BatchItem, error) {
+	query := `SELECT id, batch_id, action, item_url, status, COALESCE(error_message, ''), processed_at
+			  FROM batch_items WHERE batch_id = ? ORDER BY processed_at`
+
+	rows, err := d.db.Query(query, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*BatchItem
+	for rows.Next() {
+		var item BatchItem
+		if err := rows.Scan(&item.ID, &item.BatchID, &item.Action, &item.ItemURL, &item.Status, &item.ErrorMessage, &item.ProcessedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan batch item: %w", err)
+		}
+		items = append(items, &item)
+	}
+
+	return items, nil
+}
+
+// GetRecentBatchItems retrieves the latest recorded items across all batches, newest first
+func (d *Database) GetRecentBatchItems(limit int) ([]*BatchItem, error) {
+	query := `SELECT id, batch_id, action, item_url, status, COALESCE(error_message, ''), processed_at
+			  FROM batch_items ORDER BY processed_at DESC, id DESC LIMIT ?`
+
+	rows, err := d.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent batch items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*BatchItem
+	for rows.Next() {
+		var item BatchItem
+		if err := rows.Scan(&item.ID, &item.BatchID, &item.Action, &item.ItemURL, &item.Status, &item.ErrorMessage, &item.ProcessedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan batch item: %w", err)
+		}
+		items = append(items, &item)
+	}
+
+	return items, nil
+}
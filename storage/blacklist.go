@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// BlacklistEntry is a profile, company domain or name pattern that must never be contacted
+type BlacklistEntry struct {
+	ID        int       `json:"id"`
+	Kind      string    `json:"kind"` // profile, domain, name
+	Value     string    `json:"value"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AddBlacklistEntry stores an entry, updating the reason if it is already listed.
+// It reports whether the entry is new.
+func (d *Database) AddBlacklistEntry(entry *BlacklistEntry) (bool, error) {
+	var count int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM blacklist WHERE kind = ? AND value = ?`, entry.Kind, entry.Value).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check blacklist: %w", err)
+	}
+
+	query := `INSERT INTO blacklist (kind, value, reason, created_at) VALUES (?, ?, ?, ?)
+			  ON CONFLICT(kind, value) DO UPDATE SET reason = excluded.reason`
+
+	if _, err := d.db.Exec(query, entry.Kind, entry.Value, entry.Reason, time.Now().UTC()); err != nil {
+		return false, fmt.Errorf("failed to add blacklist entry: %w", err)
+	}
+
+	d.logger.WithField("kind", entry.Kind).WithField("value", entry.Value).Debug("Blacklist entry saved")
+	return count == 0, nil
+}
+
+// RemoveBlacklistEntry deletes an entry, reporting whether it was listed
+func (d *Database) RemoveBlacklistEntry(kind, value string) (bool, error) {
+	result, err := d.db.Exec(`DELETE FROM blacklist WHERE kind = ? AND value = ?`, kind, value)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove blacklist entry: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get deleted rows: %w", err)
+	}
+
+	d.logger.WithField("kind", kind).WithField("value", value).Debug("Blacklist entry removed")
+	return affected > 0, nil
+}
+
+// ListBlacklist returns every blacklist entry, ordered by kind and value
+func (d *Database) ListBlacklist() ([]*BlacklistEntry, error) {
+	query := `SELECT id, kind, value, COALESCE(reason, ''), created_at FROM blacklist ORDER BY kind, value`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blacklist: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*BlacklistEntry
+	for rows.Next() {
+		var entry BlacklistEntry
+		if err := rows.Scan(&entry.ID, &entry.Kind, &entry.Value, &entry.Reason, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan blacklist entry: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
+
+// RecordOptOut marks a profile as having asked not to be contacted again,
+// keeping the first reply that said so
+func (d *Database) RecordOptOut(profileURL, threadID, phrase, content string) error {
+	query := `INSERT OR IGNORE INTO opt_outs (profile_url, thread_id, phrase, content, opted_out_at)
+			  VALUES (?, ?, ?, ?, ?)`
+
+	if _, err := d.db.Exec(query, profileurl.Canonicalize(profileURL), threadID, phrase, content, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to record opt-out: %w", err)
+	}
+
+	d.logger.WithField("profile_url", profileURL).Debug("Opt-out recorded")
+	return nil
+}
@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// CampaignStats summarizes the connection requests sent in one campaign
+type CampaignStats struct {
+	Name     string     `json:"name"`
+	Sent     int        `json:"sent"`
+	Accepted int        `json:"accepted"`
+	PausedAt *time.Time `json:"paused_at,omitempty"` // Set while queued tasks of the campaign are held back
+}
+
+// AcceptanceRate returns the share of sent requests that were accepted, as a percentage
+func (s *CampaignStats) AcceptanceRate() float64 {
+	if s.Sent == 0 {
+		return 0
+	}
+	return float64(s.Accepted) * 100 / float64(s.Sent)
+}
+
+// PauseCampaign holds back the queued tasks of a campaign, reporting whether it
+// was running
+func (d *Database) PauseCampaign(name string) (bool, error) {
+	result, err := d.db.Exec(`INSERT OR IGNORE INTO paused_campaigns (name, paused_at) VALUES (?, ?)`, name, time.Now().UTC())
+	if err != nil {
+		return false, fmt.Errorf("failed to pause campaign: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get inserted rows: %w", err)
+	}
+
+	d.logger.WithField("campaign", name).Info("Campaign paused")
+	return affected > 0, nil
+}
+
+// ResumeCampaign lets the queued tasks of a paused campaign run again,
+// reporting whether it was paused
+func (d *Database) ResumeCampaign(name string) (bool, error) {
+	result, err := d.db.Exec(`DELETE FROM paused_campaigns WHERE name = ?`, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to resume campaign: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get deleted rows: %w", err)
+	}
+
+	d.logger.WithField("campaign", name).Info("Campaign resumed")
+	return affected > 0, nil
+}
+
+// IsCampaignPaused reports whether a campaign is paused
+func (d *Database) IsCampaignPaused(name string) (bool, error) {
+	var count int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM paused_campaigns WHERE name = ?`, name).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to get paused campaign: %w", err)
+	}
+	return count > 0, nil
+}
+
+// GetCampaignStats returns request and acceptance counts for every campaign,
+// including paused campaigns that have not sent anything yet
+func (d *Database) GetCampaignStats() ([]*CampaignStats, error) {
+	query := `SELECT campaign, COUNT(*), SUM(CASE WHEN status = 'accepted' THEN 1 ELSE 0 END)
+			  FROM connection_requests
+			  WHERE campaign IS NOT NULL AND campaign != '' AND dry_run = 0 AND COALESCE(action, 'connect') = 'connect'
+			  GROUP BY campaign`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign stats: %w", err)
+	}
+
+	campaigns := make(map[string]*CampaignStats)
+	for rows.Next() {
+		var s CampaignStats
+		if err := rows.Scan(&s.Name, &s.Sent, &s.Accepted); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan campaign stats: %w", err)
+		}
+		campaigns[s.Name] = &s
+	}
+	rows.Close()
+
+	rows, err = d.db.Query(`SELECT name, paused_at FROM paused_campaigns`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get paused campaigns: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		var pausedAt time.Time
+		if err := rows.Scan(&name, &pausedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan paused campaign: %w", err)
+		}
+		s, ok := campaigns[name]
+		if !ok {
+			s = &CampaignStats{Name: name}
+			campaigns[name] = s
+		}
+		s.PausedAt = &pausedAt
+	}
+
+	stats := make([]*CampaignStats, 0, len(campaigns))
+	for _, s := range campaigns {
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+
+	return stats, nil
+}
+
+// ListConnectionRequests returns the latest requests, newest first, for one
+// campaign or for every campaign when campaign is empty
+func (d *Database) ListConnectionRequests(campaign string, limit int) ([]*ConnectionRequest, error) {
+	query := `SELECT id, profile_url, COALESCE(message, ''), status, sent_at, accepted_at, COALESCE(campaign, ''),
+			  COALESCE(variant, ''), COALESCE(template, ''), dry_run
+			  FROM connection_requests WHERE (? = '' OR campaign = ?) ORDER BY sent_at DESC, id DESC LIMIT ?`
+
+	rows, err := d.db.Query(query, campaign, campaign, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list connection requests: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []*ConnectionRequest
+	for rows.Next() {
+		var request ConnectionRequest
+		if err := rows.Scan(&request.ID, &request.ProfileURL, &request.Message, &request.Status, &request.SentAt, &request.AcceptedAt,
+			&request.Campaign, &request.Variant, &request.Template, &request.DryRun); err != nil {
+			return nil, fmt.Errorf("failed to scan connection request: %w", err)
+		}
+		requests = append(requests, &request)
+	}
+
+	return requests, nil
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// CompanyPost represents a post scraped from a company page
+type CompanyPost struct {
+	CompanyURL string    `json:"company_url"`
+	PostURN    string    `json:"post_urn"`
+	AuthorName string    `json:"author_name,omitempty"`
+	Content    string    `json:"content"`
+	ScrapedAt  time.Time `json:"scraped_at"`
+}
+
+// SaveCompanyPost stores a company post, updating it if it was scraped before
+func (d *Database) SaveCompanyPost(post *CompanyPost) error {
+	if post.ScrapedAt.IsZero() {
+		post.ScrapedAt = time.Now()
+	}
+
+	query := `INSERT INTO company_posts (post_urn, company_url, author_name, content, scraped_at)
+			  VALUES (?, ?, ?, ?, ?)
+			  ON CONFLICT(post_urn) DO UPDATE SET
+			  company_url = excluded.company_url, author_name = excluded.author_name,
+			  content = excluded.content, scraped_at = excluded.scraped_at`
+
+	if _, err := d.db.Exec(query, post.PostURN, post.CompanyURL, post.AuthorName, post.Content, post.ScrapedAt.UTC()); err != nil {
+		return fmt.Errorf("failed to save company post: %w", err)
+	}
+
+	d.logger.WithField("post_urn", post.PostURN).Debug("Company post saved")
+	return nil
+}
+
+// GetCompanyPosts retrieves the stored posts of a company page, most recently scraped first
+func (d *Database) GetCompanyPosts(companyURL string) ([]*CompanyPost, error) {
+	query := `SELECT company_url, post_urn, COALESCE(author_name, ''), content, scraped_at
+			  FROM company_posts WHERE company_url = ? ORDER BY scraped_at DESC`
+
+	rows, err := d.db.Query(query, companyURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company posts: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []*CompanyPost
+	for rows.Next() {
+		var post CompanyPost
+		if err := rows.Scan(&post.CompanyURL, &post.PostURN, &post.AuthorName, &post.Content, &post.ScrapedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan company post: %w", err)
+		}
+		posts = append(posts, &post)
+	}
+
+	return posts, nil
+}
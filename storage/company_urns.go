@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CompanyURN is a company name resolved to its LinkedIn company ID
+type CompanyURN struct {
+	Query       string    `json:"query"` // Normalized name that was looked up
+	CompanyID   string    `json:"company_id"`
+	CompanyName string    `json:"company_name"` // Name of the matched company as LinkedIn shows it
+	ResolvedAt  time.Time `json:"resolved_at"`
+}
+
+// URN returns the company's URN, e.g. urn:li:company:1035
+func (c *CompanyURN) URN() string {
+	return "urn:li:company:" + c.CompanyID
+}
+
+// NormalizeCompanyQuery returns the cache key for a company name: trimmed,
+// lower case and with runs of spaces collapsed
+func NormalizeCompanyQuery(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// SaveCompanyURN caches a resolved company, replacing an earlier result for the same name
+func (d *Database) SaveCompanyURN(company *CompanyURN) error {
+	query := `INSERT INTO company_urns (query, company_id, company_name, resolved_at)
+			  VALUES (?, ?, ?, ?)
+			  ON CONFLICT(query) DO UPDATE SET
+			  company_id = excluded.company_id, company_name = excluded.company_name, resolved_at = excluded.resolved_at`
+
+	_, err := d.db.Exec(query, NormalizeCompanyQuery(company.Query), company.CompanyID, company.CompanyName, company.ResolvedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to save company URN: %w", err)
+	}
+
+	return nil
+}
+
+// GetCompanyURN returns the cached company for a name, or nil if it has not been resolved
+func (d *Database) GetCompanyURN(name string) (*CompanyURN, error) {
+	query := `SELECT query, company_id, COALESCE(company_name, ''), resolved_at FROM company_urns WHERE query = ?`
+
+	var company CompanyURN
+	err := d.db.QueryRow(query, NormalizeCompanyQuery(name)).Scan(&company.Query, &company.CompanyID, &company.CompanyName, &company.ResolvedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get company URN: %w", err)
+	}
+
+	return &company, nil
+}
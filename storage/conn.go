@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// errNotInserted is returned by insert when INSERT OR IGNORE skipped an existing row
+var errNotInserted = errors.New("row already exists")
+
+// conn is a database handle that adapts each query to the backend's dialect
+type conn struct {
+	db      *sql.DB
+	dialect dialect
+}
+
+func (c *conn) Exec(query string, args ...interface{}) (sql.Result, error) {
+	query, args = c.dialect.rewrite(query, args)
+	return c.db.Exec(query, args...)
+}
+
+func (c *conn) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	query, args = c.dialect.rewrite(query, args)
+	return c.db.Query(query, args...)
+}
+
+func (c *conn) QueryRow(query string, args ...interface{}) *sql.Row {
+	query, args = c.dialect.rewrite(query, args)
+	return c.db.QueryRow(query, args...)
+}
+
+func (c *conn) Begin() (*tx, error) {
+	t, err := c.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return &tx{tx: t, dialect: c.dialect}, nil
+}
+
+func (c *conn) Close() error {
+	return c.db.Close()
+}
+
+// insert runs an INSERT into a table with an id column and returns the new row's ID
+func (c *conn) insert(query string, args ...interface{}) (int64, error) {
+	query, args = c.dialect.rewrite(query, args)
+
+	if c.dialect.returningID() {
+		var id int64
+		err := c.db.QueryRow(query+" RETURNING id", args...).Scan(&id)
+		if err == sql.ErrNoRows {
+			return 0, errNotInserted
+		}
+		return id, err
+	}
+
+	result, err := c.db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get inserted rows: %w", err)
+	}
+	if affected == 0 {
+		return 0, errNotInserted
+	}
+	return result.LastInsertId()
+}
+
+// tx is a transaction that adapts each query to the backend's dialect
+type tx struct {
+	tx      *sql.Tx
+	dialect dialect
+}
+
+func (t *tx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	query, args = t.dialect.rewrite(query, args)
+	return t.tx.Exec(query, args...)
+}
+
+func (t *tx) QueryRow(query string, args ...interface{}) *sql.Row {
+	query, args = t.dialect.rewrite(query, args)
+	return t.tx.QueryRow(query, args...)
+}
+
+func (t *tx) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *tx) Rollback() error {
+	return t.tx.Rollback()
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"fmt"
+
+	"linkedin-automation/profileurl"
+)
+
+// GetContactedProfiles returns the canonical URLs of every profile that has been
+// sent a connection request or a message, including successful batch items
+func (d *Database) GetContactedProfiles() (map[string]bool, error) {
+	query := `SELECT profile_url FROM connection_requests WHERE dry_run = 0
+			  UNION SELECT recipient_url FROM messages WHERE dry_run = 0
+			  UNION SELECT item_url FROM batch_items WHERE status = ? AND action IN ('connect', 'message')`
+
+	rows, err := d.db.Query(query, BatchItemSuccess)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contacted profiles: %w", err)
+	}
+	defer rows.Close()
+
+	// URLs are canonicalized here rather than in SQL so rows written before
+	// canonicalization was introduced still match
+	contacted := make(map[string]bool)
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, fmt.Errorf("failed to scan contacted profile: %w", err)
+		}
+		contacted[profileurl.Canonicalize(url)] = true
+	}
+
+	return contacted, nil
+}
+
+// GetMessagedProfiles returns the canonical URLs of every profile sent a
+// message, in a batch or otherwise
+func (d *Database) GetMessagedProfiles() (map[string]bool, error) {
+	query := `SELECT recipient_url FROM messages WHERE dry_run = 0
+			  UNION SELECT item_url FROM batch_items WHERE status = ? AND action = 'message'`
+
+	rows, err := d.db.Query(query, BatchItemSuccess)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messaged profiles: %w", err)
+	}
+	defer rows.Close()
+
+	messaged := make(map[string]bool)
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, fmt.Errorf("failed to scan messaged profile: %w", err)
+		}
+		messaged[profileurl.Canonicalize(url)] = true
+	}
+
+	return messaged, nil
+}
+
+// IsProfileContacted reports whether a profile has already been contacted
+func (d *Database) IsProfileContacted(url string) (bool, error) {
+	contacted, err := d.GetContactedProfiles()
+	if err != nil {
+		return false, err
+	}
+	return contacted[profileurl.Canonicalize(url)], nil
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// GetAcceptedProfiles returns the profiles whose connection requests were
+// accepted, with whatever details were scraped for them
+func (d *Database) GetAcceptedProfiles() ([]*Profile, error) {
+	query := `SELECT DISTINCT c.profile_url, COALESCE(p.name, ''), COALESCE(p.title, ''), COALESCE(p.headline, ''),
+			  COALESCE(p.company, ''), COALESCE(p.location, ''), COALESCE(p.search_query, '')
+			  FROM connection_requests c LEFT JOIN profiles p ON p.url = c.profile_url
+			  WHERE c.status = 'accepted' AND c.dry_run = 0 ORDER BY c.profile_url`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accepted profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []*Profile
+	for rows.Next() {
+		var profile Profile
+		if err := rows.Scan(&profile.URL, &profile.Name, &profile.Title, &profile.Headline, &profile.Company,
+			&profile.Location, &profile.SearchQuery); err != nil {
+			return nil, fmt.Errorf("failed to scan accepted profile: %w", err)
+		}
+		profiles = append(profiles, &profile)
+	}
+
+	return profiles, nil
+}
+
+// IsCRMSynced reports whether a profile has already been pushed to a CRM connector
+func (d *Database) IsCRMSynced(connector, profileURL string) (bool, error) {
+	query := `SELECT COUNT(*) FROM crm_syncs WHERE connector = ? AND profile_url = ?`
+
+	var count int
+	if err := d.db.QueryRow(query, connector, profileurl.Canonicalize(profileURL)).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check CRM sync: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// RecordCRMSync records that a profile was pushed to a CRM connector
+func (d *Database) RecordCRMSync(connector, profileURL, externalID string) error {
+	query := `INSERT INTO crm_syncs (connector, profile_url, external_id, synced_at) VALUES (?, ?, ?, ?)
+			  ON CONFLICT(connector, profile_url) DO UPDATE SET
+			  external_id = excluded.external_id, synced_at = excluded.synced_at`
+
+	if _, err := d.db.Exec(query, connector, profileurl.Canonicalize(profileURL), externalID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to record CRM sync: %w", err)
+	}
+
+	d.logger.WithField("connector", connector).WithField("profile_url", profileURL).Debug("CRM sync recorded")
+	return nil
+}
@@ -9,12 +9,18 @@ import (
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/profileurl"
 )
 
-// Database represents the SQLite database connection
+// Database represents the connection to the SQLite, Postgres or MySQL database
 type Database struct {
-	db     *sql.DB
-	logger *logrus.Logger
+	db      *conn
+	backend string
+	source  string
+	logger  *logrus.Logger
+	scorer  func(profile *Profile) int
+	runID   string // Stored with the rows this run creates
 }
 
 // Profile represents a LinkedIn profile
@@ -23,11 +29,24 @@ type Profile struct {
 	URL         string    `json:"url"`
 	Name        string    `json:"name"`
 	Title       string    `json:"title"`
+	Headline    string    `json:"headline"`
 	Company     string    `json:"company"`
 	Location    string    `json:"location"`
+	Industry    string    `json:"industry,omitempty"`
 	SearchQuery string    `json:"search_query"`
+	Source      string    `json:"source,omitempty"` // Where the profile was first found when not by a search, e.g. "also-viewed:<seed URL>"
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	Email          string     `json:"email,omitempty"`            // Work email found by 'enrich emails'
+	EmailProvider  string     `json:"email_provider,omitempty"`   // Email finder that found it
+	EmailCheckedAt *time.Time `json:"email_checked_at,omitempty"` // Last lookup, whether or not it found an address
+	CompanySize    int        `json:"company_size,omitempty"`     // Employees of the company, where a company page scrape found it
+	Score          int        `json:"score"`                      // Fit with the ideal customer profile, from the scoring rules
+	Degree            int  `json:"degree,omitempty"` // 1, 2 or 3 as the latest search result showed it; 0 when unknown
+	MutualConnections int  `json:"mutual_connections,omitempty"`
+	OpenToWork        bool `json:"open_to_work,omitempty"`
+	Hiring            bool `json:"hiring,omitempty"`
+	Premium           bool `json:"premium,omitempty"`
 }
 
 // ConnectionRequest represents a sent connection request
@@ -35,9 +54,14 @@ type ConnectionRequest struct {
 	ID          int       `json:"id"`
 	ProfileURL  string    `json:"profile_url"`
 	Message     string    `json:"message"`
-	Status      string    `json:"status"` // pending, accepted, rejected
+	Status      string    `json:"status"` // pending, accepted, rejected; followed or messaged for fallbacks
 	SentAt      time.Time `json:"sent_at"`
 	AcceptedAt  *time.Time `json:"accepted_at,omitempty"`
+	Campaign    string    `json:"campaign,omitempty"`
+	Variant     string    `json:"variant,omitempty"` // A/B variant of the note that was sent
+	Template    string    `json:"template,omitempty"` // Template the note was rendered from
+	DryRun      bool      `json:"dry_run,omitempty"` // Recorded by a dry run; nothing was sent
+	Action      string    `json:"action,omitempty"` // connect, or follow or message when the profile offered no Connect button
 }
 
 // Message represents a sent message
@@ -45,10 +69,13 @@ type Message struct {
 	ID             int       `json:"id"`
 	RecipientURL   string    `json:"recipient_url"`
 	Content        string    `json:"content"`
-	Type           string    `json:"type"` // connection_note, follow_up
+	Type           string    `json:"type"` // connection_note, follow_up, direct, inmail
+	Subject        string    `json:"subject,omitempty"` // InMail subject line
+	Template       string    `json:"template,omitempty"` // Template the content was rendered from
 	Status         string    `json:"status"` // sent, failed
 	SentAt         time.Time `json:"sent_at"`
 	ConnectionID   *int      `json:"connection_id,omitempty"`
+	DryRun         bool      `json:"dry_run,omitempty"` // Recorded by a dry run; nothing was sent
 }
 
 // SearchSession represents a search session
@@ -59,35 +86,55 @@ type SearchSession struct {
 	CreatedAt   time.Time `json:"created_at"`
 }
 
-// NewDatabase creates a new database connection
+// NewDatabase creates a new SQLite database connection
 func NewDatabase(dbPath string, logger *logrus.Logger) (*Database, error) {
 	// Create directory if it doesn't exist
 	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
 		return nil, fmt.Errorf("failed to create database directory: %w", err)
 	}
 
+	return Open(BackendSQLite, dbPath, logger)
+}
+
+// Open connects to the database of a storage backend. The source is a file
+// path for SQLite and a DSN for Postgres and MySQL, where the tables are
+// created on first use like they are in a new SQLite file.
+func Open(backend, source string, logger *logrus.Logger) (*Database, error) {
+	dialect, err := newDialect(backend)
+	if err != nil {
+		return nil, err
+	}
+	driver, dsn, err := dialect.driver(source)
+	if err != nil {
+		return nil, err
+	}
+
 	// Open database connection
-	db, err := sql.Open("sqlite3", dbPath)
+	db, err := sql.Open(driver, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
 	// Test connection
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
 	database := &Database{
-		db:     db,
-		logger: logger,
+		db:      &conn{db: db, dialect: dialect},
+		backend: backend,
+		source:  source,
+		logger:  logger,
 	}
 
 	// Initialize tables
 	if err := database.initTables(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to initialize tables: %w", err)
 	}
 
-	logger.Info("Database initialized successfully")
+	logger.WithField("backend", backend).Info("Database initialized successfully")
 	return database, nil
 }
 
@@ -96,30 +143,32 @@ func (d *Database) initTables() error {
 	queries := []string{
 		`CREATE TABLE IF NOT EXISTS profiles (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			url TEXT UNIQUE NOT NULL,
+			url VARCHAR(255) UNIQUE NOT NULL,
 			name TEXT,
 			title TEXT,
+			headline TEXT,
 			company TEXT,
 			location TEXT,
 			search_query TEXT,
+			source TEXT,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)`,
 		`CREATE TABLE IF NOT EXISTS connection_requests (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			profile_url TEXT NOT NULL,
+			profile_url VARCHAR(255) NOT NULL,
 			message TEXT,
-			status TEXT DEFAULT 'pending',
+			status VARCHAR(255) DEFAULT 'pending',
 			sent_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			accepted_at DATETIME,
 			FOREIGN KEY (profile_url) REFERENCES profiles(url)
 		)`,
 		`CREATE TABLE IF NOT EXISTS messages (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			recipient_url TEXT NOT NULL,
+			recipient_url VARCHAR(255) NOT NULL,
 			content TEXT NOT NULL,
 			type TEXT NOT NULL,
-			status TEXT DEFAULT 'sent',
+			status VARCHAR(255) DEFAULT 'sent',
 			sent_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			connection_id INTEGER,
 			FOREIGN KEY (connection_id) REFERENCES connection_requests(id)
@@ -130,56 +179,461 @@ func (d *Database) initTables() error {
 			results_count INTEGER DEFAULT 0,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)`,
+		`CREATE TABLE IF NOT EXISTS batch_items (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			batch_id VARCHAR(255) NOT NULL,
+			action TEXT NOT NULL,
+			item_url VARCHAR(255) NOT NULL,
+			status TEXT NOT NULL,
+			error_message TEXT,
+			processed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(batch_id, item_url)
+		)`,
+		`CREATE TABLE IF NOT EXISTS rate_limit_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			action VARCHAR(255) NOT NULL,
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS action_blocks (
+			action VARCHAR(255) PRIMARY KEY,
+			blocked_until DATETIME NOT NULL,
+			reason TEXT,
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS inmail_credits (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			remaining INTEGER NOT NULL,
+			checked_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS templates (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name VARCHAR(255) UNIQUE NOT NULL,
+			kind TEXT NOT NULL,
+			content TEXT NOT NULL,
+			type TEXT,
+			variables TEXT,
+			character_limit INTEGER DEFAULT 0,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS queue_tasks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			kind TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			priority INTEGER DEFAULT 0,
+			status VARCHAR(255) NOT NULL DEFAULT 'pending',
+			attempts INTEGER DEFAULT 0,
+			last_error TEXT,
+			scheduled_at DATETIME NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			finished_at DATETIME
+		)`,
+		`CREATE TABLE IF NOT EXISTS messages_received (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			thread_id VARCHAR(255) NOT NULL,
+			sender_url VARCHAR(255),
+			sender_name TEXT,
+			content TEXT NOT NULL,
+			sent_label TEXT,
+			content_hash VARCHAR(255) NOT NULL,
+			received_at DATETIME NOT NULL,
+			UNIQUE(thread_id, content_hash)
+		)`,
+		`CREATE TABLE IF NOT EXISTS inbox_threads (
+			thread_id VARCHAR(255) PRIMARY KEY,
+			participant_url TEXT,
+			last_snippet TEXT,
+			synced_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS crm_syncs (
+			connector VARCHAR(255) NOT NULL,
+			profile_url VARCHAR(255) NOT NULL,
+			external_id TEXT,
+			synced_at DATETIME NOT NULL,
+			PRIMARY KEY (connector, profile_url)
+		)`,
+		`CREATE TABLE IF NOT EXISTS profile_visits (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			profile_url VARCHAR(255) NOT NULL,
+			dwell_seconds INTEGER NOT NULL DEFAULT 0,
+			visited_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS engagements (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			target_url TEXT NOT NULL,
+			post_urn VARCHAR(255) NOT NULL,
+			action VARCHAR(255) NOT NULL,
+			content TEXT,
+			status TEXT NOT NULL,
+			error_message TEXT,
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS endorsements (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			profile_url VARCHAR(255) NOT NULL,
+			skill VARCHAR(255) NOT NULL,
+			status TEXT NOT NULL,
+			error_message TEXT,
+			dry_run INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS company_posts (
+			post_urn VARCHAR(255) PRIMARY KEY,
+			company_url VARCHAR(255) NOT NULL,
+			author_name TEXT,
+			content TEXT NOT NULL,
+			scraped_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS blacklist (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			kind VARCHAR(255) NOT NULL,
+			value VARCHAR(255) NOT NULL,
+			reason TEXT,
+			created_at DATETIME NOT NULL,
+			UNIQUE(kind, value)
+		)`,
+		`CREATE TABLE IF NOT EXISTS opt_outs (
+			profile_url VARCHAR(255) PRIMARY KEY,
+			thread_id TEXT,
+			phrase TEXT NOT NULL,
+			content TEXT,
+			opted_out_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS sequence_enrollments (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			sequence VARCHAR(255) NOT NULL,
+			profile_url VARCHAR(255) NOT NULL,
+			step INTEGER NOT NULL DEFAULT 0,
+			status VARCHAR(255) NOT NULL,
+			task_id INTEGER,
+			next_run_at DATETIME NOT NULL,
+			last_step_at DATETIME,
+			last_error TEXT,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL,
+			UNIQUE(sequence, profile_url)
+		)`,
+		`CREATE TABLE IF NOT EXISTS audit_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			occurred_at DATETIME NOT NULL,
+			action TEXT NOT NULL,
+			target TEXT,
+			page_url TEXT,
+			profile_url VARCHAR(255),
+			detail TEXT,
+			outcome TEXT NOT NULL,
+			error TEXT,
+			duration_ms INTEGER NOT NULL DEFAULT 0
+		)`,
+		`CREATE TABLE IF NOT EXISTS tags (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name VARCHAR(255) UNIQUE NOT NULL,
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS prospect_tags (
+			tag_id INTEGER NOT NULL,
+			profile_url VARCHAR(255) NOT NULL,
+			created_at DATETIME NOT NULL,
+			PRIMARY KEY (tag_id, profile_url),
+			FOREIGN KEY (tag_id) REFERENCES tags(id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS saved_searches (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name VARCHAR(255) UNIQUE NOT NULL,
+			query TEXT NOT NULL,
+			campaign TEXT,
+			template TEXT,
+			last_run_at DATETIME,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS saved_search_results (
+			search_id INTEGER NOT NULL,
+			profile_url VARCHAR(255) NOT NULL,
+			first_seen_at DATETIME NOT NULL,
+			last_seen_at DATETIME NOT NULL,
+			PRIMARY KEY (search_id, profile_url),
+			FOREIGN KEY (search_id) REFERENCES saved_searches(id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS network_connections (
+			profile_url VARCHAR(255) PRIMARY KEY,
+			name TEXT,
+			headline TEXT,
+			connected_at DATETIME,
+			first_seen_at DATETIME NOT NULL,
+			last_seen_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS invitation_actions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			profile_url VARCHAR(255) NOT NULL,
+			name TEXT,
+			headline TEXT,
+			action VARCHAR(255) NOT NULL,
+			rule TEXT,
+			dry_run INTEGER NOT NULL DEFAULT 0,
+			acted_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS company_urns (
+			query VARCHAR(255) PRIMARY KEY,
+			company_id TEXT NOT NULL,
+			company_name TEXT,
+			resolved_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS account_assignments (
+			profile_url VARCHAR(255) PRIMARY KEY,
+			account VARCHAR(255) NOT NULL,
+			campaign VARCHAR(255) NOT NULL,
+			assigned_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS paused_campaigns (
+			name VARCHAR(255) PRIMARY KEY,
+			paused_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS ai_openers (
+			profile_url VARCHAR(255) NOT NULL,
+			config_key VARCHAR(255) NOT NULL,
+			opener TEXT NOT NULL,
+			generator TEXT,
+			tokens INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL,
+			PRIMARY KEY (profile_url, config_key)
+		)`,
+		`CREATE TABLE IF NOT EXISTS reply_labels (
+			profile_url VARCHAR(255) PRIMARY KEY,
+			label VARCHAR(255) NOT NULL,
+			source VARCHAR(255) NOT NULL,
+			message_id INTEGER,
+			content TEXT,
+			revisit_at DATETIME,
+			labeled_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS ai_opener_previews (
+			config_key VARCHAR(255) PRIMARY KEY,
+			mode VARCHAR(255) NOT NULL,
+			previewed_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS restriction_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			account VARCHAR(255) NOT NULL DEFAULT '',
+			reason TEXT NOT NULL,
+			url TEXT,
+			detected_at DATETIME NOT NULL,
+			cooldown_until DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS warmups (
+			account VARCHAR(255) PRIMARY KEY,
+			started_at DATETIME NOT NULL,
+			week INTEGER NOT NULL,
+			completed_at DATETIME,
+			last_active_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS profile_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			profile_url VARCHAR(255) NOT NULL,
+			field VARCHAR(32) NOT NULL,
+			old_value TEXT NOT NULL,
+			new_value TEXT NOT NULL,
+			changed_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS search_cache (
+			query_key VARCHAR(64) PRIMARY KEY,
+			session TEXT NOT NULL,
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS reminder_nudges (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			profile_url VARCHAR(255) NOT NULL,
+			template TEXT,
+			task_id INTEGER,
+			queued_at DATETIME NOT NULL,
+			run_id VARCHAR(64)
+		)`,
 		`CREATE INDEX IF NOT EXISTS idx_profiles_url ON profiles(url)`,
 		`CREATE INDEX IF NOT EXISTS idx_connection_requests_profile_url ON connection_requests(profile_url)`,
 		`CREATE INDEX IF NOT EXISTS idx_connection_requests_status ON connection_requests(status)`,
 		`CREATE INDEX IF NOT EXISTS idx_messages_recipient_url ON messages(recipient_url)`,
 		`CREATE INDEX IF NOT EXISTS idx_messages_status ON messages(status)`,
+		`CREATE INDEX IF NOT EXISTS idx_batch_items_batch_id ON batch_items(batch_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_rate_limit_events_action_created_at ON rate_limit_events(action, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_queue_tasks_status_scheduled_at ON queue_tasks(status, scheduled_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_messages_received_sender_url ON messages_received(sender_url)`,
+		`CREATE INDEX IF NOT EXISTS idx_profile_visits_profile_url ON profile_visits(profile_url)`,
+		`CREATE INDEX IF NOT EXISTS idx_engagements_post_urn_action ON engagements(post_urn, action)`,
+		`CREATE INDEX IF NOT EXISTS idx_endorsements_profile_url ON endorsements(profile_url)`,
+		`CREATE INDEX IF NOT EXISTS idx_company_posts_company_url ON company_posts(company_url)`,
+		`CREATE INDEX IF NOT EXISTS idx_invitation_actions_acted_at ON invitation_actions(acted_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_sequence_enrollments_status_next_run_at ON sequence_enrollments(status, next_run_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_log_occurred_at ON audit_log(occurred_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_log_profile_url ON audit_log(profile_url)`,
+		`CREATE INDEX IF NOT EXISTS idx_prospect_tags_profile_url ON prospect_tags(profile_url)`,
+		`CREATE INDEX IF NOT EXISTS idx_account_assignments_campaign ON account_assignments(campaign)`,
+		`CREATE INDEX IF NOT EXISTS idx_restriction_events_account_cooldown_until ON restriction_events(account, cooldown_until)`,
+		`CREATE INDEX IF NOT EXISTS idx_profile_history_profile_url_field ON profile_history(profile_url, field)`,
+		`CREATE INDEX IF NOT EXISTS idx_reminder_nudges_profile_url ON reminder_nudges(profile_url)`,
 	}
 
 	for _, query := range queries {
-		if _, err := d.db.Exec(query); err != nil {
+		query = d.db.dialect.schema(query)
+		if _, err := d.db.Exec(query); err != nil && !d.db.dialect.schemaExists(err) {
 			return fmt.Errorf("failed to execute query: %s, error: %w", query, err)
 		}
 	}
 
+	// Add columns introduced after the initial schema
+	if err := d.migrateColumns(); err != nil {
+		return err
+	}
+
 	d.logger.Info("Database tables initialized successfully")
 	return nil
 }
 
+// migrateColumns adds columns that are missing from databases created by older versions
+func (d *Database) migrateColumns() error {
+	columns := []struct {
+		table      string
+		column     string
+		definition string
+	}{
+		{"profiles", "headline", "TEXT"},
+		{"connection_requests", "campaign", "TEXT"},
+		{"connection_requests", "variant", "TEXT"},
+		{"messages", "subject", "TEXT"},
+		{"connection_requests", "dry_run", "INTEGER NOT NULL DEFAULT 0"},
+		{"messages", "dry_run", "INTEGER NOT NULL DEFAULT 0"},
+		{"engagements", "dry_run", "INTEGER NOT NULL DEFAULT 0"},
+		{"connection_requests", "template", "TEXT"},
+		{"messages", "template", "TEXT"},
+		{"profiles", "source", "TEXT"},
+		{"connection_requests", "action", "TEXT"},
+		{"profiles", "email", "TEXT"},
+		{"profiles", "email_provider", "TEXT"},
+		{"profiles", "email_checked_at", "DATETIME"},
+		{"profiles", "company_size", "INTEGER"},
+		{"profiles", "score", "INTEGER"},
+		{"profiles", "degree", "INTEGER"},
+		{"profiles", "mutual_connections", "INTEGER"},
+		{"profiles", "open_to_work", "INTEGER NOT NULL DEFAULT 0"},
+		{"profiles", "hiring", "INTEGER NOT NULL DEFAULT 0"},
+		{"profiles", "premium", "INTEGER NOT NULL DEFAULT 0"},
+		{"saved_searches", "next_page", "INTEGER NOT NULL DEFAULT 0"},
+		{"connection_requests", "run_id", "VARCHAR(64)"},
+		{"messages", "run_id", "VARCHAR(64)"},
+		{"messages_received", "run_id", "VARCHAR(64)"},
+		{"batch_items", "run_id", "VARCHAR(64)"},
+		{"profile_visits", "run_id", "VARCHAR(64)"},
+		{"engagements", "run_id", "VARCHAR(64)"},
+		{"endorsements", "run_id", "VARCHAR(64)"},
+		{"invitation_actions", "run_id", "VARCHAR(64)"},
+		{"profile_history", "run_id", "VARCHAR(64)"},
+		{"queue_tasks", "run_id", "VARCHAR(64)"},
+		{"audit_log", "run_id", "VARCHAR(64)"},
+		{"profiles", "industry", "TEXT"},
+		{"queue_tasks", "claimed_by", "VARCHAR(255)"},
+		{"queue_tasks", "heartbeat_at", "DATETIME"},
+	}
+
+	for _, c := range columns {
+		exists, err := d.columnExists(c.table, c.column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		query := d.db.dialect.schema(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.definition))
+		if _, err := d.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", c.table, c.column, err)
+		}
+		d.logger.WithField("column", c.table+"."+c.column).Info("Database column added")
+	}
+
+	return nil
+}
+
+// columnExists reports whether a table has the given column
+func (d *Database) columnExists(table, column string) (bool, error) {
+	var count int
+	if err := d.db.QueryRow(d.db.dialect.columnQuery(), table, column).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to read table info for %s: %w", table, err)
+	}
+
+	return count > 0, nil
+}
+
 // Close closes the database connection
 func (d *Database) Close() error {
 	return d.db.Close()
 }
 
+// SetRunID makes the rows created from now on record runID, the ID of the
+// command invocation creating them
+func (d *Database) SetRunID(runID string) {
+	d.runID = runID
+}
+
 // SaveProfile saves a profile to the database
 func (d *Database) SaveProfile(profile *Profile) error {
-	query := `INSERT OR REPLACE INTO profiles (url, name, title, company, location, search_query, updated_at) 
-			  VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`
-
-	result, err := d.db.Exec(query, profile.URL, profile.Name, profile.Title, profile.Company, profile.Location, profile.SearchQuery)
-	if err != nil {
+	profile.URL = profileurl.Canonicalize(profile.URL)
+
+	// The first recorded source is kept, so a profile's provenance is where it was first found
+	// A company size and an industry are kept until a scrape finds new ones
+	query := `INSERT INTO profiles (url, name, title, headline, company, location, search_query, source, company_size, industry, updated_at) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, 0), NULLIF(?, ''), CURRENT_TIMESTAMP)
+			  ON CONFLICT(url) DO UPDATE SET
+			  name = excluded.name, title = excluded.title, headline = excluded.headline, company = excluded.company,
+			  location = excluded.location, search_query = excluded.search_query,
+			  source = COALESCE(profiles.source, excluded.source),
+			  company_size = COALESCE(excluded.company_size, profiles.company_size),
+			  industry = COALESCE(excluded.industry, profiles.industry), updated_at = CURRENT_TIMESTAMP`
+
+	if _, err := d.db.Exec(query, profile.URL, profile.Name, profile.Title, profile.Headline, profile.Company, profile.Location, profile.SearchQuery, profile.Source, profile.CompanySize, profile.Industry); err != nil {
 		return fmt.Errorf("failed to save profile: %w", err)
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
+	// The upsert keeps the ID of an existing profile, which not every backend reports back
+	if err := d.db.QueryRow(`SELECT id FROM profiles WHERE url = ?`, profile.URL).Scan(&profile.ID); err != nil {
 		return fmt.Errorf("failed to get profile ID: %w", err)
 	}
 
-	profile.ID = int(id)
+	if d.scorer != nil {
+		if err := d.scoreProfile(profile.URL); err != nil {
+			return err
+		}
+	}
+
 	d.logger.WithField("profile_url", profile.URL).Debug("Profile saved")
 	return nil
 }
 
+// SaveProfileInsights records the degree, mutual connections and badges a
+// search result showed for the profile
+func (d *Database) SaveProfileInsights(profile *Profile) error {
+	query := `UPDATE profiles SET degree = NULLIF(?, 0), mutual_connections = ?, open_to_work = ?, hiring = ?, premium = ?
+			  WHERE url = ?`
+
+	if _, err := d.db.Exec(query, profile.Degree, profile.MutualConnections, profile.OpenToWork, profile.Hiring,
+		profile.Premium, profileurl.Canonicalize(profile.URL)); err != nil {
+		return fmt.Errorf("failed to save profile insights: %w", err)
+	}
+	return nil
+}
+
 // GetProfile retrieves a profile by URL
 func (d *Database) GetProfile(url string) (*Profile, error) {
-	query := `SELECT id, url, name, title, company, location, search_query, created_at, updated_at 
+	query := `SELECT id, url, COALESCE(name, ''), COALESCE(title, ''), COALESCE(headline, ''), COALESCE(company, ''),
+			  COALESCE(location, ''), COALESCE(search_query, ''), COALESCE(source, ''), created_at, updated_at,
+			  COALESCE(email, ''), COALESCE(email_provider, ''), email_checked_at, COALESCE(company_size, 0), COALESCE(score, 0),
+			  COALESCE(degree, 0), COALESCE(mutual_connections, 0), open_to_work, hiring, premium, COALESCE(industry, '')
 			  FROM profiles WHERE url = ?`
 
-	row := d.db.QueryRow(query, url)
+	row := d.db.QueryRow(query, profileurl.Canonicalize(url))
 	var profile Profile
-	err := row.Scan(&profile.ID, &profile.URL, &profile.Name, &profile.Title, &profile.Company, &profile.Location, &profile.SearchQuery, &profile.CreatedAt, &profile.UpdatedAt)
+	err := row.Scan(&profile.ID, &profile.URL, &profile.Name, &profile.Title, &profile.Headline, &profile.Company, &profile.Location, &profile.SearchQuery, &profile.Source, &profile.CreatedAt, &profile.UpdatedAt,
+		&profile.Email, &profile.EmailProvider, &profile.EmailCheckedAt, &profile.CompanySize, &profile.Score,
+		&profile.Degree, &profile.MutualConnections, &profile.OpenToWork, &profile.Hiring, &profile.Premium, &profile.Industry)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -192,17 +646,15 @@ func (d *Database) GetProfile(url string) (*Profile, error) {
 
 // SaveConnectionRequest saves a connection request
 func (d *Database) SaveConnectionRequest(request *ConnectionRequest) error {
-	query := `INSERT INTO connection_requests (profile_url, message, status, sent_at) 
-			  VALUES (?, ?, ?, ?)`
+	query := `INSERT INTO connection_requests (profile_url, message, status, sent_at, campaign, variant, template, dry_run, action, run_id) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''))`
 
-	result, err := d.db.Exec(query, request.ProfileURL, request.Message, request.Status, request.SentAt)
-	if err != nil {
-		return fmt.Errorf("failed to save connection request: %w", err)
-	}
+	request.ProfileURL = profileurl.Canonicalize(request.ProfileURL)
 
-	id, err := result.LastInsertId()
+	id, err := d.db.insert(query, request.ProfileURL, request.Message, request.Status, request.SentAt,
+		request.Campaign, request.Variant, request.Template, request.DryRun, request.Action, d.runID)
 	if err != nil {
-		return fmt.Errorf("failed to get connection request ID: %w", err)
+		return fmt.Errorf("failed to save connection request: %w", err)
 	}
 
 	request.ID = int(id)
@@ -213,7 +665,7 @@ func (d *Database) SaveConnectionRequest(request *ConnectionRequest) error {
 // GetPendingConnectionRequests retrieves all pending connection requests
 func (d *Database) GetPendingConnectionRequests() ([]*ConnectionRequest, error) {
 	query := `SELECT id, profile_url, message, status, sent_at, accepted_at 
-			  FROM connection_requests WHERE status = 'pending'`
+			  FROM connection_requests WHERE status = 'pending' AND dry_run = 0`
 
 	rows, err := d.db.Query(query)
 	if err != nil {
@@ -261,17 +713,14 @@ func (d *Database) UpdateConnectionRequestStatus(id int, status string) error {
 
 // SaveMessage saves a message
 func (d *Database) SaveMessage(message *Message) error {
-	query := `INSERT INTO messages (recipient_url, content, type, status, sent_at, connection_id) 
-			  VALUES (?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO messages (recipient_url, content, type, status, sent_at, connection_id, subject, template, dry_run, run_id) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''))`
 
-	result, err := d.db.Exec(query, message.RecipientURL, message.Content, message.Type, message.Status, message.SentAt, message.ConnectionID)
-	if err != nil {
-		return fmt.Errorf("failed to save message: %w", err)
-	}
+	message.RecipientURL = profileurl.Canonicalize(message.RecipientURL)
 
-	id, err := result.LastInsertId()
+	id, err := d.db.insert(query, message.RecipientURL, message.Content, message.Type, message.Status, message.SentAt, message.ConnectionID, message.Subject, message.Template, message.DryRun, d.runID)
 	if err != nil {
-		return fmt.Errorf("failed to get message ID: %w", err)
+		return fmt.Errorf("failed to save message: %w", err)
 	}
 
 	message.ID = int(id)
@@ -282,9 +731,9 @@ func (d *Database) SaveMessage(message *Message) error {
 // GetMessagesByRecipient retrieves all messages for a recipient
 func (d *Database) GetMessagesByRecipient(recipientURL string) ([]*Message, error) {
 	query := `SELECT id, recipient_url, content, type, status, sent_at, connection_id 
-			  FROM messages WHERE recipient_url = ? ORDER BY sent_at DESC`
+			  FROM messages WHERE recipient_url = ? AND dry_run = 0 ORDER BY sent_at DESC`
 
-	rows, err := d.db.Query(query, recipientURL)
+	rows, err := d.db.Query(query, profileurl.Canonicalize(recipientURL))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get messages: %w", err)
 	}
@@ -303,21 +752,29 @@ func (d *Database) GetMessagesByRecipient(recipientURL string) ([]*Message, erro
 	return messages, nil
 }
 
+// HasMessageSince reports whether a message of the given type and template has
+// been sent to the recipient since the given time
+func (d *Database) HasMessageSince(recipientURL, messageType, template string, since time.Time) (bool, error) {
+	query := `SELECT COUNT(*) FROM messages
+			  WHERE recipient_url = ? AND type = ? AND COALESCE(template, '') = ? AND dry_run = 0 AND sent_at >= ?`
+
+	var count int
+	if err := d.db.QueryRow(query, profileurl.Canonicalize(recipientURL), messageType, template, since).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check messages: %w", err)
+	}
+	return count > 0, nil
+}
+
 // SaveSearchSession saves a search session
 func (d *Database) SaveSearchSession(session *SearchSession) error {
 	query := `INSERT INTO search_sessions (query, results_count, created_at) 
 			  VALUES (?, ?, ?)`
 
-	result, err := d.db.Exec(query, session.Query, session.ResultsCount, session.CreatedAt)
+	id, err := d.db.insert(query, session.Query, session.ResultsCount, session.CreatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to save search session: %w", err)
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get search session ID: %w", err)
-	}
-
 	session.ID = int(id)
 	d.logger.WithField("query", session.Query).Debug("Search session saved")
 	return nil
@@ -327,9 +784,9 @@ func (d *Database) SaveSearchSession(session *SearchSession) error {
 func (d *Database) GetDailyStats(date time.Time) (map[string]int, error) {
 	query := `
 		SELECT 
-			(SELECT COUNT(*) FROM connection_requests WHERE DATE(sent_at) = DATE(?)) as connections_sent,
-			(SELECT COUNT(*) FROM connection_requests WHERE status = 'accepted' AND DATE(accepted_at) = DATE(?)) as connections_accepted,
-			(SELECT COUNT(*) FROM messages WHERE DATE(sent_at) = DATE(?)) as messages_sent
+			(SELECT COUNT(*) FROM connection_requests WHERE dry_run = 0 AND COALESCE(action, 'connect') = 'connect' AND DATE(sent_at) = DATE(?)) as connections_sent,
+			(SELECT COUNT(*) FROM connection_requests WHERE status = 'accepted' AND dry_run = 0 AND DATE(accepted_at) = DATE(?)) as connections_accepted,
+			(SELECT COUNT(*) FROM messages WHERE dry_run = 0 AND DATE(sent_at) = DATE(?)) as messages_sent
 	`
 
 	row := d.db.QueryRow(query, date, date, date)
@@ -385,7 +842,10 @@ func (d *Database) ExportData() (map[string]interface{}, error) {
 
 // Helper methods for data export
 func (d *Database) getAllProfiles() ([]*Profile, error) {
-	query := `SELECT id, url, name, title, company, location, search_query, created_at, updated_at FROM profiles`
+	query := `SELECT id, url, COALESCE(name, ''), COALESCE(title, ''), COALESCE(headline, ''), COALESCE(company, ''),
+			  COALESCE(location, ''), COALESCE(search_query, ''), COALESCE(source, ''), created_at, updated_at,
+			  COALESCE(email, ''), COALESCE(email_provider, ''), email_checked_at, COALESCE(company_size, 0), COALESCE(score, 0),
+			  COALESCE(degree, 0), COALESCE(mutual_connections, 0), open_to_work, hiring, premium FROM profiles`
 	rows, err := d.db.Query(query)
 	if err != nil {
 		return nil, err
@@ -395,7 +855,9 @@ func (d *Database) getAllProfiles() ([]*Profile, error) {
 	var profiles []*Profile
 	for rows.Next() {
 		var profile Profile
-		err := rows.Scan(&profile.ID, &profile.URL, &profile.Name, &profile.Title, &profile.Company, &profile.Location, &profile.SearchQuery, &profile.CreatedAt, &profile.UpdatedAt)
+		err := rows.Scan(&profile.ID, &profile.URL, &profile.Name, &profile.Title, &profile.Headline, &profile.Company, &profile.Location, &profile.SearchQuery, &profile.Source, &profile.CreatedAt, &profile.UpdatedAt,
+			&profile.Email, &profile.EmailProvider, &profile.EmailCheckedAt, &profile.CompanySize, &profile.Score,
+			&profile.Degree, &profile.MutualConnections, &profile.OpenToWork, &profile.Hiring, &profile.Premium)
 		if err != nil {
 			return nil, err
 		}
@@ -405,7 +867,8 @@ func (d *Database) getAllProfiles() ([]*Profile, error) {
 }
 
 func (d *Database) getAllConnectionRequests() ([]*ConnectionRequest, error) {
-	query := `SELECT id, profile_url, message, status, sent_at, accepted_at FROM connection_requests`
+	query := `SELECT id, profile_url, COALESCE(message, ''), status, sent_at, accepted_at, COALESCE(campaign, ''),
+			  COALESCE(variant, ''), COALESCE(action, '') FROM connection_requests WHERE dry_run = 0`
 	rows, err := d.db.Query(query)
 	if err != nil {
 		return nil, err
@@ -415,7 +878,8 @@ func (d *Database) getAllConnectionRequests() ([]*ConnectionRequest, error) {
 	var requests []*ConnectionRequest
 	for rows.Next() {
 		var request ConnectionRequest
-		err := rows.Scan(&request.ID, &request.ProfileURL, &request.Message, &request.Status, &request.SentAt, &request.AcceptedAt)
+		err := rows.Scan(&request.ID, &request.ProfileURL, &request.Message, &request.Status, &request.SentAt, &request.AcceptedAt,
+			&request.Campaign, &request.Variant, &request.Action)
 		if err != nil {
 			return nil, err
 		}
@@ -425,7 +889,7 @@ func (d *Database) getAllConnectionRequests() ([]*ConnectionRequest, error) {
 }
 
 func (d *Database) getAllMessages() ([]*Message, error) {
-	query := `SELECT id, recipient_url, content, type, status, sent_at, connection_id FROM messages`
+	query := `SELECT id, recipient_url, content, type, status, sent_at, connection_id FROM messages WHERE dry_run = 0`
 	rows, err := d.db.Query(query)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,211 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+)
+
+// Storage backends
+const (
+	BackendSQLite   = "sqlite"
+	BackendPostgres = "postgres"
+	BackendMySQL    = "mysql"
+)
+
+// dialect adapts the SQLite-flavoured SQL written throughout this package to
+// a backend, so queries only need to be written once
+type dialect interface {
+	// driver names the database/sql driver and builds its data source name
+	driver(source string) (string, string, error)
+	// schema rewrites a CREATE TABLE or CREATE INDEX statement
+	schema(statement string) string
+	// schemaExists reports whether a failed schema statement only means the
+	// table or index already exists
+	schemaExists(err error) bool
+	// rewrite adapts a query and its arguments
+	rewrite(query string, args []interface{}) (string, []interface{})
+	// columnQuery counts the columns of a table (first argument) with a name (second argument)
+	columnQuery() string
+	// returningID reports whether inserts return their row ID through RETURNING
+	// instead of the driver's LastInsertId
+	returningID() bool
+	// lockClause is appended to a SELECT that claims rows within a transaction,
+	// so concurrent workers skip rows another one is claiming
+	lockClause() string
+}
+
+// newDialect returns the dialect of a backend
+func newDialect(backend string) (dialect, error) {
+	switch strings.ToLower(backend) {
+	case "", BackendSQLite, "sqlite3":
+		return sqliteDialect{}, nil
+	case BackendPostgres, "postgresql":
+		return postgresDialect{}, nil
+	case BackendMySQL:
+		return mysqlDialect{}, nil
+	}
+	return nil, fmt.Errorf("unsupported storage type %q (use %s, %s or %s)", backend, BackendSQLite, BackendPostgres, BackendMySQL)
+}
+
+var (
+	autoincrementPattern = regexp.MustCompile(`INTEGER PRIMARY KEY AUTOINCREMENT`)
+	foreignKeyPattern    = regexp.MustCompile(`,\s*FOREIGN KEY \([^)]*\) REFERENCES \w+\([^)]*\)`)
+	datetimePattern      = regexp.MustCompile(`\bDATETIME\b`)
+	bareDatetimePattern  = regexp.MustCompile(`\bDATETIME\b([^(]|$)`)
+	insertIgnorePattern  = regexp.MustCompile(`^(\s*)INSERT OR IGNORE INTO`)
+	onConflictPattern    = regexp.MustCompile(`ON CONFLICT\s*\([^)]*\)\s*DO UPDATE SET`)
+	excludedPattern      = regexp.MustCompile(`excluded\.(\w+)`)
+	dateParamPattern     = regexp.MustCompile(`DATE\(\?\)`)
+)
+
+// sqliteDialect is the SQL the package is written in
+type sqliteDialect struct{}
+
+func (sqliteDialect) driver(source string) (string, string, error) {
+	return "sqlite3", source, nil
+}
+
+func (sqliteDialect) schema(statement string) string { return statement }
+
+func (sqliteDialect) schemaExists(err error) bool { return false }
+
+func (sqliteDialect) rewrite(query string, args []interface{}) (string, []interface{}) {
+	return query, args
+}
+
+func (sqliteDialect) columnQuery() string {
+	return `SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`
+}
+
+func (sqliteDialect) returningID() bool { return false }
+
+// SQLite locks the whole database for writing, so claims never overlap
+func (sqliteDialect) lockClause() string { return "" }
+
+// postgresDialect targets PostgreSQL 9.5 or later, for ON CONFLICT and SKIP LOCKED
+type postgresDialect struct{}
+
+func (postgresDialect) driver(source string) (string, string, error) {
+	if source == "" {
+		return "", "", fmt.Errorf("storage.dsn is required for %s", BackendPostgres)
+	}
+	return "postgres", source, nil
+}
+
+func (postgresDialect) schema(statement string) string {
+	statement = autoincrementPattern.ReplaceAllString(statement, "SERIAL PRIMARY KEY")
+	statement = datetimePattern.ReplaceAllString(statement, "TIMESTAMPTZ")
+	// SQLite never enforced foreign keys; keep accepting rows for profiles not yet stored
+	return foreignKeyPattern.ReplaceAllString(statement, "")
+}
+
+func (postgresDialect) schemaExists(err error) bool { return false }
+
+func (postgresDialect) rewrite(query string, args []interface{}) (string, []interface{}) {
+	if insertIgnorePattern.MatchString(query) {
+		query = insertIgnorePattern.ReplaceAllString(query, "${1}INSERT INTO") + " ON CONFLICT DO NOTHING"
+	}
+	// DATE() of an untyped parameter is ambiguous between date and timestamp
+	query = dateParamPattern.ReplaceAllString(query, "CAST(? AS DATE)")
+	return bindNumbered(query), intBools(args)
+}
+
+func (postgresDialect) columnQuery() string {
+	return `SELECT COUNT(*) FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?`
+}
+
+func (postgresDialect) returningID() bool { return true }
+
+func (postgresDialect) lockClause() string { return " FOR UPDATE SKIP LOCKED" }
+
+// mysqlDialect targets MySQL 8.0 or later, for SKIP LOCKED
+type mysqlDialect struct{}
+
+// driver makes times come back as time.Time and be stored in UTC, as they are with SQLite
+func (mysqlDialect) driver(source string) (string, string, error) {
+	if source == "" {
+		return "", "", fmt.Errorf("storage.dsn is required for %s", BackendMySQL)
+	}
+	cfg, err := mysql.ParseDSN(source)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid storage.dsn: %w", err)
+	}
+	cfg.ParseTime = true
+	cfg.Loc = time.UTC
+	// Report the rows an UPDATE matched, as the other backends do, not only those it changed
+	cfg.ClientFoundRows = true
+	return "mysql", cfg.FormatDSN(), nil
+}
+
+func (mysqlDialect) schema(statement string) string {
+	statement = autoincrementPattern.ReplaceAllString(statement, "INTEGER PRIMARY KEY AUTO_INCREMENT")
+	statement = strings.ReplaceAll(statement, "DATETIME DEFAULT CURRENT_TIMESTAMP", "DATETIME(6) DEFAULT CURRENT_TIMESTAMP(6)")
+	statement = bareDatetimePattern.ReplaceAllString(statement, "DATETIME(6)$1")
+	statement = strings.Replace(statement, "CREATE INDEX IF NOT EXISTS", "CREATE INDEX", 1)
+	return foreignKeyPattern.ReplaceAllString(statement, "")
+}
+
+// MySQL has no CREATE INDEX IF NOT EXISTS, so an existing index fails with a duplicate key name
+func (mysqlDialect) schemaExists(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1061
+}
+
+func (mysqlDialect) rewrite(query string, args []interface{}) (string, []interface{}) {
+	query = insertIgnorePattern.ReplaceAllString(query, "${1}INSERT IGNORE INTO")
+	if onConflictPattern.MatchString(query) {
+		query = onConflictPattern.ReplaceAllString(query, "ON DUPLICATE KEY UPDATE")
+		query = excludedPattern.ReplaceAllString(query, "VALUES($1)")
+	}
+	return query, args
+}
+
+func (mysqlDialect) columnQuery() string {
+	return `SELECT COUNT(*) FROM information_schema.columns
+			WHERE table_schema = DATABASE() AND table_name = ? AND column_name = ?`
+}
+
+func (mysqlDialect) returningID() bool { return false }
+
+func (mysqlDialect) lockClause() string { return " FOR UPDATE SKIP LOCKED" }
+
+// bindNumbered turns ? placeholders outside string literals into $1, $2, ...
+func bindNumbered(query string) string {
+	var b strings.Builder
+	n := 0
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case r == '?' && !quoted:
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// intBools stores booleans as the 0 and 1 that the INTEGER flag columns hold
+func intBools(args []interface{}) []interface{} {
+	converted := make([]interface{}, len(args))
+	for i, arg := range args {
+		converted[i] = arg
+		if value, ok := arg.(bool); ok {
+			converted[i] = 0
+			if value {
+				converted[i] = 1
+			}
+		}
+	}
+	return converted
+}
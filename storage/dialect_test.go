@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"io"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestBindNumbered(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"no placeholders", `SELECT COUNT(*) FROM profiles`, `SELECT COUNT(*) FROM profiles`},
+		{"one", `SELECT * FROM profiles WHERE url = ?`, `SELECT * FROM profiles WHERE url = $1`},
+		{"several", `UPDATE t SET a = ?, b = ? WHERE id = ?`, `UPDATE t SET a = $1, b = $2 WHERE id = $3`},
+		{"inside a string literal", `SELECT '?' FROM t WHERE a = ?`, `SELECT '?' FROM t WHERE a = $1`},
+		{"after an escaped quote", `SELECT 'it''s ?' FROM t WHERE a = ?`, `SELECT 'it''s ?' FROM t WHERE a = $1`},
+		{"in a LIKE pattern", `WHERE payload LIKE ? OR payload LIKE '%?%'`, `WHERE payload LIKE $1 OR payload LIKE '%?%'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bindNumbered(tt.query); got != tt.want {
+				t.Errorf("bindNumbered(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntBools(t *testing.T) {
+	args := []interface{}{true, false, 3, "true", nil}
+	want := []interface{}{1, 0, 3, "true", nil}
+	if got := intBools(args); !reflect.DeepEqual(got, want) {
+		t.Errorf("intBools(%v) = %v, want %v", args, got, want)
+	}
+	if args[0] != true {
+		t.Errorf("intBools changed its argument")
+	}
+}
+
+func TestNewDialect(t *testing.T) {
+	tests := []struct {
+		backend string
+		want    dialect
+		wantErr bool
+	}{
+		{"", sqliteDialect{}, false},
+		{"sqlite", sqliteDialect{}, false},
+		{"sqlite3", sqliteDialect{}, false},
+		{"Postgres", postgresDialect{}, false},
+		{"postgresql", postgresDialect{}, false},
+		{"mysql", mysqlDialect{}, false},
+		{"oracle", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.backend, func(t *testing.T) {
+			got, err := newDialect(tt.backend)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newDialect(%q) error = %v, wantErr %v", tt.backend, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("newDialect(%q) = %T, want %T", tt.backend, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgresRewrite(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		args      []interface{}
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "placeholders and booleans",
+			query:     `UPDATE messages SET dry_run = ? WHERE id = ?`,
+			args:      []interface{}{true, 7},
+			wantQuery: `UPDATE messages SET dry_run = $1 WHERE id = $2`,
+			wantArgs:  []interface{}{1, 7},
+		},
+		{
+			name:      "insert or ignore",
+			query:     `INSERT OR IGNORE INTO tags (name, created_at) VALUES (?, ?)`,
+			args:      []interface{}{"a", "b"},
+			wantQuery: `INSERT INTO tags (name, created_at) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			wantArgs:  []interface{}{"a", "b"},
+		},
+		{
+			name:      "indented insert or ignore",
+			query:     "\n\t\tINSERT OR IGNORE INTO tags (name) VALUES (?)",
+			args:      []interface{}{"a"},
+			wantQuery: "\n\t\tINSERT INTO tags (name) VALUES ($1) ON CONFLICT DO NOTHING",
+			wantArgs:  []interface{}{"a"},
+		},
+		{
+			name:      "date of a parameter",
+			query:     `SELECT COUNT(*) FROM messages WHERE DATE(sent_at) = DATE(?)`,
+			args:      []interface{}{"2024-03-07"},
+			wantQuery: `SELECT COUNT(*) FROM messages WHERE DATE(sent_at) = CAST($1 AS DATE)`,
+			wantArgs:  []interface{}{"2024-03-07"},
+		},
+		{
+			name:      "upsert kept",
+			query:     `INSERT INTO crm_syncs (connector, profile_url) VALUES (?, ?) ON CONFLICT (connector, profile_url) DO UPDATE SET external_id = excluded.external_id`,
+			args:      []interface{}{"hubspot", "u"},
+			wantQuery: `INSERT INTO crm_syncs (connector, profile_url) VALUES ($1, $2) ON CONFLICT (connector, profile_url) DO UPDATE SET external_id = excluded.external_id`,
+			wantArgs:  []interface{}{"hubspot", "u"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := postgresDialect{}.rewrite(tt.query, tt.args)
+			if query != tt.wantQuery {
+				t.Errorf("query = %q, want %q", query, tt.wantQuery)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestMySQLRewrite(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "placeholders kept",
+			query: `SELECT * FROM profiles WHERE url = ?`,
+			want:  `SELECT * FROM profiles WHERE url = ?`,
+		},
+		{
+			name:  "insert or ignore",
+			query: `INSERT OR IGNORE INTO tags (name) VALUES (?)`,
+			want:  `INSERT IGNORE INTO tags (name) VALUES (?)`,
+		},
+		{
+			name:  "upsert",
+			query: `INSERT INTO crm_syncs (connector, profile_url, external_id) VALUES (?, ?, ?) ON CONFLICT (connector, profile_url) DO UPDATE SET external_id = excluded.external_id, synced_at = excluded.synced_at`,
+			want:  `INSERT INTO crm_syncs (connector, profile_url, external_id) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE external_id = VALUES(external_id), synced_at = VALUES(synced_at)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := []interface{}{true}
+			query, gotArgs := mysqlDialect{}.rewrite(tt.query, args)
+			if query != tt.want {
+				t.Errorf("query = %q, want %q", query, tt.want)
+			}
+			if !reflect.DeepEqual(gotArgs, args) {
+				t.Errorf("args = %v, want them unchanged", gotArgs)
+			}
+		})
+	}
+}
+
+func TestSchemaRewrites(t *testing.T) {
+	table := `CREATE TABLE IF NOT EXISTS messages (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			sent_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			finished_at DATETIME,
+			connection_id INTEGER,
+			FOREIGN KEY (connection_id) REFERENCES connection_requests(id)
+		)`
+	index := `CREATE INDEX IF NOT EXISTS idx_messages_status ON messages(status)`
+
+	tests := []struct {
+		name      string
+		dialect   dialect
+		statement string
+		want      string
+	}{
+		{"sqlite table", sqliteDialect{}, table, table},
+		{"postgres table", postgresDialect{}, table, `CREATE TABLE IF NOT EXISTS messages (
+			id SERIAL PRIMARY KEY,
+			sent_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+			finished_at TIMESTAMPTZ,
+			connection_id INTEGER
+		)`},
+		{"postgres index", postgresDialect{}, index, index},
+		{"mysql table", mysqlDialect{}, table, `CREATE TABLE IF NOT EXISTS messages (
+			id INTEGER PRIMARY KEY AUTO_INCREMENT,
+			sent_at DATETIME(6) DEFAULT CURRENT_TIMESTAMP(6),
+			finished_at DATETIME(6),
+			connection_id INTEGER
+		)`},
+		{"mysql index", mysqlDialect{}, index, `CREATE INDEX idx_messages_status ON messages(status)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.dialect.schema(tt.statement); got != tt.want {
+				t.Errorf("schema() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeferTaskKeepsAttempts(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	db, err := NewDatabase(filepath.Join(t.TempDir(), "test.db"), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	task := &QueueTask{Kind: "message", Payload: "{}"}
+	if err := db.EnqueueTask(task); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, wantAttempts := range []int{0, 0} {
+		claimed, err := db.ClaimNextTask(time.Now().Add(time.Minute), "worker-a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if claimed == nil || claimed.ID != task.ID {
+			t.Fatalf("ClaimNextTask() = %v, want task %d", claimed, task.ID)
+		}
+		if err := db.DeferTask(task.ID, time.Now(), "rate limited"); err != nil {
+			t.Fatalf("DeferTask() error = %v", err)
+		}
+		deferred, err := db.GetTask(task.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if deferred.Status != TaskPending || deferred.Attempts != wantAttempts {
+			t.Errorf("after DeferTask: status %s, attempts %d; want %s, %d", deferred.Status, deferred.Attempts, TaskPending, wantAttempts)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// SaveProfileEmail records an email lookup for a profile. An empty email only
+// records that the profile was looked up, keeping any address found before.
+func (d *Database) SaveProfileEmail(profileURL, email, provider string) error {
+	query := `UPDATE profiles SET email = ?, email_provider = ?, email_checked_at = ? WHERE url = ?`
+	args := []interface{}{email, provider, time.Now().UTC(), profileurl.Canonicalize(profileURL)}
+	if email == "" {
+		query = `UPDATE profiles SET email_checked_at = ? WHERE url = ?`
+		args = args[2:]
+	}
+
+	if _, err := d.db.Exec(query, args...); err != nil {
+		return fmt.Errorf("failed to save profile email: %w", err)
+	}
+
+	d.logger.WithField("profile_url", profileURL).WithField("found", email != "").Debug("Profile email lookup saved")
+	return nil
+}
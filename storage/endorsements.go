@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// Endorsement represents an attempt to endorse a connection's skill
+type Endorsement struct {
+	ID           int       `json:"id"`
+	ProfileURL   string    `json:"profile_url"`
+	Skill        string    `json:"skill"`
+	Status       string    `json:"status"` // done, failed
+	ErrorMessage string    `json:"error_message,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	DryRun       bool      `json:"dry_run,omitempty"` // Recorded by a dry run; nothing was endorsed on LinkedIn
+}
+
+// SaveEndorsement records an endorsement attempt
+func (d *Database) SaveEndorsement(endorsement *Endorsement) error {
+	endorsement.ProfileURL = profileurl.Canonicalize(endorsement.ProfileURL)
+	if endorsement.CreatedAt.IsZero() {
+		endorsement.CreatedAt = time.Now()
+	}
+
+	query := `INSERT INTO endorsements (profile_url, skill, status, error_message, created_at, dry_run, run_id)
+			  VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''))`
+
+	id, err := d.db.insert(query, endorsement.ProfileURL, endorsement.Skill, endorsement.Status,
+		endorsement.ErrorMessage, endorsement.CreatedAt.UTC(), endorsement.DryRun, d.runID)
+	if err != nil {
+		return fmt.Errorf("failed to save endorsement: %w", err)
+	}
+
+	endorsement.ID = int(id)
+	d.logger.WithField("profile_url", endorsement.ProfileURL).WithField("skill", endorsement.Skill).Debug("Endorsement saved")
+	return nil
+}
+
+// HasEndorsed reports whether a connection's skill was already endorsed
+func (d *Database) HasEndorsed(profileURL, skill string) (bool, error) {
+	query := `SELECT COUNT(*) FROM endorsements WHERE profile_url = ? AND LOWER(skill) = ? AND status = ? AND dry_run = 0`
+
+	var count int
+	if err := d.db.QueryRow(query, profileurl.Canonicalize(profileURL), strings.ToLower(skill), EngagementDone).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check endorsements: %w", err)
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// Engagement statuses
+const (
+	EngagementDone   = "done"
+	EngagementFailed = "failed"
+)
+
+// Engagement represents a like or comment on a post
+type Engagement struct {
+	ID           int       `json:"id"`
+	TargetURL    string    `json:"target_url"` // Profile or company page whose post was engaged with
+	PostURN      string    `json:"post_urn"`
+	Action       string    `json:"action"` // like, comment
+	Content      string    `json:"content,omitempty"`
+	Status       string    `json:"status"` // done, failed
+	ErrorMessage string    `json:"error_message,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	DryRun       bool      `json:"dry_run,omitempty"` // Recorded by a dry run; nothing was done on LinkedIn
+}
+
+// SaveEngagement records a like or comment attempt
+func (d *Database) SaveEngagement(engagement *Engagement) error {
+	engagement.TargetURL = profileurl.Canonicalize(engagement.TargetURL)
+	if engagement.CreatedAt.IsZero() {
+		engagement.CreatedAt = time.Now()
+	}
+
+	query := `INSERT INTO engagements (target_url, post_urn, action, content, status, error_message, created_at, dry_run, run_id)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''))`
+
+	id, err := d.db.insert(query, engagement.TargetURL, engagement.PostURN, engagement.Action, engagement.Content,
+		engagement.Status, engagement.ErrorMessage, engagement.CreatedAt.UTC(), engagement.DryRun, d.runID)
+	if err != nil {
+		return fmt.Errorf("failed to save engagement: %w", err)
+	}
+
+	engagement.ID = int(id)
+	d.logger.WithField("post_urn", engagement.PostURN).WithField("action", engagement.Action).Debug("Engagement saved")
+	return nil
+}
+
+// HasEngaged reports whether an action was already completed on a post
+func (d *Database) HasEngaged(postURN, action string) (bool, error) {
+	query := `SELECT COUNT(*) FROM engagements WHERE post_urn = ? AND action = ? AND status = ? AND dry_run = 0`
+
+	var count int
+	if err := d.db.QueryRow(query, postURN, action, EngagementDone).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check engagements: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// CountEngagements returns the number of completed engagements per action since the given time
+func (d *Database) CountEngagements(since time.Time) (map[string]int, error) {
+	rows, err := d.db.Query(`SELECT action, COUNT(*) FROM engagements WHERE status = ? AND dry_run = 0 AND created_at >= ? GROUP BY action`,
+		EngagementDone, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to count engagements: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var action string
+		var count int
+		if err := rows.Scan(&action, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan engagement count: %w", err)
+		}
+		counts[action] = count
+	}
+
+	return counts, nil
+}
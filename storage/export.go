@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExportFilter restricts exported records to a time range; zero bounds are open
+type ExportFilter struct {
+	Since    time.Time       // Inclusive
+	Until    time.Time       // Exclusive
+	Profiles map[string]bool // When set, only records of these profiles, e.g. those with a tag
+}
+
+// Includes reports whether t falls within the filter's range
+func (f ExportFilter) Includes(t time.Time) bool {
+	if !f.Since.IsZero() && t.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !t.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// IncludesProfile reports whether records of a profile are exported
+func (f ExportFilter) IncludesProfile(profileURL string) bool {
+	return f.Profiles == nil || f.Profiles[profileURL]
+}
+
+// ExportProfiles returns the profiles first saved within the filter's range
+func (d *Database) ExportProfiles(filter ExportFilter) ([]*Profile, error) {
+	profiles, err := d.getAllProfiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to export profiles: %w", err)
+	}
+
+	filtered := make([]*Profile, 0, len(profiles))
+	for _, profile := range profiles {
+		if filter.Includes(profile.CreatedAt) && filter.IncludesProfile(profile.URL) {
+			filtered = append(filtered, profile)
+		}
+	}
+	return filtered, nil
+}
+
+// ExportConnectionRequests returns the connection requests sent within the filter's range
+func (d *Database) ExportConnectionRequests(filter ExportFilter) ([]*ConnectionRequest, error) {
+	requests, err := d.getAllConnectionRequests()
+	if err != nil {
+		return nil, fmt.Errorf("failed to export connection requests: %w", err)
+	}
+
+	filtered := make([]*ConnectionRequest, 0, len(requests))
+	for _, request := range requests {
+		if filter.Includes(request.SentAt) && filter.IncludesProfile(request.ProfileURL) {
+			filtered = append(filtered, request)
+		}
+	}
+	return filtered, nil
+}
+
+// ExportMessages returns the messages sent within the filter's range
+func (d *Database) ExportMessages(filter ExportFilter) ([]*Message, error) {
+	messages, err := d.getAllMessages()
+	if err != nil {
+		return nil, fmt.Errorf("failed to export messages: %w", err)
+	}
+
+	filtered := make([]*Message, 0, len(messages))
+	for _, message := range messages {
+		if filter.Includes(message.SentAt) && filter.IncludesProfile(message.RecipientURL) {
+			filtered = append(filtered, message)
+		}
+	}
+	return filtered, nil
+}
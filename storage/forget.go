@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// personalData are the rows holding a person's data, table by table, as the
+// condition matching them with each ? standing for the profile URL. Queued
+// task payloads and cached searches are JSON, matched by the URL's /in/ path
+// followed by a quote, slash or query string. Messages received in a
+// conversation go before the conversation, which they are found through.
+// Opt-outs and blacklist entries stay, so a forgotten person is never
+// contacted again.
+var personalData = []struct {
+	table, where string
+	json         bool
+}{
+	{"profiles", "url = ?", false},
+	{"connection_requests", "profile_url = ?", false},
+	{"messages", "recipient_url = ?", false},
+	{"messages_received", "sender_url = ? OR thread_id IN (SELECT thread_id FROM inbox_threads WHERE participant_url = ?)", false},
+	{"inbox_threads", "participant_url = ?", false},
+	{"batch_items", "item_url = ?", false},
+	{"crm_syncs", "profile_url = ?", false},
+	{"profile_visits", "profile_url = ?", false},
+	{"engagements", "target_url = ?", false},
+	{"endorsements", "profile_url = ?", false},
+	{"sequence_enrollments", "profile_url = ?", false},
+	{"audit_log", "profile_url = ?", false},
+	{"prospect_tags", "profile_url = ?", false},
+	{"saved_search_results", "profile_url = ?", false},
+	{"network_connections", "profile_url = ?", false},
+	{"invitation_actions", "profile_url = ?", false},
+	{"account_assignments", "profile_url = ?", false},
+	{"ai_openers", "profile_url = ?", false},
+	{"reply_labels", "profile_url = ?", false},
+	{"profile_history", "profile_url = ?", false},
+	{"reminder_nudges", "profile_url = ?", false},
+	{"queue_tasks", "payload LIKE ? OR payload LIKE ? OR payload LIKE ?", true},
+	{"search_cache", "session LIKE ? OR session LIKE ? OR session LIKE ?", true},
+}
+
+// ForgetProfile deletes everything stored about the person with the given
+// profile URL and returns the number of rows deleted per table, leaving out
+// tables with none. With dryRun nothing is deleted and the rows that would be
+// are counted.
+func (d *Database) ForgetProfile(url string, dryRun bool) (map[string]int64, error) {
+	slug := profileurl.Slug(url)
+	if slug == "" {
+		return nil, fmt.Errorf("not a profile URL: %s", url)
+	}
+	url = profileurl.Canonicalize(url)
+	path := "%/in/" + slug
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	deleted := make(map[string]int64)
+	for _, data := range personalData {
+		args := []interface{}{path + `"%`, path + `/%`, path + `?%`}
+		if !data.json {
+			args = make([]interface{}, strings.Count(data.where, "?"))
+			for i := range args {
+				args[i] = url
+			}
+		}
+
+		var count int64
+		if dryRun {
+			if err := tx.QueryRow(`SELECT COUNT(*) FROM `+data.table+` WHERE `+data.where, args...).Scan(&count); err != nil {
+				return nil, fmt.Errorf("failed to count %s: %w", data.table, err)
+			}
+		} else {
+			result, err := tx.Exec(`DELETE FROM `+data.table+` WHERE `+data.where, args...)
+			if err != nil {
+				return nil, fmt.Errorf("failed to delete from %s: %w", data.table, err)
+			}
+			if count, err = result.RowsAffected(); err != nil {
+				return nil, fmt.Errorf("failed to get deleted rows: %w", err)
+			}
+		}
+		if count > 0 {
+			deleted[data.table] = count
+		}
+	}
+
+	if dryRun {
+		return deleted, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit deletion: %w", err)
+	}
+	d.logger.WithField("profile_url", url).WithField("tables", len(deleted)).Debug("Profile data deleted")
+	return deleted, nil
+}
+
+// InactiveProfiles returns the profile URLs with nothing stored about them
+// since cutoff: no profile update, request, message, reply or visit
+func (d *Database) InactiveProfiles(cutoff time.Time) ([]string, error) {
+	query := `SELECT url FROM (
+			  SELECT url, updated_at AS active_at FROM profiles
+			  UNION ALL SELECT profile_url, sent_at FROM connection_requests
+			  UNION ALL SELECT recipient_url, sent_at FROM messages
+			  UNION ALL SELECT sender_url, received_at FROM messages_received WHERE sender_url IS NOT NULL
+			  UNION ALL SELECT profile_url, visited_at FROM profile_visits
+			  ) activity GROUP BY url HAVING MAX(active_at) < ? ORDER BY url`
+
+	rows, err := d.db.Query(query, cutoff.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inactive profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var urls []string
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, fmt.Errorf("failed to scan inactive profile: %w", err)
+		}
+		if profileurl.Slug(url) != "" {
+			urls = append(urls, url)
+		}
+	}
+
+	return urls, nil
+}
@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// ReceivedMessage represents an incoming message found during an inbox sync
+type ReceivedMessage struct {
+	ID         int       `json:"id"`
+	ThreadID   string    `json:"thread_id"`
+	SenderURL  string    `json:"sender_url"`
+	SenderName string    `json:"sender_name"`
+	Content    string    `json:"content"`
+	SentLabel  string    `json:"sent_label,omitempty"` // Timestamp as displayed by LinkedIn, e.g. "3:45 PM"
+	ReceivedAt time.Time `json:"received_at"`          // When the sync first saw the message
+}
+
+// SaveReceivedMessage stores an incoming message, reporting whether it was new
+func (d *Database) SaveReceivedMessage(message *ReceivedMessage) (bool, error) {
+	message.SenderURL = profileurl.Canonicalize(message.SenderURL)
+	if message.ReceivedAt.IsZero() {
+		message.ReceivedAt = time.Now()
+	}
+
+	query := `INSERT OR IGNORE INTO messages_received
+			  (thread_id, sender_url, sender_name, content, sent_label, content_hash, received_at, run_id)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''))`
+
+	id, err := d.db.insert(query, message.ThreadID, message.SenderURL, message.SenderName, message.Content,
+		message.SentLabel, receivedMessageHash(message), message.ReceivedAt.UTC(), d.runID)
+	if err == errNotInserted {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to save received message: %w", err)
+	}
+
+	message.ID = int(id)
+	d.logger.WithField("thread_id", message.ThreadID).Debug("Received message saved")
+	return true, nil
+}
+
+// GetReceivedMessages retrieves messages first seen since the given time, newest first
+func (d *Database) GetReceivedMessages(since time.Time) ([]*ReceivedMessage, error) {
+	query := `SELECT id, thread_id, COALESCE(sender_url, ''), COALESCE(sender_name, ''), content,
+			  COALESCE(sent_label, ''), received_at
+			  FROM messages_received WHERE received_at >= ? ORDER BY received_at DESC, id DESC`
+
+	rows, err := d.db.Query(query, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get received messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*ReceivedMessage
+	for rows.Next() {
+		var message ReceivedMessage
+		if err := rows.Scan(&message.ID, &message.ThreadID, &message.SenderURL, &message.SenderName,
+			&message.Content, &message.SentLabel, &message.ReceivedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan received message: %w", err)
+		}
+		messages = append(messages, &message)
+	}
+
+	return messages, nil
+}
+
+// HasReplied reports whether any message has been received from a profile
+func (d *Database) HasReplied(profileURL string) (bool, error) {
+	query := `SELECT COUNT(*) FROM messages_received WHERE sender_url = ?`
+
+	var count int
+	if err := d.db.QueryRow(query, profileurl.Canonicalize(profileURL)).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check replies: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// GetRepliedProfiles returns the canonical URLs of every profile that has replied
+func (d *Database) GetRepliedProfiles() (map[string]bool, error) {
+	rows, err := d.db.Query(`SELECT DISTINCT sender_url FROM messages_received WHERE sender_url != ''`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replied profiles: %w", err)
+	}
+	defer rows.Close()
+
+	replied := make(map[string]bool)
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, fmt.Errorf("failed to scan replied profile: %w", err)
+		}
+		replied[url] = true
+	}
+
+	return replied, nil
+}
+
+// GetInboxThreadSnippet returns the conversation snippet recorded at the last sync of a thread
+func (d *Database) GetInboxThreadSnippet(threadID string) (string, error) {
+	var snippet string
+	err := d.db.QueryRow(`SELECT COALESCE(last_snippet, '') FROM inbox_threads WHERE thread_id = ?`, threadID).Scan(&snippet)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get inbox thread: %w", err)
+	}
+
+	return snippet, nil
+}
+
+// SaveInboxThread records the state of a thread after it has been synced
+func (d *Database) SaveInboxThread(threadID, participantURL, snippet string) error {
+	query := `INSERT INTO inbox_threads (thread_id, participant_url, last_snippet, synced_at)
+			  VALUES (?, ?, ?, ?)
+			  ON CONFLICT(thread_id) DO UPDATE SET
+			  participant_url = excluded.participant_url, last_snippet = excluded.last_snippet, synced_at = excluded.synced_at`
+
+	if _, err := d.db.Exec(query, threadID, profileurl.Canonicalize(participantURL), snippet, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to save inbox thread: %w", err)
+	}
+
+	return nil
+}
+
+// receivedMessageHash identifies a message within its thread; LinkedIn exposes
+// no stable message IDs in the page, so sender, displayed time and text are used
+func receivedMessageHash(message *ReceivedMessage) string {
+	hash := sha1.New()
+	hash.Write([]byte(message.SenderURL + "\n" + message.SentLabel + "\n" + message.Content))
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// RecordInMailCredits stores the number of InMail credits LinkedIn showed as remaining
+func (d *Database) RecordInMailCredits(remaining int) error {
+	query := `INSERT INTO inmail_credits (remaining, checked_at) VALUES (?, ?)`
+
+	if _, err := d.db.Exec(query, remaining, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to record InMail credits: %w", err)
+	}
+
+	d.logger.WithField("remaining", remaining).Debug("InMail credits recorded")
+	return nil
+}
+
+// GetInMailCredits returns the most recently recorded InMail credit balance and
+// when it was seen, or -1 if credits have never been recorded
+func (d *Database) GetInMailCredits() (int, time.Time, error) {
+	query := `SELECT remaining, checked_at FROM inmail_credits ORDER BY checked_at DESC, id DESC LIMIT 1`
+
+	var remaining int
+	var checkedAt time.Time
+	if err := d.db.QueryRow(query).Scan(&remaining, &checkedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return -1, time.Time{}, nil
+		}
+		return -1, time.Time{}, fmt.Errorf("failed to get InMail credits: %w", err)
+	}
+
+	return remaining, checkedAt, nil
+}
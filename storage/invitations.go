@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// InvitationAction records how an incoming connection invitation was answered
+type InvitationAction struct {
+	ID         int       `json:"id"`
+	ProfileURL string    `json:"profile_url"`
+	Name       string    `json:"name"`
+	Headline   string    `json:"headline"`
+	Action     string    `json:"action"`         // accept or ignore
+	Rule       string    `json:"rule,omitempty"` // Rule that decided, empty when answered by hand
+	DryRun     bool      `json:"dry_run"`
+	ActedAt    time.Time `json:"acted_at"`
+}
+
+// SaveInvitationAction records an answered invitation
+func (d *Database) SaveInvitationAction(action *InvitationAction) error {
+	query := `INSERT INTO invitation_actions (profile_url, name, headline, action, rule, dry_run, acted_at, run_id)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''))`
+
+	if action.ActedAt.IsZero() {
+		action.ActedAt = time.Now()
+	}
+	id, err := d.db.insert(query, profileurl.Canonicalize(action.ProfileURL), action.Name, action.Headline,
+		action.Action, action.Rule, action.DryRun, action.ActedAt.UTC(), d.runID)
+	if err != nil {
+		return fmt.Errorf("failed to save invitation action: %w", err)
+	}
+
+	action.ID = int(id)
+	return nil
+}
+
+// ListInvitationActions returns up to limit answered invitations, newest first
+func (d *Database) ListInvitationActions(limit int) ([]*InvitationAction, error) {
+	query := `SELECT id, profile_url, COALESCE(name, ''), COALESCE(headline, ''), action, COALESCE(rule, ''), dry_run, acted_at
+			  FROM invitation_actions ORDER BY acted_at DESC, id DESC LIMIT ?`
+
+	rows, err := d.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitation actions: %w", err)
+	}
+	defer rows.Close()
+
+	var actions []*InvitationAction
+	for rows.Next() {
+		var a InvitationAction
+		if err := rows.Scan(&a.ID, &a.ProfileURL, &a.Name, &a.Headline, &a.Action, &a.Rule, &a.DryRun, &a.ActedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan invitation action: %w", err)
+		}
+		actions = append(actions, &a)
+	}
+
+	return actions, nil
+}
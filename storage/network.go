@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// NetworkConnection is a 1st-degree connection from the account's connection list
+type NetworkConnection struct {
+	ProfileURL  string     `json:"profile_url"`
+	Name        string     `json:"name"`
+	Headline    string     `json:"headline"`
+	ConnectedAt *time.Time `json:"connected_at,omitempty"` // Approximate when LinkedIn only shows "Connected 2 weeks ago"
+	FirstSeenAt time.Time  `json:"first_seen_at"`
+	LastSeenAt  time.Time  `json:"last_seen_at"`
+}
+
+// SaveNetworkConnections stores connections from the connection list, updating
+// those seen before. It returns how many were not stored yet.
+func (d *Database) SaveNetworkConnections(connections []*NetworkConnection) (int, error) {
+	exists := `SELECT COUNT(*) FROM network_connections WHERE profile_url = ?`
+	// The first connection date is kept: relative dates like "2 weeks ago" get coarser over time
+	query := `INSERT INTO network_connections (profile_url, name, headline, connected_at, first_seen_at, last_seen_at)
+			  VALUES (?, ?, ?, ?, ?, ?)
+			  ON CONFLICT(profile_url) DO UPDATE SET
+			  name = excluded.name, headline = excluded.headline,
+			  connected_at = COALESCE(network_connections.connected_at, excluded.connected_at), last_seen_at = excluded.last_seen_at`
+
+	now := time.Now().UTC()
+	added := 0
+	for _, c := range connections {
+		profileURL := profileurl.Canonicalize(c.ProfileURL)
+
+		var count int
+		if err := d.db.QueryRow(exists, profileURL).Scan(&count); err != nil {
+			return added, fmt.Errorf("failed to look up connection: %w", err)
+		}
+
+		var connectedAt interface{}
+		if c.ConnectedAt != nil {
+			connectedAt = c.ConnectedAt.UTC()
+		}
+		if _, err := d.db.Exec(query, profileURL, c.Name, c.Headline, connectedAt, now, now); err != nil {
+			return added, fmt.Errorf("failed to save connection: %w", err)
+		}
+		if count == 0 {
+			added++
+		}
+	}
+
+	d.logger.WithField("count", len(connections)).WithField("added", added).Debug("Network connections saved")
+	return added, nil
+}
+
+// ListNetworkConnections returns the stored connections, most recently connected first
+func (d *Database) ListNetworkConnections() ([]*NetworkConnection, error) {
+	query := `SELECT profile_url, COALESCE(name, ''), COALESCE(headline, ''), connected_at, first_seen_at, last_seen_at
+			  FROM network_connections
+			  ORDER BY connected_at IS NULL, connected_at DESC, first_seen_at DESC, profile_url`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list connections: %w", err)
+	}
+	defer rows.Close()
+
+	var connections []*NetworkConnection
+	for rows.Next() {
+		var c NetworkConnection
+		var connectedAt sql.NullTime
+		if err := rows.Scan(&c.ProfileURL, &c.Name, &c.Headline, &connectedAt, &c.FirstSeenAt, &c.LastSeenAt); err != nil {
+			return nil, fmt.Errorf("failed to scan connection: %w", err)
+		}
+		if connectedAt.Valid {
+			c.ConnectedAt = &connectedAt.Time
+		}
+		connections = append(connections, &c)
+	}
+
+	return connections, nil
+}
@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// ProfileChange is a change in a profile's title, company or location seen
+// when it was scraped again
+type ProfileChange struct {
+	ID         int       `json:"id"`
+	ProfileURL string    `json:"profile_url"`
+	Name       string    `json:"name,omitempty"` // From the stored profile, when listing
+	Field      string    `json:"field"`          // title, company or location
+	OldValue   string    `json:"old_value"`
+	NewValue   string    `json:"new_value"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+// RecordProfileChanges compares a freshly scraped profile to the values the
+// last scrape saw and records the fields that changed, returning them. Only
+// scraped values are compared, so details taken from search results, which
+// show the headline as title, never count as a change. The first scrape of a
+// field records its value as a baseline with no old value.
+func (d *Database) RecordProfileChanges(profile *Profile, scrapedAt time.Time) ([]*ProfileChange, error) {
+	latest := `SELECT new_value FROM profile_history WHERE profile_url = ? AND field = ? ORDER BY changed_at DESC, id DESC LIMIT 1`
+	insert := `INSERT INTO profile_history (profile_url, field, old_value, new_value, changed_at, run_id) VALUES (?, ?, ?, ?, ?, NULLIF(?, ''))`
+
+	url := profileurl.Canonicalize(profile.URL)
+	var changes []*ProfileChange
+	for _, field := range []struct{ name, value string }{
+		{"title", profile.Title},
+		{"company", profile.Company},
+		{"location", profile.Location},
+	} {
+		value := strings.TrimSpace(field.value)
+		if value == "" {
+			continue
+		}
+
+		var previous string
+		err := d.db.QueryRow(latest, url, field.name).Scan(&previous)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, fmt.Errorf("failed to get profile history: %w", err)
+		}
+		if previous == value {
+			continue
+		}
+
+		change := &ProfileChange{ProfileURL: url, Field: field.name, OldValue: previous, NewValue: value, ChangedAt: scrapedAt}
+		id, err := d.db.insert(insert, url, field.name, previous, value, scrapedAt.UTC(), d.runID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record profile change: %w", err)
+		}
+		change.ID = int(id)
+		if previous != "" {
+			changes = append(changes, change)
+		}
+	}
+
+	if len(changes) > 0 {
+		d.logger.WithField("profile_url", url).WithField("changes", len(changes)).Debug("Profile changes recorded")
+	}
+	return changes, nil
+}
+
+// ListProfileChanges returns the changes to the given fields recorded since,
+// newest first, leaving out the baselines of first scrapes
+func (d *Database) ListProfileChanges(since time.Time, fields []string) ([]*ProfileChange, error) {
+	query := `SELECT h.id, h.profile_url, COALESCE(p.name, ''), h.field, h.old_value, h.new_value, h.changed_at
+			  FROM profile_history h LEFT JOIN profiles p ON p.url = h.profile_url
+			  WHERE h.old_value <> '' AND h.changed_at >= ?`
+	args := []interface{}{since.UTC()}
+	if len(fields) > 0 {
+		query += ` AND h.field IN (?` + strings.Repeat(`, ?`, len(fields)-1) + `)`
+		for _, field := range fields {
+			args = append(args, field)
+		}
+	}
+	query += ` ORDER BY h.changed_at DESC, h.id DESC`
+
+	return d.queryProfileChanges(query, args...)
+}
+
+// GetProfileHistory returns every value recorded for a profile, oldest first
+func (d *Database) GetProfileHistory(url string) ([]*ProfileChange, error) {
+	query := `SELECT h.id, h.profile_url, COALESCE(p.name, ''), h.field, h.old_value, h.new_value, h.changed_at
+			  FROM profile_history h LEFT JOIN profiles p ON p.url = h.profile_url
+			  WHERE h.profile_url = ? ORDER BY h.changed_at, h.id`
+
+	return d.queryProfileChanges(query, profileurl.Canonicalize(url))
+}
+
+func (d *Database) queryProfileChanges(query string, args ...interface{}) ([]*ProfileChange, error) {
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profile changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*ProfileChange
+	for rows.Next() {
+		var c ProfileChange
+		if err := rows.Scan(&c.ID, &c.ProfileURL, &c.Name, &c.Field, &c.OldValue, &c.NewValue, &c.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan profile change: %w", err)
+		}
+		changes = append(changes, &c)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,293 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Queue task statuses
+const (
+	TaskPending   = "pending"
+	TaskRunning   = "running"
+	TaskDone      = "done"
+	TaskFailed    = "failed"
+	TaskCancelled = "cancelled"
+)
+
+// QueueTask represents a queued connect, message or search task
+type QueueTask struct {
+	ID          int        `json:"id"`
+	Kind        string     `json:"kind"`    // connect, message, search
+	Payload     string     `json:"payload"` // JSON-encoded task parameters
+	Priority    int        `json:"priority"`
+	Status      string     `json:"status"` // pending, running, done, failed, cancelled
+	Attempts    int        `json:"attempts"`
+	LastError   string     `json:"last_error,omitempty"`
+	ScheduledAt time.Time  `json:"scheduled_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+const queueTaskColumns = `id, kind, payload, priority, status, attempts, COALESCE(last_error, ''),
+			  scheduled_at, created_at, updated_at, finished_at`
+
+// EnqueueTask adds a pending task to the queue
+func (d *Database) EnqueueTask(task *QueueTask) error {
+	if task.ScheduledAt.IsZero() {
+		task.ScheduledAt = time.Now()
+	}
+	task.Status = TaskPending
+
+	query := `INSERT INTO queue_tasks (kind, payload, priority, status, scheduled_at, created_at, updated_at, run_id)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''))`
+
+	now := time.Now().UTC()
+	id, err := d.db.insert(query, task.Kind, task.Payload, task.Priority, task.Status, task.ScheduledAt.UTC(), now, now, d.runID)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	task.ID = int(id)
+	d.logger.WithField("task_id", task.ID).WithField("kind", task.Kind).Debug("Task enqueued")
+	return nil
+}
+
+// ClaimNextTask marks the highest-priority due task as running under worker's
+// lease and returns it, or nil if no task is due
+func (d *Database) ClaimNextTask(now time.Time, worker string) (*QueueTask, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `SELECT ` + queueTaskColumns + ` FROM queue_tasks
+			  WHERE status = ? AND scheduled_at <= ?
+			  ORDER BY priority DESC, scheduled_at, id LIMIT 1` + d.db.dialect.lockClause()
+
+	task, err := scanQueueTask(tx.QueryRow(query, TaskPending, now.UTC()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get next task: %w", err)
+	}
+
+	update := `UPDATE queue_tasks SET status = ?, attempts = attempts + 1, claimed_by = ?, heartbeat_at = ?, updated_at = ? WHERE id = ?`
+	if _, err := tx.Exec(update, TaskRunning, worker, now.UTC(), now.UTC(), task.ID); err != nil {
+		return nil, fmt.Errorf("failed to claim task: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit task claim: %w", err)
+	}
+
+	task.Status = TaskRunning
+	task.Attempts++
+	return task, nil
+}
+
+// CompleteTask marks a task as done
+func (d *Database) CompleteTask(id int) error {
+	return d.finishTask(id, TaskDone, "")
+}
+
+// FailTask marks a task as permanently failed
+func (d *Database) FailTask(id int, lastError string) error {
+	return d.finishTask(id, TaskFailed, lastError)
+}
+
+// RescheduleTask returns a task to the queue to run again at the given time
+func (d *Database) RescheduleTask(id int, at time.Time, lastError string) error {
+	query := `UPDATE queue_tasks SET status = ?, scheduled_at = ?, last_error = ?, updated_at = ? WHERE id = ?`
+
+	if _, err := d.db.Exec(query, TaskPending, at.UTC(), lastError, time.Now().UTC(), id); err != nil {
+		return fmt.Errorf("failed to reschedule task: %w", err)
+	}
+
+	d.logger.WithField("task_id", id).WithField("scheduled_at", at).Debug("Task rescheduled")
+	return nil
+}
+
+// DeferTask returns a task to the queue without counting the current attempt,
+// for tasks that could not run because a rate limit was reached
+func (d *Database) DeferTask(id int, until time.Time, reason string) error {
+	query := `UPDATE queue_tasks SET status = ?, attempts = CASE WHEN attempts > 0 THEN attempts - 1 ELSE 0 END,
+			  scheduled_at = ?, last_error = ?, updated_at = ? WHERE id = ?`
+
+	if _, err := d.db.Exec(query, TaskPending, until.UTC(), reason, time.Now().UTC(), id); err != nil {
+		return fmt.Errorf("failed to defer task: %w", err)
+	}
+
+	d.logger.WithField("task_id", id).WithField("scheduled_at", until).Debug("Task deferred")
+	return nil
+}
+
+// RetryTask resets a failed or cancelled task so it runs again, reporting whether it was found
+func (d *Database) RetryTask(id int) (bool, error) {
+	query := `UPDATE queue_tasks SET status = ?, attempts = 0, scheduled_at = ?, finished_at = NULL, updated_at = ?
+			  WHERE id = ? AND status IN (?, ?)`
+
+	now := time.Now().UTC()
+	return d.updateTask(query, "retry", TaskPending, now, now, id, TaskFailed, TaskCancelled)
+}
+
+// CancelTask cancels a pending task, reporting whether it was found
+func (d *Database) CancelTask(id int) (bool, error) {
+	query := `UPDATE queue_tasks SET status = ?, finished_at = ?, updated_at = ? WHERE id = ? AND status = ?`
+
+	now := time.Now().UTC()
+	return d.updateTask(query, "cancel", TaskCancelled, now, now, id, TaskPending)
+}
+
+// HeartbeatTask renews worker's lease on a running task
+func (d *Database) HeartbeatTask(id int, worker string, now time.Time) error {
+	query := `UPDATE queue_tasks SET heartbeat_at = ? WHERE id = ? AND status = ? AND claimed_by = ?`
+
+	if _, err := d.db.Exec(query, now.UTC(), id, TaskRunning, worker); err != nil {
+		return fmt.Errorf("failed to renew task lease: %w", err)
+	}
+	return nil
+}
+
+// ResetRunningTasks returns tasks left running by an interrupted worker to the
+// queue: those whose lease was last renewed before staleBefore. Tasks other
+// live workers are running keep renewing theirs and are left alone.
+func (d *Database) ResetRunningTasks(staleBefore time.Time) (int, error) {
+	result, err := d.db.Exec(`UPDATE queue_tasks SET status = ?, claimed_by = NULL, updated_at = ?
+			  WHERE status = ? AND (heartbeat_at IS NULL OR heartbeat_at < ?)`,
+		TaskPending, time.Now().UTC(), TaskRunning, staleBefore.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to reset running tasks: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get reset rows: %w", err)
+	}
+	return int(affected), nil
+}
+
+// GetTask retrieves a task by ID
+func (d *Database) GetTask(id int) (*QueueTask, error) {
+	query := `SELECT ` + queueTaskColumns + ` FROM queue_tasks WHERE id = ?`
+
+	task, err := scanQueueTask(d.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	return task, nil
+}
+
+// ListTasks retrieves tasks in queue order, optionally filtered by status
+func (d *Database) ListTasks(status string) ([]*QueueTask, error) {
+	query := `SELECT ` + queueTaskColumns + ` FROM queue_tasks
+			  WHERE (? = '' OR status = ?) ORDER BY priority DESC, scheduled_at, id`
+
+	rows, err := d.db.Query(query, status, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []*QueueTask
+	for rows.Next() {
+		task, err := scanQueueTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// ListUpcomingTasks retrieves the pending tasks scheduled after now, soonest first
+func (d *Database) ListUpcomingTasks(now time.Time) ([]*QueueTask, error) {
+	query := `SELECT ` + queueTaskColumns + ` FROM queue_tasks
+			  WHERE status = ? AND scheduled_at > ? ORDER BY scheduled_at, priority DESC, id`
+
+	rows, err := d.db.Query(query, TaskPending, now.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list upcoming tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []*QueueTask
+	for rows.Next() {
+		task, err := scanQueueTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// CountTasksByStatus returns the number of tasks in each status
+func (d *Database) CountTasksByStatus() (map[string]int, error) {
+	rows, err := d.db.Query(`SELECT status, COUNT(*) FROM queue_tasks GROUP BY status`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan task count: %w", err)
+		}
+		counts[status] = count
+	}
+
+	return counts, nil
+}
+
+func (d *Database) finishTask(id int, status, lastError string) error {
+	query := `UPDATE queue_tasks SET status = ?, last_error = ?, finished_at = ?, updated_at = ? WHERE id = ?`
+
+	now := time.Now().UTC()
+	if _, err := d.db.Exec(query, status, lastError, now, now, id); err != nil {
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+
+	d.logger.WithField("task_id", id).WithField("status", status).Debug("Task finished")
+	return nil
+}
+
+func (d *Database) updateTask(query, action string, args ...interface{}) (bool, error) {
+	result, err := d.db.Exec(query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to %s task: %w", action, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get updated rows: %w", err)
+	}
+	return affected > 0, nil
+}
+
+func scanQueueTask(row rowScanner) (*QueueTask, error) {
+	var task QueueTask
+	var finishedAt sql.NullTime
+	err := row.Scan(&task.ID, &task.Kind, &task.Payload, &task.Priority, &task.Status, &task.Attempts,
+		&task.LastError, &task.ScheduledAt, &task.CreatedAt, &task.UpdatedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if finishedAt.Valid {
+		task.FinishedAt = &finishedAt.Time
+	}
+	return &task, nil
+}
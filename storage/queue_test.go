@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestResetRunningTasksKeepsLiveLeases(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	db, err := NewDatabase(filepath.Join(t.TempDir(), "test.db"), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	task := &QueueTask{Kind: "connect", Payload: "{}"}
+	if err := db.EnqueueTask(task); err != nil {
+		t.Fatal(err)
+	}
+	claimedAt := time.Now()
+	if claimed, err := db.ClaimNextTask(claimedAt, "worker-a"); err != nil || claimed == nil {
+		t.Fatalf("ClaimNextTask() = %v, %v; want task %d", claimed, err, task.ID)
+	}
+
+	// Another worker starting while worker-a still renews its lease
+	if err := db.HeartbeatTask(task.ID, "worker-a", claimedAt.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	reset, err := db.ResetRunningTasks(claimedAt.Add(30 * time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reset != 0 {
+		t.Errorf("ResetRunningTasks() reset %d tasks with a live lease, want 0", reset)
+	}
+	if claimed, err := db.ClaimNextTask(claimedAt.Add(time.Minute), "worker-b"); err != nil || claimed != nil {
+		t.Fatalf("ClaimNextTask() by worker-b = %v, %v; want nothing to claim", claimed, err)
+	}
+
+	// A renewal from a worker not holding the lease changes nothing
+	if err := db.HeartbeatTask(task.ID, "worker-b", claimedAt.Add(10*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	reset, err = db.ResetRunningTasks(claimedAt.Add(2 * time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reset != 1 {
+		t.Errorf("ResetRunningTasks() reset %d tasks with a stale lease, want 1", reset)
+	}
+	got, err := db.GetTask(task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != TaskPending {
+		t.Errorf("task status = %s after its lease went stale, want %s", got.Status, TaskPending)
+	}
+}
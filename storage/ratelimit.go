@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// RecordRateLimitEvent records a rate-limited action at the given time
+func (d *Database) RecordRateLimitEvent(action string, at time.Time) error {
+	query := `INSERT INTO rate_limit_events (action, created_at) VALUES (?, ?)`
+
+	if _, err := d.db.Exec(query, action, at.UTC()); err != nil {
+		return fmt.Errorf("failed to record rate limit event: %w", err)
+	}
+
+	d.logger.WithField("action", action).Debug("Rate limit event recorded")
+	return nil
+}
+
+// GetRateLimitEvents retrieves the times of all events for an action since the given time, oldest first
+func (d *Database) GetRateLimitEvents(action string, since time.Time) ([]time.Time, error) {
+	query := `SELECT created_at FROM rate_limit_events
+			  WHERE action = ? AND created_at >= ? ORDER BY created_at`
+
+	rows, err := d.db.Query(query, action, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rate limit events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []time.Time
+	for rows.Next() {
+		var createdAt time.Time
+		if err := rows.Scan(&createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan rate limit event: %w", err)
+		}
+		events = append(events, createdAt)
+	}
+
+	return events, nil
+}
+
+// CountRateLimitEvents counts the events for an action since the given time
+func (d *Database) CountRateLimitEvents(action string, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM rate_limit_events WHERE action = ? AND created_at >= ?`
+
+	var count int
+	if err := d.db.QueryRow(query, action, since.UTC()).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count rate limit events: %w", err)
+	}
+
+	return count, nil
+}
+
+// SetActionBlock records that LinkedIn refuses an action until the given time
+func (d *Database) SetActionBlock(action string, until time.Time, reason string) error {
+	query := `INSERT INTO action_blocks (action, blocked_until, reason, created_at) VALUES (?, ?, ?, ?)
+			  ON CONFLICT(action) DO UPDATE SET
+			  blocked_until = excluded.blocked_until, reason = excluded.reason, created_at = excluded.created_at`
+
+	if _, err := d.db.Exec(query, action, until.UTC(), reason, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to record action block: %w", err)
+	}
+
+	d.logger.WithField("action", action).WithField("until", until).Info("Action block recorded")
+	return nil
+}
+
+// GetActionBlock returns when the block on an action ends; the zero time means
+// the action has never been blocked
+func (d *Database) GetActionBlock(action string) (time.Time, error) {
+	var until time.Time
+	err := d.db.QueryRow(`SELECT blocked_until FROM action_blocks WHERE action = ?`, action).Scan(&until)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("failed to get action block: %w", err)
+	}
+
+	return until, nil
+}
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// NonResponder is someone who accepted a connection request and never
+// replied, with the messages and reminders they were sent since
+type NonResponder struct {
+	ProfileURL    string     `json:"profile_url"`
+	Name          string     `json:"name,omitempty"`
+	AcceptedAt    time.Time  `json:"accepted_at"`
+	LastMessageAt *time.Time `json:"last_message_at,omitempty"` // Last message sent to them, reminders included
+	Nudges        int        `json:"nudges"`                    // Reminders queued for them, leaving out cancelled tasks
+	LastNudgeAt   *time.Time `json:"last_nudge_at,omitempty"`
+}
+
+// GetNonResponders returns the people whose connection requests were
+// accepted and who have neither replied nor opted out, most recently
+// accepted first
+func (d *Database) GetNonResponders() ([]*NonResponder, error) {
+	query := `SELECT c.profile_url, COALESCE(p.name, ''), c.accepted_at
+			  FROM connection_requests c LEFT JOIN profiles p ON p.url = c.profile_url
+			  WHERE c.status = 'accepted' AND c.dry_run = 0 AND c.accepted_at IS NOT NULL
+			  AND c.profile_url NOT IN (SELECT sender_url FROM messages_received WHERE sender_url IS NOT NULL)
+			  AND c.profile_url NOT IN (SELECT profile_url FROM opt_outs)
+			  ORDER BY c.accepted_at DESC, c.id DESC`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get non-responders: %w", err)
+	}
+
+	var people []*NonResponder
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var person NonResponder
+		if err := rows.Scan(&person.ProfileURL, &person.Name, &person.AcceptedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan non-responder: %w", err)
+		}
+		// A repeated request keeps its latest acceptance, which comes first
+		if seen[person.ProfileURL] {
+			continue
+		}
+		seen[person.ProfileURL] = true
+		people = append(people, &person)
+	}
+	rows.Close()
+
+	for _, person := range people {
+		if person.LastMessageAt, err = d.lastMessageSent(person.ProfileURL); err != nil {
+			return nil, err
+		}
+		if person.Nudges, person.LastNudgeAt, err = d.reminderNudges(person.ProfileURL); err != nil {
+			return nil, err
+		}
+	}
+
+	return people, nil
+}
+
+// lastMessageSent returns when the last message was sent to a recipient, or
+// nil if none was
+func (d *Database) lastMessageSent(recipientURL string) (*time.Time, error) {
+	query := `SELECT sent_at FROM messages WHERE recipient_url = ? AND dry_run = 0 ORDER BY sent_at DESC LIMIT 1`
+
+	var sentAt time.Time
+	if err := d.db.QueryRow(query, recipientURL).Scan(&sentAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get last message: %w", err)
+	}
+	return &sentAt, nil
+}
+
+// reminderNudges returns the number of reminders queued for a prospect whose
+// tasks were not cancelled, and when the last one was
+func (d *Database) reminderNudges(profileURL string) (int, *time.Time, error) {
+	query := `SELECT n.queued_at FROM reminder_nudges n LEFT JOIN queue_tasks t ON t.id = n.task_id
+			  WHERE n.profile_url = ? AND COALESCE(t.status, '') <> ? ORDER BY n.queued_at DESC`
+
+	rows, err := d.db.Query(query, profileURL, TaskCancelled)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get reminder nudges: %w", err)
+	}
+	defer rows.Close()
+
+	count := 0
+	var last *time.Time
+	for rows.Next() {
+		var queuedAt time.Time
+		if err := rows.Scan(&queuedAt); err != nil {
+			return 0, nil, fmt.Errorf("failed to scan reminder nudge: %w", err)
+		}
+		if last == nil {
+			last = &queuedAt
+		}
+		count++
+	}
+	return count, last, nil
+}
+
+// RecordReminderNudge records that a reminder rendered from template was
+// queued for a prospect as the given task
+func (d *Database) RecordReminderNudge(profileURL, template string, taskID int) error {
+	query := `INSERT INTO reminder_nudges (profile_url, template, task_id, queued_at, run_id) VALUES (?, NULLIF(?, ''), ?, ?, ?)`
+
+	if _, err := d.db.Exec(query, profileurl.Canonicalize(profileURL), template, taskID, time.Now().UTC(), d.runID); err != nil {
+		return fmt.Errorf("failed to record reminder nudge: %w", err)
+	}
+	return nil
+}
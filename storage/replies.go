@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// Reply labels
+const (
+	ReplyInterested    = "interested"
+	ReplyNotInterested = "not_interested"
+	ReplyLater         = "later" // Asked to be contacted again some time later
+)
+
+// ReplyLabel is how a prospect's latest classified reply was labelled
+type ReplyLabel struct {
+	ProfileURL string     `json:"profile_url"`
+	Label      string     `json:"label"`
+	Source     string     `json:"source"` // keyword or ai
+	MessageID  int        `json:"message_id,omitempty"`
+	Content    string     `json:"content"`
+	RevisitAt  *time.Time `json:"revisit_at,omitempty"` // When to contact a prospect who replied later again
+	LabeledAt  time.Time  `json:"labeled_at"`
+}
+
+// SaveReplyLabel records the label of a prospect's latest reply, replacing
+// the label of an earlier one
+func (d *Database) SaveReplyLabel(label *ReplyLabel) error {
+	if label.LabeledAt.IsZero() {
+		label.LabeledAt = time.Now()
+	}
+	label.ProfileURL = profileurl.Canonicalize(label.ProfileURL)
+
+	var revisitAt interface{}
+	if label.RevisitAt != nil {
+		revisitAt = label.RevisitAt.UTC()
+	}
+
+	query := `INSERT INTO reply_labels (profile_url, label, source, message_id, content, revisit_at, labeled_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)
+			  ON CONFLICT(profile_url) DO UPDATE SET
+			  label = excluded.label, source = excluded.source, message_id = excluded.message_id,
+			  content = excluded.content, revisit_at = excluded.revisit_at, labeled_at = excluded.labeled_at`
+
+	if _, err := d.db.Exec(query, label.ProfileURL, label.Label, label.Source, label.MessageID, label.Content,
+		revisitAt, label.LabeledAt.UTC()); err != nil {
+		return fmt.Errorf("failed to save reply label: %w", err)
+	}
+
+	d.logger.WithField("profile_url", label.ProfileURL).WithField("label", label.Label).Debug("Reply label saved")
+	return nil
+}
+
+// GetReplyLabel returns the label of a prospect's latest classified reply, or
+// nil if none was classified
+func (d *Database) GetReplyLabel(profileURL string) (*ReplyLabel, error) {
+	query := `SELECT profile_url, label, source, COALESCE(message_id, 0), COALESCE(content, ''), revisit_at, labeled_at
+			  FROM reply_labels WHERE profile_url = ?`
+
+	var label ReplyLabel
+	err := d.db.QueryRow(query, profileurl.Canonicalize(profileURL)).Scan(&label.ProfileURL, &label.Label, &label.Source,
+		&label.MessageID, &label.Content, &label.RevisitAt, &label.LabeledAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get reply label: %w", err)
+	}
+	return &label, nil
+}
+
+// ListReplyLabels retrieves the labels of prospects' replies, optionally only
+// those with one label, most recent first
+func (d *Database) ListReplyLabels(label string) ([]*ReplyLabel, error) {
+	query := `SELECT profile_url, label, source, COALESCE(message_id, 0), COALESCE(content, ''), revisit_at, labeled_at
+			  FROM reply_labels WHERE (? = '' OR label = ?) ORDER BY labeled_at DESC`
+
+	rows, err := d.db.Query(query, label, label)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reply labels: %w", err)
+	}
+	defer rows.Close()
+
+	var labels []*ReplyLabel
+	for rows.Next() {
+		var l ReplyLabel
+		if err := rows.Scan(&l.ProfileURL, &l.Label, &l.Source, &l.MessageID, &l.Content, &l.RevisitAt, &l.LabeledAt); err != nil {
+			return nil, fmt.Errorf("failed to scan reply label: %w", err)
+		}
+		labels = append(labels, &l)
+	}
+	return labels, nil
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Restriction is a restriction or temporary block LinkedIn put on an account,
+// which automation waits out until CooldownUntil
+type Restriction struct {
+	ID            int       `json:"id"`
+	Account       string    `json:"account,omitempty"` // Empty for the top-level account
+	Reason        string    `json:"reason"`
+	URL           string    `json:"url,omitempty"` // Page it was noticed on
+	DetectedAt    time.Time `json:"detected_at"`
+	CooldownUntil time.Time `json:"cooldown_until"`
+}
+
+// RecordRestriction saves a restriction noticed on an account
+func (d *Database) RecordRestriction(restriction *Restriction) error {
+	query := `INSERT INTO restriction_events (account, reason, url, detected_at, cooldown_until) VALUES (?, ?, ?, ?, ?)`
+
+	id, err := d.db.insert(query, restriction.Account, restriction.Reason, restriction.URL,
+		restriction.DetectedAt.UTC(), restriction.CooldownUntil.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to record restriction: %w", err)
+	}
+
+	restriction.ID = int(id)
+	d.logger.WithField("account", restriction.Account).WithField("cooldown_until", restriction.CooldownUntil).Warn("Restriction recorded")
+	return nil
+}
+
+// GetActiveRestriction returns the restriction of account whose cool-down
+// lasts longest past now, or nil when the account is not cooling down
+func (d *Database) GetActiveRestriction(account string, now time.Time) (*Restriction, error) {
+	query := `SELECT id, account, reason, url, detected_at, cooldown_until FROM restriction_events
+			  WHERE account = ? AND cooldown_until > ? ORDER BY cooldown_until DESC LIMIT 1`
+
+	restriction, err := scanRestriction(d.db.QueryRow(query, account, now.UTC()))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active restriction: %w", err)
+	}
+	return restriction, nil
+}
+
+// ListRestrictions returns the restrictions noticed since the given time on
+// any account, newest first
+func (d *Database) ListRestrictions(since time.Time) ([]*Restriction, error) {
+	query := `SELECT id, account, reason, url, detected_at, cooldown_until FROM restriction_events
+			  WHERE detected_at >= ? ORDER BY detected_at DESC`
+
+	rows, err := d.db.Query(query, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list restrictions: %w", err)
+	}
+	defer rows.Close()
+
+	var restrictions []*Restriction
+	for rows.Next() {
+		restriction, err := scanRestriction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan restriction: %w", err)
+		}
+		restrictions = append(restrictions, restriction)
+	}
+	return restrictions, rows.Err()
+}
+
+// EndRestrictionCooldown ends the cool-down of account early, keeping its
+// restrictions on record, and returns how many were still cooling down
+func (d *Database) EndRestrictionCooldown(account string, now time.Time) (int, error) {
+	result, err := d.db.Exec(`UPDATE restriction_events SET cooldown_until = ? WHERE account = ? AND cooldown_until > ?`,
+		now.UTC(), account, now.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to end restriction cool-down: %w", err)
+	}
+	ended, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to end restriction cool-down: %w", err)
+	}
+	return int(ended), nil
+}
+
+func scanRestriction(row rowScanner) (*Restriction, error) {
+	var restriction Restriction
+	var url sql.NullString
+	if err := row.Scan(&restriction.ID, &restriction.Account, &restriction.Reason, &url,
+		&restriction.DetectedAt, &restriction.CooldownUntil); err != nil {
+		return nil, err
+	}
+	restriction.URL = url.String
+	return &restriction, nil
+}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// messageText are the columns holding the text of messages sent and
+// received, table by table, with the column dating each row and the value it
+// is cleared to. The rows stay, so limits, sequences and analytics still know
+// who was contacted and who replied.
+var messageText = []struct {
+	table, column, dated, cleared string
+}{
+	{"messages", "content", "sent_at", "''"},
+	{"connection_requests", "message", "sent_at", "NULL"},
+	{"messages_received", "content", "received_at", "''"},
+	{"inbox_threads", "last_snippet", "synced_at", "NULL"},
+	{"reply_labels", "content", "labeled_at", "NULL"},
+	{"opt_outs", "content", "opted_out_at", "NULL"},
+}
+
+// identifyingData are the columns naming or describing a person, table by
+// table, with the condition matching the person's rows. The profile URL
+// stays, so an anonymized person is never contacted twice.
+var identifyingData = []struct {
+	table, where string
+	columns      []string
+}{
+	{"profiles", "url = ?", []string{"name", "title", "headline", "company", "location", "email", "email_provider"}},
+	{"network_connections", "profile_url = ?", []string{"name", "headline"}},
+	{"invitation_actions", "profile_url = ?", []string{"name", "headline"}},
+	{"messages_received", "sender_url = ?", []string{"sender_name"}},
+}
+
+// ClearMessageText clears the text of the messages sent, received and
+// labeled before cutoff and returns the number of rows cleared per table,
+// leaving out tables with none. With dryRun nothing is cleared and the rows
+// that would be are counted.
+func (d *Database) ClearMessageText(cutoff time.Time, dryRun bool) (map[string]int64, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	cleared := make(map[string]int64)
+	for _, text := range messageText {
+		where := text.dated + ` < ? AND ` + text.column + ` IS NOT NULL AND ` + text.column + ` <> ''`
+		count, err := updateRows(tx, dryRun, text.table, text.column+` = `+text.cleared, where, cutoff.UTC())
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			cleared[text.table] = count
+		}
+	}
+
+	if dryRun {
+		return cleared, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit message cleanup: %w", err)
+	}
+	return cleared, nil
+}
+
+// AnonymizeProfile clears the name, job, location and email stored about the
+// person with the given profile URL and deletes their profile history,
+// keeping the URL. It returns the number of rows anonymized per table,
+// leaving out tables with none. With dryRun nothing changes and the rows that
+// would are counted.
+func (d *Database) AnonymizeProfile(url string, dryRun bool) (map[string]int64, error) {
+	url = profileurl.Canonicalize(url)
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	anonymized := make(map[string]int64)
+	for _, data := range identifyingData {
+		set := make([]string, len(data.columns))
+		stored := make([]string, len(data.columns))
+		for i, column := range data.columns {
+			set[i] = column + ` = NULL`
+			stored[i] = column + ` IS NOT NULL`
+		}
+		where := data.where + ` AND (` + strings.Join(stored, ` OR `) + `)`
+		count, err := updateRows(tx, dryRun, data.table, strings.Join(set, `, `), where, url)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			anonymized[data.table] = count
+		}
+	}
+
+	var count int64
+	if dryRun {
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM profile_history WHERE profile_url = ?`, url).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count profile_history: %w", err)
+		}
+	} else {
+		result, err := tx.Exec(`DELETE FROM profile_history WHERE profile_url = ?`, url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete from profile_history: %w", err)
+		}
+		if count, err = result.RowsAffected(); err != nil {
+			return nil, fmt.Errorf("failed to get deleted rows: %w", err)
+		}
+	}
+	if count > 0 {
+		anonymized["profile_history"] = count
+	}
+
+	if dryRun {
+		return anonymized, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit anonymization: %w", err)
+	}
+	if len(anonymized) > 0 {
+		d.logger.WithField("profile_url", url).WithField("tables", len(anonymized)).Debug("Profile anonymized")
+	}
+	return anonymized, nil
+}
+
+// updateRows sets the rows of table matching where and returns how many it
+// set, or with dryRun only counts them
+func updateRows(t *tx, dryRun bool, table, set, where string, args ...interface{}) (int64, error) {
+	var count int64
+	if dryRun {
+		if err := t.QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE `+where, args...).Scan(&count); err != nil {
+			return 0, fmt.Errorf("failed to count %s: %w", table, err)
+		}
+		return count, nil
+	}
+
+	result, err := t.Exec(`UPDATE `+table+` SET `+set+` WHERE `+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update %s: %w", table, err)
+	}
+	if count, err = result.RowsAffected(); err != nil {
+		return 0, fmt.Errorf("failed to get updated rows: %w", err)
+	}
+	return count, nil
+}
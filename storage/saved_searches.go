@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// SavedSearch is a named people search that can be re-run to find new entrants
+type SavedSearch struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Query     string     `json:"query"`               // JSON-encoded search query
+	Campaign  string     `json:"campaign,omitempty"`  // New profiles are queued for connection requests in this campaign
+	Template  string     `json:"template,omitempty"`  // Connection template for queued requests
+	Profiles  int        `json:"profiles"`            // Profiles found over all runs
+	NextPage  int        `json:"next_page,omitempty"` // Results page the next run resumes from; 0 starts over
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+const savedSearchColumns = `s.id, s.name, s.query, COALESCE(s.campaign, ''), COALESCE(s.template, ''),
+			  (SELECT COUNT(*) FROM saved_search_results r WHERE r.search_id = s.id), COALESCE(s.next_page, 0),
+			  s.last_run_at, s.created_at, s.updated_at`
+
+// SaveSavedSearch creates or updates a saved search by name. Updating a search
+// keeps the profiles it found before, so only later entrants are reported;
+// changing its query starts its paging over. (next_page is set first since
+// MySQL applies the assignments in order.)
+func (d *Database) SaveSavedSearch(search *SavedSearch) error {
+	query := `INSERT INTO saved_searches (name, query, campaign, template, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?)
+			  ON CONFLICT(name) DO UPDATE SET
+			  next_page = CASE WHEN saved_searches.query = excluded.query THEN saved_searches.next_page ELSE 0 END,
+			  query = excluded.query, campaign = excluded.campaign, template = excluded.template, updated_at = excluded.updated_at`
+
+	now := time.Now().UTC()
+	if _, err := d.db.Exec(query, search.Name, search.Query, search.Campaign, search.Template, now, now); err != nil {
+		return fmt.Errorf("failed to save search: %w", err)
+	}
+
+	d.logger.WithField("search", search.Name).Debug("Saved search stored")
+	return nil
+}
+
+// GetSavedSearch retrieves a saved search by name, or nil if there is none
+func (d *Database) GetSavedSearch(name string) (*SavedSearch, error) {
+	query := `SELECT ` + savedSearchColumns + ` FROM saved_searches s WHERE s.name = ?`
+
+	search, err := scanSavedSearch(d.db.QueryRow(query, name))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get saved search: %w", err)
+	}
+
+	return search, nil
+}
+
+// ListSavedSearches returns every saved search, ordered by name
+func (d *Database) ListSavedSearches() ([]*SavedSearch, error) {
+	query := `SELECT ` + savedSearchColumns + ` FROM saved_searches s ORDER BY s.name`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved searches: %w", err)
+	}
+	defer rows.Close()
+
+	var searches []*SavedSearch
+	for rows.Next() {
+		search, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved search: %w", err)
+		}
+		searches = append(searches, search)
+	}
+
+	return searches, nil
+}
+
+// DeleteSavedSearch deletes a saved search and the profiles it found,
+// reporting whether it existed
+func (d *Database) DeleteSavedSearch(name string) (bool, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM saved_search_results WHERE search_id IN (SELECT id FROM saved_searches WHERE name = ?)`, name); err != nil {
+		return false, fmt.Errorf("failed to delete saved search results: %w", err)
+	}
+	result, err := tx.Exec(`DELETE FROM saved_searches WHERE name = ?`, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete saved search: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get deleted rows: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit saved search deletion: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// RecordSavedSearchRun stores the profiles a run of a saved search found and
+// returns those no earlier run had found, in the order given
+func (d *Database) RecordSavedSearchRun(searchID int, profileURLs []string, ranAt time.Time) ([]string, error) {
+	insert := `INSERT OR IGNORE INTO saved_search_results (search_id, profile_url, first_seen_at, last_seen_at) VALUES (?, ?, ?, ?)`
+	update := `UPDATE saved_search_results SET last_seen_at = ? WHERE search_id = ? AND profile_url = ?`
+
+	ranAt = ranAt.UTC()
+	var added []string
+	for _, profileURL := range profileURLs {
+		profileURL = profileurl.Canonicalize(profileURL)
+		result, err := d.db.Exec(insert, searchID, profileURL, ranAt, ranAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record saved search result: %w", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get inserted rows: %w", err)
+		}
+		if affected > 0 {
+			added = append(added, profileURL)
+			continue
+		}
+		if _, err := d.db.Exec(update, ranAt, searchID, profileURL); err != nil {
+			return nil, fmt.Errorf("failed to update saved search result: %w", err)
+		}
+	}
+
+	if _, err := d.db.Exec(`UPDATE saved_searches SET last_run_at = ? WHERE id = ?`, ranAt, searchID); err != nil {
+		return nil, fmt.Errorf("failed to update saved search: %w", err)
+	}
+
+	return added, nil
+}
+
+// SetSavedSearchNextPage records the results page the next run of a saved
+// search resumes from, 0 to start over from the first
+func (d *Database) SetSavedSearchNextPage(searchID, page int) error {
+	if _, err := d.db.Exec(`UPDATE saved_searches SET next_page = ? WHERE id = ?`, page, searchID); err != nil {
+		return fmt.Errorf("failed to update saved search page: %w", err)
+	}
+	return nil
+}
+
+func scanSavedSearch(row rowScanner) (*SavedSearch, error) {
+	var search SavedSearch
+	var lastRunAt sql.NullTime
+	err := row.Scan(&search.ID, &search.Name, &search.Query, &search.Campaign, &search.Template,
+		&search.Profiles, &search.NextPage, &lastRunAt, &search.CreatedAt, &search.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastRunAt.Valid {
+		search.LastRunAt = &lastRunAt.Time
+	}
+	return &search, nil
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"fmt"
+
+	"linkedin-automation/profileurl"
+)
+
+// SetScorer makes every saved profile get the score scorer gives it, so
+// stored scores follow the scoring rules as profiles are found and updated
+func (d *Database) SetScorer(scorer func(profile *Profile) int) {
+	d.scorer = scorer
+}
+
+// scoreProfile scores a profile from everything stored about it
+func (d *Database) scoreProfile(url string) error {
+	profile, err := d.GetProfile(url)
+	if err != nil || profile == nil {
+		return err
+	}
+	return d.saveScore(profile.URL, d.scorer(profile))
+}
+
+// RescoreProfiles scores every stored profile again, e.g. after the scoring
+// rules changed, and returns the profiles with their new scores
+func (d *Database) RescoreProfiles(scorer func(profile *Profile) int) ([]*Profile, error) {
+	profiles, err := d.getAllProfiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profiles: %w", err)
+	}
+
+	for _, profile := range profiles {
+		score := scorer(profile)
+		if score == profile.Score {
+			continue
+		}
+		profile.Score = score
+		if err := d.saveScore(profile.URL, score); err != nil {
+			return nil, err
+		}
+	}
+
+	d.logger.WithField("profiles", len(profiles)).Info("Profiles scored")
+	return profiles, nil
+}
+
+func (d *Database) saveScore(url string, score int) error {
+	if _, err := d.db.Exec(`UPDATE profiles SET score = ? WHERE url = ?`, score, profileurl.Canonicalize(url)); err != nil {
+		return fmt.Errorf("failed to save profile score: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// CachedSearch is the stored outcome of a search, returned for identical
+// searches soon after instead of searching LinkedIn again
+type CachedSearch struct {
+	Key       string    // Identifies the account and query searched
+	Session   string    // JSON-encoded search session
+	CreatedAt time.Time // When LinkedIn was searched
+}
+
+// GetCachedSearch returns the search stored under key if it was stored after
+// since, or nil if there is none
+func (d *Database) GetCachedSearch(key string, since time.Time) (*CachedSearch, error) {
+	query := `SELECT query_key, session, created_at FROM search_cache WHERE query_key = ? AND created_at > ?`
+
+	var cached CachedSearch
+	err := d.db.QueryRow(query, key, since.UTC()).Scan(&cached.Key, &cached.Session, &cached.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cached search: %w", err)
+	}
+	return &cached, nil
+}
+
+// SaveCachedSearch stores a search under its key, replacing an older one,
+// and removes searches stored before expiredBefore
+func (d *Database) SaveCachedSearch(cached *CachedSearch, expiredBefore time.Time) error {
+	query := `INSERT INTO search_cache (query_key, session, created_at) VALUES (?, ?, ?)
+			  ON CONFLICT(query_key) DO UPDATE SET session = excluded.session, created_at = excluded.created_at`
+
+	if _, err := d.db.Exec(query, cached.Key, cached.Session, cached.CreatedAt.UTC()); err != nil {
+		return fmt.Errorf("failed to cache search: %w", err)
+	}
+	if _, err := d.db.Exec(`DELETE FROM search_cache WHERE created_at < ?`, expiredBefore.UTC()); err != nil {
+		return fmt.Errorf("failed to remove expired searches: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// Sequence enrollment statuses
+const (
+	EnrollmentActive    = "active"    // Waiting for the next step to become due
+	EnrollmentRunning   = "running"   // The current step is queued as a task
+	EnrollmentCompleted = "completed" // Every step has run
+	EnrollmentReplied   = "replied"   // Stopped because the prospect replied
+	EnrollmentPaused    = "paused"    // Held because the prospect replied with interest, for a person to take over
+	EnrollmentStopped   = "stopped"   // Stopped by hand, by the blacklist or by a cancelled task
+	EnrollmentFailed    = "failed"    // A step's task failed
+)
+
+// SequenceEnrollment is a prospect's progress through a drip sequence
+type SequenceEnrollment struct {
+	ID         int        `json:"id"`
+	Sequence   string     `json:"sequence"`
+	ProfileURL string     `json:"profile_url"`
+	Step       int        `json:"step"` // Index of the next step to run
+	Status     string     `json:"status"`
+	TaskID     int        `json:"task_id,omitempty"` // Queue task running the current step
+	NextRunAt  time.Time  `json:"next_run_at"`
+	LastStepAt *time.Time `json:"last_step_at,omitempty"`
+	LastError  string     `json:"last_error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+const enrollmentColumns = `id, sequence, profile_url, step, status, COALESCE(task_id, 0), next_run_at,
+			  last_step_at, COALESCE(last_error, ''), created_at, updated_at`
+
+// EnrollInSequence starts a prospect on a sequence, reporting whether they were not enrolled already
+func (d *Database) EnrollInSequence(sequence, profileURL string) (bool, error) {
+	query := `INSERT OR IGNORE INTO sequence_enrollments (sequence, profile_url, step, status, next_run_at, created_at, updated_at)
+			  VALUES (?, ?, 0, ?, ?, ?, ?)`
+
+	now := time.Now().UTC()
+	result, err := d.db.Exec(query, sequence, profileurl.Canonicalize(profileURL), EnrollmentActive, now, now, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to enroll in sequence: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get inserted rows: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// GetSequenceEnrollment retrieves a prospect's enrollment in a sequence
+func (d *Database) GetSequenceEnrollment(sequence, profileURL string) (*SequenceEnrollment, error) {
+	query := `SELECT ` + enrollmentColumns + ` FROM sequence_enrollments WHERE sequence = ? AND profile_url = ?`
+
+	enrollment, err := scanEnrollment(d.db.QueryRow(query, sequence, profileurl.Canonicalize(profileURL)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get sequence enrollment: %w", err)
+	}
+
+	return enrollment, nil
+}
+
+// ListSequenceEnrollments retrieves enrollments, optionally filtered by sequence and status
+func (d *Database) ListSequenceEnrollments(sequence, status string) ([]*SequenceEnrollment, error) {
+	query := `SELECT ` + enrollmentColumns + ` FROM sequence_enrollments
+			  WHERE (? = '' OR sequence = ?) AND (? = '' OR status = ?) ORDER BY next_run_at, id`
+
+	rows, err := d.db.Query(query, sequence, sequence, status, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sequence enrollments: %w", err)
+	}
+	defer rows.Close()
+
+	var enrollments []*SequenceEnrollment
+	for rows.Next() {
+		enrollment, err := scanEnrollment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sequence enrollment: %w", err)
+		}
+		enrollments = append(enrollments, enrollment)
+	}
+
+	return enrollments, nil
+}
+
+// GetProfileEnrollments retrieves a prospect's enrollments in every sequence
+func (d *Database) GetProfileEnrollments(profileURL string) ([]*SequenceEnrollment, error) {
+	query := `SELECT ` + enrollmentColumns + ` FROM sequence_enrollments WHERE profile_url = ? ORDER BY id`
+
+	rows, err := d.db.Query(query, profileurl.Canonicalize(profileURL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile enrollments: %w", err)
+	}
+	defer rows.Close()
+
+	var enrollments []*SequenceEnrollment
+	for rows.Next() {
+		enrollment, err := scanEnrollment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sequence enrollment: %w", err)
+		}
+		enrollments = append(enrollments, enrollment)
+	}
+
+	return enrollments, nil
+}
+
+// UpdateSequenceEnrollment saves the progress of an enrollment
+func (d *Database) UpdateSequenceEnrollment(enrollment *SequenceEnrollment) error {
+	query := `UPDATE sequence_enrollments SET step = ?, status = ?, task_id = ?, next_run_at = ?, last_step_at = ?,
+			  last_error = ?, updated_at = ? WHERE id = ?`
+
+	var lastStepAt interface{}
+	if enrollment.LastStepAt != nil {
+		lastStepAt = enrollment.LastStepAt.UTC()
+	}
+
+	enrollment.UpdatedAt = time.Now()
+	if _, err := d.db.Exec(query, enrollment.Step, enrollment.Status, enrollment.TaskID, enrollment.NextRunAt.UTC(),
+		lastStepAt, enrollment.LastError, enrollment.UpdatedAt.UTC(), enrollment.ID); err != nil {
+		return fmt.Errorf("failed to update sequence enrollment: %w", err)
+	}
+
+	d.logger.WithField("enrollment_id", enrollment.ID).WithField("status", enrollment.Status).Debug("Sequence enrollment updated")
+	return nil
+}
+
+// GetConnectionStatus returns the status of the latest connection request sent
+// to a profile and when it was accepted, or an empty status if none was sent
+func (d *Database) GetConnectionStatus(profileURL string) (string, *time.Time, error) {
+	query := `SELECT status, accepted_at FROM connection_requests
+			  WHERE profile_url = ? AND dry_run = 0 ORDER BY sent_at DESC, id DESC LIMIT 1`
+
+	var status string
+	var acceptedAt sql.NullTime
+	err := d.db.QueryRow(query, profileurl.Canonicalize(profileURL)).Scan(&status, &acceptedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil, nil
+		}
+		return "", nil, fmt.Errorf("failed to get connection status: %w", err)
+	}
+
+	if acceptedAt.Valid {
+		return status, &acceptedAt.Time, nil
+	}
+	return status, nil, nil
+}
+
+func scanEnrollment(row rowScanner) (*SequenceEnrollment, error) {
+	var enrollment SequenceEnrollment
+	var lastStepAt sql.NullTime
+	err := row.Scan(&enrollment.ID, &enrollment.Sequence, &enrollment.ProfileURL, &enrollment.Step, &enrollment.Status,
+		&enrollment.TaskID, &enrollment.NextRunAt, &lastStepAt, &enrollment.LastError, &enrollment.CreatedAt, &enrollment.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastStepAt.Valid {
+		enrollment.LastStepAt = &lastStepAt.Time
+	}
+	return &enrollment, nil
+}
@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// Tag is a label for segmenting prospects, e.g. "conference-2024" or "warm"
+type Tag struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Prospects int       `json:"prospects"` // Number of profiles with the tag
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NormalizeTag returns the stored form of a tag name: trimmed and lower case
+func NormalizeTag(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// TagProspects adds a tag to profiles, creating the tag if needed. It returns
+// how many of the profiles did not have the tag yet.
+func (d *Database) TagProspects(name string, profileURLs []string) (int, error) {
+	name = NormalizeTag(name)
+	if name == "" {
+		return 0, fmt.Errorf("tag name is required")
+	}
+
+	if _, err := d.db.Exec(`INSERT OR IGNORE INTO tags (name, created_at) VALUES (?, ?)`, name, time.Now().UTC()); err != nil {
+		return 0, fmt.Errorf("failed to create tag: %w", err)
+	}
+	var tagID int
+	if err := d.db.QueryRow(`SELECT id FROM tags WHERE name = ?`, name).Scan(&tagID); err != nil {
+		return 0, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	query := `INSERT OR IGNORE INTO prospect_tags (tag_id, profile_url, created_at) VALUES (?, ?, ?)`
+
+	added := 0
+	now := time.Now().UTC()
+	for _, profileURL := range profileURLs {
+		result, err := d.db.Exec(query, tagID, profileurl.Canonicalize(profileURL), now)
+		if err != nil {
+			return added, fmt.Errorf("failed to tag prospect: %w", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return added, fmt.Errorf("failed to get inserted rows: %w", err)
+		}
+		added += int(affected)
+	}
+
+	d.logger.WithField("tag", name).WithField("added", added).Debug("Prospects tagged")
+	return added, nil
+}
+
+// UntagProspects removes a tag from profiles, returning how many had it
+func (d *Database) UntagProspects(name string, profileURLs []string) (int, error) {
+	query := `DELETE FROM prospect_tags WHERE profile_url = ? AND tag_id IN (SELECT id FROM tags WHERE name = ?)`
+
+	removed := 0
+	for _, profileURL := range profileURLs {
+		result, err := d.db.Exec(query, profileurl.Canonicalize(profileURL), NormalizeTag(name))
+		if err != nil {
+			return removed, fmt.Errorf("failed to untag prospect: %w", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return removed, fmt.Errorf("failed to get deleted rows: %w", err)
+		}
+		removed += int(affected)
+	}
+
+	d.logger.WithField("tag", name).WithField("removed", removed).Debug("Prospects untagged")
+	return removed, nil
+}
+
+// DeleteTag removes a tag from every profile and deletes it, reporting whether it existed
+func (d *Database) DeleteTag(name string) (bool, error) {
+	name = NormalizeTag(name)
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM prospect_tags WHERE tag_id IN (SELECT id FROM tags WHERE name = ?)`, name); err != nil {
+		return false, fmt.Errorf("failed to untag prospects: %w", err)
+	}
+	result, err := tx.Exec(`DELETE FROM tags WHERE name = ?`, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete tag: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get deleted rows: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit tag deletion: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// ListTags returns every tag with its number of prospects, ordered by name
+func (d *Database) ListTags() ([]*Tag, error) {
+	query := `SELECT t.id, t.name, COUNT(pt.profile_url), t.created_at FROM tags t
+			  LEFT JOIN prospect_tags pt ON pt.tag_id = t.id
+			  GROUP BY t.id, t.name, t.created_at ORDER BY t.name`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []*Tag
+	for rows.Next() {
+		var tag Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.Prospects, &tag.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, &tag)
+	}
+
+	return tags, nil
+}
+
+// GetTaggedProfiles returns the profiles that have every one of the tags, in
+// the order they were first tagged
+func (d *Database) GetTaggedProfiles(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, 0, len(names)+1)
+	for _, name := range names {
+		args = append(args, NormalizeTag(name))
+	}
+	args = append(args, len(names))
+
+	query := `SELECT pt.profile_url FROM prospect_tags pt JOIN tags t ON t.id = pt.tag_id
+			  WHERE t.name IN (?` + strings.Repeat(", ?", len(names)-1) + `)
+			  GROUP BY pt.profile_url HAVING COUNT(DISTINCT t.id) = ?
+			  ORDER BY MIN(pt.created_at), pt.profile_url`
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tagged profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []string
+	for rows.Next() {
+		var profileURL string
+		if err := rows.Scan(&profileURL); err != nil {
+			return nil, fmt.Errorf("failed to scan tagged profile: %w", err)
+		}
+		profiles = append(profiles, profileURL)
+	}
+
+	return profiles, nil
+}
+
+// GetProspectTags returns the tags of a profile, ordered by name
+func (d *Database) GetProspectTags(profileURL string) ([]string, error) {
+	query := `SELECT t.name FROM tags t JOIN prospect_tags pt ON pt.tag_id = t.id
+			  WHERE pt.profile_url = ? ORDER BY t.name`
+
+	rows, err := d.db.Query(query, profileurl.Canonicalize(profileURL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prospect tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan prospect tag: %w", err)
+		}
+		tags = append(tags, name)
+	}
+
+	return tags, nil
+}
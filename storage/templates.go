@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Template represents a user-defined connection or message template
+type Template struct {
+	ID             int       `json:"id"`
+	Name           string    `json:"name"`
+	Kind           string    `json:"kind"` // connection, message
+	Content        string    `json:"content"`
+	Type           string    `json:"type,omitempty"` // follow_up, custom
+	Variables      []string  `json:"variables"`
+	CharacterLimit int       `json:"character_limit"`
+	BuiltIn        bool      `json:"built_in"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// SaveTemplate creates or updates a template by name
+func (d *Database) SaveTemplate(template *Template) error {
+	query := `INSERT INTO templates (name, kind, content, type, variables, character_limit, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+			  ON CONFLICT(name) DO UPDATE SET
+			  kind = excluded.kind, content = excluded.content, type = excluded.type,
+			  variables = excluded.variables, character_limit = excluded.character_limit, updated_at = CURRENT_TIMESTAMP`
+
+	_, err := d.db.Exec(query, template.Name, template.Kind, template.Content, template.Type,
+		strings.Join(template.Variables, ","), template.CharacterLimit)
+	if err != nil {
+		return fmt.Errorf("failed to save template: %w", err)
+	}
+
+	d.logger.WithField("template", template.Name).Debug("Template saved")
+	return nil
+}
+
+// GetTemplate retrieves a template by name
+func (d *Database) GetTemplate(name string) (*Template, error) {
+	query := `SELECT id, name, kind, content, COALESCE(type, ''), COALESCE(variables, ''), character_limit, created_at, updated_at
+			  FROM templates WHERE name = ?`
+
+	template, err := scanTemplate(d.db.QueryRow(query, name))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+
+	return template, nil
+}
+
+// ListTemplates retrieves all templates, optionally filtered by kind
+func (d *Database) ListTemplates(kind string) ([]*Template, error) {
+	query := `SELECT id, name, kind, content, COALESCE(type, ''), COALESCE(variables, ''), character_limit, created_at, updated_at
+			  FROM templates WHERE (? = '' OR kind = ?) ORDER BY kind, name`
+
+	rows, err := d.db.Query(query, kind, kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*Template
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan template: %w", err)
+		}
+		templates = append(templates, template)
+	}
+
+	return templates, nil
+}
+
+// DeleteTemplate deletes a template by name, reporting whether it existed
+func (d *Database) DeleteTemplate(name string) (bool, error) {
+	result, err := d.db.Exec(`DELETE FROM templates WHERE name = ?`, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get deleted rows: %w", err)
+	}
+
+	d.logger.WithField("template", name).Debug("Template deleted")
+	return affected > 0, nil
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTemplate(row rowScanner) (*Template, error) {
+	var template Template
+	var variables string
+	err := row.Scan(&template.ID, &template.Name, &template.Kind, &template.Content, &template.Type,
+		&variables, &template.CharacterLimit, &template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if variables != "" {
+		template.Variables = strings.Split(variables, ",")
+	}
+	return &template, nil
+}
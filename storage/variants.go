@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"fmt"
+
+	"linkedin-automation/profileurl"
+)
+
+// VariantStats summarizes the connection requests sent with one A/B variant
+type VariantStats struct {
+	Campaign string `json:"campaign"`
+	Variant  string `json:"variant"`
+	Sent     int    `json:"sent"`
+	Accepted int    `json:"accepted"`
+}
+
+// AcceptanceRate returns the share of sent requests that were accepted, as a percentage
+func (s *VariantStats) AcceptanceRate() float64 {
+	if s.Sent == 0 {
+		return 0
+	}
+	return float64(s.Accepted) * 100 / float64(s.Sent)
+}
+
+// GetVariantStats returns per-variant request and acceptance counts, for one
+// campaign or for every campaign when campaign is empty
+func (d *Database) GetVariantStats(campaign string) ([]*VariantStats, error) {
+	query := `SELECT COALESCE(campaign, ''), variant, COUNT(*),
+			  SUM(CASE WHEN status = 'accepted' THEN 1 ELSE 0 END)
+			  FROM connection_requests
+			  WHERE variant IS NOT NULL AND variant != '' AND dry_run = 0 AND COALESCE(action, 'connect') = 'connect' AND (? = '' OR campaign = ?)
+			  GROUP BY campaign, variant ORDER BY campaign, variant`
+
+	rows, err := d.db.Query(query, campaign, campaign)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variant stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*VariantStats
+	for rows.Next() {
+		var s VariantStats
+		if err := rows.Scan(&s.Campaign, &s.Variant, &s.Sent, &s.Accepted); err != nil {
+			return nil, fmt.Errorf("failed to scan variant stats: %w", err)
+		}
+		stats = append(stats, &s)
+	}
+
+	return stats, nil
+}
+
+// MarkConnectionAccepted marks pending requests to a profile as accepted, reporting whether any were found
+func (d *Database) MarkConnectionAccepted(profileURL string) (bool, error) {
+	query := `UPDATE connection_requests SET status = 'accepted', accepted_at = CURRENT_TIMESTAMP
+			  WHERE profile_url = ? AND status = 'pending' AND dry_run = 0`
+
+	result, err := d.db.Exec(query, profileurl.Canonicalize(profileURL))
+	if err != nil {
+		return false, fmt.Errorf("failed to mark connection accepted: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get updated rows: %w", err)
+	}
+	return affected > 0, nil
+}
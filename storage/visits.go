@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"linkedin-automation/profileurl"
+)
+
+// ProfileVisit represents a profile that was opened and read
+type ProfileVisit struct {
+	ID         int           `json:"id"`
+	ProfileURL string        `json:"profile_url"`
+	Dwell      time.Duration `json:"dwell"`
+	VisitedAt  time.Time     `json:"visited_at"`
+}
+
+// RecordProfileVisit saves a profile visit
+func (d *Database) RecordProfileVisit(visit *ProfileVisit) error {
+	visit.ProfileURL = profileurl.Canonicalize(visit.ProfileURL)
+
+	query := `INSERT INTO profile_visits (profile_url, dwell_seconds, visited_at, run_id) VALUES (?, ?, ?, NULLIF(?, ''))`
+
+	id, err := d.db.insert(query, visit.ProfileURL, int(visit.Dwell.Seconds()), visit.VisitedAt.UTC(), d.runID)
+	if err != nil {
+		return fmt.Errorf("failed to record profile visit: %w", err)
+	}
+
+	visit.ID = int(id)
+	d.logger.WithField("profile_url", visit.ProfileURL).Debug("Profile visit recorded")
+	return nil
+}
+
+// GetVisitedProfiles returns the canonical URLs of profiles visited since the given time
+func (d *Database) GetVisitedProfiles(since time.Time) (map[string]bool, error) {
+	rows, err := d.db.Query(`SELECT DISTINCT profile_url FROM profile_visits WHERE visited_at >= ?`, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get visited profiles: %w", err)
+	}
+	defer rows.Close()
+
+	visited := make(map[string]bool)
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, fmt.Errorf("failed to scan visited profile: %w", err)
+		}
+		visited[url] = true
+	}
+
+	return visited, nil
+}
+
+// CountProfileVisits returns the number of visits recorded since the given time
+func (d *Database) CountProfileVisits(since time.Time) (int, error) {
+	var count int
+	if err := d.db.QueryRow(`SELECT COUNT(*) FROM profile_visits WHERE visited_at >= ?`, since.UTC()).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count profile visits: %w", err)
+	}
+	return count, nil
+}
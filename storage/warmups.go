@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Warmup is how far an account has come in warming up, during which its
+// limits ramp up from low starting values week by week
+type Warmup struct {
+	Account      string     `json:"account,omitempty"` // Empty for the top-level account
+	StartedAt    time.Time  `json:"started_at"`
+	Week         int        `json:"week"` // 1 in the first week of the ramp
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	LastActiveAt time.Time  `json:"last_active_at"` // The last action, to notice an account falling idle
+}
+
+// GetWarmup returns the warm-up of account, or nil when it never started one
+func (d *Database) GetWarmup(account string) (*Warmup, error) {
+	query := `SELECT account, started_at, week, completed_at, last_active_at FROM warmups WHERE account = ?`
+
+	var warmup Warmup
+	var completedAt sql.NullTime
+	err := d.db.QueryRow(query, account).Scan(&warmup.Account, &warmup.StartedAt, &warmup.Week,
+		&completedAt, &warmup.LastActiveAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warm-up: %w", err)
+	}
+
+	if completedAt.Valid {
+		warmup.CompletedAt = &completedAt.Time
+	}
+	return &warmup, nil
+}
+
+// SaveWarmup creates or replaces the warm-up of its account
+func (d *Database) SaveWarmup(warmup *Warmup) error {
+	query := `INSERT INTO warmups (account, started_at, week, completed_at, last_active_at) VALUES (?, ?, ?, ?, ?)
+			  ON CONFLICT(account) DO UPDATE SET
+			  started_at = excluded.started_at, week = excluded.week,
+			  completed_at = excluded.completed_at, last_active_at = excluded.last_active_at`
+
+	var completedAt sql.NullTime
+	if warmup.CompletedAt != nil {
+		completedAt = sql.NullTime{Time: warmup.CompletedAt.UTC(), Valid: true}
+	}
+	if _, err := d.db.Exec(query, warmup.Account, warmup.StartedAt.UTC(), warmup.Week,
+		completedAt, warmup.LastActiveAt.UTC()); err != nil {
+		return fmt.Errorf("failed to save warm-up: %w", err)
+	}
+	return nil
+}
+
+// DeleteWarmup forgets the warm-up of account, so the next session starts a
+// new one; it reports whether there was one
+func (d *Database) DeleteWarmup(account string) (bool, error) {
+	result, err := d.db.Exec(`DELETE FROM warmups WHERE account = ?`, account)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete warm-up: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete warm-up: %w", err)
+	}
+	return deleted > 0, nil
+}
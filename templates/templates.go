@@ -0,0 +1,277 @@
+package templates
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/connect"
+	"linkedin-automation/engage"
+	"linkedin-automation/message"
+	"linkedin-automation/personalize"
+	"linkedin-automation/storage"
+)
+
+// Template kinds
+const (
+	KindConnection = "connection"
+	KindMessage    = "message"
+	KindComment    = "comment"
+)
+
+// ConnectionNoteLimit is LinkedIn's maximum length for a connection note,
+// which Premium accounts get
+const ConnectionNoteLimit = 300
+
+// FreeConnectionNoteLimit is the maximum length of a connection note sent
+// from a free account
+const FreeConnectionNoteLimit = 200
+
+// MessageLimit is a sensible default length cap for follow-up messages
+const MessageLimit = 8000
+
+// CommentLimit is LinkedIn's maximum length for a post comment
+const CommentLimit = 1250
+
+// Manager resolves templates from storage, falling back to the built-in defaults
+type Manager struct {
+	store  Store
+	logger *logrus.Logger
+}
+
+// Store persists user-defined templates
+type Store interface {
+	SaveTemplate(template *storage.Template) error
+	GetTemplate(name string) (*storage.Template, error)
+	ListTemplates(kind string) ([]*storage.Template, error)
+	DeleteTemplate(name string) (bool, error)
+}
+
+// NewManager creates a new template manager
+func NewManager(store Store, logger *logrus.Logger) *Manager {
+	return &Manager{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Get returns the named template of the given kind; stored templates take
+// precedence over built-in ones with the same name
+func (m *Manager) Get(kind, name string) (*storage.Template, error) {
+	stored, err := m.store.GetTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		if kind != "" && stored.Kind != kind {
+			return nil, fmt.Errorf("template %q is a %s template, not %s", name, stored.Kind, kind)
+		}
+		return stored, nil
+	}
+
+	for _, t := range BuiltIn(kind) {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("template %q not found", name)
+}
+
+// List returns stored and built-in templates, optionally filtered by kind
+func (m *Manager) List(kind string) ([]*storage.Template, error) {
+	stored, err := m.store.ListTemplates(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	overridden := make(map[string]bool)
+	for _, t := range stored {
+		overridden[t.Name] = true
+	}
+
+	result := stored
+	for _, t := range BuiltIn(kind) {
+		if !overridden[t.Name] {
+			result = append(result, t)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// Save validates and stores a template
+func (m *Manager) Save(template *storage.Template) error {
+	if len(template.Variables) == 0 {
+		template.Variables = Placeholders(template.Content)
+	}
+	if template.CharacterLimit == 0 {
+		template.CharacterLimit = DefaultLimit(template.Kind)
+	}
+
+	if err := Validate(template); err != nil {
+		return err
+	}
+
+	if err := m.store.SaveTemplate(template); err != nil {
+		return err
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"template": template.Name,
+		"kind":     template.Kind,
+	}).Info("Template saved")
+	return nil
+}
+
+// Delete removes a stored template; built-in templates cannot be deleted
+func (m *Manager) Delete(name string) error {
+	deleted, err := m.store.DeleteTemplate(name)
+	if err != nil {
+		return err
+	}
+	if deleted {
+		return nil
+	}
+
+	for _, t := range BuiltIn("") {
+		if t.Name == name {
+			return fmt.Errorf("template %q is built-in and cannot be deleted", name)
+		}
+	}
+	return fmt.Errorf("template %q not found", name)
+}
+
+// Validate checks a template's kind, content, declared variables and length
+func Validate(template *storage.Template) error {
+	if strings.TrimSpace(template.Name) == "" {
+		return fmt.Errorf("template name is required")
+	}
+	if template.Kind != KindConnection && template.Kind != KindMessage && template.Kind != KindComment {
+		return fmt.Errorf("template kind must be %q, %q or %q", KindConnection, KindMessage, KindComment)
+	}
+	if strings.TrimSpace(template.Content) == "" {
+		return fmt.Errorf("template content is required")
+	}
+
+	if err := personalize.Validate(template.Content); err != nil {
+		return err
+	}
+
+	declared := make(map[string]bool)
+	for _, v := range template.Variables {
+		declared[v] = true
+	}
+	for _, v := range Placeholders(template.Content) {
+		if !declared[v] {
+			return fmt.Errorf("template uses undeclared variable %q", v)
+		}
+	}
+
+	if template.Kind == KindConnection && template.CharacterLimit > ConnectionNoteLimit {
+		return fmt.Errorf("connection templates are limited to %d characters", ConnectionNoteLimit)
+	}
+	if template.Kind == KindComment && template.CharacterLimit > CommentLimit {
+		return fmt.Errorf("comment templates are limited to %d characters", CommentLimit)
+	}
+	if template.CharacterLimit > 0 {
+		if length := StaticLength(template.Content); length > template.CharacterLimit {
+			return fmt.Errorf("template is %d characters before variables are filled, over the %d character limit", length, template.CharacterLimit)
+		}
+	}
+
+	return nil
+}
+
+// Placeholders returns the distinct variable names used in content, in order of appearance
+func Placeholders(content string) []string {
+	return personalize.Placeholders(content)
+}
+
+// StaticLength returns the length of content before variables are filled
+func StaticLength(content string) int {
+	return personalize.StaticLength(content)
+}
+
+// NoteLimit returns the maximum length of a connection note for the account type
+func NoteLimit(premium bool) int {
+	if premium {
+		return ConnectionNoteLimit
+	}
+	return FreeConnectionNoteLimit
+}
+
+// CheckNote returns an error if the connection note content is longer than
+// limit before its variables are filled, so it could never go out whole
+func CheckNote(content string, limit int) error {
+	if length := StaticLength(content); limit > 0 && length > limit {
+		return fmt.Errorf("connection note is %d characters before variables are filled, over the %d character limit of the account", length, limit)
+	}
+	return nil
+}
+
+// DefaultLimit returns the default character limit for a template kind
+func DefaultLimit(kind string) int {
+	switch kind {
+	case KindConnection:
+		return ConnectionNoteLimit
+	case KindComment:
+		return CommentLimit
+	}
+	return MessageLimit
+}
+
+// BuiltIn returns the compiled-in default templates, optionally filtered by kind
+func BuiltIn(kind string) []*storage.Template {
+	var result []*storage.Template
+
+	if kind == "" || kind == KindConnection {
+		for _, t := range connect.GetDefaultTemplates() {
+			result = append(result, &storage.Template{
+				Name:           t.ID,
+				Kind:           KindConnection,
+				Content:        t.Content,
+				Variables:      t.Variables,
+				CharacterLimit: ConnectionNoteLimit,
+				BuiltIn:        true,
+			})
+		}
+	}
+
+	if kind == "" || kind == KindMessage {
+		for _, t := range message.GetDefaultMessageTemplates() {
+			result = append(result, &storage.Template{
+				Name:           t.ID,
+				Kind:           KindMessage,
+				Content:        t.Content,
+				Type:           t.Type,
+				Variables:      t.Variables,
+				CharacterLimit: t.CharacterLimit,
+				BuiltIn:        true,
+			})
+		}
+	}
+
+	if kind == "" || kind == KindComment {
+		for _, t := range engage.GetDefaultCommentTemplates() {
+			result = append(result, &storage.Template{
+				Name:           t.ID,
+				Kind:           KindComment,
+				Content:        t.Content,
+				Variables:      t.Variables,
+				CharacterLimit: CommentLimit,
+				BuiltIn:        true,
+			})
+		}
+	}
+
+	return result
+}
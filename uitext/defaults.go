@@ -0,0 +1,197 @@
+package uitext
+
+// Buttons and labels
+const (
+	Connect         Key = "connect"
+	Connected       Key = "connected"
+	Pending         Key = "pending"
+	Message         Key = "message"
+	MoreActions     Key = "more_actions"
+	InviteToConnect Key = "invite_to_connect" // Part of the aria-label of Connect in the More menu
+	SendInvitation  Key = "send_invitation"
+	AddNote         Key = "add_note"
+	SendWithoutNote Key = "send_without_note"
+	Send            Key = "send"
+	Other           Key = "other"
+	Dismiss         Key = "dismiss"
+	Next            Key = "next"
+	ShowMoreResults Key = "show_more_results"
+	WriteMessage    Key = "write_message"
+	Endorse         Key = "endorse"
+	Endorsed        Key = "endorsed"
+	Like            Key = "like"
+	Comment         Key = "comment"
+	Follow          Key = "follow"
+	Following       Key = "following"
+	Unfollow        Key = "unfollow"
+)
+
+// Alerts and list details
+const (
+	WeeklyLimit      Key = "weekly_limit"
+	Today            Key = "today"
+	JustNow          Key = "just_now"
+	MutualConnection Key = "mutual_connection"
+	OpenProfile      Key = "open_profile" // Shown in the InMail form when messaging the member is free
+	Employees        Key = "employees"    // Follows the company size on company pages
+	Restricted       Key = "restricted"   // Banners and pages of a restricted or temporarily blocked account
+)
+
+// builtin is the wording per language. English must list every key.
+var builtin = map[string]map[Key][]string{
+	English: {
+		Connect:          {"Connect"},
+		Connected:        {"Connected"},
+		Pending:          {"Pending"},
+		Message:          {"Message"},
+		MoreActions:      {"More actions"},
+		InviteToConnect:  {"to connect"},
+		SendInvitation:   {"Send invitation"},
+		AddNote:          {"Add a note"},
+		SendWithoutNote:  {"Send without a note"},
+		Send:             {"Send"},
+		Other:            {"Other"},
+		Dismiss:          {"Dismiss"},
+		Next:             {"Next"},
+		ShowMoreResults:  {"Show more results"},
+		WriteMessage:     {"Write a message"},
+		Endorse:          {"Endorse"},
+		Endorsed:         {"Endorsed"},
+		Like:             {"Like"},
+		Comment:          {"Comment"},
+		Follow:           {"Follow"},
+		Following:        {"Following"},
+		Unfollow:         {"Unfollow"},
+		WeeklyLimit:      {"weekly invitation limit"},
+		Today:            {"today"},
+		JustNow:          {"just now"},
+		MutualConnection: {"mutual connection"},
+		OpenProfile:      {"Open Profile", "free message"},
+		Employees:        {"employees"},
+		Restricted:       {"unusual activity", "temporarily restricted", "account has been restricted", "account is restricted"},
+	},
+	"de": {
+		Connect:          {"Vernetzen"},
+		Connected:        {"Vernetzt"},
+		Pending:          {"Ausstehend"},
+		Message:          {"Nachricht"},
+		MoreActions:      {"Weitere Aktionen"},
+		InviteToConnect:  {"zu vernetzen"},
+		SendInvitation:   {"Einladung senden", "Senden"},
+		AddNote:          {"Nachricht hinzufügen"},
+		SendWithoutNote:  {"Ohne Nachricht senden"},
+		Send:             {"Senden"},
+		Other:            {"Sonstiges"},
+		Dismiss:          {"Verwerfen", "Schließen"},
+		Next:             {"Weiter"},
+		ShowMoreResults:  {"Weitere Ergebnisse anzeigen"},
+		WriteMessage:     {"Nachricht verfassen"},
+		Endorse:          {"Bestätigen"},
+		Endorsed:         {"Bestätigt"},
+		Like:             {"Gefällt mir"},
+		Comment:          {"Kommentieren"},
+		Follow:           {"Folgen"},
+		Following:        {"Folge ich", "Gefolgt"},
+		Unfollow:         {"Nicht mehr folgen"},
+		WeeklyLimit:      {"wöchentliche"},
+		Today:            {"heute"},
+		JustNow:          {"gerade eben"},
+		MutualConnection: {"gemeinsame"},
+		OpenProfile:      {"Open Profile", "kostenlose Nachricht"},
+		Employees:        {"Beschäftigte", "Mitarbeiter"},
+		Restricted:       {"ungewöhnliche Aktivität", "vorübergehend eingeschränkt", "Konto wurde eingeschränkt"},
+	},
+	"es": {
+		Connect:          {"Conectar"},
+		Connected:        {"Conectado"},
+		Pending:          {"Pendiente"},
+		Message:          {"Mensaje"},
+		MoreActions:      {"Más acciones"},
+		InviteToConnect:  {"a conectar"},
+		SendInvitation:   {"Enviar invitación", "Enviar"},
+		AddNote:          {"Añadir una nota"},
+		SendWithoutNote:  {"Enviar sin nota"},
+		Send:             {"Enviar"},
+		Other:            {"Otro"},
+		Dismiss:          {"Descartar", "Cerrar"},
+		Next:             {"Siguiente"},
+		ShowMoreResults:  {"Mostrar más resultados"},
+		WriteMessage:     {"Escribe un mensaje"},
+		Endorse:          {"Validar"},
+		Endorsed:         {"Validada", "Validado"},
+		Like:             {"Recomendar"},
+		Comment:          {"Comentar"},
+		Follow:           {"Seguir"},
+		Following:        {"Siguiendo"},
+		Unfollow:         {"Dejar de seguir"},
+		WeeklyLimit:      {"límite semanal"},
+		Today:            {"hoy"},
+		JustNow:          {"ahora mismo"},
+		MutualConnection: {"en común"},
+		OpenProfile:      {"Open Profile", "mensaje gratuito"},
+		Employees:        {"empleados"},
+		Restricted:       {"actividad inusual", "restringida temporalmente", "cuenta ha sido restringida"},
+	},
+	"fr": {
+		Connect:          {"Se connecter"},
+		Connected:        {"Connecté", "Relation"},
+		Pending:          {"En attente"},
+		Message:          {"Message"},
+		MoreActions:      {"Plus d’actions", "Plus d'actions"},
+		InviteToConnect:  {"rejoindre votre réseau"},
+		SendInvitation:   {"Envoyer l’invitation", "Envoyer l'invitation", "Envoyer"},
+		AddNote:          {"Ajouter une note"},
+		SendWithoutNote:  {"Envoyer sans note"},
+		Send:             {"Envoyer"},
+		Other:            {"Autre"},
+		Dismiss:          {"Ignorer", "Fermer"},
+		Next:             {"Suivant"},
+		ShowMoreResults:  {"Afficher plus de résultats"},
+		WriteMessage:     {"Rédigez un message", "Écrire un message"},
+		Endorse:          {"Recommander"},
+		Endorsed:         {"Recommandé", "Recommandée"},
+		Like:             {"J’aime", "J'aime"},
+		Comment:          {"Commenter"},
+		Follow:           {"Suivre"},
+		Following:        {"Abonné", "Suivi"},
+		Unfollow:         {"Ne plus suivre"},
+		WeeklyLimit:      {"limite hebdomadaire"},
+		Today:            {"aujourd’hui", "aujourd'hui"},
+		JustNow:          {"à l’instant", "à l'instant"},
+		MutualConnection: {"en commun"},
+		OpenProfile:      {"Open Profile", "message gratuit"},
+		Employees:        {"employés"},
+		Restricted:       {"activité inhabituelle", "temporairement restreint", "compte a été restreint"},
+	},
+	"pt": {
+		Connect:          {"Conectar"},
+		Connected:        {"Conectado"},
+		Pending:          {"Pendente"},
+		Message:          {"Mensagem"},
+		MoreActions:      {"Mais ações"},
+		InviteToConnect:  {"para se conectar"},
+		SendInvitation:   {"Enviar convite", "Enviar"},
+		AddNote:          {"Adicionar nota"},
+		SendWithoutNote:  {"Enviar sem nota"},
+		Send:             {"Enviar"},
+		Other:            {"Outro"},
+		Dismiss:          {"Descartar", "Fechar"},
+		Next:             {"Avançar", "Próximo"},
+		ShowMoreResults:  {"Exibir mais resultados"},
+		WriteMessage:     {"Escreva uma mensagem"},
+		Endorse:          {"Recomendar"},
+		Endorsed:         {"Recomendado", "Recomendada"},
+		Like:             {"Gostei"},
+		Comment:          {"Comentar"},
+		Follow:           {"Seguir"},
+		Following:        {"Seguindo"},
+		Unfollow:         {"Deixar de seguir"},
+		WeeklyLimit:      {"limite semanal"},
+		Today:            {"hoje"},
+		JustNow:          {"agora"},
+		MutualConnection: {"em comum"},
+		OpenProfile:      {"Open Profile", "mensagem gratuita"},
+		Employees:        {"funcionários"},
+		Restricted:       {"atividade incomum", "restringida temporariamente", "conta foi restringida"},
+	},
+}
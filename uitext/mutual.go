@@ -0,0 +1,32 @@
+package uitext
+
+import (
+	"regexp"
+	"strconv"
+)
+
+var (
+	otherMutualPattern = regexp.MustCompile(`(?i)(\d+)\s+other\s+mutual`)
+	mutualPattern      = regexp.MustCompile(`(?i)(\d+)\s+mutual`)
+)
+
+// MutualCount reads how many mutual connections texts such as "Jane Doe and
+// 12 other mutual connections" or "3 mutual connections" speak of, 0 when
+// text does not mention any
+func MutualCount(text string) int {
+	if text == "" {
+		return 0
+	}
+	if match := otherMutualPattern.FindStringSubmatch(text); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		return n + 1
+	}
+	if match := mutualPattern.FindStringSubmatch(text); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		return n
+	}
+	if Contains(text, MutualConnection) {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,158 @@
+// Package uitext holds the LinkedIn interface wording the automation looks
+// for in buttons, labels and alerts, in each supported UI language. Text is
+// matched in the account's configured language and in English, since
+// LinkedIn leaves some labels untranslated.
+package uitext
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Key names a piece of interface wording
+type Key string
+
+// English is the language LinkedIn falls back to, and the default
+const English = "en"
+
+var (
+	mu        sync.RWMutex
+	language  = English
+	overrides map[Key][]string
+)
+
+// Languages returns the languages with built-in wording, sorted
+func Languages() []string {
+	languages := make([]string, 0, len(builtin))
+	for lang := range builtin {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Check validates a language and overrides the way Configure would use them
+func Check(lang string, custom map[string][]string) error {
+	if lang == "" {
+		lang = English
+	}
+	if _, ok := builtin[lang]; !ok && len(custom) == 0 {
+		return fmt.Errorf("unknown UI language %q (built in: %s); add its wording under ui_text", lang, strings.Join(Languages(), ", "))
+	}
+	for name, list := range custom {
+		if _, ok := builtin[English][Key(name)]; !ok {
+			return fmt.Errorf("unknown UI text key %q", name)
+		}
+		if len(list) == 0 {
+			return fmt.Errorf("UI text key %q needs at least one string", name)
+		}
+	}
+	return nil
+}
+
+// Configure sets the account's UI language, with custom wording taking the
+// place of the built-in wording for its keys. A language without built-in
+// wording works once its keys are given as custom wording; English covers
+// the rest.
+func Configure(lang string, custom map[string][]string) error {
+	if err := Check(lang, custom); err != nil {
+		return err
+	}
+	if lang == "" {
+		lang = English
+	}
+
+	converted := make(map[Key][]string, len(custom))
+	for name, list := range custom {
+		converted[Key(name)] = append([]string(nil), list...)
+	}
+
+	mu.Lock()
+	language, overrides = lang, converted
+	mu.Unlock()
+	return nil
+}
+
+// Get returns the wording for key in the configured language, then in English
+func Get(key Key) []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var result []string
+	if list, ok := overrides[key]; ok {
+		result = append(result, list...)
+	} else if list, ok := builtin[language][key]; ok {
+		result = append(result, list...)
+	}
+	if language != English {
+		result = append(result, builtin[English][key]...)
+	}
+	return result
+}
+
+// Contains reports whether text contains any wording for key, ignoring case
+func Contains(text string, key Key) bool {
+	text = strings.ToLower(text)
+	for _, s := range Get(key) {
+		if strings.Contains(text, strings.ToLower(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPrefix reports whether text starts with any wording for key, ignoring case
+func HasPrefix(text string, key Key) bool {
+	text = strings.ToLower(strings.TrimSpace(text))
+	for _, s := range Get(key) {
+		if strings.HasPrefix(text, strings.ToLower(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Equal reports whether text, trimmed, is any wording for key, ignoring case
+func Equal(text string, key Key) bool {
+	text = strings.TrimSpace(text)
+	for _, s := range Get(key) {
+		if strings.EqualFold(text, s) {
+			return true
+		}
+	}
+	return false
+}
+
+var placeholder = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// Expand turns a CSS selector with {key} placeholders inside quoted
+// attribute values, e.g. button[aria-label*='{connect}'], into one selector
+// per wording for the key. Selectors without placeholders are returned as is.
+func Expand(selector string) []string {
+	match := placeholder.FindStringSubmatchIndex(selector)
+	if match == nil {
+		return []string{selector}
+	}
+
+	key := Key(selector[match[2]:match[3]])
+	before, after := selector[:match[0]], selector[match[1]:]
+	var result []string
+	for _, s := range Get(key) {
+		s = strings.NewReplacer(`\`, `\\`, `'`, `\'`, `"`, `\"`).Replace(s)
+		result = append(result, Expand(before+s+after)...)
+	}
+	return result
+}
+
+// CheckSelector reports placeholders in selector that name no known key
+func CheckSelector(selector string) error {
+	for _, match := range placeholder.FindAllStringSubmatch(selector, -1) {
+		if _, ok := builtin[English][Key(match[1])]; !ok {
+			return fmt.Errorf("unknown UI text placeholder {%s}", match[1])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+package visit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/pause"
+	"linkedin-automation/personalize"
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/storage"
+)
+
+// VisitManager opens profiles the way a person browsing them would
+type VisitManager struct {
+	page        *rod.Page
+	logger      *logrus.Logger
+	stealth     StealthManager
+	batchStore  BatchStore
+	rateLimiter RateLimiter
+	capturer    Capturer
+	navigator   Navigator
+}
+
+// StealthManager interface for stealth operations
+type StealthManager interface {
+	RandomDelay() time.Duration
+	HumanLikeScroll(page *rod.Page, scrollAmount int) error
+	AddIdleMovement(page *rod.Page) error
+}
+
+// BatchStore persists per-profile batch progress so interrupted runs can be resumed
+type BatchStore interface {
+	IsBatchItemCompleted(batchID, itemURL string) (bool, error)
+	RecordBatchItem(batchID, action, itemURL, status, errorMessage string) error
+}
+
+// RateLimiter gates actions against configured quotas
+type RateLimiter interface {
+	WaitForPermission(ctx context.Context, action ratelimit.ActionType) error
+}
+
+// Navigator opens profiles through in-page clicks rather than by URL
+type Navigator interface {
+	OpenProfile(page *rod.Page, profileURL string) error
+}
+
+// Capturer saves the page for debugging when an action fails
+type Capturer interface {
+	Failure(page *rod.Page, action string, err error)
+}
+
+// BatchOptions controls how a batch of visits is tracked and paced
+type BatchOptions struct {
+	BatchID  string        // Identifier used to record progress in storage
+	Resume   bool          // Skip profiles already completed under the same batch ID
+	MinDwell time.Duration // Shortest time spent on a profile
+	MaxDwell time.Duration // Longest time spent on a profile
+}
+
+// VisitResult represents the outcome of a profile visit
+type VisitResult struct {
+	Success      bool
+	ProfileURL   string
+	ErrorMessage string
+	Dwell        time.Duration
+	VisitedAt    time.Time
+	Skipped      bool
+	Profile      *personalize.ProfileData // Details read from the page, if found
+}
+
+// BatchResult represents the outcome of a batch of visits
+type BatchResult struct {
+	Results        []*VisitResult
+	StoppedAtLimit bool // The batch ended early because a quota was exhausted
+	StopReason     string
+	StopErr        error // The limit error that ended the batch
+}
+
+// NewVisitManager creates a new visit manager
+func NewVisitManager(page *rod.Page, logger *logrus.Logger, stealth StealthManager) *VisitManager {
+	return &VisitManager{
+		page:    page,
+		logger:  logger,
+		stealth: stealth,
+	}
+}
+
+// SetBatchStore enables per-profile progress tracking for batch operations
+func (v *VisitManager) SetBatchStore(store BatchStore) {
+	v.batchStore = store
+}
+
+// SetRateLimiter enables quota enforcement for batch operations
+func (v *VisitManager) SetRateLimiter(limiter RateLimiter) {
+	v.rateLimiter = limiter
+}
+
+// SetNavigator makes profiles open through navigator instead of by URL
+func (v *VisitManager) SetNavigator(navigator Navigator) {
+	v.navigator = navigator
+}
+
+// SetCapturer saves a screenshot and HTML snapshot of the page whenever a
+// visit fails
+func (v *VisitManager) SetCapturer(capturer Capturer) {
+	v.capturer = capturer
+}
+
+// VisitProfile opens a profile and reads it for a random time between minDwell and maxDwell
+func (v *VisitManager) VisitProfile(ctx context.Context, profileURL string, minDwell, maxDwell time.Duration) (result *VisitResult, err error) {
+	v.logger.WithField("profile_url", profileURL).Info("Visiting profile")
+	defer v.withContext(ctx)()
+	defer func() {
+		if err != nil && v.capturer != nil {
+			v.capturer.Failure(v.page, "visit", err)
+		}
+	}()
+
+	result = &VisitResult{
+		ProfileURL: profileURL,
+		VisitedAt:  time.Now(),
+	}
+
+	if err := v.openProfile(profileURL); err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to navigate to profile: %v", err)
+		return result, err
+	}
+	if err := v.page.WaitLoad(); err != nil {
+		result.ErrorMessage = fmt.Sprintf("Failed to wait for profile load: %v", err)
+		return result, err
+	}
+
+	if data, err := personalize.ScrapeProfile(v.page); err == nil {
+		data.URL = profileURL
+		result.Profile = data
+	} else {
+		v.logger.WithError(err).Debug("Could not read profile details")
+	}
+
+	if err := v.dwell(ctx, randomDuration(minDwell, maxDwell)); err != nil {
+		result.ErrorMessage = err.Error()
+		return result, err
+	}
+
+	result.Dwell = time.Since(result.VisitedAt)
+	result.Success = true
+
+	v.logger.WithFields(logrus.Fields{
+		"profile_url": profileURL,
+		"dwell":       result.Dwell.Round(time.Second),
+	}).Info("Profile visit completed")
+
+	return result, nil
+}
+
+// BatchVisitProfiles visits multiple profiles
+func (v *VisitManager) BatchVisitProfiles(ctx context.Context, profiles []string, opts BatchOptions) (*BatchResult, error) {
+	v.logger.WithFields(logrus.Fields{
+		"count":    len(profiles),
+		"batch_id": opts.BatchID,
+		"resume":   opts.Resume,
+	}).Info("Starting batch profile visits")
+	defer v.withContext(ctx)()
+
+	batch := &BatchResult{}
+	results := make([]*VisitResult, 0, len(profiles))
+
+	for i, profileURL := range profiles {
+		if opts.Resume && v.isBatchItemCompleted(opts.BatchID, profileURL) {
+			v.logger.WithField("profile", profileURL).Info("Skipping profile already visited in this batch")
+			results = append(results, &VisitResult{
+				ProfileURL: profileURL,
+				Skipped:    true,
+			})
+			continue
+		}
+
+		if v.rateLimiter != nil {
+			if err := v.rateLimiter.WaitForPermission(ctx, ratelimit.ActionVisit); err != nil {
+				if !errors.Is(err, ratelimit.ErrLimitReached) {
+					batch.Results = results
+					return batch, err
+				}
+				v.logger.WithError(err).Warn("Stopping batch at rate limit")
+				batch.StoppedAtLimit = true
+				batch.StopReason = err.Error()
+				batch.StopErr = err
+				break
+			}
+		}
+
+		result, err := v.VisitProfile(ctx, profileURL, opts.MinDwell, opts.MaxDwell)
+		if err != nil {
+			v.logger.WithError(err).Error("Failed to visit profile")
+			if ctx.Err() != nil {
+				batch.Results = results
+				return batch, ctx.Err()
+			}
+		}
+
+		results = append(results, result)
+		v.recordBatchItem(opts.BatchID, result)
+
+		if i < len(profiles)-1 {
+			pause.Sleep(ctx, v.stealth.RandomDelay())
+		}
+	}
+
+	successCount := 0
+	skippedCount := 0
+	for _, result := range results {
+		if result.Skipped {
+			skippedCount++
+		} else if result.Success {
+			successCount++
+		}
+	}
+
+	v.logger.WithFields(logrus.Fields{
+		"total":            len(profiles),
+		"success":          successCount,
+		"skipped":          skippedCount,
+		"stopped_at_limit": batch.StoppedAtLimit,
+	}).Info("Batch profile visits completed")
+
+	batch.Results = results
+	return batch, nil
+}
+
+// withContext binds the page to ctx until the returned function is called,
+// so navigation, element lookups and pauses stop once ctx is cancelled
+func (v *VisitManager) withContext(ctx context.Context) func() {
+	page := v.page
+	v.page = page.Context(ctx)
+	return func() { v.page = page }
+}
+
+// dwell reads down the page in uneven steps, sometimes scrolling back up,
+// until the dwell time has passed
+func (v *VisitManager) dwell(ctx context.Context, duration time.Duration) error {
+	deadline := time.Now().Add(duration)
+
+	for time.Now().Before(deadline) {
+		amount := 200 + rand.Intn(400)
+		if rand.Float64() < 0.15 {
+			amount = -amount / 2
+		}
+		if err := v.stealth.HumanLikeScroll(v.page, amount); err != nil {
+			v.logger.WithError(err).Debug("Failed to scroll profile")
+		}
+
+		if err := v.stealth.AddIdleMovement(v.page); err != nil {
+			v.logger.WithError(err).Debug("Failed to add idle movement")
+		}
+
+		pause := v.stealth.RandomDelay()
+		if remaining := time.Until(deadline); pause > remaining {
+			pause = remaining
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pause):
+		}
+	}
+
+	return nil
+}
+
+// openProfile opens profileURL through the navigator, if one is set
+func (v *VisitManager) openProfile(profileURL string) error {
+	if v.navigator == nil {
+		return v.page.Navigate(profileURL)
+	}
+	return v.navigator.OpenProfile(v.page, profileURL)
+}
+
+func (v *VisitManager) isBatchItemCompleted(batchID, profileURL string) bool {
+	if v.batchStore == nil || batchID == "" {
+		return false
+	}
+
+	completed, err := v.batchStore.IsBatchItemCompleted(batchID, profileURL)
+	if err != nil {
+		v.logger.WithError(err).Warn("Failed to check batch progress")
+		return false
+	}
+	return completed
+}
+
+func (v *VisitManager) recordBatchItem(batchID string, result *VisitResult) {
+	if v.batchStore == nil || batchID == "" {
+		return
+	}
+
+	status := storage.BatchItemFailed
+	if result.Success {
+		status = storage.BatchItemSuccess
+	}
+
+	if err := v.batchStore.RecordBatchItem(batchID, "visit", result.ProfileURL, status, result.ErrorMessage); err != nil {
+		v.logger.WithError(err).Warn("Failed to record batch progress")
+	}
+}
+
+func randomDuration(min, max time.Duration) time.Duration {
+	if max <= min {
+		return min
+	}
+	return min + time.Duration(rand.Int63n(int64(max-min)))
+}
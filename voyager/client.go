@@ -0,0 +1,162 @@
+// Package voyager calls LinkedIn's internal Voyager REST API with the cookies
+// of a logged-in session. It is much faster than rendering pages but is
+// undocumented, so callers should keep browser scraping as a fallback.
+package voyager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/errs"
+)
+
+const defaultBaseURL = "https://www.linkedin.com/voyager/api"
+
+var (
+	// ErrUnauthorized means the session cookies were rejected and need refreshing
+	ErrUnauthorized = fmt.Errorf("%w: voyager session is not authorized", errs.ErrSessionExpired)
+	// ErrThrottled means LinkedIn asked the client to slow down
+	ErrThrottled = fmt.Errorf("%w: voyager request was throttled", errs.ErrRateLimited)
+)
+
+// Credentials are the session cookies the API authenticates with
+type Credentials struct {
+	LiAt       string // li_at cookie
+	JSessionID string // JSESSIONID cookie, which doubles as the CSRF token
+}
+
+// Valid reports whether both cookies are present
+func (c Credentials) Valid() bool {
+	return c.LiAt != "" && c.JSessionID != ""
+}
+
+// Client is a Voyager API client
+type Client struct {
+	httpClient    *http.Client
+	baseURL       string
+	credentials   Credentials
+	userAgent     string
+	searchQueryID string
+	logger        *logrus.Logger
+}
+
+// NewClient creates a Voyager client for a logged-in session
+func NewClient(credentials Credentials, userAgent string, logger *logrus.Logger) *Client {
+	return &Client{
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		baseURL:     defaultBaseURL,
+		credentials: credentials,
+		userAgent:   userAgent,
+		logger:      logger,
+	}
+}
+
+// get requests path (relative to the API base, with its query string already
+// encoded) and decodes the JSON response into out
+func (c *Client) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	csrf := strings.Trim(c.credentials.JSessionID, `"`)
+	req.Header.Set("Cookie", fmt.Sprintf(`li_at=%s; JSESSIONID="%s"`, c.credentials.LiAt, csrf))
+	req.Header.Set("csrf-token", csrf)
+	req.Header.Set("Accept", "application/vnd.linkedin.normalized+json+2.1")
+	req.Header.Set("x-restli-protocol-version", "2.0.0")
+	req.Header.Set("x-li-lang", "en_US")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"path":   strings.SplitN(path, "?", 2)[0],
+		"status": resp.StatusCode,
+	}).Debug("Voyager request")
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == 999:
+		return ErrThrottled
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// normalizedResponse is the envelope of normalized+json responses: the
+// requested data refers by URN to entities listed in included
+type normalizedResponse struct {
+	Data     json.RawMessage   `json:"data"`
+	Included []json.RawMessage `json:"included"`
+}
+
+// entity is the subset of fields read from included entities
+type entity struct {
+	Type                 string     `json:"$type"`
+	EntityURN            string     `json:"entityUrn"`
+	Title                *textValue `json:"title"`
+	PrimarySubtitle      *textValue `json:"primarySubtitle"`
+	SecondarySubtitle    *textValue `json:"secondarySubtitle"`
+	NavigationURL        string     `json:"navigationUrl"`
+	FirstName            string     `json:"firstName"`
+	LastName             string     `json:"lastName"`
+	Headline             string     `json:"headline"`
+	PublicIdentifier     string     `json:"publicIdentifier"`
+	Name                 string     `json:"name"`
+	DefaultLocalizedName string     `json:"defaultLocalizedName"`
+	IndustryName         string     `json:"industryName"`
+	TrackingInfo         struct {
+		MemberDistance string `json:"memberDistance"`
+	} `json:"entityCustomTrackingInfo"`
+	Insights []struct {
+		SimpleInsight struct {
+			Title *textValue `json:"title"`
+		} `json:"simpleInsight"`
+	} `json:"insightsResolutionResults"`
+}
+
+type textValue struct {
+	Text string `json:"text"`
+}
+
+func (t *textValue) String() string {
+	if t == nil {
+		return ""
+	}
+	return strings.TrimSpace(t.Text)
+}
+
+func decodeEntities(raw []json.RawMessage) []*entity {
+	entities := make([]*entity, 0, len(raw))
+	for _, item := range raw {
+		var e entity
+		if err := json.Unmarshal(item, &e); err == nil {
+			entities = append(entities, &e)
+		}
+	}
+	return entities
+}
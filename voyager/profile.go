@@ -0,0 +1,69 @@
+package voyager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"linkedin-automation/profileurl"
+)
+
+const profileDecoration = "com.linkedin.voyager.dash.deco.identity.profile.WebTopCardCore-16"
+
+// Profile is the top card of a member profile
+type Profile struct {
+	FirstName  string
+	LastName   string
+	Headline   string
+	Company    string
+	Location   string
+	Industry   string
+	ProfileURL string
+}
+
+// Name returns the member's full name
+func (p *Profile) Name() string {
+	return strings.TrimSpace(p.FirstName + " " + p.LastName)
+}
+
+// GetProfile fetches the top card of the profile at profileURL
+func (c *Client) GetProfile(ctx context.Context, profileURL string) (*Profile, error) {
+	slug := profileurl.Slug(profileURL)
+	if slug == "" {
+		return nil, fmt.Errorf("not a profile URL: %s", profileURL)
+	}
+
+	path := "/identity/dash/profiles?q=memberIdentity&memberIdentity=" + restliEscape(slug) +
+		"&decorationId=" + profileDecoration
+
+	var resp normalizedResponse
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, fmt.Errorf("profile fetch failed: %w", err)
+	}
+
+	profile := &Profile{ProfileURL: profileurl.Canonicalize(profileURL)}
+	found := false
+	for _, e := range decodeEntities(resp.Included) {
+		switch {
+		case strings.HasSuffix(e.Type, "identity.profile.Profile") && strings.EqualFold(e.PublicIdentifier, slug):
+			profile.FirstName = e.FirstName
+			profile.LastName = e.LastName
+			profile.Headline = e.Headline
+			if profile.Industry == "" {
+				profile.Industry = e.IndustryName
+			}
+			found = true
+		case strings.HasSuffix(e.Type, "organization.Company") && profile.Company == "":
+			profile.Company = e.Name
+		case strings.HasSuffix(e.Type, "common.Geo") && profile.Location == "":
+			profile.Location = e.DefaultLocalizedName
+		case strings.HasSuffix(e.Type, "common.Industry") && e.Name != "":
+			profile.Industry = e.Name
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("profile %s not found in response", slug)
+	}
+	return profile, nil
+}
@@ -0,0 +1,123 @@
+package voyager
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"linkedin-automation/profileurl"
+)
+
+// DefaultSearchQueryID identifies the GraphQL query behind people search. LinkedIn
+// rotates it occasionally; it can be overridden with SetSearchQueryID.
+const DefaultSearchQueryID = "voyagerSearchDashClusters.b0928897b71bd00a5a7291755dcd64f0"
+
+// SearchPageSize is the number of results returned per search request
+const SearchPageSize = 10
+
+// SearchParams describes a people search
+type SearchParams struct {
+	Keywords string
+	Filters  map[string][]string // Facet name to values, e.g. currentCompany: [1035]
+}
+
+// Person is a people search result
+type Person struct {
+	Name       string
+	Headline   string
+	Location   string
+	ProfileURL string
+	Degree     int      // 1, 2 or 3 for a 1st, 2nd or 3rd-degree connection; 0 when not in the network
+	Insights   []string // Notes under the result, such as its mutual connections
+}
+
+// SetSearchQueryID overrides the GraphQL query ID used for people search
+func (c *Client) SetSearchQueryID(queryID string) {
+	c.searchQueryID = queryID
+}
+
+// SearchPeople returns one page of people search results starting at offset start
+func (c *Client) SearchPeople(ctx context.Context, params SearchParams, start int) ([]*Person, error) {
+	var resp normalizedResponse
+	if err := c.get(ctx, c.searchPath(params, start), &resp); err != nil {
+		return nil, fmt.Errorf("people search failed: %w", err)
+	}
+
+	people := make([]*Person, 0, SearchPageSize)
+	seen := make(map[string]bool)
+	for _, e := range decodeEntities(resp.Included) {
+		if !strings.HasSuffix(e.Type, "EntityResultViewModel") || profileurl.Slug(e.NavigationURL) == "" {
+			continue
+		}
+
+		profileURL := profileurl.Canonicalize(e.NavigationURL)
+		if seen[profileURL] {
+			continue
+		}
+		seen[profileURL] = true
+
+		person := &Person{
+			Name:       e.Title.String(),
+			Headline:   e.PrimarySubtitle.String(),
+			Location:   e.SecondarySubtitle.String(),
+			ProfileURL: profileURL,
+			Degree:     memberDistance(e.TrackingInfo.MemberDistance),
+		}
+		for _, insight := range e.Insights {
+			if text := insight.SimpleInsight.Title.String(); text != "" {
+				person.Insights = append(person.Insights, text)
+			}
+		}
+		people = append(people, person)
+	}
+
+	return people, nil
+}
+
+// memberDistance converts a distance such as DISTANCE_2 to the connection degree
+func memberDistance(distance string) int {
+	switch distance {
+	case "DISTANCE_1":
+		return 1
+	case "DISTANCE_2":
+		return 2
+	case "DISTANCE_3":
+		return 3
+	}
+	return 0
+}
+
+// searchPath encodes a search in the Rest.li syntax the GraphQL endpoint expects
+func (c *Client) searchPath(params SearchParams, start int) string {
+	filters := []string{"(key:resultType,value:List(PEOPLE))"}
+
+	names := make([]string, 0, len(params.Filters))
+	for name := range params.Filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		values := make([]string, 0, len(params.Filters[name]))
+		for _, value := range params.Filters[name] {
+			values = append(values, restliEscape(value))
+		}
+		filters = append(filters, fmt.Sprintf("(key:%s,value:List(%s))", name, strings.Join(values, ",")))
+	}
+
+	queryID := c.searchQueryID
+	if queryID == "" {
+		queryID = DefaultSearchQueryID
+	}
+
+	variables := fmt.Sprintf("(start:%d,origin:FACETED_SEARCH,query:(keywords:%s,flagshipSearchIntent:SEARCH_SRP,queryParameters:List(%s),includeFiltersInResponse:false))",
+		start, restliEscape(params.Keywords), strings.Join(filters, ","))
+
+	return "/graphql?variables=" + variables + "&queryId=" + queryID
+}
+
+// restliEscape percent-encodes a value so Rest.li delimiters in it are taken literally
+func restliEscape(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
@@ -0,0 +1,89 @@
+package voyager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// companyURNPattern extracts the numeric ID from the company URNs typeahead hits refer to
+var companyURNPattern = regexp.MustCompile(`^urn:li:(?:fs_miniCompany|fsd_company|company|organization):(\d+)$`)
+
+// Company is a company suggested by the typeahead
+type Company struct {
+	ID       string // Numeric company ID, as used by the currentCompany and pastCompany facets
+	Name     string
+	Subtitle string // e.g. "Company • Software Development"
+}
+
+// typeaheadHit is one element of a typeahead response
+type typeaheadHit struct {
+	Text      *textValue `json:"text"`
+	Subtext   *textValue `json:"subtext"`
+	TargetURN string     `json:"targetUrn"`
+	ObjectURN string     `json:"objectUrn"`
+}
+
+// TypeaheadCompanies returns the companies LinkedIn suggests for keywords, best match first
+func (c *Client) TypeaheadCompanies(ctx context.Context, keywords string) ([]*Company, error) {
+	path := "/typeahead/hitsV2?keywords=" + url.QueryEscape(keywords) + "&origin=OTHER&q=type&type=COMPANY"
+
+	var resp normalizedResponse
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, fmt.Errorf("company typeahead failed: %w", err)
+	}
+
+	var data struct {
+		Elements []typeaheadHit `json:"elements"`
+	}
+	if len(resp.Data) > 0 {
+		if err := json.Unmarshal(resp.Data, &data); err != nil {
+			return nil, fmt.Errorf("failed to decode typeahead hits: %w", err)
+		}
+	}
+
+	// Names missing from a hit are filled in from the companies it refers to
+	names := make(map[string]string)
+	for _, e := range decodeEntities(resp.Included) {
+		if id := companyID(e.EntityURN); id != "" && e.Name != "" {
+			names[id] = e.Name
+		}
+	}
+
+	companies := make([]*Company, 0, len(data.Elements))
+	seen := make(map[string]bool)
+	for _, hit := range data.Elements {
+		id := ""
+		for _, urn := range []string{hit.TargetURN, hit.ObjectURN} {
+			if id = companyID(urn); id != "" {
+				break
+			}
+		}
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		name := hit.Text.String()
+		if name == "" {
+			name = names[id]
+		}
+		companies = append(companies, &Company{
+			ID:       id,
+			Name:     name,
+			Subtitle: hit.Subtext.String(),
+		})
+	}
+
+	return companies, nil
+}
+
+func companyID(urn string) string {
+	if match := companyURNPattern.FindStringSubmatch(strings.TrimSpace(urn)); match != nil {
+		return match[1]
+	}
+	return ""
+}
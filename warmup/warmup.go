@@ -0,0 +1,206 @@
+// Package warmup ramps up the limits of a new or long idle account. A
+// sudden burst of invitations from an account that sent none before is what
+// LinkedIn flags first, so during the warm-up the daily limits start low and
+// grow week by week until the configured limits apply.
+package warmup
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/ratelimit"
+	"linkedin-automation/storage"
+)
+
+// Week is how long each step of the ramp lasts
+const Week = 7 * 24 * time.Hour
+
+// refreshInterval is how often a running session looks whether the warm-up
+// moved on to the next week
+const refreshInterval = time.Hour
+
+// Config controls the ramp
+type Config struct {
+	Weeks            int           // Length of the ramp
+	StartConnections int           // Daily connection requests in the first week
+	StartMessages    int           // Daily messages in the first week
+	RestartAfter     time.Duration // Starts the warm-up over after this long without actions; 0 never does
+}
+
+// Store keeps how far each account has warmed up
+type Store interface {
+	GetWarmup(account string) (*storage.Warmup, error)
+	SaveWarmup(warmup *storage.Warmup) error
+}
+
+// Quotas is the limiter whose quotas the warm-up lowers
+type Quotas interface {
+	ratelimit.Limiter
+	SetConfig(config ratelimit.Config)
+}
+
+// Advance returns warmup moved on to the week now falls in. A nil warmup, or
+// one of an account idle for config.RestartAfter, gives a new one starting now.
+func Advance(warmup *storage.Warmup, account string, config Config, now time.Time) *storage.Warmup {
+	if warmup == nil || (config.RestartAfter > 0 && now.Sub(warmup.LastActiveAt) >= config.RestartAfter) {
+		return &storage.Warmup{Account: account, StartedAt: now, Week: 1, LastActiveAt: now}
+	}
+
+	advanced := *warmup
+	if advanced.CompletedAt != nil {
+		return &advanced
+	}
+	advanced.Week = int(now.Sub(advanced.StartedAt)/Week) + 1
+	if advanced.Week > config.Weeks {
+		advanced.Week = config.Weeks
+		advanced.CompletedAt = &now
+	}
+	return &advanced
+}
+
+// Limits returns quotas as they apply during warmup. Connection requests and
+// messages start from the configured starting values, likes, comments and
+// endorsements from the same share of their limit as connection requests,
+// and all of them grow evenly each week. Hourly limits are held to the daily
+// ones, and limits of 0, which mean no limit, are left alone.
+func Limits(quotas ratelimit.Config, warmup *storage.Warmup, config Config) ratelimit.Config {
+	if warmup == nil || warmup.CompletedAt != nil || config.Weeks <= 0 {
+		return quotas
+	}
+
+	share := 1.0
+	if quotas.DailyConnects > 0 && config.StartConnections < quotas.DailyConnects {
+		share = float64(config.StartConnections) / float64(quotas.DailyConnects)
+	}
+	scaled := func(limit int) int {
+		return max(1, int(float64(limit)*share))
+	}
+	ramp := func(start, limit int) int {
+		if limit <= 0 || start >= limit {
+			return limit
+		}
+		return start + (limit-start)*(warmup.Week-1)/config.Weeks
+	}
+
+	quotas.DailyConnects = ramp(config.StartConnections, quotas.DailyConnects)
+	quotas.DailyMessages = ramp(config.StartMessages, quotas.DailyMessages)
+	quotas.DailyLikes = ramp(scaled(quotas.DailyLikes), quotas.DailyLikes)
+	quotas.DailyComments = ramp(scaled(quotas.DailyComments), quotas.DailyComments)
+	quotas.DailyEndorsements = ramp(scaled(quotas.DailyEndorsements), quotas.DailyEndorsements)
+	if quotas.DailyConnects > 0 && quotas.HourlyConnects > quotas.DailyConnects {
+		quotas.HourlyConnects = quotas.DailyConnects
+	}
+	if quotas.DailyMessages > 0 && quotas.HourlyMessages > quotas.DailyMessages {
+		quotas.HourlyMessages = quotas.DailyMessages
+	}
+	return quotas
+}
+
+// Limiter lowers the quotas of the limiter it wraps while the account warms
+// up. The warm-up starts with the account's first session, moves on as the
+// weeks pass, also during a long running queue, and is kept in the store
+// together with when the account last acted.
+type Limiter struct {
+	limiter Quotas
+	store   Store
+	account string
+	config  Config
+	logger  *logrus.Logger
+	mu      sync.Mutex
+	quotas  ratelimit.Config // Applying once the warm-up completes
+	warmup  *storage.Warmup
+	checked time.Time
+}
+
+// NewLimiter warms up account, lowering limiter's quotas from the given ones
+func NewLimiter(limiter Quotas, quotas ratelimit.Config, store Store, account string, config Config, logger *logrus.Logger) *Limiter {
+	l := &Limiter{
+		limiter: limiter,
+		store:   store,
+		account: account,
+		config:  config,
+		logger:  logger,
+		quotas:  quotas,
+	}
+	l.refresh(time.Now())
+	return l
+}
+
+// Unwrap returns the limiter whose quotas are lowered
+func (l *Limiter) Unwrap() ratelimit.Limiter {
+	return l.limiter
+}
+
+// AdjustQuotas takes quotas as the ones to ramp up to and returns them as
+// they apply in the current week
+func (l *Limiter) AdjustQuotas(quotas ratelimit.Config) ratelimit.Config {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.quotas = quotas
+	return Limits(quotas, l.warmup, l.config)
+}
+
+// WaitForPermission moves the warm-up on if a week passed, then defers to the
+// wrapped limiter
+func (l *Limiter) WaitForPermission(ctx context.Context, action ratelimit.ActionType) error {
+	l.mu.Lock()
+	if now := time.Now(); now.Sub(l.checked) >= refreshInterval {
+		l.refresh(now)
+	}
+	l.mu.Unlock()
+	return l.limiter.WaitForPermission(ctx, action)
+}
+
+// refresh advances the stored warm-up, records the account as active and
+// applies the week's quotas. When the stored warm-up cannot be loaded, the
+// limits stay as they were, or those of the first week.
+func (l *Limiter) refresh(now time.Time) {
+	l.checked = now
+
+	stored, err := l.store.GetWarmup(l.account)
+	if err != nil {
+		l.logger.WithError(err).Warn("Failed to load warm-up, keeping the lower limits")
+		if l.warmup == nil {
+			l.warmup = Advance(nil, l.account, l.config, now)
+			l.limiter.SetConfig(Limits(l.quotas, l.warmup, l.config))
+		}
+		return
+	}
+	warmup := Advance(stored, l.account, l.config, now)
+	l.log(stored, warmup)
+
+	warmup.LastActiveAt = now
+	if err := l.store.SaveWarmup(warmup); err != nil {
+		l.logger.WithError(err).Warn("Failed to save warm-up")
+	}
+
+	if l.warmup == nil || l.warmup.Week != warmup.Week || (l.warmup.CompletedAt == nil) != (warmup.CompletedAt == nil) {
+		l.limiter.SetConfig(Limits(l.quotas, warmup, l.config))
+	}
+	l.warmup = warmup
+}
+
+// log reports the warm-up starting, moving on and completing
+func (l *Limiter) log(stored, warmup *storage.Warmup) {
+	limits := Limits(l.quotas, warmup, l.config)
+	entry := l.logger.WithFields(logrus.Fields{
+		"week":              warmup.Week,
+		"weeks":             l.config.Weeks,
+		"daily_connections": limits.DailyConnects,
+		"daily_messages":    limits.DailyMessages,
+	})
+
+	switch {
+	case stored == nil:
+		entry.Info("Starting account warm-up with low limits")
+	case !warmup.StartedAt.Equal(stored.StartedAt):
+		entry.WithField("idle_since", stored.LastActiveAt).Warn("Account was idle, starting the warm-up over")
+	case warmup.CompletedAt != nil && stored.CompletedAt == nil:
+		l.logger.Info("Account warm-up complete, the configured limits apply")
+	case warmup.CompletedAt == nil && (l.warmup == nil || warmup.Week != stored.Week):
+		entry.Info("Account warming up")
+	}
+}
@@ -0,0 +1,232 @@
+"use strict";
+
+// Refresh interval of the dashboard in milliseconds
+const REFRESH = 10000;
+
+// The API token is asked for once and kept in the browser
+let token = localStorage.getItem("token") || "";
+
+async function api(method, path) {
+  const response = await fetch(path, {
+    method: method,
+    headers: token ? { Authorization: "Bearer " + token } : {},
+  });
+  if (response.status === 401) {
+    token = prompt("API token (web.token in the daemon's config):") || "";
+    localStorage.setItem("token", token);
+    throw new Error("Not authorized; reload after entering the token");
+  }
+  const body = await response.json();
+  if (!response.ok) {
+    throw new Error(body.error || response.statusText);
+  }
+  return body;
+}
+
+function el(tag, text, className) {
+  const node = document.createElement(tag);
+  if (text !== undefined) node.textContent = text;
+  if (className) node.className = className;
+  return node;
+}
+
+function row(cells) {
+  const tr = el("tr");
+  for (const cell of cells) {
+    const td = el("td");
+    if (cell instanceof Node) td.appendChild(cell);
+    else td.textContent = cell === undefined || cell === null ? "" : cell;
+    tr.appendChild(td);
+  }
+  return tr;
+}
+
+function fill(id, rows, columns) {
+  const body = document.getElementById(id);
+  body.replaceChildren(...rows);
+  if (rows.length === 0) {
+    const td = el("td", "None", "empty");
+    td.colSpan = columns;
+    body.appendChild(el("tr")).appendChild(td);
+  }
+}
+
+function figures(id, items) {
+  const node = document.getElementById(id);
+  node.replaceChildren(...items.map(([label, value]) => {
+    const figure = el("div", undefined, "figure");
+    figure.appendChild(el("strong", String(value || 0)));
+    figure.appendChild(el("span", label));
+    return figure;
+  }));
+}
+
+function when(value) {
+  return value ? new Date(value).toLocaleString() : "";
+}
+
+function button(label, action) {
+  const node = el("button", label);
+  node.addEventListener("click", async () => {
+    node.disabled = true;
+    try {
+      await action();
+      await refresh();
+    } catch (err) {
+      showError(err);
+    } finally {
+      node.disabled = false;
+    }
+  });
+  return node;
+}
+
+function showError(err) {
+  const node = document.getElementById("error");
+  node.textContent = err ? err.message : "";
+  node.hidden = !err;
+}
+
+// drawChart draws requests sent, accepted and messages sent per day as bars
+function drawChart(daily) {
+  const canvas = document.getElementById("chart");
+  const ctx = canvas.getContext("2d");
+  const series = [
+    ["connections_sent", "#0a66c2"],
+    ["connections_accepted", "#057642"],
+    ["messages_sent", "#e7a33e"],
+  ];
+  const pad = 24;
+  const width = canvas.width - pad * 2;
+  const height = canvas.height - pad * 2;
+  ctx.clearRect(0, 0, canvas.width, canvas.height);
+
+  let max = 1;
+  for (const day of daily) {
+    for (const [key] of series) max = Math.max(max, day[key]);
+  }
+
+  ctx.fillStyle = "#666";
+  ctx.font = "11px sans-serif";
+  ctx.fillText(String(max), 0, pad);
+  ctx.fillText("0", 0, pad + height);
+  ctx.strokeStyle = "#e0e0e0";
+  ctx.beginPath();
+  ctx.moveTo(pad, pad + height);
+  ctx.lineTo(pad + width, pad + height);
+  ctx.stroke();
+
+  if (daily.length === 0) {
+    ctx.fillText("No activity yet", pad + width / 2 - 40, pad + height / 2);
+    return;
+  }
+
+  const slot = width / daily.length;
+  const bar = Math.max(1, (slot - 4) / series.length);
+  daily.forEach((day, i) => {
+    series.forEach(([key, color], j) => {
+      const h = (day[key] / max) * height;
+      ctx.fillStyle = color;
+      ctx.fillRect(pad + i * slot + 2 + j * bar, pad + height - h, bar, h);
+    });
+  });
+  ctx.fillStyle = "#666";
+  ctx.fillText(daily[0].date, pad, canvas.height - 4);
+  const last = daily[daily.length - 1].date;
+  ctx.fillText(last, pad + width - ctx.measureText(last).width, canvas.height - 4);
+}
+
+async function refreshStats() {
+  const stats = await api("GET", "/api/stats");
+  figures("today", Object.entries(stats.today));
+  document.getElementById("days").textContent = stats.days;
+  figures("totals", [
+    ["requests sent", stats.totals.connections_sent],
+    ["accepted", stats.totals.connections_accepted],
+    ["profiles messaged", stats.totals.profiles_messaged],
+    ["replied", stats.totals.profiles_replied],
+  ]);
+  figures("queue", ["pending", "running", "done", "failed", "cancelled"].map(s => [s, stats.queue[s]]));
+  drawChart(stats.daily || []);
+}
+
+async function refreshBatches() {
+  const batches = await api("GET", "/api/batches");
+  fill("batches", batches.map(b => row([
+    b.batch_id, b.action, b.processed + "/" + b.total, b.succeeded, b.failed, b.skipped,
+    b.wait_reason && new Date(b.waiting_until) > new Date()
+      ? "waiting: " + b.wait_reason + " until " + new Date(b.waiting_until).toLocaleTimeString()
+      : b.current,
+  ])), 7);
+}
+
+async function refreshCampaigns() {
+  const campaigns = await api("GET", "/api/campaigns");
+  fill("campaigns", campaigns.map(c => {
+    const name = encodeURIComponent(c.name);
+    const action = c.paused
+      ? button("Resume", () => api("POST", "/api/campaigns/" + name + "/resume"))
+      : button("Pause", () => api("POST", "/api/campaigns/" + name + "/pause"));
+    return row([
+      c.name, c.sent, c.accepted, c.acceptance_rate.toFixed(1) + "%", c.queued,
+      el("span", c.paused ? "paused" : "active", c.paused ? "paused" : ""), action,
+    ]);
+  }), 7);
+
+  const select = document.getElementById("campaign-filter");
+  const selected = select.value;
+  select.replaceChildren(el("option", "All"));
+  select.firstChild.value = "";
+  for (const c of campaigns) {
+    const option = el("option", c.name);
+    option.value = c.name;
+    select.appendChild(option);
+  }
+  select.value = selected;
+}
+
+async function refreshFailed() {
+  const tasks = await api("GET", "/api/tasks?status=failed");
+  fill("failed", tasks.map(t => row([
+    t.id, t.kind, t.attempts, when(t.updated_at), t.last_error,
+    button("Retry", () => api("POST", "/api/tasks/" + t.id + "/retry")),
+  ])), 6);
+}
+
+async function refreshProspects() {
+  const campaign = document.getElementById("campaign-filter").value;
+  const prospects = await api("GET", "/api/prospects?campaign=" + encodeURIComponent(campaign));
+  fill("prospects", prospects.map(p => {
+    const link = el("a", p.profile_url);
+    link.href = p.profile_url;
+    link.target = "_blank";
+    link.rel = "noopener";
+    return row([link, p.campaign, p.dry_run ? p.status + " (dry run)" : p.status, when(p.sent_at), when(p.accepted_at)]);
+  }), 5);
+}
+
+async function refresh() {
+  try {
+    await Promise.all([refreshStats(), refreshBatches(), refreshCampaigns(), refreshFailed()]);
+    await refreshProspects();
+    showError(null);
+    document.getElementById("updated").textContent = "Updated " + new Date().toLocaleTimeString();
+  } catch (err) {
+    showError(err);
+  }
+}
+
+document.getElementById("retry-failed").addEventListener("click", async () => {
+  try {
+    const result = await api("POST", "/api/tasks/retry-failed");
+    showError(null);
+    alert(result.retried + " task(s) queued again");
+    await refresh();
+  } catch (err) {
+    showError(err);
+  }
+});
+document.getElementById("campaign-filter").addEventListener("change", () => refreshProspects().catch(showError));
+
+refresh();
+setInterval(refresh, REFRESH);
@@ -0,0 +1,420 @@
+// Package web serves the dashboard of the queue daemon: a REST API over the
+// database and an embedded single-page UI that uses it.
+package web
+
+import (
+	"context"
+	"crypto/subtle"
+	"embed"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"linkedin-automation/progress"
+	"linkedin-automation/queue"
+	"linkedin-automation/storage"
+)
+
+//go:embed static
+var static embed.FS
+
+// Defaults of the list endpoints
+const (
+	statsDays        = 30
+	defaultProspects = 100
+	maxProspects     = 1000
+)
+
+// Store is the part of the database the API reads and changes
+type Store interface {
+	GetDailyStats(date time.Time) (map[string]int, error)
+	GetOutreachTotals(since time.Time) (*storage.OutreachTotals, error)
+	GetDailyActivity(since time.Time) ([]*storage.DailyActivity, error)
+	CountTasksByStatus() (map[string]int, error)
+	GetCampaignStats() ([]*storage.CampaignStats, error)
+	PauseCampaign(name string) (bool, error)
+	ResumeCampaign(name string) (bool, error)
+	ListConnectionRequests(campaign string, limit int) ([]*storage.ConnectionRequest, error)
+	ListTasks(status string) ([]*storage.QueueTask, error)
+	RetryTask(id int) (bool, error)
+}
+
+// Server serves the API under /api/ and the UI everywhere else
+type Server struct {
+	store       Store
+	progressDir string
+	token       string
+	logger      *logrus.Logger
+	mux         *http.ServeMux
+}
+
+// NewServer creates a server. With a token, API requests must send it as a
+// bearer token.
+func NewServer(store Store, progressDir, token string, logger *logrus.Logger) *Server {
+	s := &Server{
+		store:       store,
+		progressDir: progressDir,
+		token:       token,
+		logger:      logger,
+		mux:         http.NewServeMux(),
+	}
+
+	assets, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err) // The embedded directory is part of the binary
+	}
+	s.mux.Handle("/", http.FileServer(http.FS(assets)))
+	s.mux.HandleFunc("/api/stats", s.handleStats)
+	s.mux.HandleFunc("/api/campaigns", s.handleCampaigns)
+	s.mux.HandleFunc("/api/campaigns/", s.handleCampaignAction)
+	s.mux.HandleFunc("/api/prospects", s.handleProspects)
+	s.mux.HandleFunc("/api/batches", s.handleBatches)
+	s.mux.HandleFunc("/api/tasks", s.handleTasks)
+	s.mux.HandleFunc("/api/tasks/", s.handleTaskAction)
+
+	return s
+}
+
+// Serve answers requests on listener until ctx is cancelled
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	server := &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !hostAllowed(r) {
+		writeError(w, http.StatusForbidden, "host not allowed")
+		return
+	}
+	if crossSite(r) {
+		writeError(w, http.StatusForbidden, "cross-site request refused")
+		return
+	}
+	if strings.HasPrefix(r.URL.Path, "/api/") && !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "missing or wrong token")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// hostAllowed refuses requests that reached a loopback listener under a name
+// other than a loopback one, as a DNS rebinding page's requests do
+func hostAllowed(r *http.Request) bool {
+	local, ok := r.Context().Value(http.LocalAddrContextKey).(*net.TCPAddr)
+	if !ok || !local.IP.IsLoopback() {
+		return true
+	}
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return isLoopbackHost(strings.Trim(host, "[]"))
+}
+
+// crossSite reports whether a request other than a GET comes from another
+// site, such as a form on a page open in the operator's browser
+func crossSite(r *http.Request) bool {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return false
+	}
+	if site := r.Header.Get("Sec-Fetch-Site"); site != "" {
+		return site != "same-origin" && site != "none"
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		// Not sent by a browser, e.g. curl
+		return false
+	}
+	u, err := url.Parse(origin)
+	return err != nil || u.Host != r.Host
+}
+
+// isLoopbackHost reports whether host names this machine only
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// authorized checks the bearer token of an API request
+func (s *Server) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	now := time.Now()
+	since := now.AddDate(0, 0, -statsDays)
+	today, err := s.store.GetDailyStats(now)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	totals, err := s.store.GetOutreachTotals(since)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	daily, err := s.store.GetDailyActivity(since)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	tasks, err := s.store.CountTasksByStatus()
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"today":  today,
+		"totals": totals,
+		"days":   statsDays,
+		"daily":  daily,
+		"queue":  tasks,
+	})
+}
+
+// campaign is a campaign with the number of its tasks still queued
+type campaign struct {
+	*storage.CampaignStats
+	AcceptanceRate float64 `json:"acceptance_rate"`
+	Queued         int     `json:"queued"`
+	Paused         bool    `json:"paused"`
+}
+
+func (s *Server) handleCampaigns(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	stats, err := s.store.GetCampaignStats()
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	pending, err := s.store.ListTasks(storage.TaskPending)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+
+	campaigns := make([]*campaign, 0, len(stats))
+	byName := make(map[string]*campaign, len(stats))
+	for _, stat := range stats {
+		c := &campaign{CampaignStats: stat, AcceptanceRate: stat.AcceptanceRate(), Paused: stat.PausedAt != nil}
+		campaigns = append(campaigns, c)
+		byName[stat.Name] = c
+	}
+	for _, task := range pending {
+		var payload queue.ConnectPayload
+		if task.Kind != queue.KindConnect || queue.Decode(task, &payload) != nil || payload.Campaign == "" {
+			continue
+		}
+		c, ok := byName[payload.Campaign]
+		if !ok {
+			c = &campaign{CampaignStats: &storage.CampaignStats{Name: payload.Campaign}}
+			campaigns = append(campaigns, c)
+			byName[payload.Campaign] = c
+		}
+		c.Queued++
+	}
+
+	writeJSON(w, http.StatusOK, campaigns)
+}
+
+// handleCampaignAction serves POST /api/campaigns/{name}/pause and /resume
+func (s *Server) handleCampaignAction(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	rest := strings.TrimPrefix(r.URL.EscapedPath(), "/api/campaigns/")
+	i := strings.LastIndex(rest, "/")
+	if i <= 0 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	name, err := url.PathUnescape(rest[:i])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid campaign name")
+		return
+	}
+
+	var changed bool
+	switch rest[i+1:] {
+	case "pause":
+		changed, err = s.store.PauseCampaign(name)
+	case "resume":
+		changed, err = s.store.ResumeCampaign(name)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"campaign": name, "changed": changed})
+}
+
+func (s *Server) handleProspects(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	limit := defaultProspects
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxProspects {
+			writeError(w, http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxProspects))
+			return
+		}
+		limit = n
+	}
+
+	requests, err := s.store.ListConnectionRequests(r.URL.Query().Get("campaign"), limit)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	if requests == nil {
+		requests = []*storage.ConnectionRequest{}
+	}
+	writeJSON(w, http.StatusOK, requests)
+}
+
+func (s *Server) handleBatches(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	states, err := progress.List(s.progressDir)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	if states == nil {
+		states = []*progress.State{}
+	}
+	writeJSON(w, http.StatusOK, states)
+}
+
+func (s *Server) handleTasks(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	tasks, err := s.store.ListTasks(r.URL.Query().Get("status"))
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	if tasks == nil {
+		tasks = []*storage.QueueTask{}
+	}
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+// handleTaskAction serves POST /api/tasks/{id}/retry and /api/tasks/retry-failed
+func (s *Server) handleTaskAction(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	rest := strings.TrimPrefix(r.URL.Path, "/api/tasks/")
+	if rest == "retry-failed" {
+		failed, err := s.store.ListTasks(storage.TaskFailed)
+		if err != nil {
+			s.fail(w, err)
+			return
+		}
+		retried := 0
+		for _, task := range failed {
+			ok, err := s.store.RetryTask(task.ID)
+			if err != nil {
+				s.fail(w, err)
+				return
+			}
+			if ok {
+				retried++
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]int{"retried": retried})
+		return
+	}
+
+	idText, action, found := strings.Cut(rest, "/")
+	id, err := strconv.Atoi(idText)
+	if !found || action != "retry" || err != nil {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	ok, err := s.store.RetryTask(id)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	if !ok {
+		writeError(w, http.StatusConflict, "task not found or not failed or cancelled")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"retried": 1})
+}
+
+// fail reports an unexpected error without exposing it beyond the log
+func (s *Server) fail(w http.ResponseWriter, err error) {
+	s.logger.WithError(err).Warn("Web API request failed")
+	writeError(w, http.StatusInternalServerError, "internal error; see the daemon log")
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}